@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"hw/pkg/ethindexa/entitygen"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "internal/indexer/schema.yaml", "entity schema file")
+	outPath := flag.String("out", "internal/indexer/entities/entities_gen.go", "generated Go file")
+	migration := flag.String("migration", "", "migration path without the .up.sql/.down.sql suffix, e.g. migrations/20241012_create_entities")
+	flag.Parse()
+
+	data, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		log.Fatalf("Failed to read schema: %v", err)
+	}
+	schema, err := entitygen.Parse(data)
+	if err != nil {
+		log.Fatalf("Invalid schema %s: %v", *schemaPath, err)
+	}
+
+	source, err := entitygen.GenerateGo(schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(*outPath), 0o755); err != nil {
+		log.Fatalf("Failed to create %s: %v", filepath.Dir(*outPath), err)
+	}
+	if err := os.WriteFile(*outPath, source, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *outPath, err)
+	}
+	log.Printf("Wrote %s", *outPath)
+
+	if *migration == "" {
+		return
+	}
+	up, down, err := entitygen.GenerateMigration(schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for path, content := range map[string][]byte{*migration + ".up.sql": up, *migration + ".down.sql": down} {
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+		log.Printf("Wrote %s", path)
+	}
+}
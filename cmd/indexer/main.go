@@ -1,96 +1,277 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
-	"hw/internal/indexer/handlers"
-	"hw/internal/repository"
-	"hw/internal/service"
+	"hw/internal/app"
+	"hw/pkg/config"
 	"hw/pkg/ethindexa"
 	"hw/pkg/logger"
-	"hw/pkg/pg"
 
-	"github.com/golang-migrate/migrate/v4"
-
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"go.uber.org/fx"
 )
 
-func migrateDB() {
-	// TODO: Configure according to production environment settings
-	connString := os.Getenv("DATABASE_URL")
+// printStatus fetches the status of the running indexer from the admin URL and prints it as a table.
+func printStatus(adminURL string) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(strings.TrimSuffix(adminURL, "/") + ethindexa.StatusPath)
+	if err != nil {
+		log.Fatalf("Failed to get indexer status: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("Failed to get indexer status: %s", resp.Status)
+	}
 
-	m, err := migrate.New(
-		"file://migrations",
-		connString,
-	)
+	var status ethindexa.IndexerStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		log.Fatalf("Failed to decode indexer status: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NETWORK\tCONTRACT\tSTATE\tPROCESSED\tHEAD\tLAG\tEVENTS/MIN\tFAILED\tQUEUES (EVENT/HANDLER)\tLAST ERROR")
+	for _, network := range status.Networks {
+		fmt.Fprintf(w, "%s\t\t%s\t%d\t%d\t%d\t\t\t%d/%d\t%s\n", network.Network, runState(network.Paused), network.LastProcessedBlock, network.HeadBlock, network.Lag,
+			network.EventQueueDepth, network.HandlerQueueDepth, network.LastError)
+		for _, contract := range network.Contracts {
+			fmt.Fprintf(w, "\t%s\t%s\t%d\t\t\t%d\t%s\t\t%s\n", contract.Contract, runState(contract.Paused), contract.LastEventBlock, contract.EventsPerMinute,
+				handlerFailures(contract.Handlers), contract.LastError)
+		}
+	}
+	w.Flush()
+
+	for _, quarantined := range status.Quarantined {
+		fmt.Printf("\nQuarantined %s since %s after %d failures (%d events skipped): %s\n", quarantined.Handler,
+			quarantined.QuarantinedAt.Format(time.RFC3339), quarantined.Failures, quarantined.Skipped, quarantined.LastError)
+	}
+}
+
+// handlerFailures formats the failed runs of a contract's handlers out of their runs.
+func handlerFailures(handlers []ethindexa.HandlerStatus) string {
+	var runs, failures uint64
+	for _, handler := range handlers {
+		runs += handler.Runs
+		failures += handler.Failures
+	}
+	return fmt.Sprintf("%d/%d", failures, runs)
+}
+
+// traceTx asks the running indexer to trace a transaction through its handlers without
+// writing anything, and prints the decoded logs and the writes the handlers would have made.
+func traceTx(adminURL string, args []string) {
+	flags := flag.NewFlagSet("trace-tx", flag.ExitOnError)
+	network := flags.String("network", "mainnet", "network of the transaction")
+	tx := flags.String("tx", "", "hash of the transaction")
+	flags.Parse(args)
+	if *tx == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	query := url.Values{"network": {*network}, "tx": {*tx}}
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Get(strings.TrimSuffix(adminURL, "/") + ethindexa.TracePath + "?" + query.Encode())
 	if err != nil {
-		log.Fatal(err)
+		log.Fatalf("Failed to trace transaction: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Fatalf("Failed to trace transaction: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var trace ethindexa.TxTrace
+	decoder := json.NewDecoder(resp.Body)
+	// Keep uint256 arguments exact
+	decoder.UseNumber()
+	if err := decoder.Decode(&trace); err != nil {
+		log.Fatalf("Failed to decode transaction trace: %v", err)
+	}
+
+	fmt.Printf("Transaction %s on %s in block %d: %d logs\n", trace.Transaction, trace.Network, trace.BlockNumber, len(trace.Events))
+	for _, event := range trace.Events {
+		fmt.Println()
+		if event.Contract == "" {
+			fmt.Printf("#%d %s skipped: %s\n", event.LogIndex, event.Address, event.Skipped)
+			continue
+		}
+		fmt.Printf("#%d %s %s (%s)", event.LogIndex, event.Contract, event.Event, event.Handler)
+		if event.Skipped != "" {
+			fmt.Printf(" skipped: %s\n", event.Skipped)
+		} else {
+			fmt.Printf(" %s in %s\n", event.Status, event.Duration)
+		}
+		names := make([]string, 0, len(event.Args))
+		for name := range event.Args {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %s: %v\n", name, event.Args[name])
+		}
+		if event.Error != "" {
+			fmt.Printf("  error: %s\n", event.Error)
+		}
+		for _, write := range event.Writes {
+			args, _ := json.Marshal(write.Args)
+			fmt.Printf("  write %s %s\n", write.Target, args)
+		}
 	}
+}
 
-	// Execute Down migration to remove all tables
-	if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-		log.Fatalf("Migration Down failed: %v", err)
+// simulate runs the handlers over a block range of a fork of a network, e.g. an Anvil node forked
+// at a chosen block, writing to a scratch schema copied from the database, and prints the outcome
+// of every event and the tables whose row count changed.
+func simulate(cfg config.Config, args []string) {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	network := flags.String("network", "mainnet", "network the fork was made from")
+	forkURL := flags.String("fork-url", "", "RPC URL of the fork, e.g. http://localhost:8545 for anvil --fork-url <rpc> --fork-block-number <block>")
+	from := flags.Uint64("from", 0, "first block to simulate")
+	to := flags.Uint64("to", 0, "last block to simulate; the head of the fork when zero")
+	schema := flags.String("schema", "sim_"+time.Now().UTC().Format("20060102150405"), "scratch schema the handlers write to")
+	keep := flags.Bool("keep", false, "keep the scratch schema to inspect the writes")
+	flags.Parse(args)
+	if *forkURL == "" || *from == 0 {
+		flags.Usage()
+		os.Exit(2)
 	}
 
-	// Execute Up migration to recreate tables
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+	configPath, err := ethindexa.DefaultConfigPath()
+	if err != nil {
 		log.Fatal(err)
 	}
+
+	report, err := app.Simulate(context.Background(), cfg, configPath, app.SimulationOptions{
+		Network:   *network,
+		ForkURL:   *forkURL,
+		FromBlock: *from,
+		ToBlock:   *to,
+		Schema:    *schema,
+		Keep:      *keep,
+	})
+	if err != nil {
+		log.Fatalf("Failed to simulate: %v", err)
+	}
+
+	fmt.Printf("Simulated %s blocks %d to %d: %d events\n", report.Network, report.FromBlock, report.ToBlock, len(report.Events))
+	for _, event := range report.Events {
+		fmt.Printf("\n#%d/%s:%d %s %s (%s)", event.BlockNumber, event.Transaction, event.LogIndex, event.Contract, event.Event, event.Handler)
+		if event.Skipped != "" {
+			fmt.Printf(" skipped: %s\n", event.Skipped)
+			continue
+		}
+		fmt.Printf(" %s in %s\n", event.Status, event.Duration)
+		if event.Error != "" {
+			fmt.Printf("  error: %s\n", event.Error)
+		}
+	}
+
+	tables := make([]string, 0, len(report.Changes))
+	for table := range report.Changes {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	fmt.Printf("\nRows written to %s:\n", report.Schema)
+	for _, table := range tables {
+		fmt.Printf("  %s: %d -> %d\n", table, report.Changes[table][0], report.Changes[table][1])
+	}
+	if *keep {
+		fmt.Printf("\nSchema %s is kept; drop it with DROP SCHEMA %s CASCADE\n", report.Schema, report.Schema)
+	}
 }
 
-// setupIndexer initializes the indexer with the necessary handlers and starts the event listeners.
-func setupIndexer(db *pg.PostgresDB, svc service.Service) error {
-	// Define all event handlers to be registered
-	// key come from contract {name}:{network}:{event} in config file
-	handlersMap := map[string]ethindexa.EventHandler{
-		"UniswapV2:mainnet:Swap": handlers.HandleUSDCWETHSwap,
+// validateConfig checks config.json, including start blocks against the network heads,
+// and exits non-zero listing every problem found.
+func validateConfig() {
+	configPath, err := ethindexa.DefaultConfigPath()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		// If you need to handle other events, add them here
-		"USDC:mainnet:Transfer": handlers.HandleTransfer,
-		"USDC:base:Approval":    handlers.HandleApproval,
-		"AAVE:mainnet:Approval": handlers.HandleApproval,
+	if _, err := ethindexa.CheckConfig(context.Background(), configPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
+	fmt.Printf("%s is valid\n", configPath)
+}
 
-	// Create indexer with registered events only
-	_, err := ethindexa.NewIndexer(db, svc, handlersMap)
+// preflight checks that the indexer is ready to start, prints the readiness report and exits
+// non-zero when a check failed.
+func preflight(cfg config.Config) {
+	configPath, err := ethindexa.DefaultConfigPath()
 	if err != nil {
-		return fmt.Errorf("failed to create indexer: %w", err)
+		log.Fatal(err)
 	}
 
-	// Start all event listeners
-	// indexer.StartAllEventListeners()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
 
-	return nil
+	report := app.Preflight(ctx, cfg, configPath)
+	report.Print(os.Stdout)
+	if !report.Ready() {
+		os.Exit(1)
+	}
+}
+
+func runState(paused bool) string {
+	if paused {
+		return "paused"
+	}
+	return "running"
 }
 
 func main() {
+	// The subcommands only need part of the configuration, so it is validated when the indexer starts
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Initialize logger
-	logger.Init()
+	logger.Init(cfg.Log)
 
-	// Initialize PostgresDB
-	db, err := pg.NewPostgresDB()
-	if err != nil {
-		log.Fatalf("Failed to connect to PostgresDB: %v", err)
+	// `indexer validate-config` only checks the configuration file
+	if len(os.Args) > 1 && os.Args[1] == "validate-config" {
+		validateConfig()
+		return
 	}
-	defer db.Close()
 
-	// Initialize Repository
-	repo := repository.NewRepository(db)
+	// `indexer preflight` checks the database, networks, ABIs and handlers before a deployment
+	if len(os.Args) > 1 && os.Args[1] == "preflight" {
+		preflight(cfg)
+		return
+	}
 
-	// Initialize Service
-	svc := service.NewService(repo)
+	// `indexer trace-tx --network mainnet --tx 0x...` dry-runs the handlers of a transaction on the running indexer
+	if len(os.Args) > 1 && os.Args[1] == "trace-tx" {
+		traceTx(cfg.Indexer.AdminURL, os.Args[2:])
+		return
+	}
 
-	// Perform database migrations
-	migrateDB()
+	// `indexer simulate --fork-url http://localhost:8545 --from 21000000` runs the handlers against a fork, writing to a scratch schema
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		simulate(cfg, os.Args[2:])
+		return
+	}
 
-	// Setup Indexer
-	if err := setupIndexer(db, svc); err != nil {
-		log.Fatalf("Failed to setup indexer: %v", err)
+	// `indexer status` prints the status of the running indexer
+	if len(os.Args) > 1 && os.Args[1] == "status" {
+		printStatus(cfg.Indexer.AdminURL)
+		return
 	}
 
-	// Block main goroutine
-	select {}
+	// Run the indexer until SIGINT or SIGTERM, then stop it and close the database
+	fx.New(app.Indexer).Run()
 }
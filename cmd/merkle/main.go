@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// balanceMapClaim is a claim in the output format of Uniswap's merkle-distributor scripts.
+type balanceMapClaim struct {
+	Index  uint64   `json:"index"`
+	Amount string   `json:"amount"` // hex
+	Proof  []string `json:"proof"`
+}
+
+// balanceMap is the output format of Uniswap's merkle-distributor scripts.
+type balanceMap struct {
+	MerkleRoot string                     `json:"merkleRoot"`
+	TokenTotal string                     `json:"tokenTotal"` // hex
+	Claims     map[string]balanceMapClaim `json:"claims"`
+}
+
+// newBalanceMap converts a distribution to the merkle-distributor format, keyed by checksummed address.
+func newBalanceMap(distribution *model.MerkleDistribution, proofs []model.MerkleProof) (*balanceMap, error) {
+	out := &balanceMap{MerkleRoot: distribution.Root, Claims: make(map[string]balanceMapClaim, len(proofs))}
+	total, ok := new(big.Int).SetString(distribution.TokenTotal, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token total %q", distribution.TokenTotal)
+	}
+	out.TokenTotal = hexutil.EncodeBig(total)
+	for _, proof := range proofs {
+		amount, ok := new(big.Int).SetString(proof.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %q of %s", proof.Amount, proof.Address)
+		}
+		out.Claims[common.HexToAddress(proof.Address).Hex()] = balanceMapClaim{
+			Index:  proof.Index,
+			Amount: hexutil.EncodeBig(amount),
+			Proof:  proof.Proof,
+		}
+	}
+	return out, nil
+}
+
+func main() {
+	cutoffFlag := flag.String("cutoff", "", "RFC 3339 time of the points snapshot (default now)")
+	outPath := flag.String("out", "", "also write the distribution as a merkle-distributor JSON file")
+	flag.Parse()
+
+	cutoff := time.Now()
+	if *cutoffFlag != "" {
+		var err error
+		if cutoff, err = time.Parse(time.RFC3339, *cutoffFlag); err != nil {
+			log.Fatalf("Invalid cutoff %q: %v", *cutoffFlag, err)
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	logger.Init(cfg.Log)
+
+	db, err := pg.NewPostgresDB(cfg.Database.URL, pg.WithPreparedQueries(repository.Queries()))
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewRepository(db)
+	projectID, err := service.ResolveProject(context.Background(), repo, cfg.Points.Project)
+	if err != nil {
+		log.Fatalf("Failed to resolve points project %q: %v", cfg.Points.Project, err)
+	}
+	svc := service.NewService(repo, service.WithProject(projectID))
+	distribution, proofs, err := svc.GenerateDistribution(context.Background(), cutoff)
+	if err != nil {
+		log.Fatalf("Failed to generate distribution: %v", err)
+	}
+	log.Printf("Distribution %d: root %s, %d claims, token total %s", distribution.ID, distribution.Root, len(proofs), distribution.TokenTotal)
+
+	if *outPath == "" {
+		return
+	}
+	out, err := newBalanceMap(distribution, proofs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*outPath, data, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *outPath, err)
+	}
+	log.Printf("Wrote %s", *outPath)
+}
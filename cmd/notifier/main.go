@@ -0,0 +1,12 @@
+package main
+
+import (
+	"hw/internal/app"
+
+	"go.uber.org/fx"
+)
+
+func main() {
+	// Send the due notifications every interval until SIGINT or SIGTERM
+	fx.New(app.Notifier).Run()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"hw/internal/repository"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+)
+
+// Manages the projects served by the API and their API keys:
+//
+//	project -create acme -name "Acme rewards"  creates a project
+//	project -key acme                          issues an API key of a project
+//	project -revoke 1a2b3c4d                   revokes an API key by its prefix
+func main() {
+	create := flag.String("create", "", "slug of a project to create")
+	name := flag.String("name", "", "name of the project to create (default its slug)")
+	key := flag.String("key", "", "slug of a project to issue an API key for")
+	revoke := flag.String("revoke", "", "prefix of an API key to revoke")
+	flag.Parse()
+
+	if *create == "" && *key == "" && *revoke == "" {
+		flag.Usage()
+		log.Fatal("One of -create, -key or -revoke is required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	logger.Init(cfg.Log)
+
+	db, err := pg.NewPostgresDB(cfg.Database.URL, pg.WithPreparedQueries(repository.Queries()))
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	svc := service.NewService(repository.NewRepository(db))
+
+	if *create != "" {
+		project, err := svc.CreateProject(ctx, *create, *name)
+		if err != nil {
+			log.Fatalf("Failed to create project %s: %v", *create, err)
+		}
+		log.Printf("Created project %d %s (%s)", project.ID, project.Slug, project.Name)
+	}
+	if *key != "" {
+		apiKey, err := svc.CreateAPIKey(ctx, *key)
+		if err != nil {
+			log.Fatalf("Failed to issue API key of project %s: %v", *key, err)
+		}
+		log.Printf("Issued API key %s of project %s; it is printed once, store it now", apiKey.Prefix, *key)
+		fmt.Println(apiKey.Key)
+	}
+	if *revoke != "" {
+		if err := svc.RevokeAPIKey(ctx, *revoke); err != nil {
+			log.Fatalf("Failed to revoke API key %s: %v", *revoke, err)
+		}
+		log.Printf("Revoked API key %s", *revoke)
+	}
+}
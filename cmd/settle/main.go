@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"hw/internal/repository"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+	"hw/pkg/transactor"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func main() {
+	distributionID := flag.Int("distribution", 0, "ID of the Merkle distribution to settle")
+	fund := flag.Bool("fund", false, "transfer the token total of the distribution to the distributor")
+	root := flag.Bool("root", false, "set the root of the distribution on the distributor")
+	wait := flag.Bool("wait", false, "wait until the pending settlement transactions are mined")
+	flag.Parse()
+
+	if (*fund || *root) && *distributionID == 0 {
+		log.Fatal("-distribution is required with -fund or -root")
+	}
+	if !*fund && !*root && !*wait {
+		log.Fatal("Nothing to do: use -fund, -root or -wait")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	if cfg.Settlement.RPCURL == "" || cfg.Settlement.Distributor == "" {
+		log.Fatal("settlement.rpcURL and settlement.distributor are required")
+	}
+
+	logger.Init(cfg.Log)
+
+	db, err := pg.NewPostgresDB(cfg.Database.URL, pg.WithPreparedQueries(repository.Queries()))
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	client, err := ethclient.Dial(cfg.Settlement.RPCURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", cfg.Settlement.Network, err)
+	}
+	defer client.Close()
+
+	signer, err := transactor.NewSigner(ctx, cfg.Settlement)
+	if err != nil {
+		log.Fatalf("Failed to initialize signer: %v", err)
+	}
+	fees, err := transactor.NewFeeOracle(client, cfg.Settlement.FeeStrategy,
+		transactor.GweiToWei(cfg.Settlement.MaxFeeGwei), transactor.GweiToWei(cfg.Settlement.MaxTipGwei))
+	if err != nil {
+		log.Fatal(err)
+	}
+	tr := transactor.New(client, signer,
+		transactor.WithFeeOracle(fees),
+		transactor.WithGasLimitMargin(cfg.Settlement.GasLimitMargin),
+		transactor.WithReceiptPollInterval(cfg.Settlement.ReceiptPollInterval),
+	)
+
+	repo := repository.NewRepository(db)
+	projectID, err := service.ResolveProject(ctx, repo, cfg.Points.Project)
+	if err != nil {
+		log.Fatalf("Failed to resolve points project %q: %v", cfg.Points.Project, err)
+	}
+	svc := service.NewService(repo, service.WithProject(projectID), service.WithSettlement(cfg.Settlement, tr))
+
+	// The distributor is funded before its root is set, so no claim can be made before it holds the tokens
+	if *fund {
+		tx, err := svc.FundDistribution(ctx, *distributionID)
+		if err != nil {
+			log.Fatalf("Failed to fund distribution %d: %v", *distributionID, err)
+		}
+		log.Printf("Funding distribution %d: %s (nonce %d)", *distributionID, tx.Hash, tx.Nonce)
+	}
+	if *root {
+		tx, err := svc.UpdateDistributionRoot(ctx, *distributionID)
+		if err != nil {
+			log.Fatalf("Failed to update root of distribution %d: %v", *distributionID, err)
+		}
+		log.Printf("Updating root of distribution %d: %s (nonce %d)", *distributionID, tx.Hash, tx.Nonce)
+	}
+	if !*wait {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Settlement.ReceiptPollInterval)
+	defer ticker.Stop()
+	for {
+		mined, err := svc.TrackSettlements(ctx)
+		if err != nil {
+			log.Fatalf("Failed to track settlements: %v", err)
+		}
+		for _, tx := range mined {
+			log.Printf("Settlement %s of distribution %d %s in block %d", tx.Action, tx.DistributionID, tx.Status, *tx.BlockNumber)
+		}
+		pending, err := repo.GetPendingSettlementTxs(ctx, cfg.Settlement.Network)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(pending) == 0 {
+			return
+		}
+		// Fees above the maximum are retried on the next poll, as the base fee may fall
+		if _, err := svc.ReplaceStuckSettlements(ctx, time.Now()); err != nil {
+			log.Printf("Failed to replace stuck settlements: %v", err)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"hw/internal/repository"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// readHolders reads the holder addresses of a file, one per line. Blank lines and lines starting
+// with # are skipped.
+func readHolders(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var holders []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !common.IsHexAddress(line) {
+			return nil, fmt.Errorf("invalid holder address %q", line)
+		}
+		holders = append(holders, strings.ToLower(line))
+	}
+	return holders, nil
+}
+
+func main() {
+	network := flag.String("network", "mainnet", "network of the token")
+	token := flag.String("token", "", "address of the ERC-20 token")
+	block := flag.Int64("block", 0, "block number of the snapshot")
+	holdersPath := flag.String("holders", "", "file of holder addresses, one per line, to read with balanceOf (default the holders of the indexed transfers)")
+	rpcURL := flag.String("rpc", "", "RPC URL of the network, required with -holders")
+	flag.Parse()
+
+	if !common.IsHexAddress(*token) {
+		log.Fatalf("Invalid token %q", *token)
+	}
+
+	var (
+		holders []string
+		client  *ethclient.Client
+	)
+	if *holdersPath != "" {
+		var err error
+		if holders, err = readHolders(*holdersPath); err != nil {
+			log.Fatalf("Failed to read holders: %v", err)
+		}
+		if *rpcURL == "" {
+			log.Fatal("-rpc is required with -holders")
+		}
+		if client, err = ethclient.Dial(*rpcURL); err != nil {
+			log.Fatalf("Failed to connect to %s: %v", *network, err)
+		}
+		defer client.Close()
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	logger.Init(cfg.Log)
+
+	db, err := pg.NewPostgresDB(cfg.Database.URL, pg.WithPreparedQueries(repository.Queries()))
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	svc := service.NewService(repository.NewRepository(db))
+	snapshot, _, err := svc.TakeBalanceSnapshot(context.Background(), client, *network, strings.ToLower(*token), *block, holders)
+	if err != nil {
+		log.Fatalf("Failed to take balance snapshot: %v", err)
+	}
+	log.Printf("Snapshot %d of %s at block %d from %s: %d holders, total %s", snapshot.ID, *token, *block, snapshot.Source, snapshot.Holders, snapshot.Total)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+)
+
+func main() {
+	network := flag.String("network", "mainnet", "network of the pool")
+	pool := flag.String("pool", "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc", "address of the pool")
+	fromFlag := flag.String("from", "", "RFC 3339 start of the reward period at midnight UTC (default 7 days before to)")
+	toFlag := flag.String("to", "", "RFC 3339 end of the reward period at midnight UTC (default today's midnight UTC)")
+	pointsFlag := flag.String("points", "10000", "points shared by the traders")
+	flag.Parse()
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if *toFlag != "" {
+		var err error
+		if to, err = time.Parse(time.RFC3339, *toFlag); err != nil {
+			log.Fatalf("Invalid to %q: %v", *toFlag, err)
+		}
+	}
+	from := to.Add(-7 * 24 * time.Hour)
+	if *fromFlag != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, *fromFlag); err != nil {
+			log.Fatalf("Invalid from %q: %v", *fromFlag, err)
+		}
+	}
+	points, err := model.NewDecimalFromString(*pointsFlag)
+	if err != nil {
+		log.Fatalf("Invalid points: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	logger.Init(cfg.Log)
+
+	db, err := pg.NewPostgresDB(cfg.Database.URL, pg.WithPreparedQueries(repository.Queries()))
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewRepository(db)
+	projectID, err := service.ResolveProject(context.Background(), repo, cfg.Points.Project)
+	if err != nil {
+		log.Fatalf("Failed to resolve points project %q: %v", cfg.Points.Project, err)
+	}
+	svc := service.NewService(repo, service.WithProject(projectID), service.WithPoints(cfg.Points))
+	rewards, err := svc.DistributeTWAVRewards(context.Background(), *network, strings.ToLower(*pool), from, to, points)
+	if err != nil {
+		log.Fatalf("Failed to distribute TWAV rewards: %v", err)
+	}
+	for _, reward := range rewards {
+		log.Printf("%s: %s points (TWAV %s USD over %d active days, %s of the total)", reward.Account, reward.Points, reward.TWAV, reward.ActiveDays, reward.Share)
+	}
+	log.Printf("Distributed TWAV rewards of %s from %s to %s to %d traders", *pool, from.Format(time.RFC3339), to.Format(time.RFC3339), len(rewards))
+}
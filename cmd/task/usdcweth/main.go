@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"log"
+	"time"
 
+	"hw/internal/model"
 	"hw/internal/repository"
 	"hw/internal/service"
 	"hw/pkg/bigrat"
+	"hw/pkg/config"
 	"hw/pkg/logger"
 	"hw/pkg/pg"
 
@@ -17,43 +20,78 @@ import (
 // TODO: use transaction
 
 func main() {
-	logger.Init()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
 
-	db, err := pg.NewPostgresDB()
+	logger.Init(cfg.Log)
+
+	db, err := pg.NewPostgresDB(cfg.Database.URL, pg.WithPreparedQueries(repository.Queries()))
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
 	repo := repository.NewRepository(db)
-	service := service.NewService(repo)
+	projectID, err := service.ResolveProject(context.Background(), repo, cfg.Points.Project)
+	if err != nil {
+		log.Fatalf("Failed to resolve points project %q: %v", cfg.Points.Project, err)
+	}
+	svc := service.NewService(repo, service.WithProject(projectID), service.WithPoints(cfg.Points))
 
+	network := "mainnet"
 	usdcweth := "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
 	totalSharePoolPoints := 10000.00
+	task := "sharepool_usdcweth_task"
+
+	// The share pool covers the seven UTC days ending today, as GetUserSwapSummaryLast7Days
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	metadata := &model.PointsMetadata{Campaign: service.RewardCampaign(usdcweth, today.AddDate(0, 0, -6), today.AddDate(0, 0, 1))}
 
-	userSwapSummary, err := service.GetUserSwapSummaryLast7Days(context.Background(), usdcweth)
+	excludeWashTrades := svc.ExcludesWashTrades(task)
+	userSwapSummary, err := svc.GetUserSwapSummaryLast7Days(context.Background(), usdcweth, excludeWashTrades)
 	if err != nil {
 		log.Fatalf("Failed to retrieve user swap summary: %v", err)
 	}
 
+	washUSD := model.ZeroDecimal
 	for _, userSwap := range userSwapSummary {
-		user, err := service.GetOrCreateAccount(context.Background(), userSwap.Account)
-		if err != nil {
-			log.Fatalf("Failed to retrieve user: %v", err)
-		}
+		washUSD = washUSD.Add(userSwap.WashUSD)
+	}
+	if excludeWashTrades {
+		logger.Infof("Excluding %s USD of wash trades", washUSD)
+	} else {
+		logger.Infof("Counting %s USD of wash trades", washUSD)
+	}
+
+	accounts := make([]string, len(userSwapSummary))
+	for i, userSwap := range userSwapSummary {
+		accounts[i] = userSwap.Account
+	}
+	users, err := svc.GetOrCreateAccounts(context.Background(), accounts)
+	if err != nil {
+		log.Fatalf("Failed to retrieve users: %v", err)
+	}
+
+	for _, userSwap := range userSwapSummary {
+		user := users[userSwap.Account]
 
-		completed, err := service.IsOnboardingTaskCompleted(context.Background(), userSwap.Account)
+		completed, err := svc.IsOnboardingTaskCompleted(context.Background(), userSwap.Account)
 		if err != nil {
 			log.Fatalf("Failed to retrieve user points history: %v", err)
 		}
 
-		// if not completed, skip awarding points
-		if !completed {
+		// if not completed, or only wash traded, skip awarding points
+		if !completed || !userSwap.Percentage.IsPositive() {
 			continue
 		}
 
-		newPoints := bigrat.NewBigN(totalSharePoolPoints).Mul(userSwap.Percentage).ToTruncateFloat64(3)
+		newPoints := model.NewDecimal(bigrat.New(totalSharePoolPoints).Mul(userSwap.Percentage.Decimal).ToTruncateDecimal(3))
 
-		if err := service.AccumulateUserPoints(context.Background(), usdcweth, user.Address, "sharepool_usdcweth_task", newPoints); err != nil {
+		if err := svc.AccumulateUserPointsWithMetadata(context.Background(), network, usdcweth, user.Address, task, newPoints, metadata); err != nil {
 			log.Fatalf("Failed to create points history: %v", err)
 		}
 	}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+
+	"go.uber.org/fx"
+)
+
+// NewSwapAnalytics creates the ClickHouse copy of the swaps. Its table is created when the
+// application starts, and its buffered swaps are inserted until it stops, the last ones on stop.
+func NewSwapAnalytics(lc fx.Lifecycle, cfg config.Config) *service.ClickHouseSwapAnalytics {
+	analytics := service.NewClickHouseSwapAnalytics(cfg.ClickHouse)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(startCtx context.Context) error {
+			if err := analytics.EnsureSchema(startCtx); err != nil {
+				return err
+			}
+			go func() {
+				defer close(done)
+				if err := analytics.Run(ctx); err != nil {
+					logger.Errorf("Failed to insert %d buffered swaps into ClickHouse: %v", analytics.Buffered(), err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+	return analytics
+}
@@ -0,0 +1,180 @@
+// Package app wires the API server and the indexer from fx modules, so both binaries share
+// their startup and shutdown order and tests can replace any dependency.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"hw/internal/repository"
+	"hw/internal/service"
+	"hw/pkg/blobstore"
+	"hw/pkg/cache"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/notify"
+	"hw/pkg/pg"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ConfigModule provides the configuration of every subsystem.
+var ConfigModule = fx.Module("config",
+	fx.Provide(NewConfig),
+)
+
+// LoggerModule provides the global zap logger and logs the fx lifecycle through it.
+var LoggerModule = fx.Module("logger",
+	fx.Provide(NewLogger),
+	fx.WithLogger(logger.NewFxLogger),
+)
+
+// DBModule provides the Postgres pool, as *pg.PostgresDB and pg.PgxPool.
+var DBModule = fx.Module("db",
+	fx.Provide(fx.Annotate(NewDB, fx.As(fx.Self()), fx.As(new(pg.PgxPool)))),
+)
+
+// CacheModule provides the local cache.
+var CacheModule = fx.Module("cache",
+	fx.Provide(NewCache),
+)
+
+// RepositoryModule provides the repository.
+var RepositoryModule = fx.Module("repository",
+	fx.Provide(NewRepository),
+)
+
+// ServiceModule provides the service.
+var ServiceModule = fx.Module("service",
+	fx.Provide(NewService),
+)
+
+// API is the API server application.
+var API = fx.Options(
+	ConfigModule,
+	LoggerModule,
+	DBModule,
+	CacheModule,
+	RepositoryModule,
+	ServiceModule,
+	TransportModule,
+)
+
+// Indexer is the indexer application.
+var Indexer = fx.Options(
+	ConfigModule,
+	LoggerModule,
+	DBModule,
+	CacheModule,
+	RepositoryModule,
+	ServiceModule,
+	IndexerModule,
+)
+
+// Notifier is the notification worker application.
+var Notifier = fx.Options(
+	ConfigModule,
+	LoggerModule,
+	DBModule,
+	CacheModule,
+	RepositoryModule,
+	ServiceModule,
+	NotifierModule,
+)
+
+// NewConfig loads the configuration from CONFIG_FILE and the environment, and fails the startup
+// listing every invalid setting.
+func NewConfig() (config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return config.Config{}, err
+	}
+	return cfg, nil
+}
+
+// NewLogger initializes the global logger.
+func NewLogger(cfg config.Config) *zap.Logger {
+	return logger.Init(cfg.Log)
+}
+
+// NewDB connects to the configured database, timing its queries and bounding its statements by
+// the statement timeout, and closes the pool when the application stops.
+func NewDB(lc fx.Lifecycle, cfg config.Config) (*pg.PostgresDB, error) {
+	db, err := pg.NewPostgresDB(cfg.Database.URL,
+		pg.WithQueryTracer(pg.NewQueryTracer(cfg.Database.SlowQueryThreshold)),
+		pg.WithStatementTimeout(cfg.Database.StatementTimeout),
+		pg.WithPreparedQueries(repository.Queries()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the database: %w", err)
+	}
+	lc.Append(fx.StopHook(db.Close))
+	return db, nil
+}
+
+// NewRepository creates the repository, bounding its queries by the configured query timeout.
+func NewRepository(db pg.PgxPool, cfg config.Config) repository.Repository {
+	return repository.NewRepository(db, repository.WithQueryTimeout(cfg.Database.QueryTimeout))
+}
+
+// NewCache creates the local cache.
+func NewCache(cfg config.Config) cache.Cache {
+	return cache.NewLocalCache(cfg.Cache)
+}
+
+// ServiceParams are the dependencies of the service. Only the notifier provides a Sender and only
+// the indexer a retention Archive.
+type ServiceParams struct {
+	fx.In
+
+	Lifecycle  fx.Lifecycle
+	Config     config.Config
+	Repo       repository.Repository
+	TokenCache cache.Cache
+	Sender     notify.Sender   `optional:"true"`
+	Archive    blobstore.Store `optional:"true"`
+}
+
+// NewService creates the service of the configured project on top of the shared token cache,
+// publishing live events, mirroring the leaderboard in Redis and copying swaps to ClickHouse when
+// they are enabled.
+func NewService(p ServiceParams) (service.Service, error) {
+	projectID, err := service.ResolveProject(context.Background(), p.Repo, p.Config.Points.Project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve points project %q: %w", p.Config.Points.Project, err)
+	}
+	opts := []service.Option{
+		service.WithProject(projectID),
+		service.WithTokenCache(p.TokenCache),
+		service.WithClaims(p.Config.Claims),
+		service.WithPoints(p.Config.Points),
+		service.WithQuests(p.Config.Quests),
+		service.WithBoosts(p.Config.Boosts),
+		service.WithAuth(p.Config.Auth),
+		service.WithNotifications(p.Config.Notifications, p.Sender),
+		service.WithRetention(p.Config.Retention, p.Archive),
+		service.WithLiveEvents(),
+	}
+	if p.Config.Leaderboard.RedisEnabled {
+		opts = append(opts, service.WithLeaderboardStore(service.NewRedisLeaderboardFromConfig(p.Config.Cache)))
+	}
+	if p.Config.ClickHouse.Enabled {
+		opts = append(opts, service.WithSwapAnalytics(NewSwapAnalytics(p.Lifecycle, p.Config)))
+	}
+	return service.NewService(p.Repo, opts...), nil
+}
+
+// SyncLeaderboard rebuilds the Redis leaderboard mirror, when enabled, before handlers start accumulating points.
+func SyncLeaderboard(cfg config.Config, svc service.Service) error {
+	if !cfg.Leaderboard.RedisEnabled {
+		return nil
+	}
+	if err := svc.SyncLeaderboard(context.Background()); err != nil {
+		return fmt.Errorf("failed to sync leaderboard: %w", err)
+	}
+	return nil
+}
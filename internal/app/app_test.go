@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/internal/service"
+	"hw/internal/service/mocks"
+	"hw/pkg/config"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestValidateApps tests that every dependency of the API, the indexer and the notifier is provided.
+func TestValidateApps(t *testing.T) {
+	assert.NoError(t, fx.ValidateApp(API))
+	assert.NoError(t, fx.ValidateApp(Indexer))
+	assert.NoError(t, fx.ValidateApp(Notifier))
+}
+
+// TestTransportModule tests that the API is served with a replaced service, listening to live
+// events, and shuts down on stop.
+func TestTransportModule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cfg := config.Default()
+	cfg.Server.Port = "0"
+
+	mockService := mocks.NewMockService(ctrl)
+	mockService.EXPECT().ListenLiveEvents(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	var router *chi.Mux
+	app := fxtest.New(t,
+		fx.Supply(cfg),
+		fx.Provide(zap.NewNop),
+		fx.Provide(func() service.Service { return mockService }),
+		TransportModule,
+		fx.Populate(&router),
+	)
+	app.RequireStart()
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	app.RequireStop()
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"hw/internal/transport/api"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+)
+
+// maxAuditedBody caps the request body kept in the audit log.
+const maxAuditedBody = 64 << 10
+
+// auditStatusRecorder keeps the status written by a handler.
+type auditStatusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *auditStatusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// auditMutations records every request under /admin/ that is not a GET or HEAD in audit_log, with
+// its actor, body and response status, for compliance review. The actor is the operator of the
+// admin token authenticated by api.RequireAdminToken, which must wrap the returned handler; requests
+// the handler rejects are recorded too. Without a database the handler is returned unchanged.
+func auditMutations(db pg.PgxPool, next http.Handler) http.Handler {
+	const query = `
+		INSERT INTO audit_log (actor, method, path, request, status)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if db == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || !strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxAuditedBody+1))
+		if err != nil {
+			http.Error(w, "failed to read request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+		if len(body) > maxAuditedBody {
+			body = body[:maxAuditedBody]
+		}
+
+		actor := api.AdminActor(r.Context())
+
+		recorder := &auditStatusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// The request context may be cancelled once the response is written
+		if _, err := db.Exec(context.WithoutCancel(r.Context()), query, actor, r.Method, r.URL.Path, string(body), recorder.status); err != nil {
+			logger.Errorf("Failed to audit %s %s by %s: %v", r.Method, r.URL.Path, actor, err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/transport/api"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestAuditMutations tests that admin mutations are recorded with the operator of their admin token
+// as actor, body and status, that the handler still reads the body, that mutations without a valid
+// token are rejected, and that reads and requests outside /admin/ are not recorded.
+func TestAuditMutations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+
+	var handledBody string
+	token := strings.Repeat("a", 32)
+	handler := api.RequireAdminToken([]string{"alice:" + token}, auditMutations(mockDB, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handledBody = string(body)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})))
+
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), "alice", http.MethodPost, "/admin/indexer/pause", `{"network":"base"}`, http.StatusBadRequest).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/indexer/pause", strings.NewReader(`{"network":"base"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, `{"network":"base"}`, handledBody)
+
+	for _, header := range []string{"", "Bearer " + strings.Repeat("b", 32), "X-Audit-Actor alice"} {
+		req = httptest.NewRequest(http.MethodPost, "/admin/indexer/pause", strings.NewReader(`{"network":"base"}`))
+		req.Header.Set("Authorization", header)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/indexer/pauses", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/auth/verify", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+
+	"hw/internal/indexer/handlers"
+	"hw/internal/service"
+	"hw/internal/transport/api"
+	"hw/pkg/cache"
+	"hw/pkg/config"
+	"hw/pkg/ethindexa"
+	"hw/pkg/ethindexa/utils"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+
+	"github.com/golang-migrate/migrate/v4"
+	"go.uber.org/fx"
+
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// IndexerModule migrates the database, starts the indexer, serves its admin endpoints and applies
+// the retention of the history tables. Invokes run in order, so the indexer only starts once
+// migrations and the leaderboard sync are done.
+var IndexerModule = fx.Module("indexer",
+	fx.Provide(NewIndexer, NewRetentionArchive),
+	fx.Invoke(MigrateDB, SyncLeaderboard, ServeAdmin, RunRetention),
+)
+
+// MigrateDB applies the pending migrations of the configured source. It never migrates down, so
+// the data of the indexer, e.g. its pauses, block ranges and queued handler jobs, survives a
+// restart, and a replica starting does not touch the tables the others are using.
+func MigrateDB(cfg config.Config) error {
+	m, err := migrate.New(
+		cfg.Database.Migrations,
+		cfg.Database.URL,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("migration Up failed: %w", err)
+	}
+	return nil
+}
+
+// EventHandlers returns the handlers of the indexed events, keyed by {contract}:{network}:{event}
+// as in the indexer configuration file.
+func EventHandlers() map[string]ethindexa.EventHandler {
+	return map[string]ethindexa.EventHandler{
+		"UniswapV2:mainnet:Swap":     handlers.TrackGas(handlers.HandleUSDCWETHSwap),
+		"UniswapV2:mainnet:Sync":     handlers.HandleUniswapV2Sync,
+		"UniswapV2:mainnet:Mint":     handlers.TrackGas(handlers.HandleUniswapV2Mint),
+		"UniswapV2:mainnet:Burn":     handlers.TrackGas(handlers.HandleUniswapV2Burn),
+		"UniswapV2:mainnet:Transfer": handlers.TrackGas(handlers.HandleLPTransfer),
+
+		// If you need to handle other events, add them here
+		"USDC:mainnet:Transfer": handlers.HandleTransfer,
+		"AAVE:mainnet:Transfer": handlers.HandleTokenTransfer,
+		"USDC:base:Approval":    handlers.HandleApproval,
+		"AAVE:mainnet:Approval": handlers.HandleApproval,
+	}
+}
+
+// NewIndexer creates the indexer with the necessary handlers, which starts the event listeners,
+// and stops it when the application stops.
+func NewIndexer(lc fx.Lifecycle, cfg config.Config, db *pg.PostgresDB, svc service.Service) (*ethindexa.IndexerImpl, error) {
+	handlersMap := EventHandlers()
+	utils.SetCallCache(NewCallCache(cfg), cfg.Indexer.CallCacheTTL)
+
+	// Create indexer with registered events only
+	indexer, err := ethindexa.NewIndexer(db, svc, handlersMap, cfg.Blobstore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create indexer: %w", err)
+	}
+	lc.Append(fx.StopHook(indexer.Stop))
+
+	return indexer, nil
+}
+
+// NewCallCache creates the cache of contract call results: local, or backed by Redis when
+// indexers share it.
+func NewCallCache(cfg config.Config) cache.Cache {
+	if cfg.Indexer.CallCacheRedis {
+		return cache.NewHybridCache(cfg.Cache)
+	}
+	return cache.NewLocalCache(cfg.Cache)
+}
+
+// ServeAdmin serves the indexer admin endpoints on the admin port while the application runs.
+// Mutations require one of the configured admin tokens and are audited.
+func ServeAdmin(lc fx.Lifecycle, cfg config.Config, indexer *ethindexa.IndexerImpl, db *pg.PostgresDB, svc service.Service) {
+	mux := http.NewServeMux()
+	mux.Handle("GET "+pg.QueryStatsPath, pg.QueryStatsHandler(db.QueryTracer()))
+	mux.Handle("GET "+ethindexa.StatusPath, ethindexa.StatusHandler(indexer))
+	mux.Handle("GET "+ethindexa.MetricsPath, ethindexa.MetricsHandler(indexer))
+	mux.Handle("GET /admin/indexer/pauses", ethindexa.PausesHandler(indexer))
+	mux.Handle("POST /admin/indexer/pause", ethindexa.PauseHandler(indexer, false))
+	mux.Handle("POST /admin/indexer/resume", ethindexa.PauseHandler(indexer, true))
+	mux.Handle("GET /admin/indexer/quarantine", ethindexa.QuarantineHandler(indexer))
+	mux.Handle("POST /admin/indexer/unquarantine", ethindexa.UnquarantineHandler(indexer))
+	mux.Handle("GET "+ethindexa.TracePath, ethindexa.TraceHandler(indexer))
+	mux.Handle("GET /admin/log-level", logger.LevelHandler())
+	mux.Handle("PUT /admin/log-level", logger.LevelHandler())
+	labels := api.LabelsHandler(api.Server{Service: svc})
+	mux.Handle("/admin/labels", labels)
+	mux.Handle("/admin/labels/", labels)
+	if cfg.Indexer.Profiling {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	serve(lc, "indexer admin server", ":"+cfg.Indexer.AdminPort, api.RequireAdminToken(cfg.Indexer.AdminTokens, auditMutations(indexer.DB, mux)))
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hw/pkg/config"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/stub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// persistentStub is a migration driver keeping its state across opens, like a database across
+// restarts of the indexer.
+type persistentStub struct {
+	*stub.Stub
+}
+
+func (s persistentStub) Open(url string) (database.Driver, error) {
+	return s, nil
+}
+
+var migrationDB = persistentStub{Stub: &stub.Stub{}}
+
+func init() {
+	database.Register("persistentstub", migrationDB)
+}
+
+// TestMigrateDB_KeepsData tests that restarting the indexer only applies the pending migrations,
+// so tables such as handler_jobs keep their queued jobs across restarts.
+func TestMigrateDB_KeepsData(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"1_create_handler_jobs.up.sql":   "CREATE TABLE handler_jobs (id serial);",
+		"1_create_handler_jobs.down.sql": "DROP TABLE handler_jobs;",
+		"2_add_attempts.up.sql":          "ALTER TABLE handler_jobs ADD COLUMN attempts integer;",
+		"2_add_attempts.down.sql":        "ALTER TABLE handler_jobs DROP COLUMN attempts;",
+	}
+	for name, sql := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(sql), 0o644))
+	}
+
+	db := migrationDB
+	*db.Stub = stub.Stub{CurrentVersion: database.NilVersion}
+	cfg := config.Config{}
+	cfg.Database.Migrations = "file://" + dir
+	cfg.Database.URL = "persistentstub://"
+
+	// Two restarts of the indexer
+	require.NoError(t, MigrateDB(cfg))
+	require.NoError(t, MigrateDB(cfg))
+
+	assert.Equal(t, 2, db.CurrentVersion)
+	assert.Len(t, db.MigrationSequence, 2, "each migration runs once")
+	for _, sql := range db.MigrationSequence {
+		assert.False(t, strings.HasPrefix(sql, "DROP"), "no down migration runs: %s", sql)
+		assert.NotContains(t, sql, "DROP COLUMN")
+	}
+}
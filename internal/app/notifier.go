@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/notify"
+
+	"go.uber.org/fx"
+)
+
+// NotifierModule sends the due notifications periodically while the application runs.
+var NotifierModule = fx.Module("notifier",
+	fx.Provide(NewSender),
+	fx.Invoke(RunNotifier),
+)
+
+// NewSender creates the configured notification sender.
+func NewSender(cfg config.Config) (notify.Sender, error) {
+	return notify.New(cfg.Notifications)
+}
+
+// RunNotifier sends the due notifications at start and then every interval, until the application stops.
+func RunNotifier(lc fx.Lifecycle, cfg config.Config, svc service.Service) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				runNotifier(ctx, svc, cfg.Notifications.Interval)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+func runNotifier(ctx context.Context, svc service.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := svc.SendNotifications(ctx, time.Now()); err != nil {
+			logger.Errorf("Failed to send notifications: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
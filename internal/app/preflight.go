@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"hw/pkg/config"
+	"hw/pkg/ethindexa"
+	"hw/pkg/pg"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// Preflight checks that the indexer is ready to start without starting it: the database is
+// reachable and its migrations are not dirty, then the indexer configuration at configPath
+// resolves against the networks, ABIs and EventHandlers.
+func Preflight(ctx context.Context, cfg config.Config, configPath string) *ethindexa.PreflightReport {
+	report := &ethindexa.PreflightReport{}
+
+	if db, err := pg.NewPostgresDB(cfg.Database.URL); err != nil {
+		report.Add("database", ethindexa.PreflightFail, "%v", err)
+	} else {
+		db.Close()
+		report.Add("database", ethindexa.PreflightOK, "connected")
+		checkMigrations(cfg, report)
+	}
+
+	report.Checks = append(report.Checks, ethindexa.Preflight(ctx, configPath, EventHandlers()).Checks...)
+	return report
+}
+
+// checkMigrations compares the applied migration with the latest one. Pending migrations are
+// only a warning, as MigrateDB applies them when the indexer starts, but a dirty database needs
+// to be repaired by hand.
+func checkMigrations(cfg config.Config, report *ethindexa.PreflightReport) {
+	latest, err := latestMigration(cfg.Database.Migrations)
+	if err != nil {
+		report.Add("migrations", ethindexa.PreflightFail, "failed to read %s: %v", cfg.Database.Migrations, err)
+		return
+	}
+
+	m, err := migrate.New(cfg.Database.Migrations, cfg.Database.URL)
+	if err != nil {
+		report.Add("migrations", ethindexa.PreflightFail, "%v", err)
+		return
+	}
+	defer m.Close()
+
+	version, dirty, err := m.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		report.Add("migrations", ethindexa.PreflightWarn, "none applied, up to %d applied at startup", latest)
+	case err != nil:
+		report.Add("migrations", ethindexa.PreflightFail, "failed to get the applied version: %v", err)
+	case dirty:
+		report.Add("migrations", ethindexa.PreflightFail, "version %d is dirty", version)
+	case version < latest:
+		report.Add("migrations", ethindexa.PreflightWarn, "version %d, up to %d applied at startup", version, latest)
+	default:
+		report.Add("migrations", ethindexa.PreflightOK, "version %d", version)
+	}
+}
+
+// latestMigration returns the version of the last migration at sourceURL.
+func latestMigration(sourceURL string) (uint, error) {
+	src, err := source.Open(sourceURL)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("no migrations: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"hw/internal/service"
+	"hw/pkg/blobstore"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+
+	"go.uber.org/fx"
+)
+
+// NewRetentionArchive creates the store the retention archives expired history rows to, nil when
+// they are dropped without being archived.
+func NewRetentionArchive(cfg config.Config) (blobstore.Store, error) {
+	if !cfg.Retention.Archive {
+		return nil, nil
+	}
+	return blobstore.New(cfg.Blobstore)
+}
+
+// RunRetention applies the retention of the history tables at start and then every interval,
+// until the application stops.
+func RunRetention(lc fx.Lifecycle, cfg config.Config, svc service.Service) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				runRetention(ctx, svc, cfg.Retention.Interval)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+func runRetention(ctx context.Context, svc service.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := svc.ApplyRetention(ctx, time.Now()); err != nil {
+			logger.Errorf("Failed to apply history retention: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
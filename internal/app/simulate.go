@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"hw/internal/repository"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/ethindexa"
+	"hw/pkg/pg"
+)
+
+// SimulationOptions selects the network, fork and blocks of a simulation.
+type SimulationOptions struct {
+	Network   string
+	ForkURL   string // RPC URL of a fork of the network, e.g. an Anvil node
+	FromBlock uint64
+	ToBlock   uint64 // the head of the fork when zero
+	Schema    string // scratch schema the handlers write to
+	Keep      bool   // keep the scratch schema after the simulation
+}
+
+// SimulationReport is the outcome of a simulation: the events run and the number of rows of the
+// tables of the scratch schema whose row count changed, before and after.
+type SimulationReport struct {
+	*ethindexa.Simulation
+	Schema  string
+	Changes map[string][2]int64
+}
+
+// Simulate runs the handlers of EventHandlers over a block range of a fork of a network, writing
+// to a scratch schema copied from the database, so changes to campaigns can be previewed on
+// production data without touching it. Live events, the Redis leaderboard mirror and the call
+// cache are left out, so nothing outside the scratch schema sees the writes. The scratch schema
+// is dropped afterwards unless opts.Keep is set.
+func Simulate(ctx context.Context, cfg config.Config, configPath string, opts SimulationOptions) (report *SimulationReport, err error) {
+	indexerConfig, err := ethindexa.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := pg.NewPostgresDB(cfg.Database.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the database: %w", err)
+	}
+	defer db.Close()
+
+	if err := pg.CreateScratchSchema(ctx, db, opts.Schema); err != nil {
+		return nil, err
+	}
+	if !opts.Keep {
+		defer func() {
+			if dropErr := pg.DropScratchSchema(context.Background(), db, opts.Schema); dropErr != nil && err == nil {
+				err = dropErr
+			}
+		}()
+	}
+	before, err := pg.ScratchRowCounts(ctx, db, opts.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	scratch, err := pg.NewPostgresDB(cfg.Database.URL,
+		pg.WithSearchPath(opts.Schema),
+		pg.WithStatementTimeout(cfg.Database.StatementTimeout),
+		pg.WithPreparedQueries(repository.Queries()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the scratch database: %w", err)
+	}
+	defer scratch.Close()
+
+	repo := NewRepository(scratch, cfg)
+	projectID, err := service.ResolveProject(ctx, repo, cfg.Points.Project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve points project %q: %w", cfg.Points.Project, err)
+	}
+	svc := service.NewService(repo,
+		service.WithProject(projectID),
+		service.WithTokenCache(NewCache(cfg)),
+		service.WithClaims(cfg.Claims),
+		service.WithPoints(cfg.Points),
+		service.WithQuests(cfg.Quests),
+		service.WithBoosts(cfg.Boosts),
+		service.WithAuth(cfg.Auth),
+	)
+	simulator, err := ethindexa.NewSimulator(ctx, indexerConfig, opts.Network, opts.ForkURL, scratch, svc, EventHandlers())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create simulator: %w", err)
+	}
+
+	toBlock := opts.ToBlock
+	if toBlock == 0 {
+		header, err := simulator.Clients[opts.Network].HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get head block of the fork: %w", err)
+		}
+		toBlock = header.Number.Uint64()
+	}
+
+	simulation, err := simulator.Simulate(ctx, opts.Network, opts.FromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := pg.ScratchRowCounts(ctx, db, opts.Schema)
+	if err != nil {
+		return nil, err
+	}
+	report = &SimulationReport{Simulation: simulation, Schema: opts.Schema, Changes: make(map[string][2]int64)}
+	for table, count := range after {
+		if count != before[table] {
+			report.Changes[table] = [2]int64{before[table], count}
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"hw/internal/service"
+	"hw/internal/transport/api"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/micro-tree/http/server"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// TransportModule serves the API over HTTP while the application runs.
+var TransportModule = fx.Module("transport",
+	fx.Provide(NewRouter),
+	fx.Invoke(ServeHTTP, ListenLiveEvents),
+)
+
+// RouterParams are the dependencies of NewRouter.
+type RouterParams struct {
+	fx.In
+
+	Config  config.Config
+	Logger  *zap.Logger
+	Service service.Service
+}
+
+// NewRouter creates the API router, answering browser frontends of the configured origins.
+func NewRouter(p RouterParams) *chi.Mux {
+	srv := api.Server{
+		Logger:           p.Logger,
+		Service:          p.Service,
+		WebSocketOrigins: p.Config.Server.CORSAllowedOrigins,
+	}
+	router := server.NewHTTPServer(
+		server.WithSecurityHeaders(p.Config.Server.HSTSMaxAge),
+		server.WithCORS(p.Config.Server.CORSAllowedOrigins, p.Config.Server.CORSAllowedMethods, api.RequestHeaders),
+	)
+	api.ConfigureHTTPServer(router, srv)
+	return router
+}
+
+// ServeHTTP serves the API router on the server port from start until the application stops.
+// The admin endpoints are served by the indexer admin server, not by the API.
+func ServeHTTP(lc fx.Lifecycle, cfg config.Config, router *chi.Mux) {
+	serve(lc, "server", ":"+cfg.Server.Port, router)
+}
+
+// ListenLiveEvents passes the live events published by the indexer to the subscribers of
+// /stream from start until the application stops.
+func ListenLiveEvents(lc fx.Lifecycle, svc service.Service) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				svc.ListenLiveEvents(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+// serve listens on addr when the application starts, so a busy port fails the startup,
+// and shuts the server down gracefully when it stops.
+func serve(lc fx.Lifecycle, name, addr string, handler http.Handler) {
+	srv := &http.Server{Addr: addr, Handler: handler}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("failed to start the %s: %w", name, err)
+			}
+			logger.Infof("Start %s on %s", name, listener.Addr())
+			go func() {
+				if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Errorf("The %s stopped: %v", name, err)
+				}
+			}()
+			return nil
+		},
+		OnStop: srv.Shutdown,
+	})
+}
@@ -0,0 +1,118 @@
+// Code generated by entitygen. DO NOT EDIT.
+
+package entities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/ethindexa"
+	"hw/pkg/pg"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PairDayData is a row of pair_day_data.
+type PairDayData struct {
+	ID        string        `json:"id"`
+	Network   string        `json:"network"`
+	Pair      string        `json:"pair"`
+	Day       int64         `json:"day"`
+	VolumeUsd model.Decimal `json:"volumeUsd"`
+	SwapCount int64         `json:"swapCount"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// PairDayDataRepository reads and writes PairDayData entities.
+type PairDayDataRepository struct {
+	db pg.PgxPool
+}
+
+// NewPairDayDataRepository creates a PairDayDataRepository on top of the given pool.
+func NewPairDayDataRepository(db pg.PgxPool) *PairDayDataRepository {
+	return &PairDayDataRepository{db: db}
+}
+
+// Get returns the PairDayData with the given id, or ethindexa.ErrEntityNotFound.
+func (r *PairDayDataRepository) Get(ctx context.Context, id string) (*PairDayData, error) {
+	const query = `SELECT id, network, pair, day, volume_usd, swap_count, updated_at FROM pair_day_data WHERE id = $1`
+
+	var entity PairDayData
+	err := r.db.QueryRow(ctx, query, id).Scan(&entity.ID, &entity.Network, &entity.Pair, &entity.Day, &entity.VolumeUsd, &entity.SwapCount, &entity.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ethindexa.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to get PairDayData %s: %w", id, err)
+	}
+	return &entity, nil
+}
+
+// List returns up to limit PairDayData entities ordered by id, skipping the first offset.
+func (r *PairDayDataRepository) List(ctx context.Context, limit, offset int) ([]*PairDayData, error) {
+	const query = `SELECT id, network, pair, day, volume_usd, swap_count, updated_at FROM pair_day_data ORDER BY id LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PairDayData: %w", err)
+	}
+	defer rows.Close()
+
+	entities := []*PairDayData{}
+	for rows.Next() {
+		var entity PairDayData
+		if err := rows.Scan(&entity.ID, &entity.Network, &entity.Pair, &entity.Day, &entity.VolumeUsd, &entity.SwapCount, &entity.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan PairDayData: %w", err)
+		}
+		entities = append(entities, &entity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list PairDayData: %w", err)
+	}
+	return entities, nil
+}
+
+// Upsert creates the PairDayData or replaces its fields.
+func (r *PairDayDataRepository) Upsert(ctx context.Context, entity *PairDayData) error {
+	const query = `
+		INSERT INTO pair_day_data (id, network, pair, day, volume_usd, swap_count, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			network = EXCLUDED.network,
+			pair = EXCLUDED.pair,
+			day = EXCLUDED.day,
+			volume_usd = EXCLUDED.volume_usd,
+			swap_count = EXCLUDED.swap_count,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.Exec(ctx, query, entity.ID, entity.Network, entity.Pair, entity.Day, entity.VolumeUsd, entity.SwapCount, entity.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert PairDayData %s: %w", entity.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the PairDayData with the given id.
+func (r *PairDayDataRepository) Delete(ctx context.Context, id string) error {
+	const query = `DELETE FROM pair_day_data WHERE id = $1`
+
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete PairDayData %s: %w", id, err)
+	}
+	return nil
+}
+
+// Repositories holds the repository of every entity.
+type Repositories struct {
+	PairDayData *PairDayDataRepository
+}
+
+// NewRepositories creates the repositories of every entity on top of the given pool.
+func NewRepositories(db pg.PgxPool) *Repositories {
+	return &Repositories{
+		PairDayData: NewPairDayDataRepository(db),
+	}
+}
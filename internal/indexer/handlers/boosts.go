@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"hw/internal/service"
+	"hw/pkg/ethindexa"
+	"hw/pkg/ethindexa/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	balanceOfABIOnce sync.Once
+	balanceOfABI     abi.ABI
+	balanceOfABIErr  error
+)
+
+// balanceReader reads the balances of an account at the block of event. ERC-20 and ERC-721
+// contracts share the balanceOf(address) function, so both are read with the ERC-20 ABI.
+func balanceReader(idx *ethindexa.IndexerService, event ethindexa.Event, account string) service.BalanceReader {
+	return func(contract string) (*big.Int, error) {
+		balanceOfABIOnce.Do(func() {
+			balanceOfABI, balanceOfABIErr = utils.LoadABI("erc20_usdc")
+		})
+		if balanceOfABIErr != nil {
+			return nil, balanceOfABIErr
+		}
+
+		result, err := idx.ReadContract(common.HexToAddress(contract), balanceOfABI, event.Block.Number(), "balanceOf", common.HexToAddress(account))
+		if err != nil {
+			return nil, err
+		}
+		values, ok := result.([]interface{})
+		if !ok || len(values) == 0 {
+			return nil, fmt.Errorf("unexpected balanceOf result of %s: %v", contract, result)
+		}
+		balance, ok := values[0].(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("unexpected balanceOf result of %s: %v", contract, values[0])
+		}
+		return balance, nil
+	}
+}
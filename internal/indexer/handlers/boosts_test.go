@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"math/big"
+	"testing"
+
+	"hw/internal/service/mocks"
+	"hw/pkg/ethindexa/ethindexatest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestBalanceReader tests that a balance is read from the balanceOf result, and that a result of
+// an unexpected shape is an error rather than a panic.
+func TestBalanceReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  interface{}
+		want    *big.Int
+		wantErr bool
+	}{
+		{name: "balance", result: []interface{}{big.NewInt(2)}, want: big.NewInt(2)},
+		{name: "empty result", result: []interface{}{}, wantErr: true},
+		{name: "not a list", result: big.NewInt(2), wantErr: true},
+		{name: "not a number", result: []interface{}{common.Address{}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			idx, chain := ethindexatest.NewIndexerService(mocks.NewMockService(ctrl))
+			token := "0x7fc66500c84a76ad7e9c93437bfc5ac33e2ddae9"
+			chain.StubCall(common.HexToAddress(token), "balanceOf", tt.result, nil)
+
+			balance, err := balanceReader(idx, newSwapEvent(1).Build(), "0xabcdef0000000000000000000000000000000001")(token)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, balance)
+		})
+	}
+}
@@ -1,13 +1,22 @@
 package handlers
 
 import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"hw/internal/model"
 	"hw/pkg/ethindexa"
 	"hw/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
 )
 
 // var chainlinkETHUSDAddress = common.HexToAddress("0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419")
 
-func HandleTransfer(idx *ethindexa.IndexerService, event ethindexa.Event) {
+func HandleTransfer(idx *ethindexa.IndexerService, event ethindexa.Event) error {
 	logger.Infof("#%s:%s:%s %+v %v", event.NetworkName, event.ContractName, event.EventName, event.ContractAddress, event.Args)
 
 	// trt to call chainlink contract to get eth price
@@ -27,8 +36,27 @@ func HandleTransfer(idx *ethindexa.IndexerService, event ethindexa.Event) {
 	// 	return
 	// }
 	// logger.Infof("latestAnswer: %+v", answer)
+	return nil
 }
 
-func HandleApproval(idx *ethindexa.IndexerService, event ethindexa.Event) {
-	logger.Infof("#%s:%s:%s %+v %v", event.NetworkName, event.ContractName, event.EventName, event.ContractAddress, event.Args)
+// HandleApproval records the allowance an ERC-20 Approval event sets for the spender over the
+// owner's tokens.
+func HandleApproval(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+	owner := event.Args["owner"].(common.Address)
+	spender := event.Args["spender"].(common.Address)
+	err := idx.Service.RecordApproval(event.Ctx, &model.Allowance{
+		Network:         event.NetworkName,
+		Token:           strings.ToLower(event.ContractAddress.Hex()),
+		Owner:           strings.ToLower(owner.Hex()),
+		Spender:         strings.ToLower(spender.Hex()),
+		Amount:          model.NewDecimal(decimal.NewFromBigInt(event.Args["value"].(*big.Int), 0)),
+		TransactionHash: event.TransactionHash.Hex(),
+		BlockNumber:     event.Block.Number().Int64(),
+		LogIndex:        event.LogIndex,
+		UpdatedAt:       time.Unix(event.Block.Time(), 0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record approval of %s by %s: %w", spender.Hex(), owner.Hex(), err)
+	}
+	return nil
 }
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"math/big"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+	"hw/pkg/ethindexa/ethindexatest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestHandleApproval tests that an Approval sets the allowance of its spender.
+func TestHandleApproval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	idx, _ := ethindexatest.NewIndexerService(mockService)
+	builder := ethindexatest.NewEvent("USDC", "base", "Approval").
+		ContractAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913").
+		TxHash("0xabc").
+		Block(20933200, 1727740800).
+		Index(1, 7).
+		Arg("owner", common.HexToAddress("0xABCDEF0000000000000000000000000000000001")).
+		Arg("spender", common.HexToAddress("0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24")).
+		Arg("value", big.NewInt(2500000))
+
+	mockService.EXPECT().RecordApproval(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, allowance *model.Allowance) error {
+		assert.Equal(t, "base", allowance.Network)
+		assert.Equal(t, "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913", allowance.Token)
+		assert.Equal(t, "0xabcdef0000000000000000000000000000000001", allowance.Owner)
+		assert.Equal(t, "0x4752ba5dbc23f44d87826276bf6fd6b1c372ad24", allowance.Spender)
+		assert.Equal(t, "2500000", allowance.Amount.String())
+		assert.Equal(t, int64(20933200), allowance.BlockNumber)
+		assert.Equal(t, uint(7), allowance.LogIndex)
+		return nil
+	})
+
+	assert.NoError(t, HandleApproval(idx, builder.Build()))
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/ethindexa"
+	"hw/pkg/logger"
+
+	"github.com/shopspring/decimal"
+)
+
+// TrackGas returns handler recording first the gas fee the sender of the event's transaction paid,
+// read from the transaction receipt, e.g. for gas rebate campaigns. A transaction is recorded once
+// however many of its logs are handled. Failing to record the gas is logged and does not fail the
+// event.
+func TrackGas(handler ethindexa.EventHandler) ethindexa.EventHandler {
+	return func(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+		recordGasSpend(idx, event)
+		return handler(idx, event)
+	}
+}
+
+func recordGasSpend(idx *ethindexa.IndexerService, event ethindexa.Event) {
+	receipt, err := idx.GetTransactionReceipt(event.TransactionHash)
+	if err != nil {
+		logger.Warnf("#%s:%s:%s failed to get receipt of %s: %v", event.NetworkName, event.ContractName, event.EventName, event.TransactionHash.Hex(), err)
+		return
+	}
+
+	gasPrice := receipt.EffectiveGasPrice
+	if gasPrice == nil {
+		gasPrice = new(big.Int)
+	}
+	spend := &model.GasSpend{
+		Network:         event.NetworkName,
+		TransactionHash: event.TransactionHash.Hex(),
+		Account:         strings.ToLower(event.Transaction.From),
+		BlockNumber:     event.Block.Number().Int64(),
+		GasUsed:         receipt.GasUsed,
+		GasPrice:        model.NewDecimal(decimal.NewFromBigInt(gasPrice, 0)),
+		Fee:             model.NewDecimal(decimal.NewFromBigInt(receipt.GasFee(), 0)),
+		BlockTime:       time.Unix(event.Block.Time(), 0),
+	}
+	if err := idx.Service.RecordGasSpend(event.Ctx, spend); err != nil {
+		logger.Warnf("#%s:%s:%s failed to record gas of %s: %v", event.NetworkName, event.ContractName, event.EventName, event.TransactionHash.Hex(), err)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+	"hw/pkg/ethindexa"
+	"hw/pkg/ethindexa/ethindexatest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestTrackGas tests that the fee of the transaction is recorded for its sender before the event is handled.
+func TestTrackGas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	idx, chain := ethindexatest.NewIndexerService(mockService)
+	chain.AddReceipt(ethindexa.ReceiptInfo{TxHash: common.HexToHash("0xabc"), Status: 1, GasUsed: 100000, EffectiveGasPrice: big.NewInt(20e9)})
+	event := ethindexatest.NewEvent("UniswapV2", "mainnet", "Swap").
+		TxHash("0xabc").
+		From("0xABCDEF0000000000000000000000000000000001").
+		Block(20933200, 1727740800).
+		Build()
+
+	handled := false
+	mockService.EXPECT().RecordGasSpend(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, spend *model.GasSpend) error {
+		assert.Equal(t, "mainnet", spend.Network)
+		assert.Equal(t, "0xabcdef0000000000000000000000000000000001", spend.Account)
+		assert.Equal(t, int64(20933200), spend.BlockNumber)
+		assert.Equal(t, uint64(100000), spend.GasUsed)
+		assert.Equal(t, "20000000000", spend.GasPrice.String())
+		assert.Equal(t, "2000000000000000", spend.Fee.String())
+		assert.False(t, handled, "gas is recorded before the event is handled")
+		return nil
+	})
+	handler := TrackGas(func(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+		handled = true
+		return nil
+	})
+
+	assert.NoError(t, handler(idx, event))
+	assert.True(t, handled)
+}
+
+// TestTrackGas_Failures tests that the event is still handled when the receipt or the gas cannot be
+// recorded.
+func TestTrackGas_Failures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	idx, chain := ethindexatest.NewIndexerService(mockService)
+	builder := ethindexatest.NewEvent("UniswapV2", "mainnet", "Swap").Block(20933200, 1727740800)
+
+	handled := 0
+	handler := TrackGas(func(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+		handled++
+		return nil
+	})
+
+	// No receipt
+	assert.NoError(t, handler(idx, builder.TxHash("0xabc").Build()))
+
+	// Failing store
+	chain.AddReceipt(ethindexa.ReceiptInfo{TxHash: common.HexToHash("0xdef"), GasUsed: 21000})
+	mockService.EXPECT().RecordGasSpend(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+	assert.NoError(t, handler(idx, builder.TxHash("0xdef").Build()))
+
+	assert.Equal(t, 2, handled)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/ethindexa"
+	"hw/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+// HandleLPTransfer records the LP positions changed by a UniswapV2 pair's Transfer event.
+var HandleLPTransfer = PositionTransferHandler("uniswap_v2", model.PositionLP)
+
+// HandleTokenTransfer records the token balances changed by an ERC-20 Transfer event, which keep
+// the holder stats of the token. Balances are only complete when every transfer is indexed: from
+// the deployment block of the token and without filters.
+var HandleTokenTransfer = PositionTransferHandler("erc20", model.PositionToken)
+
+// PositionTransferHandler returns a handler recording the positions changed by the Transfer event
+// of a share token, e.g. LP tokens or vault shares, as positions of a kind in the token contract.
+// Mints are transfers from the zero address and burns transfers to it; neither the zero address
+// nor the contract itself, which holds the shares being burned, holds a position.
+func PositionTransferHandler(protocol, kind string) ethindexa.EventHandler {
+	return func(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+		contract := event.ContractAddress
+		value := event.Args["value"].(*big.Int)
+		if value.Sign() == 0 {
+			return nil
+		}
+
+		for _, side := range []struct {
+			account common.Address
+			delta   *big.Int
+		}{
+			{account: event.Args["from"].(common.Address), delta: new(big.Int).Neg(value)},
+			{account: event.Args["to"].(common.Address), delta: value},
+		} {
+			if side.account == (common.Address{}) || side.account == contract {
+				continue
+			}
+			if err := recordPositionChange(idx, event, protocol, kind, side.account, side.delta); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// PositionStakeHandler returns a handler recording the stake positions changed by the Staked, or
+// Withdrawn when withdraw is set, event of a staking contract with user and amount arguments.
+func PositionStakeHandler(protocol string, withdraw bool) ethindexa.EventHandler {
+	return func(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+		delta := new(big.Int).Set(event.Args["amount"].(*big.Int))
+		if delta.Sign() == 0 {
+			return nil
+		}
+		if withdraw {
+			delta.Neg(delta)
+		}
+
+		return recordPositionChange(idx, event, protocol, model.PositionStake, event.Args["user"].(common.Address), delta)
+	}
+}
+
+func recordPositionChange(idx *ethindexa.IndexerService, event ethindexa.Event, protocol, kind string, account common.Address, delta *big.Int) error {
+	err := idx.Service.RecordPositionChange(event.Ctx, &model.PositionChange{
+		Network:         event.NetworkName,
+		Protocol:        protocol,
+		Kind:            kind,
+		Contract:        strings.ToLower(event.ContractAddress.Hex()),
+		Account:         strings.ToLower(account.Hex()),
+		TransactionHash: event.TransactionHash.Hex(),
+		LogIndex:        event.LogIndex,
+		BlockNumber:     event.Block.Number().Int64(),
+		Delta:           model.NewDecimal(decimal.NewFromBigInt(delta, 0)),
+		BlockTime:       time.Unix(event.Block.Time(), 0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record %s position change of %s: %w", kind, account.Hex(), err)
+	}
+	return nil
+}
+
+// HandleUniswapV2Mint logs liquidity added to a UniswapV2 pair. The LP tokens minted for it are
+// recorded from the Transfer event of the same transaction.
+func HandleUniswapV2Mint(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+	logger.Infof("#%s:%s:%s %s added %v and %v at %d", event.NetworkName, event.ContractName, event.EventName, event.ContractAddress, event.Args["amount0"], event.Args["amount1"], event.Block.Number())
+	return nil
+}
+
+// HandleUniswapV2Burn logs liquidity removed from a UniswapV2 pair. The LP tokens burned for it are
+// recorded from the Transfer events of the same transaction.
+func HandleUniswapV2Burn(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+	logger.Infof("#%s:%s:%s %s removed %v and %v to %v at %d", event.NetworkName, event.ContractName, event.EventName, event.ContractAddress, event.Args["amount0"], event.Args["amount1"], event.Args["to"], event.Block.Number())
+	return nil
+}
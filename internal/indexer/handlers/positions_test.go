@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"math/big"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+	"hw/pkg/ethindexa/ethindexatest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestHandleLPTransfer tests that both sides of a transfer are recorded, except the zero address and the pair.
+func TestHandleLPTransfer(t *testing.T) {
+	provider := "0xabcdef0000000000000000000000000000000001"
+	other := "0xabcdef0000000000000000000000000000000002"
+
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		deltas map[string]int64
+	}{
+		{name: "mint", from: "0x0000000000000000000000000000000000000000", to: provider, deltas: map[string]int64{provider: 500}},
+		{name: "transfer", from: provider, to: other, deltas: map[string]int64{provider: -500, other: 500}},
+		{name: "returned to pair for burn", from: provider, to: USDCWETHPool, deltas: map[string]int64{provider: -500}},
+		{name: "burn", from: USDCWETHPool, to: "0x0000000000000000000000000000000000000000", deltas: map[string]int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockService(ctrl)
+			idx, _ := ethindexatest.NewIndexerService(mockService)
+			event := ethindexatest.NewEvent("UniswapV2", "mainnet", "Transfer").
+				ContractAddress(USDCWETHPool).
+				TxHash("0xabc").
+				Block(20933200, 1727740800).
+				Index(3, 7).
+				Arg("from", common.HexToAddress(tt.from)).
+				Arg("to", common.HexToAddress(tt.to)).
+				Arg("value", big.NewInt(500)).
+				Build()
+
+			recorded := make(map[string]int64)
+			mockService.EXPECT().RecordPositionChange(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, change *model.PositionChange) error {
+				assert.Equal(t, USDCWETHPool, change.Contract)
+				assert.Equal(t, model.PositionLP, change.Kind)
+				assert.Equal(t, uint(7), change.LogIndex)
+				recorded[change.Account] = change.Delta.IntPart()
+				return nil
+			}).Times(len(tt.deltas))
+
+			assert.NoError(t, HandleLPTransfer(idx, event))
+
+			assert.Equal(t, tt.deltas, recorded)
+		})
+	}
+}
+
+// TestPositionStakeHandler tests that stakes increase and withdrawals decrease the stake position of the user.
+func TestPositionStakeHandler(t *testing.T) {
+	staking := "0xabcdef0000000000000000000000000000000099"
+	user := "0xabcdef0000000000000000000000000000000001"
+
+	tests := []struct {
+		name     string
+		event    string
+		withdraw bool
+		amount   int64
+		delta    int64
+	}{
+		{name: "staked", event: "Staked", amount: 500, delta: 500},
+		{name: "withdrawn", event: "Withdrawn", withdraw: true, amount: 200, delta: -200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockService(ctrl)
+			idx, _ := ethindexatest.NewIndexerService(mockService)
+			amount := big.NewInt(tt.amount)
+			event := ethindexatest.NewEvent("StakingRewards", "mainnet", tt.event).
+				ContractAddress(staking).
+				TxHash("0xabc").
+				Block(20933200, 1727740800).
+				Index(3, 7).
+				Arg("user", common.HexToAddress(user)).
+				Arg("amount", amount).
+				Build()
+
+			mockService.EXPECT().RecordPositionChange(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, change *model.PositionChange) error {
+				assert.Equal(t, "synthetix", change.Protocol)
+				assert.Equal(t, model.PositionStake, change.Kind)
+				assert.Equal(t, staking, change.Contract)
+				assert.Equal(t, user, change.Account)
+				assert.Equal(t, tt.delta, change.Delta.IntPart())
+				return nil
+			})
+
+			assert.NoError(t, PositionStakeHandler("synthetix", tt.withdraw)(idx, event))
+
+			assert.Equal(t, tt.amount, amount.Int64())
+		})
+	}
+}
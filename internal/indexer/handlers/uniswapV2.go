@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 	"time"
@@ -11,25 +13,113 @@ import (
 	"hw/pkg/ethindexa"
 	"hw/pkg/logger"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 const (
 	USDCWETHPool = "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
 	USDC         = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	WETH         = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
 )
 
-// HandleUSDCWETHSwap processes a USDC-WETH swap event.
-func HandleUSDCWETHSwap(idx *ethindexa.IndexerService, event ethindexa.Event) {
+// onboardingTask is the points task of the onboarding campaign, and onboardingCampaign its id in
+// points history metadata.
+const (
+	onboardingTask     = "onboarding_task"
+	onboardingCampaign = "onboarding"
+)
+
+var (
+	// onboardingThresholdUSD is the swap volume completing the onboarding task.
+	onboardingThresholdUSD = model.NewDecimalFromFloat(1000)
+	// onboardingPoints is awarded once the onboarding task is completed.
+	onboardingPoints = model.NewDecimalFromFloat(100)
+)
+
+// HandleUniswapV2Swap records a swap of any UniswapV2 pair, valued in USD by Valuation.
+func HandleUniswapV2Swap(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+	_, _, err := recordSwap(idx, event)
+	return err
+}
+
+// HandleUSDCWETHSwap processes a USDC-WETH swap event: it records the swap and the WETH price
+// it implies, and completes the onboarding task of the sender, boosted by the boost of the swap.
+func HandleUSDCWETHSwap(idx *ethindexa.IndexerService, event ethindexa.Event) error {
 	// token0 = USDC
 	// token1 = WETH
 
-	// startTime := time.Now()
-	// defer func() {
-	// 	elapsed := time.Since(startTime)
-	// 	logger.Infof("Elapsed time: %s", elapsed)
-	// }()
+	swapHistory, usdValue, err := recordSwap(idx, event)
+	if err != nil || swapHistory == nil {
+		return err
+	}
+	accountID := swapHistory.Account
+
+	// Record the WETH price implied by the swap; a failure does not affect points
+	recordWETHPrice(idx, event, usdValue)
+
+	// Check if onboarding task is completed
+	completed, err := idx.Service.IsOnboardingTaskCompleted(event.Ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to check onboarding task of %s: %w", accountID, err)
+	}
 
+	// If not completed, verify if onboarding criteria are met
+	if !completed {
+		total, err := idx.Service.GetSwapTotalUsd(event.Ctx, accountID, USDCWETHPool)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve swap total of %s: %w", accountID, err)
+		}
+		counted := total.UsdValue
+		if idx.Service.ExcludesWashTrades(onboardingTask) {
+			counted = counted.Sub(total.WashUsdValue)
+		}
+		if !total.Empty() && counted.GreaterThanOrEqual(onboardingThresholdUSD.Decimal) {
+			if err := idx.Service.AccumulateSwapPoints(event.Ctx, swapHistory, onboardingCampaign, onboardingTask, onboardingPoints); err != nil {
+				return fmt.Errorf("failed to award onboarding points to %s: %w", accountID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// HandleUniswapV2Sync records the reserves of a UniswapV2 pair after a Sync event, with their
+// value in USD when the pair can be valued.
+func HandleUniswapV2Sync(idx *ethindexa.IndexerService, event ethindexa.Event) error {
+	pool := strings.ToLower(event.ContractAddress.Hex())
+	reserve0 := event.Args["reserve0"].(*big.Int)
+	reserve1 := event.Args["reserve1"].(*big.Int)
+
+	reserves := &model.PoolReserves{
+		Network:     event.NetworkName,
+		Pool:        pool,
+		BlockNumber: event.Block.Number().Int64(),
+		LogIndex:    event.LogIndex,
+		Reserve0:    model.NewDecimal(decimal.NewFromBigInt(reserve0, 0)),
+		Reserve1:    model.NewDecimal(decimal.NewFromBigInt(reserve1, 0)),
+		BlockTime:   time.Unix(event.Block.Time(), 0),
+	}
+
+	// Reserves are recorded even when they cannot be valued
+	tvl, err := Valuation.ReservesUSDValue(idx, event, pool, reserve0, reserve1)
+	if err != nil {
+		logger.Warnf("#%s:%s:%s cannot value reserves of %s: %v", event.NetworkName, event.ContractName, event.EventName, pool, err)
+	} else {
+		tvlUsd := model.NewDecimal(tvl.ToTruncateDecimal(6))
+		reserves.TVLUsd = &tvlUsd
+	}
+
+	if err := idx.Service.RecordPoolReserves(event.Ctx, reserves); err != nil {
+		return fmt.Errorf("failed to record reserves of %s: %w", pool, err)
+	}
+	return nil
+}
+
+// recordSwap values a Swap event in USD and records it in the swap history of the sender.
+// It returns a nil swap history without an error when the event was skipped: a pair without a
+// USD valuation route, or a swap already recorded.
+func recordSwap(idx *ethindexa.IndexerService, event ethindexa.Event) (*model.SwapHistory, bigrat.BigN, error) {
 	// Retrieve user account ID
 	accountID := strings.ToLower(event.Transaction.From)
 
@@ -39,53 +129,88 @@ func HandleUSDCWETHSwap(idx *ethindexa.IndexerService, event ethindexa.Event) {
 	// print processed message
 	logger.Infof("#%s:%s:%s %s %s at %d", event.NetworkName, event.ContractName, event.EventName, event.ContractAddress, event.TransactionHash.Hex(), event.Block.Number())
 
-	ctx := context.WithValue(event.Ctx, "reqID", reqID)
+	event.Ctx = context.WithValue(event.Ctx, "reqID", reqID)
 
-	// Retrieve or create USDC token information
-	usdcToken, err := idx.Service.GetOrCreateToken(ctx, idx.Client, USDC, event.Block.Number().Int64())
-	if err != nil {
-		logger.Errorw("Error retrieving USDC token:", err)
-		return
+	// Value the swap in USD
+	usdValue, err := Valuation.SwapUSDValue(idx, event)
+	if errors.Is(err, ErrNoValuationRoute) {
+		logger.Warnf("#%s:%s:%s cannot value swap %s: %v", event.NetworkName, event.ContractName, event.EventName, event.TransactionHash.Hex(), err)
+		return nil, bigrat.BigN{}, nil
 	}
-
-	// Calculate USDC value
-	usdValue := bigrat.NewBigN(event.Args["amount0In"].(*big.Int))
-	if event.Args["amount0Out"].(*big.Int).Cmp(big.NewInt(0)) != 0 {
-		usdValue = bigrat.NewBigN(event.Args["amount0Out"].(*big.Int))
+	if err != nil {
+		return nil, bigrat.BigN{}, fmt.Errorf("failed to value swap: %w", err)
 	}
 
 	// Create swap history record
 	swapHistory := &model.SwapHistory{
-		Token:           USDCWETHPool, // USDC-WETH pool address
+		Network:         event.NetworkName,
+		Token:           strings.ToLower(event.ContractAddress.Hex()), // pool address
 		Account:         accountID,
 		TransactionHash: event.TransactionHash.Hex(),
-		UsdValue:        usdValue.Div(bigrat.NewBigN(10).Pow(usdcToken.Decimals)).ToTruncateFloat64(6),
+		LogIndex:        event.LogIndex,
+		UsdValue:        model.NewDecimal(usdValue.ToTruncateDecimal(6)),
 		LastUpdated:     time.Unix(event.Block.Time(), 0),
+		BlockNumber:     event.Block.Number().Int64(),
 	}
 
-	if err := idx.Service.CreateSwapHistory(event.Ctx, swapHistory); err != nil {
-		logger.Errorw("Error creating swap history:", err)
-		return
+	// Boost the points earned with the swap by the holdings of the account at its block
+	boost, err := idx.Service.ResolvePointsBoost(event.Ctx, event.NetworkName, accountID, balanceReader(idx, event, accountID))
+	if err != nil {
+		return nil, bigrat.BigN{}, fmt.Errorf("failed to resolve points boost: %w", err)
 	}
+	swapHistory.Boost = boost
 
-	// Check if onboarding task is completed
-	completed, err := idx.Service.IsOnboardingTaskCompleted(event.Ctx, accountID)
+	// A redelivered swap was counted the first time it was handled
+	err = idx.Service.CreateSwapHistory(event.Ctx, swapHistory)
+	if errors.Is(err, model.ErrAlreadyExists) {
+		logger.Infof("#%s:%s:%s swap %s:%d already recorded", event.NetworkName, event.ContractName, event.EventName, event.TransactionHash.Hex(), event.LogIndex)
+		return nil, bigrat.BigN{}, nil
+	}
 	if err != nil {
-		logger.Errorw("Error checking onboarding task status:", err)
-		return
+		return nil, bigrat.BigN{}, fmt.Errorf("failed to record swap: %w", err)
 	}
 
-	// If not completed, verify if onboarding criteria are met
-	if !completed {
-		totalUSD, err := idx.Service.GetSwapTotalUsd(event.Ctx, accountID, USDCWETHPool)
-		if err != nil {
-			logger.Errorw("Error retrieving total swap USD:", err)
-			return
-		}
-		if totalUSD >= 1000 {
-			if err := idx.Service.AccumulateUserPoints(event.Ctx, USDCWETHPool, accountID, "onboarding_task", 100); err != nil {
-				logger.Errorw("Error accumulating user points:", err)
-			}
+	// A swap paying the trader back may close a round trip through the pool
+	if returnsToTrader(event) {
+		if _, err := idx.Service.FlagRoundTripSwaps(event.Ctx, swapHistory); err != nil {
+			return nil, bigrat.BigN{}, fmt.Errorf("failed to flag round trip of %s: %w", accountID, err)
 		}
 	}
+
+	return swapHistory, usdValue, nil
+}
+
+// returnsToTrader reports whether the output of a Swap event goes back to the trader, the sender
+// of the transaction: paid to the trader, or to the contract the transaction called, e.g. an
+// aggregator router forwarding it to the trader or a contract of the trader. The output of a hop
+// of a route paid to the next pool does not.
+func returnsToTrader(event ethindexa.Event) bool {
+	recipient, ok := event.Args["to"].(common.Address)
+	if !ok {
+		return false
+	}
+	return strings.EqualFold(recipient.Hex(), event.Transaction.From) ||
+		(event.Transaction.To != "" && strings.EqualFold(recipient.Hex(), event.Transaction.To))
+}
+
+// recordWETHPrice records the USD price of WETH implied by a USDC-WETH swap of usdValue.
+func recordWETHPrice(idx *ethindexa.IndexerService, event ethindexa.Event, usdValue bigrat.BigN) {
+	wethAmount, err := Valuation.wholeAmount(idx, event, WETH, swapAmount(event, "1"))
+	if err != nil {
+		logger.Errorw("Error retrieving WETH token:", err)
+		return
+	}
+	if !wethAmount.Gt(0) {
+		return
+	}
+
+	price := &model.TokenPrice{
+		Network: event.NetworkName,
+		Token:   WETH,
+		Price:   model.NewDecimal(usdValue.Div(wethAmount).ToTruncateDecimal(18)),
+		Time:    time.Unix(event.Block.Time(), 0),
+	}
+	if err := idx.Service.RecordTokenPrice(event.Ctx, price); err != nil {
+		logger.Errorw("Error recording WETH price:", err)
+	}
 }
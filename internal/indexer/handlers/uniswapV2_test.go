@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/service"
+	"hw/internal/service/mocks"
+	"hw/pkg/ethindexa/ethindexatest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// newSwapEvent builds a USDC-WETH Swap event where the sender pays amount0In USDC for 0.6 WETH.
+func newSwapEvent(amount0In int64) *ethindexatest.EventBuilder {
+	return ethindexatest.NewEvent("UniswapV2", "mainnet", "Swap").
+		ContractAddress(USDCWETHPool).
+		TxHash("0xabc").
+		From("0xAbCdEf0000000000000000000000000000000001").
+		Block(20933200, 1727740800).
+		Arg("amount0In", big.NewInt(amount0In)).
+		Arg("amount0Out", big.NewInt(0)).
+		Arg("amount1In", big.NewInt(0)).
+		Arg("amount1Out", big.NewInt(600_000000_000000_000))
+}
+
+// stubPair serves the tokens of a pair.
+func stubPair(chain *ethindexatest.FakeChain, pair, token0, token1 string) {
+	chain.StubCall(common.HexToAddress(pair), "token0", []interface{}{common.HexToAddress(token0)}, nil)
+	chain.StubCall(common.HexToAddress(pair), "token1", []interface{}{common.HexToAddress(token1)}, nil)
+}
+
+// TestHandleUSDCWETHSwap_Onboarding tests that a swap is recorded and completes the onboarding task once 1000 USD is reached.
+func TestHandleUSDCWETHSwap_Onboarding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	idx, chain := ethindexatest.NewIndexerService(mockService)
+	stubPair(chain, USDCWETHPool, USDC, WETH)
+	event := newSwapEvent(1500_000000).Index(2, 7).Build()
+	account := "0xabcdef0000000000000000000000000000000001"
+
+	mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, USDC, int64(20933200)).Return(&model.Token{ID: USDC, Decimals: 6}, nil)
+	mockService.EXPECT().ResolvePointsBoost(gomock.Any(), "mainnet", account, gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().CreateSwapHistory(gomock.Any(), &model.SwapHistory{
+		Network:         "mainnet",
+		Token:           USDCWETHPool,
+		Account:         account,
+		TransactionHash: event.TransactionHash.Hex(),
+		LogIndex:        7,
+		UsdValue:        model.NewDecimal(decimal.New(1500_000000, -6)),
+		LastUpdated:     time.Unix(1727740800, 0),
+		BlockNumber:     20933200,
+	}).Return(nil)
+	mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, WETH, int64(20933200)).Return(&model.Token{ID: WETH, Decimals: 18}, nil)
+	mockService.EXPECT().RecordTokenPrice(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, price *model.TokenPrice) error {
+		assert.Equal(t, "mainnet", price.Network)
+		assert.Equal(t, WETH, price.Token)
+		assert.True(t, price.Price.Equal(decimal.NewFromInt(2500)), "1500 USDC for 0.6 WETH is 2500 USD per WETH")
+		assert.Equal(t, time.Unix(1727740800, 0), price.Time)
+		return nil
+	})
+	mockService.EXPECT().IsOnboardingTaskCompleted(gomock.Any(), account).Return(false, nil)
+	mockService.EXPECT().GetSwapTotalUsd(gomock.Any(), account, USDCWETHPool).Return(model.SwapTotal{UsdValue: model.NewDecimalFromFloat(1500), SwapCount: 2}, nil)
+	mockService.EXPECT().ExcludesWashTrades("onboarding_task").Return(true)
+	mockService.EXPECT().AccumulateSwapPoints(gomock.Any(), gomock.Any(), "onboarding", "onboarding_task", model.NewDecimalFromFloat(100)).DoAndReturn(func(_ interface{}, history *model.SwapHistory, _, _ string, _ model.Decimal) error {
+		assert.Equal(t, event.TransactionHash.Hex(), history.TransactionHash)
+		return nil
+	})
+
+	assert.NoError(t, HandleUSDCWETHSwap(idx, event))
+}
+
+// TestHandleUSDCWETHSwap_BelowThreshold tests that no points are granted while the swap total is under 1000 USD,
+// and that failing to record the WETH price does not stop the swap from being processed.
+func TestHandleUSDCWETHSwap_BelowThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	idx, chain := ethindexatest.NewIndexerService(mockService)
+	stubPair(chain, USDCWETHPool, USDC, WETH)
+	event := newSwapEvent(250_000000).Build()
+
+	mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, USDC, gomock.Any()).Return(&model.Token{ID: USDC, Decimals: 6}, nil)
+	mockService.EXPECT().ResolvePointsBoost(gomock.Any(), "mainnet", gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().CreateSwapHistory(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, history *model.SwapHistory) error {
+		assert.True(t, history.UsdValue.Equal(decimal.NewFromInt(250)))
+		return nil
+	})
+	mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, WETH, gomock.Any()).Return(&model.Token{ID: WETH, Decimals: 18}, nil)
+	mockService.EXPECT().RecordTokenPrice(gomock.Any(), gomock.Any()).Return(errors.New("database unavailable"))
+	mockService.EXPECT().IsOnboardingTaskCompleted(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockService.EXPECT().GetSwapTotalUsd(gomock.Any(), gomock.Any(), USDCWETHPool).Return(model.SwapTotal{UsdValue: model.NewDecimalFromFloat(250), SwapCount: 1}, nil)
+	mockService.EXPECT().ExcludesWashTrades(gomock.Any()).Return(false)
+
+	assert.NoError(t, HandleUSDCWETHSwap(idx, event))
+}
+
+// TestHandleUSDCWETHSwap_RoundTrip tests that a swap paying the trader back through the router
+// flags its round trip, and that the wash trades are left out of the onboarding total.
+func TestHandleUSDCWETHSwap_RoundTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	idx, chain := ethindexatest.NewIndexerService(mockService)
+	stubPair(chain, USDCWETHPool, USDC, WETH)
+	router := "0x7a250d5630b4cf539739df2c5dacb4c659f2488d"
+	event := newSwapEvent(1500_000000).To(router).Arg("to", common.HexToAddress(router)).Build()
+
+	mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, gomock.Any(), gomock.Any()).Return(&model.Token{ID: USDC, Decimals: 6}, nil).AnyTimes()
+	mockService.EXPECT().ResolvePointsBoost(gomock.Any(), "mainnet", gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().CreateSwapHistory(gomock.Any(), gomock.Any()).Return(nil)
+	mockService.EXPECT().FlagRoundTripSwaps(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, history *model.SwapHistory) (bool, error) {
+		assert.Equal(t, event.TransactionHash.Hex(), history.TransactionHash)
+		history.WashTrade = true
+		return true, nil
+	})
+	mockService.EXPECT().RecordTokenPrice(gomock.Any(), gomock.Any()).Return(nil)
+	mockService.EXPECT().IsOnboardingTaskCompleted(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockService.EXPECT().GetSwapTotalUsd(gomock.Any(), gomock.Any(), USDCWETHPool).Return(model.SwapTotal{UsdValue: model.NewDecimalFromFloat(3000), WashUsdValue: model.NewDecimalFromFloat(3000), SwapCount: 2}, nil)
+	mockService.EXPECT().ExcludesWashTrades("onboarding_task").Return(true)
+
+	assert.NoError(t, HandleUSDCWETHSwap(idx, event))
+}
+
+// TestHandleUSDCWETHSwap_AlreadyRecorded tests that a redelivered swap neither records a price nor
+// earns onboarding points again.
+func TestHandleUSDCWETHSwap_AlreadyRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	idx, chain := ethindexatest.NewIndexerService(mockService)
+	stubPair(chain, USDCWETHPool, USDC, WETH)
+	event := newSwapEvent(1500_000000).Build()
+
+	mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, USDC, gomock.Any()).Return(&model.Token{ID: USDC, Decimals: 6}, nil)
+	mockService.EXPECT().ResolvePointsBoost(gomock.Any(), "mainnet", gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().CreateSwapHistory(gomock.Any(), gomock.Any()).Return(fmt.Errorf("swap 0xabc:0: %w", model.ErrAlreadyExists))
+
+	assert.NoError(t, HandleUSDCWETHSwap(idx, event))
+}
+
+// TestHandleUSDCWETHSwap_Boost tests that the holdings of the sender are read at the block of the
+// swap, and that the boost they give is recorded with the swap and applied to the onboarding points.
+func TestHandleUSDCWETHSwap_Boost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	idx, chain := ethindexatest.NewIndexerService(mockService)
+	stubPair(chain, USDCWETHPool, USDC, WETH)
+	nft := "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d"
+	chain.StubCall(common.HexToAddress(nft), "balanceOf", []interface{}{big.NewInt(2)}, nil)
+	event := newSwapEvent(1500_000000).Build()
+	account := "0xabcdef0000000000000000000000000000000001"
+	boost := &model.PointsBoost{ID: "og_nft", Multiplier: model.NewDecimalFromFloat(1.5)}
+
+	mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, gomock.Any(), gomock.Any()).Return(&model.Token{ID: USDC, Decimals: 6}, nil).AnyTimes()
+	mockService.EXPECT().ResolvePointsBoost(gomock.Any(), "mainnet", account, gomock.Any()).DoAndReturn(func(_ interface{}, _, _ string, balanceOf service.BalanceReader) (*model.PointsBoost, error) {
+		balance, err := balanceOf(nft)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(2), balance)
+		return boost, nil
+	})
+	mockService.EXPECT().CreateSwapHistory(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, history *model.SwapHistory) error {
+		assert.Equal(t, boost, history.Boost)
+		return nil
+	})
+	mockService.EXPECT().RecordTokenPrice(gomock.Any(), gomock.Any()).Return(nil)
+	mockService.EXPECT().IsOnboardingTaskCompleted(gomock.Any(), account).Return(false, nil)
+	mockService.EXPECT().GetSwapTotalUsd(gomock.Any(), account, USDCWETHPool).Return(model.SwapTotal{UsdValue: model.NewDecimalFromFloat(1500), SwapCount: 1}, nil)
+	mockService.EXPECT().ExcludesWashTrades("onboarding_task").Return(false)
+	mockService.EXPECT().AccumulateSwapPoints(gomock.Any(), gomock.Any(), "onboarding", "onboarding_task", model.NewDecimalFromFloat(100)).DoAndReturn(func(_ interface{}, history *model.SwapHistory, _, _ string, _ model.Decimal) error {
+		assert.Equal(t, boost, history.Boost)
+		return nil
+	})
+
+	assert.NoError(t, HandleUSDCWETHSwap(idx, event))
+
+	var read *ethindexatest.ContractCall
+	for _, call := range chain.Calls() {
+		if call.Function == "balanceOf" {
+			read = &call
+		}
+	}
+	if assert.NotNil(t, read) {
+		assert.Equal(t, big.NewInt(20933200), read.Block)
+		assert.Equal(t, []interface{}{common.HexToAddress(account)}, read.Params)
+	}
+}
+
+// TestReturnsToTrader tests which recipients of a swap output count as the trader.
+func TestReturnsToTrader(t *testing.T) {
+	trader := "0xAbCdEf0000000000000000000000000000000001"
+	router := "0x7a250d5630b4cf539739df2c5dacb4c659f2488d"
+
+	tests := []struct {
+		name      string
+		recipient string
+		returns   bool
+	}{
+		{name: "trader", recipient: trader, returns: true},
+		{name: "router", recipient: router, returns: true},
+		{name: "next pool", recipient: "0x0d4a11d5eeaac28ec3f61d100daf4d40471f1852", returns: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := newSwapEvent(1).From(trader).To(router).Arg("to", common.HexToAddress(tt.recipient)).Build()
+			assert.Equal(t, tt.returns, returnsToTrader(event))
+		})
+	}
+	assert.False(t, returnsToTrader(newSwapEvent(1).Build()), "a swap without a recipient")
+}
+
+// TestHandleUniswapV2Sync tests that reserves are recorded with their TVL, and without it when the pair cannot be valued.
+func TestHandleUniswapV2Sync(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		tvl     *decimal.Decimal
+	}{
+		{name: "valued", network: "mainnet", tvl: func() *decimal.Decimal { d := decimal.NewFromInt(5_200_000); return &d }()},
+		{name: "no valuation route", network: "base"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockService(ctrl)
+			idx, chain := ethindexatest.NewIndexerService(mockService)
+			stubPair(chain, USDCWETHPool, USDC, WETH)
+			event := ethindexatest.NewEvent("UniswapV2", tt.network, "Sync").
+				ContractAddress(USDCWETHPool).
+				Block(20933200, 1727740800).
+				Index(3, 7).
+				Arg("reserve0", big.NewInt(2_600_000_000000)).
+				Arg("reserve1", new(big.Int).Mul(big.NewInt(1000), big.NewInt(1_000000_000000_000000))).
+				Build()
+
+			if tt.tvl != nil {
+				mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, USDC, int64(20933200)).Return(&model.Token{ID: USDC, Decimals: 6}, nil)
+			}
+			mockService.EXPECT().RecordPoolReserves(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, reserves *model.PoolReserves) error {
+				assert.Equal(t, USDCWETHPool, reserves.Pool)
+				assert.Equal(t, int64(20933200), reserves.BlockNumber)
+				assert.Equal(t, uint(7), reserves.LogIndex)
+				assert.True(t, reserves.Reserve0.Equal(decimal.NewFromInt(2_600_000_000000)))
+				if tt.tvl == nil {
+					assert.Nil(t, reserves.TVLUsd)
+				} else if assert.NotNil(t, reserves.TVLUsd) {
+					assert.True(t, reserves.TVLUsd.Equal(*tt.tvl), "twice the 2.6M USDC reserve")
+				}
+				return nil
+			})
+
+			assert.NoError(t, HandleUniswapV2Sync(idx, event))
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/bigrat"
+	"hw/pkg/ethindexa"
+	"hw/pkg/ethindexa/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	USDT             = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	DAI              = "0x6b175474e89094c44da98b954eedeac495271d0f"
+	UniswapV2Factory = "0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f"
+)
+
+// DefaultMaxPriceAge is the age above which a recorded WETH price is ignored and the reserves
+// of the USD-WETH pair are read instead.
+var DefaultMaxPriceAge = 10 * time.Minute
+
+// ErrNoValuationRoute is returned when a token cannot be valued in USD on a network.
+var ErrNoValuationRoute = errors.New("no USD valuation route")
+
+// ValuationTokens are the reference tokens and contracts of a network used to value swaps in USD.
+type ValuationTokens struct {
+	Stablecoins []string // tokens valued at one dollar
+	WETH        string
+	USDWETHPair string // UniswapV2 pair of a stablecoin and WETH, read when no recent WETH price was recorded
+	Factory     string // UniswapV2 factory resolving the WETH pair of other tokens
+}
+
+// ValuationService values token amounts in USD at the block of an event. Stablecoins are worth
+// one dollar. WETH is worth its latest price recorded in token_prices, or the price implied by
+// the reserves of the USD-WETH pair when none is recent enough. Any other token is routed
+// through its WETH pair: its price in WETH from the pair reserves times the price of WETH.
+type ValuationService struct {
+	networks    map[string]ValuationTokens
+	maxPriceAge time.Duration
+
+	abiOnce    sync.Once
+	pairABI    abi.ABI
+	factoryABI abi.ABI
+	abiErr     error
+
+	pairs sync.Map // {network}:{pair} to the [2]string tokens of the pair, which never change
+}
+
+// NewValuationService creates a ValuationService for the given networks.
+func NewValuationService(networks map[string]ValuationTokens, maxPriceAge time.Duration) *ValuationService {
+	return &ValuationService{networks: networks, maxPriceAge: maxPriceAge}
+}
+
+// Valuation values the swaps of every swap handler.
+var Valuation = NewValuationService(map[string]ValuationTokens{
+	"mainnet": {
+		Stablecoins: []string{USDC, USDT, DAI},
+		WETH:        WETH,
+		USDWETHPair: USDCWETHPool,
+		Factory:     UniswapV2Factory,
+	},
+}, DefaultMaxPriceAge)
+
+// SwapUSDValue values a UniswapV2 Swap event in USD. The side of the swap holding a stablecoin
+// is valued, else the side holding WETH, else token0.
+func (v *ValuationService) SwapUSDValue(idx *ethindexa.IndexerService, event ethindexa.Event) (bigrat.BigN, error) {
+	pair := strings.ToLower(event.ContractAddress.Hex())
+	token0, token1, err := v.pairTokens(idx, event, pair)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+
+	token, amount := token0, swapAmount(event, "0")
+	if v.rank(event.NetworkName, token1) < v.rank(event.NetworkName, token0) {
+		token, amount = token1, swapAmount(event, "1")
+	}
+	return v.USDValue(idx, event, token, amount)
+}
+
+// ReservesUSDValue values the reserves of a UniswapV2 pair in USD, the total value locked: both
+// reserves of a pair are worth the same, so the reserve of the token valued like in SwapUSDValue
+// is doubled.
+func (v *ValuationService) ReservesUSDValue(idx *ethindexa.IndexerService, event ethindexa.Event, pair string, reserve0, reserve1 *big.Int) (bigrat.BigN, error) {
+	token0, token1, err := v.pairTokens(idx, event, pair)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+
+	token, reserve := token0, reserve0
+	if v.rank(event.NetworkName, token1) < v.rank(event.NetworkName, token0) {
+		token, reserve = token1, reserve1
+	}
+	value, err := v.USDValue(idx, event, token, reserve)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+	return value.Mul(2), nil
+}
+
+// USDValue values an amount in the smallest unit of a token in USD at the block of event.
+func (v *ValuationService) USDValue(idx *ethindexa.IndexerService, event ethindexa.Event, token string, amount *big.Int) (bigrat.BigN, error) {
+	price, err := v.PriceUSD(idx, event, token)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+	whole, err := v.wholeAmount(idx, event, token, amount)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+	value := whole.Mul(price)
+	return value, value.Error()
+}
+
+// PriceUSD returns the USD price of one whole token at the block of event.
+func (v *ValuationService) PriceUSD(idx *ethindexa.IndexerService, event ethindexa.Event, token string) (bigrat.BigN, error) {
+	tokens, exists := v.networks[event.NetworkName]
+	if !exists {
+		return bigrat.BigN{}, fmt.Errorf("%w for %s on %s", ErrNoValuationRoute, token, event.NetworkName)
+	}
+	token = strings.ToLower(token)
+
+	switch {
+	case tokens.isStablecoin(token):
+		return bigrat.New(1), nil
+	case token == tokens.WETH:
+		return v.wethPriceUSD(idx, event, tokens)
+	}
+
+	pair, err := v.wethPair(idx, event, tokens, token)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+	priceInWETH, err := v.pairPrice(idx, event, pair, token)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+	wethPrice, err := v.wethPriceUSD(idx, event, tokens)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+	price := priceInWETH.Mul(wethPrice)
+	return price, price.Error()
+}
+
+// rank orders the tokens of a pair by how directly they are valued: stablecoins, WETH, others.
+func (v *ValuationService) rank(network, token string) int {
+	tokens := v.networks[network]
+	switch {
+	case tokens.isStablecoin(token):
+		return 0
+	case token == tokens.WETH:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// wethPriceUSD returns the latest recorded price of WETH, or reads it from the USD-WETH pair.
+func (v *ValuationService) wethPriceUSD(idx *ethindexa.IndexerService, event ethindexa.Event, tokens ValuationTokens) (bigrat.BigN, error) {
+	at := time.Unix(event.Block.Time(), 0)
+	price, err := idx.Service.GetLatestTokenPrice(event.Ctx, tokens.WETH, event.NetworkName, at, v.maxPriceAge)
+	if err == nil {
+		return bigrat.New(price.Price.Decimal), nil
+	}
+	if !errors.Is(err, model.ErrPriceNotFound) {
+		return bigrat.BigN{}, fmt.Errorf("failed to look up WETH price: %w", err)
+	}
+	if tokens.USDWETHPair == "" {
+		return bigrat.BigN{}, fmt.Errorf("%w for WETH on %s", ErrNoValuationRoute, event.NetworkName)
+	}
+	return v.pairPrice(idx, event, tokens.USDWETHPair, tokens.WETH)
+}
+
+// wethPair returns the UniswapV2 pair of a token and WETH.
+func (v *ValuationService) wethPair(idx *ethindexa.IndexerService, event ethindexa.Event, tokens ValuationTokens, token string) (string, error) {
+	if tokens.Factory == "" {
+		return "", fmt.Errorf("%w for %s on %s", ErrNoValuationRoute, token, event.NetworkName)
+	}
+	if err := v.loadABIs(); err != nil {
+		return "", err
+	}
+	result, err := idx.ReadContract(common.HexToAddress(tokens.Factory), v.factoryABI, event.Block.Number(), "getPair", common.HexToAddress(token), common.HexToAddress(tokens.WETH))
+	if err != nil {
+		return "", fmt.Errorf("failed to read WETH pair of %s: %w", token, err)
+	}
+	pair := result.([]interface{})[0].(common.Address)
+	if pair == (common.Address{}) {
+		return "", fmt.Errorf("%w for %s on %s: no WETH pair", ErrNoValuationRoute, token, event.NetworkName)
+	}
+	return strings.ToLower(pair.Hex()), nil
+}
+
+// pairPrice returns the price of one whole token in the other token of a pair, from the reserves
+// of the pair at the block of event.
+func (v *ValuationService) pairPrice(idx *ethindexa.IndexerService, event ethindexa.Event, pair, token string) (bigrat.BigN, error) {
+	token0, token1, err := v.pairTokens(idx, event, pair)
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+	result, err := idx.ReadContract(common.HexToAddress(pair), v.pairABI, event.Block.Number(), "getReserves")
+	if err != nil {
+		return bigrat.BigN{}, fmt.Errorf("failed to read reserves of %s: %w", pair, err)
+	}
+	reserves := result.([]interface{})
+
+	reserve0, err := v.wholeAmount(idx, event, token0, reserves[0].(*big.Int))
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+	reserve1, err := v.wholeAmount(idx, event, token1, reserves[1].(*big.Int))
+	if err != nil {
+		return bigrat.BigN{}, err
+	}
+
+	var price bigrat.BigN
+	switch token {
+	case token0:
+		price = reserve1.Div(reserve0)
+	case token1:
+		price = reserve0.Div(reserve1)
+	default:
+		return bigrat.BigN{}, fmt.Errorf("token %s is not in pair %s", token, pair)
+	}
+	if err := price.Error(); err != nil {
+		return bigrat.BigN{}, fmt.Errorf("pair %s has no liquidity: %w", pair, err)
+	}
+	return price, nil
+}
+
+// pairTokens returns the lowercased token0 and token1 of a pair.
+func (v *ValuationService) pairTokens(idx *ethindexa.IndexerService, event ethindexa.Event, pair string) (string, string, error) {
+	key := event.NetworkName + ":" + pair
+	if tokens, ok := v.pairs.Load(key); ok {
+		return tokens.([2]string)[0], tokens.([2]string)[1], nil
+	}
+	if err := v.loadABIs(); err != nil {
+		return "", "", err
+	}
+
+	var tokens [2]string
+	for i, function := range []string{"token0", "token1"} {
+		result, err := idx.ReadContract(common.HexToAddress(pair), v.pairABI, event.Block.Number(), function)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read %s of %s: %w", function, pair, err)
+		}
+		tokens[i] = strings.ToLower(result.([]interface{})[0].(common.Address).Hex())
+	}
+	v.pairs.Store(key, tokens)
+	return tokens[0], tokens[1], nil
+}
+
+// wholeAmount converts an amount in the smallest unit of a token to whole tokens.
+func (v *ValuationService) wholeAmount(idx *ethindexa.IndexerService, event ethindexa.Event, token string, amount *big.Int) (bigrat.BigN, error) {
+	info, err := idx.Service.GetOrCreateToken(event.Ctx, idx.Client, token, event.Block.Number().Int64())
+	if err != nil {
+		return bigrat.BigN{}, fmt.Errorf("failed to retrieve token %s: %w", token, err)
+	}
+	return bigrat.New(amount).Div(bigrat.New(10).Pow(info.Decimals)), nil
+}
+
+func (v *ValuationService) loadABIs() error {
+	v.abiOnce.Do(func() {
+		if v.pairABI, v.abiErr = utils.LoadABI("uniswapV2"); v.abiErr != nil {
+			return
+		}
+		v.factoryABI, v.abiErr = utils.LoadABI("uniswapV2Factory")
+	})
+	return v.abiErr
+}
+
+func (t ValuationTokens) isStablecoin(token string) bool {
+	for _, stablecoin := range t.Stablecoins {
+		if token == stablecoin {
+			return true
+		}
+	}
+	return false
+}
+
+// swapAmount returns the amount of token0 or token1 ("0" or "1") swapped by a Swap event:
+// the amount out when it is not zero, else the amount in.
+func swapAmount(event ethindexa.Event, side string) *big.Int {
+	if amountOut := event.Args["amount"+side+"Out"].(*big.Int); amountOut.Sign() != 0 {
+		return amountOut
+	}
+	return event.Args["amount"+side+"In"].(*big.Int)
+}
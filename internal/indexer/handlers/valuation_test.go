@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+	"hw/pkg/ethindexa/ethindexatest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	AAVE         = "0x7fc66500c84a76ad7e9c93437bfc5ac33e2ddae9"
+	AAVEWETHPair = "0xdfc14d2af169b0d36c4eff567ada9b2e0cae044f"
+)
+
+// tokenDecimals serves the decimals of the tokens used by the valuation tests.
+func tokenDecimals(mockService *mocks.MockService) {
+	decimals := map[string]int64{USDC: 6, WETH: 18, AAVE: 18}
+	mockService.EXPECT().GetOrCreateToken(gomock.Any(), nil, gomock.Any(), int64(20933200)).DoAndReturn(
+		func(_ interface{}, _ interface{}, token string, _ int64) (*model.Token, error) {
+			return &model.Token{ID: token, Decimals: decimals[token]}, nil
+		}).AnyTimes()
+}
+
+// stubReserves serves the reserves of a pair.
+func stubReserves(chain *ethindexatest.FakeChain, pair string, reserve0, reserve1 *big.Int) {
+	chain.StubCall(common.HexToAddress(pair), "getReserves", []interface{}{reserve0, reserve1, uint32(1727740800)}, nil)
+}
+
+// ether returns n whole tokens of 18 decimals.
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1_000000_000000_000000))
+}
+
+// TestValuationService_PriceUSD tests that stablecoins, WETH and tokens routed through their WETH pair are priced.
+func TestValuationService_PriceUSD(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		token   string
+		setup   func(mockService *mocks.MockService, chain *ethindexatest.FakeChain)
+		price   int64
+		err     error
+	}{
+		{
+			name:    "stablecoin",
+			network: "mainnet",
+			token:   USDC,
+			price:   1,
+		},
+		{
+			name:    "recorded WETH price",
+			network: "mainnet",
+			token:   WETH,
+			setup: func(mockService *mocks.MockService, chain *ethindexatest.FakeChain) {
+				mockService.EXPECT().GetLatestTokenPrice(gomock.Any(), WETH, "mainnet", gomock.Any(), DefaultMaxPriceAge).
+					Return(&model.TokenPrice{Price: model.NewDecimalFromFloat(2500)}, nil)
+			},
+			price: 2500,
+		},
+		{
+			name:    "WETH from reserves",
+			network: "mainnet",
+			token:   WETH,
+			setup: func(mockService *mocks.MockService, chain *ethindexatest.FakeChain) {
+				mockService.EXPECT().GetLatestTokenPrice(gomock.Any(), WETH, "mainnet", gomock.Any(), DefaultMaxPriceAge).
+					Return(nil, model.ErrPriceNotFound)
+				stubPair(chain, USDCWETHPool, USDC, WETH)
+				stubReserves(chain, USDCWETHPool, big.NewInt(2_600_000_000000), ether(1000))
+			},
+			price: 2600,
+		},
+		{
+			name:    "routed through WETH",
+			network: "mainnet",
+			token:   AAVE,
+			setup: func(mockService *mocks.MockService, chain *ethindexatest.FakeChain) {
+				chain.StubCall(common.HexToAddress(UniswapV2Factory), "getPair", []interface{}{common.HexToAddress(AAVEWETHPair)}, nil)
+				stubPair(chain, AAVEWETHPair, AAVE, WETH)
+				stubReserves(chain, AAVEWETHPair, ether(1000), ether(40))
+				mockService.EXPECT().GetLatestTokenPrice(gomock.Any(), WETH, "mainnet", gomock.Any(), DefaultMaxPriceAge).
+					Return(&model.TokenPrice{Price: model.NewDecimalFromFloat(2500)}, nil)
+			},
+			price: 100,
+		},
+		{
+			name:    "no WETH pair",
+			network: "mainnet",
+			token:   AAVE,
+			setup: func(mockService *mocks.MockService, chain *ethindexatest.FakeChain) {
+				chain.StubCall(common.HexToAddress(UniswapV2Factory), "getPair", []interface{}{common.Address{}}, nil)
+			},
+			err: ErrNoValuationRoute,
+		},
+		{
+			name:    "unknown network",
+			network: "base",
+			token:   USDC,
+			err:     ErrNoValuationRoute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockService(ctrl)
+			tokenDecimals(mockService)
+			idx, chain := ethindexatest.NewIndexerService(mockService)
+			if tt.setup != nil {
+				tt.setup(mockService, chain)
+			}
+			valuation := NewValuationService(map[string]ValuationTokens{
+				"mainnet": {Stablecoins: []string{USDC}, WETH: WETH, USDWETHPair: USDCWETHPool, Factory: UniswapV2Factory},
+			}, DefaultMaxPriceAge)
+			event := ethindexatest.NewEvent("UniswapV2", tt.network, "Swap").Block(20933200, 1727740800).Build()
+
+			price, err := valuation.PriceUSD(idx, event, tt.token)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, price.Eq(tt.price), "price %s, want %d", price.ToTruncateString(6), tt.price)
+		})
+	}
+}
+
+// TestHandleUniswapV2Swap tests that a swap of a pair without a stablecoin is valued through its WETH side.
+func TestHandleUniswapV2Swap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	tokenDecimals(mockService)
+	idx, chain := ethindexatest.NewIndexerService(mockService)
+	stubPair(chain, AAVEWETHPair, AAVE, WETH)
+	event := ethindexatest.NewEvent("UniswapV2", "mainnet", "Swap").
+		ContractAddress(AAVEWETHPair).
+		TxHash("0xdef").
+		From("0xAbCdEf0000000000000000000000000000000001").
+		Block(20933200, 1727740800).
+		Arg("amount0In", ether(10)).
+		Arg("amount0Out", big.NewInt(0)).
+		Arg("amount1In", big.NewInt(0)).
+		Arg("amount1Out", new(big.Int).Div(ether(1), big.NewInt(2))).
+		Build()
+
+	mockService.EXPECT().GetLatestTokenPrice(gomock.Any(), WETH, "mainnet", gomock.Any(), DefaultMaxPriceAge).
+		Return(&model.TokenPrice{Price: model.NewDecimalFromFloat(2500)}, nil)
+	mockService.EXPECT().ResolvePointsBoost(gomock.Any(), "mainnet", gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().CreateSwapHistory(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, history *model.SwapHistory) error {
+		assert.Equal(t, AAVEWETHPair, history.Token)
+		assert.Equal(t, "0xabcdef0000000000000000000000000000000001", history.Account)
+		assert.True(t, history.UsdValue.Equal(decimal.NewFromInt(1250)), "0.5 WETH at 2500 USD")
+		return nil
+	})
+
+	assert.NoError(t, HandleUniswapV2Swap(idx, event))
+}
+
+// TestHandleUniswapV2Swap_Errors tests that a swap that cannot be valued is skipped, while a swap
+// that cannot be recorded fails the handler.
+func TestHandleUniswapV2Swap_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		setup   func(mockService *mocks.MockService)
+		wantErr bool
+	}{
+		{
+			name:    "no valuation route",
+			network: "base",
+		},
+		{
+			name:    "swap history not recorded",
+			network: "mainnet",
+			setup: func(mockService *mocks.MockService) {
+				mockService.EXPECT().GetLatestTokenPrice(gomock.Any(), WETH, "mainnet", gomock.Any(), DefaultMaxPriceAge).
+					Return(&model.TokenPrice{Price: model.NewDecimalFromFloat(2500)}, nil)
+				mockService.EXPECT().ResolvePointsBoost(gomock.Any(), "mainnet", gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockService.EXPECT().CreateSwapHistory(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockService(ctrl)
+			tokenDecimals(mockService)
+			idx, chain := ethindexatest.NewIndexerService(mockService)
+			stubPair(chain, AAVEWETHPair, AAVE, WETH)
+			if tt.setup != nil {
+				tt.setup(mockService)
+			}
+			event := ethindexatest.NewEvent("UniswapV2", tt.network, "Swap").
+				ContractAddress(AAVEWETHPair).
+				TxHash("0xdef").
+				From("0xAbCdEf0000000000000000000000000000000001").
+				Block(20933200, 1727740800).
+				Arg("amount0In", ether(10)).
+				Arg("amount0Out", big.NewInt(0)).
+				Arg("amount1In", big.NewInt(0)).
+				Arg("amount1Out", new(big.Int).Div(ether(1), big.NewInt(2))).
+				Build()
+
+			err := HandleUniswapV2Swap(idx, event)
+
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "failed to record swap")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// Decimal is an exact decimal used for USD values and points. It scans from and is
+// written to NUMERIC columns as text, and is serialized to JSON as a bare number so
+// values keep every digit stored in the database.
+type Decimal struct {
+	decimal.Decimal
+}
+
+// ZeroDecimal is the zero value of Decimal.
+var ZeroDecimal = Decimal{}
+
+// NewDecimal wraps a shopspring decimal.
+func NewDecimal(d decimal.Decimal) Decimal {
+	return Decimal{Decimal: d}
+}
+
+// NewDecimalFromFloat converts a float64 through its shortest decimal representation, so
+// NewDecimalFromFloat(200) is the same value as the parsed "200". It is meant for constants
+// and tests; computed amounts should stay decimals end-to-end.
+func NewDecimalFromFloat(f float64) Decimal {
+	return Decimal{Decimal: decimal.RequireFromString(strconv.FormatFloat(f, 'f', -1, 64))}
+}
+
+// NewDecimalFromString parses a decimal string such as "1234.5678".
+func NewDecimalFromString(s string) (Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("invalid decimal %q: %w", s, err)
+	}
+	return Decimal{Decimal: d}, nil
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{Decimal: d.Decimal.Add(other.Decimal)}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{Decimal: d.Decimal.Sub(other.Decimal)}
+}
+
+// InexactFloat64 returns the nearest float64, for consumers that only accept floats such as Redis scores.
+func (d Decimal) InexactFloat64() float64 {
+	f, _ := d.Decimal.Float64()
+	return f
+}
+
+// Scan implements sql.Scanner for NUMERIC columns, including NULL as zero.
+func (d *Decimal) Scan(value interface{}) error {
+	if value == nil {
+		d.Decimal = decimal.Zero
+		return nil
+	}
+	return d.Decimal.Scan(value)
+}
+
+// Value implements driver.Valuer, sending the exact decimal as text.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.Decimal.String(), nil
+}
+
+// MarshalJSON writes the decimal as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.Decimal.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal string.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	return d.Decimal.UnmarshalJSON(data)
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDecimal_JSON tests that decimals are written as bare numbers and read from numbers or strings.
+func TestDecimal_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Value Decimal `json:"value"`
+	}{Value: NewDecimalFromFloat(1500.123456)})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"value":1500.123456}`, string(data))
+
+	var fromNumber, fromString Decimal
+	assert.NoError(t, json.Unmarshal([]byte(`0.1`), &fromNumber))
+	assert.NoError(t, json.Unmarshal([]byte(`"0.1"`), &fromString))
+	assert.Equal(t, "0.3", fromNumber.Add(fromString).Add(fromNumber).String())
+}
+
+// TestDecimal_Scan tests scanning NUMERIC text and NULL.
+func TestDecimal_Scan(t *testing.T) {
+	var d Decimal
+	assert.NoError(t, d.Scan("12345678901234.567"))
+	assert.Equal(t, "12345678901234.567", d.String())
+
+	value, err := d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678901234.567", value)
+
+	assert.NoError(t, d.Scan(nil))
+	assert.True(t, d.IsZero())
+}
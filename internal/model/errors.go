@@ -0,0 +1,167 @@
+package model
+
+import (
+	"errors"
+)
+
+// Kinds of errors, matched with errors.Is. Every error returned by the repository and the service
+// that the caller can act on has one of these kinds; errors without a kind are internal failures.
+var (
+	// ErrNotFound is the kind of errors for a missing resource.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is the kind of errors for a request conflicting with the current state, e.g. a duplicate.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalid is the kind of errors for a malformed or out-of-range request.
+	ErrInvalid = errors.New("invalid")
+	// ErrUnauthenticated is the kind of errors for a missing, forged or expired signature or session.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrUnavailable is the kind of errors for a temporary failure; the request can be retried.
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Error is an error of a kind. errors.Is matches it against its kind as well as itself, and
+// errors.As retrieves it from a wrapped error.
+type Error struct {
+	Kind    error  // one of ErrNotFound, ErrConflict, ErrInvalid, ErrUnauthenticated or ErrUnavailable
+	Message string // may be empty when Err describes the error
+	Err     error  // underlying cause, may be nil
+}
+
+// NewError returns an error of a kind.
+func NewError(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// WrapError returns err as an error of a kind, keeping err in the chain.
+func WrapError(kind error, err error) error {
+	return &Error{Kind: kind, Err: err}
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of e.
+func (e *Error) Is(target error) bool {
+	return target != nil && target == e.Kind
+}
+
+// KindOf returns the kind of err, or nil for an internal failure.
+func KindOf(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrConflict, ErrInvalid, ErrUnauthenticated, ErrUnavailable} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// Retryable reports whether the request failing with err may succeed when retried unchanged.
+func Retryable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
+
+// Canonical gRPC status codes, as defined by google.golang.org/grpc/codes.
+const (
+	grpcOK                 uint32 = 0
+	grpcUnknown            uint32 = 2
+	grpcInvalidArgument    uint32 = 3
+	grpcNotFound           uint32 = 5
+	grpcAlreadyExists      uint32 = 6
+	grpcFailedPrecondition uint32 = 9
+	grpcUnavailable        uint32 = 14
+	grpcUnauthenticated    uint32 = 16
+)
+
+// GRPCCode returns the canonical gRPC status code of err: OK for nil, Unknown for an internal failure.
+// A conflict maps to FailedPrecondition, or to AlreadyExists for a duplicate (ErrAlreadyExists).
+func GRPCCode(err error) uint32 {
+	switch {
+	case err == nil:
+		return grpcOK
+	case errors.Is(err, ErrAlreadyExists):
+		return grpcAlreadyExists
+	}
+	switch KindOf(err) {
+	case ErrNotFound:
+		return grpcNotFound
+	case ErrConflict:
+		return grpcFailedPrecondition
+	case ErrInvalid:
+		return grpcInvalidArgument
+	case ErrUnauthenticated:
+		return grpcUnauthenticated
+	case ErrUnavailable:
+		return grpcUnavailable
+	default:
+		return grpcUnknown
+	}
+}
+
+// ErrUserNotFound is returned when a user cannot be found.
+var (
+	ErrUserNotFound  = NewError(ErrNotFound, "user not found")
+	ErrTokenNotFound = NewError(ErrNotFound, "token not found")
+	ErrInvalidCursor = NewError(ErrInvalid, "invalid cursor")
+	// ErrAlreadyExists is returned when a row to create collides with an existing one.
+	ErrAlreadyExists = NewError(ErrConflict, "already exists")
+	// ErrQueryTimeout is returned when a database query runs past its deadline or statement timeout.
+	ErrQueryTimeout = NewError(ErrUnavailable, "query timed out")
+	// ErrTokenInfoUnavailable is returned while a token's metadata lookup is backing off after failures.
+	ErrTokenInfoUnavailable = NewError(ErrUnavailable, "token info unavailable")
+	// ErrNothingToClaim is returned when a user has no claimable points.
+	ErrNothingToClaim = NewError(ErrConflict, "nothing to claim")
+	// ErrInvalidClaimSignature is returned when a claim is not signed by the user, has expired or was already used.
+	ErrInvalidClaimSignature = NewError(ErrUnauthenticated, "invalid claim signature")
+	// ErrProofNotFound is returned when an address has no proof in the latest Merkle distribution.
+	ErrProofNotFound = NewError(ErrNotFound, "proof not found")
+	// ErrSnapshotNotFound is returned when no balance snapshot of a token was taken at the requested block.
+	ErrSnapshotNotFound = NewError(ErrNotFound, "balance snapshot not found")
+	// ErrNothingToDistribute is returned when no account had points at the cutoff of a distribution.
+	ErrNothingToDistribute = NewError(ErrConflict, "nothing to distribute")
+	// ErrDistributionNotFound is returned when a Merkle distribution does not exist.
+	ErrDistributionNotFound = NewError(ErrNotFound, "merkle distribution not found")
+	// ErrAlreadySettled is returned when a distribution already has a settlement transaction of an action that is not failed or replaced.
+	ErrAlreadySettled = NewError(ErrConflict, "already settled")
+	// ErrSettlementNotPending is returned when replacing a settlement transaction that is no longer pending.
+	ErrSettlementNotPending = NewError(ErrConflict, "settlement transaction not pending")
+	// ErrInvalidSignIn is returned when a sign-in message is malformed, unsigned by its address, expired,
+	// bound to another domain or reuses a nonce.
+	ErrInvalidSignIn = NewError(ErrUnauthenticated, "invalid sign-in")
+	// ErrInvalidSession is returned when a session token is missing, forged or expired.
+	ErrInvalidSession = NewError(ErrUnauthenticated, "invalid session")
+	// ErrProfileNotFound is returned when a user has not saved a profile.
+	ErrProfileNotFound = NewError(ErrNotFound, "profile not found")
+	// ErrPoolReservesNotFound is returned when no Sync event of a pool was indexed.
+	ErrPoolReservesNotFound = NewError(ErrNotFound, "pool reserves not found")
+	// ErrPriceNotFound is returned when no price of a token was recorded in the requested period.
+	ErrPriceNotFound = NewError(ErrNotFound, "price not found")
+	// ErrProjectNotFound is returned when no project has the requested slug.
+	ErrProjectNotFound = NewError(ErrNotFound, "project not found")
+	// ErrAPIKeyNotFound is returned when no active API key has the requested prefix.
+	ErrAPIKeyNotFound = NewError(ErrNotFound, "API key not found")
+	// ErrInvalidAPIKey is returned when an API key is unknown or revoked.
+	ErrInvalidAPIKey = NewError(ErrUnauthenticated, "invalid API key")
+	// ErrIdempotencyKeyInUse is returned while the first request with an idempotency key is being processed.
+	ErrIdempotencyKeyInUse = NewError(ErrConflict, "a request with this idempotency key is in progress")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent with another request than its first.
+	ErrIdempotencyKeyReused = NewError(ErrInvalid, "idempotency key already used for another request")
+	// ErrAddressLabelNotFound is returned when an address does not carry the requested label.
+	ErrAddressLabelNotFound = NewError(ErrNotFound, "address label not found")
+	// ErrUnknownAddressLabel is returned when a label is not one of AddressLabels.
+	ErrUnknownAddressLabel = NewError(ErrInvalid, "unknown address label")
+	// ErrAnalyticsUnavailable is returned by the analytics endpoints when no analytics database is configured.
+	ErrAnalyticsUnavailable = NewError(ErrUnavailable, "swap analytics unavailable")
+)
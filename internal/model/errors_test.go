@@ -0,0 +1,47 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestError tests that errors match their kind and themselves through wrapping, and their gRPC codes.
+func TestError(t *testing.T) {
+	cause := errors.New("connection reset")
+	wrapped := fmt.Errorf("failed to get user: %w", WrapError(ErrUnavailable, cause))
+
+	assert.True(t, errors.Is(wrapped, ErrUnavailable))
+	assert.True(t, errors.Is(wrapped, cause))
+	assert.True(t, Retryable(wrapped))
+	assert.Equal(t, "failed to get user: connection reset", wrapped.Error())
+
+	var modelErr *Error
+	assert.True(t, errors.As(wrapped, &modelErr))
+	assert.Equal(t, ErrUnavailable, modelErr.Kind)
+
+	notFound := fmt.Errorf("%w: 0xabc", ErrUserNotFound)
+	assert.True(t, errors.Is(notFound, ErrUserNotFound))
+	assert.True(t, errors.Is(notFound, ErrNotFound))
+	assert.False(t, errors.Is(notFound, ErrTokenNotFound))
+	assert.False(t, Retryable(notFound))
+
+	tests := []struct {
+		err  error
+		want uint32
+	}{
+		{err: nil, want: grpcOK},
+		{err: notFound, want: grpcNotFound},
+		{err: ErrNothingToClaim, want: grpcFailedPrecondition},
+		{err: fmt.Errorf("%w: %w", ErrAlreadyExists, cause), want: grpcAlreadyExists},
+		{err: ErrInvalidCursor, want: grpcInvalidArgument},
+		{err: ErrInvalidSession, want: grpcUnauthenticated},
+		{err: wrapped, want: grpcUnavailable},
+		{err: cause, want: grpcUnknown},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, GRPCCode(tt.err), "%v", tt.err)
+	}
+}
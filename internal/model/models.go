@@ -1,16 +1,23 @@
 package model
 
 import (
-	"errors"
+	"encoding/json"
 	"time"
 )
 
 type User struct {
-	ID          int       `json:"id"`
-	Address     string    `json:"address"`
-	TotalPoints float64   `json:"total_points"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int     `json:"id"`
+	Address     string  `json:"address"`
+	TotalPoints Decimal `json:"total_points"`
+	// ClaimedPoints is the part of TotalPoints already claimed. Only GetUserByAddress loads it.
+	ClaimedPoints Decimal   `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ClaimablePoints returns the points accrued since the last claim.
+func (u User) ClaimablePoints() Decimal {
+	return u.TotalPoints.Sub(u.ClaimedPoints)
 }
 
 type Token struct {
@@ -21,34 +28,584 @@ type Token struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// TokenVolume is a token with its all-time swap volume.
+type TokenVolume struct {
+	Token
+	VolumeUsd Decimal `json:"volume_usd"`
+	SwapCount int64   `json:"swap_count"`
+}
+
 type SwapHistory struct {
 	ID              int       `json:"id"`
+	Network         string    `json:"network"`
 	Token           string    `json:"token"`
 	Account         string    `json:"account"`
 	TransactionHash string    `json:"transaction_hash"`
-	UsdValue        float64   `json:"usd_value"`
+	LogIndex        uint      `json:"-"` // position of the Swap log in the transaction, recorded once
+	UsdValue        Decimal   `json:"usd_value"`
+	CountedUsdValue Decimal   `json:"counted_usd_value"` // USD counted towards points, below UsdValue when capped
+	WashTrade       bool      `json:"wash_trade"`        // leg of a round trip of the account through the pool in one transaction
 	LastUpdated     time.Time `json:"last_updated"`
 	CreatedAt       time.Time `json:"created_at"`
+	// BlockNumber and Boost, the multiplier of the points the swap earns from the holdings of the
+	// account at its block, are not stored with the swap.
+	BlockNumber int64        `json:"-"`
+	Boost       *PointsBoost `json:"-"`
 }
 
 type PointsHistory struct {
-	ID          int       `json:"id"`
-	Token       string    `json:"token"`
-	Account     string    `json:"account"`
-	Points      float64   `json:"points"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int             `json:"id"`
+	Network     string          `json:"network"`
+	Token       string          `json:"token"`
+	Account     string          `json:"account"`
+	Points      Decimal         `json:"points"`
+	Description string          `json:"description"`
+	Metadata    *PointsMetadata `json:"metadata,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// PointsBoost is a boost an account held when it earned points, by its configured id.
+type PointsBoost struct {
+	ID         string  `json:"id"`
+	Multiplier Decimal `json:"multiplier"`
+}
+
+// PointsMetadata is the provenance of the points of a points history entry, stored as JSON: the
+// campaign that awarded them, the swap that earned them and the boost applied.
+type PointsMetadata struct {
+	Campaign        string   `json:"campaign,omitempty"`         // quest id, or contract and period of a reward distribution
+	TransactionHash string   `json:"transaction_hash,omitempty"` // of the swap earning the points
+	BlockNumber     int64    `json:"block_number,omitempty"`     // of the swap earning the points
+	Boost           string   `json:"boost,omitempty"`            // id of the boost applied
+	Multiplier      *Decimal `json:"multiplier,omitempty"`       // of the boost applied
+	BasePoints      *Decimal `json:"base_points,omitempty"`      // points before the boost
 }
 
 // other
 type UserSwapPercentage struct {
 	Account    string  `json:"account"`
-	TotalUSD   float64 `json:"total_usd"`
-	Percentage float64 `json:"percentage"`
+	TotalUSD   Decimal `json:"total_usd"`
+	WashUSD    Decimal `json:"wash_usd"` // USD of wash trades, excluded from TotalUSD when wash trades are excluded
+	Percentage Decimal `json:"percentage"`
+}
+
+// NetworkSummary aggregates a user's swap volume and points on a single network.
+type NetworkSummary struct {
+	UsdValue     Decimal `json:"usd_value"`
+	WashUsdValue Decimal `json:"wash_usd_value"` // part of UsdValue flagged as wash trades
+	Points       Decimal `json:"points"`
+}
+
+// UserTask is a points award of a user in a token.
+type UserTask struct {
+	Description string  `json:"description"`
+	Points      Decimal `json:"points"`
+}
+
+// UserPool is a user's swap volume in a token and the points awarded for it.
+type UserPool struct {
+	TotalUsdValue Decimal    `json:"total_usd_value"`
+	Points        Decimal    `json:"points"`
+	Tasks         []UserTask `json:"tasks"`
+}
+
+// UserOverview is a user's swap volume and points by token and by network.
+// Networks always carries the per-network breakdown; Network is set when the view is filtered to one network.
+// Claims are not per network, so ClaimablePoints and ClaimedPoints always cover every network.
+type UserOverview struct {
+	Network         string                    `json:"network,omitempty"`
+	TotalUsdValue   Decimal                   `json:"total_usd_value"`
+	TotalPoints     Decimal                   `json:"total_points"`
+	ClaimablePoints Decimal                   `json:"claimable_points"`
+	ClaimedPoints   Decimal                   `json:"claimed_points"`
+	Pool            map[string]*UserPool      `json:"pool"`
+	Networks        map[string]NetworkSummary `json:"networks"`
+	Labels          []string                  `json:"labels,omitempty"` // e.g. exchange or team_wallet, see AddressLabels
+}
+
+// SwapTotal is the total USD value of the swaps of an account in a token. An account without swaps
+// has a zero total rather than no result.
+type SwapTotal struct {
+	UsdValue     Decimal `json:"usd_value"`
+	WashUsdValue Decimal `json:"wash_usd_value"` // part of UsdValue flagged as wash trades
+	SwapCount    int64   `json:"swap_count"`
+}
+
+// Empty reports whether the total covers no swap.
+func (t SwapTotal) Empty() bool {
+	return t.SwapCount == 0
+}
+
+// PoolVolumeStats aggregates swap activity of a pool within a time window.
+type PoolVolumeStats struct {
+	VolumeUsd      Decimal `json:"volume_usd"`
+	SwapCount      int64   `json:"swap_count"`
+	UniqueAccounts int64   `json:"unique_accounts"`
+}
+
+// PoolReserves is a snapshot of the reserves of a pool after the last Sync event of a block.
+// Reserves are in the smallest unit of each token; TVLUsd is nil when the pool could not be valued.
+type PoolReserves struct {
+	Network     string    `json:"network"`
+	Pool        string    `json:"pool"`
+	BlockNumber int64     `json:"block_number"`
+	LogIndex    uint      `json:"-"`
+	Reserve0    Decimal   `json:"reserve0"`
+	Reserve1    Decimal   `json:"reserve1"`
+	TVLUsd      *Decimal  `json:"tvl_usd"`
+	BlockTime   time.Time `json:"block_time"`
+}
+
+// Kinds of positions. A position is an account's balance held in a contract, in the smallest unit
+// of what the contract accounts in: LP tokens of a pool, tokens staked, shares of a vault or
+// ERC-20 tokens.
+const (
+	PositionLP    = "lp"
+	PositionStake = "stake"
+	PositionVault = "vault"
+	PositionToken = "token"
+)
+
+// Position is the current balance of an account in a contract of a protocol.
+type Position struct {
+	Network     string    `json:"network"`
+	Protocol    string    `json:"protocol"`
+	Kind        string    `json:"kind"`
+	Contract    string    `json:"contract"`
+	Account     string    `json:"account"`
+	Balance     Decimal   `json:"balance"`
+	BlockNumber int64     `json:"block_number"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PositionChange is a change of a position by an event, e.g. an LP token transfer, a stake or a
+// vault deposit. Delta is negative when the balance decreases.
+type PositionChange struct {
+	Network         string    `json:"network"`
+	Protocol        string    `json:"protocol"`
+	Kind            string    `json:"kind"`
+	Contract        string    `json:"contract"`
+	Account         string    `json:"account"`
+	TransactionHash string    `json:"transaction_hash"`
+	LogIndex        uint      `json:"log_index"`
+	BlockNumber     int64     `json:"block_number"`
+	Delta           Decimal   `json:"delta"`
+	BlockTime       time.Time `json:"block_time"`
+}
+
+// PositionReward is the points awarded to an account for its position in a contract over a period,
+// in proportion to its time-weighted balance: the balance integrated over the period, in balance
+// units times seconds.
+type PositionReward struct {
+	Account   string  `json:"account"`
+	Liquidity Decimal `json:"liquidity"`
+	Share     Decimal `json:"share"`
+	Points    Decimal `json:"points"`
+}
+
+// DailyVolume is the USD value of the swaps of an account through a pool on a UTC day.
+type DailyVolume struct {
+	Day      time.Time `json:"day"`
+	Account  string    `json:"account"`
+	UsdValue Decimal   `json:"usd_value"`
+}
+
+// TWAVReward is the points awarded to an account by a time-weighted average volume campaign over
+// a period of days, in proportion to its TWAV: the square of the mean square root of its daily
+// volume, which is its average daily volume when steady and shrinks as the volume is concentrated
+// on fewer days.
+type TWAVReward struct {
+	Account    string  `json:"account"`
+	TWAV       Decimal `json:"twav"`
+	ActiveDays int     `json:"active_days"`
+	Share      Decimal `json:"share"`
+	Points     Decimal `json:"points"`
+}
+
+// TraderVolume represents the swap volume of a single account in a pool.
+type TraderVolume struct {
+	Account   string   `json:"account"`
+	Labels    []string `json:"labels,omitempty"` // labels of the account, see AddressLabel
+	TotalUSD  Decimal  `json:"total_usd"`
+	SwapCount int64    `json:"swap_count"`
+}
+
+// PoolStats contains the windowed statistics and top traders of a pool.
+type PoolStats struct {
+	Pool       string                     `json:"pool"`
+	Windows    map[string]PoolVolumeStats `json:"windows"`
+	TopTraders []TraderVolume             `json:"top_traders"`
+}
+
+// LeaderboardRank is a user's position on the points leaderboard.
+// Users with equal points share a rank.
+type LeaderboardRank struct {
+	Address string  `json:"address"`
+	Points  Decimal `json:"points"`
+	Rank    int64   `json:"rank"`
+}
+
+// PointClaim is the receipt of a claim of every point a user could claim.
+type PointClaim struct {
+	ID      int     `json:"id"`
+	Address string  `json:"address"`
+	Points  Decimal `json:"points"`
+	// Leaf is the MerkleDistributor leaf of the claim, keccak256(abi.encodePacked(id, address, amount)),
+	// when claims record leaves.
+	Leaf      string    `json:"leaf,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PointsBalance is an account's total points.
+type PointsBalance struct {
+	Address string  `json:"address"`
+	Points  Decimal `json:"points"`
+}
+
+// GasSpend is the gas fee paid by an account for a transaction interacting with an indexed
+// contract. GasPrice and Fee are in wei of the native token of the network.
+type GasSpend struct {
+	Network         string    `json:"network"`
+	TransactionHash string    `json:"transaction_hash"`
+	Account         string    `json:"account"`
+	BlockNumber     int64     `json:"block_number"`
+	GasUsed         uint64    `json:"gas_used"`
+	GasPrice        Decimal   `json:"gas_price"`
+	Fee             Decimal   `json:"fee"`
+	BlockTime       time.Time `json:"block_time"`
+}
+
+// GasTotal is the gas an account paid for its indexed transactions on a network. Fees of different
+// networks are paid in different native tokens, so they are never summed together.
+type GasTotal struct {
+	Network      string  `json:"network"`
+	Account      string  `json:"account"`
+	Transactions int     `json:"transactions"`
+	GasUsed      Decimal `json:"gas_used"`
+	Fee          Decimal `json:"fee"`
+}
+
+// HolderBucket counts the holders of a token whose balance, in the smallest unit of the token, is
+// of an order of magnitude: within [MinBalance, MaxBalance), where MinBalance is 10^Magnitude.
+type HolderBucket struct {
+	Magnitude  int     `json:"magnitude"`
+	MinBalance Decimal `json:"min_balance"`
+	MaxBalance Decimal `json:"max_balance"`
+	Holders    int64   `json:"holders"`
+}
+
+// HolderStats is the number of holders of a token on a network and their distribution by balance.
+type HolderStats struct {
+	Network string         `json:"network"`
+	Token   string         `json:"token"`
+	Holders int64          `json:"holders"`
+	Buckets []HolderBucket `json:"buckets"`
+}
+
+// Allowance is the amount of a token a spender may transfer from an owner's account, as set by the
+// owner's latest Approval event. Transfers made with the allowance do not emit an Approval, so the
+// amount left may be lower. Unlimited is set for the maximum uint256 approval.
+type Allowance struct {
+	Network         string    `json:"network"`
+	Token           string    `json:"token"`
+	Owner           string    `json:"owner"`
+	Spender         string    `json:"spender"`
+	Amount          Decimal   `json:"amount"`
+	Unlimited       bool      `json:"unlimited"`
+	TransactionHash string    `json:"transaction_hash"`
+	BlockNumber     int64     `json:"block_number"`
+	LogIndex        uint      `json:"-"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
-// ErrUserNotFound is returned when a user cannot be found.
-var (
-	ErrUserNotFound  = errors.New("user not found")
-	ErrTokenNotFound = errors.New("token not found")
+// Labels of known addresses, e.g. to tell exchanges, routers and internal wallets apart from users.
+const (
+	LabelExchange   = "exchange"
+	LabelRouter     = "router"
+	LabelContract   = "contract"
+	LabelTeamWallet = "team_wallet"
 )
+
+// AddressLabels are the labels an address may carry.
+var AddressLabels = []string{LabelExchange, LabelRouter, LabelContract, LabelTeamWallet}
+
+// AddressLabel tags an address with one of AddressLabels. Name tells which one it is, e.g.
+// "Uniswap V2: Router 2".
+type AddressLabel struct {
+	Address   string    `json:"address"`
+	Label     string    `json:"label"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"` // admin operator who created the label
+	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedBy string    `json:"updated_by"` // admin operator who last changed the label
+}
+
+// Exclusion is the accounts left out of the leaderboard and of points distributions: the
+// addresses carrying one of Labels and the Addresses themselves, lowercased.
+type Exclusion struct {
+	Labels    []string
+	Addresses []string
+}
+
+// Sources of balance snapshots: the indexed Transfer events of the token, or balanceOf calls for a
+// list of holders.
+const (
+	SnapshotSourceTransfers = "transfers"
+	SnapshotSourceBalanceOf = "balance_of"
+)
+
+// BalanceSnapshot is the balances of the holders of a token at a block, taken to weight airdrops
+// and points. Total is the sum of the balances, in the smallest unit of the token.
+type BalanceSnapshot struct {
+	ID          int       `json:"id"`
+	Network     string    `json:"network"`
+	Token       string    `json:"token"`
+	BlockNumber int64     `json:"block_number"`
+	Source      string    `json:"source"`
+	Holders     int       `json:"holders"`
+	Total       Decimal   `json:"total"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// HolderBalance is the balance of an account in a BalanceSnapshot.
+type HolderBalance struct {
+	Account string  `json:"account"`
+	Balance Decimal `json:"balance"`
+}
+
+// MerkleDistribution is a snapshot of total points at a cutoff, distributed on chain by a
+// MerkleDistributor contract with Root. TokenTotal is the sum of the amounts, in token units.
+type MerkleDistribution struct {
+	ID         int       `json:"id"`
+	Cutoff     time.Time `json:"cutoff"`
+	Root       string    `json:"merkle_root"`
+	TokenTotal string    `json:"token_total"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// MerkleProof is an address's claim in a MerkleDistribution: the arguments of MerkleDistributor.claim.
+// Amounts are decimal strings of token units, since they exceed the precision of JSON numbers.
+type MerkleProof struct {
+	DistributionID int       `json:"distribution_id"`
+	Root           string    `json:"merkle_root"`
+	Cutoff         time.Time `json:"cutoff"`
+	Index          uint64    `json:"index"`
+	Address        string    `json:"address"`
+	Amount         string    `json:"amount"`
+	Proof          []string  `json:"proof"`
+}
+
+// Settlement actions, the on-chain transactions settling a MerkleDistribution.
+const (
+	SettlementFund       = "fund"        // transfer of the token total to the MerkleDistributor
+	SettlementUpdateRoot = "update_root" // setMerkleRoot of the MerkleDistributor
+)
+
+// Statuses of a SettlementTx.
+const (
+	SettlementSending   = "sending" // recorded before it is sent, without a hash and nonce
+	SettlementPending   = "pending"
+	SettlementConfirmed = "confirmed"
+	SettlementFailed    = "failed"
+	SettlementReplaced  = "replaced"  // superseded by a transaction with the same nonce
+	SettlementReconcile = "reconcile" // sending failed; whether it reached the network is to be checked by hand
+)
+
+// SettlementTx is a transaction sent to settle a MerkleDistribution, tracked until its receipt.
+// BlockNumber and GasUsed are set once it is mined. Replaces is the ID of the transaction a
+// replacement was sent for.
+type SettlementTx struct {
+	ID             int       `json:"id"`
+	DistributionID int       `json:"distribution_id"`
+	Action         string    `json:"action"`
+	Network        string    `json:"network"`
+	Hash           string    `json:"hash"`
+	Sender         string    `json:"sender"`
+	Nonce          uint64    `json:"nonce"`
+	Status         string    `json:"status"`
+	BlockNumber    *int64    `json:"block_number,omitempty"`
+	GasUsed        *int64    `json:"gas_used,omitempty"`
+	Replaces       *int      `json:"replaces,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// SignInNonce is a single-use nonce to put in a Sign-In With Ethereum message.
+type SignInNonce struct {
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Session is issued to a wallet that signed in. Token authenticates the private endpoints as a bearer token.
+type Session struct {
+	Token     string    `json:"token"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Project is a reward program served by the deployment. API keys belong to a project, and requests
+// made with a key are scoped to its project.
+type Project struct {
+	ID        int       `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DefaultProjectID is the project of the points, claims, distributions, quests and labels of a
+// deployment that does not configure one, and of the data recorded before projects scoped it.
+const DefaultProjectID = 0
+
+// APIKey is a key of a project. Only a hash of the key is stored; Prefix identifies it, e.g. to
+// revoke it, and Key is set only when the key is created.
+type APIKey struct {
+	ID        int        `json:"id"`
+	ProjectID int        `json:"project_id"`
+	Prefix    string     `json:"prefix"`
+	Key       string     `json:"key,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// NotificationPreferences are the notifications a user opted into, sent to the email of their profile.
+type NotificationPreferences struct {
+	WeeklySummary bool `json:"weekly_summary"` // points and swaps of the past week, every Monday
+	BigSwaps      bool `json:"big_swaps"`      // swaps above the alert threshold
+}
+
+// Notification kinds, recorded with the period they cover so each is sent once.
+const (
+	NotificationWeeklySummary = "weekly_summary"
+	NotificationBigSwaps      = "big_swaps"
+)
+
+// WeeklySummary is a user's activity over the week starting at WeekStart.
+type WeeklySummary struct {
+	Address      string    `json:"address"`
+	Email        string    `json:"email"`
+	WeekStart    time.Time `json:"week_start"`
+	PointsEarned Decimal   `json:"points_earned"`
+	TotalPoints  Decimal   `json:"total_points"`
+	SwapCount    int64     `json:"swap_count"`
+	SwapVolume   Decimal   `json:"swap_volume"` // USD
+}
+
+// BigSwapAlert lists a user's swaps above the alert threshold since their last alert, oldest first.
+type BigSwapAlert struct {
+	Address string        `json:"address"`
+	Email   string        `json:"email"`
+	Swaps   []SwapHistory `json:"swaps"`
+}
+
+// UserProfile is the private metadata a signed-in user bound to their address.
+type UserProfile struct {
+	Address       string                  `json:"address"`
+	Email         string                  `json:"email"`
+	Notifications NotificationPreferences `json:"notifications"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// TokenPrice is a USD price of a token observed at a point in time.
+type TokenPrice struct {
+	Network string    `json:"network"`
+	Token   string    `json:"token"`
+	Price   Decimal   `json:"price"`
+	Time    time.Time `json:"time"`
+}
+
+// PriceCandle is the open, high, low and close USD price of a token within an interval starting at Time.
+type PriceCandle struct {
+	Time  time.Time `json:"time"`
+	Open  Decimal   `json:"open"`
+	High  Decimal   `json:"high"`
+	Low   Decimal   `json:"low"`
+	Close Decimal   `json:"close"`
+}
+
+// VolumeBucket is the swap volume within an interval starting at Time.
+type VolumeBucket struct {
+	Time           time.Time `json:"time"`
+	VolumeUsd      Decimal   `json:"volume_usd"`
+	SwapCount      int64     `json:"swap_count"`
+	UniqueAccounts int64     `json:"unique_accounts"`
+}
+
+// IdempotentResponse is the response stored for an idempotency key, replayed to the retries of its request.
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Kinds of LiveEvent.
+const (
+	LiveEventSwap   = "swap"
+	LiveEventPoints = "points"
+)
+
+// LiveEvent is a swap or a points award, published once committed to stream to live dashboards.
+// Pool is the pool of the swap or the token the points were awarded for; UsdValue and
+// TransactionHash are set for swaps, Points, Description and Project for points.
+type LiveEvent struct {
+	Type            string    `json:"type"`
+	Network         string    `json:"network"`
+	Pool            string    `json:"pool"`
+	Account         string    `json:"account"`
+	TransactionHash string    `json:"transaction_hash,omitempty"`
+	UsdValue        *Decimal  `json:"usd_value,omitempty"`
+	Points          *Decimal  `json:"points,omitempty"`
+	Description     string    `json:"description,omitempty"`
+	Project         int       `json:"project,omitempty"`
+	Time            time.Time `json:"time"`
+}
+
+// LiveEventFilter selects the live events of a pool and of an account, and the points awards of a
+// project; empty pool and account match any.
+type LiveEventFilter struct {
+	Pool    string
+	Account string
+	Project int
+}
+
+// Match reports whether the filter selects event. Swaps are selected for every project.
+func (f LiveEventFilter) Match(event LiveEvent) bool {
+	return (f.Pool == "" || f.Pool == event.Pool) && (f.Account == "" || f.Account == event.Account) &&
+		(event.Type != LiveEventPoints || f.Project == event.Project)
+}
+
+// History tables, partitioned by month. The retention rolls up, archives and drops their expired months.
+const (
+	HistorySwaps  = "swap_history"
+	HistoryPoints = "points_history"
+)
+
+// HistoryRow is a row of a history table as archived: its ID and its columns as a JSON object.
+type HistoryRow struct {
+	ID   int64
+	Data json.RawMessage
+}
+
+// QuestProgress is the progress of an account on a quest: the distinct days counted, or the USD
+// counted, and its current run of consecutive days ending on LastDay.
+type QuestProgress struct {
+	QuestID     string     `json:"quest_id"`
+	Account     string     `json:"account"`
+	Progress    Decimal    `json:"progress"`
+	Streak      int        `json:"streak"`
+	LastDay     *time.Time `json:"last_day,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// UserQuest is a quest of the configuration with the progress of a user towards its target.
+type UserQuest struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	Kind        string     `json:"kind"` // days, streak or volume
+	Target      int        `json:"target"`
+	MinDailyUSD int        `json:"min_daily_usd,omitempty"`
+	Points      int        `json:"points"`
+	Progress    Decimal    `json:"progress"` // days, consecutive days up to yesterday or today, or USD
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"hw/internal/model"
+)
+
+var setAllowanceQuery = queries.Add("SetAllowance", `
+	INSERT INTO allowances (network, token, owner, spender, amount, transaction_hash, block_number, log_index, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (network, token, owner, spender) DO UPDATE SET
+		amount = EXCLUDED.amount,
+		transaction_hash = EXCLUDED.transaction_hash,
+		block_number = EXCLUDED.block_number,
+		log_index = EXCLUDED.log_index,
+		updated_at = EXCLUDED.updated_at
+	WHERE (EXCLUDED.block_number, EXCLUDED.log_index) > (allowances.block_number, allowances.log_index)
+`)
+
+// SetAllowance sets the allowance of a spender over an owner's tokens, unless a later approval set it already.
+func (r *repository) SetAllowance(ctx context.Context, allowance *model.Allowance) error {
+	_, err := r.db.Exec(
+		ctx,
+		setAllowanceQuery,
+		allowance.Network,
+		allowance.Token,
+		allowance.Owner,
+		allowance.Spender,
+		allowance.Amount,
+		allowance.TransactionHash,
+		allowance.BlockNumber,
+		allowance.LogIndex,
+		allowance.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set allowance: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var getOwnerAllowancesQuery = queries.Add("GetOwnerAllowances", `
+	SELECT network, token, spender, amount, transaction_hash, block_number, updated_at
+	FROM allowances
+	WHERE owner = $1 AND ($2 = '' OR network = $2) AND amount > 0
+	ORDER BY network, token, spender
+`)
+
+// GetOwnerAllowances retrieves the outstanding allowances granted by an owner, on every network when network is empty.
+func (r *repository) GetOwnerAllowances(ctx context.Context, owner, network string) ([]model.Allowance, error) {
+	rows, err := r.db.Query(ctx, getOwnerAllowancesQuery, owner, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve allowances: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	allowances := []model.Allowance{}
+	for rows.Next() {
+		allowance := model.Allowance{Owner: owner}
+		if err := rows.Scan(&allowance.Network, &allowance.Token, &allowance.Spender, &allowance.Amount, &allowance.TransactionHash, &allowance.BlockNumber, &allowance.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		allowances = append(allowances, allowance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return allowances, nil
+}
@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestSetAllowance tests that an approval sets the allowance of its spender.
+func TestSetAllowance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	allowance := &model.Allowance{
+		Network:         "mainnet",
+		Token:           "0xtoken",
+		Owner:           "0xowner",
+		Spender:         "0xspender",
+		Amount:          model.NewDecimalFromFloat(1e6),
+		TransactionHash: "0xabc",
+		BlockNumber:     20933200,
+		LogIndex:        4,
+		UpdatedAt:       time.Unix(1727740800, 0),
+	}
+
+	mockDB.EXPECT().
+		Exec(ctx, pgMock.Query("SetAllowance"), "mainnet", "0xtoken", "0xowner", "0xspender", allowance.Amount, "0xabc", int64(20933200), uint(4), allowance.UpdatedAt).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	assert.NoError(t, repo.SetAllowance(ctx, allowance))
+
+	mockDB.EXPECT().
+		Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pgconn.CommandTag{}, errors.New("exec error"))
+
+	err := repo.SetAllowance(ctx, allowance)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to set allowance")
+}
+
+// TestGetOwnerAllowances tests that the outstanding allowances of an owner are read.
+func TestGetOwnerAllowances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetOwnerAllowances"), "0xowner", "").Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "mainnet"
+		*(dest[1].(*string)) = "0xtoken"
+		*(dest[2].(*string)) = "0xspender"
+		*(dest[3].(*model.Decimal)) = model.NewDecimalFromFloat(1e6)
+		*(dest[5].(*int64)) = 20933200
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	allowances, err := repo.GetOwnerAllowances(ctx, "0xowner", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Allowance{{Network: "mainnet", Token: "0xtoken", Owner: "0xowner", Spender: "0xspender", Amount: model.NewDecimalFromFloat(1e6), BlockNumber: 20933200}}, allowances)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("query error"))
+
+	_, err = repo.GetOwnerAllowances(ctx, "0xowner", "base")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve allowances")
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var createSignInNonceQuery = queries.Add("CreateSignInNonce", `
+	WITH expired AS (
+		DELETE FROM sign_in_nonces WHERE expires_at <= CURRENT_TIMESTAMP
+	)
+	INSERT INTO sign_in_nonces (nonce, expires_at) VALUES ($1, $2)
+`)
+
+// CreateSignInNonce stores a sign-in nonce until expiresAt, deleting the expired ones in the same statement
+// so unused nonces do not accumulate.
+func (r *repository) CreateSignInNonce(ctx context.Context, nonce string, expiresAt time.Time) error {
+	if _, err := r.db.Exec(ctx, createSignInNonceQuery, nonce, expiresAt); err != nil {
+		return fmt.Errorf("failed to create sign-in nonce: %w", dbError(err))
+	}
+	return nil
+}
+
+var consumeSignInNonceQuery = queries.Add("ConsumeSignInNonce", `DELETE FROM sign_in_nonces WHERE nonce = $1 AND expires_at > CURRENT_TIMESTAMP`)
+
+// ConsumeSignInNonce deletes an unexpired sign-in nonce, so it signs in once.
+// It returns model.ErrInvalidSignIn when the nonce is unknown, expired or already used.
+func (r *repository) ConsumeSignInNonce(ctx context.Context, nonce string) error {
+	tag, err := r.db.Exec(ctx, consumeSignInNonceQuery, nonce)
+	if err != nil {
+		return fmt.Errorf("failed to consume sign-in nonce: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrInvalidSignIn
+	}
+	return nil
+}
+
+var getUserProfileQuery = queries.Add("GetUserProfile", `SELECT address, email, notifications, updated_at FROM user_profiles WHERE address = $1`)
+
+// GetUserProfile retrieves a user's profile, or model.ErrProfileNotFound when none was saved.
+func (r *repository) GetUserProfile(ctx context.Context, address string) (*model.UserProfile, error) {
+	var (
+		profile       model.UserProfile
+		notifications []byte
+	)
+	err := r.db.QueryRow(ctx, getUserProfileQuery, address).Scan(&profile.Address, &profile.Email, &notifications, &profile.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("failed to get user profile: %w", dbError(err))
+	}
+	if err := json.Unmarshal(notifications, &profile.Notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode notification preferences: %w", dbError(err))
+	}
+
+	return &profile, nil
+}
+
+var upsertUserProfileQuery = queries.Add("UpsertUserProfile", `
+	INSERT INTO user_profiles (address, email, notifications)
+	VALUES ($1, $2, $3::jsonb)
+	ON CONFLICT (address) DO UPDATE
+	SET email = EXCLUDED.email, notifications = EXCLUDED.notifications, updated_at = CURRENT_TIMESTAMP
+	RETURNING updated_at
+`)
+
+// UpsertUserProfile creates or replaces a user's profile and sets its UpdatedAt.
+func (r *repository) UpsertUserProfile(ctx context.Context, profile *model.UserProfile) error {
+	notifications, err := json.Marshal(profile.Notifications)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification preferences: %w", dbError(err))
+	}
+	if err := r.db.QueryRow(ctx, upsertUserProfileQuery, profile.Address, profile.Email, string(notifications)).Scan(&profile.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert user profile: %w", dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestConsumeSignInNonce tests that a nonce signs in once and an unknown or used nonce is rejected.
+func TestConsumeSignInNonce(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		wantErr error
+	}{
+		{name: "consumed", tag: "DELETE 1"},
+		{name: "unknown or used", tag: "DELETE 0", wantErr: model.ErrInvalidSignIn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			repo := repository.NewRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().Exec(ctx, gomock.Any(), "32891756aBcd").Return(pgconn.NewCommandTag(tt.tag), nil)
+
+			err := repo.ConsumeSignInNonce(ctx, "32891756aBcd")
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+// TestGetUserProfile tests that the notification preferences are decoded, and the error without a profile.
+func TestGetUserProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanErr error
+		want    *model.UserProfile
+		wantErr error
+	}{
+		{
+			name: "found",
+			want: &model.UserProfile{Address: "0xabc", Email: "user@example.com", Notifications: model.NotificationPreferences{WeeklySummary: true}},
+		},
+		{name: "not found", scanErr: pgx.ErrNoRows, wantErr: model.ErrProfileNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRow := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "0xabc").Return(mockRow)
+			mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				if tt.scanErr != nil {
+					return tt.scanErr
+				}
+				*(dest[0].(*string)) = "0xabc"
+				*(dest[1].(*string)) = "user@example.com"
+				*(dest[2].(*[]byte)) = []byte(`{"weekly_summary": true, "big_swaps": false}`)
+				return nil
+			})
+
+			profile, err := repo.GetUserProfile(ctx, "0xabc")
+
+			assert.True(t, errors.Is(err, tt.wantErr))
+			assert.Equal(t, tt.want, profile)
+		})
+	}
+}
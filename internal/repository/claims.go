@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var claimUserPointsQuery = queries.Add("ClaimUserPoints", `
+	WITH claimable AS (
+		SELECT address, total_points, total_points - claimed_points AS points
+		FROM users
+		WHERE address = $1 AND project_id = $3 AND total_points > claimed_points
+		FOR UPDATE
+	), claimed AS (
+		UPDATE users u
+		SET claimed_points = c.total_points, updated_at = CURRENT_TIMESTAMP
+		FROM claimable c
+		WHERE u.address = c.address AND u.project_id = $3
+		RETURNING c.address, c.points
+	)
+	INSERT INTO point_claims (address, points, signature, project_id)
+	SELECT address, points, $2, $3 FROM claimed
+	RETURNING id, address, points, created_at
+`)
+
+// ClaimUserPoints marks a user's claimable points as claimed and records the claim under its signature,
+// in a single statement so concurrent claims cannot claim the same points twice.
+// It returns model.ErrNothingToClaim when the user has no claimable points and
+// model.ErrInvalidClaimSignature when the signature was already used.
+func (r *repository) ClaimUserPoints(ctx context.Context, address, signature string) (*model.PointClaim, error) {
+	var claim model.PointClaim
+	err := r.db.QueryRow(ctx, claimUserPointsQuery, address, signature, r.project).Scan(&claim.ID, &claim.Address, &claim.Points, &claim.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNothingToClaim
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, model.ErrInvalidClaimSignature
+		}
+		return nil, fmt.Errorf("failed to claim user points: %w", dbError(err))
+	}
+
+	return &claim, nil
+}
+
+var setPointClaimLeafQuery = queries.Add("SetPointClaimLeaf", `UPDATE point_claims SET leaf = $2 WHERE id = $1`)
+
+// SetPointClaimLeaf records the Merkle leaf of a claim.
+func (r *repository) SetPointClaimLeaf(ctx context.Context, id int, leaf string) error {
+	if _, err := r.db.Exec(ctx, setPointClaimLeafQuery, id, leaf); err != nil {
+		return fmt.Errorf("failed to set point claim leaf: %w", dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestClaimUserPoints tests the claim receipt and the mapping of empty claims and reused signatures.
+func TestClaimUserPoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanErr error
+		wantErr error
+	}{
+		{name: "claimed"},
+		{name: "nothing to claim", scanErr: pgx.ErrNoRows, wantErr: model.ErrNothingToClaim},
+		{name: "signature reused", scanErr: &pgconn.PgError{Code: "23505"}, wantErr: model.ErrInvalidClaimSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRow := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "0xabc", "0xsig", model.DefaultProjectID).Return(mockRow)
+			mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				if tt.scanErr != nil {
+					return tt.scanErr
+				}
+				*(dest[0].(*int)) = 3
+				*(dest[1].(*string)) = "0xabc"
+				*(dest[2].(*model.Decimal)) = model.NewDecimalFromFloat(42)
+				return nil
+			})
+
+			claim, err := repo.ClaimUserPoints(ctx, "0xabc", "0xsig")
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, claim)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &model.PointClaim{ID: 3, Address: "0xabc", Points: model.NewDecimalFromFloat(42)}, claim)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+)
+
+// Cursor is the decoded form of an opaque keyset pagination cursor.
+// It points at the last row of the previous page as a (sort key, id) pair.
+type Cursor struct {
+	Key string
+	ID  int
+}
+
+// EncodeCursor encodes a sort key and row id into an opaque cursor string.
+func EncodeCursor(key string, id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + "|" + strconv.Itoa(id)))
+}
+
+// EncodeTimeCursor encodes a (created_at, id) pair into an opaque cursor string.
+func EncodeTimeCursor(t time.Time, id int) string {
+	return EncodeCursor(t.UTC().Format(time.RFC3339Nano), id)
+}
+
+// DecodeCursor decodes an opaque cursor string. An empty string yields a nil cursor, meaning the first page.
+func DecodeCursor(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidCursor, err)
+	}
+
+	sep := strings.LastIndexByte(string(raw), '|')
+	if sep < 0 {
+		return nil, model.ErrInvalidCursor
+	}
+
+	id, err := strconv.Atoi(string(raw[sep+1:]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidCursor, err)
+	}
+
+	return &Cursor{Key: string(raw[:sep]), ID: id}, nil
+}
+
+// Time interprets the cursor key as a timestamp.
+func (c *Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", model.ErrInvalidCursor, err)
+	}
+	return t, nil
+}
+
+// Decimal interprets the cursor key as a decimal number.
+func (c *Cursor) Decimal() (model.Decimal, error) {
+	d, err := model.NewDecimalFromString(c.Key)
+	if err != nil {
+		return model.ZeroDecimal, fmt.Errorf("%w: %v", model.ErrInvalidCursor, err)
+	}
+	return d, nil
+}
+
+// decodeTimeCursor decodes a (created_at, id) cursor into nullable query arguments.
+func decodeTimeCursor(s string) (*time.Time, *int, error) {
+	cursor, err := DecodeCursor(s)
+	if err != nil || cursor == nil {
+		return nil, nil, err
+	}
+
+	t, err := cursor.Time()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &t, &cursor.ID, nil
+}
+
+// nextPage trims a result set fetched with limit+1 rows down to limit and
+// returns the cursor of the last kept row, or an empty cursor on the last page.
+func nextPage[T any](items []T, limit int, cursorOf func(T) string) ([]T, string) {
+	if len(items) <= limit {
+		return items, ""
+	}
+	items = items[:limit]
+	return items, cursorOf(items[len(items)-1])
+}
@@ -0,0 +1,136 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestCursor_RoundTrip verifies that encoded cursors decode to the same position.
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 10, 2, 8, 30, 15, 123456789, time.UTC)
+
+	cursor, err := repository.DecodeCursor(repository.EncodeTimeCursor(createdAt, 42))
+	assert.NoError(t, err)
+	assert.Equal(t, 42, cursor.ID)
+
+	decodedTime, err := cursor.Time()
+	assert.NoError(t, err)
+	assert.True(t, createdAt.Equal(decodedTime))
+
+	cursor, err = repository.DecodeCursor(repository.EncodeCursor("150.5", 7))
+	assert.NoError(t, err)
+	points, err := cursor.Decimal()
+	assert.NoError(t, err)
+	assert.Equal(t, "150.5", points.String())
+	assert.Equal(t, 7, cursor.ID)
+}
+
+// TestDecodeCursor_Empty verifies that an empty cursor means the first page.
+func TestDecodeCursor_Empty(t *testing.T) {
+	cursor, err := repository.DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+}
+
+// TestDecodeCursor_Invalid verifies that malformed cursors are rejected.
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"!!!", "bm9waXBl", repository.EncodeCursor("abc", 1)[:4]} {
+		_, err := repository.DecodeCursor(s)
+		assert.ErrorIs(t, err, model.ErrInvalidCursor, s)
+	}
+
+	cursor, err := repository.DecodeCursor(repository.EncodeCursor("not-a-time", 1))
+	assert.NoError(t, err)
+	_, err = cursor.Time()
+	assert.ErrorIs(t, err, model.ErrInvalidCursor)
+}
+
+// TestGetLeaderboardPage_HasNextPage verifies that an extra row yields a cursor pointing at the last kept row.
+func TestGetLeaderboardPage_HasNextPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	var nilPoints *model.Decimal
+	var nilID *int
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetLeaderboardPage"), nilPoints, nilID, 2, []string{}, []string{}, model.DefaultProjectID).Return(mockRows, nil)
+
+	usersData := []model.User{
+		{ID: 3, Address: "address3", TotalPoints: model.NewDecimalFromFloat(300)},
+		{ID: 1, Address: "address1", TotalPoints: model.NewDecimalFromFloat(200.5)},
+	}
+	for _, u := range usersData {
+		u := u
+		mockRows.EXPECT().Next().Return(true)
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+			*(dest[0].(*int)) = u.ID
+			*(dest[1].(*string)) = u.Address
+			*(dest[2].(*model.Decimal)) = u.TotalPoints
+			return nil
+		})
+	}
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	users, next, err := repo.GetLeaderboardPage(ctx, "", 1, model.Exclusion{})
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "address3", users[0].Address)
+
+	cursor, err := repository.DecodeCursor(next)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cursor.ID)
+	assert.Equal(t, "300", cursor.Key)
+}
+
+// TestGetPointsHistoryPage_WithCursor verifies that the cursor position is bound as query arguments.
+func TestGetPointsHistoryPage_WithCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	createdAt := time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC)
+	id := 10
+
+	mockDB.EXPECT().
+		Query(ctx, gomock.Any(), "accountXYZ", "tokenABC", &createdAt, &id, 21, model.DefaultProjectID).
+		Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	histories, next, err := repo.GetPointsHistoryPage(ctx, "accountXYZ", "tokenABC", repository.EncodeTimeCursor(createdAt, id), 20)
+
+	assert.NoError(t, err)
+	assert.Empty(t, histories)
+	assert.Empty(t, next)
+}
+
+// TestGetSwapHistoryPage_InvalidCursor verifies that a malformed cursor is rejected before querying.
+func TestGetSwapHistoryPage_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	_, _, err := repo.GetSwapHistoryPage(context.Background(), "accountXYZ", "%%%", 20)
+
+	assert.ErrorIs(t, err, model.ErrInvalidCursor)
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+)
+
+// rollupDay truncates t to the UTC calendar day used as the rollup bucket.
+func rollupDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+var incrementDailySwapRollupQuery = queries.Add("IncrementDailySwapRollup", `
+	INSERT INTO daily_user_pool_stats (day, network, token, account, usd_value, counted_usd_value, swap_count, project_id)
+	VALUES ($1, $2, $3, $4, $5, $6, 1, $7)
+	ON CONFLICT (project_id, day, network, token, account) DO UPDATE SET
+		usd_value = daily_user_pool_stats.usd_value + EXCLUDED.usd_value,
+		counted_usd_value = daily_user_pool_stats.counted_usd_value + EXCLUDED.counted_usd_value,
+		swap_count = daily_user_pool_stats.swap_count + 1
+`)
+
+// IncrementDailySwapRollup adds a swap to the daily per-user per-pool rollup.
+func (r *repository) IncrementDailySwapRollup(ctx context.Context, swapHistory *model.SwapHistory) error {
+	_, err := r.db.Exec(
+		ctx,
+		incrementDailySwapRollupQuery,
+		rollupDay(swapHistory.LastUpdated),
+		swapHistory.Network,
+		swapHistory.Token,
+		swapHistory.Account,
+		swapHistory.UsdValue,
+		swapHistory.CountedUsdValue,
+		r.project,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to increment daily swap rollup: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var getDailyCountedUsdQuery = queries.Add("GetDailyCountedUsd", `
+	SELECT COALESCE(SUM(counted_usd_value), 0)
+	FROM daily_user_pool_stats
+	WHERE account = $1 AND day = $2 AND project_id = $3
+`)
+
+// GetDailyCountedUsd retrieves the USD value of the swaps of an account counted towards points
+// on the UTC day of t, across pools and networks.
+func (r *repository) GetDailyCountedUsd(ctx context.Context, account string, t time.Time) (model.Decimal, error) {
+	var counted model.Decimal
+	if err := r.db.QueryRow(ctx, getDailyCountedUsdQuery, account, rollupDay(t), r.project).Scan(&counted); err != nil {
+		return model.Decimal{}, fmt.Errorf("failed to get daily counted USD: %w", dbError(err))
+	}
+	return counted, nil
+}
+
+var getDailyPoolVolumesQuery = queries.Add("GetDailyPoolVolumes", `
+	SELECT day, account, counted_usd_value
+	FROM daily_user_pool_stats
+	WHERE network = $1 AND token = $2 AND day >= $3 AND day < $4 AND counted_usd_value > 0 AND project_id = $5
+	ORDER BY account, day
+`)
+
+// GetDailyPoolVolumes retrieves the USD value of the swaps through a pool of a network counted towards
+// points, per account and UTC day within [from, to), by account then day. Days without volume are left out.
+func (r *repository) GetDailyPoolVolumes(ctx context.Context, network, token string, from, to time.Time) ([]model.DailyVolume, error) {
+	rows, err := r.db.Query(ctx, getDailyPoolVolumesQuery, network, token, rollupDay(from), rollupDay(to), r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily pool volumes: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var volumes []model.DailyVolume
+	for rows.Next() {
+		var volume model.DailyVolume
+		if err := rows.Scan(&volume.Day, &volume.Account, &volume.UsdValue); err != nil {
+			return nil, fmt.Errorf("failed to scan daily pool volume: %w", dbError(err))
+		}
+		volumes = append(volumes, volume)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get daily pool volumes: %w", dbError(err))
+	}
+
+	return volumes, nil
+}
+
+var incrementDailyPointsRollupQuery = queries.Add("IncrementDailyPointsRollup", `
+	INSERT INTO daily_user_pool_stats (day, network, token, account, points, project_id)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (project_id, day, network, token, account) DO UPDATE SET
+		points = daily_user_pool_stats.points + EXCLUDED.points
+`)
+
+// IncrementDailyPointsRollup adds awarded points to the daily per-user per-pool rollup.
+func (r *repository) IncrementDailyPointsRollup(ctx context.Context, pointsHistory *model.PointsHistory) error {
+	_, err := r.db.Exec(
+		ctx,
+		incrementDailyPointsRollupQuery,
+		rollupDay(pointsHistory.CreatedAt),
+		pointsHistory.Network,
+		pointsHistory.Token,
+		pointsHistory.Account,
+		pointsHistory.Points,
+		r.project,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to increment daily points rollup: %w", dbError(err))
+	}
+
+	return nil
+}
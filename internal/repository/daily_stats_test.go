@@ -0,0 +1,181 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestIncrementDailySwapRollup_Success tests that swaps are bucketed by their UTC day.
+func TestIncrementDailySwapRollup_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	swapHistory := &model.SwapHistory{
+		Network:         "base",
+		Token:           "tokenABC",
+		Account:         "accountXYZ",
+		UsdValue:        model.NewDecimalFromFloat(250.75),
+		CountedUsdValue: model.NewDecimalFromFloat(100),
+		LastUpdated:     time.Date(2024, 10, 2, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*3600)),
+	}
+
+	expectedDay := time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC)
+	mockDB.EXPECT().
+		Exec(ctx, pgMock.Query("IncrementDailySwapRollup"), expectedDay, "base", swapHistory.Token, swapHistory.Account, swapHistory.UsdValue, swapHistory.CountedUsdValue, model.DefaultProjectID).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := repo.IncrementDailySwapRollup(ctx, swapHistory)
+
+	assert.NoError(t, err)
+}
+
+// TestIncrementDailySwapRollup_Failure tests the failure scenario when updating the swap rollup.
+func TestIncrementDailySwapRollup_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().
+		Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pgconn.CommandTag{}, errors.New("exec error"))
+
+	err := repo.IncrementDailySwapRollup(ctx, &model.SwapHistory{LastUpdated: time.Now()})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to increment daily swap rollup")
+}
+
+// TestGetDailyCountedUsd tests that the counted USD of an account is summed over its UTC day.
+func TestGetDailyCountedUsd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	expectedDay := time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetDailyCountedUsd"), "accountXYZ", expectedDay, model.DefaultProjectID).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*model.Decimal)) = model.NewDecimalFromFloat(750)
+		return nil
+	})
+
+	counted, err := repo.GetDailyCountedUsd(ctx, "accountXYZ", time.Date(2024, 10, 2, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*3600)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.NewDecimalFromFloat(750), counted)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).Return(errors.New("scan error"))
+
+	_, err = repo.GetDailyCountedUsd(ctx, "accountXYZ", time.Now())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get daily counted USD")
+}
+
+// TestIncrementDailyPointsRollup_Success tests the successful update of the points rollup.
+func TestIncrementDailyPointsRollup_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	pointsHistory := &model.PointsHistory{
+		Network:   "base",
+		Token:     "tokenABC",
+		Account:   "accountXYZ",
+		Points:    model.NewDecimalFromFloat(100),
+		CreatedAt: time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC),
+	}
+
+	expectedDay := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	mockDB.EXPECT().
+		Exec(ctx, pgMock.Query("IncrementDailyPointsRollup"), expectedDay, "base", pointsHistory.Token, pointsHistory.Account, pointsHistory.Points, model.DefaultProjectID).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := repo.IncrementDailyPointsRollup(ctx, pointsHistory)
+
+	assert.NoError(t, err)
+}
+
+// TestIncrementDailyPointsRollup_Project tests that the points of a project are rolled up apart
+// from those of the other projects.
+func TestIncrementDailyPointsRollup_Project(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB).ForProject(3)
+
+	ctx := context.Background()
+	pointsHistory := &model.PointsHistory{
+		Network:   "mainnet",
+		Token:     "tokenABC",
+		Account:   "accountXYZ",
+		Points:    model.NewDecimalFromFloat(100),
+		CreatedAt: time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC),
+	}
+
+	expectedDay := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	mockDB.EXPECT().
+		Exec(ctx, pgMock.Query("IncrementDailyPointsRollup"), expectedDay, "mainnet", "tokenABC", "accountXYZ", pointsHistory.Points, 3).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := repo.IncrementDailyPointsRollup(ctx, pointsHistory)
+
+	assert.NoError(t, err)
+}
+
+// TestGetDailyPoolVolumes tests that the counted USD of a pool is read per account and UTC day of the period.
+func TestGetDailyPoolVolumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	from := time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetDailyPoolVolumes"), "mainnet", "0xpool", from, to, model.DefaultProjectID).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*time.Time)) = from
+		*(dest[1].(*string)) = "accountXYZ"
+		*(dest[2].(*model.Decimal)) = model.NewDecimalFromFloat(750)
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	volumes, err := repo.GetDailyPoolVolumes(ctx, "mainnet", "0xpool", from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.DailyVolume{{Day: from, Account: "accountXYZ", UsdValue: model.NewDecimalFromFloat(750)}}, volumes)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("query error"))
+
+	_, err = repo.GetDailyPoolVolumes(ctx, "mainnet", "0xpool", from, to)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get daily pool volumes")
+}
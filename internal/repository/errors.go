@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes of a unique constraint violation and of a statement canceled, e.g. by
+// its statement_timeout.
+const (
+	uniqueViolation = "23505"
+	queryCanceled   = "57014"
+)
+
+// dbError gives a database error its model kind: a duplicate is model.ErrAlreadyExists, a violated
+// constraint or malformed value is invalid, a query past its deadline or canceled by the server is
+// model.ErrQueryTimeout, and it and a lost connection, serialization failure or overloaded server
+// are unavailable, so callers can retry them. Other errors are returned unchanged.
+func dbError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == uniqueViolation:
+			return fmt.Errorf("%w: %w", model.ErrAlreadyExists, err)
+		case pgErr.Code == queryCanceled:
+			return fmt.Errorf("%w: %w", model.ErrQueryTimeout, err)
+		case strings.HasPrefix(pgErr.Code, "22"), strings.HasPrefix(pgErr.Code, "23"):
+			// Data exceptions and the other integrity constraint violations
+			return model.WrapError(model.ErrInvalid, err)
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "40"),
+			strings.HasPrefix(pgErr.Code, "53"), strings.HasPrefix(pgErr.Code, "57P"):
+			// Connection exceptions, transaction rollbacks, insufficient resources and shutdowns
+			return model.WrapError(model.ErrUnavailable, err)
+		}
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return fmt.Errorf("%w: %w", model.ErrQueryTimeout, err)
+	}
+	var netErr net.Error
+	if pgconn.SafeToRetry(err) || errors.As(err, &netErr) {
+		return model.WrapError(model.ErrUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestDBErrorKinds tests that database errors returned by the repository carry their model kind.
+func TestDBErrorKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		scanErr  error
+		wantKind error
+		wantErr  error // matched in addition to scanErr
+	}{
+		{name: "duplicate", scanErr: &pgconn.PgError{Code: "23505"}, wantKind: model.ErrConflict, wantErr: model.ErrAlreadyExists},
+		{name: "check violation", scanErr: &pgconn.PgError{Code: "23514"}, wantKind: model.ErrInvalid},
+		{name: "serialization failure", scanErr: &pgconn.PgError{Code: "40001"}, wantKind: model.ErrUnavailable},
+		{name: "timeout", scanErr: context.DeadlineExceeded, wantKind: model.ErrUnavailable, wantErr: model.ErrQueryTimeout},
+		{name: "statement timeout", scanErr: &pgconn.PgError{Code: "57014"}, wantKind: model.ErrUnavailable, wantErr: model.ErrQueryTimeout},
+		{name: "syntax error", scanErr: &pgconn.PgError{Code: "42601"}},
+		{name: "other", scanErr: errors.New("unexpected")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRow := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "0xabc").Return(mockRow)
+			mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.scanErr)
+
+			_, err := repo.CreateUser(ctx, "0xabc")
+
+			assert.ErrorIs(t, err, tt.scanErr)
+			assert.Equal(t, tt.wantKind, model.KindOf(err))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"hw/internal/model"
+)
+
+var recordGasSpendQuery = queries.Add("RecordGasSpend", `
+	INSERT INTO gas_spend (network, transaction_hash, account, block_number, gas_used, gas_price, fee, block_time)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (network, transaction_hash) DO NOTHING
+`)
+
+// RecordGasSpend records the gas fee paid for a transaction, once per transaction.
+func (r *repository) RecordGasSpend(ctx context.Context, spend *model.GasSpend) error {
+	_, err := r.db.Exec(
+		ctx,
+		recordGasSpendQuery,
+		spend.Network,
+		spend.TransactionHash,
+		spend.Account,
+		spend.BlockNumber,
+		int64(spend.GasUsed),
+		spend.GasPrice,
+		spend.Fee,
+		spend.BlockTime,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record gas spend: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var getGasLeaderboardQuery = queries.Add("GetGasLeaderboard", `
+	SELECT account, COUNT(*), SUM(gas_used), SUM(fee)
+	FROM gas_spend
+	WHERE network = $1
+	GROUP BY account
+	ORDER BY SUM(fee) DESC, account
+	LIMIT $2
+`)
+
+// GetGasLeaderboard retrieves the accounts that paid the most gas on a network, largest fees first.
+func (r *repository) GetGasLeaderboard(ctx context.Context, network string, limit int) ([]model.GasTotal, error) {
+	rows, err := r.db.Query(ctx, getGasLeaderboardQuery, network, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas leaderboard: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	totals := []model.GasTotal{}
+	for rows.Next() {
+		total := model.GasTotal{Network: network}
+		if err := rows.Scan(&total.Account, &total.Transactions, &total.GasUsed, &total.Fee); err != nil {
+			return nil, fmt.Errorf("failed to scan gas total: %w", dbError(err))
+		}
+		totals = append(totals, total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	return totals, nil
+}
+
+var getAccountGasTotalsQuery = queries.Add("GetAccountGasTotals", `
+	SELECT network, COUNT(*), SUM(gas_used), SUM(fee)
+	FROM gas_spend
+	WHERE account = $1
+	GROUP BY network
+	ORDER BY network
+`)
+
+// GetAccountGasTotals retrieves the gas an account paid on each network.
+func (r *repository) GetAccountGasTotals(ctx context.Context, account string) ([]model.GasTotal, error) {
+	rows, err := r.db.Query(ctx, getAccountGasTotalsQuery, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account gas totals: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	totals := []model.GasTotal{}
+	for rows.Next() {
+		total := model.GasTotal{Account: account}
+		if err := rows.Scan(&total.Network, &total.Transactions, &total.GasUsed, &total.Fee); err != nil {
+			return nil, fmt.Errorf("failed to scan gas total: %w", dbError(err))
+		}
+		totals = append(totals, total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestRecordGasSpend tests that the gas of a transaction is recorded with its fee.
+func TestRecordGasSpend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	spend := &model.GasSpend{
+		Network:         "mainnet",
+		TransactionHash: "0xabc",
+		Account:         "accountXYZ",
+		BlockNumber:     20933200,
+		GasUsed:         100000,
+		GasPrice:        model.NewDecimalFromFloat(20e9),
+		Fee:             model.NewDecimalFromFloat(2e15),
+		BlockTime:       time.Unix(1727740800, 0),
+	}
+
+	mockDB.EXPECT().
+		Exec(ctx, pgMock.Query("RecordGasSpend"), "mainnet", "0xabc", "accountXYZ", int64(20933200), int64(100000), spend.GasPrice, spend.Fee, spend.BlockTime).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	assert.NoError(t, repo.RecordGasSpend(ctx, spend))
+
+	mockDB.EXPECT().
+		Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pgconn.CommandTag{}, errors.New("exec error"))
+
+	err := repo.RecordGasSpend(ctx, spend)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to record gas spend")
+}
+
+// TestGetGasLeaderboard tests that the gas totals of the accounts of a network are read.
+func TestGetGasLeaderboard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetGasLeaderboard"), "mainnet", 10).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "accountXYZ"
+		*(dest[1].(*int)) = 3
+		*(dest[3].(*model.Decimal)) = model.NewDecimalFromFloat(2e15)
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	totals, err := repo.GetGasLeaderboard(ctx, "mainnet", 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.GasTotal{{Network: "mainnet", Account: "accountXYZ", Transactions: 3, Fee: model.NewDecimalFromFloat(2e15)}}, totals)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("query error"))
+
+	_, err = repo.GetGasLeaderboard(ctx, "mainnet", 10)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get gas leaderboard")
+}
+
+// TestGetAccountGasTotals tests that the gas totals of an account are read per network.
+func TestGetAccountGasTotals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetAccountGasTotals"), "accountXYZ").Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "base"
+		*(dest[1].(*int)) = 1
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	totals, err := repo.GetAccountGasTotals(ctx, "accountXYZ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.GasTotal{{Network: "base", Account: "accountXYZ", Transactions: 1}}, totals)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), gomock.Any()).Return(nil, errors.New("query error"))
+
+	_, err = repo.GetAccountGasTotals(ctx, "accountXYZ")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get account gas totals")
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var reserveIdempotencyKeyQuery = queries.Add("ReserveIdempotencyKey", `
+	INSERT INTO idempotency_keys (scope, key, request_hash)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (scope, key) DO UPDATE
+	SET request_hash = EXCLUDED.request_hash, status = NULL, content_type = NULL, body = NULL, created_at = CURRENT_TIMESTAMP
+	WHERE idempotency_keys.created_at < $4
+	RETURNING key
+`)
+
+var getIdempotencyKeyQuery = queries.Add("GetIdempotencyKey", `
+	SELECT request_hash, status, content_type, body
+	FROM idempotency_keys
+	WHERE scope = $1 AND key = $2
+`)
+
+// ReserveIdempotencyKey reserves a key of a scope for the request hashed requestHash. A key
+// reserved before expiredBefore is reserved again. When the key is reserved for another
+// request, it returns the hash of that request and its response, nil while it is processed.
+func (r *repository) ReserveIdempotencyKey(ctx context.Context, scope, key, requestHash string, expiredBefore time.Time) (bool, string, *model.IdempotentResponse, error) {
+	var reservedKey string
+	err := r.db.QueryRow(ctx, reserveIdempotencyKeyQuery, scope, key, requestHash, expiredBefore).Scan(&reservedKey)
+	if err == nil {
+		return true, "", nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, "", nil, fmt.Errorf("failed to reserve idempotency key: %w", dbError(err))
+	}
+
+	var (
+		existingHash string
+		status       *int
+		contentType  *string
+		body         []byte
+	)
+	err = r.db.QueryRow(ctx, getIdempotencyKeyQuery, scope, key).Scan(&existingHash, &status, &contentType, &body)
+	if err != nil {
+		return false, "", nil, fmt.Errorf("failed to get idempotency key: %w", dbError(err))
+	}
+	if status == nil {
+		return false, existingHash, nil, nil
+	}
+	response := &model.IdempotentResponse{Status: *status, Body: body}
+	if contentType != nil {
+		response.ContentType = *contentType
+	}
+	return false, existingHash, response, nil
+}
+
+var completeIdempotencyKeyQuery = queries.Add("CompleteIdempotencyKey", `
+	UPDATE idempotency_keys
+	SET status = $3, content_type = $4, body = $5
+	WHERE scope = $1 AND key = $2
+`)
+
+// CompleteIdempotencyKey stores the response of the request of a reserved key.
+func (r *repository) CompleteIdempotencyKey(ctx context.Context, scope, key string, response *model.IdempotentResponse) error {
+	_, err := r.db.Exec(ctx, completeIdempotencyKeyQuery, scope, key, response.Status, response.ContentType, response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", dbError(err))
+	}
+	return nil
+}
+
+var releaseIdempotencyKeyQuery = queries.Add("ReleaseIdempotencyKey", `
+	DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND status IS NULL
+`)
+
+// ReleaseIdempotencyKey deletes a reserved key whose request has no response to store, so it can be retried.
+func (r *repository) ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	if _, err := r.db.Exec(ctx, releaseIdempotencyKeyQuery, scope, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestReserveIdempotencyKey tests that a free key is reserved, and a taken one returns the hash
+// of its request with its response once stored.
+func TestReserveIdempotencyKey(t *testing.T) {
+	expiredBefore := time.Date(2024, 10, 24, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		reserved     bool
+		status       *int
+		wantResponse *model.IdempotentResponse
+	}{
+		{name: "free", reserved: true},
+		{name: "in progress"},
+		{name: "completed", status: func() *int { s := 201; return &s }(), wantResponse: &model.IdempotentResponse{Status: 201, ContentType: "application/json", Body: []byte(`{}`)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRow := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().QueryRow(ctx, pgMock.Query("ReserveIdempotencyKey"), "acme", "key-1", "hash", expiredBefore).Return(mockRow)
+			mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				if !tt.reserved {
+					return pgx.ErrNoRows
+				}
+				*dest[0].(*string) = "key-1"
+				return nil
+			})
+			if !tt.reserved {
+				existingRow := pgMock.NewMockPgxRows(ctrl)
+				mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetIdempotencyKey"), "acme", "key-1").Return(existingRow)
+				existingRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+					*dest[0].(*string) = "other"
+					*dest[1].(**int) = tt.status
+					if tt.status != nil {
+						contentType := "application/json"
+						*dest[2].(**string) = &contentType
+						*dest[3].(*[]byte) = []byte(`{}`)
+					}
+					return nil
+				})
+			}
+
+			reserved, existingHash, response, err := repo.ReserveIdempotencyKey(ctx, "acme", "key-1", "hash", expiredBefore)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.reserved, reserved)
+			if !tt.reserved {
+				assert.Equal(t, "other", existingHash)
+			}
+			assert.Equal(t, tt.wantResponse, response)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"hw/internal/model"
+)
+
+var upsertAddressLabelQuery = queries.Add("UpsertAddressLabel", `
+	INSERT INTO address_labels (address, label, name, created_by, updated_by, project_id)
+	VALUES ($1, $2, $3, $4, $4, $5)
+	ON CONFLICT (project_id, address, label) DO UPDATE SET
+		name = EXCLUDED.name,
+		created_at = CASE WHEN address_labels.deleted_at IS NULL THEN address_labels.created_at ELSE CURRENT_TIMESTAMP END,
+		created_by = CASE WHEN address_labels.deleted_at IS NULL THEN address_labels.created_by ELSE EXCLUDED.created_by END,
+		updated_at = CURRENT_TIMESTAMP,
+		updated_by = EXCLUDED.updated_by,
+		deleted_at = NULL
+	RETURNING created_at, created_by, updated_at
+`)
+
+// UpsertAddressLabel labels an address, or renames its label, by the operator in the label's
+// UpdatedBy, and sets its CreatedAt, CreatedBy and UpdatedAt. A removed label is created again.
+func (r *repository) UpsertAddressLabel(ctx context.Context, label *model.AddressLabel) error {
+	if err := r.db.QueryRow(ctx, upsertAddressLabelQuery, label.Address, label.Label, label.Name, label.UpdatedBy, r.project).Scan(&label.CreatedAt, &label.CreatedBy, &label.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert address label: %w", dbError(err))
+	}
+	return nil
+}
+
+var deleteAddressLabelQuery = queries.Add("DeleteAddressLabel", `
+	UPDATE address_labels SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP, updated_by = $3
+	WHERE address = $1 AND label = $2 AND project_id = $4 AND deleted_at IS NULL
+`)
+
+// DeleteAddressLabel removes a label from an address by an operator, or returns
+// model.ErrAddressLabelNotFound. The label is kept, marked deleted, for review.
+func (r *repository) DeleteAddressLabel(ctx context.Context, address, label, deletedBy string) error {
+	tag, err := r.db.Exec(ctx, deleteAddressLabelQuery, address, label, deletedBy, r.project)
+	if err != nil {
+		return fmt.Errorf("failed to delete address label: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrAddressLabelNotFound
+	}
+	return nil
+}
+
+var getAddressLabelsQuery = queries.Add("GetAddressLabels", `
+	SELECT address, label, name, created_at, created_by, updated_at, updated_by
+	FROM address_labels
+	WHERE project_id = $3 AND ($1 = '' OR address = $1) AND ($2 = '' OR label = $2) AND deleted_at IS NULL
+	ORDER BY address, label
+`)
+
+// GetAddressLabels retrieves the labels of an address, or of every address when address is empty,
+// limited to one label unless label is empty.
+func (r *repository) GetAddressLabels(ctx context.Context, address, label string) ([]model.AddressLabel, error) {
+	rows, err := r.db.Query(ctx, getAddressLabelsQuery, address, label, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve address labels: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	labels := []model.AddressLabel{}
+	for rows.Next() {
+		var l model.AddressLabel
+		if err := rows.Scan(&l.Address, &l.Label, &l.Name, &l.CreatedAt, &l.CreatedBy, &l.UpdatedAt, &l.UpdatedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		labels = append(labels, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return labels, nil
+}
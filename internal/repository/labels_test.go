@@ -0,0 +1,104 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestUpsertAddressLabel tests that labelling an address records its operator and sets the
+// timestamps and creator of the label.
+func TestUpsertAddressLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	created := time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("UpsertAddressLabel"), "0xabc", model.LabelRouter, "Uniswap V2: Router 2", "bob", model.DefaultProjectID).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*time.Time)) = created
+		*(dest[1].(*string)) = "alice"
+		*(dest[2].(*time.Time)) = created
+		return nil
+	})
+
+	label := &model.AddressLabel{Address: "0xabc", Label: model.LabelRouter, Name: "Uniswap V2: Router 2", UpdatedBy: "bob"}
+	assert.NoError(t, repo.UpsertAddressLabel(ctx, label))
+	assert.Equal(t, created, label.CreatedAt)
+	assert.Equal(t, "alice", label.CreatedBy)
+	assert.Equal(t, created, label.UpdatedAt)
+}
+
+// TestDeleteAddressLabel tests removing a label an address carries, by its operator, and one it does not.
+func TestDeleteAddressLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		wantErr error
+	}{
+		{name: "deleted", tag: "UPDATE 1"},
+		{name: "not labelled", tag: "UPDATE 0", wantErr: model.ErrAddressLabelNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			repo := repository.NewRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().Exec(ctx, pgMock.Query("DeleteAddressLabel"), "0xabc", model.LabelExchange, "alice", model.DefaultProjectID).Return(pgconn.NewCommandTag(tt.tag), nil)
+
+			err := repo.DeleteAddressLabel(ctx, "0xabc", model.LabelExchange, "alice")
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+// TestGetAddressLabels tests retrieving the labels of an address.
+func TestGetAddressLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetAddressLabels"), "0xabc", "", model.DefaultProjectID).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "0xabc"
+		*(dest[1].(*string)) = model.LabelTeamWallet
+		*(dest[2].(*string)) = "Treasury"
+		*(dest[4].(*string)) = "alice"
+		*(dest[6].(*string)) = "bob"
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	labels, err := repo.GetAddressLabels(ctx, "0xabc", "")
+	assert.NoError(t, err)
+	if assert.Len(t, labels, 1) {
+		assert.Equal(t, model.LabelTeamWallet, labels[0].Label)
+		assert.Equal(t, "Treasury", labels[0].Name)
+		assert.Equal(t, "alice", labels[0].CreatedBy)
+		assert.Equal(t, "bob", labels[0].UpdatedBy)
+	}
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "", model.LabelRouter, model.DefaultProjectID).Return(nil, errors.New("query error"))
+	_, err = repo.GetAddressLabels(ctx, "", model.LabelRouter)
+	assert.ErrorContains(t, err, "failed to retrieve address labels")
+}
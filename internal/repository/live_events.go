@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"hw/internal/model"
+)
+
+// liveEventsChannel is the Postgres notification channel carrying live events between the
+// indexer, which publishes them, and the API servers, which stream them.
+const liveEventsChannel = "live_events"
+
+var notifyLiveEventQuery = queries.Add("NotifyLiveEvent", `SELECT pg_notify($1, $2)`)
+
+// NotifyLiveEvent publishes a live event to every listening API server. Like any NOTIFY, it is
+// delivered when the surrounding transaction commits, if any, and is lost when nobody listens.
+func (r *repository) NotifyLiveEvent(ctx context.Context, event *model.LiveEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode live event: %w", err)
+	}
+	if _, err := r.db.Exec(ctx, notifyLiveEventQuery, liveEventsChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify live event: %w", dbError(err))
+	}
+	return nil
+}
+
+// ListenLiveEvents holds a connection listening to the live events and passes each to handle,
+// until ctx is done or the connection fails. It always returns a non-nil error.
+func (r *repository) ListenLiveEvents(ctx context.Context, handle func(model.LiveEvent)) error {
+	pooled, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", dbError(err))
+	}
+	// The connection keeps listening until it is closed, so it is not returned to the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+liveEventsChannel); err != nil {
+		return fmt.Errorf("failed to listen to live events: %w", dbError(err))
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for live events: %w", dbError(err))
+		}
+		var event model.LiveEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			// Not published by NotifyLiveEvent
+			continue
+		}
+		handle(event)
+	}
+}
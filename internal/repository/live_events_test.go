@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestNotifyLiveEvent tests that a live event is notified as JSON on the live events channel.
+func TestNotifyLiveEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	usdValue := model.NewDecimalFromFloat(2500.5)
+	event := &model.LiveEvent{
+		Type:     model.LiveEventSwap,
+		Network:  "mainnet",
+		Pool:     "0xpool",
+		Account:  "0xuser",
+		UsdValue: &usdValue,
+		Time:     time.Unix(1727740800, 0).UTC(),
+	}
+
+	mockDB.EXPECT().Exec(ctx, pgMock.Query("NotifyLiveEvent"), "live_events", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+		var decoded model.LiveEvent
+		assert.NoError(t, json.Unmarshal([]byte(args[1].(string)), &decoded))
+		assert.Equal(t, *event, decoded)
+		return pgconn.NewCommandTag("SELECT 1"), nil
+	})
+	assert.NoError(t, repo.NotifyLiveEvent(ctx, event))
+
+	mockDB.EXPECT().Exec(ctx, pgMock.Query("NotifyLiveEvent"), gomock.Any(), gomock.Any()).Return(pgconn.CommandTag{}, errors.New("exec error"))
+	assert.ErrorContains(t, repo.NotifyLiveEvent(ctx, event), "failed to notify live event")
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var getPointsSnapshotQuery = queries.Add("GetPointsSnapshot", `
+	SELECT account, SUM(points)
+	FROM (
+		SELECT account, points FROM points_history WHERE created_at <= $1 AND project_id = $2
+		UNION ALL
+		SELECT account, points FROM points_history_rollups WHERE month <= ($1::timestamptz AT TIME ZONE 'UTC')::date AND project_id = $2
+	) awards
+	GROUP BY account
+	HAVING SUM(points) > 0
+	ORDER BY account
+`)
+
+// GetPointsSnapshot retrieves every account's total points awarded up to cutoff, ordered by account.
+// A month dropped by the retention counts in full through its rollups once cutoff is past its start.
+func (r *repository) GetPointsSnapshot(ctx context.Context, cutoff time.Time) ([]model.PointsBalance, error) {
+	rows, err := r.db.Query(ctx, getPointsSnapshotQuery, cutoff, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get points snapshot: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var balances []model.PointsBalance
+	for rows.Next() {
+		var balance model.PointsBalance
+		if err := rows.Scan(&balance.Address, &balance.Points); err != nil {
+			return nil, fmt.Errorf("failed to scan points balance: %w", dbError(err))
+		}
+		balances = append(balances, balance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	return balances, nil
+}
+
+var createMerkleDistributionQuery = queries.Add("CreateMerkleDistribution", `
+	WITH distribution AS (
+		INSERT INTO merkle_distributions (cutoff, root, token_total, project_id)
+		VALUES ($1, $2, $3::numeric, $8)
+		RETURNING id, created_at
+	), proofs AS (
+		INSERT INTO merkle_proofs (distribution_id, address, index, amount, proof)
+		SELECT d.id, p.address, p.index, p.amount::numeric, p.proof::jsonb
+		FROM distribution d, unnest($4::text[], $5::int8[], $6::text[], $7::text[]) AS p(address, index, amount, proof)
+	)
+	SELECT id, created_at FROM distribution
+`)
+
+// CreateMerkleDistribution inserts a distribution together with the proof of every address in a single
+// statement, so a distribution is never visible without its proofs. It sets the ID and CreatedAt of distribution.
+func (r *repository) CreateMerkleDistribution(ctx context.Context, distribution *model.MerkleDistribution, proofs []model.MerkleProof) error {
+	addresses := make([]string, len(proofs))
+	indexes := make([]int64, len(proofs))
+	amounts := make([]string, len(proofs))
+	proofsJSON := make([]string, len(proofs))
+	for i, proof := range proofs {
+		data, err := json.Marshal(proof.Proof)
+		if err != nil {
+			return fmt.Errorf("failed to encode merkle proof: %w", dbError(err))
+		}
+		addresses[i], indexes[i], amounts[i], proofsJSON[i] = proof.Address, int64(proof.Index), proof.Amount, string(data)
+	}
+
+	err := r.db.QueryRow(ctx, createMerkleDistributionQuery, distribution.Cutoff, distribution.Root, distribution.TokenTotal,
+		addresses, indexes, amounts, proofsJSON, r.project).Scan(&distribution.ID, &distribution.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create merkle distribution: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var getMerkleDistributionQuery = queries.Add("GetMerkleDistribution", `
+	SELECT id, cutoff, root, token_total::text, created_at
+	FROM merkle_distributions
+	WHERE id = $1 AND project_id = $2
+`)
+
+// GetMerkleDistribution retrieves a distribution by ID.
+func (r *repository) GetMerkleDistribution(ctx context.Context, id int) (*model.MerkleDistribution, error) {
+	var distribution model.MerkleDistribution
+	err := r.db.QueryRow(ctx, getMerkleDistributionQuery, id, r.project).Scan(&distribution.ID, &distribution.Cutoff, &distribution.Root, &distribution.TokenTotal, &distribution.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrDistributionNotFound
+		}
+		return nil, fmt.Errorf("failed to get merkle distribution: %w", dbError(err))
+	}
+
+	return &distribution, nil
+}
+
+var getLatestMerkleProofQuery = queries.Add("GetLatestMerkleProof", `
+	SELECT d.id, d.root, d.cutoff, p.index, p.address, p.amount::text, p.proof
+	FROM merkle_proofs p
+	JOIN merkle_distributions d ON d.id = p.distribution_id
+	WHERE p.address = $1 AND d.id = (SELECT MAX(id) FROM merkle_distributions WHERE project_id = $2)
+`)
+
+// GetLatestMerkleProof retrieves an address's proof in the latest distribution.
+func (r *repository) GetLatestMerkleProof(ctx context.Context, address string) (*model.MerkleProof, error) {
+	var (
+		proof model.MerkleProof
+		index int64
+	)
+	err := r.db.QueryRow(ctx, getLatestMerkleProofQuery, address, r.project).Scan(&proof.DistributionID, &proof.Root, &proof.Cutoff, &index, &proof.Address, &proof.Amount, &proof.Proof)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrProofNotFound
+		}
+		return nil, fmt.Errorf("failed to get merkle proof: %w", dbError(err))
+	}
+	proof.Index = uint64(index)
+
+	return &proof, nil
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	model "hw/internal/model"
+	repository "hw/internal/repository"
 	pg "hw/pkg/pg"
 	reflect "reflect"
 	time "time"
@@ -42,6 +43,50 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddQuestVolume mocks base method.
+func (m *MockRepository) AddQuestVolume(ctx context.Context, questID, account string, usd model.Decimal) (*model.QuestProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddQuestVolume", ctx, questID, account, usd)
+	ret0, _ := ret[0].(*model.QuestProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddQuestVolume indicates an expected call of AddQuestVolume.
+func (mr *MockRepositoryMockRecorder) AddQuestVolume(ctx, questID, account, usd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddQuestVolume", reflect.TypeOf((*MockRepository)(nil).AddQuestVolume), ctx, questID, account, usd)
+}
+
+// AdvanceQuestDays mocks base method.
+func (m *MockRepository) AdvanceQuestDays(ctx context.Context, questID, account string, t time.Time) (*model.QuestProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceQuestDays", ctx, questID, account, t)
+	ret0, _ := ret[0].(*model.QuestProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceQuestDays indicates an expected call of AdvanceQuestDays.
+func (mr *MockRepositoryMockRecorder) AdvanceQuestDays(ctx, questID, account, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceQuestDays", reflect.TypeOf((*MockRepository)(nil).AdvanceQuestDays), ctx, questID, account, t)
+}
+
+// ApplyPositionChange mocks base method.
+func (m *MockRepository) ApplyPositionChange(ctx context.Context, change *model.PositionChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPositionChange", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyPositionChange indicates an expected call of ApplyPositionChange.
+func (mr *MockRepositoryMockRecorder) ApplyPositionChange(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPositionChange", reflect.TypeOf((*MockRepository)(nil).ApplyPositionChange), ctx, change)
+}
+
 // BeginTransaction mocks base method.
 func (m *MockRepository) BeginTransaction(ctx context.Context) (pg.PgxTx, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +102,106 @@ func (mr *MockRepositoryMockRecorder) BeginTransaction(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockRepository)(nil).BeginTransaction), ctx)
 }
 
+// ClaimUserPoints mocks base method.
+func (m *MockRepository) ClaimUserPoints(ctx context.Context, address, signature string) (*model.PointClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimUserPoints", ctx, address, signature)
+	ret0, _ := ret[0].(*model.PointClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimUserPoints indicates an expected call of ClaimUserPoints.
+func (mr *MockRepositoryMockRecorder) ClaimUserPoints(ctx, address, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimUserPoints", reflect.TypeOf((*MockRepository)(nil).ClaimUserPoints), ctx, address, signature)
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockRepository) CompleteIdempotencyKey(ctx context.Context, scope, key string, response *model.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", ctx, scope, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) CompleteIdempotencyKey(ctx, scope, key, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).CompleteIdempotencyKey), ctx, scope, key, response)
+}
+
+// CompleteQuest mocks base method.
+func (m *MockRepository) CompleteQuest(ctx context.Context, questID, account string, t time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteQuest", ctx, questID, account, t)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteQuest indicates an expected call of CompleteQuest.
+func (mr *MockRepositoryMockRecorder) CompleteQuest(ctx, questID, account, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteQuest", reflect.TypeOf((*MockRepository)(nil).CompleteQuest), ctx, questID, account, t)
+}
+
+// ConsumeSignInNonce mocks base method.
+func (m *MockRepository) ConsumeSignInNonce(ctx context.Context, nonce string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeSignInNonce", ctx, nonce)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeSignInNonce indicates an expected call of ConsumeSignInNonce.
+func (mr *MockRepositoryMockRecorder) ConsumeSignInNonce(ctx, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeSignInNonce", reflect.TypeOf((*MockRepository)(nil).ConsumeSignInNonce), ctx, nonce)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockRepository) CreateAPIKey(ctx context.Context, key *model.APIKey, keyHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key, keyHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockRepositoryMockRecorder) CreateAPIKey(ctx, key, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockRepository)(nil).CreateAPIKey), ctx, key, keyHash)
+}
+
+// CreateBalanceSnapshot mocks base method.
+func (m *MockRepository) CreateBalanceSnapshot(ctx context.Context, snapshot *model.BalanceSnapshot, balances []model.HolderBalance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBalanceSnapshot", ctx, snapshot, balances)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBalanceSnapshot indicates an expected call of CreateBalanceSnapshot.
+func (mr *MockRepositoryMockRecorder) CreateBalanceSnapshot(ctx, snapshot, balances any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalanceSnapshot", reflect.TypeOf((*MockRepository)(nil).CreateBalanceSnapshot), ctx, snapshot, balances)
+}
+
+// CreateMerkleDistribution mocks base method.
+func (m *MockRepository) CreateMerkleDistribution(ctx context.Context, distribution *model.MerkleDistribution, proofs []model.MerkleProof) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMerkleDistribution", ctx, distribution, proofs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMerkleDistribution indicates an expected call of CreateMerkleDistribution.
+func (mr *MockRepositoryMockRecorder) CreateMerkleDistribution(ctx, distribution, proofs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMerkleDistribution", reflect.TypeOf((*MockRepository)(nil).CreateMerkleDistribution), ctx, distribution, proofs)
+}
+
 // CreatePointsHistory mocks base method.
 func (m *MockRepository) CreatePointsHistory(ctx context.Context, pointsHistory *model.PointsHistory) error {
 	m.ctrl.T.Helper()
@@ -71,6 +216,34 @@ func (mr *MockRepositoryMockRecorder) CreatePointsHistory(ctx, pointsHistory any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePointsHistory", reflect.TypeOf((*MockRepository)(nil).CreatePointsHistory), ctx, pointsHistory)
 }
 
+// CreateProject mocks base method.
+func (m *MockRepository) CreateProject(ctx context.Context, project *model.Project) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProject", ctx, project)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockRepositoryMockRecorder) CreateProject(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockRepository)(nil).CreateProject), ctx, project)
+}
+
+// CreateSignInNonce mocks base method.
+func (m *MockRepository) CreateSignInNonce(ctx context.Context, nonce string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSignInNonce", ctx, nonce, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSignInNonce indicates an expected call of CreateSignInNonce.
+func (mr *MockRepositoryMockRecorder) CreateSignInNonce(ctx, nonce, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSignInNonce", reflect.TypeOf((*MockRepository)(nil).CreateSignInNonce), ctx, nonce, expiresAt)
+}
+
 // CreateSwapHistory mocks base method.
 func (m *MockRepository) CreateSwapHistory(ctx context.Context, swapHistory *model.SwapHistory) error {
 	m.ctrl.T.Helper()
@@ -114,136 +287,1156 @@ func (mr *MockRepositoryMockRecorder) CreateUser(ctx, userId any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepository)(nil).CreateUser), ctx, userId)
 }
 
-// GetLeaderboard mocks base method.
-func (m *MockRepository) GetLeaderboard(ctx context.Context) ([]model.User, error) {
+// CreateUsers mocks base method.
+func (m *MockRepository) CreateUsers(ctx context.Context, addresses []string) ([]model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLeaderboard", ctx)
+	ret := m.ctrl.Call(m, "CreateUsers", ctx, addresses)
 	ret0, _ := ret[0].([]model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLeaderboard indicates an expected call of GetLeaderboard.
-func (mr *MockRepositoryMockRecorder) GetLeaderboard(ctx any) *gomock.Call {
+// CreateUsers indicates an expected call of CreateUsers.
+func (mr *MockRepositoryMockRecorder) CreateUsers(ctx, addresses any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboard", reflect.TypeOf((*MockRepository)(nil).GetLeaderboard), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUsers", reflect.TypeOf((*MockRepository)(nil).CreateUsers), ctx, addresses)
 }
 
-// GetPointsHistory mocks base method.
-func (m *MockRepository) GetPointsHistory(ctx context.Context, account, token string) ([]model.PointsHistory, error) {
+// DeleteAddressLabel mocks base method.
+func (m *MockRepository) DeleteAddressLabel(ctx context.Context, address, label, deletedBy string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPointsHistory", ctx, account, token)
-	ret0, _ := ret[0].([]model.PointsHistory)
+	ret := m.ctrl.Call(m, "DeleteAddressLabel", ctx, address, label, deletedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddressLabel indicates an expected call of DeleteAddressLabel.
+func (mr *MockRepositoryMockRecorder) DeleteAddressLabel(ctx, address, label, deletedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddressLabel", reflect.TypeOf((*MockRepository)(nil).DeleteAddressLabel), ctx, address, label, deletedBy)
+}
+
+// DropHistoryMonth mocks base method.
+func (m *MockRepository) DropHistoryMonth(ctx context.Context, table string, month time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropHistoryMonth", ctx, table, month)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPointsHistory indicates an expected call of GetPointsHistory.
-func (mr *MockRepositoryMockRecorder) GetPointsHistory(ctx, account, token any) *gomock.Call {
+// DropHistoryMonth indicates an expected call of DropHistoryMonth.
+func (mr *MockRepositoryMockRecorder) DropHistoryMonth(ctx, table, month any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistory", reflect.TypeOf((*MockRepository)(nil).GetPointsHistory), ctx, account, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropHistoryMonth", reflect.TypeOf((*MockRepository)(nil).DropHistoryMonth), ctx, table, month)
 }
 
-// GetSwapTotalUsd mocks base method.
-func (m *MockRepository) GetSwapTotalUsd(ctx context.Context, account, token string) (float64, error) {
+// EnsureHistoryPartitions mocks base method.
+func (m *MockRepository) EnsureHistoryPartitions(ctx context.Context, table string, from, to time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSwapTotalUsd", ctx, account, token)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "EnsureHistoryPartitions", ctx, table, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureHistoryPartitions indicates an expected call of EnsureHistoryPartitions.
+func (mr *MockRepositoryMockRecorder) EnsureHistoryPartitions(ctx, table, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureHistoryPartitions", reflect.TypeOf((*MockRepository)(nil).EnsureHistoryPartitions), ctx, table, from, to)
+}
+
+// FlagRoundTripSwaps mocks base method.
+func (m *MockRepository) FlagRoundTripSwaps(ctx context.Context, swapHistory *model.SwapHistory) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlagRoundTripSwaps", ctx, swapHistory)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSwapTotalUsd indicates an expected call of GetSwapTotalUsd.
-func (mr *MockRepositoryMockRecorder) GetSwapTotalUsd(ctx, account, token any) *gomock.Call {
+// FlagRoundTripSwaps indicates an expected call of FlagRoundTripSwaps.
+func (mr *MockRepositoryMockRecorder) FlagRoundTripSwaps(ctx, swapHistory any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwapTotalUsd", reflect.TypeOf((*MockRepository)(nil).GetSwapTotalUsd), ctx, account, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagRoundTripSwaps", reflect.TypeOf((*MockRepository)(nil).FlagRoundTripSwaps), ctx, swapHistory)
 }
 
-// GetTokenByAddress mocks base method.
-func (m *MockRepository) GetTokenByAddress(ctx context.Context, address string) (*model.Token, error) {
+// ForProject mocks base method.
+func (m *MockRepository) ForProject(projectID int) repository.Repository {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTokenByAddress", ctx, address)
-	ret0, _ := ret[0].(*model.Token)
+	ret := m.ctrl.Call(m, "ForProject", projectID)
+	ret0, _ := ret[0].(repository.Repository)
+	return ret0
+}
+
+// ForProject indicates an expected call of ForProject.
+func (mr *MockRepositoryMockRecorder) ForProject(projectID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForProject", reflect.TypeOf((*MockRepository)(nil).ForProject), projectID)
+}
+
+// GetAccountGasTotals mocks base method.
+func (m *MockRepository) GetAccountGasTotals(ctx context.Context, account string) ([]model.GasTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountGasTotals", ctx, account)
+	ret0, _ := ret[0].([]model.GasTotal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTokenByAddress indicates an expected call of GetTokenByAddress.
-func (mr *MockRepositoryMockRecorder) GetTokenByAddress(ctx, address any) *gomock.Call {
+// GetAccountGasTotals indicates an expected call of GetAccountGasTotals.
+func (mr *MockRepositoryMockRecorder) GetAccountGasTotals(ctx, account any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenByAddress", reflect.TypeOf((*MockRepository)(nil).GetTokenByAddress), ctx, address)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountGasTotals", reflect.TypeOf((*MockRepository)(nil).GetAccountGasTotals), ctx, account)
 }
 
-// GetUserByAddress mocks base method.
-func (m *MockRepository) GetUserByAddress(ctx context.Context, address string) (*model.User, error) {
+// GetAccountPositions mocks base method.
+func (m *MockRepository) GetAccountPositions(ctx context.Context, account, network string) ([]model.Position, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByAddress", ctx, address)
-	ret0, _ := ret[0].(*model.User)
+	ret := m.ctrl.Call(m, "GetAccountPositions", ctx, account, network)
+	ret0, _ := ret[0].([]model.Position)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByAddress indicates an expected call of GetUserByAddress.
-func (mr *MockRepositoryMockRecorder) GetUserByAddress(ctx, address any) *gomock.Call {
+// GetAccountPositions indicates an expected call of GetAccountPositions.
+func (mr *MockRepositoryMockRecorder) GetAccountPositions(ctx, account, network any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByAddress", reflect.TypeOf((*MockRepository)(nil).GetUserByAddress), ctx, address)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountPositions", reflect.TypeOf((*MockRepository)(nil).GetAccountPositions), ctx, account, network)
 }
 
-// GetUserSwapSummary mocks base method.
-func (m *MockRepository) GetUserSwapSummary(ctx context.Context, account string) (map[string]float64, error) {
+// GetAddressLabels mocks base method.
+func (m *MockRepository) GetAddressLabels(ctx context.Context, address, label string) ([]model.AddressLabel, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserSwapSummary", ctx, account)
-	ret0, _ := ret[0].(map[string]float64)
+	ret := m.ctrl.Call(m, "GetAddressLabels", ctx, address, label)
+	ret0, _ := ret[0].([]model.AddressLabel)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserSwapSummary indicates an expected call of GetUserSwapSummary.
-func (mr *MockRepositoryMockRecorder) GetUserSwapSummary(ctx, account any) *gomock.Call {
+// GetAddressLabels indicates an expected call of GetAddressLabels.
+func (mr *MockRepositoryMockRecorder) GetAddressLabels(ctx, address, label any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummary", reflect.TypeOf((*MockRepository)(nil).GetUserSwapSummary), ctx, account)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressLabels", reflect.TypeOf((*MockRepository)(nil).GetAddressLabels), ctx, address, label)
 }
 
-// GetUserSwapSummaryLast7Days mocks base method.
-func (m *MockRepository) GetUserSwapSummaryLast7Days(ctx context.Context, referenceTime time.Time, token string) ([]model.UserSwapPercentage, error) {
+// GetBigSwapAlerts mocks base method.
+func (m *MockRepository) GetBigSwapAlerts(ctx context.Context, minUSD model.Decimal) ([]model.BigSwapAlert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserSwapSummaryLast7Days", ctx, referenceTime, token)
-	ret0, _ := ret[0].([]model.UserSwapPercentage)
+	ret := m.ctrl.Call(m, "GetBigSwapAlerts", ctx, minUSD)
+	ret0, _ := ret[0].([]model.BigSwapAlert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserSwapSummaryLast7Days indicates an expected call of GetUserSwapSummaryLast7Days.
-func (mr *MockRepositoryMockRecorder) GetUserSwapSummaryLast7Days(ctx, referenceTime, token any) *gomock.Call {
+// GetBigSwapAlerts indicates an expected call of GetBigSwapAlerts.
+func (mr *MockRepositoryMockRecorder) GetBigSwapAlerts(ctx, minUSD any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummaryLast7Days", reflect.TypeOf((*MockRepository)(nil).GetUserSwapSummaryLast7Days), ctx, referenceTime, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBigSwapAlerts", reflect.TypeOf((*MockRepository)(nil).GetBigSwapAlerts), ctx, minUSD)
 }
 
-// IsOnboardingTaskCompleted mocks base method.
-func (m *MockRepository) IsOnboardingTaskCompleted(ctx context.Context, account string) (bool, error) {
+// GetDailyCountedUsd mocks base method.
+func (m *MockRepository) GetDailyCountedUsd(ctx context.Context, account string, t time.Time) (model.Decimal, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsOnboardingTaskCompleted", ctx, account)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetDailyCountedUsd", ctx, account, t)
+	ret0, _ := ret[0].(model.Decimal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsOnboardingTaskCompleted indicates an expected call of IsOnboardingTaskCompleted.
-func (mr *MockRepositoryMockRecorder) IsOnboardingTaskCompleted(ctx, account any) *gomock.Call {
+// GetDailyCountedUsd indicates an expected call of GetDailyCountedUsd.
+func (mr *MockRepositoryMockRecorder) GetDailyCountedUsd(ctx, account, t any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOnboardingTaskCompleted", reflect.TypeOf((*MockRepository)(nil).IsOnboardingTaskCompleted), ctx, account)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyCountedUsd", reflect.TypeOf((*MockRepository)(nil).GetDailyCountedUsd), ctx, account, t)
 }
 
-// UpsertUserPoints mocks base method.
-func (m *MockRepository) UpsertUserPoints(ctx context.Context, address string, point float64) error {
+// GetDailyPoolVolumes mocks base method.
+func (m *MockRepository) GetDailyPoolVolumes(ctx context.Context, network, token string, from, to time.Time) ([]model.DailyVolume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpsertUserPoints", ctx, address, point)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetDailyPoolVolumes", ctx, network, token, from, to)
+	ret0, _ := ret[0].([]model.DailyVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpsertUserPoints indicates an expected call of UpsertUserPoints.
-func (mr *MockRepositoryMockRecorder) UpsertUserPoints(ctx, address, point any) *gomock.Call {
+// GetDailyPoolVolumes indicates an expected call of GetDailyPoolVolumes.
+func (mr *MockRepositoryMockRecorder) GetDailyPoolVolumes(ctx, network, token, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserPoints", reflect.TypeOf((*MockRepository)(nil).UpsertUserPoints), ctx, address, point)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyPoolVolumes", reflect.TypeOf((*MockRepository)(nil).GetDailyPoolVolumes), ctx, network, token, from, to)
+}
+
+// GetDefaultPartitionMonths mocks base method.
+func (m *MockRepository) GetDefaultPartitionMonths(ctx context.Context, table string) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultPartitionMonths", ctx, table)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefaultPartitionMonths indicates an expected call of GetDefaultPartitionMonths.
+func (mr *MockRepositoryMockRecorder) GetDefaultPartitionMonths(ctx, table any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultPartitionMonths", reflect.TypeOf((*MockRepository)(nil).GetDefaultPartitionMonths), ctx, table)
+}
+
+// GetGasLeaderboard mocks base method.
+func (m *MockRepository) GetGasLeaderboard(ctx context.Context, network string, limit int) ([]model.GasTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGasLeaderboard", ctx, network, limit)
+	ret0, _ := ret[0].([]model.GasTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGasLeaderboard indicates an expected call of GetGasLeaderboard.
+func (mr *MockRepositoryMockRecorder) GetGasLeaderboard(ctx, network, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGasLeaderboard", reflect.TypeOf((*MockRepository)(nil).GetGasLeaderboard), ctx, network, limit)
+}
+
+// GetHistoryMonthRows mocks base method.
+func (m *MockRepository) GetHistoryMonthRows(ctx context.Context, table string, month time.Time, afterID int64, limit int) ([]model.HistoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistoryMonthRows", ctx, table, month, afterID, limit)
+	ret0, _ := ret[0].([]model.HistoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistoryMonthRows indicates an expected call of GetHistoryMonthRows.
+func (mr *MockRepositoryMockRecorder) GetHistoryMonthRows(ctx, table, month, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryMonthRows", reflect.TypeOf((*MockRepository)(nil).GetHistoryMonthRows), ctx, table, month, afterID, limit)
+}
+
+// GetLatestBalanceSnapshot mocks base method.
+func (m *MockRepository) GetLatestBalanceSnapshot(ctx context.Context, token, network string, blockNumber int64) (*model.BalanceSnapshot, []model.HolderBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestBalanceSnapshot", ctx, token, network, blockNumber)
+	ret0, _ := ret[0].(*model.BalanceSnapshot)
+	ret1, _ := ret[1].([]model.HolderBalance)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLatestBalanceSnapshot indicates an expected call of GetLatestBalanceSnapshot.
+func (mr *MockRepositoryMockRecorder) GetLatestBalanceSnapshot(ctx, token, network, blockNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestBalanceSnapshot", reflect.TypeOf((*MockRepository)(nil).GetLatestBalanceSnapshot), ctx, token, network, blockNumber)
+}
+
+// GetLatestMerkleProof mocks base method.
+func (m *MockRepository) GetLatestMerkleProof(ctx context.Context, address string) (*model.MerkleProof, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestMerkleProof", ctx, address)
+	ret0, _ := ret[0].(*model.MerkleProof)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestMerkleProof indicates an expected call of GetLatestMerkleProof.
+func (mr *MockRepositoryMockRecorder) GetLatestMerkleProof(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestMerkleProof", reflect.TypeOf((*MockRepository)(nil).GetLatestMerkleProof), ctx, address)
+}
+
+// GetLatestPoolReserves mocks base method.
+func (m *MockRepository) GetLatestPoolReserves(ctx context.Context, pool, network string) (*model.PoolReserves, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestPoolReserves", ctx, pool, network)
+	ret0, _ := ret[0].(*model.PoolReserves)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestPoolReserves indicates an expected call of GetLatestPoolReserves.
+func (mr *MockRepositoryMockRecorder) GetLatestPoolReserves(ctx, pool, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestPoolReserves", reflect.TypeOf((*MockRepository)(nil).GetLatestPoolReserves), ctx, pool, network)
+}
+
+// GetLatestTokenPrice mocks base method.
+func (m *MockRepository) GetLatestTokenPrice(ctx context.Context, token, network string, since, at time.Time) (*model.TokenPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestTokenPrice", ctx, token, network, since, at)
+	ret0, _ := ret[0].(*model.TokenPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestTokenPrice indicates an expected call of GetLatestTokenPrice.
+func (mr *MockRepositoryMockRecorder) GetLatestTokenPrice(ctx, token, network, since, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestTokenPrice", reflect.TypeOf((*MockRepository)(nil).GetLatestTokenPrice), ctx, token, network, since, at)
+}
+
+// GetLeaderboard mocks base method.
+func (m *MockRepository) GetLeaderboard(ctx context.Context, exclude model.Exclusion) ([]model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboard", ctx, exclude)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboard indicates an expected call of GetLeaderboard.
+func (mr *MockRepositoryMockRecorder) GetLeaderboard(ctx, exclude any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboard", reflect.TypeOf((*MockRepository)(nil).GetLeaderboard), ctx, exclude)
+}
+
+// GetLeaderboardPage mocks base method.
+func (m *MockRepository) GetLeaderboardPage(ctx context.Context, cursor string, limit int, exclude model.Exclusion) ([]model.User, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardPage", ctx, cursor, limit, exclude)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLeaderboardPage indicates an expected call of GetLeaderboardPage.
+func (mr *MockRepositoryMockRecorder) GetLeaderboardPage(ctx, cursor, limit, exclude any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardPage", reflect.TypeOf((*MockRepository)(nil).GetLeaderboardPage), ctx, cursor, limit, exclude)
+}
+
+// GetMerkleDistribution mocks base method.
+func (m *MockRepository) GetMerkleDistribution(ctx context.Context, id int) (*model.MerkleDistribution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerkleDistribution", ctx, id)
+	ret0, _ := ret[0].(*model.MerkleDistribution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerkleDistribution indicates an expected call of GetMerkleDistribution.
+func (mr *MockRepositoryMockRecorder) GetMerkleDistribution(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerkleDistribution", reflect.TypeOf((*MockRepository)(nil).GetMerkleDistribution), ctx, id)
+}
+
+// GetOldestHistoryTime mocks base method.
+func (m *MockRepository) GetOldestHistoryTime(ctx context.Context, table string) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOldestHistoryTime", ctx, table)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOldestHistoryTime indicates an expected call of GetOldestHistoryTime.
+func (mr *MockRepositoryMockRecorder) GetOldestHistoryTime(ctx, table any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldestHistoryTime", reflect.TypeOf((*MockRepository)(nil).GetOldestHistoryTime), ctx, table)
+}
+
+// GetOwnerAllowances mocks base method.
+func (m *MockRepository) GetOwnerAllowances(ctx context.Context, owner, network string) ([]model.Allowance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOwnerAllowances", ctx, owner, network)
+	ret0, _ := ret[0].([]model.Allowance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOwnerAllowances indicates an expected call of GetOwnerAllowances.
+func (mr *MockRepositoryMockRecorder) GetOwnerAllowances(ctx, owner, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwnerAllowances", reflect.TypeOf((*MockRepository)(nil).GetOwnerAllowances), ctx, owner, network)
+}
+
+// GetPendingSettlementTxs mocks base method.
+func (m *MockRepository) GetPendingSettlementTxs(ctx context.Context, network string) ([]model.SettlementTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingSettlementTxs", ctx, network)
+	ret0, _ := ret[0].([]model.SettlementTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingSettlementTxs indicates an expected call of GetPendingSettlementTxs.
+func (mr *MockRepositoryMockRecorder) GetPendingSettlementTxs(ctx, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingSettlementTxs", reflect.TypeOf((*MockRepository)(nil).GetPendingSettlementTxs), ctx, network)
+}
+
+// GetPointsHistory mocks base method.
+func (m *MockRepository) GetPointsHistory(ctx context.Context, account, token string) ([]model.PointsHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPointsHistory", ctx, account, token)
+	ret0, _ := ret[0].([]model.PointsHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPointsHistory indicates an expected call of GetPointsHistory.
+func (mr *MockRepositoryMockRecorder) GetPointsHistory(ctx, account, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistory", reflect.TypeOf((*MockRepository)(nil).GetPointsHistory), ctx, account, token)
+}
+
+// GetPointsHistoryByNetwork mocks base method.
+func (m *MockRepository) GetPointsHistoryByNetwork(ctx context.Context, account, token, network string) ([]model.PointsHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPointsHistoryByNetwork", ctx, account, token, network)
+	ret0, _ := ret[0].([]model.PointsHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPointsHistoryByNetwork indicates an expected call of GetPointsHistoryByNetwork.
+func (mr *MockRepositoryMockRecorder) GetPointsHistoryByNetwork(ctx, account, token, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistoryByNetwork", reflect.TypeOf((*MockRepository)(nil).GetPointsHistoryByNetwork), ctx, account, token, network)
+}
+
+// GetPointsHistoryByTokens mocks base method.
+func (m *MockRepository) GetPointsHistoryByTokens(ctx context.Context, account string, tokens []string, network string) ([]model.PointsHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPointsHistoryByTokens", ctx, account, tokens, network)
+	ret0, _ := ret[0].([]model.PointsHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPointsHistoryByTokens indicates an expected call of GetPointsHistoryByTokens.
+func (mr *MockRepositoryMockRecorder) GetPointsHistoryByTokens(ctx, account, tokens, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistoryByTokens", reflect.TypeOf((*MockRepository)(nil).GetPointsHistoryByTokens), ctx, account, tokens, network)
+}
+
+// GetPointsHistoryPage mocks base method.
+func (m *MockRepository) GetPointsHistoryPage(ctx context.Context, account, token, cursor string, limit int) ([]model.PointsHistory, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPointsHistoryPage", ctx, account, token, cursor, limit)
+	ret0, _ := ret[0].([]model.PointsHistory)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPointsHistoryPage indicates an expected call of GetPointsHistoryPage.
+func (mr *MockRepositoryMockRecorder) GetPointsHistoryPage(ctx, account, token, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistoryPage", reflect.TypeOf((*MockRepository)(nil).GetPointsHistoryPage), ctx, account, token, cursor, limit)
+}
+
+// GetPointsSnapshot mocks base method.
+func (m *MockRepository) GetPointsSnapshot(ctx context.Context, cutoff time.Time) ([]model.PointsBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPointsSnapshot", ctx, cutoff)
+	ret0, _ := ret[0].([]model.PointsBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPointsSnapshot indicates an expected call of GetPointsSnapshot.
+func (mr *MockRepositoryMockRecorder) GetPointsSnapshot(ctx, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsSnapshot", reflect.TypeOf((*MockRepository)(nil).GetPointsSnapshot), ctx, cutoff)
+}
+
+// GetPoolTopTraders mocks base method.
+func (m *MockRepository) GetPoolTopTraders(ctx context.Context, token string, since time.Time, limit int) ([]model.TraderVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoolTopTraders", ctx, token, since, limit)
+	ret0, _ := ret[0].([]model.TraderVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoolTopTraders indicates an expected call of GetPoolTopTraders.
+func (mr *MockRepositoryMockRecorder) GetPoolTopTraders(ctx, token, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoolTopTraders", reflect.TypeOf((*MockRepository)(nil).GetPoolTopTraders), ctx, token, since, limit)
+}
+
+// GetPoolVolumeStats mocks base method.
+func (m *MockRepository) GetPoolVolumeStats(ctx context.Context, token string, since time.Time) (*model.PoolVolumeStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoolVolumeStats", ctx, token, since)
+	ret0, _ := ret[0].(*model.PoolVolumeStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoolVolumeStats indicates an expected call of GetPoolVolumeStats.
+func (mr *MockRepositoryMockRecorder) GetPoolVolumeStats(ctx, token, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoolVolumeStats", reflect.TypeOf((*MockRepository)(nil).GetPoolVolumeStats), ctx, token, since)
+}
+
+// GetPositionChanges mocks base method.
+func (m *MockRepository) GetPositionChanges(ctx context.Context, contract, network string, before time.Time) ([]model.PositionChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPositionChanges", ctx, contract, network, before)
+	ret0, _ := ret[0].([]model.PositionChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPositionChanges indicates an expected call of GetPositionChanges.
+func (mr *MockRepositoryMockRecorder) GetPositionChanges(ctx, contract, network, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPositionChanges", reflect.TypeOf((*MockRepository)(nil).GetPositionChanges), ctx, contract, network, before)
+}
+
+// GetProjectByAPIKey mocks base method.
+func (m *MockRepository) GetProjectByAPIKey(ctx context.Context, keyHash string) (*model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectByAPIKey", ctx, keyHash)
+	ret0, _ := ret[0].(*model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectByAPIKey indicates an expected call of GetProjectByAPIKey.
+func (mr *MockRepositoryMockRecorder) GetProjectByAPIKey(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectByAPIKey", reflect.TypeOf((*MockRepository)(nil).GetProjectByAPIKey), ctx, keyHash)
+}
+
+// GetProjectBySlug mocks base method.
+func (m *MockRepository) GetProjectBySlug(ctx context.Context, slug string) (*model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectBySlug", ctx, slug)
+	ret0, _ := ret[0].(*model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectBySlug indicates an expected call of GetProjectBySlug.
+func (mr *MockRepositoryMockRecorder) GetProjectBySlug(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectBySlug", reflect.TypeOf((*MockRepository)(nil).GetProjectBySlug), ctx, slug)
+}
+
+// GetQuestProgress mocks base method.
+func (m *MockRepository) GetQuestProgress(ctx context.Context, account string) ([]model.QuestProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuestProgress", ctx, account)
+	ret0, _ := ret[0].([]model.QuestProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuestProgress indicates an expected call of GetQuestProgress.
+func (mr *MockRepositoryMockRecorder) GetQuestProgress(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuestProgress", reflect.TypeOf((*MockRepository)(nil).GetQuestProgress), ctx, account)
+}
+
+// GetSettlementTxs mocks base method.
+func (m *MockRepository) GetSettlementTxs(ctx context.Context, distributionID int) ([]model.SettlementTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettlementTxs", ctx, distributionID)
+	ret0, _ := ret[0].([]model.SettlementTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettlementTxs indicates an expected call of GetSettlementTxs.
+func (mr *MockRepositoryMockRecorder) GetSettlementTxs(ctx, distributionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettlementTxs", reflect.TypeOf((*MockRepository)(nil).GetSettlementTxs), ctx, distributionID)
+}
+
+// GetSwapHistoryPage mocks base method.
+func (m *MockRepository) GetSwapHistoryPage(ctx context.Context, account, cursor string, limit int) ([]model.SwapHistory, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSwapHistoryPage", ctx, account, cursor, limit)
+	ret0, _ := ret[0].([]model.SwapHistory)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSwapHistoryPage indicates an expected call of GetSwapHistoryPage.
+func (mr *MockRepositoryMockRecorder) GetSwapHistoryPage(ctx, account, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwapHistoryPage", reflect.TypeOf((*MockRepository)(nil).GetSwapHistoryPage), ctx, account, cursor, limit)
+}
+
+// GetSwapTotalUsd mocks base method.
+func (m *MockRepository) GetSwapTotalUsd(ctx context.Context, account, token string) (model.SwapTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSwapTotalUsd", ctx, account, token)
+	ret0, _ := ret[0].(model.SwapTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSwapTotalUsd indicates an expected call of GetSwapTotalUsd.
+func (mr *MockRepositoryMockRecorder) GetSwapTotalUsd(ctx, account, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwapTotalUsd", reflect.TypeOf((*MockRepository)(nil).GetSwapTotalUsd), ctx, account, token)
+}
+
+// GetTokenByAddress mocks base method.
+func (m *MockRepository) GetTokenByAddress(ctx context.Context, address string) (*model.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenByAddress", ctx, address)
+	ret0, _ := ret[0].(*model.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenByAddress indicates an expected call of GetTokenByAddress.
+func (mr *MockRepositoryMockRecorder) GetTokenByAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenByAddress", reflect.TypeOf((*MockRepository)(nil).GetTokenByAddress), ctx, address)
+}
+
+// GetTokenHolderBuckets mocks base method.
+func (m *MockRepository) GetTokenHolderBuckets(ctx context.Context, token, network string) ([]model.HolderBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenHolderBuckets", ctx, token, network)
+	ret0, _ := ret[0].([]model.HolderBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenHolderBuckets indicates an expected call of GetTokenHolderBuckets.
+func (mr *MockRepositoryMockRecorder) GetTokenHolderBuckets(ctx, token, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenHolderBuckets", reflect.TypeOf((*MockRepository)(nil).GetTokenHolderBuckets), ctx, token, network)
+}
+
+// GetTokenPriceCandles mocks base method.
+func (m *MockRepository) GetTokenPriceCandles(ctx context.Context, token, network string, from, to time.Time, interval time.Duration) ([]model.PriceCandle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenPriceCandles", ctx, token, network, from, to, interval)
+	ret0, _ := ret[0].([]model.PriceCandle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenPriceCandles indicates an expected call of GetTokenPriceCandles.
+func (mr *MockRepositoryMockRecorder) GetTokenPriceCandles(ctx, token, network, from, to, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenPriceCandles", reflect.TypeOf((*MockRepository)(nil).GetTokenPriceCandles), ctx, token, network, from, to, interval)
+}
+
+// GetTokenVolume mocks base method.
+func (m *MockRepository) GetTokenVolume(ctx context.Context, address string) (*model.TokenVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenVolume", ctx, address)
+	ret0, _ := ret[0].(*model.TokenVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenVolume indicates an expected call of GetTokenVolume.
+func (mr *MockRepositoryMockRecorder) GetTokenVolume(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenVolume", reflect.TypeOf((*MockRepository)(nil).GetTokenVolume), ctx, address)
+}
+
+// GetTokensByAddresses mocks base method.
+func (m *MockRepository) GetTokensByAddresses(ctx context.Context, addresses []string) ([]model.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokensByAddresses", ctx, addresses)
+	ret0, _ := ret[0].([]model.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokensByAddresses indicates an expected call of GetTokensByAddresses.
+func (mr *MockRepositoryMockRecorder) GetTokensByAddresses(ctx, addresses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokensByAddresses", reflect.TypeOf((*MockRepository)(nil).GetTokensByAddresses), ctx, addresses)
+}
+
+// GetTransferBalances mocks base method.
+func (m *MockRepository) GetTransferBalances(ctx context.Context, contract, network string, blockNumber int64) ([]model.HolderBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferBalances", ctx, contract, network, blockNumber)
+	ret0, _ := ret[0].([]model.HolderBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferBalances indicates an expected call of GetTransferBalances.
+func (mr *MockRepositoryMockRecorder) GetTransferBalances(ctx, contract, network, blockNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferBalances", reflect.TypeOf((*MockRepository)(nil).GetTransferBalances), ctx, contract, network, blockNumber)
+}
+
+// GetUserByAddress mocks base method.
+func (m *MockRepository) GetUserByAddress(ctx context.Context, address string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByAddress", ctx, address)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByAddress indicates an expected call of GetUserByAddress.
+func (mr *MockRepositoryMockRecorder) GetUserByAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByAddress", reflect.TypeOf((*MockRepository)(nil).GetUserByAddress), ctx, address)
+}
+
+// GetUserNetworkSummary mocks base method.
+func (m *MockRepository) GetUserNetworkSummary(ctx context.Context, account string) (map[string]model.NetworkSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNetworkSummary", ctx, account)
+	ret0, _ := ret[0].(map[string]model.NetworkSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNetworkSummary indicates an expected call of GetUserNetworkSummary.
+func (mr *MockRepositoryMockRecorder) GetUserNetworkSummary(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNetworkSummary", reflect.TypeOf((*MockRepository)(nil).GetUserNetworkSummary), ctx, account)
+}
+
+// GetUserPools mocks base method.
+func (m *MockRepository) GetUserPools(ctx context.Context, account, network string) (map[string]*model.UserPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPools", ctx, account, network)
+	ret0, _ := ret[0].(map[string]*model.UserPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPools indicates an expected call of GetUserPools.
+func (mr *MockRepositoryMockRecorder) GetUserPools(ctx, account, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPools", reflect.TypeOf((*MockRepository)(nil).GetUserPools), ctx, account, network)
+}
+
+// GetUserProfile mocks base method.
+func (m *MockRepository) GetUserProfile(ctx context.Context, address string) (*model.UserProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserProfile", ctx, address)
+	ret0, _ := ret[0].(*model.UserProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserProfile indicates an expected call of GetUserProfile.
+func (mr *MockRepositoryMockRecorder) GetUserProfile(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserProfile", reflect.TypeOf((*MockRepository)(nil).GetUserProfile), ctx, address)
+}
+
+// GetUserRank mocks base method.
+func (m *MockRepository) GetUserRank(ctx context.Context, address string, exclude model.Exclusion) (*model.LeaderboardRank, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRank", ctx, address, exclude)
+	ret0, _ := ret[0].(*model.LeaderboardRank)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRank indicates an expected call of GetUserRank.
+func (mr *MockRepositoryMockRecorder) GetUserRank(ctx, address, exclude any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRank", reflect.TypeOf((*MockRepository)(nil).GetUserRank), ctx, address, exclude)
+}
+
+// GetUserSwapSummary mocks base method.
+func (m *MockRepository) GetUserSwapSummary(ctx context.Context, account string) (map[string]model.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSwapSummary", ctx, account)
+	ret0, _ := ret[0].(map[string]model.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSwapSummary indicates an expected call of GetUserSwapSummary.
+func (mr *MockRepositoryMockRecorder) GetUserSwapSummary(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummary", reflect.TypeOf((*MockRepository)(nil).GetUserSwapSummary), ctx, account)
+}
+
+// GetUserSwapSummaryByNetwork mocks base method.
+func (m *MockRepository) GetUserSwapSummaryByNetwork(ctx context.Context, account, network string) (map[string]model.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSwapSummaryByNetwork", ctx, account, network)
+	ret0, _ := ret[0].(map[string]model.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSwapSummaryByNetwork indicates an expected call of GetUserSwapSummaryByNetwork.
+func (mr *MockRepositoryMockRecorder) GetUserSwapSummaryByNetwork(ctx, account, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummaryByNetwork", reflect.TypeOf((*MockRepository)(nil).GetUserSwapSummaryByNetwork), ctx, account, network)
+}
+
+// GetUserSwapSummaryLast7Days mocks base method.
+func (m *MockRepository) GetUserSwapSummaryLast7Days(ctx context.Context, referenceTime time.Time, token string, excludeWashTrades bool, exclude model.Exclusion) ([]model.UserSwapPercentage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSwapSummaryLast7Days", ctx, referenceTime, token, excludeWashTrades, exclude)
+	ret0, _ := ret[0].([]model.UserSwapPercentage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSwapSummaryLast7Days indicates an expected call of GetUserSwapSummaryLast7Days.
+func (mr *MockRepositoryMockRecorder) GetUserSwapSummaryLast7Days(ctx, referenceTime, token, excludeWashTrades, exclude any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummaryLast7Days", reflect.TypeOf((*MockRepository)(nil).GetUserSwapSummaryLast7Days), ctx, referenceTime, token, excludeWashTrades, exclude)
+}
+
+// GetUsersByAddresses mocks base method.
+func (m *MockRepository) GetUsersByAddresses(ctx context.Context, addresses []string) ([]model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByAddresses", ctx, addresses)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByAddresses indicates an expected call of GetUsersByAddresses.
+func (mr *MockRepositoryMockRecorder) GetUsersByAddresses(ctx, addresses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByAddresses", reflect.TypeOf((*MockRepository)(nil).GetUsersByAddresses), ctx, addresses)
+}
+
+// GetWeeklySummaries mocks base method.
+func (m *MockRepository) GetWeeklySummaries(ctx context.Context, weekStart, weekEnd time.Time) ([]model.WeeklySummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWeeklySummaries", ctx, weekStart, weekEnd)
+	ret0, _ := ret[0].([]model.WeeklySummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWeeklySummaries indicates an expected call of GetWeeklySummaries.
+func (mr *MockRepositoryMockRecorder) GetWeeklySummaries(ctx, weekStart, weekEnd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWeeklySummaries", reflect.TypeOf((*MockRepository)(nil).GetWeeklySummaries), ctx, weekStart, weekEnd)
+}
+
+// IncrementDailyPointsRollup mocks base method.
+func (m *MockRepository) IncrementDailyPointsRollup(ctx context.Context, pointsHistory *model.PointsHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementDailyPointsRollup", ctx, pointsHistory)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementDailyPointsRollup indicates an expected call of IncrementDailyPointsRollup.
+func (mr *MockRepositoryMockRecorder) IncrementDailyPointsRollup(ctx, pointsHistory any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementDailyPointsRollup", reflect.TypeOf((*MockRepository)(nil).IncrementDailyPointsRollup), ctx, pointsHistory)
+}
+
+// IncrementDailySwapRollup mocks base method.
+func (m *MockRepository) IncrementDailySwapRollup(ctx context.Context, swapHistory *model.SwapHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementDailySwapRollup", ctx, swapHistory)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementDailySwapRollup indicates an expected call of IncrementDailySwapRollup.
+func (mr *MockRepositoryMockRecorder) IncrementDailySwapRollup(ctx, swapHistory any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementDailySwapRollup", reflect.TypeOf((*MockRepository)(nil).IncrementDailySwapRollup), ctx, swapHistory)
+}
+
+// IsOnboardingTaskCompleted mocks base method.
+func (m *MockRepository) IsOnboardingTaskCompleted(ctx context.Context, account string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOnboardingTaskCompleted", ctx, account)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsOnboardingTaskCompleted indicates an expected call of IsOnboardingTaskCompleted.
+func (mr *MockRepositoryMockRecorder) IsOnboardingTaskCompleted(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOnboardingTaskCompleted", reflect.TypeOf((*MockRepository)(nil).IsOnboardingTaskCompleted), ctx, account)
+}
+
+// ListTokens mocks base method.
+func (m *MockRepository) ListTokens(ctx context.Context, search, cursor string, limit int) ([]model.TokenVolume, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTokens", ctx, search, cursor, limit)
+	ret0, _ := ret[0].([]model.TokenVolume)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTokens indicates an expected call of ListTokens.
+func (mr *MockRepositoryMockRecorder) ListTokens(ctx, search, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTokens", reflect.TypeOf((*MockRepository)(nil).ListTokens), ctx, search, cursor, limit)
+}
+
+// ListenLiveEvents mocks base method.
+func (m *MockRepository) ListenLiveEvents(ctx context.Context, handle func(model.LiveEvent)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenLiveEvents", ctx, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenLiveEvents indicates an expected call of ListenLiveEvents.
+func (mr *MockRepositoryMockRecorder) ListenLiveEvents(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenLiveEvents", reflect.TypeOf((*MockRepository)(nil).ListenLiveEvents), ctx, handle)
+}
+
+// NotifyLiveEvent mocks base method.
+func (m *MockRepository) NotifyLiveEvent(ctx context.Context, event *model.LiveEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyLiveEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyLiveEvent indicates an expected call of NotifyLiveEvent.
+func (mr *MockRepositoryMockRecorder) NotifyLiveEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyLiveEvent", reflect.TypeOf((*MockRepository)(nil).NotifyLiveEvent), ctx, event)
+}
+
+// RecordGasSpend mocks base method.
+func (m *MockRepository) RecordGasSpend(ctx context.Context, spend *model.GasSpend) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordGasSpend", ctx, spend)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordGasSpend indicates an expected call of RecordGasSpend.
+func (mr *MockRepositoryMockRecorder) RecordGasSpend(ctx, spend any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordGasSpend", reflect.TypeOf((*MockRepository)(nil).RecordGasSpend), ctx, spend)
+}
+
+// RecordNotification mocks base method.
+func (m *MockRepository) RecordNotification(ctx context.Context, address, kind string, period time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordNotification", ctx, address, kind, period)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordNotification indicates an expected call of RecordNotification.
+func (mr *MockRepositoryMockRecorder) RecordNotification(ctx, address, kind, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordNotification", reflect.TypeOf((*MockRepository)(nil).RecordNotification), ctx, address, kind, period)
+}
+
+// RecordSentSettlementTx mocks base method.
+func (m *MockRepository) RecordSentSettlementTx(ctx context.Context, tx *model.SettlementTx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSentSettlementTx", ctx, tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSentSettlementTx indicates an expected call of RecordSentSettlementTx.
+func (mr *MockRepositoryMockRecorder) RecordSentSettlementTx(ctx, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSentSettlementTx", reflect.TypeOf((*MockRepository)(nil).RecordSentSettlementTx), ctx, tx)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockRepository) ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) ReleaseIdempotencyKey(ctx, scope, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).ReleaseIdempotencyKey), ctx, scope, key)
+}
+
+// ReplaceSettlementTx mocks base method.
+func (m *MockRepository) ReplaceSettlementTx(ctx context.Context, replaced int, tx *model.SettlementTx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceSettlementTx", ctx, replaced, tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceSettlementTx indicates an expected call of ReplaceSettlementTx.
+func (mr *MockRepositoryMockRecorder) ReplaceSettlementTx(ctx, replaced, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceSettlementTx", reflect.TypeOf((*MockRepository)(nil).ReplaceSettlementTx), ctx, replaced, tx)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockRepository) ReserveIdempotencyKey(ctx context.Context, scope, key, requestHash string, expiredBefore time.Time) (bool, string, *model.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, scope, key, requestHash, expiredBefore)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(*model.IdempotentResponse)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) ReserveIdempotencyKey(ctx, scope, key, requestHash, expiredBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).ReserveIdempotencyKey), ctx, scope, key, requestHash, expiredBefore)
+}
+
+// ReserveSettlementTx mocks base method.
+func (m *MockRepository) ReserveSettlementTx(ctx context.Context, tx *model.SettlementTx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveSettlementTx", ctx, tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReserveSettlementTx indicates an expected call of ReserveSettlementTx.
+func (mr *MockRepositoryMockRecorder) ReserveSettlementTx(ctx, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSettlementTx", reflect.TypeOf((*MockRepository)(nil).ReserveSettlementTx), ctx, tx)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockRepository) RevokeAPIKey(ctx context.Context, prefix string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, prefix)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockRepositoryMockRecorder) RevokeAPIKey(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockRepository)(nil).RevokeAPIKey), ctx, prefix)
+}
+
+// SetAllowance mocks base method.
+func (m *MockRepository) SetAllowance(ctx context.Context, allowance *model.Allowance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAllowance", ctx, allowance)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAllowance indicates an expected call of SetAllowance.
+func (mr *MockRepositoryMockRecorder) SetAllowance(ctx, allowance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllowance", reflect.TypeOf((*MockRepository)(nil).SetAllowance), ctx, allowance)
+}
+
+// SetPointClaimLeaf mocks base method.
+func (m *MockRepository) SetPointClaimLeaf(ctx context.Context, id int, leaf string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPointClaimLeaf", ctx, id, leaf)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPointClaimLeaf indicates an expected call of SetPointClaimLeaf.
+func (mr *MockRepositoryMockRecorder) SetPointClaimLeaf(ctx, id, leaf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPointClaimLeaf", reflect.TypeOf((*MockRepository)(nil).SetPointClaimLeaf), ctx, id, leaf)
+}
+
+// UpdateSettlementTx mocks base method.
+func (m *MockRepository) UpdateSettlementTx(ctx context.Context, id int, status string, blockNumber, gasUsed int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettlementTx", ctx, id, status, blockNumber, gasUsed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSettlementTx indicates an expected call of UpdateSettlementTx.
+func (mr *MockRepositoryMockRecorder) UpdateSettlementTx(ctx, id, status, blockNumber, gasUsed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettlementTx", reflect.TypeOf((*MockRepository)(nil).UpdateSettlementTx), ctx, id, status, blockNumber, gasUsed)
+}
+
+// UpsertAddressLabel mocks base method.
+func (m *MockRepository) UpsertAddressLabel(ctx context.Context, label *model.AddressLabel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAddressLabel", ctx, label)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertAddressLabel indicates an expected call of UpsertAddressLabel.
+func (mr *MockRepositoryMockRecorder) UpsertAddressLabel(ctx, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAddressLabel", reflect.TypeOf((*MockRepository)(nil).UpsertAddressLabel), ctx, label)
+}
+
+// UpsertPoolReserves mocks base method.
+func (m *MockRepository) UpsertPoolReserves(ctx context.Context, reserves *model.PoolReserves) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPoolReserves", ctx, reserves)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertPoolReserves indicates an expected call of UpsertPoolReserves.
+func (mr *MockRepositoryMockRecorder) UpsertPoolReserves(ctx, reserves any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPoolReserves", reflect.TypeOf((*MockRepository)(nil).UpsertPoolReserves), ctx, reserves)
+}
+
+// UpsertTokenPrice mocks base method.
+func (m *MockRepository) UpsertTokenPrice(ctx context.Context, price *model.TokenPrice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTokenPrice", ctx, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertTokenPrice indicates an expected call of UpsertTokenPrice.
+func (mr *MockRepositoryMockRecorder) UpsertTokenPrice(ctx, price any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTokenPrice", reflect.TypeOf((*MockRepository)(nil).UpsertTokenPrice), ctx, price)
+}
+
+// UpsertUserPoints mocks base method.
+func (m *MockRepository) UpsertUserPoints(ctx context.Context, address string, point model.Decimal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserPoints", ctx, address, point)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserPoints indicates an expected call of UpsertUserPoints.
+func (mr *MockRepositoryMockRecorder) UpsertUserPoints(ctx, address, point any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserPoints", reflect.TypeOf((*MockRepository)(nil).UpsertUserPoints), ctx, address, point)
+}
+
+// UpsertUserProfile mocks base method.
+func (m *MockRepository) UpsertUserProfile(ctx context.Context, profile *model.UserProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserProfile", ctx, profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserProfile indicates an expected call of UpsertUserProfile.
+func (mr *MockRepositoryMockRecorder) UpsertUserProfile(ctx, profile any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserProfile", reflect.TypeOf((*MockRepository)(nil).UpsertUserProfile), ctx, profile)
+}
+
+// WithTx mocks base method.
+func (m *MockRepository) WithTx(tx pg.PgxTx) repository.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryMockRecorder) WithTx(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), tx)
 }
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+)
+
+var getWeeklySummariesQuery = queries.Add("GetWeeklySummaries", `
+	SELECT p.address, p.email, COALESCE(u.total_points, 0), pts.earned, s.swap_count, s.swap_volume
+	FROM user_profiles p
+	LEFT JOIN users u ON u.address = p.address AND u.project_id = $4
+	CROSS JOIN LATERAL (
+		SELECT COALESCE(SUM(points), 0) AS earned
+		FROM points_history
+		WHERE account = p.address AND project_id = $4 AND created_at >= $1 AND created_at < $2
+	) pts
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS swap_count, COALESCE(SUM(usd_value), 0) AS swap_volume
+		FROM swap_history
+		WHERE account = p.address AND created_at >= $1 AND created_at < $2
+	) s
+	WHERE p.email <> '' AND (p.notifications->>'weekly_summary')::boolean
+		AND NOT EXISTS (
+			SELECT 1 FROM notification_deliveries d
+			WHERE d.address = p.address AND d.kind = $3 AND d.period = $1
+		)
+	ORDER BY p.address
+`)
+
+// GetWeeklySummaries retrieves the activity between weekStart and weekEnd of every user who opted into
+// weekly summaries, has an email and was not sent the summary of that week yet, ordered by address.
+func (r *repository) GetWeeklySummaries(ctx context.Context, weekStart, weekEnd time.Time) ([]model.WeeklySummary, error) {
+	rows, err := r.db.Query(ctx, getWeeklySummariesQuery, weekStart, weekEnd, model.NotificationWeeklySummary, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly summaries: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var summaries []model.WeeklySummary
+	for rows.Next() {
+		summary := model.WeeklySummary{WeekStart: weekStart}
+		if err := rows.Scan(&summary.Address, &summary.Email, &summary.TotalPoints, &summary.PointsEarned, &summary.SwapCount, &summary.SwapVolume); err != nil {
+			return nil, fmt.Errorf("failed to scan weekly summary: %w", dbError(err))
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	return summaries, nil
+}
+
+var getBigSwapAlertsQuery = queries.Add("GetBigSwapAlerts", `
+	SELECT p.address, p.email, s.id, s.network, s.token, s.account, s.transaction_hash, s.usd_value, s.counted_usd_value, s.last_updated, s.created_at
+	FROM user_profiles p
+	JOIN swap_history s ON s.account = p.address
+	WHERE p.email <> '' AND (p.notifications->>'big_swaps')::boolean
+		AND s.usd_value >= $1
+		AND s.created_at > COALESCE((
+			SELECT MAX(d.period) FROM notification_deliveries d
+			WHERE d.address = p.address AND d.kind = $2
+		), p.updated_at)
+	ORDER BY p.address, s.created_at, s.id
+`)
+
+// GetBigSwapAlerts retrieves, for every user who opted into big-swap alerts and has an email, their swaps
+// of at least minUSD since their last alert, or since they last saved their profile when never alerted.
+func (r *repository) GetBigSwapAlerts(ctx context.Context, minUSD model.Decimal) ([]model.BigSwapAlert, error) {
+	rows, err := r.db.Query(ctx, getBigSwapAlertsQuery, minUSD, model.NotificationBigSwaps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get big swap alerts: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var alerts []model.BigSwapAlert
+	for rows.Next() {
+		var (
+			address, email string
+			swap           model.SwapHistory
+		)
+		if err := rows.Scan(&address, &email, &swap.ID, &swap.Network, &swap.Token, &swap.Account,
+			&swap.TransactionHash, &swap.UsdValue, &swap.CountedUsdValue, &swap.LastUpdated, &swap.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan big swap: %w", dbError(err))
+		}
+		if len(alerts) == 0 || alerts[len(alerts)-1].Address != address {
+			alerts = append(alerts, model.BigSwapAlert{Address: address, Email: email})
+		}
+		alerts[len(alerts)-1].Swaps = append(alerts[len(alerts)-1].Swaps, swap)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	return alerts, nil
+}
+
+var recordNotificationQuery = queries.Add("RecordNotification", `
+	INSERT INTO notification_deliveries (address, kind, period)
+	VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING
+`)
+
+// RecordNotification records that the notification of a kind covering period was sent to a user.
+func (r *repository) RecordNotification(ctx context.Context, address, kind string, period time.Time) error {
+	if _, err := r.db.Exec(ctx, recordNotificationQuery, address, kind, period); err != nil {
+		return fmt.Errorf("failed to record notification: %w", dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetBigSwapAlerts tests that consecutive swaps of a user are grouped into one alert.
+func TestGetBigSwapAlerts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+	minUSD := model.NewDecimalFromFloat(10000)
+
+	rows := []struct {
+		address string
+		swapID  int
+	}{
+		{address: "0xa", swapID: 1},
+		{address: "0xa", swapID: 2},
+		{address: "0xb", swapID: 3},
+	}
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), minUSD, model.NotificationBigSwaps).Return(mockRows, nil)
+	for _, row := range rows {
+		row := row
+		mockRows.EXPECT().Next().Return(true)
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*string)) = row.address
+			*(dest[1].(*string)) = row.address + "@example.com"
+			*(dest[2].(*int)) = row.swapID
+			return nil
+		})
+	}
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	alerts, err := repo.GetBigSwapAlerts(ctx, minUSD)
+
+	assert.NoError(t, err)
+	if assert.Len(t, alerts, 2) {
+		assert.Equal(t, "0xa@example.com", alerts[0].Email)
+		assert.Equal(t, []model.SwapHistory{{ID: 1}, {ID: 2}}, alerts[0].Swaps)
+		assert.Equal(t, "0xb", alerts[1].Address)
+		assert.Equal(t, []model.SwapHistory{{ID: 3}}, alerts[1].Swaps)
+	}
+}
@@ -2,65 +2,210 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
 )
 
-// CreatePointsHistory inserts a new PointsHistory record into the database.
-func (r *repository) CreatePointsHistory(ctx context.Context, pointsHistory *model.PointsHistory) error {
-	const query = `
-		INSERT INTO points_history (token, account, points, description)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at
-	`
+var createPointsHistoryQuery = queries.Add("CreatePointsHistory", `
+	WITH onboarded AS (
+		INSERT INTO points_onboarding (account, project_id)
+		SELECT $3::char(42), $7::int WHERE $5::varchar = 'onboarding_task'
+		ON CONFLICT DO NOTHING
+		RETURNING account
+	)
+	INSERT INTO points_history (network, token, account, points, description, metadata, project_id)
+	SELECT $1::varchar, $2::char(42), $3, $4::numeric, $5, $6::jsonb, $7
+	WHERE $5 <> 'onboarding_task' OR EXISTS (SELECT 1 FROM onboarded)
+	RETURNING id, created_at
+`)
 
+// CreatePointsHistory inserts a new PointsHistory record into the database. A second onboarding
+// award of an account, kept unique by points_onboarding as points_history is partitioned, is not
+// inserted and leaves the ID zero.
+func (r *repository) CreatePointsHistory(ctx context.Context, pointsHistory *model.PointsHistory) error {
 	err := r.db.QueryRow(
 		ctx,
-		query,
+		createPointsHistoryQuery,
+		pointsHistory.Network,
 		pointsHistory.Token,
 		pointsHistory.Account,
 		pointsHistory.Points,
 		pointsHistory.Description,
+		pointsHistory.Metadata,
+		r.project,
 	).Scan(&pointsHistory.ID, &pointsHistory.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create points history record: %w", err)
+		return fmt.Errorf("failed to create points history record: %w", dbError(err))
 	}
 
 	return nil
 }
 
+var isOnboardingTaskCompletedQuery = queries.Add("IsOnboardingTaskCompleted", `
+	SELECT EXISTS (SELECT 1 FROM points_onboarding WHERE account = $1 AND project_id = $2)
+`)
+
 // IsOnboardingTaskCompleted checks if the onboarding task is completed for the specified account.
+// It reads the onboarding awards, which outlive the points history dropped by the retention.
 func (r *repository) IsOnboardingTaskCompleted(ctx context.Context, account string) (bool, error) {
-	const (
-		description = "onboarding_task"
-		query       = `
-			SELECT COUNT(*)
-			FROM points_history
-			WHERE account = $1 AND description = $2
-		`
-	)
-
-	var count int
-	if err := r.db.QueryRow(ctx, query, account, description).Scan(&count); err != nil {
-		return false, fmt.Errorf("failed to retrieve points history records: %w", err)
+	var completed bool
+	if err := r.db.QueryRow(ctx, isOnboardingTaskCompletedQuery, account, r.project).Scan(&completed); err != nil {
+		return false, fmt.Errorf("failed to retrieve onboarding award: %w", dbError(err))
 	}
 
-	return count > 0, nil
+	return completed, nil
 }
 
+var getPointsHistoryQuery = queries.Add("GetPointsHistory", `
+	SELECT id, token, account, points, description, metadata, created_at
+	FROM points_history
+	WHERE account = $1 AND token = $2 AND project_id = $3
+	ORDER BY created_at DESC
+`)
+
 // GetPointsHistory retrieves the points history for the specified account and token.
 func (r *repository) GetPointsHistory(ctx context.Context, account, token string) ([]model.PointsHistory, error) {
-	const query = `
-		SELECT id, token, account, points, description, created_at
-		FROM points_history
-		WHERE account = $1 AND token = $2
-		ORDER BY created_at DESC
-	`
-
-	rows, err := r.db.Query(ctx, query, account, token)
+	rows, err := r.db.Query(ctx, getPointsHistoryQuery, account, token, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query points history: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var histories []model.PointsHistory
+	for rows.Next() {
+		var ph model.PointsHistory
+		if err := rows.Scan(
+			&ph.ID,
+			&ph.Token,
+			&ph.Account,
+			&ph.Points,
+			&ph.Description,
+			&ph.Metadata,
+			&ph.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan points history row: %w", dbError(err))
+		}
+		histories = append(histories, ph)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate through points history rows: %w", dbError(err))
+	}
+
+	return histories, nil
+}
+
+var getPointsHistoryByTokensQuery = queries.Add("GetPointsHistoryByTokens", `
+	SELECT id, network, token, account, points, description, metadata, created_at
+	FROM points_history
+	WHERE account = $1 AND token = ANY($2) AND ($3 = '' OR network = $3) AND project_id = $4
+	ORDER BY created_at DESC
+`)
+
+// GetPointsHistoryByTokens retrieves the points history for the specified account and tokens in
+// one query, on a single network unless network is empty.
+func (r *repository) GetPointsHistoryByTokens(ctx context.Context, account string, tokens []string, network string) ([]model.PointsHistory, error) {
+	rows, err := r.db.Query(ctx, getPointsHistoryByTokensQuery, account, tokens, network, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query points history: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var histories []model.PointsHistory
+	for rows.Next() {
+		var ph model.PointsHistory
+		if err := rows.Scan(
+			&ph.ID,
+			&ph.Network,
+			&ph.Token,
+			&ph.Account,
+			&ph.Points,
+			&ph.Description,
+			&ph.Metadata,
+			&ph.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan points history row: %w", dbError(err))
+		}
+		histories = append(histories, ph)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate through points history rows: %w", dbError(err))
+	}
+
+	return histories, nil
+}
+
+var getPointsHistoryPageQuery = queries.Add("GetPointsHistoryPage", `
+	SELECT id, network, token, account, points, description, metadata, created_at
+	FROM points_history
+	WHERE account = $1 AND token = $2 AND project_id = $6
+		AND ($3::timestamptz IS NULL OR (created_at, id) < ($3::timestamptz, $4::int))
+	ORDER BY created_at DESC, id DESC
+	LIMIT $5
+`)
+
+// GetPointsHistoryPage retrieves one page of points history for the specified account and token, newest first.
+func (r *repository) GetPointsHistoryPage(ctx context.Context, account, token, cursor string, limit int) ([]model.PointsHistory, string, error) {
+	afterTime, afterID, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := r.db.Query(ctx, getPointsHistoryPageQuery, account, token, afterTime, afterID, limit+1, r.project)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query points history page: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	histories := make([]model.PointsHistory, 0, limit+1)
+	for rows.Next() {
+		var ph model.PointsHistory
+		if err := rows.Scan(
+			&ph.ID,
+			&ph.Network,
+			&ph.Token,
+			&ph.Account,
+			&ph.Points,
+			&ph.Description,
+			&ph.Metadata,
+			&ph.CreatedAt,
+		); err != nil {
+			return nil, "", fmt.Errorf("failed to scan points history row: %w", dbError(err))
+		}
+		histories = append(histories, ph)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to iterate through points history rows: %w", dbError(err))
+	}
+
+	histories, next := nextPage(histories, limit, func(ph model.PointsHistory) string {
+		return EncodeTimeCursor(ph.CreatedAt, ph.ID)
+	})
+
+	return histories, next, nil
+}
+
+var getPointsHistoryByNetworkQuery = queries.Add("GetPointsHistoryByNetwork", `
+	SELECT id, network, token, account, points, description, metadata, created_at
+	FROM points_history
+	WHERE account = $1 AND token = $2 AND network = $3 AND project_id = $4
+	ORDER BY created_at DESC
+`)
+
+// GetPointsHistoryByNetwork retrieves the points history for the specified account and token on a single network.
+func (r *repository) GetPointsHistoryByNetwork(ctx context.Context, account, token, network string) ([]model.PointsHistory, error) {
+	rows, err := r.db.Query(ctx, getPointsHistoryByNetworkQuery, account, token, network, r.project)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query points history: %w", err)
+		return nil, fmt.Errorf("failed to query points history: %w", dbError(err))
 	}
 	defer rows.Close()
 
@@ -69,19 +214,21 @@ func (r *repository) GetPointsHistory(ctx context.Context, account, token string
 		var ph model.PointsHistory
 		if err := rows.Scan(
 			&ph.ID,
+			&ph.Network,
 			&ph.Token,
 			&ph.Account,
 			&ph.Points,
 			&ph.Description,
+			&ph.Metadata,
 			&ph.CreatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan points history row: %w", err)
+			return nil, fmt.Errorf("failed to scan points history row: %w", dbError(err))
 		}
 		histories = append(histories, ph)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate through points history rows: %w", err)
+		return nil, fmt.Errorf("failed to iterate through points history rows: %w", dbError(err))
 	}
 
 	return histories, nil
@@ -10,6 +10,7 @@ import (
 	"hw/internal/repository"
 	pgMock "hw/pkg/pg/mocks"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -25,19 +26,23 @@ func TestCreatePointsHistory_Success(t *testing.T) {
 
 	ctx := context.Background()
 	pointsHistory := &model.PointsHistory{
+		Network:     "mainnet",
 		Token:       "token123",
 		Account:     "account123",
-		Points:      100.5,
+		Points:      model.NewDecimalFromFloat(100.5),
 		Description: "Test description",
 	}
 
 	mockDB.EXPECT().QueryRow(
 		ctx,
 		gomock.Any(),
+		pointsHistory.Network,
 		pointsHistory.Token,
 		pointsHistory.Account,
 		pointsHistory.Points,
 		pointsHistory.Description,
+		pointsHistory.Metadata,
+		model.DefaultProjectID,
 	).Return(mockRow)
 
 	expectedID := 1
@@ -58,6 +63,33 @@ func TestCreatePointsHistory_Success(t *testing.T) {
 	assert.Equal(t, expectedCreatedAt, pointsHistory.CreatedAt)
 }
 
+// TestCreatePointsHistory_Conflict tests that a record skipped by a unique constraint leaves the ID zero.
+func TestCreatePointsHistory_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	pointsHistory := &model.PointsHistory{
+		Network:     "mainnet",
+		Token:       "token123",
+		Account:     "account123",
+		Points:      model.NewDecimalFromFloat(300),
+		Description: "onboarding_task",
+	}
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+	err := repo.CreatePointsHistory(ctx, pointsHistory)
+
+	assert.NoError(t, err)
+	assert.Zero(t, pointsHistory.ID)
+}
+
 // TestIsOnboardingTaskCompleted_Success tests the scenario where the onboarding task is completed.
 func TestIsOnboardingTaskCompleted_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -69,19 +101,17 @@ func TestIsOnboardingTaskCompleted_Success(t *testing.T) {
 
 	ctx := context.Background()
 	account := "account123"
-	description := "onboarding_task"
 	mockDB.EXPECT().QueryRow(
 		ctx,
-		gomock.Any(),
+		pgMock.Query("IsOnboardingTaskCompleted"),
 		account,
-		description,
 	).Return(mockRow)
 
-	var count int
+	var completed bool
 	mockRow.EXPECT().Scan(
-		gomock.AssignableToTypeOf(&count),
+		gomock.AssignableToTypeOf(&completed),
 	).DoAndReturn(func(dest ...any) error {
-		*(dest[0].(*int)) = 1
+		*(dest[0].(*bool)) = true
 		return nil
 	})
 
@@ -102,19 +132,17 @@ func TestIsOnboardingTaskCompleted_NotFound(t *testing.T) {
 
 	ctx := context.Background()
 	account := "account123"
-	description := "onboarding_task"
 	mockDB.EXPECT().QueryRow(
 		ctx,
-		gomock.Any(),
+		pgMock.Query("IsOnboardingTaskCompleted"),
 		account,
-		description,
 	).Return(mockRow)
 
-	var count int
+	var completed bool
 	mockRow.EXPECT().Scan(
-		gomock.AssignableToTypeOf(&count),
+		gomock.AssignableToTypeOf(&completed),
 	).DoAndReturn(func(dest ...any) error {
-		*(dest[0].(*int)) = 0
+		*(dest[0].(*bool)) = false
 		return nil
 	})
 
@@ -142,7 +170,6 @@ func TestIsOnboardingTaskCompleted_QueryError(t *testing.T) {
 		ctx,
 		gomock.Any(),
 		account,
-		"onboarding_task",
 	).Return(nil).DoAndReturn(func(ctx context.Context, query string, args ...interface{}) *pgMock.MockPgxRows {
 		// When QueryRow returns an error, Scan method should return the error
 		mockRow := pgMock.NewMockPgxRows(ctrl)
@@ -156,7 +183,7 @@ func TestIsOnboardingTaskCompleted_QueryError(t *testing.T) {
 	// Assert the results
 	assert.Error(t, err)
 	assert.False(t, completed)
-	assert.Contains(t, err.Error(), "failed to retrieve points history records:")
+	assert.Contains(t, err.Error(), "failed to retrieve onboarding award:")
 	assert.Contains(t, err.Error(), expectedErr.Error())
 }
 
@@ -174,7 +201,7 @@ func TestGetPointsHistory_Success(t *testing.T) {
 	token := "token123"
 
 	// Set expected database behavior
-	mockDB.EXPECT().Query(ctx, gomock.Any(), account, token).Return(mockRows, nil)
+	mockDB.EXPECT().Query(ctx, gomock.Any(), account, token, model.DefaultProjectID).Return(mockRows, nil)
 
 	// Simulate row data
 	firstCall := mockRows.EXPECT().Next().Return(true)
@@ -185,8 +212,9 @@ func TestGetPointsHistory_Success(t *testing.T) {
 		ID:          1,
 		Token:       token,
 		Account:     account,
-		Points:      100.5,
+		Points:      model.NewDecimalFromFloat(100.5),
 		Description: "Test description",
+		Metadata:    &model.PointsMetadata{Campaign: "og", TransactionHash: "0xabc", BlockNumber: 20933200},
 		CreatedAt:   time.Now(),
 	}
 
@@ -197,13 +225,15 @@ func TestGetPointsHistory_Success(t *testing.T) {
 		gomock.Any(),
 		gomock.Any(),
 		gomock.Any(),
+		gomock.Any(),
 	).DoAndReturn(func(dest ...any) error {
 		*(dest[0].(*int)) = expectedPH.ID
 		*(dest[1].(*string)) = expectedPH.Token
 		*(dest[2].(*string)) = expectedPH.Account
-		*(dest[3].(*float64)) = expectedPH.Points
+		*(dest[3].(*model.Decimal)) = expectedPH.Points
 		*(dest[4].(*string)) = expectedPH.Description
-		*(dest[5].(*time.Time)) = expectedPH.CreatedAt
+		*(dest[5].(**model.PointsMetadata)) = expectedPH.Metadata
+		*(dest[6].(*time.Time)) = expectedPH.CreatedAt
 		return nil
 	})
 
@@ -233,7 +263,7 @@ func TestGetPointsHistory_QueryError(t *testing.T) {
 	expectedErr := errors.New("query error")
 
 	// Mock Query method to return error
-	mockDB.EXPECT().Query(ctx, gomock.Any(), account, token).Return(nil, expectedErr)
+	mockDB.EXPECT().Query(ctx, gomock.Any(), account, token, model.DefaultProjectID).Return(nil, expectedErr)
 
 	// Call the method under test
 	histories, err := repo.GetPointsHistory(ctx, account, token)
@@ -245,6 +275,44 @@ func TestGetPointsHistory_QueryError(t *testing.T) {
 	assert.Contains(t, err.Error(), expectedErr.Error())
 }
 
+// TestGetPointsHistoryByTokens tests that the points history of several tokens is read in one query.
+func TestGetPointsHistoryByTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	account := "account123"
+	tokens := []string{"tokenA", "tokenB"}
+	expected := []model.PointsHistory{
+		{ID: 2, Network: "mainnet", Token: "tokenB", Account: account, Points: model.NewDecimalFromFloat(5), Description: "swap_task", CreatedAt: time.Now()},
+		{ID: 1, Network: "mainnet", Token: "tokenA", Account: account, Points: model.NewDecimalFromFloat(10), Description: "onboarding_task", CreatedAt: time.Now().Add(-time.Hour)},
+	}
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetPointsHistoryByTokens"), account, tokens, "", model.DefaultProjectID).Return(mockRows, nil)
+	for _, ph := range expected {
+		mockRows.EXPECT().Next().Return(true)
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+			*(dest[0].(*int)) = ph.ID
+			*(dest[1].(*string)) = ph.Network
+			*(dest[2].(*string)) = ph.Token
+			*(dest[3].(*string)) = ph.Account
+			*(dest[4].(*model.Decimal)) = ph.Points
+			*(dest[5].(*string)) = ph.Description
+			*(dest[7].(*time.Time)) = ph.CreatedAt
+			return nil
+		})
+	}
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	histories, err := repo.GetPointsHistoryByTokens(ctx, account, tokens, "")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, histories)
+}
+
 // TestGetPointsHistory_ScanError tests the scenario where there is a scan error.
 func TestGetPointsHistory_ScanError(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -259,7 +327,7 @@ func TestGetPointsHistory_ScanError(t *testing.T) {
 	token := "token123"
 	expectedErr := errors.New("scan error")
 
-	mockDB.EXPECT().Query(ctx, gomock.Any(), account, token).Return(mockRows, nil)
+	mockDB.EXPECT().Query(ctx, gomock.Any(), account, token, model.DefaultProjectID).Return(mockRows, nil)
 
 	mockRows.EXPECT().Next().Return(true)
 	mockRows.EXPECT().Scan(
@@ -269,6 +337,7 @@ func TestGetPointsHistory_ScanError(t *testing.T) {
 		gomock.Any(),
 		gomock.Any(),
 		gomock.Any(),
+		gomock.Any(),
 	).Return(expectedErr)
 	mockRows.EXPECT().Close()
 
@@ -296,7 +365,7 @@ func TestGetPointsHistory_RowsError(t *testing.T) {
 	token := "token123"
 	expectedErr := errors.New("rows error")
 
-	mockDB.EXPECT().Query(ctx, gomock.Any(), account, token).Return(mockRows, nil)
+	mockDB.EXPECT().Query(ctx, gomock.Any(), account, token, model.DefaultProjectID).Return(mockRows, nil)
 
 	mockRows.EXPECT().Next().Return(false)
 	mockRows.EXPECT().Err().Return(expectedErr)
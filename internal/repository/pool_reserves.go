@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var upsertPoolReservesQuery = queries.Add("UpsertPoolReserves", `
+	INSERT INTO pool_reserves (network, pool, block_number, log_index, reserve0, reserve1, tvl_usd, block_time)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (pool, network, block_number) DO UPDATE SET
+		log_index = EXCLUDED.log_index,
+		reserve0 = EXCLUDED.reserve0,
+		reserve1 = EXCLUDED.reserve1,
+		tvl_usd = EXCLUDED.tvl_usd
+	WHERE pool_reserves.log_index <= EXCLUDED.log_index
+`)
+
+// UpsertPoolReserves records the reserves of a pool at a block, keeping the snapshot of the
+// latest Sync event of the block.
+func (r *repository) UpsertPoolReserves(ctx context.Context, reserves *model.PoolReserves) error {
+	_, err := r.db.Exec(
+		ctx,
+		upsertPoolReservesQuery,
+		reserves.Network,
+		reserves.Pool,
+		reserves.BlockNumber,
+		reserves.LogIndex,
+		reserves.Reserve0,
+		reserves.Reserve1,
+		reserves.TVLUsd,
+		reserves.BlockTime,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert pool reserves: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var getLatestPoolReservesQuery = queries.Add("GetLatestPoolReserves", `
+	SELECT network, pool, block_number, log_index, reserve0, reserve1, tvl_usd, block_time
+	FROM pool_reserves
+	WHERE pool = $1 AND network = $2
+	ORDER BY block_number DESC
+	LIMIT 1
+`)
+
+// GetLatestPoolReserves retrieves the latest reserves snapshot of a pool.
+func (r *repository) GetLatestPoolReserves(ctx context.Context, pool, network string) (*model.PoolReserves, error) {
+	reserves := &model.PoolReserves{}
+	err := r.db.QueryRow(ctx, getLatestPoolReservesQuery, pool, network).Scan(
+		&reserves.Network,
+		&reserves.Pool,
+		&reserves.BlockNumber,
+		&reserves.LogIndex,
+		&reserves.Reserve0,
+		&reserves.Reserve1,
+		&reserves.TVLUsd,
+		&reserves.BlockTime,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrPoolReservesNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve pool reserves: %w", dbError(err))
+	}
+
+	return reserves, nil
+}
@@ -0,0 +1,112 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestUpsertPoolReserves_Success tests that a reserves snapshot is written with its TVL.
+func TestUpsertPoolReserves_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	tvl := model.NewDecimalFromFloat(5200000)
+	reserves := &model.PoolReserves{
+		Network:     "mainnet",
+		Pool:        "poolABC",
+		BlockNumber: 20933200,
+		LogIndex:    7,
+		Reserve0:    model.NewDecimalFromFloat(2600000000000),
+		Reserve1:    model.NewDecimalFromFloat(1000000000000000000000),
+		TVLUsd:      &tvl,
+		BlockTime:   time.Unix(1727740800, 0),
+	}
+
+	mockDB.EXPECT().
+		Exec(ctx, gomock.Any(), "mainnet", "poolABC", int64(20933200), uint(7), reserves.Reserve0, reserves.Reserve1, &tvl, reserves.BlockTime).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := repo.UpsertPoolReserves(ctx, reserves)
+
+	assert.NoError(t, err)
+}
+
+// TestUpsertPoolReserves_Failure tests the failure scenario when recording reserves.
+func TestUpsertPoolReserves_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().
+		Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pgconn.CommandTag{}, errors.New("exec error"))
+
+	err := repo.UpsertPoolReserves(ctx, &model.PoolReserves{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to upsert pool reserves")
+}
+
+// TestGetLatestPoolReserves tests the lookup of the latest snapshot and the error without one.
+func TestGetLatestPoolReserves(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanErr error
+		wantErr error
+	}{
+		{name: "found"},
+		{name: "not found", scanErr: pgx.ErrNoRows, wantErr: model.ErrPoolReservesNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRow := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+
+			ctx := context.Background()
+
+			mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "poolABC", "mainnet").Return(mockRow)
+			mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(dest ...interface{}) error {
+					if tt.scanErr != nil {
+						return tt.scanErr
+					}
+					*(dest[0].(*string)) = "mainnet"
+					*(dest[1].(*string)) = "poolABC"
+					*(dest[2].(*int64)) = 20933200
+					return nil
+				})
+
+			reserves, err := repo.GetLatestPoolReserves(ctx, "poolABC", "mainnet")
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, reserves)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(20933200), reserves.BlockNumber)
+			assert.Nil(t, reserves.TVLUsd)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+)
+
+var applyPositionChangeQuery = queries.Add("ApplyPositionChange", `
+	WITH inserted AS (
+		INSERT INTO position_changes (network, protocol, kind, contract, account, transaction_hash, log_index, block_number, delta, block_time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (network, transaction_hash, log_index, account) DO NOTHING
+		RETURNING network, protocol, kind, contract, account, delta, block_number, block_time
+	), applied AS (
+		INSERT INTO positions (network, protocol, kind, contract, account, balance, block_number, updated_at)
+		SELECT network, protocol, kind, contract, account, delta, block_number, block_time FROM inserted
+		ON CONFLICT (network, contract, account) DO UPDATE SET
+			balance = positions.balance + EXCLUDED.balance,
+			block_number = GREATEST(positions.block_number, EXCLUDED.block_number),
+			updated_at = GREATEST(positions.updated_at, EXCLUDED.updated_at)
+		RETURNING network, contract, balance
+	), moves AS (
+		SELECT applied.network, applied.contract, applied.balance - inserted.delta AS balance, -1 AS holders
+		FROM applied CROSS JOIN inserted
+		UNION ALL
+		SELECT network, contract, balance, 1 FROM applied
+	)
+	INSERT INTO token_holder_buckets (network, token, magnitude, holders)
+	SELECT network, contract, length(balance::text) - 1, SUM(holders)
+	FROM moves
+	WHERE balance > 0
+	GROUP BY network, contract, length(balance::text) - 1
+	HAVING SUM(holders) <> 0
+	ON CONFLICT (network, token, magnitude) DO UPDATE SET holders = token_holder_buckets.holders + EXCLUDED.holders
+`)
+
+// ApplyPositionChange records a change of a position, once per log and account, and applies it to
+// the current balance of the position and to the holder buckets of the contract.
+func (r *repository) ApplyPositionChange(ctx context.Context, change *model.PositionChange) error {
+	_, err := r.db.Exec(
+		ctx,
+		applyPositionChangeQuery,
+		change.Network,
+		change.Protocol,
+		change.Kind,
+		change.Contract,
+		change.Account,
+		change.TransactionHash,
+		change.LogIndex,
+		change.BlockNumber,
+		change.Delta,
+		change.BlockTime,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to apply position change: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var getPositionChangesQuery = queries.Add("GetPositionChanges", `
+	SELECT protocol, kind, account, transaction_hash, log_index, block_number, delta, block_time
+	FROM position_changes
+	WHERE contract = $1 AND network = $2 AND block_time < $3
+	ORDER BY block_number, log_index
+`)
+
+// GetPositionChanges retrieves the position changes of a contract before the given time, in chain order.
+func (r *repository) GetPositionChanges(ctx context.Context, contract, network string, before time.Time) ([]model.PositionChange, error) {
+	rows, err := r.db.Query(ctx, getPositionChangesQuery, contract, network, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve position changes: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	changes := []model.PositionChange{}
+	for rows.Next() {
+		change := model.PositionChange{Network: network, Contract: contract}
+		if err := rows.Scan(&change.Protocol, &change.Kind, &change.Account, &change.TransactionHash, &change.LogIndex, &change.BlockNumber, &change.Delta, &change.BlockTime); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return changes, nil
+}
+
+var getAccountPositionsQuery = queries.Add("GetAccountPositions", `
+	SELECT network, protocol, kind, contract, account, balance, block_number, updated_at
+	FROM positions
+	WHERE account = $1 AND ($2 = '' OR network = $2) AND balance > 0
+	ORDER BY network, kind, contract
+`)
+
+// GetAccountPositions retrieves the open positions of an account, on every network when network is empty.
+func (r *repository) GetAccountPositions(ctx context.Context, account, network string) ([]model.Position, error) {
+	rows, err := r.db.Query(ctx, getAccountPositionsQuery, account, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account positions: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	positions := []model.Position{}
+	for rows.Next() {
+		var position model.Position
+		if err := rows.Scan(&position.Network, &position.Protocol, &position.Kind, &position.Contract, &position.Account, &position.Balance, &position.BlockNumber, &position.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return positions, nil
+}
+
+var getTokenHolderBucketsQuery = queries.Add("GetTokenHolderBuckets", `
+	SELECT magnitude, holders
+	FROM token_holder_buckets
+	WHERE token = $1 AND network = $2 AND holders > 0
+	ORDER BY magnitude
+`)
+
+// GetTokenHolderBuckets retrieves the number of holders of a token by order of magnitude of their balance, smallest first.
+func (r *repository) GetTokenHolderBuckets(ctx context.Context, token, network string) ([]model.HolderBucket, error) {
+	rows, err := r.db.Query(ctx, getTokenHolderBucketsQuery, token, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve token holder buckets: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	buckets := []model.HolderBucket{}
+	for rows.Next() {
+		var bucket model.HolderBucket
+		if err := rows.Scan(&bucket.Magnitude, &bucket.Holders); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return buckets, nil
+}
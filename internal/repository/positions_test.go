@@ -0,0 +1,176 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestApplyPositionChange_Success tests the successful recording of a position change.
+func TestApplyPositionChange_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	change := &model.PositionChange{
+		Network:         "mainnet",
+		Protocol:        "uniswap_v2",
+		Kind:            model.PositionLP,
+		Contract:        "poolABC",
+		Account:         "accountXYZ",
+		TransactionHash: "0xabc",
+		LogIndex:        7,
+		BlockNumber:     20933200,
+		Delta:           model.NewDecimalFromFloat(-500),
+		BlockTime:       time.Unix(1727740800, 0),
+	}
+
+	mockDB.EXPECT().
+		Exec(ctx, gomock.Any(), "mainnet", "uniswap_v2", "lp", "poolABC", "accountXYZ", "0xabc", uint(7), int64(20933200), change.Delta, change.BlockTime).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := repo.ApplyPositionChange(ctx, change)
+
+	assert.NoError(t, err)
+}
+
+// TestGetPositionChanges_Success tests that position changes are read in chain order.
+func TestGetPositionChanges_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	before := time.Unix(1727740800, 0)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "poolABC", "mainnet", before).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "uniswap_v2"
+		*(dest[1].(*string)) = model.PositionLP
+		*(dest[2].(*string)) = "accountXYZ"
+		*(dest[6].(*model.Decimal)) = model.NewDecimalFromFloat(500)
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	changes, err := repo.GetPositionChanges(ctx, "poolABC", "mainnet", before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.PositionChange{{Network: "mainnet", Protocol: "uniswap_v2", Kind: model.PositionLP, Contract: "poolABC", Account: "accountXYZ", Delta: model.NewDecimalFromFloat(500)}}, changes)
+}
+
+// TestGetPositionChanges_Failure tests the failure scenario when retrieving position changes.
+func TestGetPositionChanges_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("query error"))
+
+	_, err := repo.GetPositionChanges(ctx, "poolABC", "mainnet", time.Now())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve position changes")
+}
+
+// TestGetAccountPositions tests that the open positions of an account are read, on every network by default.
+func TestGetAccountPositions(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		rows    int
+		err     error
+		wantErr string
+	}{
+		{name: "every network", rows: 1},
+		{name: "one network", network: "base"},
+		{name: "query error", err: errors.New("query error"), wantErr: "failed to retrieve account positions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRows := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+
+			ctx := context.Background()
+
+			if tt.err != nil {
+				mockDB.EXPECT().Query(ctx, gomock.Any(), "accountXYZ", tt.network).Return(nil, tt.err)
+			} else {
+				mockDB.EXPECT().Query(ctx, gomock.Any(), "accountXYZ", tt.network).Return(mockRows, nil)
+				mockRows.EXPECT().Next().Return(true).Times(tt.rows)
+				mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+					*(dest[0].(*string)) = "mainnet"
+					*(dest[3].(*string)) = "poolABC"
+					*(dest[5].(*model.Decimal)) = model.NewDecimalFromFloat(500)
+					return nil
+				}).Times(tt.rows)
+				mockRows.EXPECT().Next().Return(false)
+				mockRows.EXPECT().Err().Return(nil)
+				mockRows.EXPECT().Close()
+			}
+
+			positions, err := repo.GetAccountPositions(ctx, "accountXYZ", tt.network)
+
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, positions, tt.rows)
+		})
+	}
+}
+
+// TestGetTokenHolderBuckets tests retrieving the holder buckets of a token.
+func TestGetTokenHolderBuckets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetTokenHolderBuckets"), "tokenABC", "mainnet").Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*int)) = 18
+		*(dest[1].(*int64)) = 42
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	buckets, err := repo.GetTokenHolderBuckets(ctx, "tokenABC", "mainnet")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.HolderBucket{{Magnitude: 18, Holders: 42}}, buckets)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "tokenABC", "mainnet").Return(nil, errors.New("query error"))
+	_, err = repo.GetTokenHolderBuckets(ctx, "tokenABC", "mainnet")
+	assert.ErrorContains(t, err, "failed to retrieve token holder buckets")
+}
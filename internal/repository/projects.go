@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var createProjectQuery = queries.Add("CreateProject", `
+	INSERT INTO projects (slug, name) VALUES ($1, $2)
+	RETURNING id, created_at
+`)
+
+// CreateProject creates a project and sets its ID and CreatedAt. It returns model.ErrAlreadyExists
+// when the slug is taken.
+func (r *repository) CreateProject(ctx context.Context, project *model.Project) error {
+	if err := r.db.QueryRow(ctx, createProjectQuery, project.Slug, project.Name).Scan(&project.ID, &project.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create project: %w", dbError(err))
+	}
+	return nil
+}
+
+var getProjectBySlugQuery = queries.Add("GetProjectBySlug", `SELECT id, slug, name, created_at FROM projects WHERE slug = $1`)
+
+// GetProjectBySlug retrieves a project, or model.ErrProjectNotFound.
+func (r *repository) GetProjectBySlug(ctx context.Context, slug string) (*model.Project, error) {
+	var project model.Project
+	err := r.db.QueryRow(ctx, getProjectBySlugQuery, slug).Scan(&project.ID, &project.Slug, &project.Name, &project.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get project: %w", dbError(err))
+	}
+	return &project, nil
+}
+
+var createAPIKeyQuery = queries.Add("CreateAPIKey", `
+	INSERT INTO api_keys (project_id, prefix, key_hash) VALUES ($1, $2, $3)
+	RETURNING id, created_at
+`)
+
+// CreateAPIKey stores the hash of a project's API key and sets the ID and CreatedAt of key.
+func (r *repository) CreateAPIKey(ctx context.Context, key *model.APIKey, keyHash string) error {
+	if err := r.db.QueryRow(ctx, createAPIKeyQuery, key.ProjectID, key.Prefix, keyHash).Scan(&key.ID, &key.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create API key: %w", dbError(err))
+	}
+	return nil
+}
+
+var getProjectByAPIKeyQuery = queries.Add("GetProjectByAPIKey", `
+	SELECT p.id, p.slug, p.name, p.created_at
+	FROM api_keys k
+	JOIN projects p ON p.id = k.project_id
+	WHERE k.key_hash = $1 AND k.revoked_at IS NULL
+`)
+
+// GetProjectByAPIKey retrieves the project of an active API key by the hash of the key. It returns
+// model.ErrInvalidAPIKey when no active key has this hash.
+func (r *repository) GetProjectByAPIKey(ctx context.Context, keyHash string) (*model.Project, error) {
+	var project model.Project
+	err := r.db.QueryRow(ctx, getProjectByAPIKeyQuery, keyHash).Scan(&project.ID, &project.Slug, &project.Name, &project.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to get project of API key: %w", dbError(err))
+	}
+	return &project, nil
+}
+
+var revokeAPIKeyQuery = queries.Add("RevokeAPIKey", `UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE prefix = $1 AND revoked_at IS NULL`)
+
+// RevokeAPIKey revokes the active API key with a prefix, or returns model.ErrAPIKeyNotFound.
+func (r *repository) RevokeAPIKey(ctx context.Context, prefix string) error {
+	tag, err := r.db.Exec(ctx, revokeAPIKeyQuery, prefix)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetProjectByAPIKey tests that an active key resolves to its project and any other key is invalid.
+func TestGetProjectByAPIKey(t *testing.T) {
+	createdAt := time.Date(2024, 10, 24, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		scanErr error
+		want    *model.Project
+		wantErr error
+	}{
+		{name: "active", want: &model.Project{ID: 3, Slug: "acme", Name: "Acme rewards", CreatedAt: createdAt}},
+		{name: "unknown or revoked", scanErr: pgx.ErrNoRows, wantErr: model.ErrInvalidAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRow := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetProjectByAPIKey"), "keyhash").Return(mockRow)
+			mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				if tt.scanErr != nil {
+					return tt.scanErr
+				}
+				*dest[0].(*int) = 3
+				*dest[1].(*string) = "acme"
+				*dest[2].(*string) = "Acme rewards"
+				*dest[3].(*time.Time) = createdAt
+				return nil
+			})
+
+			project, err := repo.GetProjectByAPIKey(ctx, "keyhash")
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, project)
+		})
+	}
+}
+
+// TestRevokeAPIKey tests that revoking an unknown or already revoked key is reported.
+func TestRevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		wantErr error
+	}{
+		{name: "revoked", tag: "UPDATE 1"},
+		{name: "unknown or already revoked", tag: "UPDATE 0", wantErr: model.ErrAPIKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			repo := repository.NewRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().Exec(ctx, pgMock.Query("RevokeAPIKey"), "1a2b3c4d").Return(pgconn.NewCommandTag(tt.tag), nil)
+
+			err := repo.RevokeAPIKey(ctx, "1a2b3c4d")
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
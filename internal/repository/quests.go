@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var advanceQuestDaysQuery = queries.Add("AdvanceQuestDays", `
+	INSERT INTO quest_progress (quest_id, account, progress, streak, last_day, project_id)
+	VALUES ($1, $2, 1, 1, $3, $4)
+	ON CONFLICT (project_id, quest_id, account) DO UPDATE SET
+		progress = quest_progress.progress + 1,
+		streak = CASE WHEN quest_progress.last_day = $3::date - 1 THEN quest_progress.streak + 1 ELSE 1 END,
+		last_day = $3,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE quest_progress.completed_at IS NULL AND (quest_progress.last_day IS NULL OR quest_progress.last_day < $3)
+	RETURNING progress, streak, last_day
+`)
+
+// AdvanceQuestDays counts the UTC day of t towards a day quest of an account, extending its streak
+// when it follows the last day counted. It returns nil, without error, when the day or a later one
+// was counted already or the quest is completed.
+func (r *repository) AdvanceQuestDays(ctx context.Context, questID, account string, t time.Time) (*model.QuestProgress, error) {
+	progress := &model.QuestProgress{QuestID: questID, Account: account}
+	err := r.db.QueryRow(ctx, advanceQuestDaysQuery, questID, account, rollupDay(t), r.project).Scan(&progress.Progress, &progress.Streak, &progress.LastDay)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance quest days: %w", dbError(err))
+	}
+	return progress, nil
+}
+
+var addQuestVolumeQuery = queries.Add("AddQuestVolume", `
+	INSERT INTO quest_progress (quest_id, account, progress, project_id)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (project_id, quest_id, account) DO UPDATE SET
+		progress = quest_progress.progress + EXCLUDED.progress,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE quest_progress.completed_at IS NULL
+	RETURNING progress
+`)
+
+// AddQuestVolume adds USD to a volume quest of an account. It returns nil, without error, when the
+// quest is completed.
+func (r *repository) AddQuestVolume(ctx context.Context, questID, account string, usd model.Decimal) (*model.QuestProgress, error) {
+	progress := &model.QuestProgress{QuestID: questID, Account: account}
+	err := r.db.QueryRow(ctx, addQuestVolumeQuery, questID, account, usd, r.project).Scan(&progress.Progress)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to add quest volume: %w", dbError(err))
+	}
+	return progress, nil
+}
+
+var completeQuestQuery = queries.Add("CompleteQuest", `
+	UPDATE quest_progress SET completed_at = $3, updated_at = CURRENT_TIMESTAMP
+	WHERE quest_id = $1 AND account = $2 AND project_id = $4 AND completed_at IS NULL
+`)
+
+// CompleteQuest marks a quest of an account completed at t, and reports whether it was not already.
+func (r *repository) CompleteQuest(ctx context.Context, questID, account string, t time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, completeQuestQuery, questID, account, t, r.project)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete quest: %w", dbError(err))
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+var getQuestProgressQuery = queries.Add("GetQuestProgress", `
+	SELECT quest_id, progress, streak, last_day, completed_at
+	FROM quest_progress
+	WHERE account = $1 AND project_id = $2
+	ORDER BY quest_id
+`)
+
+// GetQuestProgress retrieves the progress of an account on every quest it made progress on.
+func (r *repository) GetQuestProgress(ctx context.Context, account string) ([]model.QuestProgress, error) {
+	rows, err := r.db.Query(ctx, getQuestProgressQuery, account, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve quest progress: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	progress := []model.QuestProgress{}
+	for rows.Next() {
+		p := model.QuestProgress{Account: account}
+		if err := rows.Scan(&p.QuestID, &p.Progress, &p.Streak, &p.LastDay, &p.CompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		progress = append(progress, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve quest progress: %w", dbError(err))
+	}
+
+	return progress, nil
+}
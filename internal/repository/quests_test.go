@@ -0,0 +1,105 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestAdvanceQuestDays tests that a quest day is counted on the UTC day of the swap, and that a day
+// counted already is reported as no progress rather than an error.
+func TestAdvanceQuestDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	swapTime := time.Date(2024, 10, 2, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*3600))
+	expectedDay := time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("AdvanceQuestDays"), "swap_5_days", "0xabc", expectedDay, model.DefaultProjectID).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*model.Decimal)) = model.NewDecimalFromFloat(3)
+		*(dest[1].(*int)) = 2
+		*(dest[2].(**time.Time)) = &expectedDay
+		return nil
+	})
+
+	progress, err := repo.AdvanceQuestDays(ctx, "swap_5_days", "0xabc", swapTime)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.QuestProgress{QuestID: "swap_5_days", Account: "0xabc", Progress: model.NewDecimalFromFloat(3), Streak: 2, LastDay: &expectedDay}, progress)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), model.DefaultProjectID).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+	progress, err = repo.AdvanceQuestDays(ctx, "swap_5_days", "0xabc", swapTime)
+
+	assert.NoError(t, err)
+	assert.Nil(t, progress)
+}
+
+// TestCompleteQuest tests that completing a quest reports whether it was not completed already.
+func TestCompleteQuest(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want bool
+	}{
+		{name: "completed", tag: "UPDATE 1", want: true},
+		{name: "already completed", tag: "UPDATE 0", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			repo := repository.NewRepository(mockDB)
+
+			ctx := context.Background()
+			now := time.Date(2024, 10, 3, 12, 0, 0, 0, time.UTC)
+			mockDB.EXPECT().Exec(ctx, pgMock.Query("CompleteQuest"), "swap_5_days", "0xabc", now, model.DefaultProjectID).Return(pgconn.NewCommandTag(tt.tag), nil)
+
+			completed, err := repo.CompleteQuest(ctx, "swap_5_days", "0xabc", now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, completed)
+		})
+	}
+}
+
+// TestGetQuestProgress tests that the progress of an account is read for every quest it advanced.
+func TestGetQuestProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetQuestProgress"), "0xabc").Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*string)) = "volume_10k"
+		*(dest[1].(*model.Decimal)) = model.NewDecimalFromFloat(2500)
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	progress, err := repo.GetQuestProgress(ctx, "0xabc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.QuestProgress{{QuestID: "volume_10k", Account: "0xabc", Progress: model.NewDecimalFromFloat(2500)}}, progress)
+}
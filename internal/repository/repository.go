@@ -20,31 +20,208 @@ type Repository interface {
 	IsOnboardingTaskCompleted(ctx context.Context, account string) (bool, error)
 	// GetPointsHistory retrieves the points history for the specified account and token.
 	GetPointsHistory(ctx context.Context, account, token string) ([]model.PointsHistory, error)
-	// CreateSwapHistory inserts a new swap history record into the database.
+	// GetPointsHistoryByNetwork retrieves the points history for the specified account and token on a single network.
+	GetPointsHistoryByNetwork(ctx context.Context, account, token, network string) ([]model.PointsHistory, error)
+	// GetPointsHistoryByTokens retrieves the points history for the specified account and tokens in one query, on a single network unless network is empty.
+	GetPointsHistoryByTokens(ctx context.Context, account string, tokens []string, network string) ([]model.PointsHistory, error)
+	// GetPointsHistoryPage retrieves one page of points history for the specified account and token, newest first.
+	GetPointsHistoryPage(ctx context.Context, account, token, cursor string, limit int) ([]model.PointsHistory, string, error)
+	// CreateSwapHistory inserts a new swap history record into the database, or returns model.ErrAlreadyExists when the swap of the log was recorded.
 	CreateSwapHistory(ctx context.Context, swapHistory *model.SwapHistory) error
-	// GetSwapTotalUsd retrieves the total USD value of swaps for a given account and token.
-	GetSwapTotalUsd(ctx context.Context, account, token string) (float64, error)
+	// GetSwapTotalUsd retrieves the total USD value and count of swaps for a given account and token.
+	GetSwapTotalUsd(ctx context.Context, account, token string) (model.SwapTotal, error)
+	// FlagRoundTripSwaps flags the swaps of an account through a pool in one transaction as wash trades when there are several.
+	FlagRoundTripSwaps(ctx context.Context, swapHistory *model.SwapHistory) (bool, error)
 	// GetUserSwapSummary retrieves the sum of USD values grouped by token for a given account.
-	GetUserSwapSummary(ctx context.Context, account string) (map[string]float64, error)
+	GetUserSwapSummary(ctx context.Context, account string) (map[string]model.Decimal, error)
+	// GetUserSwapSummaryByNetwork retrieves the sum of USD values grouped by token for a given account on a single network.
+	GetUserSwapSummaryByNetwork(ctx context.Context, account, network string) (map[string]model.Decimal, error)
+	// GetUserPools retrieves a user's swap volume by token with the points awarded for each in one query, on a single
+	// network unless network is empty.
+	GetUserPools(ctx context.Context, account, network string) (map[string]*model.UserPool, error)
+	// GetUserNetworkSummary retrieves a user's swap volume and points grouped by network.
+	GetUserNetworkSummary(ctx context.Context, account string) (map[string]model.NetworkSummary, error)
 	// GetUserSwapSummaryLast7Days retrieves the total USD and percentage of swaps for each user over the past seven days for a specific token.
-	GetUserSwapSummaryLast7Days(ctx context.Context, referenceTime time.Time, token string) ([]model.UserSwapPercentage, error)
+	// The USD of wash trades is reported separately, and left out of the totals when excludeWashTrades is set. Excluded users are left out.
+	GetUserSwapSummaryLast7Days(ctx context.Context, referenceTime time.Time, token string, excludeWashTrades bool, exclude model.Exclusion) ([]model.UserSwapPercentage, error)
+	// GetSwapHistoryPage retrieves one page of swap history for the specified account, newest first.
+	GetSwapHistoryPage(ctx context.Context, account, cursor string, limit int) ([]model.SwapHistory, string, error)
+	// IncrementDailySwapRollup adds a swap to the daily per-user per-pool rollup.
+	IncrementDailySwapRollup(ctx context.Context, swapHistory *model.SwapHistory) error
+	// GetDailyCountedUsd retrieves the USD value of an account's swaps counted towards points on a UTC day.
+	GetDailyCountedUsd(ctx context.Context, account string, t time.Time) (model.Decimal, error)
+	// GetDailyPoolVolumes retrieves the USD value of the swaps through a pool of a network counted towards points, per
+	// account and UTC day within [from, to).
+	GetDailyPoolVolumes(ctx context.Context, network, token string, from, to time.Time) ([]model.DailyVolume, error)
+	// AdvanceQuestDays counts the UTC day of t towards a day quest of an account, or returns nil when it was counted already
+	// or the quest is completed.
+	AdvanceQuestDays(ctx context.Context, questID, account string, t time.Time) (*model.QuestProgress, error)
+	// AddQuestVolume adds USD to a volume quest of an account, or returns nil when the quest is completed.
+	AddQuestVolume(ctx context.Context, questID, account string, usd model.Decimal) (*model.QuestProgress, error)
+	// CompleteQuest marks a quest of an account completed at t, and reports whether it was not already.
+	CompleteQuest(ctx context.Context, questID, account string, t time.Time) (bool, error)
+	// GetQuestProgress retrieves the progress of an account on every quest it made progress on.
+	GetQuestProgress(ctx context.Context, account string) ([]model.QuestProgress, error)
+	// IncrementDailyPointsRollup adds awarded points to the daily per-user per-pool rollup.
+	IncrementDailyPointsRollup(ctx context.Context, pointsHistory *model.PointsHistory) error
+	// EnsureHistoryPartitions creates the missing monthly partitions of a history table from the month of from to the month of to.
+	EnsureHistoryPartitions(ctx context.Context, table string, from, to time.Time) error
+	// GetOldestHistoryTime retrieves the time of the oldest row of a history table, nil when it is empty.
+	GetOldestHistoryTime(ctx context.Context, table string) (*time.Time, error)
+	// GetDefaultPartitionMonths retrieves the months of the rows of a history table in its default partition, oldest first.
+	GetDefaultPartitionMonths(ctx context.Context, table string) ([]time.Time, error)
+	// GetHistoryMonthRows retrieves up to limit rows of the month of a history table with an ID greater than afterID, ordered by ID.
+	GetHistoryMonthRows(ctx context.Context, table string, month time.Time, afterID int64, limit int) ([]model.HistoryRow, error)
+	// DropHistoryMonth adds the rows of the month of a history table to its monthly rollups and removes them, returning their number.
+	DropHistoryMonth(ctx context.Context, table string, month time.Time) (int64, error)
+	// GetPoolVolumeStats retrieves the USD volume, swap count and unique accounts of a pool since the given time.
+	GetPoolVolumeStats(ctx context.Context, token string, since time.Time) (*model.PoolVolumeStats, error)
+	// GetPoolTopTraders retrieves the accounts with the highest USD volume in a pool since the given time.
+	GetPoolTopTraders(ctx context.Context, token string, since time.Time, limit int) ([]model.TraderVolume, error)
+	// UpsertPoolReserves records the reserves of a pool at a block, keeping the latest Sync event of the block.
+	UpsertPoolReserves(ctx context.Context, reserves *model.PoolReserves) error
+	// GetLatestPoolReserves retrieves the latest reserves snapshot of a pool.
+	GetLatestPoolReserves(ctx context.Context, pool, network string) (*model.PoolReserves, error)
+	// ApplyPositionChange records a change of a position, once per log and account, and applies it to the current balance.
+	ApplyPositionChange(ctx context.Context, change *model.PositionChange) error
+	// GetPositionChanges retrieves the position changes of a contract before the given time, in chain order.
+	GetPositionChanges(ctx context.Context, contract, network string, before time.Time) ([]model.PositionChange, error)
+	// GetAccountPositions retrieves the open positions of an account, on every network when network is empty.
+	GetAccountPositions(ctx context.Context, account, network string) ([]model.Position, error)
+	// GetTokenHolderBuckets retrieves the number of holders of a token by order of magnitude of their balance, smallest first.
+	GetTokenHolderBuckets(ctx context.Context, token, network string) ([]model.HolderBucket, error)
+	// UpsertTokenPrice records a price in the minute bucket of a token.
+	UpsertTokenPrice(ctx context.Context, price *model.TokenPrice) error
+	// GetTokenPriceCandles aggregates the minute prices of a token within [from, to) into candles of the given interval.
+	GetTokenPriceCandles(ctx context.Context, token, network string, from, to time.Time, interval time.Duration) ([]model.PriceCandle, error)
+	// GetLatestTokenPrice retrieves the close of the latest minute bucket of a token within [since, at].
+	GetLatestTokenPrice(ctx context.Context, token, network string, since, at time.Time) (*model.TokenPrice, error)
 	// GetTokenByAddress retrieves a token by its address from the database.
 	GetTokenByAddress(ctx context.Context, address string) (*model.Token, error)
+	// GetTokensByAddresses retrieves the tokens of several addresses in one query; unknown addresses are left out.
+	GetTokensByAddresses(ctx context.Context, addresses []string) ([]model.Token, error)
 	// CreateToken inserts a new token into the database.
 	CreateToken(ctx context.Context, token *model.Token) error
+	// ListTokens retrieves one page of tokens ordered by address, optionally filtered by a symbol or name search.
+	ListTokens(ctx context.Context, search, cursor string, limit int) ([]model.TokenVolume, string, error)
+	// GetTokenVolume retrieves a token with its swap volume.
+	GetTokenVolume(ctx context.Context, address string) (*model.TokenVolume, error)
 	// CreateUser inserts a new user into the users table.
 	CreateUser(ctx context.Context, userId string) (*model.User, error)
 	// GetUserByAddress retrieves a user by their address.
 	GetUserByAddress(ctx context.Context, address string) (*model.User, error)
+	// GetUsersByAddresses retrieves the users of several addresses in one query; addresses without a user are left out.
+	GetUsersByAddresses(ctx context.Context, addresses []string) ([]model.User, error)
+	// CreateUsers inserts the users of several addresses in one query and returns those created.
+	CreateUsers(ctx context.Context, addresses []string) ([]model.User, error)
 	// UpsertUserPoints atomically updates a user's total points.
-	UpsertUserPoints(ctx context.Context, address string, point float64) error
-	// GetLeaderboard retrieves the leaderboard.
-	GetLeaderboard(ctx context.Context) ([]model.User, error)
+	UpsertUserPoints(ctx context.Context, address string, point model.Decimal) error
+	// GetLeaderboard retrieves the leaderboard, without the excluded users.
+	GetLeaderboard(ctx context.Context, exclude model.Exclusion) ([]model.User, error)
+	// GetLeaderboardPage retrieves one page of the leaderboard without the excluded users, keyed on (total_points, id).
+	GetLeaderboardPage(ctx context.Context, cursor string, limit int, exclude model.Exclusion) ([]model.User, string, error)
+	// GetUserRank retrieves a user's position on the leaderboard among the users not excluded.
+	GetUserRank(ctx context.Context, address string, exclude model.Exclusion) (*model.LeaderboardRank, error)
+	// ClaimUserPoints marks a user's claimable points as claimed and records the claim under its signature.
+	ClaimUserPoints(ctx context.Context, address, signature string) (*model.PointClaim, error)
+	// SetPointClaimLeaf records the Merkle leaf of a claim.
+	SetPointClaimLeaf(ctx context.Context, id int, leaf string) error
+	// GetPointsSnapshot retrieves every account's total points awarded up to cutoff.
+	GetPointsSnapshot(ctx context.Context, cutoff time.Time) ([]model.PointsBalance, error)
+	// RecordGasSpend records the gas fee paid for a transaction, once per transaction.
+	RecordGasSpend(ctx context.Context, spend *model.GasSpend) error
+	// GetGasLeaderboard retrieves the accounts that paid the most gas on a network, largest fees first.
+	GetGasLeaderboard(ctx context.Context, network string, limit int) ([]model.GasTotal, error)
+	// GetAccountGasTotals retrieves the gas an account paid on each network.
+	GetAccountGasTotals(ctx context.Context, account string) ([]model.GasTotal, error)
+	// SetAllowance sets the allowance of a spender over an owner's tokens, unless a later approval set it already.
+	SetAllowance(ctx context.Context, allowance *model.Allowance) error
+	// GetOwnerAllowances retrieves the outstanding allowances granted by an owner, on every network when network is empty.
+	GetOwnerAllowances(ctx context.Context, owner, network string) ([]model.Allowance, error)
+	// UpsertAddressLabel labels an address, or renames its label.
+	UpsertAddressLabel(ctx context.Context, label *model.AddressLabel) error
+	// DeleteAddressLabel removes a label from an address by an operator.
+	DeleteAddressLabel(ctx context.Context, address, label, deletedBy string) error
+	// GetAddressLabels retrieves the labels of an address, or of every address when address is empty, of one label unless label is empty.
+	GetAddressLabels(ctx context.Context, address, label string) ([]model.AddressLabel, error)
+	// GetTransferBalances retrieves the balance of every holder of a contract at a block, summed from its indexed transfers.
+	GetTransferBalances(ctx context.Context, contract, network string, blockNumber int64) ([]model.HolderBalance, error)
+	// CreateBalanceSnapshot inserts a balance snapshot together with the balance of every holder.
+	CreateBalanceSnapshot(ctx context.Context, snapshot *model.BalanceSnapshot, balances []model.HolderBalance) error
+	// GetLatestBalanceSnapshot retrieves the latest snapshot of a token taken at a block, with the balance of every holder.
+	GetLatestBalanceSnapshot(ctx context.Context, token, network string, blockNumber int64) (*model.BalanceSnapshot, []model.HolderBalance, error)
+	// CreateMerkleDistribution inserts a distribution together with the proof of every address.
+	CreateMerkleDistribution(ctx context.Context, distribution *model.MerkleDistribution, proofs []model.MerkleProof) error
+	// GetLatestMerkleProof retrieves an address's proof in the latest distribution.
+	GetLatestMerkleProof(ctx context.Context, address string) (*model.MerkleProof, error)
+	// GetMerkleDistribution retrieves a distribution by ID.
+	GetMerkleDistribution(ctx context.Context, id int) (*model.MerkleDistribution, error)
+	// ReserveSettlementTx records a settlement transaction before it is sent and sets its ID, CreatedAt and UpdatedAt.
+	ReserveSettlementTx(ctx context.Context, tx *model.SettlementTx) error
+	// RecordSentSettlementTx records the hash, nonce and status of a reserved settlement transaction once it is sent.
+	RecordSentSettlementTx(ctx context.Context, tx *model.SettlementTx) error
+	// ReplaceSettlementTx marks a pending settlement transaction as replaced and records its replacement.
+	ReplaceSettlementTx(ctx context.Context, replaced int, tx *model.SettlementTx) error
+	// GetSettlementTxs retrieves the settlement transactions of a distribution, oldest first.
+	GetSettlementTxs(ctx context.Context, distributionID int) ([]model.SettlementTx, error)
+	// GetPendingSettlementTxs retrieves the settlement transactions on a network without a receipt yet, oldest first.
+	GetPendingSettlementTxs(ctx context.Context, network string) ([]model.SettlementTx, error)
+	// UpdateSettlementTx records the status of a settlement transaction, with the block it was mined in and the gas it used, zero for a transaction not mined.
+	UpdateSettlementTx(ctx context.Context, id int, status string, blockNumber, gasUsed int64) error
+	// CreateSignInNonce stores a sign-in nonce until expiresAt.
+	CreateSignInNonce(ctx context.Context, nonce string, expiresAt time.Time) error
+	// ConsumeSignInNonce deletes an unexpired sign-in nonce, so it signs in once.
+	ConsumeSignInNonce(ctx context.Context, nonce string) error
+	// GetUserProfile retrieves a user's profile.
+	GetUserProfile(ctx context.Context, address string) (*model.UserProfile, error)
+	// UpsertUserProfile creates or replaces a user's profile.
+	UpsertUserProfile(ctx context.Context, profile *model.UserProfile) error
+	// GetWeeklySummaries retrieves the week's activity of every user due a weekly summary.
+	GetWeeklySummaries(ctx context.Context, weekStart, weekEnd time.Time) ([]model.WeeklySummary, error)
+	// GetBigSwapAlerts retrieves the swaps of at least minUSD of every user due a big-swap alert.
+	GetBigSwapAlerts(ctx context.Context, minUSD model.Decimal) ([]model.BigSwapAlert, error)
+	// RecordNotification records that the notification of a kind covering period was sent to a user.
+	RecordNotification(ctx context.Context, address, kind string, period time.Time) error
+	// CreateProject creates a project.
+	CreateProject(ctx context.Context, project *model.Project) error
+	// GetProjectBySlug retrieves a project by its slug.
+	GetProjectBySlug(ctx context.Context, slug string) (*model.Project, error)
+	// CreateAPIKey stores the hash of a project's API key.
+	CreateAPIKey(ctx context.Context, key *model.APIKey, keyHash string) error
+	// GetProjectByAPIKey retrieves the project of an active API key by the hash of the key.
+	GetProjectByAPIKey(ctx context.Context, keyHash string) (*model.Project, error)
+	// RevokeAPIKey revokes the active API key with a prefix.
+	RevokeAPIKey(ctx context.Context, prefix string) error
+	// ReserveIdempotencyKey reserves an idempotency key for a request, or returns the request hash and response of the key.
+	ReserveIdempotencyKey(ctx context.Context, scope, key, requestHash string, expiredBefore time.Time) (reserved bool, existingHash string, response *model.IdempotentResponse, err error)
+	// CompleteIdempotencyKey stores the response of the request of a reserved idempotency key.
+	CompleteIdempotencyKey(ctx context.Context, scope, key string, response *model.IdempotentResponse) error
+	// ReleaseIdempotencyKey deletes a reserved idempotency key without response.
+	ReleaseIdempotencyKey(ctx context.Context, scope, key string) error
+	// NotifyLiveEvent publishes a live event to the listening API servers.
+	NotifyLiveEvent(ctx context.Context, event *model.LiveEvent) error
+	// ListenLiveEvents passes the published live events to handle until ctx is done or the connection fails.
+	ListenLiveEvents(ctx context.Context, handle func(model.LiveEvent)) error
+	// ForProject returns the repository reading and writing the points, claims, distributions, quests and labels of a project.
+	ForProject(projectID int) Repository
+	// WithTx returns the repository running its queries in tx, to commit the writes of several methods together.
+	WithTx(tx pg.PgxTx) Repository
+}
+
+// queries holds the named statements of the repository, registered next to the method running them.
+var queries = pg.NewQueries()
+
+// Queries returns the named statements of the repository, to be prepared on every connection of
+// the pool with pg.WithPreparedQueries.
+func Queries() *pg.Queries {
+	return queries
 }
 
 // repository manages database operations for users.
 type repository struct {
 	db pg.PgxPool
+	// project is the project of the reward data, model.DefaultProjectID unless set by ForProject.
+	project int
 }
 
 // BeginTransaction starts a new transaction.
@@ -52,9 +229,25 @@ func (r *repository) BeginTransaction(ctx context.Context) (pg.PgxTx, error) {
 	return r.db.Begin(ctx)
 }
 
+// ForProject returns a repository sharing the connections of r that reads and writes the points,
+// claims, distributions, quests and labels of a project. The indexed chain data is shared by every
+// project.
+func (r *repository) ForProject(projectID int) Repository {
+	scoped := *r
+	scoped.project = projectID
+	return &scoped
+}
+
+// Option configures a Repository.
+type Option func(*repository)
+
 // NewRepository creates a new Repository with the provided PostgresDB.
-func NewRepository(pgdb pg.PgxPool) Repository {
-	return &repository{
+func NewRepository(pgdb pg.PgxPool, opts ...Option) Repository {
+	r := &repository{
 		db: pgdb,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
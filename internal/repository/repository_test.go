@@ -4,9 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"hw/internal/model"
 	"hw/internal/repository"
 	pgMock "hw/pkg/pg/mocks"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
@@ -35,3 +38,49 @@ func TestBeginTransaction(t *testing.T) {
 		t.Errorf("Expected tx to be %v, got %v", mockTx, tx)
 	}
 }
+
+// TestForProject tests that a repository of a project binds its project to the queries of the
+// reward data, and leaves the repository it was derived from on the default project.
+func TestForProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	scoped := repo.ForProject(3)
+
+	ctx := context.Background()
+
+	gomock.InOrder(
+		mockDB.EXPECT().Query(ctx, pgMock.Query("GetLeaderboard"), []string{}, []string{}, 3).Return(mockRows, nil),
+		mockDB.EXPECT().Query(ctx, pgMock.Query("GetLeaderboard"), []string{}, []string{}, model.DefaultProjectID).Return(mockRows, nil),
+	)
+	mockRows.EXPECT().Next().Return(false).Times(2)
+	mockRows.EXPECT().Err().Return(nil).Times(2)
+	mockRows.EXPECT().Close().Times(2)
+
+	_, err := scoped.GetLeaderboard(ctx, model.Exclusion{})
+	assert.NoError(t, err)
+	_, err = repo.GetLeaderboard(ctx, model.Exclusion{})
+	assert.NoError(t, err)
+}
+
+// TestWithTx tests that a repository of a transaction runs its queries in the transaction, and
+// leaves the repository it was derived from on the pool.
+func TestWithTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	repo := repository.NewRepository(mockDB)
+	inTx := repo.WithTx(mockTx)
+
+	ctx := context.Background()
+	history := &model.SwapHistory{Token: "tokenABC", Account: "accountXYZ"}
+
+	mockTx.EXPECT().Exec(ctx, pgMock.Query("IncrementDailySwapRollup"), gomock.Any(), gomock.Any(), "tokenABC", "accountXYZ", gomock.Any(), gomock.Any(), gomock.Any()).Return(pgconn.CommandTag{}, nil)
+	mockDB.EXPECT().Exec(ctx, pgMock.Query("IncrementDailySwapRollup"), gomock.Any(), gomock.Any(), "tokenABC", "accountXYZ", gomock.Any(), gomock.Any(), gomock.Any()).Return(pgconn.CommandTag{}, nil)
+
+	assert.NoError(t, inTx.IncrementDailySwapRollup(ctx, history))
+	assert.NoError(t, repo.IncrementDailySwapRollup(ctx, history))
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+)
+
+// rollupMonth truncates t to the UTC calendar month a history partition covers.
+func rollupMonth(t time.Time) time.Time {
+	year, month, _ := t.UTC().Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// historyTable is a history table partitioned by month of key, with the statements reading and
+// rolling up the rows of a month.
+type historyTable struct {
+	key                string
+	oldestQuery        string
+	defaultMonthsQuery string
+	rowsQuery          string
+	rollupQuery        string
+}
+
+var historyTables = map[string]historyTable{
+	model.HistorySwaps: {
+		key: "last_updated",
+		oldestQuery: queries.Add("GetOldestSwapHistory", `
+			SELECT MIN(last_updated) FROM swap_history
+		`),
+		defaultMonthsQuery: queries.Add("GetSwapHistoryDefaultMonths", `
+			SELECT DISTINCT date_trunc('month', last_updated, 'UTC') FROM swap_history_default ORDER BY 1
+		`),
+		rowsQuery: queries.Add("GetSwapHistoryMonthRows", `
+			SELECT id, row_to_json(swap_history)
+			FROM swap_history
+			WHERE last_updated >= $1::timestamptz AND last_updated < $1::timestamptz + interval '1 month' AND id > $2
+			ORDER BY id
+			LIMIT $3
+		`),
+		rollupQuery: queries.Add("RollupSwapHistoryMonth", `
+			INSERT INTO swap_history_rollups (month, network, token, account, usd_value, counted_usd_value, wash_usd_value, wash_counted_usd_value, swap_count)
+			SELECT ($1::timestamptz AT TIME ZONE 'UTC')::date, network, token, account,
+				SUM(usd_value),
+				SUM(counted_usd_value),
+				COALESCE(SUM(usd_value) FILTER (WHERE wash_trade), 0),
+				COALESCE(SUM(counted_usd_value) FILTER (WHERE wash_trade), 0),
+				COUNT(*)
+			FROM swap_history
+			WHERE last_updated >= $1::timestamptz AND last_updated < $1::timestamptz + interval '1 month'
+			GROUP BY network, token, account
+			ON CONFLICT (month, network, token, account) DO UPDATE SET
+				usd_value = swap_history_rollups.usd_value + EXCLUDED.usd_value,
+				counted_usd_value = swap_history_rollups.counted_usd_value + EXCLUDED.counted_usd_value,
+				wash_usd_value = swap_history_rollups.wash_usd_value + EXCLUDED.wash_usd_value,
+				wash_counted_usd_value = swap_history_rollups.wash_counted_usd_value + EXCLUDED.wash_counted_usd_value,
+				swap_count = swap_history_rollups.swap_count + EXCLUDED.swap_count
+		`),
+	},
+	model.HistoryPoints: {
+		key: "created_at",
+		oldestQuery: queries.Add("GetOldestPointsHistory", `
+			SELECT MIN(created_at) FROM points_history
+		`),
+		defaultMonthsQuery: queries.Add("GetPointsHistoryDefaultMonths", `
+			SELECT DISTINCT date_trunc('month', created_at, 'UTC') FROM points_history_default ORDER BY 1
+		`),
+		rowsQuery: queries.Add("GetPointsHistoryMonthRows", `
+			SELECT id, row_to_json(points_history)
+			FROM points_history
+			WHERE created_at >= $1::timestamptz AND created_at < $1::timestamptz + interval '1 month' AND id > $2
+			ORDER BY id
+			LIMIT $3
+		`),
+		rollupQuery: queries.Add("RollupPointsHistoryMonth", `
+			INSERT INTO points_history_rollups (project_id, month, network, token, account, description, points, awards)
+			SELECT project_id, ($1::timestamptz AT TIME ZONE 'UTC')::date, network, token, account, description, SUM(points), COUNT(*)
+			FROM points_history
+			WHERE created_at >= $1::timestamptz AND created_at < $1::timestamptz + interval '1 month'
+			GROUP BY project_id, network, token, account, description
+			ON CONFLICT (project_id, month, network, token, account, description) DO UPDATE SET
+				points = points_history_rollups.points + EXCLUDED.points,
+				awards = points_history_rollups.awards + EXCLUDED.awards
+		`),
+	},
+}
+
+// lookupHistoryTable returns the description of a history table.
+func lookupHistoryTable(table string) (historyTable, error) {
+	t, ok := historyTables[table]
+	if !ok {
+		return historyTable{}, fmt.Errorf("unknown history table %q", table)
+	}
+	return t, nil
+}
+
+var createHistoryPartitionQuery = queries.Add("CreateHistoryPartition", `
+	SELECT create_monthly_partition($1, $2, ($3::timestamptz AT TIME ZONE 'UTC')::date)
+`)
+
+// EnsureHistoryPartitions creates the missing monthly partitions of a history table from the month
+// of from to the month of to, moving the rows of their months out of the default partition.
+func (r *repository) EnsureHistoryPartitions(ctx context.Context, table string, from, to time.Time) error {
+	t, err := lookupHistoryTable(table)
+	if err != nil {
+		return err
+	}
+
+	for month := rollupMonth(from); !month.After(to); month = month.AddDate(0, 1, 0) {
+		if _, err := r.db.Exec(ctx, createHistoryPartitionQuery, table, t.key, month); err != nil {
+			return fmt.Errorf("failed to create partition of %s for %s: %w", table, month.Format("2006-01"), dbError(err))
+		}
+	}
+
+	return nil
+}
+
+// GetOldestHistoryTime retrieves the time of the oldest row of a history table, nil when it is empty.
+func (r *repository) GetOldestHistoryTime(ctx context.Context, table string) (*time.Time, error) {
+	t, err := lookupHistoryTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	var oldest *time.Time
+	if err := r.db.QueryRow(ctx, t.oldestQuery).Scan(&oldest); err != nil {
+		return nil, fmt.Errorf("failed to get oldest row of %s: %w", table, dbError(err))
+	}
+
+	return oldest, nil
+}
+
+// GetDefaultPartitionMonths retrieves the months of the rows of a history table in its default
+// partition, i.e. of the months without a partition, oldest first.
+func (r *repository) GetDefaultPartitionMonths(ctx context.Context, table string) ([]time.Time, error) {
+	t, err := lookupHistoryTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, t.defaultMonthsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query default partition months of %s: %w", table, dbError(err))
+	}
+	defer rows.Close()
+
+	var months []time.Time
+	for rows.Next() {
+		var month time.Time
+		if err := rows.Scan(&month); err != nil {
+			return nil, fmt.Errorf("failed to scan default partition month of %s: %w", table, dbError(err))
+		}
+		months = append(months, rollupMonth(month))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate through default partition months of %s: %w", table, dbError(err))
+	}
+
+	return months, nil
+}
+
+// GetHistoryMonthRows retrieves up to limit rows of the month of a history table with an ID
+// greater than afterID, ordered by ID.
+func (r *repository) GetHistoryMonthRows(ctx context.Context, table string, month time.Time, afterID int64, limit int) ([]model.HistoryRow, error) {
+	t, err := lookupHistoryTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, t.rowsQuery, rollupMonth(month), afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows of %s: %w", table, dbError(err))
+	}
+	defer rows.Close()
+
+	historyRows := make([]model.HistoryRow, 0, limit)
+	for rows.Next() {
+		var row model.HistoryRow
+		if err := rows.Scan(&row.ID, &row.Data); err != nil {
+			return nil, fmt.Errorf("failed to scan row of %s: %w", table, dbError(err))
+		}
+		historyRows = append(historyRows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate through rows of %s: %w", table, dbError(err))
+	}
+
+	return historyRows, nil
+}
+
+var dropHistoryPartitionQuery = queries.Add("DropHistoryPartition", `
+	SELECT drop_monthly_partition($1, $2, ($3::timestamptz AT TIME ZONE 'UTC')::date)
+`)
+
+// DropHistoryMonth adds the rows of the month of a history table to its monthly rollups and
+// removes them, dropping the partition of the month, in one transaction without a statement
+// timeout, since rolling up a month can outlast it. It returns the number of rows removed. Rows
+// of the month inserted later, e.g. by a backfill, are added to the rollups by the next call.
+func (r *repository) DropHistoryMonth(ctx context.Context, table string, month time.Time) (int64, error) {
+	t, err := lookupHistoryTable(table)
+	if err != nil {
+		return 0, err
+	}
+	month = rollupMonth(month)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", dbError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	// The rollup and the removal must see the same rows
+	if _, err := tx.Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		return 0, fmt.Errorf("failed to set isolation level: %w", dbError(err))
+	}
+	if _, err := tx.Exec(ctx, "SET LOCAL statement_timeout = 0"); err != nil {
+		return 0, fmt.Errorf("failed to disable statement timeout: %w", dbError(err))
+	}
+	if _, err := tx.Exec(ctx, t.rollupQuery, month); err != nil {
+		return 0, fmt.Errorf("failed to roll up %s for %s: %w", table, month.Format("2006-01"), dbError(err))
+	}
+	var removed int64
+	if err := tx.QueryRow(ctx, dropHistoryPartitionQuery, table, t.key, month).Scan(&removed); err != nil {
+		return 0, fmt.Errorf("failed to drop %s for %s: %w", table, month.Format("2006-01"), dbError(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", dbError(err))
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,160 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestEnsureHistoryPartitions tests that a partition is created for every month of the range, by
+// the partition key of the table.
+func TestEnsureHistoryPartitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	for _, month := range []time.Time{
+		time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		mockDB.EXPECT().Exec(ctx, pgMock.Query("CreateHistoryPartition"), model.HistorySwaps, "last_updated", month).Return(pgconn.NewCommandTag("SELECT 1"), nil)
+	}
+
+	err := repo.EnsureHistoryPartitions(ctx, model.HistorySwaps, time.Date(2024, 11, 20, 8, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+
+	assert.Error(t, repo.EnsureHistoryPartitions(ctx, "users", time.Now(), time.Now()))
+}
+
+// TestGetOldestHistoryTime tests that an empty history table has no oldest row.
+func TestGetOldestHistoryTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetOldestPointsHistory")).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).Return(nil)
+
+	oldest, err := repo.GetOldestHistoryTime(ctx, model.HistoryPoints)
+	assert.NoError(t, err)
+	assert.Nil(t, oldest)
+}
+
+// TestGetDefaultPartitionMonths tests that the months of the default partition are truncated to
+// UTC months.
+func TestGetDefaultPartitionMonths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetSwapHistoryDefaultMonths")).Return(mockRows, nil)
+	mockRows.EXPECT().Close()
+	gomock.InOrder(
+		mockRows.EXPECT().Next().Return(true),
+		mockRows.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*time.Time)) = time.Date(2024, 4, 1, 8, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60))
+			return nil
+		}),
+		mockRows.EXPECT().Next().Return(false),
+	)
+	mockRows.EXPECT().Err().Return(nil)
+
+	months, err := repo.GetDefaultPartitionMonths(ctx, model.HistorySwaps)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}, months)
+}
+
+// TestGetHistoryMonthRows tests that the rows of a month are read from the month start after an ID.
+func TestGetHistoryMonthRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetSwapHistoryMonthRows"), month, int64(41), 2).Return(mockRows, nil)
+	mockRows.EXPECT().Close()
+	gomock.InOrder(
+		mockRows.EXPECT().Next().Return(true),
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*int64)) = 42
+			*(dest[1].(*json.RawMessage)) = json.RawMessage(`{"id":42}`)
+			return nil
+		}),
+		mockRows.EXPECT().Next().Return(false),
+	)
+	mockRows.EXPECT().Err().Return(nil)
+
+	rows, err := repo.GetHistoryMonthRows(ctx, model.HistorySwaps, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), 41, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.HistoryRow{{ID: 42, Data: json.RawMessage(`{"id":42}`)}}, rows)
+}
+
+// TestDropHistoryMonth tests that a month is rolled up and dropped in one transaction, and rolled
+// back when the drop fails.
+func TestDropHistoryMonth(t *testing.T) {
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("dropped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := pgMock.NewMockPgxPool(ctrl)
+		mockTx := pgMock.NewMockPgxTx(ctrl)
+		mockRow := pgMock.NewMockPgxRows(ctrl)
+		repo := repository.NewRepository(mockDB)
+		ctx := context.Background()
+
+		mockDB.EXPECT().Begin(ctx).Return(mockTx, nil)
+		gomock.InOrder(
+			mockTx.EXPECT().Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Return(pgconn.NewCommandTag("SET"), nil),
+			mockTx.EXPECT().Exec(ctx, "SET LOCAL statement_timeout = 0").Return(pgconn.NewCommandTag("SET"), nil),
+			mockTx.EXPECT().Exec(ctx, pgMock.Query("RollupPointsHistoryMonth"), month).Return(pgconn.NewCommandTag("INSERT 0 3"), nil),
+			mockTx.EXPECT().QueryRow(ctx, pgMock.Query("DropHistoryPartition"), model.HistoryPoints, "created_at", month).Return(mockRow),
+			mockTx.EXPECT().Commit(ctx).Return(nil),
+		)
+		mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*int64)) = 12
+			return nil
+		})
+		mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+		removed, err := repo.DropHistoryMonth(ctx, model.HistoryPoints, month.Add(36*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), removed)
+	})
+
+	t.Run("drop fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := pgMock.NewMockPgxPool(ctrl)
+		mockTx := pgMock.NewMockPgxTx(ctrl)
+		mockRow := pgMock.NewMockPgxRows(ctrl)
+		repo := repository.NewRepository(mockDB)
+		ctx := context.Background()
+
+		mockDB.EXPECT().Begin(ctx).Return(mockTx, nil)
+		mockTx.EXPECT().Exec(ctx, gomock.Any()).Return(pgconn.NewCommandTag("SET"), nil).Times(2)
+		mockTx.EXPECT().Exec(ctx, pgMock.Query("RollupSwapHistoryMonth"), month).Return(pgconn.NewCommandTag("INSERT 0 3"), nil)
+		mockTx.EXPECT().QueryRow(ctx, pgMock.Query("DropHistoryPartition"), model.HistorySwaps, "last_updated", month).Return(mockRow)
+		mockRow.EXPECT().Scan(gomock.Any()).Return(errors.New("lock timeout"))
+		mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+		_, err := repo.DropHistoryMonth(ctx, model.HistorySwaps, month)
+		assert.ErrorContains(t, err, "failed to drop swap_history for 2024-03")
+	})
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var reserveSettlementTxQuery = queries.Add("ReserveSettlementTx", `
+	INSERT INTO settlement_transactions (distribution_id, action, network, sender, status)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, created_at, updated_at
+`)
+
+// ReserveSettlementTx records a settlement transaction before it is sent, without a hash and
+// nonce, and sets its ID, CreatedAt and UpdatedAt. It returns model.ErrAlreadyExists when the
+// distribution has a transaction of the action that is not failed or replaced, so an action is
+// sent once however many settlements of it run.
+func (r *repository) ReserveSettlementTx(ctx context.Context, tx *model.SettlementTx) error {
+	err := r.db.QueryRow(ctx, reserveSettlementTxQuery, tx.DistributionID, tx.Action, tx.Network, tx.Sender, tx.Status).
+		Scan(&tx.ID, &tx.CreatedAt, &tx.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to reserve settlement transaction: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var recordSentSettlementTxQuery = queries.Add("RecordSentSettlementTx", `
+	UPDATE settlement_transactions
+	SET hash = $2, nonce = $3, status = $4, updated_at = now()
+	WHERE id = $1
+	RETURNING updated_at
+`)
+
+// RecordSentSettlementTx records the hash, nonce and status of a reserved settlement transaction
+// once it is sent, and sets its UpdatedAt.
+func (r *repository) RecordSentSettlementTx(ctx context.Context, tx *model.SettlementTx) error {
+	err := r.db.QueryRow(ctx, recordSentSettlementTxQuery, tx.ID, tx.Hash, int64(tx.Nonce), tx.Status).Scan(&tx.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record sent settlement transaction: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var replaceSettlementTxQuery = queries.Add("ReplaceSettlementTx", `
+	UPDATE settlement_transactions
+	SET status = 'replaced', updated_at = now()
+	WHERE id = $1 AND status = 'pending'
+`)
+
+var createSettlementTxQuery = queries.Add("CreateSettlementTx", `
+	INSERT INTO settlement_transactions (distribution_id, action, network, hash, sender, nonce, status, replaces)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id, created_at, updated_at
+`)
+
+// ReplaceSettlementTx marks the pending settlement transaction replaced as replaced and records
+// its replacement tx, setting its ID, CreatedAt and UpdatedAt, in one transaction. It returns
+// model.ErrSettlementNotPending when replaced is no longer pending.
+func (r *repository) ReplaceSettlementTx(ctx context.Context, replaced int, tx *model.SettlementTx) error {
+	dbTx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", dbError(err))
+	}
+	defer dbTx.Rollback(ctx)
+
+	// The replaced transaction leaves the unique index of pending transactions first
+	tag, err := dbTx.Exec(ctx, replaceSettlementTxQuery, replaced)
+	if err != nil {
+		return fmt.Errorf("failed to replace settlement transaction: %w", dbError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrSettlementNotPending
+	}
+	tx.Replaces = &replaced
+	err = dbTx.QueryRow(ctx, createSettlementTxQuery, tx.DistributionID, tx.Action, tx.Network, tx.Hash, tx.Sender, int64(tx.Nonce), tx.Status, tx.Replaces).
+		Scan(&tx.ID, &tx.CreatedAt, &tx.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement transaction: %w", dbError(err))
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// A transaction still sending or to reconcile has neither hash nor nonce
+const settlementTxColumns = `id, distribution_id, action, network, COALESCE(hash, ''), sender, COALESCE(nonce, 0), status, block_number, gas_used, replaces, created_at, updated_at`
+
+var getSettlementTxsQuery = queries.Add("GetSettlementTxs", `
+	SELECT `+settlementTxColumns+`
+	FROM settlement_transactions
+	WHERE distribution_id = $1
+	ORDER BY id
+`)
+
+// GetSettlementTxs retrieves the settlement transactions of a distribution, oldest first.
+func (r *repository) GetSettlementTxs(ctx context.Context, distributionID int) ([]model.SettlementTx, error) {
+	rows, err := r.db.Query(ctx, getSettlementTxsQuery, distributionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query settlement transactions: %w", dbError(err))
+	}
+	return collectSettlementTxs(rows)
+}
+
+var getPendingSettlementTxsQuery = queries.Add("GetPendingSettlementTxs", `
+	SELECT `+settlementTxColumns+`
+	FROM settlement_transactions
+	WHERE network = $1 AND status = 'pending'
+	ORDER BY id
+`)
+
+// GetPendingSettlementTxs retrieves the settlement transactions on a network without a receipt
+// yet, oldest first.
+func (r *repository) GetPendingSettlementTxs(ctx context.Context, network string) ([]model.SettlementTx, error) {
+	rows, err := r.db.Query(ctx, getPendingSettlementTxsQuery, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending settlement transactions: %w", dbError(err))
+	}
+	return collectSettlementTxs(rows)
+}
+
+// collectSettlementTxs scans and closes rows of settlementTxColumns.
+func collectSettlementTxs(rows pgx.Rows) ([]model.SettlementTx, error) {
+	defer rows.Close()
+
+	txs := []model.SettlementTx{}
+	for rows.Next() {
+		var (
+			tx    model.SettlementTx
+			nonce int64
+		)
+		if err := rows.Scan(&tx.ID, &tx.DistributionID, &tx.Action, &tx.Network, &tx.Hash, &tx.Sender, &nonce, &tx.Status,
+			&tx.BlockNumber, &tx.GasUsed, &tx.Replaces, &tx.CreatedAt, &tx.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan settlement transaction: %w", dbError(err))
+		}
+		tx.Nonce = uint64(nonce)
+		txs = append(txs, tx)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate through settlement transactions: %w", dbError(err))
+	}
+
+	return txs, nil
+}
+
+var updateSettlementTxQuery = queries.Add("UpdateSettlementTx", `
+	UPDATE settlement_transactions
+	SET status = $2, block_number = NULLIF($3, 0), gas_used = NULLIF($4, 0), updated_at = now()
+	WHERE id = $1
+`)
+
+// UpdateSettlementTx records the status of a settlement transaction, with the block it was mined
+// in and the gas it used, zero for a transaction not mined.
+func (r *repository) UpdateSettlementTx(ctx context.Context, id int, status string, blockNumber, gasUsed int64) error {
+	if _, err := r.db.Exec(ctx, updateSettlementTxQuery, id, status, blockNumber, gasUsed); err != nil {
+		return fmt.Errorf("failed to update settlement transaction: %w", dbError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestReserveSettlementTx tests that a reserved settlement transaction gets its ID, and that a
+// second reservation of an action is a conflict.
+func TestReserveSettlementTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	tx := &model.SettlementTx{DistributionID: 3, Action: model.SettlementFund, Network: "mainnet", Sender: "0xf1", Status: model.SettlementSending}
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("ReserveSettlementTx"), 3, model.SettlementFund, "mainnet", "0xf1", model.SettlementSending).Return(mockRow).Times(2)
+	gomock.InOrder(
+		mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*int)) = 9
+			*(dest[1].(*time.Time)) = time.Date(2024, 11, 4, 0, 0, 0, 0, time.UTC)
+			return nil
+		}),
+		mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(&pgconn.PgError{Code: "23505"}),
+	)
+
+	assert.NoError(t, repo.ReserveSettlementTx(ctx, tx))
+	assert.Equal(t, 9, tx.ID)
+
+	err := repo.ReserveSettlementTx(ctx, tx)
+	assert.ErrorIs(t, err, model.ErrAlreadyExists)
+}
+
+// TestRecordSentSettlementTx tests that the hash, nonce and status of a sent transaction are
+// recorded on its reservation.
+func TestRecordSentSettlementTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	updatedAt := time.Date(2024, 11, 4, 0, 0, 1, 0, time.UTC)
+	tx := &model.SettlementTx{ID: 9, Hash: "0xabc", Nonce: 4, Status: model.SettlementPending}
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("RecordSentSettlementTx"), 9, "0xabc", int64(4), model.SettlementPending).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*time.Time)) = updatedAt
+		return nil
+	})
+
+	assert.NoError(t, repo.RecordSentSettlementTx(ctx, tx))
+	assert.Equal(t, updatedAt, tx.UpdatedAt)
+}
+
+// TestGetPendingSettlementTxs tests that the pending transactions of a network are scanned with
+// their nonce, and without a block while not mined.
+func TestGetPendingSettlementTxs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetPendingSettlementTxs"), "base").Return(mockRows, nil)
+	mockRows.EXPECT().Close()
+	gomock.InOrder(
+		mockRows.EXPECT().Next().Return(true),
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*int)) = 9
+			*(dest[1].(*int)) = 3
+			*(dest[2].(*string)) = model.SettlementUpdateRoot
+			*(dest[3].(*string)) = "base"
+			*(dest[6].(*int64)) = 12
+			*(dest[7].(*string)) = model.SettlementPending
+			return nil
+		}),
+		mockRows.EXPECT().Next().Return(false),
+	)
+	mockRows.EXPECT().Err().Return(nil)
+
+	txs, err := repo.GetPendingSettlementTxs(ctx, "base")
+	assert.NoError(t, err)
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, 9, txs[0].ID)
+		assert.Equal(t, 3, txs[0].DistributionID)
+		assert.Equal(t, uint64(12), txs[0].Nonce)
+		assert.Nil(t, txs[0].BlockNumber)
+	}
+}
+
+// TestReplaceSettlementTx tests that a replacement is recorded in the transaction marking the
+// replaced transaction, which must still be pending.
+func TestReplaceSettlementTx(t *testing.T) {
+	t.Run("replaced", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := pgMock.NewMockPgxPool(ctrl)
+		mockTx := pgMock.NewMockPgxTx(ctrl)
+		mockRow := pgMock.NewMockPgxRows(ctrl)
+		repo := repository.NewRepository(mockDB)
+		ctx := context.Background()
+
+		replaces := 9
+		mockDB.EXPECT().Begin(ctx).Return(mockTx, nil)
+		gomock.InOrder(
+			mockTx.EXPECT().Exec(ctx, pgMock.Query("ReplaceSettlementTx"), 9).Return(pgconn.NewCommandTag("UPDATE 1"), nil),
+			mockTx.EXPECT().QueryRow(ctx, pgMock.Query("CreateSettlementTx"), 3, model.SettlementFund, "mainnet", "0xdef", "0xf1", int64(4), model.SettlementPending, &replaces).Return(mockRow),
+			mockTx.EXPECT().Commit(ctx).Return(nil),
+		)
+		mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*int)) = 10
+			return nil
+		})
+		mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+		tx := &model.SettlementTx{DistributionID: 3, Action: model.SettlementFund, Network: "mainnet", Hash: "0xdef", Sender: "0xf1", Nonce: 4, Status: model.SettlementPending}
+		assert.NoError(t, repo.ReplaceSettlementTx(ctx, 9, tx))
+		assert.Equal(t, 10, tx.ID)
+		assert.Equal(t, 9, *tx.Replaces)
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := pgMock.NewMockPgxPool(ctrl)
+		mockTx := pgMock.NewMockPgxTx(ctrl)
+		repo := repository.NewRepository(mockDB)
+		ctx := context.Background()
+
+		mockDB.EXPECT().Begin(ctx).Return(mockTx, nil)
+		mockTx.EXPECT().Exec(ctx, pgMock.Query("ReplaceSettlementTx"), 9).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+		mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+		err := repo.ReplaceSettlementTx(ctx, 9, &model.SettlementTx{})
+		assert.ErrorIs(t, err, model.ErrSettlementNotPending)
+	})
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var getTransferBalancesQuery = queries.Add("GetTransferBalances", `
+	SELECT account, SUM(delta)
+	FROM position_changes
+	WHERE contract = $1 AND network = $2 AND block_number <= $3
+	GROUP BY account
+	HAVING SUM(delta) > 0
+	ORDER BY account
+`)
+
+// GetTransferBalances retrieves the balance of every holder of a contract at a block, summed from
+// its indexed transfers and ordered by account.
+func (r *repository) GetTransferBalances(ctx context.Context, contract, network string, blockNumber int64) ([]model.HolderBalance, error) {
+	rows, err := r.db.Query(ctx, getTransferBalancesQuery, contract, network, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer balances: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	balances := []model.HolderBalance{}
+	for rows.Next() {
+		var balance model.HolderBalance
+		if err := rows.Scan(&balance.Account, &balance.Balance); err != nil {
+			return nil, fmt.Errorf("failed to scan holder balance: %w", dbError(err))
+		}
+		balances = append(balances, balance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	return balances, nil
+}
+
+var createBalanceSnapshotQuery = queries.Add("CreateBalanceSnapshot", `
+	WITH snapshot AS (
+		INSERT INTO balance_snapshots (network, token, block_number, source, holders, total)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	), holders AS (
+		INSERT INTO balance_snapshot_holders (snapshot_id, account, balance)
+		SELECT s.id, h.account, h.balance::numeric
+		FROM snapshot s, unnest($7::text[], $8::text[]) AS h(account, balance)
+	)
+	SELECT id, created_at FROM snapshot
+`)
+
+// CreateBalanceSnapshot inserts a snapshot together with the balance of every holder in a single
+// statement, so a snapshot is never visible without its balances. It sets the ID and CreatedAt of snapshot.
+func (r *repository) CreateBalanceSnapshot(ctx context.Context, snapshot *model.BalanceSnapshot, balances []model.HolderBalance) error {
+	accounts := make([]string, len(balances))
+	amounts := make([]string, len(balances))
+	for i, balance := range balances {
+		accounts[i], amounts[i] = balance.Account, balance.Balance.String()
+	}
+
+	err := r.db.QueryRow(ctx, createBalanceSnapshotQuery, snapshot.Network, snapshot.Token, snapshot.BlockNumber, snapshot.Source,
+		snapshot.Holders, snapshot.Total, accounts, amounts).Scan(&snapshot.ID, &snapshot.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create balance snapshot: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var getLatestBalanceSnapshotQuery = queries.Add("GetLatestBalanceSnapshot", `
+	SELECT id, source, holders, total, created_at
+	FROM balance_snapshots
+	WHERE token = $1 AND network = $2 AND block_number = $3
+	ORDER BY id DESC
+	LIMIT 1
+`)
+
+var getBalanceSnapshotHoldersQuery = queries.Add("GetBalanceSnapshotHolders", `
+	SELECT account, balance
+	FROM balance_snapshot_holders
+	WHERE snapshot_id = $1
+	ORDER BY balance DESC, account
+`)
+
+// GetLatestBalanceSnapshot retrieves the latest snapshot of a token taken at a block, with the
+// balance of every holder, largest first.
+func (r *repository) GetLatestBalanceSnapshot(ctx context.Context, token, network string, blockNumber int64) (*model.BalanceSnapshot, []model.HolderBalance, error) {
+	snapshot := model.BalanceSnapshot{Network: network, Token: token, BlockNumber: blockNumber}
+	err := r.db.QueryRow(ctx, getLatestBalanceSnapshotQuery, token, network, blockNumber).
+		Scan(&snapshot.ID, &snapshot.Source, &snapshot.Holders, &snapshot.Total, &snapshot.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, model.ErrSnapshotNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get balance snapshot: %w", dbError(err))
+	}
+
+	rows, err := r.db.Query(ctx, getBalanceSnapshotHoldersQuery, snapshot.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get balance snapshot holders: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	balances := []model.HolderBalance{}
+	for rows.Next() {
+		var balance model.HolderBalance
+		if err := rows.Scan(&balance.Account, &balance.Balance); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan holder balance: %w", dbError(err))
+		}
+		balances = append(balances, balance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	return &snapshot, balances, nil
+}
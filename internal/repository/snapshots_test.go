@@ -0,0 +1,144 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetTransferBalances tests that the balances of the holders of a contract are summed up to a block.
+func TestGetTransferBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetTransferBalances"), "tokenABC", "mainnet", int64(20933200)).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "accountXYZ"
+		*(dest[1].(*model.Decimal)) = model.NewDecimalFromFloat(500)
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	balances, err := repo.GetTransferBalances(ctx, "tokenABC", "mainnet", 20933200)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.HolderBalance{{Account: "accountXYZ", Balance: model.NewDecimalFromFloat(500)}}, balances)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("query error"))
+
+	_, err = repo.GetTransferBalances(ctx, "tokenABC", "mainnet", 20933200)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get transfer balances")
+}
+
+// TestCreateBalanceSnapshot tests that a snapshot is inserted with the balances of its holders and gets its ID.
+func TestCreateBalanceSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	createdAt := time.Unix(1727740800, 0)
+	snapshot := &model.BalanceSnapshot{
+		Network:     "mainnet",
+		Token:       "tokenABC",
+		BlockNumber: 20933200,
+		Source:      model.SnapshotSourceTransfers,
+		Holders:     2,
+		Total:       model.NewDecimalFromFloat(800),
+	}
+	balances := []model.HolderBalance{
+		{Account: "accountA", Balance: model.NewDecimalFromFloat(500)},
+		{Account: "accountB", Balance: model.NewDecimalFromFloat(300)},
+	}
+
+	mockDB.EXPECT().
+		QueryRow(ctx, pgMock.Query("CreateBalanceSnapshot"), "mainnet", "tokenABC", int64(20933200), model.SnapshotSourceTransfers, 2, snapshot.Total,
+			[]string{"accountA", "accountB"}, []string{"500", "300"}).
+		Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*int)) = 7
+		*(dest[1].(*time.Time)) = createdAt
+		return nil
+	})
+
+	err := repo.CreateBalanceSnapshot(ctx, snapshot, balances)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, snapshot.ID)
+	assert.Equal(t, createdAt, snapshot.CreatedAt)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(errors.New("insert error"))
+
+	err = repo.CreateBalanceSnapshot(ctx, snapshot, balances)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create balance snapshot")
+}
+
+// TestGetLatestBalanceSnapshot tests that the latest snapshot at a block is read with its holders.
+func TestGetLatestBalanceSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetLatestBalanceSnapshot"), "tokenABC", "mainnet", int64(20933200)).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*int)) = 7
+		*(dest[1].(*string)) = model.SnapshotSourceBalanceOf
+		*(dest[2].(*int)) = 1
+		*(dest[3].(*model.Decimal)) = model.NewDecimalFromFloat(500)
+		return nil
+	})
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetBalanceSnapshotHolders"), 7).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "accountXYZ"
+		*(dest[1].(*model.Decimal)) = model.NewDecimalFromFloat(500)
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	snapshot, balances, err := repo.GetLatestBalanceSnapshot(ctx, "tokenABC", "mainnet", 20933200)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, snapshot.ID)
+	assert.Equal(t, model.SnapshotSourceBalanceOf, snapshot.Source)
+	assert.Equal(t, "tokenABC", snapshot.Token)
+	assert.Equal(t, []model.HolderBalance{{Account: "accountXYZ", Balance: model.NewDecimalFromFloat(500)}}, balances)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+	_, _, err = repo.GetLatestBalanceSnapshot(ctx, "tokenABC", "mainnet", 20933201)
+
+	assert.ErrorIs(t, err, model.ErrSnapshotNotFound)
+}
@@ -2,124 +2,444 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
 )
 
-// CreateSwapHistory inserts a new swap history record into the database.
-func (r *repository) CreateSwapHistory(ctx context.Context, swapHistory *model.SwapHistory) error {
-	const query = `
-		INSERT INTO swap_history (token, account, transaction_hash, usd_value, last_updated)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
-	`
+var createSwapHistoryQuery = queries.Add("CreateSwapHistory", `
+	INSERT INTO swap_history (network, token, account, transaction_hash, log_index, usd_value, counted_usd_value, last_updated)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (network, transaction_hash, log_index, last_updated) DO NOTHING
+	RETURNING id, created_at
+`)
 
+// CreateSwapHistory inserts a new swap history record into the database. It returns
+// model.ErrAlreadyExists when the swap of the log was already recorded.
+func (r *repository) CreateSwapHistory(ctx context.Context, swapHistory *model.SwapHistory) error {
 	err := r.db.QueryRow(
 		ctx,
-		query,
+		createSwapHistoryQuery,
+		swapHistory.Network,
 		swapHistory.Token,
 		swapHistory.Account,
 		swapHistory.TransactionHash,
+		swapHistory.LogIndex,
 		swapHistory.UsdValue,
+		swapHistory.CountedUsdValue,
 		swapHistory.LastUpdated,
 	).Scan(&swapHistory.ID, &swapHistory.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("swap %s:%d: %w", swapHistory.TransactionHash, swapHistory.LogIndex, model.ErrAlreadyExists)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create swap history: %w", err)
+		return fmt.Errorf("failed to create swap history: %w", dbError(err))
 	}
 
 	return nil
 }
 
-// GetSwapTotalUsd retrieves the total USD value of swaps for a given account and token.
-func (r *repository) GetSwapTotalUsd(ctx context.Context, account, token string) (float64, error) {
-	const query = `
-		SELECT SUM(usd_value)
+var getSwapTotalUsdQuery = queries.Add("GetSwapTotalUsd", `
+	SELECT
+		COALESCE(SUM(counted_usd_value), 0),
+		COALESCE(SUM(wash_counted_usd_value), 0),
+		COALESCE(SUM(swap_count), 0)
+	FROM (
+		SELECT counted_usd_value, CASE WHEN wash_trade THEN counted_usd_value ELSE 0 END AS wash_counted_usd_value, 1 AS swap_count
+		FROM swap_history WHERE account = $1 AND token = $2
+		UNION ALL
+		SELECT counted_usd_value, wash_counted_usd_value, swap_count
+		FROM swap_history_rollups WHERE account = $1 AND token = $2
+	) swaps
+`)
+
+// GetSwapTotalUsd retrieves the total USD value counted towards points, the part of it flagged as
+// wash trades and count of swaps for a given account and token, an empty total when the account has no swaps.
+// The months dropped by the retention count through their rollups.
+func (r *repository) GetSwapTotalUsd(ctx context.Context, account, token string) (model.SwapTotal, error) {
+	var total model.SwapTotal
+	err := r.db.QueryRow(ctx, getSwapTotalUsdQuery, account, token).Scan(&total.UsdValue, &total.WashUsdValue, &total.SwapCount)
+	if err != nil {
+		return model.SwapTotal{}, fmt.Errorf("failed to get total swap USD: %w", dbError(err))
+	}
+
+	return total, nil
+}
+
+var flagRoundTripSwapsQuery = queries.Add("FlagRoundTripSwaps", `
+	WITH legs AS (
+		SELECT id, transaction_hash, log_index
 		FROM swap_history
-		WHERE account = $1 AND token = $2
-	`
+		WHERE transaction_hash = $1 AND network = $2 AND token = $3 AND account = $4 AND last_updated = $5
+	)
+	UPDATE swap_history
+	SET wash_trade = true
+	WHERE last_updated = $5 AND id IN (SELECT id FROM legs) AND (SELECT COUNT(DISTINCT (transaction_hash, log_index)) FROM legs) > 1
+	RETURNING id
+`)
 
-	var totalUsd float64
-	err := r.db.QueryRow(ctx, query, account, token).Scan(&totalUsd)
+// FlagRoundTripSwaps flags the swaps of the account of a swap history entry through its pool in
+// its transaction as wash trades when there are several, and reports whether they were flagged.
+// Legs are counted by log, so a swap recorded twice is not a round trip by itself. The legs share
+// the block time of the transaction, so only the partition of its month is read.
+func (r *repository) FlagRoundTripSwaps(ctx context.Context, swapHistory *model.SwapHistory) (bool, error) {
+	rows, err := r.db.Query(ctx, flagRoundTripSwapsQuery, swapHistory.TransactionHash, swapHistory.Network, swapHistory.Token, swapHistory.Account, swapHistory.LastUpdated)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get total swap USD: %w", err)
+		return false, fmt.Errorf("failed to flag round trip swaps: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	flagged := false
+	for rows.Next() {
+		flagged = true
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to flag round trip swaps: %w", dbError(err))
 	}
 
-	return totalUsd, nil
+	return flagged, nil
 }
 
+var getUserSwapSummaryQuery = queries.Add("GetUserSwapSummary", `
+	SELECT token, SUM(usd_value)
+	FROM (
+		SELECT token, usd_value FROM swap_history WHERE account = $1
+		UNION ALL
+		SELECT token, usd_value FROM swap_history_rollups WHERE account = $1
+	) swaps
+	GROUP BY token
+`)
+
 // GetUserSwapSummary retrieves the sum of USD values grouped by token for a given account.
-func (r *repository) GetUserSwapSummary(ctx context.Context, account string) (map[string]float64, error) {
-	const query = `
-		SELECT token, SUM(usd_value)
-		FROM swap_history
-		WHERE account = $1
-		GROUP BY token
-	`
+func (r *repository) GetUserSwapSummary(ctx context.Context, account string) (map[string]model.Decimal, error) {
+	rows, err := r.db.Query(ctx, getUserSwapSummaryQuery, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve token USD sums: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	result := make(map[string]model.Decimal)
+	for rows.Next() {
+		var token string
+		var sumUsd model.Decimal
+		if err := rows.Scan(&token, &sumUsd); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		result[token] = sumUsd
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return result, nil
+}
 
-	rows, err := r.db.Query(ctx, query, account)
+var getUserSwapSummaryByNetworkQuery = queries.Add("GetUserSwapSummaryByNetwork", `
+	SELECT token, SUM(usd_value)
+	FROM (
+		SELECT token, usd_value FROM swap_history WHERE account = $1 AND network = $2
+		UNION ALL
+		SELECT token, usd_value FROM swap_history_rollups WHERE account = $1 AND network = $2
+	) swaps
+	GROUP BY token
+`)
+
+// GetUserSwapSummaryByNetwork retrieves the sum of USD values grouped by token for a given account on a single network.
+func (r *repository) GetUserSwapSummaryByNetwork(ctx context.Context, account, network string) (map[string]model.Decimal, error) {
+	rows, err := r.db.Query(ctx, getUserSwapSummaryByNetworkQuery, account, network)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve token USD sums: %w", err)
+		return nil, fmt.Errorf("failed to retrieve token USD sums: %w", dbError(err))
 	}
 	defer rows.Close()
 
-	result := make(map[string]float64)
+	result := make(map[string]model.Decimal)
 	for rows.Next() {
 		var token string
-		var sumUsd float64
+		var sumUsd model.Decimal
 		if err := rows.Scan(&token, &sumUsd); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
 		}
 		result[token] = sumUsd
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return result, nil
+}
+
+var getUserPoolsQuery = queries.Add("GetUserPools", `
+	WITH swaps AS (
+		SELECT token, SUM(usd_value) AS usd_value
+		FROM (
+			SELECT token, usd_value FROM swap_history WHERE account = $1 AND ($2 = '' OR network = $2)
+			UNION ALL
+			SELECT token, usd_value FROM swap_history_rollups WHERE account = $1 AND ($2 = '' OR network = $2)
+		) history
+		GROUP BY token
+	)
+	SELECT swaps.token, swaps.usd_value, points_history.description, points_history.points
+	FROM swaps
+	LEFT JOIN points_history ON points_history.account = $1 AND points_history.token = swaps.token
+		AND ($2 = '' OR points_history.network = $2) AND points_history.project_id = $3
+	ORDER BY swaps.token, points_history.created_at DESC
+`)
+
+// GetUserPools retrieves the swap volume of an account by token with the points awarded for each,
+// newest first, in one query, on a single network unless network is empty.
+func (r *repository) GetUserPools(ctx context.Context, account, network string) (map[string]*model.UserPool, error) {
+	rows, err := r.db.Query(ctx, getUserPoolsQuery, account, network, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user pools: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	result := make(map[string]*model.UserPool)
+	for rows.Next() {
+		var token string
+		var usdValue model.Decimal
+		var description *string
+		var points model.Decimal
+		if err := rows.Scan(&token, &usdValue, &description, &points); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		pool := result[token]
+		if pool == nil {
+			pool = &model.UserPool{TotalUsdValue: usdValue, Tasks: []model.UserTask{}}
+			result[token] = pool
+		}
+		// A pool without points has a single row without a task
+		if description == nil {
+			continue
+		}
+		pool.Points = pool.Points.Add(points)
+		pool.Tasks = append(pool.Tasks, model.UserTask{Description: *description, Points: points})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return result, nil
+}
+
+var getUserNetworkSummaryQuery = queries.Add("GetUserNetworkSummary", `
+	SELECT network, COALESCE(SUM(usd_value), 0), COALESCE(SUM(wash_usd_value), 0), COALESCE(SUM(points), 0)
+	FROM (
+		SELECT network, usd_value, CASE WHEN wash_trade THEN usd_value ELSE 0 END AS wash_usd_value, 0 AS points
+		FROM swap_history WHERE account = $1
+		UNION ALL
+		SELECT network, usd_value, wash_usd_value, 0 AS points FROM swap_history_rollups WHERE account = $1
+		UNION ALL
+		SELECT network, 0 AS usd_value, 0 AS wash_usd_value, points FROM points_history WHERE account = $1 AND project_id = $2
+		UNION ALL
+		SELECT network, 0 AS usd_value, 0 AS wash_usd_value, points FROM points_history_rollups WHERE account = $1 AND project_id = $2
+	) activity
+	GROUP BY network
+`)
+
+// GetUserNetworkSummary retrieves a user's swap volume, the part of it flagged as wash trades, and
+// points grouped by network.
+func (r *repository) GetUserNetworkSummary(ctx context.Context, account string) (map[string]model.NetworkSummary, error) {
+	rows, err := r.db.Query(ctx, getUserNetworkSummaryQuery, account, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve network summary: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	result := make(map[string]model.NetworkSummary)
+	for rows.Next() {
+		var network string
+		var summary model.NetworkSummary
+		if err := rows.Scan(&network, &summary.UsdValue, &summary.WashUsdValue, &summary.Points); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		result[network] = summary
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
 	}
 
 	return result, nil
 }
 
-// GetUserSwapSummaryLast7Days retrieves the total USD and percentage of swaps for each user over the past seven days for a specific token.
-func (r *repository) GetUserSwapSummaryLast7Days(ctx context.Context, referenceTime time.Time, token string) ([]model.UserSwapPercentage, error) {
-	const query = `
-		WITH total_usd AS (
-			SELECT SUM(usd_value) AS sum_usd_value
-			FROM swap_history
-			WHERE last_updated BETWEEN $1 AND $2 AND token = $3
-		)
-		SELECT 
+var getUserSwapSummaryLast7DaysQuery = queries.Add("GetUserSwapSummaryLast7Days", `
+	WITH volumes AS (
+		SELECT account, SUM(counted_usd_value) AS counted_usd, 0 AS wash_usd
+		FROM daily_user_pool_stats
+		WHERE day > $1 AND day <= $2 AND token = $3 AND project_id = $7
+		GROUP BY account
+		UNION ALL
+		SELECT account, 0 AS counted_usd, SUM(counted_usd_value) AS wash_usd
+		FROM swap_history
+		WHERE wash_trade AND token = $3 AND last_updated >= $1::timestamptz + interval '1 day' AND last_updated < $2::timestamptz + interval '1 day'
+		GROUP BY account
+	), totals AS (
+		SELECT
 			account,
-			SUM(usd_value) AS total_usd,
-			(SUM(usd_value) / total_usd.sum_usd_value) AS percentage
-		FROM swap_history, total_usd
-		WHERE last_updated BETWEEN $1 AND $2 AND token = $3
-		GROUP BY account, total_usd.sum_usd_value
-		ORDER BY total_usd DESC
-	`
+			SUM(counted_usd) - CASE WHEN $4::boolean THEN SUM(wash_usd) ELSE 0 END AS total_usd,
+			SUM(wash_usd) AS wash_usd
+		FROM volumes
+		WHERE NOT (account = ANY($5))
+			AND NOT EXISTS (SELECT 1 FROM address_labels WHERE address_labels.project_id = $7 AND address_labels.address = volumes.account AND label = ANY($6) AND deleted_at IS NULL)
+		GROUP BY account
+	)
+	SELECT
+		account,
+		total_usd,
+		wash_usd,
+		COALESCE(total_usd / NULLIF(SUM(total_usd) OVER (), 0), 0) AS percentage
+	FROM totals
+	WHERE total_usd > 0 OR wash_usd > 0
+	ORDER BY total_usd DESC, account
+`)
 
-	startTime := referenceTime.AddDate(0, 0, -7)
-	endTime := referenceTime
+// GetUserSwapSummaryLast7Days retrieves the total USD and percentage of swaps for each user over the past seven days for a specific token,
+// counting the USD value of swaps counted towards points. It reads from the daily rollups, so the window covers the seven UTC calendar days ending on the reference day.
+// The USD of wash trades is reported separately, and left out of the totals and percentages when excludeWashTrades is set;
+// a user with only wash trades then has a zero percentage. Excluded users are left out, so the percentages of the others add up to 1.
+// A window without swaps yields an empty slice.
+func (r *repository) GetUserSwapSummaryLast7Days(ctx context.Context, referenceTime time.Time, token string, excludeWashTrades bool, exclude model.Exclusion) ([]model.UserSwapPercentage, error) {
+	endTime := rollupDay(referenceTime)
+	startTime := endTime.AddDate(0, 0, -7)
 
-	rows, err := r.db.Query(ctx, query, startTime, endTime, token)
+	addresses, labels := exclusionArgs(exclude)
+	rows, err := r.db.Query(ctx, getUserSwapSummaryLast7DaysQuery, startTime, endTime, token, excludeWashTrades, addresses, labels, r.project)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve user swap percentages: %w", err)
+		return nil, fmt.Errorf("failed to retrieve user swap percentages: %w", dbError(err))
 	}
 	defer rows.Close()
 
-	var results []model.UserSwapPercentage
+	results := []model.UserSwapPercentage{}
 	for rows.Next() {
 		var usp model.UserSwapPercentage
-		if err := rows.Scan(&usp.Account, &usp.TotalUSD, &usp.Percentage); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+		if err := rows.Scan(&usp.Account, &usp.TotalUSD, &usp.WashUSD, &usp.Percentage); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
 		}
 		results = append(results, usp)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
 	}
 
 	return results, nil
 }
+
+var getPoolVolumeStatsQuery = queries.Add("GetPoolVolumeStats", `
+	SELECT COALESCE(SUM(usd_value), 0), COUNT(*), COUNT(DISTINCT account)
+	FROM swap_history
+	WHERE token = $1 AND last_updated >= $2
+`)
+
+// GetPoolVolumeStats retrieves the USD volume, swap count and unique accounts of a pool since the given time.
+func (r *repository) GetPoolVolumeStats(ctx context.Context, token string, since time.Time) (*model.PoolVolumeStats, error) {
+	stats := &model.PoolVolumeStats{}
+	err := r.db.QueryRow(ctx, getPoolVolumeStatsQuery, token, since).Scan(&stats.VolumeUsd, &stats.SwapCount, &stats.UniqueAccounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool volume stats: %w", dbError(err))
+	}
+
+	return stats, nil
+}
+
+var getPoolTopTradersQuery = queries.Add("GetPoolTopTraders", `
+	WITH traders AS (
+		SELECT account, SUM(usd_value) AS total_usd, COUNT(*) AS swap_count
+		FROM swap_history
+		WHERE token = $1 AND last_updated >= $2
+		GROUP BY account
+		ORDER BY total_usd DESC
+		LIMIT $3
+	)
+	SELECT traders.account, COALESCE(array_agg(address_labels.label ORDER BY address_labels.label) FILTER (WHERE address_labels.label IS NOT NULL), '{}'),
+		traders.total_usd, traders.swap_count
+	FROM traders
+	LEFT JOIN address_labels ON address_labels.project_id = $4 AND address_labels.address = traders.account AND address_labels.deleted_at IS NULL
+	GROUP BY traders.account, traders.total_usd, traders.swap_count
+	ORDER BY traders.total_usd DESC
+`)
+
+// GetPoolTopTraders retrieves the accounts with the highest USD volume in a pool since the given time, with their labels.
+func (r *repository) GetPoolTopTraders(ctx context.Context, token string, since time.Time, limit int) ([]model.TraderVolume, error) {
+	rows, err := r.db.Query(ctx, getPoolTopTradersQuery, token, since, limit, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pool top traders: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	traders := make([]model.TraderVolume, 0, limit)
+	for rows.Next() {
+		var tv model.TraderVolume
+		if err := rows.Scan(&tv.Account, &tv.Labels, &tv.TotalUSD, &tv.SwapCount); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		traders = append(traders, tv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return traders, nil
+}
+
+var getSwapHistoryPageQuery = queries.Add("GetSwapHistoryPage", `
+	SELECT id, network, token, account, transaction_hash, usd_value, counted_usd_value, wash_trade, last_updated, created_at
+	FROM swap_history
+	WHERE account = $1
+		AND ($2::timestamptz IS NULL OR (created_at, id) < ($2::timestamptz, $3::int))
+	ORDER BY created_at DESC, id DESC
+	LIMIT $4
+`)
+
+// GetSwapHistoryPage retrieves one page of swap history for the specified account, newest first.
+func (r *repository) GetSwapHistoryPage(ctx context.Context, account, cursor string, limit int) ([]model.SwapHistory, string, error) {
+	afterTime, afterID, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := r.db.Query(ctx, getSwapHistoryPageQuery, account, afterTime, afterID, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query swap history page: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	histories := make([]model.SwapHistory, 0, limit+1)
+	for rows.Next() {
+		var sh model.SwapHistory
+		if err := rows.Scan(
+			&sh.ID,
+			&sh.Network,
+			&sh.Token,
+			&sh.Account,
+			&sh.TransactionHash,
+			&sh.UsdValue,
+			&sh.CountedUsdValue,
+			&sh.WashTrade,
+			&sh.LastUpdated,
+			&sh.CreatedAt,
+		); err != nil {
+			return nil, "", fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		histories = append(histories, sh)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	histories, next := nextPage(histories, limit, func(sh model.SwapHistory) string {
+		return EncodeTimeCursor(sh.CreatedAt, sh.ID)
+	})
+
+	return histories, next, nil
+}
@@ -3,6 +3,7 @@ package repository_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"hw/internal/repository"
 	pgMock "hw/pkg/pg/mocks"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -25,26 +27,26 @@ func TestCreateSwapHistory_Success(t *testing.T) {
 
 	ctx := context.Background()
 	swapHistory := &model.SwapHistory{
+		Network:         "mainnet",
 		Token:           "tokenABC",
 		Account:         "accountXYZ",
 		TransactionHash: "tx123456",
-		UsdValue:        250.75,
+		LogIndex:        4,
+		UsdValue:        model.NewDecimalFromFloat(250.75),
+		CountedUsdValue: model.NewDecimalFromFloat(100),
 		LastUpdated:     time.Now(),
 	}
 
-	const query = `
-		INSERT INTO swap_history (token, account, transaction_hash, usd_value, last_updated)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
-	`
-
 	mockDB.EXPECT().QueryRow(
 		ctx,
-		query,
+		pgMock.Query("CreateSwapHistory"),
+		swapHistory.Network,
 		swapHistory.Token,
 		swapHistory.Account,
 		swapHistory.TransactionHash,
+		swapHistory.LogIndex,
 		swapHistory.UsdValue,
+		swapHistory.CountedUsdValue,
 		swapHistory.LastUpdated,
 	).Return(mockRow)
 
@@ -76,26 +78,26 @@ func TestCreateSwapHistory_Failure(t *testing.T) {
 
 	ctx := context.Background()
 	swapHistory := &model.SwapHistory{
+		Network:         "mainnet",
 		Token:           "tokenABC",
 		Account:         "accountXYZ",
 		TransactionHash: "tx123456",
-		UsdValue:        250.75,
+		LogIndex:        4,
+		UsdValue:        model.NewDecimalFromFloat(250.75),
+		CountedUsdValue: model.NewDecimalFromFloat(100),
 		LastUpdated:     time.Now(),
 	}
 
-	const query = `
-		INSERT INTO swap_history (token, account, transaction_hash, usd_value, last_updated)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
-	`
-
 	mockDB.EXPECT().QueryRow(
 		ctx,
-		query,
+		pgMock.Query("CreateSwapHistory"),
+		swapHistory.Network,
 		swapHistory.Token,
 		swapHistory.Account,
 		swapHistory.TransactionHash,
+		swapHistory.LogIndex,
 		swapHistory.UsdValue,
+		swapHistory.CountedUsdValue,
 		swapHistory.LastUpdated,
 	).Return(nil).DoAndReturn(func(ctx context.Context, query string, args ...interface{}) *pgMock.MockPgxRows {
 		mockRow := pgMock.NewMockPgxRows(ctrl)
@@ -109,8 +111,9 @@ func TestCreateSwapHistory_Failure(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to create swap history")
 }
 
-// TestGetSwapTotalUsd_Success tests the successful retrieval of total USD value.
-func TestGetSwapTotalUsd_Success(t *testing.T) {
+// TestCreateSwapHistory_AlreadyRecorded tests that a swap whose log was already recorded is reported as
+// model.ErrAlreadyExists.
+func TestCreateSwapHistory_AlreadyRecorded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	mockDB := pgMock.NewMockPgxPool(ctrl)
@@ -119,27 +122,61 @@ func TestGetSwapTotalUsd_Success(t *testing.T) {
 	repo := repository.NewRepository(mockDB)
 
 	ctx := context.Background()
-	account := "accountXYZ"
-	token := "tokenABC"
-	expectedTotalUsd := 1000.50
+	swapHistory := &model.SwapHistory{Network: "mainnet", TransactionHash: "tx123456", LogIndex: 4, LastUpdated: time.Now()}
 
-	const query = `
-		SELECT SUM(usd_value)
-		FROM swap_history
-		WHERE account = $1 AND token = $2
-	`
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("CreateSwapHistory"), gomock.Any(), gomock.Any(), gomock.Any(), "tx123456", uint(4), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
 
-	mockDB.EXPECT().QueryRow(ctx, query, account, token).Return(mockRow)
+	err := repo.CreateSwapHistory(ctx, swapHistory)
 
-	mockRow.EXPECT().Scan(gomock.AssignableToTypeOf(&expectedTotalUsd)).DoAndReturn(func(dest ...any) error {
-		*(dest[0].(*float64)) = expectedTotalUsd
-		return nil
-	})
+	assert.ErrorIs(t, err, model.ErrAlreadyExists)
+	assert.Zero(t, swapHistory.ID)
+}
 
-	totalUsd, err := repo.GetSwapTotalUsd(ctx, account, token)
+// TestGetSwapTotalUsd_Success tests the retrieval of total USD value, and an empty total for an account without swaps.
+func TestGetSwapTotalUsd_Success(t *testing.T) {
 
-	assert.NoError(t, err)
-	assert.Equal(t, expectedTotalUsd, totalUsd)
+	tests := []struct {
+		name      string
+		usdValue  model.Decimal
+		washValue model.Decimal
+		swapCount int64
+		empty     bool
+	}{
+		{name: "swaps", usdValue: model.NewDecimalFromFloat(1000.50), washValue: model.NewDecimalFromFloat(400), swapCount: 3},
+		{name: "no swaps", usdValue: model.ZeroDecimal, empty: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRow := pgMock.NewMockPgxRows(ctrl)
+
+			repo := repository.NewRepository(mockDB)
+
+			ctx := context.Background()
+			account := "accountXYZ"
+			token := "tokenABC"
+
+			mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetSwapTotalUsd"), account, token).Return(mockRow)
+
+			mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+				*(dest[0].(*model.Decimal)) = tt.usdValue
+				*(dest[1].(*model.Decimal)) = tt.washValue
+				*(dest[2].(*int64)) = tt.swapCount
+				return nil
+			})
+
+			total, err := repo.GetSwapTotalUsd(ctx, account, token)
+
+			assert.NoError(t, err)
+			assert.Equal(t, model.SwapTotal{UsdValue: tt.usdValue, WashUsdValue: tt.washValue, SwapCount: tt.swapCount}, total)
+			assert.Equal(t, tt.empty, total.Empty())
+		})
+	}
 }
 
 // TestGetSwapTotalUsd_Failure tests the failure scenario when retrieving total USD value.
@@ -155,23 +192,82 @@ func TestGetSwapTotalUsd_Failure(t *testing.T) {
 	account := "accountXYZ"
 	token := "tokenABC"
 
-	const query = `
-		SELECT SUM(usd_value)
-		FROM swap_history
-		WHERE account = $1 AND token = $2
-	`
-
-	mockDB.EXPECT().QueryRow(ctx, query, account, token).Return(mockRow)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetSwapTotalUsd"), account, token).Return(mockRow)
 
-	mockRow.EXPECT().Scan(gomock.Any()).Return(errors.New("scan error"))
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("scan error"))
 
 	totalUsd, err := repo.GetSwapTotalUsd(ctx, account, token)
 
 	assert.Error(t, err)
-	assert.Equal(t, float64(0), totalUsd)
+	assert.True(t, totalUsd.Empty())
 	assert.Contains(t, err.Error(), "failed to get total swap USD")
 }
 
+// TestFlagRoundTripSwaps tests that the legs of a round trip are reported flagged, and a single swap is not.
+func TestFlagRoundTripSwaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		legs    int
+		flagged bool
+	}{
+		{name: "round trip", legs: 2, flagged: true},
+		{name: "single swap", legs: 0, flagged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRows := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+
+			ctx := context.Background()
+			blockTime := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+			swapHistory := &model.SwapHistory{Network: "mainnet", Token: "tokenABC", Account: "accountXYZ", TransactionHash: "0xtx", LastUpdated: blockTime}
+
+			mockDB.EXPECT().Query(ctx, pgMock.Query("FlagRoundTripSwaps"), "0xtx", "mainnet", "tokenABC", "accountXYZ", blockTime).Return(mockRows, nil)
+			mockRows.EXPECT().Next().Return(true).Times(tt.legs)
+			mockRows.EXPECT().Next().Return(false)
+			mockRows.EXPECT().Err().Return(nil)
+			mockRows.EXPECT().Close()
+
+			flagged, err := repo.FlagRoundTripSwaps(ctx, swapHistory)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.flagged, flagged)
+		})
+	}
+}
+
+// TestFlagRoundTripSwaps_DuplicatedLeg tests that the legs are counted by log, so a swap whose log was recorded
+// twice is not flagged as a round trip of its own.
+func TestFlagRoundTripSwaps_DuplicatedLeg(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	blockTime := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	swapHistory := &model.SwapHistory{Network: "mainnet", Token: "tokenABC", Account: "accountXYZ", TransactionHash: "0xtx", LogIndex: 4, LastUpdated: blockTime}
+
+	countsLogs := gomock.Cond(func(x any) bool {
+		query, _ := x.(string)
+		return strings.Contains(query, "COUNT(DISTINCT (transaction_hash, log_index)) FROM legs) > 1")
+	})
+	mockDB.EXPECT().Query(ctx, gomock.All(pgMock.Query("FlagRoundTripSwaps"), countsLogs), "0xtx", "mainnet", "tokenABC", "accountXYZ", blockTime).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	flagged, err := repo.FlagRoundTripSwaps(ctx, swapHistory)
+
+	assert.NoError(t, err)
+	assert.False(t, flagged)
+}
+
 // TestGetUserSwapSummary_Success tests the successful retrieval of user swap summary.
 func TestGetUserSwapSummary_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -184,19 +280,12 @@ func TestGetUserSwapSummary_Success(t *testing.T) {
 	ctx := context.Background()
 	account := "accountXYZ"
 
-	const query = `
-		SELECT token, SUM(usd_value)
-		FROM swap_history
-		WHERE account = $1
-		GROUP BY token
-	`
-
-	mockDB.EXPECT().Query(ctx, query, account).Return(mockRows, nil)
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetUserSwapSummary"), account).Return(mockRows, nil)
 
 	mockRows.EXPECT().Next().Return(true)
 	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
 		*(dest[0].(*string)) = "tokenABC"
-		*(dest[1].(*float64)) = 1000.50
+		*(dest[1].(*model.Decimal)) = model.NewDecimalFromFloat(1000.50)
 		return nil
 	})
 	mockRows.EXPECT().Next().Return(false)
@@ -207,7 +296,7 @@ func TestGetUserSwapSummary_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Len(t, summary, 1)
-	assert.Equal(t, 1000.50, summary["tokenABC"])
+	assert.Equal(t, "1000.5", summary["tokenABC"].String())
 }
 
 // TestGetUserSwapSummary_Failure tests the failure scenario when retrieving user swap summary.
@@ -221,14 +310,7 @@ func TestGetUserSwapSummary_Failure(t *testing.T) {
 	ctx := context.Background()
 	account := "accountXYZ"
 
-	const query = `
-		SELECT token, SUM(usd_value)
-		FROM swap_history
-		WHERE account = $1
-		GROUP BY token
-	`
-
-	mockDB.EXPECT().Query(ctx, query, account).Return(nil, errors.New("query error"))
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetUserSwapSummary"), account).Return(nil, errors.New("query error"))
 
 	summary, err := repo.GetUserSwapSummary(ctx, account)
 
@@ -237,6 +319,97 @@ func TestGetUserSwapSummary_Failure(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to retrieve token USD sums")
 }
 
+// TestGetUserPools tests that the swap volume of every pool is read with its points in one query,
+// and that a pool without points has no tasks.
+func TestGetUserPools(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	account := "accountXYZ"
+	swapTask, onboardingTask := "swap_task", "onboarding_task"
+	rows := []struct {
+		token       string
+		usdValue    float64
+		description *string
+		points      float64
+	}{
+		{"tokenABC", 1000.50, &swapTask, 10.5},
+		{"tokenABC", 1000.50, &onboardingTask, 100},
+		{"tokenXYZ", 500.25, nil, 0},
+	}
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetUserPools"), account, "mainnet", model.DefaultProjectID).Return(mockRows, nil)
+	for _, row := range rows {
+		mockRows.EXPECT().Next().Return(true)
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*string)) = row.token
+			*(dest[1].(*model.Decimal)) = model.NewDecimalFromFloat(row.usdValue)
+			*(dest[2].(**string)) = row.description
+			*(dest[3].(*model.Decimal)) = model.NewDecimalFromFloat(row.points)
+			return nil
+		})
+	}
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	pools, err := repo.GetUserPools(ctx, account, "mainnet")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*model.UserPool{
+		"tokenABC": {
+			TotalUsdValue: model.NewDecimalFromFloat(1000.50),
+			Points:        model.NewDecimalFromFloat(110.5),
+			Tasks: []model.UserTask{
+				{Description: "swap_task", Points: model.NewDecimalFromFloat(10.5)},
+				{Description: "onboarding_task", Points: model.NewDecimalFromFloat(100)},
+			},
+		},
+		"tokenXYZ": {
+			TotalUsdValue: model.NewDecimalFromFloat(500.25),
+			Tasks:         []model.UserTask{},
+		},
+	}, pools)
+}
+
+// TestGetUserNetworkSummary_Success tests the successful retrieval of the per-network user summary.
+func TestGetUserNetworkSummary_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	account := "accountXYZ"
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), account).Return(mockRows, nil)
+
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "mainnet"
+		*(dest[1].(*model.Decimal)) = model.NewDecimalFromFloat(1000.50)
+		*(dest[2].(*model.Decimal)) = model.NewDecimalFromFloat(250)
+		*(dest[3].(*model.Decimal)) = model.NewDecimalFromFloat(100)
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	summary, err := repo.GetUserNetworkSummary(ctx, account)
+
+	assert.NoError(t, err)
+	assert.Len(t, summary, 1)
+	assert.Equal(t, model.NetworkSummary{UsdValue: model.NewDecimalFromFloat(1000.50), WashUsdValue: model.NewDecimalFromFloat(250), Points: model.NewDecimalFromFloat(100)}, summary["mainnet"])
+}
+
 // TestGetUserSwapSummaryLast7Days_Success tests the successful retrieval of user swap summary for the last 7 days.
 func TestGetUserSwapSummaryLast7Days_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -247,48 +420,57 @@ func TestGetUserSwapSummaryLast7Days_Success(t *testing.T) {
 	repo := repository.NewRepository(mockDB)
 
 	ctx := context.Background()
-	referenceTime := time.Now()
+	referenceTime := time.Date(2024, 10, 2, 15, 30, 0, 0, time.UTC)
 	token := "tokenABC"
 
-	const query = `
-		WITH total_usd AS (
-			SELECT SUM(usd_value) AS sum_usd_value
-			FROM swap_history
-			WHERE last_updated BETWEEN $1 AND $2 AND token = $3
-		)
-		SELECT 
-			account,
-			SUM(usd_value) AS total_usd,
-			(SUM(usd_value) / total_usd.sum_usd_value) AS percentage
-		FROM swap_history, total_usd
-		WHERE last_updated BETWEEN $1 AND $2 AND token = $3
-		GROUP BY account, total_usd.sum_usd_value
-		ORDER BY total_usd DESC
-	`
-
-	startTime := referenceTime.AddDate(0, 0, -7)
-	endTime := referenceTime
-
-	mockDB.EXPECT().Query(ctx, query, startTime, endTime, token).Return(mockRows, nil)
+	endTime := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	startTime := time.Date(2024, 9, 25, 0, 0, 0, 0, time.UTC)
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetUserSwapSummaryLast7Days"), startTime, endTime, token, true, []string{}, []string{}, model.DefaultProjectID).Return(mockRows, nil)
 
 	mockRows.EXPECT().Next().Return(true)
-	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
 		*(dest[0].(*string)) = "accountXYZ"
-		*(dest[1].(*float64)) = 1000.50
-		*(dest[2].(*float64)) = 0.75
+		*(dest[1].(*model.Decimal)) = model.NewDecimalFromFloat(1000.50)
+		*(dest[2].(*model.Decimal)) = model.NewDecimalFromFloat(200)
+		*(dest[3].(*model.Decimal)) = model.NewDecimalFromFloat(0.75)
 		return nil
 	})
 	mockRows.EXPECT().Next().Return(false)
 	mockRows.EXPECT().Err().Return(nil)
 	mockRows.EXPECT().Close()
 
-	summary, err := repo.GetUserSwapSummaryLast7Days(ctx, referenceTime, token)
+	summary, err := repo.GetUserSwapSummaryLast7Days(ctx, referenceTime, token, true, model.Exclusion{})
 
 	assert.NoError(t, err)
 	assert.Len(t, summary, 1)
 	assert.Equal(t, "accountXYZ", summary[0].Account)
-	assert.Equal(t, 1000.50, summary[0].TotalUSD)
-	assert.Equal(t, 0.75, summary[0].Percentage)
+	assert.Equal(t, model.NewDecimalFromFloat(1000.50), summary[0].TotalUSD)
+	assert.Equal(t, model.NewDecimalFromFloat(200), summary[0].WashUSD)
+	assert.Equal(t, model.NewDecimalFromFloat(0.75), summary[0].Percentage)
+}
+
+// TestGetUserSwapSummaryLast7Days_NoSwaps tests that a window without swaps yields an empty summary rather than nil.
+func TestGetUserSwapSummaryLast7Days_NoSwaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any(), "tokenABC", false, []string{}, []string{}, model.DefaultProjectID).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	summary, err := repo.GetUserSwapSummaryLast7Days(ctx, time.Date(2024, 10, 2, 15, 30, 0, 0, time.UTC), "tokenABC", false, model.Exclusion{})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, summary)
+	assert.Empty(t, summary)
 }
 
 // TestGetUserSwapSummaryLast7Days_Failure tests the failure scenario when retrieving user swap summary for the last 7 days.
@@ -300,33 +482,126 @@ func TestGetUserSwapSummaryLast7Days_Failure(t *testing.T) {
 	repo := repository.NewRepository(mockDB)
 
 	ctx := context.Background()
-	referenceTime := time.Now()
+	referenceTime := time.Date(2024, 10, 2, 15, 30, 0, 0, time.UTC)
 	token := "tokenABC"
 
-	const query = `
-		WITH total_usd AS (
-			SELECT SUM(usd_value) AS sum_usd_value
-			FROM swap_history
-			WHERE last_updated BETWEEN $1 AND $2 AND token = $3
-		)
-		SELECT 
-			account,
-			SUM(usd_value) AS total_usd,
-			(SUM(usd_value) / total_usd.sum_usd_value) AS percentage
-		FROM swap_history, total_usd
-		WHERE last_updated BETWEEN $1 AND $2 AND token = $3
-		GROUP BY account, total_usd.sum_usd_value
-		ORDER BY total_usd DESC
-	`
-
-	startTime := referenceTime.AddDate(0, 0, -7)
-	endTime := referenceTime
-
-	mockDB.EXPECT().Query(ctx, query, startTime, endTime, token).Return(nil, errors.New("query error"))
-
-	summary, err := repo.GetUserSwapSummaryLast7Days(ctx, referenceTime, token)
+	endTime := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	startTime := time.Date(2024, 9, 25, 0, 0, 0, 0, time.UTC)
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetUserSwapSummaryLast7Days"), startTime, endTime, token, true, []string{}, []string{}, model.DefaultProjectID).Return(nil, errors.New("query error"))
+
+	summary, err := repo.GetUserSwapSummaryLast7Days(ctx, referenceTime, token, true, model.Exclusion{})
 
 	assert.Error(t, err)
 	assert.Nil(t, summary)
 	assert.Contains(t, err.Error(), "failed to retrieve user swap percentages")
 }
+
+// TestGetPoolVolumeStats_Success tests the successful retrieval of pool volume statistics.
+func TestGetPoolVolumeStats_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	token := "tokenABC"
+	since := time.Now().Add(-24 * time.Hour)
+
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetPoolVolumeStats"), token, since).Return(mockRow)
+
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*model.Decimal)) = model.NewDecimalFromFloat(2500.25)
+		*(dest[1].(*int64)) = 12
+		*(dest[2].(*int64)) = 4
+		return nil
+	})
+
+	stats, err := repo.GetPoolVolumeStats(ctx, token, since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.NewDecimalFromFloat(2500.25), stats.VolumeUsd)
+	assert.Equal(t, int64(12), stats.SwapCount)
+	assert.Equal(t, int64(4), stats.UniqueAccounts)
+}
+
+// TestGetPoolVolumeStats_Failure tests the failure scenario when retrieving pool volume statistics.
+func TestGetPoolVolumeStats_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	since := time.Now()
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "tokenABC", since).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("scan error"))
+
+	stats, err := repo.GetPoolVolumeStats(ctx, "tokenABC", since)
+
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+	assert.Contains(t, err.Error(), "failed to get pool volume stats")
+}
+
+// TestGetPoolTopTraders_Success tests the successful retrieval of pool top traders.
+func TestGetPoolTopTraders_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	token := "tokenABC"
+	since := time.Now().Add(-30 * 24 * time.Hour)
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetPoolTopTraders"), token, since, 5, model.DefaultProjectID).Return(mockRows, nil)
+
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*string)) = "accountXYZ"
+		*(dest[1].(*[]string)) = []string{model.LabelRouter}
+		*(dest[2].(*model.Decimal)) = model.NewDecimalFromFloat(1000.50)
+		*(dest[3].(*int64)) = 3
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	traders, err := repo.GetPoolTopTraders(ctx, token, since, 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, traders, 1)
+	assert.Equal(t, "accountXYZ", traders[0].Account)
+	assert.Equal(t, []string{model.LabelRouter}, traders[0].Labels)
+	assert.Equal(t, model.NewDecimalFromFloat(1000.50), traders[0].TotalUSD)
+	assert.Equal(t, int64(3), traders[0].SwapCount)
+}
+
+// TestGetPoolTopTraders_Failure tests the failure scenario when retrieving pool top traders.
+func TestGetPoolTopTraders_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	since := time.Now()
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "tokenABC", since, 5, model.DefaultProjectID).Return(nil, errors.New("query error"))
+
+	traders, err := repo.GetPoolTopTraders(ctx, "tokenABC", since, 5)
+
+	assert.Error(t, err)
+	assert.Nil(t, traders)
+	assert.Contains(t, err.Error(), "failed to retrieve pool top traders")
+}
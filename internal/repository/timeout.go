@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hw/pkg/pg"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// WithQueryTimeout gives every query of the repository outside a transaction a deadline of
+// timeout, unless its context ends earlier, so a slow query fails with model.ErrQueryTimeout
+// instead of holding its connection. The statements of a transaction are bounded by the
+// statement_timeout of the session instead. Zero disables the deadline.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(r *repository) {
+		if timeout > 0 {
+			r.db = &timeoutPool{PgxPool: r.db, timeout: timeout}
+		}
+	}
+}
+
+// timeoutPool runs the queries of a pool with a deadline, released once their result is read.
+type timeoutPool struct {
+	pg.PgxPool
+	timeout time.Duration
+}
+
+func (p *timeoutPool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return p.PgxPool.Exec(ctx, sql, arguments...)
+}
+
+func (p *timeoutPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	rows, err := p.PgxPool.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (p *timeoutPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	return &timeoutRow{row: p.PgxPool.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+// timeoutRows releases the deadline of a query when its rows are read or closed.
+type timeoutRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.cancel()
+	return false
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// timeoutRow releases the deadline of a query when its row is scanned.
+type timeoutRow struct {
+	row    pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return r.row.Scan(dest...)
+}
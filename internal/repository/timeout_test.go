@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestWithQueryTimeout tests that queries run with a deadline released once their result is read,
+// and that a query past its deadline fails with model.ErrQueryTimeout.
+func TestWithQueryTimeout(t *testing.T) {
+	t.Run("row", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := pgMock.NewMockPgxPool(ctrl)
+		mockRow := pgMock.NewMockPgxRows(ctrl)
+		repo := repository.NewRepository(mockDB, repository.WithQueryTimeout(time.Minute))
+
+		var queryCtx context.Context
+		mockDB.EXPECT().QueryRow(gomock.Any(), gomock.Any(), "0xabc").DoAndReturn(func(ctx context.Context, sql string, args ...any) pgx.Row {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+			queryCtx = ctx
+			return mockRow
+		})
+		mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(context.DeadlineExceeded)
+
+		_, err := repo.CreateUser(context.Background(), "0xabc")
+		assert.ErrorIs(t, err, model.ErrQueryTimeout)
+		assert.True(t, model.Retryable(err))
+		assert.ErrorIs(t, queryCtx.Err(), context.Canceled)
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := pgMock.NewMockPgxPool(ctrl)
+		mockRows := pgMock.NewMockPgxRows(ctrl)
+		repo := repository.NewRepository(mockDB, repository.WithQueryTimeout(time.Minute))
+
+		var queryCtx context.Context
+		mockDB.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+			queryCtx = ctx
+			return mockRows, nil
+		})
+		mockRows.EXPECT().Next().Return(false)
+		mockRows.EXPECT().Err().Return(nil)
+		mockRows.EXPECT().Close()
+
+		labels, err := repo.GetAddressLabels(context.Background(), "", "")
+		assert.NoError(t, err)
+		assert.Empty(t, labels)
+		assert.ErrorIs(t, queryCtx.Err(), context.Canceled)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := pgMock.NewMockPgxPool(ctrl)
+		mockRow := pgMock.NewMockPgxRows(ctrl)
+		repo := repository.NewRepository(mockDB, repository.WithQueryTimeout(0))
+		ctx := context.Background()
+
+		mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "0xabc").Return(mockRow)
+		mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := repo.CreateUser(ctx, "0xabc")
+		assert.NoError(t, err)
+	})
+}
@@ -3,22 +3,23 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"hw/internal/model"
 
 	"github.com/jackc/pgx/v5"
 )
 
+var getTokenByAddressQuery = queries.Add("GetTokenByAddress", `
+	SELECT id, name, symbol, decimals, created_at
+	FROM tokens
+	WHERE id = $1
+`)
+
 // GetTokenByAddress retrieves a token by its address from the database.
 func (r *repository) GetTokenByAddress(ctx context.Context, address string) (*model.Token, error) {
-	const query = `
-		SELECT id, name, symbol, decimals, created_at
-		FROM tokens
-		WHERE id = $1
-	`
-
 	token := &model.Token{}
-	err := r.db.QueryRow(ctx, query, address).Scan(
+	err := r.db.QueryRow(ctx, getTokenByAddressQuery, address).Scan(
 		&token.ID,
 		&token.Name,
 		&token.Symbol,
@@ -29,23 +30,54 @@ func (r *repository) GetTokenByAddress(ctx context.Context, address string) (*mo
 		if err == pgx.ErrNoRows {
 			return nil, model.ErrTokenNotFound
 		}
-		return nil, fmt.Errorf("failed to retrieve token: %w", err)
+		return nil, fmt.Errorf("failed to retrieve token: %w", dbError(err))
 	}
 
 	return token, nil
 }
 
+var getTokensByAddressesQuery = queries.Add("GetTokensByAddresses", `
+	SELECT id, name, symbol, decimals, created_at
+	FROM tokens
+	WHERE id = ANY($1)
+`)
+
+// GetTokensByAddresses retrieves the tokens of several addresses in one query; unknown
+// addresses are left out.
+func (r *repository) GetTokensByAddresses(ctx context.Context, addresses []string) ([]model.Token, error) {
+	rows, err := r.db.Query(ctx, getTokensByAddressesQuery, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tokens: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var tokens []model.Token
+	for rows.Next() {
+		var token model.Token
+		if err := rows.Scan(&token.ID, &token.Name, &token.Symbol, &token.Decimals, &token.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan token: %w", dbError(err))
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return tokens, nil
+}
+
+var createTokenQuery = queries.Add("CreateToken", `
+	INSERT INTO tokens (id, name, symbol, decimals)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at
+`)
+
 // CreateToken inserts a new token into the database.
 func (r *repository) CreateToken(ctx context.Context, token *model.Token) error {
-	const query = `
-		INSERT INTO tokens (id, name, symbol, decimals)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at
-	`
-
 	err := r.db.QueryRow(
 		ctx,
-		query,
+		createTokenQuery,
 		token.ID,
 		token.Name,
 		token.Symbol,
@@ -55,8 +87,111 @@ func (r *repository) CreateToken(ctx context.Context, token *model.Token) error
 		&token.CreatedAt,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create token: %s %w", token.ID, err)
+		return fmt.Errorf("failed to create token: %s %w", token.ID, dbError(err))
 	}
 
 	return nil
 }
+
+// tokenVolumeColumns selects a token and its all-time swap volume from tokens aliased t.
+const tokenVolumeColumns = `
+		t.id, t.name, t.symbol, t.decimals, t.created_at,
+		COALESCE(v.volume_usd, 0), COALESCE(v.swap_count, 0)
+	FROM tokens t
+	LEFT JOIN LATERAL (
+		SELECT SUM(usd_value) AS volume_usd, SUM(swap_count) AS swap_count
+		FROM (
+			SELECT usd_value, 1 AS swap_count FROM swap_history WHERE token = t.id
+			UNION ALL
+			SELECT usd_value, swap_count FROM swap_history_rollups WHERE token = t.id
+		) swaps
+	) v ON true
+`
+
+// likeEscaper escapes the LIKE wildcards of a search term.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+var listTokensQuery = queries.Add("ListTokens", `
+	SELECT`+tokenVolumeColumns+`
+	WHERE ($1::text IS NULL OR t.symbol ILIKE $1 OR t.name ILIKE $1)
+		AND ($2::text IS NULL OR t.id > $2)
+	ORDER BY t.id
+	LIMIT $3
+`)
+
+// ListTokens retrieves one page of tokens ordered by address, optionally filtered by a
+// case-insensitive substring of the symbol or name. The cursor key is the last token's address.
+func (r *repository) ListTokens(ctx context.Context, search, cursor string, limit int) ([]model.TokenVolume, string, error) {
+	var pattern, after *string
+	if search != "" {
+		p := "%" + likeEscaper.Replace(search) + "%"
+		pattern = &p
+	}
+	c, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if c != nil {
+		after = &c.Key
+	}
+
+	rows, err := r.db.Query(ctx, listTokensQuery, pattern, after, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list tokens: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	tokens := make([]model.TokenVolume, 0, limit+1)
+	for rows.Next() {
+		var token model.TokenVolume
+		if err := rows.Scan(
+			&token.ID,
+			&token.Name,
+			&token.Symbol,
+			&token.Decimals,
+			&token.CreatedAt,
+			&token.VolumeUsd,
+			&token.SwapCount,
+		); err != nil {
+			return nil, "", fmt.Errorf("failed to scan token: %w", dbError(err))
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	tokens, next := nextPage(tokens, limit, func(t model.TokenVolume) string {
+		return EncodeCursor(t.ID, 0)
+	})
+
+	return tokens, next, nil
+}
+
+var getTokenVolumeQuery = queries.Add("GetTokenVolume", `
+	SELECT`+tokenVolumeColumns+`
+	WHERE t.id = $1
+`)
+
+// GetTokenVolume retrieves a token with its all-time swap volume.
+func (r *repository) GetTokenVolume(ctx context.Context, address string) (*model.TokenVolume, error) {
+	token := &model.TokenVolume{}
+	err := r.db.QueryRow(ctx, getTokenVolumeQuery, address).Scan(
+		&token.ID,
+		&token.Name,
+		&token.Symbol,
+		&token.Decimals,
+		&token.CreatedAt,
+		&token.VolumeUsd,
+		&token.SwapCount,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve token volume: %w", dbError(err))
+	}
+
+	return token, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var upsertTokenPriceQuery = queries.Add("UpsertTokenPrice", `
+	INSERT INTO token_prices (network, token, bucket, open, high, low, close, updated_at)
+	VALUES ($1, $2, $3, $4, $4, $4, $4, NOW())
+	ON CONFLICT (token, network, bucket) DO UPDATE SET
+		high = GREATEST(token_prices.high, EXCLUDED.high),
+		low = LEAST(token_prices.low, EXCLUDED.low),
+		close = EXCLUDED.close,
+		updated_at = NOW()
+`)
+
+// UpsertTokenPrice records a price in the minute bucket of a token: the first price of the
+// bucket is its open, the last its close.
+func (r *repository) UpsertTokenPrice(ctx context.Context, price *model.TokenPrice) error {
+	_, err := r.db.Exec(ctx, upsertTokenPriceQuery, price.Network, price.Token, price.Time.UTC().Truncate(time.Minute), price.Price)
+	if err != nil {
+		return fmt.Errorf("failed to upsert token price: %w", dbError(err))
+	}
+
+	return nil
+}
+
+var getTokenPriceCandlesQuery = queries.Add("GetTokenPriceCandles", `
+	SELECT
+		to_timestamp(floor(extract(epoch FROM bucket) / $5) * $5) AS candle,
+		(array_agg(open ORDER BY bucket))[1],
+		MAX(high),
+		MIN(low),
+		(array_agg(close ORDER BY bucket DESC))[1]
+	FROM token_prices
+	WHERE token = $1 AND network = $2 AND bucket >= $3 AND bucket < $4
+	GROUP BY candle
+	ORDER BY candle
+`)
+
+// GetTokenPriceCandles aggregates the minute prices of a token within [from, to) into candles of
+// the given interval, ordered by time.
+func (r *repository) GetTokenPriceCandles(ctx context.Context, token, network string, from, to time.Time, interval time.Duration) ([]model.PriceCandle, error) {
+	rows, err := r.db.Query(ctx, getTokenPriceCandlesQuery, token, network, from, to, int64(interval.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve token price candles: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	candles := []model.PriceCandle{}
+	for rows.Next() {
+		var candle model.PriceCandle
+		if err := rows.Scan(&candle.Time, &candle.Open, &candle.High, &candle.Low, &candle.Close); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		candles = append(candles, candle)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return candles, nil
+}
+
+var getLatestTokenPriceQuery = queries.Add("GetLatestTokenPrice", `
+	SELECT close, bucket
+	FROM token_prices
+	WHERE token = $1 AND network = $2 AND bucket >= $3 AND bucket <= $4
+	ORDER BY bucket DESC
+	LIMIT 1
+`)
+
+// GetLatestTokenPrice retrieves the close of the latest minute bucket of a token within [since, at].
+func (r *repository) GetLatestTokenPrice(ctx context.Context, token, network string, since, at time.Time) (*model.TokenPrice, error) {
+	price := &model.TokenPrice{Network: network, Token: token}
+	err := r.db.QueryRow(ctx, getLatestTokenPriceQuery, token, network, since, at).Scan(&price.Price, &price.Time)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrPriceNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve latest token price: %w", dbError(err))
+	}
+
+	return price, nil
+}
@@ -0,0 +1,167 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestUpsertTokenPrice_Success tests that prices are bucketed by their UTC minute.
+func TestUpsertTokenPrice_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	price := &model.TokenPrice{
+		Network: "mainnet",
+		Token:   "tokenABC",
+		Price:   model.NewDecimalFromFloat(2500.25),
+		Time:    time.Date(2024, 10, 18, 9, 30, 45, 0, time.FixedZone("UTC+8", 8*3600)),
+	}
+
+	expectedBucket := time.Date(2024, 10, 18, 1, 30, 0, 0, time.UTC)
+	mockDB.EXPECT().
+		Exec(ctx, pgMock.Query("UpsertTokenPrice"), price.Network, price.Token, expectedBucket, price.Price).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+	err := repo.UpsertTokenPrice(ctx, price)
+
+	assert.NoError(t, err)
+}
+
+// TestUpsertTokenPrice_Failure tests the failure scenario when recording a price.
+func TestUpsertTokenPrice_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().
+		Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pgconn.CommandTag{}, errors.New("exec error"))
+
+	err := repo.UpsertTokenPrice(ctx, &model.TokenPrice{Time: time.Now()})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to upsert token price")
+}
+
+// TestGetTokenPriceCandles_Success tests that candles are aggregated over the interval in seconds.
+func TestGetTokenPriceCandles_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	to := time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-24 * time.Hour)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "tokenABC", "mainnet", from, to, int64(3600)).Return(mockRows, nil)
+
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*time.Time)) = from
+		*(dest[1].(*model.Decimal)) = model.NewDecimalFromFloat(2400)
+		*(dest[2].(*model.Decimal)) = model.NewDecimalFromFloat(2550)
+		*(dest[3].(*model.Decimal)) = model.NewDecimalFromFloat(2380)
+		*(dest[4].(*model.Decimal)) = model.NewDecimalFromFloat(2500)
+		return nil
+	})
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	candles, err := repo.GetTokenPriceCandles(ctx, "tokenABC", "mainnet", from, to, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.PriceCandle{{
+		Time:  from,
+		Open:  model.NewDecimalFromFloat(2400),
+		High:  model.NewDecimalFromFloat(2550),
+		Low:   model.NewDecimalFromFloat(2380),
+		Close: model.NewDecimalFromFloat(2500),
+	}}, candles)
+}
+
+// TestGetTokenPriceCandles_Failure tests the failure scenario when retrieving price candles.
+func TestGetTokenPriceCandles_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().
+		Query(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("query error"))
+
+	_, err := repo.GetTokenPriceCandles(ctx, "tokenABC", "mainnet", time.Now().Add(-time.Hour), time.Now(), time.Minute)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve token price candles")
+}
+
+// TestGetLatestTokenPrice tests the lookup of the latest price and the error without one.
+func TestGetLatestTokenPrice(t *testing.T) {
+	at := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	since := at.Add(-10 * time.Minute)
+
+	tests := []struct {
+		name    string
+		scanErr error
+		wantErr error
+	}{
+		{name: "found"},
+		{name: "not found", scanErr: pgx.ErrNoRows, wantErr: model.ErrPriceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockDB := pgMock.NewMockPgxPool(ctrl)
+			mockRow := pgMock.NewMockPgxRows(ctrl)
+			repo := repository.NewRepository(mockDB)
+
+			ctx := context.Background()
+
+			mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "tokenABC", "mainnet", since, at).Return(mockRow)
+			mockRow.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				if tt.scanErr != nil {
+					return tt.scanErr
+				}
+				*(dest[0].(*model.Decimal)) = model.NewDecimalFromFloat(2500)
+				*(dest[1].(*time.Time)) = at.Add(-time.Minute)
+				return nil
+			})
+
+			price, err := repo.GetLatestTokenPrice(ctx, "tokenABC", "mainnet", since, at)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, price)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &model.TokenPrice{Network: "mainnet", Token: "tokenABC", Price: model.NewDecimalFromFloat(2500), Time: at.Add(-time.Minute)}, price)
+		})
+	}
+}
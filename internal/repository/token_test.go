@@ -35,13 +35,7 @@ func TestGetTokenByAddress_Success(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 
-	const query = `
-		SELECT id, name, symbol, decimals, created_at
-		FROM tokens
-		WHERE id = $1
-	`
-
-	mockDB.EXPECT().QueryRow(ctx, query, address).Return(mockRow)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetTokenByAddress"), address).Return(mockRow)
 
 	mockRow.EXPECT().Scan(
 		gomock.Any(),
@@ -76,13 +70,7 @@ func TestGetTokenByAddress_NotFound(t *testing.T) {
 	ctx := context.Background()
 	address := "0x1234567890123456789012345678901234567890"
 
-	const query = `
-		SELECT id, name, symbol, decimals, created_at
-		FROM tokens
-		WHERE id = $1
-	`
-
-	mockDB.EXPECT().QueryRow(ctx, query, address).Return(mockRow)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetTokenByAddress"), address).Return(mockRow)
 
 	mockRow.EXPECT().Scan(
 		gomock.Any(),
@@ -99,6 +87,40 @@ func TestGetTokenByAddress_NotFound(t *testing.T) {
 	assert.Equal(t, model.ErrTokenNotFound, err)
 }
 
+// TestGetTokensByAddresses_Success tests retrieving several tokens with one query.
+func TestGetTokensByAddresses_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	addresses := []string{"0xusdc", "0xunknown"}
+	expected := model.Token{ID: "0xusdc", Name: "USD Coin", Symbol: "USDC", Decimals: 6}
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetTokensByAddresses"), addresses).Return(mockRows, nil)
+	gomock.InOrder(
+		mockRows.EXPECT().Next().Return(true),
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(dest ...any) error {
+				*(dest[0].(*string)) = expected.ID
+				*(dest[1].(*string)) = expected.Name
+				*(dest[2].(*string)) = expected.Symbol
+				*(dest[3].(*int64)) = expected.Decimals
+				return nil
+			}),
+		mockRows.EXPECT().Next().Return(false),
+		mockRows.EXPECT().Err().Return(nil),
+		mockRows.EXPECT().Close(),
+	)
+
+	tokens, err := repo.GetTokensByAddresses(ctx, addresses)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Token{expected}, tokens)
+}
+
 // TestCreateToken_Success tests successfully creating a token.
 func TestCreateToken_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -116,15 +138,9 @@ func TestCreateToken_Success(t *testing.T) {
 		Decimals: 18,
 	}
 
-	const query = `
-		INSERT INTO tokens (id, name, symbol, decimals)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at
-	`
-
 	mockDB.EXPECT().QueryRow(
 		ctx,
-		query,
+		pgMock.Query("CreateToken"),
 		token.ID,
 		token.Name,
 		token.Symbol,
@@ -161,15 +177,9 @@ func TestCreateToken_Failure(t *testing.T) {
 		Decimals: 18,
 	}
 
-	const query = `
-		INSERT INTO tokens (id, name, symbol, decimals)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at
-	`
-
 	mockDB.EXPECT().QueryRow(
 		ctx,
-		query,
+		pgMock.Query("CreateToken"),
 		token.ID,
 		token.Name,
 		token.Symbol,
@@ -185,3 +195,67 @@ func TestCreateToken_Failure(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to create token")
 	assert.Contains(t, err.Error(), expectedError.Error())
 }
+
+// TestListTokens_SearchAndCursor tests that the search is escaped and the cursor resumes after the last address.
+func TestListTokens_SearchAndCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	after := "0x0000000000000000000000000000000000000001"
+	cursor := repository.EncodeCursor(after, 0)
+
+	pattern := `%us\_d%`
+	mockDB.EXPECT().Query(ctx, gomock.Any(), &pattern, &after, 2).Return(mockRows, nil)
+
+	tokensData := []model.TokenVolume{
+		{Token: model.Token{ID: "0x0000000000000000000000000000000000000002", Symbol: "US_DC"}, VolumeUsd: model.NewDecimalFromFloat(1500.5), SwapCount: 3},
+		{Token: model.Token{ID: "0x0000000000000000000000000000000000000003", Symbol: "US_DT"}},
+	}
+	for _, token := range tokensData {
+		token := token
+		mockRows.EXPECT().Next().Return(true)
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+			*(dest[0].(*string)) = token.ID
+			*(dest[2].(*string)) = token.Symbol
+			*(dest[5].(*model.Decimal)) = token.VolumeUsd
+			*(dest[6].(*int64)) = token.SwapCount
+			return nil
+		})
+	}
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	tokens, next, err := repo.ListTokens(ctx, "us_d", cursor, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, tokensData[:1], tokens)
+
+	nextCursor, err := repository.DecodeCursor(next)
+	assert.NoError(t, err)
+	assert.Equal(t, tokensData[0].ID, nextCursor.Key)
+}
+
+// TestGetTokenVolume_NotFound tests that an unknown token yields ErrTokenNotFound.
+func TestGetTokenVolume_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	address := "0x0000000000000000000000000000000000000001"
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), address).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+	token, err := repo.GetTokenVolume(ctx, address)
+
+	assert.Nil(t, token)
+	assert.Equal(t, model.ErrTokenNotFound, err)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"hw/pkg/pg"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WithTx returns a repository of the same project that runs its queries in tx, so the writes of
+// several methods commit or roll back together. A transaction begun by it is a savepoint of tx.
+func (r *repository) WithTx(tx pg.PgxTx) Repository {
+	scoped := *r
+	scoped.db = &txPool{tx: tx}
+	return &scoped
+}
+
+// txPool runs the queries of a repository in a transaction.
+type txPool struct {
+	tx pg.PgxTx
+}
+
+func (p *txPool) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	return nil, errors.New("cannot acquire a connection within a transaction")
+}
+
+func (p *txPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.tx.Begin(ctx)
+}
+
+// Close leaves the transaction to its owner, who commits or rolls it back.
+func (p *txPool) Close() {}
+
+func (p *txPool) Ping(ctx context.Context) error {
+	return p.tx.Conn().Ping(ctx)
+}
+
+func (p *txPool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return p.tx.Exec(ctx, sql, arguments...)
+}
+
+func (p *txPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return p.tx.Query(ctx, sql, args...)
+}
+
+func (p *txPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return p.tx.QueryRow(ctx, sql, args...)
+}
@@ -3,46 +3,48 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"hw/internal/model"
 
 	"github.com/jackc/pgx/v5"
 )
 
+var createUserQuery = queries.Add("CreateUser", `
+	INSERT INTO users (address, project_id)
+	VALUES ($1, $2)
+	RETURNING id, created_at, updated_at
+`)
+
 // CreateUser inserts a new user into the users table.
 func (r *repository) CreateUser(ctx context.Context, userId string) (*model.User, error) {
-	const query = `
-		INSERT INTO users (address)
-		VALUES ($1)
-		RETURNING id, created_at, updated_at
-	`
-
 	user := &model.User{
 		Address: userId,
 	}
 
-	err := r.db.QueryRow(ctx, query, user.Address).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	err := r.db.QueryRow(ctx, createUserQuery, user.Address, r.project).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+		return nil, fmt.Errorf("failed to create user: %w", dbError(err))
 	}
 
 	return user, nil
 }
 
+var getUserByAddressQuery = queries.Add("GetUserByAddress", `
+	SELECT id, address, total_points, claimed_points, created_at, updated_at
+	FROM users
+	WHERE address = $1 AND project_id = $2
+	LIMIT 1
+`)
+
 // GetUserByAddress retrieves a user by their address.
 func (r *repository) GetUserByAddress(ctx context.Context, address string) (*model.User, error) {
-	const query = `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		WHERE address = $1
-		LIMIT 1
-	`
-
 	var user model.User
-	err := r.db.QueryRow(ctx, query, address).Scan(
+	err := r.db.QueryRow(ctx, getUserByAddressQuery, address, r.project).Scan(
 		&user.ID,
 		&user.Address,
 		&user.TotalPoints,
+		&user.ClaimedPoints,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -50,47 +52,111 @@ func (r *repository) GetUserByAddress(ctx context.Context, address string) (*mod
 		if err == pgx.ErrNoRows {
 			return nil, model.ErrUserNotFound
 		}
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", dbError(err))
 	}
 
 	return &user, nil
 }
 
-// UpsertUserPoints atomically updates a user's total points.
-func (r *repository) UpsertUserPoints(ctx context.Context, address string, point float64) error {
-	const query = `
-		INSERT INTO users (address, total_points)
-		VALUES ($1, $2)
-		ON CONFLICT (address) DO UPDATE SET 
-			total_points = users.total_points + EXCLUDED.total_points,
-			updated_at = CURRENT_TIMESTAMP
-		RETURNING id, created_at, updated_at
-	`
+var getUsersByAddressesQuery = queries.Add("GetUsersByAddresses", `
+	SELECT id, address, total_points, claimed_points, created_at, updated_at
+	FROM users
+	WHERE address = ANY($1) AND project_id = $2
+`)
+
+// GetUsersByAddresses retrieves the users of several addresses in one query; addresses without
+// a user are left out.
+func (r *repository) GetUsersByAddresses(ctx context.Context, addresses []string) ([]model.User, error) {
+	rows, err := r.db.Query(ctx, getUsersByAddressesQuery, addresses, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", dbError(err))
+	}
+	return scanUsers(rows)
+}
+
+var createUsersQuery = queries.Add("CreateUsers", `
+	INSERT INTO users (address, project_id)
+	SELECT unnest($1::text[]), $2
+	ON CONFLICT (project_id, address) DO NOTHING
+	RETURNING id, address, total_points, claimed_points, created_at, updated_at
+`)
+
+// CreateUsers inserts the users of several addresses in one query and returns those created;
+// addresses that already have a user are left out.
+func (r *repository) CreateUsers(ctx context.Context, addresses []string) ([]model.User, error) {
+	rows, err := r.db.Query(ctx, createUsersQuery, addresses, r.project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create users: %w", dbError(err))
+	}
+	return scanUsers(rows)
+}
+
+// scanUsers scans and closes rows of users with their claimed points.
+func scanUsers(rows pgx.Rows) ([]model.User, error) {
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var user model.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Address,
+			&user.TotalPoints,
+			&user.ClaimedPoints,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", dbError(err))
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", dbError(err))
+	}
+
+	return users, nil
+}
+
+var upsertUserPointsQuery = queries.Add("UpsertUserPoints", `
+	INSERT INTO users (address, total_points, project_id)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (project_id, address) DO UPDATE SET
+		total_points = users.total_points + EXCLUDED.total_points,
+		updated_at = CURRENT_TIMESTAMP
+	RETURNING id, created_at, updated_at
+`)
 
+// UpsertUserPoints atomically updates a user's total points.
+func (r *repository) UpsertUserPoints(ctx context.Context, address string, point model.Decimal) error {
 	user := &model.User{
 		Address:     address,
 		TotalPoints: point,
 	}
 
-	err := r.db.QueryRow(ctx, query, user.Address, user.TotalPoints).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	err := r.db.QueryRow(ctx, upsertUserPointsQuery, user.Address, user.TotalPoints, r.project).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to upsert user points: %w", err)
+		return fmt.Errorf("failed to upsert user points: %w", dbError(err))
 	}
 
 	return nil
 }
 
-// GetLeaderboard retrieves the leaderboard.
-func (r *repository) GetLeaderboard(ctx context.Context) ([]model.User, error) {
-	const query = `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		ORDER BY total_points DESC
-	`
+var getLeaderboardQuery = queries.Add("GetLeaderboard", `
+	SELECT id, address, total_points, created_at, updated_at
+	FROM users
+	WHERE project_id = $3 AND NOT (address = ANY($1))
+		AND NOT EXISTS (SELECT 1 FROM address_labels WHERE address_labels.project_id = users.project_id AND address_labels.address = users.address AND label = ANY($2) AND deleted_at IS NULL)
+	ORDER BY total_points DESC
+`)
 
-	rows, err := r.db.Query(ctx, query)
+// GetLeaderboard retrieves the leaderboard, without the excluded users.
+func (r *repository) GetLeaderboard(ctx context.Context, exclude model.Exclusion) ([]model.User, error) {
+	addresses, labels := exclusionArgs(exclude)
+	rows, err := r.db.Query(ctx, getLeaderboardQuery, addresses, labels, r.project)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+		return nil, fmt.Errorf("failed to get leaderboard: %w", dbError(err))
 	}
 	defer rows.Close()
 
@@ -105,14 +171,115 @@ func (r *repository) GetLeaderboard(ctx context.Context) ([]model.User, error) {
 			&user.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
+			return nil, fmt.Errorf("failed to scan user: %w", dbError(err))
 		}
 		users = append(users, user)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+		return nil, fmt.Errorf("error iterating rows: %w", dbError(err))
 	}
 
 	return users, nil
 }
+
+var getLeaderboardPageQuery = queries.Add("GetLeaderboardPage", `
+	SELECT id, address, total_points, created_at, updated_at
+	FROM users
+	WHERE project_id = $6 AND ($1::numeric IS NULL OR (total_points, id) < ($1::numeric, $2::int))
+		AND NOT (address = ANY($4))
+		AND NOT EXISTS (SELECT 1 FROM address_labels WHERE address_labels.project_id = users.project_id AND address_labels.address = users.address AND label = ANY($5) AND deleted_at IS NULL)
+	ORDER BY total_points DESC, id DESC
+	LIMIT $3
+`)
+
+// GetLeaderboardPage retrieves one page of the leaderboard without the excluded users, keyed on (total_points, id).
+func (r *repository) GetLeaderboardPage(ctx context.Context, cursor string, limit int, exclude model.Exclusion) ([]model.User, string, error) {
+	var (
+		afterPoints *model.Decimal
+		afterID     *int
+	)
+	c, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if c != nil {
+		points, err := c.Decimal()
+		if err != nil {
+			return nil, "", err
+		}
+		afterPoints, afterID = &points, &c.ID
+	}
+
+	addresses, labels := exclusionArgs(exclude)
+	rows, err := r.db.Query(ctx, getLeaderboardPageQuery, afterPoints, afterID, limit+1, addresses, labels, r.project)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get leaderboard page: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	users := make([]model.User, 0, limit+1)
+	for rows.Next() {
+		var user model.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Address,
+			&user.TotalPoints,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan user: %w", dbError(err))
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("error iterating rows: %w", dbError(err))
+	}
+
+	users, next := nextPage(users, limit, func(u model.User) string {
+		return EncodeCursor(u.TotalPoints.String(), u.ID)
+	})
+
+	return users, next, nil
+}
+
+var getUserRankQuery = queries.Add("GetUserRank", `
+	WITH ranked AS (
+		SELECT address, total_points
+		FROM users
+		WHERE project_id = $4 AND NOT (address = ANY($2))
+			AND NOT EXISTS (SELECT 1 FROM address_labels WHERE address_labels.project_id = users.project_id AND address_labels.address = users.address AND label = ANY($3) AND deleted_at IS NULL)
+	)
+	SELECT u.address, u.total_points,
+		(SELECT COUNT(*) FROM ranked WHERE total_points > u.total_points) + 1
+	FROM ranked u
+	WHERE u.address = $1
+`)
+
+// GetUserRank retrieves a user's position on the leaderboard among the users not excluded. Users with
+// equal points share a rank, and excluded users are not found.
+func (r *repository) GetUserRank(ctx context.Context, address string, exclude model.Exclusion) (*model.LeaderboardRank, error) {
+	var rank model.LeaderboardRank
+	addresses, labels := exclusionArgs(exclude)
+	err := r.db.QueryRow(ctx, getUserRankQuery, address, addresses, labels, r.project).Scan(&rank.Address, &rank.Points, &rank.Rank)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user rank: %w", dbError(err))
+	}
+
+	return &rank, nil
+}
+
+// exclusionArgs returns the addresses and labels of an exclusion as query arguments, never nil, since
+// comparing with a NULL array would exclude every account.
+func exclusionArgs(exclude model.Exclusion) (addresses, labels []string) {
+	addresses, labels = []string{}, []string{}
+	for _, address := range exclude.Addresses {
+		addresses = append(addresses, strings.ToLower(address))
+	}
+	return addresses, append(labels, exclude.Labels...)
+}
@@ -26,13 +26,7 @@ func TestCreateUser_Success(t *testing.T) {
 	ctx := context.Background()
 	userID := "0x1234567890123456789012345678901234567890"
 
-	const query = `
-		INSERT INTO users (address)
-		VALUES ($1)
-		RETURNING id, created_at, updated_at
-	`
-
-	mockDB.EXPECT().QueryRow(ctx, query, userID).Return(mockRow)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("CreateUser"), userID).Return(mockRow)
 
 	expectedUser := &model.User{
 		ID:        1,
@@ -69,13 +63,7 @@ func TestCreateUser_Error(t *testing.T) {
 	ctx := context.Background()
 	userId := "0x1234567890123456789012345678901234567890"
 
-	const query = `
-		INSERT INTO users (address)
-		VALUES ($1)
-		RETURNING id, created_at, updated_at
-	`
-
-	mockDB.EXPECT().QueryRow(ctx, query, userId).Return(mockRow)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("CreateUser"), userId).Return(mockRow)
 
 	expectedError := errors.New("database error")
 	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedError)
@@ -98,35 +86,31 @@ func TestGetUserByAddress_Success(t *testing.T) {
 	ctx := context.Background()
 	address := "0x1234567890123456789012345678901234567890"
 
-	const query = `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		WHERE address = $1
-		LIMIT 1
-	`
-
-	mockDB.EXPECT().QueryRow(ctx, query, address).Return(mockRow)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetUserByAddress"), address).Return(mockRow)
 
 	expectedUser := &model.User{
-		ID:          1,
-		Address:     address,
-		TotalPoints: 100.5,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:            1,
+		Address:       address,
+		TotalPoints:   model.NewDecimalFromFloat(100.5),
+		ClaimedPoints: model.NewDecimalFromFloat(40),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
 	}
 
 	mockRow.EXPECT().Scan(
 		gomock.AssignableToTypeOf(&expectedUser.ID),
 		gomock.AssignableToTypeOf(&expectedUser.Address),
 		gomock.AssignableToTypeOf(&expectedUser.TotalPoints),
+		gomock.AssignableToTypeOf(&expectedUser.ClaimedPoints),
 		gomock.AssignableToTypeOf(&expectedUser.CreatedAt),
 		gomock.AssignableToTypeOf(&expectedUser.UpdatedAt),
 	).DoAndReturn(func(dest ...interface{}) error {
 		*(dest[0].(*int)) = expectedUser.ID
 		*(dest[1].(*string)) = expectedUser.Address
-		*(dest[2].(*float64)) = expectedUser.TotalPoints
-		*(dest[3].(*time.Time)) = expectedUser.CreatedAt
-		*(dest[4].(*time.Time)) = expectedUser.UpdatedAt
+		*(dest[2].(*model.Decimal)) = expectedUser.TotalPoints
+		*(dest[3].(*model.Decimal)) = expectedUser.ClaimedPoints
+		*(dest[4].(*time.Time)) = expectedUser.CreatedAt
+		*(dest[5].(*time.Time)) = expectedUser.UpdatedAt
 		return nil
 	})
 
@@ -147,15 +131,8 @@ func TestGetUserByAddress_NotFound(t *testing.T) {
 	ctx := context.Background()
 	address := "0x1234567890123456789012345678901234567890"
 
-	const query = `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		WHERE address = $1
-		LIMIT 1
-	`
-
-	mockDB.EXPECT().QueryRow(ctx, query, address).Return(mockRow)
-	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("GetUserByAddress"), address).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
 
 	user, err := repo.GetUserByAddress(ctx, address)
 
@@ -164,6 +141,58 @@ func TestGetUserByAddress_NotFound(t *testing.T) {
 	assert.Equal(t, model.ErrUserNotFound, err)
 }
 
+// TestGetUsersByAddresses_Success tests retrieving the users of several addresses with one query.
+func TestGetUsersByAddresses_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	addresses := []string{"address1", "address2"}
+	expected := model.User{ID: 1, Address: "address1", TotalPoints: model.NewDecimalFromFloat(10), ClaimedPoints: model.NewDecimalFromFloat(2)}
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetUsersByAddresses"), addresses).Return(mockRows, nil)
+	gomock.InOrder(
+		mockRows.EXPECT().Next().Return(true),
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(dest ...any) error {
+				*(dest[0].(*int)) = expected.ID
+				*(dest[1].(*string)) = expected.Address
+				*(dest[2].(*model.Decimal)) = expected.TotalPoints
+				*(dest[3].(*model.Decimal)) = expected.ClaimedPoints
+				return nil
+			}),
+		mockRows.EXPECT().Next().Return(false),
+		mockRows.EXPECT().Err().Return(nil),
+		mockRows.EXPECT().Close(),
+	)
+
+	users, err := repo.GetUsersByAddresses(ctx, addresses)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.User{expected}, users)
+}
+
+// TestCreateUsers_Error tests that a failed batch insert is reported.
+func TestCreateUsers_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	addresses := []string{"address1", "address2"}
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("CreateUsers"), addresses).Return(nil, errors.New("insert failed"))
+
+	users, err := repo.CreateUsers(ctx, addresses)
+
+	assert.Nil(t, users)
+	assert.ErrorContains(t, err, "failed to create users")
+}
+
 // TestUpsertUserPoints_Success verifies successful upsertion of user points.
 func TestUpsertUserPoints_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -174,18 +203,9 @@ func TestUpsertUserPoints_Success(t *testing.T) {
 
 	ctx := context.Background()
 	address := "0x1234567890123456789012345678901234567890"
-	points := 50.5
-
-	const query = `
-		INSERT INTO users (address, total_points)
-		VALUES ($1, $2)
-		ON CONFLICT (address) DO UPDATE SET 
-			total_points = users.total_points + EXCLUDED.total_points,
-			updated_at = CURRENT_TIMESTAMP
-		RETURNING id, created_at, updated_at
-	`
+	points := model.NewDecimalFromFloat(50.5)
 
-	mockDB.EXPECT().QueryRow(ctx, query, address, points).Return(mockRow)
+	mockDB.EXPECT().QueryRow(ctx, pgMock.Query("UpsertUserPoints"), address, points, model.DefaultProjectID).Return(mockRow)
 
 	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
@@ -204,28 +224,22 @@ func TestGetLeaderboard_Success(t *testing.T) {
 
 	ctx := context.Background()
 
-	expectedQuery := `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		ORDER BY total_points DESC
-	`
-
 	mockDB.EXPECT().
-		Query(ctx, expectedQuery).
+		Query(ctx, pgMock.Query("GetLeaderboard"), []string{}, []string{}, model.DefaultProjectID).
 		Return(mockRows, nil)
 
 	usersData := []model.User{
 		{
 			ID:          1,
 			Address:     "address1",
-			TotalPoints: 100.0,
+			TotalPoints: model.NewDecimalFromFloat(100.0),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
 		{
 			ID:          2,
 			Address:     "address2",
-			TotalPoints: 90.0,
+			TotalPoints: model.NewDecimalFromFloat(90.0),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
@@ -242,7 +256,7 @@ func TestGetLeaderboard_Success(t *testing.T) {
 		).DoAndReturn(func(dest ...any) error {
 			*(dest[0].(*int)) = usersData[0].ID
 			*(dest[1].(*string)) = usersData[0].Address
-			*(dest[2].(*float64)) = usersData[0].TotalPoints
+			*(dest[2].(*model.Decimal)) = usersData[0].TotalPoints
 			*(dest[3].(*time.Time)) = usersData[0].CreatedAt
 			*(dest[4].(*time.Time)) = usersData[0].UpdatedAt
 			return nil
@@ -258,7 +272,7 @@ func TestGetLeaderboard_Success(t *testing.T) {
 		).DoAndReturn(func(dest ...any) error {
 			*(dest[0].(*int)) = usersData[1].ID
 			*(dest[1].(*string)) = usersData[1].Address
-			*(dest[2].(*float64)) = usersData[1].TotalPoints
+			*(dest[2].(*model.Decimal)) = usersData[1].TotalPoints
 			*(dest[3].(*time.Time)) = usersData[1].CreatedAt
 			*(dest[4].(*time.Time)) = usersData[1].UpdatedAt
 			return nil
@@ -269,7 +283,7 @@ func TestGetLeaderboard_Success(t *testing.T) {
 		mockRows.EXPECT().Close(),
 	)
 
-	result, err := repo.GetLeaderboard(ctx)
+	result, err := repo.GetLeaderboard(ctx, model.Exclusion{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, usersData, result)
@@ -285,24 +299,38 @@ func TestGetLeaderboard_EmptyResult(t *testing.T) {
 
 	ctx := context.Background()
 
-	expectedQuery := `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		ORDER BY total_points DESC
-	`
-
-	mockDB.EXPECT().Query(ctx, expectedQuery).Return(mockRows, nil)
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetLeaderboard"), []string{}, []string{}, model.DefaultProjectID).Return(mockRows, nil)
 
 	mockRows.EXPECT().Next().Return(false)
 	mockRows.EXPECT().Err().Return(nil)
 	mockRows.EXPECT().Close()
 
-	result, err := repo.GetLeaderboard(ctx)
+	result, err := repo.GetLeaderboard(ctx, model.Exclusion{})
 
 	assert.NoError(t, err)
 	assert.Empty(t, result, "Result should be an empty slice")
 }
 
+// TestGetLeaderboard_Exclusion verifies that the excluded addresses, lowercased, and labels are bound as query arguments.
+func TestGetLeaderboard_Exclusion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetLeaderboard"), []string{"0xabc"}, []string{model.LabelTeamWallet}, model.DefaultProjectID).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	_, err := repo.GetLeaderboard(ctx, model.Exclusion{Labels: []string{model.LabelTeamWallet}, Addresses: []string{"0xABC"}})
+
+	assert.NoError(t, err)
+}
+
 // TestGetLeaderboard_QueryError verifies behavior when the query fails.
 func TestGetLeaderboard_QueryError(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -312,16 +340,10 @@ func TestGetLeaderboard_QueryError(t *testing.T) {
 
 	ctx := context.Background()
 
-	expectedQuery := `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		ORDER BY total_points DESC
-	`
-
 	expectedError := errors.New("database query error")
-	mockDB.EXPECT().Query(ctx, expectedQuery).Return(nil, expectedError)
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetLeaderboard"), []string{}, []string{}, model.DefaultProjectID).Return(nil, expectedError)
 
-	result, err := repo.GetLeaderboard(ctx)
+	result, err := repo.GetLeaderboard(ctx, model.Exclusion{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -338,20 +360,14 @@ func TestGetLeaderboard_ScanError(t *testing.T) {
 
 	ctx := context.Background()
 
-	expectedQuery := `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		ORDER BY total_points DESC
-	`
-
-	mockDB.EXPECT().Query(ctx, expectedQuery).Return(mockRows, nil)
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetLeaderboard"), []string{}, []string{}, model.DefaultProjectID).Return(mockRows, nil)
 
 	mockRows.EXPECT().Next().Return(true)
 	scanError := errors.New("scan error")
 	mockRows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(scanError)
 	mockRows.EXPECT().Close()
 
-	result, err := repo.GetLeaderboard(ctx)
+	result, err := repo.GetLeaderboard(ctx, model.Exclusion{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -368,22 +384,61 @@ func TestGetLeaderboard_RowsError(t *testing.T) {
 
 	ctx := context.Background()
 
-	expectedQuery := `
-		SELECT id, address, total_points, created_at, updated_at
-		FROM users
-		ORDER BY total_points DESC
-	`
-
-	mockDB.EXPECT().Query(ctx, expectedQuery).Return(mockRows, nil)
+	mockDB.EXPECT().Query(ctx, pgMock.Query("GetLeaderboard"), []string{}, []string{}, model.DefaultProjectID).Return(mockRows, nil)
 
 	mockRows.EXPECT().Next().Return(false)
 	rowsError := errors.New("rows error")
 	mockRows.EXPECT().Err().Return(rowsError)
 	mockRows.EXPECT().Close()
 
-	result, err := repo.GetLeaderboard(ctx)
+	result, err := repo.GetLeaderboard(ctx, model.Exclusion{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "error iterating rows")
 }
+
+// TestGetUserRank_Success verifies that a user's rank is scanned from the row.
+func TestGetUserRank_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	address := "0x1234567890123456789012345678901234567890"
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), address, []string{}, []string{}, model.DefaultProjectID).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*dest[0].(*string) = address
+		*dest[1].(*model.Decimal) = model.NewDecimalFromFloat(42.5)
+		*dest[2].(*int64) = 3
+		return nil
+	})
+
+	rank, err := repo.GetUserRank(ctx, address, model.Exclusion{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.LeaderboardRank{Address: address, Points: model.NewDecimalFromFloat(42.5), Rank: 3}, rank)
+}
+
+// TestGetUserRank_NotFound verifies that an unknown user yields ErrUserNotFound.
+func TestGetUserRank_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+	repo := repository.NewRepository(mockDB)
+
+	ctx := context.Background()
+	address := "0x1234567890123456789012345678901234567890"
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), address, []string{}, []string{}, model.DefaultProjectID).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+	rank, err := repo.GetUserRank(ctx, address, model.Exclusion{})
+
+	assert.Nil(t, rank)
+	assert.Equal(t, model.ErrUserNotFound, err)
+}
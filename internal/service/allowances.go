@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"hw/internal/model"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/shopspring/decimal"
+)
+
+// unlimitedAllowance is the maximum uint256 amount wallets approve for an unlimited allowance.
+var unlimitedAllowance = decimal.NewFromBigInt(math.MaxBig256, 0)
+
+// RecordApproval sets the allowance of a spender over an owner's tokens from an Approval event,
+// unless a later approval set it already.
+func (s *service) RecordApproval(ctx context.Context, allowance *model.Allowance) error {
+	return s.repo.SetAllowance(ctx, allowance)
+}
+
+// GetUserApprovals retrieves the outstanding allowances granted by a user, on every network when network is empty.
+func (s *service) GetUserApprovals(ctx context.Context, owner, network string) ([]model.Allowance, error) {
+	allowances, err := s.repo.GetOwnerAllowances(ctx, owner, network)
+	if err != nil {
+		return nil, err
+	}
+	for i := range allowances {
+		allowances[i].Unlimited = allowances[i].Amount.Decimal.Equal(unlimitedAllowance)
+	}
+	return allowances, nil
+}
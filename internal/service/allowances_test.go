@@ -0,0 +1,36 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetUserApprovals tests that maximum uint256 allowances are flagged unlimited.
+func TestGetUserApprovals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetOwnerAllowances(ctx, "0xowner", "mainnet").Return([]model.Allowance{
+		{Spender: "0xrouter", Amount: model.NewDecimal(decimal.NewFromBigInt(math.MaxBig256, 0))},
+		{Spender: "0xvault", Amount: model.NewDecimalFromFloat(1e6)},
+	}, nil)
+
+	allowances, err := svc.GetUserApprovals(ctx, "0xowner", "mainnet")
+
+	assert.NoError(t, err)
+	if assert.Len(t, allowances, 2) {
+		assert.True(t, allowances[0].Unlimited)
+		assert.False(t, allowances[1].Unlimited)
+	}
+}
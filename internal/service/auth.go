@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/config"
+	"hw/pkg/jwt"
+	"hw/pkg/siwe"
+)
+
+// WithAuth configures Sign-In With Ethereum and the sessions it issues.
+// Without it, sign-in uses the defaults of config.Default.
+func WithAuth(cfg config.Auth) Option {
+	return func(s *service) {
+		s.auth = cfg
+	}
+}
+
+// sessionKey returns the configured key of session tokens, or a random key when none is configured,
+// in which case sessions end with the process.
+func sessionKey(cfg config.Auth) []byte {
+	if cfg.JWTSecret != "" {
+		return []byte(cfg.JWTSecret)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate a session key: %v", err))
+	}
+	return key
+}
+
+// CreateSignInNonce issues a single-use nonce for a Sign-In With Ethereum message.
+func (s *service) CreateSignInNonce(ctx context.Context) (*model.SignInNonce, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	nonce := &model.SignInNonce{
+		Nonce:     hex.EncodeToString(buf),
+		ExpiresAt: time.Now().Add(s.auth.NonceTTL).Truncate(time.Second),
+	}
+	if err := s.repo.CreateSignInNonce(ctx, nonce.Nonce, nonce.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// SignIn verifies a Sign-In With Ethereum (EIP-4361) message signed by its address with personal_sign,
+// consumes its nonce and issues a session for the address.
+func (s *service) SignIn(ctx context.Context, message, signature string) (*model.Session, error) {
+	m, err := siwe.Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidSignIn, err)
+	}
+	if m.Domain != s.auth.Domain {
+		return nil, fmt.Errorf("%w: message is bound to domain %q", model.ErrInvalidSignIn, m.Domain)
+	}
+	now := time.Now()
+	if err := m.ValidAt(now); err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidSignIn, err)
+	}
+	if _, signer, err := recoverSigner(message, signature); err != nil || signer != m.Address {
+		return nil, fmt.Errorf("%w: message is not signed by %s", model.ErrInvalidSignIn, m.Address.Hex())
+	}
+	// The nonce is consumed last, so an invalid attempt cannot burn the nonce of a valid one
+	if err := s.repo.ConsumeSignInNonce(ctx, m.Nonce); err != nil {
+		if errors.Is(err, model.ErrInvalidSignIn) {
+			return nil, fmt.Errorf("%w: nonce is unknown, expired or used", model.ErrInvalidSignIn)
+		}
+		return nil, err
+	}
+
+	session := &model.Session{
+		Address:   strings.ToLower(m.Address.Hex()),
+		ExpiresAt: time.Unix(now.Add(s.auth.SessionTTL).Unix(), 0),
+	}
+	session.Token, err = jwt.Sign(jwt.Claims{
+		Subject:   session.Address,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: session.ExpiresAt.Unix(),
+	}, s.sessionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign session: %w", err)
+	}
+	return session, nil
+}
+
+// Authenticate verifies a session token and returns the lowercased address it was issued to.
+func (s *service) Authenticate(token string) (string, error) {
+	claims, err := jwt.Verify(token, s.sessionKey, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", model.ErrInvalidSession, err)
+	}
+	return claims.Subject, nil
+}
+
+// GetUserProfile retrieves a user's profile, or an empty profile when none was saved.
+func (s *service) GetUserProfile(ctx context.Context, address string) (*model.UserProfile, error) {
+	profile, err := s.repo.GetUserProfile(ctx, address)
+	if errors.Is(err, model.ErrProfileNotFound) {
+		return &model.UserProfile{Address: address}, nil
+	}
+	return profile, err
+}
+
+// UpdateUserProfile replaces a user's profile.
+func (s *service) UpdateUserProfile(ctx context.Context, profile *model.UserProfile) error {
+	return s.repo.UpsertUserProfile(ctx, profile)
+}
@@ -0,0 +1,141 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/siwe"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// signMessage returns the personal_sign signature of message by the claim test key.
+func signMessage(t *testing.T, message string) string {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	assert.NoError(t, err)
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	assert.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig)
+}
+
+// TestSignIn tests that a signed message issues a session authenticating its address, and every rejection.
+func TestSignIn(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	valid := siwe.Message{
+		Domain:         "localhost:3000",
+		Address:        common.HexToAddress(claimAddress),
+		URI:            "http://localhost:3000",
+		Version:        "1",
+		ChainID:        1,
+		Nonce:          "32891756abcd",
+		IssuedAt:       now,
+		ExpirationTime: now.Add(time.Minute),
+	}
+
+	tests := []struct {
+		name        string
+		message     func(m siwe.Message) string
+		signature   func(message string) string
+		nonceErr    error
+		wantConsume bool
+		wantErr     bool
+	}{
+		{name: "signed in", wantConsume: true},
+		{name: "other domain", message: func(m siwe.Message) string { m.Domain = "evil.example.com"; return m.String() }, wantErr: true},
+		{name: "expired", message: func(m siwe.Message) string { m.ExpirationTime = now.Add(-time.Second); return m.String() }, wantErr: true},
+		{name: "malformed", message: func(m siwe.Message) string { return "Sign in" }, wantErr: true},
+		{
+			name: "other signer", wantErr: true,
+			signature: func(message string) string { return signMessage(t, message+"\n") },
+		},
+		{name: "used nonce", nonceErr: model.ErrInvalidSignIn, wantConsume: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := repositoryMock.NewMockRepository(ctrl)
+			svc := service.NewService(mockRepo, service.WithAuth(config.Default().Auth))
+			ctx := context.Background()
+
+			message := valid.String()
+			if tt.message != nil {
+				message = tt.message(valid)
+			}
+			signature := signMessage(t, message)
+			if tt.signature != nil {
+				signature = tt.signature(message)
+			}
+			if tt.wantConsume {
+				mockRepo.EXPECT().ConsumeSignInNonce(ctx, "32891756abcd").Return(tt.nonceErr)
+			}
+
+			session, err := svc.SignIn(ctx, message, signature)
+
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, model.ErrInvalidSignIn), "got %v", err)
+				assert.Nil(t, session)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "0x71562b71999873db5b286df957af199ec94617f7", session.Address)
+			assert.Equal(t, now.Add(24*time.Hour), session.ExpiresAt)
+
+			address, err := svc.Authenticate(session.Token)
+			assert.NoError(t, err)
+			assert.Equal(t, session.Address, address)
+		})
+	}
+}
+
+// TestAuthenticate tests that sessions only verify with the key that issued them.
+func TestAuthenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	cfg := config.Default().Auth
+	cfg.JWTSecret = "0123456789abcdef0123456789abcdef"
+	svc := service.NewService(mockRepo, service.WithAuth(cfg))
+
+	_, err := svc.Authenticate("not a token")
+	assert.True(t, errors.Is(err, model.ErrInvalidSession))
+
+	// A service without a configured secret signs with a random key
+	other := service.NewService(mockRepo)
+	message := siwe.Message{
+		Domain: cfg.Domain, Address: common.HexToAddress(claimAddress), URI: "http://localhost:3000",
+		Version: "1", ChainID: 1, Nonce: "32891756abcd", IssuedAt: time.Now(),
+	}
+	mockRepo.EXPECT().ConsumeSignInNonce(gomock.Any(), "32891756abcd").Return(nil)
+	session, err := other.SignIn(context.Background(), message.String(), signMessage(t, message.String()))
+	assert.NoError(t, err)
+
+	_, err = svc.Authenticate(session.Token)
+	assert.True(t, errors.Is(err, model.ErrInvalidSession))
+}
+
+// TestGetUserProfile tests that a user without a saved profile gets an empty one.
+func TestGetUserProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetUserProfile(ctx, "0xabc").Return(nil, model.ErrProfileNotFound)
+
+	profile, err := svc.GetUserProfile(ctx, "0xabc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.UserProfile{Address: "0xabc"}, profile)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"hw/internal/model"
+	"hw/pkg/config"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceReader reads the balanceOf of an account in an ERC-20 or ERC-721 contract, at the block
+// of the swap being handled.
+type BalanceReader func(contract string) (*big.Int, error)
+
+// WithBoosts multiplies the points accounts earn with a swap while they hold the NFTs or tokens of boosts.
+func WithBoosts(boosts []config.Boost) Option {
+	return func(s *service) {
+		s.boosts = boosts
+	}
+}
+
+// ResolvePointsBoost returns the largest boost of a network whose NFT, or enough of whose token,
+// an account holds according to balanceOf, or nil when it holds none. Boosts not larger than one
+// already held are not read.
+func (s *service) ResolvePointsBoost(ctx context.Context, network, account string, balanceOf BalanceReader) (*model.PointsBoost, error) {
+	var held *config.Boost
+	for i := range s.boosts {
+		boost := &s.boosts[i]
+		if boost.Network != network || (held != nil && boost.Multiplier <= held.Multiplier) {
+			continue
+		}
+		balance, err := balanceOf(boost.Contract)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read balance of %s for boost %s: %w", account, boost.ID, err)
+		}
+		if balance.Cmp(boostMinBalance(boost)) >= 0 {
+			held = boost
+		}
+	}
+	if held == nil {
+		return nil, nil
+	}
+	return &model.PointsBoost{ID: held.ID, Multiplier: model.NewDecimal(decimal.NewFromFloat(held.Multiplier))}, nil
+}
+
+// boostMinBalance returns the balance an account must hold for a boost: one NFT, or the MinBalance of a token.
+func boostMinBalance(boost *config.Boost) *big.Int {
+	if boost.Kind == config.BoostNFT {
+		return big.NewInt(1)
+	}
+	minBalance, _ := new(big.Int).SetString(boost.MinBalance, 10)
+	return minBalance
+}
+
+// BoostedPoints returns points multiplied by boost, or the points themselves without a boost.
+func BoostedPoints(points model.Decimal, boost *model.PointsBoost) model.Decimal {
+	if boost == nil {
+		return points
+	}
+	return model.NewDecimal(points.Mul(boost.Multiplier.Decimal))
+}
@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	boostNFT   = "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d"
+	boostToken = "0x7fc66500c84a76ad7e9c93437bfc5ac33e2ddae9"
+)
+
+var testBoosts = []config.Boost{
+	{ID: "aave_holder", Network: "mainnet", Contract: boostToken, Kind: config.BoostToken, MinBalance: "100000000000000000000", Multiplier: 1.2},
+	{ID: "base_nft", Network: "base", Contract: boostNFT, Kind: config.BoostNFT, Multiplier: 3},
+	{ID: "og_nft", Network: "mainnet", Contract: boostNFT, Kind: config.BoostNFT, Multiplier: 1.5},
+	{ID: "aave_whale", Network: "mainnet", Contract: boostToken, Kind: config.BoostToken, MinBalance: "1000000000000000000000", Multiplier: 1.4},
+}
+
+// TestResolvePointsBoost tests that the largest boost held on the network of the swap applies, and
+// that boosts of other networks or not larger than one held are not read.
+func TestResolvePointsBoost(t *testing.T) {
+	ether := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18)) }
+	tests := []struct {
+		name     string
+		balances map[string]*big.Int
+		want     *model.PointsBoost
+		reads    []string
+	}{
+		{
+			name:     "nothing held",
+			balances: map[string]*big.Int{boostToken: ether(99), boostNFT: big.NewInt(0)},
+			reads:    []string{boostToken, boostNFT, boostToken},
+		},
+		{
+			name:     "token held",
+			balances: map[string]*big.Int{boostToken: ether(100), boostNFT: big.NewInt(0)},
+			want:     &model.PointsBoost{ID: "aave_holder", Multiplier: model.NewDecimalFromFloat(1.2)},
+			reads:    []string{boostToken, boostNFT, boostToken},
+		},
+		{
+			name:     "nft larger than token",
+			balances: map[string]*big.Int{boostToken: ether(500), boostNFT: big.NewInt(1)},
+			want:     &model.PointsBoost{ID: "og_nft", Multiplier: model.NewDecimalFromFloat(1.5)},
+			reads:    []string{boostToken, boostNFT},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			svc := service.NewService(repositoryMock.NewMockRepository(ctrl), service.WithBoosts(testBoosts))
+
+			var reads []string
+			boost, err := svc.ResolvePointsBoost(context.Background(), "mainnet", "0xuser", func(contract string) (*big.Int, error) {
+				reads = append(reads, contract)
+				return tt.balances[contract], nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, boost)
+			assert.Equal(t, tt.reads, reads)
+		})
+	}
+
+	ctrl := gomock.NewController(t)
+	svc := service.NewService(repositoryMock.NewMockRepository(ctrl), service.WithBoosts(testBoosts))
+	_, err := svc.ResolvePointsBoost(context.Background(), "mainnet", "0xuser", func(string) (*big.Int, error) {
+		return nil, errors.New("rpc unavailable")
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "for boost aave_holder")
+	}
+}
+
+// TestBoostedPoints tests that points are multiplied by a boost, and unchanged without one.
+func TestBoostedPoints(t *testing.T) {
+	boost := &model.PointsBoost{ID: "og_nft", Multiplier: model.NewDecimalFromFloat(1.5)}
+
+	assert.True(t, service.BoostedPoints(model.NewDecimalFromFloat(100), boost).Equal(model.NewDecimalFromFloat(150).Decimal))
+	assert.True(t, service.BoostedPoints(model.NewDecimalFromFloat(100), nil).Equal(model.NewDecimalFromFloat(100).Decimal))
+}
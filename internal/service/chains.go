@@ -0,0 +1,52 @@
+package service
+
+import "strings"
+
+// Chain describes a network the indexer knows about.
+type Chain struct {
+	Network     string `json:"network"`
+	ChainID     int64  `json:"chain_id"`
+	ExplorerURL string `json:"explorer_url"`
+}
+
+// chains maps a network name to its chain metadata.
+var chains = map[string]Chain{
+	"mainnet":  {Network: "mainnet", ChainID: 1, ExplorerURL: "https://etherscan.io"},
+	"sepolia":  {Network: "sepolia", ChainID: 11155111, ExplorerURL: "https://sepolia.etherscan.io"},
+	"base":     {Network: "base", ChainID: 8453, ExplorerURL: "https://basescan.org"},
+	"arbitrum": {Network: "arbitrum", ChainID: 42161, ExplorerURL: "https://arbiscan.io"},
+	"optimism": {Network: "optimism", ChainID: 10, ExplorerURL: "https://optimistic.etherscan.io"},
+	"polygon":  {Network: "polygon", ChainID: 137, ExplorerURL: "https://polygonscan.com"},
+	"bsc":      {Network: "bsc", ChainID: 56, ExplorerURL: "https://bscscan.com"},
+}
+
+// RegisterChain adds or replaces the metadata of a network.
+// It is meant to be called during start-up, before the API serves requests.
+func RegisterChain(chain Chain) {
+	chain.ExplorerURL = strings.TrimSuffix(chain.ExplorerURL, "/")
+	chains[chain.Network] = chain
+}
+
+// GetChain returns the metadata of a network.
+func GetChain(network string) (Chain, bool) {
+	chain, exists := chains[network]
+	return chain, exists
+}
+
+// ExplorerTxURL returns the block explorer URL of a transaction, or an empty string for an unknown network.
+func ExplorerTxURL(network, hash string) string {
+	return explorerURL(network, "tx", hash)
+}
+
+// ExplorerAddressURL returns the block explorer URL of an account or contract, or an empty string for an unknown network.
+func ExplorerAddressURL(network, address string) string {
+	return explorerURL(network, "address", address)
+}
+
+func explorerURL(network, kind, id string) string {
+	chain, exists := chains[network]
+	if !exists || chain.ExplorerURL == "" || id == "" {
+		return ""
+	}
+	return chain.ExplorerURL + "/" + kind + "/" + id
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/merkle"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ClaimDecimals is the number of decimals of the token amount in a claim leaf, so a point is worth 10^18 units.
+const ClaimDecimals = 18
+
+// WithClaims configures how claims are verified and whether they record Merkle leaves.
+// Without it, claims use the defaults of config.Default.
+func WithClaims(cfg config.Claims) Option {
+	return func(s *service) {
+		s.claims = cfg
+	}
+}
+
+// ClaimMessage returns the message a user signs with personal_sign (EIP-191) to claim their points at timestamp.
+func ClaimMessage(address string, timestamp int64) string {
+	return fmt.Sprintf("Claim points for %s at %d", strings.ToLower(address), timestamp)
+}
+
+// ClaimAmount converts points to the token amount of a claim leaf, truncated to ClaimDecimals.
+func ClaimAmount(points model.Decimal) *big.Int {
+	return points.Shift(ClaimDecimals).BigInt()
+}
+
+// ClaimPoints verifies a claim signed by the user and marks their claimable points as claimed.
+func (s *service) ClaimPoints(ctx context.Context, address string, timestamp int64, signature string) (*model.PointClaim, error) {
+	sig, err := s.verifyClaimSignature(address, timestamp, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := s.repo.ClaimUserPoints(ctx, address, hexutil.Encode(sig))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.claims.MerkleLeaves {
+		leaf := merkle.Leaf(uint64(claim.ID), common.HexToAddress(claim.Address), ClaimAmount(claim.Points)).Hex()
+		// The points are claimed already, so a missing leaf is logged rather than failing the claim
+		if err := s.repo.SetPointClaimLeaf(ctx, claim.ID, leaf); err != nil {
+			logger.Errorf("Failed to record the leaf of claim %d: %v", claim.ID, err)
+			return claim, nil
+		}
+		claim.Leaf = leaf
+	}
+
+	return claim, nil
+}
+
+// verifyClaimSignature checks that signature is the address's signature of the claim message at a timestamp
+// within the signature TTL, and returns it normalized, so every encoding of a signature is recorded the same
+// way and cannot be replayed.
+func (s *service) verifyClaimSignature(address string, timestamp int64, signature string) ([]byte, error) {
+	if age := time.Since(time.Unix(timestamp, 0)); age > s.claims.SignatureTTL || age < -s.claims.SignatureTTL {
+		return nil, model.ErrInvalidClaimSignature
+	}
+
+	sig, signer, err := recoverSigner(ClaimMessage(address, timestamp), signature)
+	if err != nil || !strings.EqualFold(signer.Hex(), address) {
+		return nil, model.ErrInvalidClaimSignature
+	}
+	return sig, nil
+}
+
+// recoverSigner recovers the address that signed message with personal_sign (EIP-191). It returns the
+// signature normalized to a low S and a recovery id of 0 or 1.
+func recoverSigner(message, signature string) ([]byte, common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return nil, common.Address{}, errors.New("signature must be 65 hex-encoded bytes")
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	r, sValue := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[crypto.RecoveryIDOffset], r, sValue, true) {
+		return nil, common.Address{}, errors.New("invalid signature values")
+	}
+
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return sig, crypto.PubkeyToAddress(*pub), nil
+}
@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/merkle"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// signClaim returns the personal_sign signature of the claim message, with a recovery id of 27 or 28 like wallets.
+func signClaim(t *testing.T, address string, timestamp int64) (string, []byte) {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	assert.NoError(t, err)
+	assert.Equal(t, address, crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	sig, err := crypto.Sign(accounts.TextHash([]byte(service.ClaimMessage(address, timestamp))), key)
+	assert.NoError(t, err)
+	normalized := append([]byte(nil), sig...)
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig), normalized
+}
+
+const claimAddress = "0x71562b71999873DB5b286dF957af199Ec94617F7"
+
+// TestClaimPoints tests that a signed claim is recorded with its normalized signature and Merkle leaf.
+func TestClaimPoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	cfg := config.Default().Claims
+	cfg.MerkleLeaves = true
+	svc := service.NewService(mockRepo, service.WithClaims(cfg))
+	ctx := context.Background()
+
+	timestamp := time.Now().Unix()
+	signature, normalized := signClaim(t, claimAddress, timestamp)
+	claim := &model.PointClaim{ID: 7, Address: claimAddress, Points: model.NewDecimalFromFloat(12.5)}
+	leaf := merkle.Leaf(7, common.HexToAddress(claimAddress), service.ClaimAmount(claim.Points)).Hex()
+
+	mockRepo.EXPECT().ClaimUserPoints(ctx, claimAddress, hexutil.Encode(normalized)).Return(claim, nil)
+	mockRepo.EXPECT().SetPointClaimLeaf(ctx, 7, leaf).Return(nil)
+
+	got, err := svc.ClaimPoints(ctx, claimAddress, timestamp, signature)
+
+	assert.NoError(t, err)
+	assert.Equal(t, leaf, got.Leaf)
+	assert.Equal(t, "12500000000000000000", service.ClaimAmount(claim.Points).String())
+}
+
+// TestClaimPoints_InvalidSignature tests that expired, foreign and malformed signatures are rejected before claiming.
+func TestClaimPoints_InvalidSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	now := time.Now().Unix()
+	expired, _ := signClaim(t, claimAddress, now-600)
+	valid, _ := signClaim(t, claimAddress, now)
+
+	tests := []struct {
+		name      string
+		address   string
+		timestamp int64
+		signature string
+	}{
+		{name: "expired", address: claimAddress, timestamp: now - 600, signature: expired},
+		{name: "other timestamp", address: claimAddress, timestamp: now - 1, signature: valid},
+		{name: "other user", address: "0x00000000000000000000000000000000000000a1", timestamp: now, signature: valid},
+		{name: "malformed", address: claimAddress, timestamp: now, signature: "0x1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ClaimPoints(ctx, tt.address, tt.timestamp, tt.signature)
+			assert.Equal(t, model.ErrInvalidClaimSignature, err)
+		})
+	}
+}
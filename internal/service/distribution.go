@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/merkle"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GenerateDistribution snapshots every account's total points at cutoff into a MerkleDistributor tree,
+// with amounts converted like claim leaves, and stores the proof of every address.
+func (s *service) GenerateDistribution(ctx context.Context, cutoff time.Time) (*model.MerkleDistribution, []model.MerkleProof, error) {
+	snapshot, err := s.repo.GetPointsSnapshot(ctx, cutoff)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	balances := make([]merkle.Balance, 0, len(snapshot))
+	for _, balance := range snapshot {
+		amount := ClaimAmount(balance.Points)
+		if amount.Sign() <= 0 {
+			continue
+		}
+		balances = append(balances, merkle.Balance{Account: common.HexToAddress(balance.Address), Amount: amount})
+	}
+	if len(balances) == 0 {
+		return nil, nil, model.ErrNothingToDistribute
+	}
+
+	tree := merkle.NewDistribution(balances)
+	distribution := &model.MerkleDistribution{
+		Cutoff:     cutoff,
+		Root:       tree.Root.Hex(),
+		TokenTotal: tree.TokenTotal.String(),
+	}
+	proofs := make([]model.MerkleProof, len(tree.Claims))
+	for i, claim := range tree.Claims {
+		proof := make([]string, len(claim.Proof))
+		for j, hash := range claim.Proof {
+			proof[j] = hash.Hex()
+		}
+		proofs[i] = model.MerkleProof{
+			Root:    distribution.Root,
+			Cutoff:  cutoff,
+			Index:   claim.Index,
+			Address: strings.ToLower(claim.Account.Hex()),
+			Amount:  claim.Amount.String(),
+			Proof:   proof,
+		}
+	}
+
+	if err := s.repo.CreateMerkleDistribution(ctx, distribution, proofs); err != nil {
+		return nil, nil, err
+	}
+	for i := range proofs {
+		proofs[i].DistributionID = distribution.ID
+	}
+
+	return distribution, proofs, nil
+}
+
+// GetClaimProof retrieves an address's proof in the latest distribution.
+func (s *service) GetClaimProof(ctx context.Context, address string) (*model.MerkleProof, error) {
+	return s.repo.GetLatestMerkleProof(ctx, address)
+}
@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/merkle"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGenerateDistribution tests that accounts with points at the cutoff get a stored proof of their amount.
+func TestGenerateDistribution(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+	cutoff := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().GetPointsSnapshot(ctx, cutoff).Return([]model.PointsBalance{
+		{Address: "0x00000000000000000000000000000000000000b2", Points: model.NewDecimalFromFloat(1.5)},
+		{Address: "0x00000000000000000000000000000000000000a1", Points: model.NewDecimalFromFloat(10)},
+		{Address: "0x00000000000000000000000000000000000000c3", Points: model.NewDecimalFromFloat(0.0000000000000000001)},
+	}, nil)
+	mockRepo.EXPECT().CreateMerkleDistribution(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, distribution *model.MerkleDistribution, _ []model.MerkleProof) error {
+			distribution.ID = 9
+			return nil
+		})
+
+	distribution, proofs, err := svc.GenerateDistribution(ctx, cutoff)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, distribution.ID)
+	assert.Equal(t, "11500000000000000000", distribution.TokenTotal)
+	if assert.Len(t, proofs, 2) {
+		assert.Equal(t, "0x00000000000000000000000000000000000000a1", proofs[0].Address)
+		assert.Equal(t, "10000000000000000000", proofs[0].Amount)
+		for _, proof := range proofs {
+			assert.Equal(t, 9, proof.DistributionID)
+			amount, _ := new(big.Int).SetString(proof.Amount, 10)
+			hashes := make([]common.Hash, len(proof.Proof))
+			for i, hash := range proof.Proof {
+				hashes[i] = common.HexToHash(hash)
+			}
+			leaf := merkle.Leaf(proof.Index, common.HexToAddress(proof.Address), amount)
+			assert.True(t, merkle.Verify(hashes, common.HexToHash(distribution.Root), leaf))
+		}
+	}
+}
+
+// TestGenerateDistribution_Empty tests that a cutoff without points is rejected.
+func TestGenerateDistribution_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetPointsSnapshot(ctx, gomock.Any()).Return(nil, nil)
+
+	_, _, err := svc.GenerateDistribution(ctx, time.Now())
+
+	assert.Equal(t, model.ErrNothingToDistribute, err)
+}
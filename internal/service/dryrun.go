@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ErrDryRun is returned by a dry run for the operations it cannot simulate.
+var ErrDryRun = errors.New("not available in a dry run")
+
+// dryRun is a Service whose reads go to the database and whose writes are recorded instead of
+// applied, so handlers can be traced without changing any state. Rows that would have been
+// created are returned as if they had been, without an ID.
+type dryRun struct {
+	*service
+	record func(method string, args any)
+}
+
+// DryRun returns a Service reading like s but passing its writes to record instead of applying them.
+func (s *service) DryRun(record func(method string, args any)) Service {
+	return &dryRun{service: s, record: record}
+}
+
+// DryRun returns a dry run recording its writes with record.
+func (d *dryRun) DryRun(record func(method string, args any)) Service {
+	return d.service.DryRun(record)
+}
+
+// ForProject returns a dry run of the service of a project recording its writes like d.
+func (d *dryRun) ForProject(projectID int) Service {
+	return d.service.ForProject(projectID).DryRun(d.record)
+}
+
+// AccumulateUserPoints records the points history entry that would be added.
+func (d *dryRun) AccumulateUserPoints(ctx context.Context, network, token, user, description string, point model.Decimal) error {
+	return d.AccumulateUserPointsWithMetadata(ctx, network, token, user, description, point, nil)
+}
+
+// AccumulateUserPointsWithMetadata records the points history entry that would be added.
+func (d *dryRun) AccumulateUserPointsWithMetadata(ctx context.Context, network, token, user, description string, point model.Decimal, metadata *model.PointsMetadata) error {
+	d.record("AccumulateUserPoints", &model.PointsHistory{
+		Network:     network,
+		Token:       token,
+		Account:     user,
+		Points:      point,
+		Description: description,
+		Metadata:    metadata,
+	})
+	return nil
+}
+
+// AccumulateSwapPoints records the points history entry of the swap that would be added.
+func (d *dryRun) AccumulateSwapPoints(ctx context.Context, history *model.SwapHistory, campaign, description string, point model.Decimal) error {
+	d.record("AccumulateUserPoints", swapPointsHistory(history, campaign, description, point))
+	return nil
+}
+
+// GetOrCreateAccount retrieves a user, recording its creation when it does not exist.
+func (d *dryRun) GetOrCreateAccount(ctx context.Context, accountId string) (*model.User, error) {
+	user, err := d.repo.GetUserByAddress(ctx, accountId)
+	if errors.Is(err, model.ErrUserNotFound) {
+		d.record("CreateAccount", accountId)
+		return &model.User{Address: accountId}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// GetOrCreateAccounts retrieves users, recording the creation of the missing ones.
+func (d *dryRun) GetOrCreateAccounts(ctx context.Context, accountIds []string) (map[string]*model.User, error) {
+	accountIds = uniqueIds(accountIds)
+	users := make(map[string]*model.User, len(accountIds))
+	if len(accountIds) == 0 {
+		return users, nil
+	}
+
+	existing, err := d.repo.GetUsersByAddresses(ctx, accountIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	for i := range existing {
+		users[existing[i].Address] = &existing[i]
+	}
+
+	if missing := missingIds(accountIds, users); len(missing) > 0 {
+		d.record("CreateAccounts", missing)
+		for _, accountId := range missing {
+			users[accountId] = &model.User{Address: accountId}
+		}
+	}
+	return users, nil
+}
+
+// GetOrCreateToken retrieves a token, recording its creation from the chain metadata when it
+// does not exist.
+func (d *dryRun) GetOrCreateToken(ctx context.Context, client *ethclient.Client, tokenId string, blockNumber int64) (*model.Token, error) {
+	if token, ok := d.tokens.get(tokenId); ok {
+		return token, nil
+	}
+
+	token, err := d.repo.GetTokenByAddress(ctx, tokenId)
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, model.ErrTokenNotFound) {
+		return nil, fmt.Errorf("failed to retrieve token %s from DB: %w", tokenId, err)
+	}
+
+	tokenInfo, err := d.getTokenInfo(ctx, client, tokenId, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token %s info: %w", tokenId, err)
+	}
+	token = &model.Token{
+		ID:       tokenId,
+		Name:     tokenInfo.Name,
+		Symbol:   tokenInfo.Symbol,
+		Decimals: tokenInfo.Decimals,
+	}
+	d.record("CreateToken", token)
+	return token, nil
+}
+
+// GetOrCreateTokens retrieves tokens one by one, recording the creation of the missing ones.
+func (d *dryRun) GetOrCreateTokens(ctx context.Context, client *ethclient.Client, tokenIds []string, blockNumber int64) (map[string]*model.Token, error) {
+	tokenIds = uniqueIds(tokenIds)
+	tokens := make(map[string]*model.Token, len(tokenIds))
+	for _, tokenId := range tokenIds {
+		token, err := d.GetOrCreateToken(ctx, client, tokenId, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		tokens[tokenId] = token
+	}
+	return tokens, nil
+}
+
+// CreateSwapHistory records the swap history entry that would be created, with the USD value
+// that would be counted towards points.
+func (d *dryRun) CreateSwapHistory(ctx context.Context, history *model.SwapHistory) error {
+	counted, err := d.countedUsdValue(ctx, history)
+	if err != nil {
+		return err
+	}
+	history.CountedUsdValue = counted
+	d.record("CreateSwapHistory", history)
+	return nil
+}
+
+// FlagRoundTripSwaps records the swap whose round trip would be flagged. The swap was not
+// recorded, so its legs are not counted and nothing is reported flagged.
+func (d *dryRun) FlagRoundTripSwaps(ctx context.Context, history *model.SwapHistory) (bool, error) {
+	d.record("FlagRoundTripSwaps", history)
+	return false, nil
+}
+
+// RecordGasSpend records the gas spend instead of storing it.
+func (d *dryRun) RecordGasSpend(ctx context.Context, spend *model.GasSpend) error {
+	d.record("RecordGasSpend", spend)
+	return nil
+}
+
+// RecordApproval records the allowance instead of setting it.
+func (d *dryRun) RecordApproval(ctx context.Context, allowance *model.Allowance) error {
+	d.record("RecordApproval", allowance)
+	return nil
+}
+
+// CreateToken records the token that would be created.
+func (d *dryRun) CreateToken(ctx context.Context, token *model.Token) error {
+	d.record("CreateToken", token)
+	return nil
+}
+
+// CreateAccount records the user that would be created.
+func (d *dryRun) CreateAccount(ctx context.Context, account *model.User) error {
+	d.record("CreateAccount", account.Address)
+	return nil
+}
+
+// RecordPoolReserves records the reserves snapshot that would be recorded.
+func (d *dryRun) RecordPoolReserves(ctx context.Context, reserves *model.PoolReserves) error {
+	d.record("RecordPoolReserves", reserves)
+	return nil
+}
+
+// RecordPositionChange records the position change that would be applied.
+func (d *dryRun) RecordPositionChange(ctx context.Context, change *model.PositionChange) error {
+	d.record("RecordPositionChange", change)
+	return nil
+}
+
+// RecordTokenPrice records the price that would be recorded.
+func (d *dryRun) RecordTokenPrice(ctx context.Context, price *model.TokenPrice) error {
+	d.record("RecordTokenPrice", price)
+	return nil
+}
+
+// UpdateUserProfile records the profile that would be saved.
+func (d *dryRun) UpdateUserProfile(ctx context.Context, profile *model.UserProfile) error {
+	d.record("UpdateUserProfile", profile)
+	return nil
+}
+
+// SyncLeaderboard is not available in a dry run.
+func (d *dryRun) SyncLeaderboard(ctx context.Context) error {
+	return fmt.Errorf("SyncLeaderboard: %w", ErrDryRun)
+}
+
+// DistributePositionRewards is not available in a dry run.
+func (d *dryRun) DistributePositionRewards(ctx context.Context, network, contract string, from, to time.Time, totalPoints model.Decimal) ([]model.PositionReward, error) {
+	return nil, fmt.Errorf("DistributePositionRewards: %w", ErrDryRun)
+}
+
+// DistributeTWAVRewards is not available in a dry run.
+func (d *dryRun) DistributeTWAVRewards(ctx context.Context, network, pool string, from, to time.Time, totalPoints model.Decimal) ([]model.TWAVReward, error) {
+	return nil, fmt.Errorf("DistributeTWAVRewards: %w", ErrDryRun)
+}
+
+// ClaimPoints is not available in a dry run.
+func (d *dryRun) ClaimPoints(ctx context.Context, address string, timestamp int64, signature string) (*model.PointClaim, error) {
+	return nil, fmt.Errorf("ClaimPoints: %w", ErrDryRun)
+}
+
+// GenerateDistribution is not available in a dry run.
+func (d *dryRun) GenerateDistribution(ctx context.Context, cutoff time.Time) (*model.MerkleDistribution, []model.MerkleProof, error) {
+	return nil, nil, fmt.Errorf("GenerateDistribution: %w", ErrDryRun)
+}
+
+// TakeBalanceSnapshot is not available in a dry run.
+func (d *dryRun) TakeBalanceSnapshot(ctx context.Context, client *ethclient.Client, network, token string, blockNumber int64, holders []string) (*model.BalanceSnapshot, []model.HolderBalance, error) {
+	return nil, nil, fmt.Errorf("TakeBalanceSnapshot: %w", ErrDryRun)
+}
+
+// CreateSignInNonce is not available in a dry run.
+func (d *dryRun) CreateSignInNonce(ctx context.Context) (*model.SignInNonce, error) {
+	return nil, fmt.Errorf("CreateSignInNonce: %w", ErrDryRun)
+}
+
+// SignIn is not available in a dry run.
+func (d *dryRun) SignIn(ctx context.Context, message, signature string) (*model.Session, error) {
+	return nil, fmt.Errorf("SignIn: %w", ErrDryRun)
+}
+
+// FundDistribution is not available in a dry run.
+func (d *dryRun) FundDistribution(ctx context.Context, distributionID int) (*model.SettlementTx, error) {
+	return nil, fmt.Errorf("FundDistribution: %w", ErrDryRun)
+}
+
+// UpdateDistributionRoot is not available in a dry run.
+func (d *dryRun) UpdateDistributionRoot(ctx context.Context, distributionID int) (*model.SettlementTx, error) {
+	return nil, fmt.Errorf("UpdateDistributionRoot: %w", ErrDryRun)
+}
+
+// ReplaceStuckSettlements is not available in a dry run.
+func (d *dryRun) ReplaceStuckSettlements(ctx context.Context, now time.Time) ([]model.SettlementTx, error) {
+	return nil, fmt.Errorf("ReplaceStuckSettlements: %w", ErrDryRun)
+}
+
+// TrackSettlements is not available in a dry run.
+func (d *dryRun) TrackSettlements(ctx context.Context) ([]model.SettlementTx, error) {
+	return nil, fmt.Errorf("TrackSettlements: %w", ErrDryRun)
+}
+
+// SendNotifications is not available in a dry run.
+func (d *dryRun) SendNotifications(ctx context.Context, now time.Time) error {
+	return fmt.Errorf("SendNotifications: %w", ErrDryRun)
+}
+
+// ApplyRetention is not available in a dry run.
+func (d *dryRun) ApplyRetention(ctx context.Context, now time.Time) error {
+	return fmt.Errorf("ApplyRetention: %w", ErrDryRun)
+}
+
+// CreateProject is not available in a dry run.
+func (d *dryRun) CreateProject(ctx context.Context, slug, name string) (*model.Project, error) {
+	return nil, fmt.Errorf("CreateProject: %w", ErrDryRun)
+}
+
+// CreateAPIKey is not available in a dry run.
+func (d *dryRun) CreateAPIKey(ctx context.Context, projectSlug string) (*model.APIKey, error) {
+	return nil, fmt.Errorf("CreateAPIKey: %w", ErrDryRun)
+}
+
+// RevokeAPIKey is not available in a dry run.
+func (d *dryRun) RevokeAPIKey(ctx context.Context, prefix string) error {
+	return fmt.Errorf("RevokeAPIKey: %w", ErrDryRun)
+}
+
+// BeginIdempotentRequest is not available in a dry run.
+func (d *dryRun) BeginIdempotentRequest(ctx context.Context, scope, key, requestHash string) (*model.IdempotentResponse, error) {
+	return nil, fmt.Errorf("BeginIdempotentRequest: %w", ErrDryRun)
+}
+
+// CompleteIdempotentRequest is not available in a dry run.
+func (d *dryRun) CompleteIdempotentRequest(ctx context.Context, scope, key string, response *model.IdempotentResponse) error {
+	return fmt.Errorf("CompleteIdempotentRequest: %w", ErrDryRun)
+}
+
+// AbortIdempotentRequest is not available in a dry run.
+func (d *dryRun) AbortIdempotentRequest(ctx context.Context, scope, key string) error {
+	return fmt.Errorf("AbortIdempotentRequest: %w", ErrDryRun)
+}
+
+// SetAddressLabel is not available in a dry run.
+func (d *dryRun) SetAddressLabel(ctx context.Context, label *model.AddressLabel) error {
+	return fmt.Errorf("SetAddressLabel: %w", ErrDryRun)
+}
+
+// DeleteAddressLabel is not available in a dry run.
+func (d *dryRun) DeleteAddressLabel(ctx context.Context, address, label, operator string) error {
+	return fmt.Errorf("DeleteAddressLabel: %w", ErrDryRun)
+}
@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// dryRunWrite is a write recorded by a dry run.
+type dryRunWrite struct {
+	method string
+	args   any
+}
+
+// TestDryRun tests that a dry run reads through the repository while recording its writes,
+// and refuses the operations it cannot simulate.
+func TestDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	var writes []dryRunWrite
+	svc := service.NewService(mockRepo).DryRun(func(method string, args any) {
+		writes = append(writes, dryRunWrite{method: method, args: args})
+	})
+
+	// Only reads reach the repository
+	mockRepo.EXPECT().GetUserByAddress(ctx, "0xnew").Return(nil, model.ErrUserNotFound)
+	mockRepo.EXPECT().GetUsersByAddresses(ctx, []string{"0xold", "0xnew"}).Return([]model.User{{ID: 7, Address: "0xold"}}, nil)
+	mockRepo.EXPECT().GetTokenByAddress(ctx, "0xusdc").Return(&model.Token{ID: "0xusdc", Decimals: 6}, nil)
+
+	user, err := svc.GetOrCreateAccount(ctx, "0xnew")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.User{Address: "0xnew"}, user)
+
+	users, err := svc.GetOrCreateAccounts(ctx, []string{"0xold", "0xnew"})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, users["0xold"].ID)
+	assert.Equal(t, "0xnew", users["0xnew"].Address)
+
+	token, err := svc.GetOrCreateToken(ctx, nil, "0xusdc", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), token.Decimals)
+
+	history := &model.SwapHistory{Account: "0xnew", Token: "0xpool"}
+	assert.NoError(t, svc.CreateSwapHistory(ctx, history))
+	assert.NoError(t, svc.AccumulateUserPoints(ctx, "mainnet", "0xpool", "0xnew", "onboarding_task", model.NewDecimalFromFloat(100)))
+
+	assert.Equal(t, []dryRunWrite{
+		{method: "CreateAccount", args: "0xnew"},
+		{method: "CreateAccounts", args: []string{"0xnew"}},
+		{method: "CreateSwapHistory", args: history},
+		{method: "AccumulateUserPoints", args: &model.PointsHistory{
+			Network:     "mainnet",
+			Token:       "0xpool",
+			Account:     "0xnew",
+			Points:      model.NewDecimalFromFloat(100),
+			Description: "onboarding_task",
+		}},
+	}, writes)
+
+	_, err = svc.ClaimPoints(ctx, "0xnew", 0, "0x")
+	assert.ErrorIs(t, err, service.ErrDryRun)
+	assert.ErrorIs(t, svc.SyncLeaderboard(ctx), service.ErrDryRun)
+}
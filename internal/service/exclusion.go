@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"hw/internal/model"
+)
+
+// exclusion returns the accounts configured to be left out of the leaderboard and of points
+// distributions, with their addresses lowercased.
+func (s *service) exclusion() model.Exclusion {
+	exclude := model.Exclusion{Labels: s.points.ExcludeLabels}
+	for _, address := range s.points.ExcludeAddresses {
+		exclude.Addresses = append(exclude.Addresses, strings.ToLower(address))
+	}
+	return exclude
+}
+
+// isExcluded reports whether an account is left out of the leaderboard and of points distributions.
+func (s *service) isExcluded(ctx context.Context, address string) (bool, error) {
+	exclude := s.exclusion()
+	address = strings.ToLower(address)
+	if slices.Contains(exclude.Addresses, address) {
+		return true, nil
+	}
+	if len(exclude.Labels) == 0 {
+		return false, nil
+	}
+
+	labels, err := s.repo.GetAddressLabels(ctx, address, "")
+	if err != nil {
+		return false, err
+	}
+	for _, label := range labels {
+		if slices.Contains(exclude.Labels, label.Label) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// excludedAccounts returns the set of accounts left out of the leaderboard and of points distributions.
+func (s *service) excludedAccounts(ctx context.Context) (map[string]bool, error) {
+	exclude := s.exclusion()
+	excluded := make(map[string]bool, len(exclude.Addresses))
+	for _, address := range exclude.Addresses {
+		excluded[address] = true
+	}
+	for _, label := range exclude.Labels {
+		labelled, err := s.repo.GetAddressLabels(ctx, "", label)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range labelled {
+			excluded[l.Address] = true
+		}
+	}
+	return excluded, nil
+}
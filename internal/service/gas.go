@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"hw/internal/model"
+)
+
+// RecordGasSpend records the gas fee paid by an account for an indexed transaction, once per transaction.
+func (s *service) RecordGasSpend(ctx context.Context, spend *model.GasSpend) error {
+	return s.handlerStore.RecordGasSpend(ctx, spend)
+}
+
+// GetGasLeaderboard retrieves the accounts that paid the most gas on a network, largest fees first.
+func (s *service) GetGasLeaderboard(ctx context.Context, network string, limit int) ([]model.GasTotal, error) {
+	return s.handlerStore.GetGasLeaderboard(ctx, network, limit)
+}
+
+// GetUserGasStats retrieves the gas a user paid for indexed transactions on each network.
+func (s *service) GetUserGasStats(ctx context.Context, account string) ([]model.GasTotal, error) {
+	return s.handlerStore.GetAccountGasTotals(ctx, account)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"hw/internal/model"
+)
+
+// HandlerStore stores the high-volume analytics outputs of handlers, pool reserves snapshots,
+// token prices and gas spend, and serves the reads of them. The repository implements it on
+// Postgres; a store for an analytics database such as ClickHouse or Timescale can take these
+// tables over with WithHandlerStore, while users, swaps and points stay in Postgres.
+type HandlerStore interface {
+	// UpsertPoolReserves records the reserves of a pool at a block, keeping the latest Sync event of the block.
+	UpsertPoolReserves(ctx context.Context, reserves *model.PoolReserves) error
+	// GetLatestPoolReserves retrieves the latest reserves snapshot of a pool.
+	GetLatestPoolReserves(ctx context.Context, pool, network string) (*model.PoolReserves, error)
+	// UpsertTokenPrice records a price in the minute bucket of a token.
+	UpsertTokenPrice(ctx context.Context, price *model.TokenPrice) error
+	// GetTokenPriceCandles aggregates the minute prices of a token within [from, to) into candles of the given interval.
+	GetTokenPriceCandles(ctx context.Context, token, network string, from, to time.Time, interval time.Duration) ([]model.PriceCandle, error)
+	// GetLatestTokenPrice retrieves the close of the latest minute bucket of a token within [since, at].
+	GetLatestTokenPrice(ctx context.Context, token, network string, since, at time.Time) (*model.TokenPrice, error)
+	// RecordGasSpend records the gas fee paid for a transaction, once per transaction.
+	RecordGasSpend(ctx context.Context, spend *model.GasSpend) error
+	// GetGasLeaderboard retrieves the accounts that paid the most gas on a network, largest fees first.
+	GetGasLeaderboard(ctx context.Context, network string, limit int) ([]model.GasTotal, error)
+	// GetAccountGasTotals retrieves the gas an account paid on each network.
+	GetAccountGasTotals(ctx context.Context, account string) ([]model.GasTotal, error)
+}
+
+// WithHandlerStore stores the analytics outputs of handlers in store instead of the repository.
+// Handlers read token prices back while valuing swaps, so the store must serve its own writes
+// without noticeable delay.
+func WithHandlerStore(store HandlerStore) Option {
+	return func(s *service) {
+		s.handlerStore = store
+	}
+}
@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeHandlerStore keeps token prices and gas spend in memory; its other methods are not used.
+type fakeHandlerStore struct {
+	service.HandlerStore
+	prices []model.TokenPrice
+	spends []model.GasSpend
+}
+
+func (s *fakeHandlerStore) UpsertTokenPrice(ctx context.Context, price *model.TokenPrice) error {
+	s.prices = append(s.prices, *price)
+	return nil
+}
+
+func (s *fakeHandlerStore) GetLatestTokenPrice(ctx context.Context, token, network string, since, at time.Time) (*model.TokenPrice, error) {
+	for i := len(s.prices) - 1; i >= 0; i-- {
+		price := s.prices[i]
+		if price.Token == token && price.Network == network && !price.Time.Before(since) && !price.Time.After(at) {
+			return &price, nil
+		}
+	}
+	return nil, model.ErrPriceNotFound
+}
+
+func (s *fakeHandlerStore) RecordGasSpend(ctx context.Context, spend *model.GasSpend) error {
+	s.spends = append(s.spends, *spend)
+	return nil
+}
+
+// TestWithHandlerStore tests that the analytics outputs of handlers are written to and read from
+// the handler store, leaving the repository untouched.
+func TestWithHandlerStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	store := &fakeHandlerStore{}
+	svc := service.NewService(mockRepo, service.WithHandlerStore(store))
+	ctx := context.Background()
+	at := time.Date(2024, 11, 6, 12, 0, 0, 0, time.UTC)
+
+	price := &model.TokenPrice{Token: "0xweth", Network: "mainnet", Time: at.Add(-time.Minute), Price: model.NewDecimalFromFloat(2500)}
+	assert.NoError(t, svc.RecordTokenPrice(ctx, price))
+	latest, err := svc.GetLatestTokenPrice(ctx, "0xweth", "mainnet", at, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, price, latest)
+
+	_, err = svc.GetLatestTokenPrice(ctx, "0xweth", "base", at, time.Hour)
+	assert.ErrorIs(t, err, model.ErrPriceNotFound)
+
+	assert.NoError(t, svc.RecordGasSpend(ctx, &model.GasSpend{Network: "mainnet", TransactionHash: "0xtx"}))
+	assert.Len(t, store.spends, 1)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"hw/internal/model"
+)
+
+// IdempotencyKeyTTL is how long the response of a request is replayed to the retries sent with
+// its idempotency key; the key can be used for another request afterwards.
+var IdempotencyKeyTTL = 24 * time.Hour
+
+// BeginIdempotentRequest reserves the idempotency key of a scope for the request hashed
+// requestHash. It returns nil when the request is to be processed, then completed or aborted, and
+// the stored response when the request was already processed. A key used for another request
+// returns model.ErrIdempotencyKeyReused, and one whose request is still processed
+// model.ErrIdempotencyKeyInUse.
+func (s *service) BeginIdempotentRequest(ctx context.Context, scope, key, requestHash string) (*model.IdempotentResponse, error) {
+	if key == "" || len(key) > 255 {
+		return nil, model.NewError(model.ErrInvalid, "idempotency key must be 1 to 255 characters")
+	}
+	reserved, existingHash, response, err := s.repo.ReserveIdempotencyKey(ctx, scope, key, requestHash, time.Now().Add(-IdempotencyKeyTTL))
+	switch {
+	case err != nil:
+		return nil, err
+	case reserved:
+		return nil, nil
+	case existingHash != requestHash:
+		return nil, model.ErrIdempotencyKeyReused
+	case response == nil:
+		return nil, model.ErrIdempotencyKeyInUse
+	default:
+		return response, nil
+	}
+}
+
+// CompleteIdempotentRequest stores the response of a request begun with BeginIdempotentRequest,
+// replayed to its retries.
+func (s *service) CompleteIdempotentRequest(ctx context.Context, scope, key string, response *model.IdempotentResponse) error {
+	return s.repo.CompleteIdempotencyKey(ctx, scope, key, response)
+}
+
+// AbortIdempotentRequest releases the key of a request begun with BeginIdempotentRequest that
+// failed without effect, so its retries are processed.
+func (s *service) AbortIdempotentRequest(ctx context.Context, scope, key string) error {
+	return s.repo.ReleaseIdempotencyKey(ctx, scope, key)
+}
@@ -0,0 +1,46 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestBeginIdempotentRequest tests the outcome of beginning a request for each state of its key.
+func TestBeginIdempotentRequest(t *testing.T) {
+	stored := &model.IdempotentResponse{Status: 200, Body: []byte(`{}`)}
+	tests := []struct {
+		name         string
+		reserved     bool
+		existingHash string
+		response     *model.IdempotentResponse
+		want         *model.IdempotentResponse
+		wantErr      error
+	}{
+		{name: "reserved", reserved: true},
+		{name: "replayed", existingHash: "hash", response: stored, want: stored},
+		{name: "in progress", existingHash: "hash", wantErr: model.ErrIdempotencyKeyInUse},
+		{name: "another request", existingHash: "other", response: stored, wantErr: model.ErrIdempotencyKeyReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := repositoryMock.NewMockRepository(ctrl)
+			svc := service.NewService(mockRepo)
+			ctx := context.Background()
+
+			mockRepo.EXPECT().ReserveIdempotencyKey(ctx, "acme", "key-1", "hash", gomock.Any()).Return(tt.reserved, tt.existingHash, tt.response, nil)
+
+			response, err := svc.BeginIdempotentRequest(ctx, "acme", "key-1", "hash")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, response)
+		})
+	}
+}
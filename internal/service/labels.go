@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"hw/internal/model"
+	"hw/pkg/logger"
+)
+
+// SetAddressLabel labels an address, or renames its label, by the admin operator in its UpdatedBy.
+// The label must be one of model.AddressLabels.
+// An address given a label excluded from the leaderboard is taken off its mirror.
+func (s *service) SetAddressLabel(ctx context.Context, label *model.AddressLabel) error {
+	if !slices.Contains(model.AddressLabels, label.Label) {
+		return model.ErrUnknownAddressLabel
+	}
+	label.Address = strings.ToLower(label.Address)
+	if err := s.repo.UpsertAddressLabel(ctx, label); err != nil {
+		return err
+	}
+
+	if s.leaderboard != nil && slices.Contains(s.points.ExcludeLabels, label.Label) {
+		if err := s.leaderboard.Remove(ctx, label.Address); err != nil {
+			logger.Warnf("Failed to remove %s from the leaderboard mirror: %v", label.Address, err)
+		}
+	}
+	return nil
+}
+
+// DeleteAddressLabel removes a label from an address by an admin operator.
+func (s *service) DeleteAddressLabel(ctx context.Context, address, label, operator string) error {
+	return s.repo.DeleteAddressLabel(ctx, strings.ToLower(address), label, operator)
+}
+
+// ListAddressLabels retrieves the labelled addresses, of one label unless label is empty.
+func (s *service) ListAddressLabels(ctx context.Context, label string) ([]model.AddressLabel, error) {
+	return s.repo.GetAddressLabels(ctx, "", label)
+}
+
+// GetAddressLabels retrieves the labels of an address.
+func (s *service) GetAddressLabels(ctx context.Context, address string) ([]string, error) {
+	labels, err := s.repo.GetAddressLabels(ctx, strings.ToLower(address), "")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Label)
+	}
+	return names, nil
+}
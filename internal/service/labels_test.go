@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestSetAddressLabel tests that addresses are labelled lowercased and unknown labels are rejected.
+func TestSetAddressLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	err := svc.SetAddressLabel(ctx, &model.AddressLabel{Address: "0xABC", Label: "whale"})
+	assert.ErrorIs(t, err, model.ErrUnknownAddressLabel)
+
+	mockRepo.EXPECT().UpsertAddressLabel(ctx, &model.AddressLabel{Address: "0xabc", Label: model.LabelExchange, Name: "Binance 14"}).Return(nil)
+	assert.NoError(t, svc.SetAddressLabel(ctx, &model.AddressLabel{Address: "0xABC", Label: model.LabelExchange, Name: "Binance 14"}))
+}
+
+// TestGetAddressLabels tests that only the labels of an address are returned.
+func TestGetAddressLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetAddressLabels(ctx, "0xabc", "").Return([]model.AddressLabel{
+		{Address: "0xabc", Label: model.LabelContract, Name: "Vault"},
+		{Address: "0xabc", Label: model.LabelTeamWallet, Name: "Treasury"},
+	}, nil)
+
+	labels, err := svc.GetAddressLabels(ctx, "0xABC")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.LabelContract, model.LabelTeamWallet}, labels)
+}
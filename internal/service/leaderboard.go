@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"hw/internal/model"
+	"hw/pkg/cache"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// errLeaderboardNotReady is returned by a LeaderboardStore that has not been rebuilt from Postgres yet.
+var errLeaderboardNotReady = errors.New("leaderboard store is not ready")
+
+// LeaderboardStore mirrors users' total points so the leaderboard can be served without Postgres.
+type LeaderboardStore interface {
+	// IncrBy adds points to a user's mirrored total.
+	IncrBy(ctx context.Context, address string, points model.Decimal) error
+	// Top returns every user ordered by points, highest first.
+	Top(ctx context.Context) ([]model.User, error)
+	// Rank returns a user's position on the leaderboard.
+	Rank(ctx context.Context, address string) (*model.LeaderboardRank, error)
+	// Replace rebuilds the mirror from the given users.
+	Replace(ctx context.Context, users []model.User) error
+	// Remove takes a user off the mirror.
+	Remove(ctx context.Context, address string) error
+}
+
+// WithLeaderboardStore serves the leaderboard from a mirror kept up to date by AccumulateUserPoints.
+func WithLeaderboardStore(store LeaderboardStore) Option {
+	return func(s *service) {
+		s.leaderboard = store
+	}
+}
+
+// leaderboardReplaceBatch is the number of members added per ZADD while rebuilding.
+const leaderboardReplaceBatch = 1000
+
+// RedisLeaderboard is a LeaderboardStore backed by a Redis sorted set scored by total points.
+// A separate ready key marks the set as complete, so readers fall back to Postgres until
+// the set has been rebuilt once.
+type RedisLeaderboard struct {
+	client   redis.UniversalClient
+	key      string
+	readyKey string
+}
+
+// NewRedisLeaderboard creates a RedisLeaderboard storing the sorted set under key.
+func NewRedisLeaderboard(client redis.UniversalClient, key string) *RedisLeaderboard {
+	return &RedisLeaderboard{
+		client:   client,
+		key:      key,
+		readyKey: key + ":ready",
+	}
+}
+
+// NewRedisLeaderboardFromConfig creates a RedisLeaderboard on the cache Redis, keyed "<cache prefix>leaderboard".
+func NewRedisLeaderboardFromConfig(cfg config.Cache) *RedisLeaderboard {
+	return NewRedisLeaderboard(cache.NewRedisClient(cfg), cfg.Prefix+"leaderboard")
+}
+
+// IncrBy adds points to a user's score.
+func (l *RedisLeaderboard) IncrBy(ctx context.Context, address string, points model.Decimal) error {
+	if err := l.client.ZIncrBy(ctx, l.key, points.InexactFloat64(), address).Err(); err != nil {
+		return fmt.Errorf("failed to increment leaderboard score: %w", err)
+	}
+	return nil
+}
+
+// Top returns every user ordered by points, highest first.
+func (l *RedisLeaderboard) Top(ctx context.Context) ([]model.User, error) {
+	var (
+		ready   *redis.IntCmd
+		members *redis.ZSliceCmd
+	)
+	_, err := l.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		ready = pipe.Exists(ctx, l.readyKey)
+		members = pipe.ZRevRangeWithScores(ctx, l.key, 0, -1)
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to read leaderboard: %w", err)
+	}
+	if ready.Val() == 0 {
+		return nil, errLeaderboardNotReady
+	}
+
+	users := make([]model.User, 0, len(members.Val()))
+	for _, member := range members.Val() {
+		users = append(users, model.User{
+			Address:     member.Member.(string),
+			TotalPoints: scoreToPoints(member.Score),
+		})
+	}
+	return users, nil
+}
+
+// Rank returns a user's position on the leaderboard. Users with equal points share a rank,
+// matching the Postgres query.
+func (l *RedisLeaderboard) Rank(ctx context.Context, address string) (*model.LeaderboardRank, error) {
+	var (
+		ready *redis.IntCmd
+		score *redis.FloatCmd
+	)
+	_, err := l.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		ready = pipe.Exists(ctx, l.readyKey)
+		score = pipe.ZScore(ctx, l.key, address)
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to read leaderboard score: %w", err)
+	}
+	if ready.Val() == 0 {
+		return nil, errLeaderboardNotReady
+	}
+	if errors.Is(score.Err(), redis.Nil) {
+		return nil, model.ErrUserNotFound
+	}
+
+	higher, err := l.client.ZCount(ctx, l.key, "("+strconv.FormatFloat(score.Val(), 'f', -1, 64), "+inf").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count leaderboard scores: %w", err)
+	}
+
+	return &model.LeaderboardRank{
+		Address: address,
+		Points:  scoreToPoints(score.Val()),
+		Rank:    higher + 1,
+	}, nil
+}
+
+// Replace rebuilds the sorted set under a temporary key and swaps it in atomically.
+func (l *RedisLeaderboard) Replace(ctx context.Context, users []model.User) error {
+	tmpKey := l.key + ":rebuild"
+	if err := l.client.Del(ctx, tmpKey).Err(); err != nil {
+		return fmt.Errorf("failed to clear leaderboard rebuild: %w", err)
+	}
+
+	for start := 0; start < len(users); start += leaderboardReplaceBatch {
+		end := min(start+leaderboardReplaceBatch, len(users))
+		members := make([]redis.Z, 0, end-start)
+		for _, user := range users[start:end] {
+			members = append(members, redis.Z{Score: user.TotalPoints.InexactFloat64(), Member: user.Address})
+		}
+		if err := l.client.ZAdd(ctx, tmpKey, members...).Err(); err != nil {
+			return fmt.Errorf("failed to rebuild leaderboard: %w", err)
+		}
+	}
+
+	_, err := l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(users) > 0 {
+			pipe.Rename(ctx, tmpKey, l.key)
+		} else {
+			pipe.Del(ctx, l.key)
+		}
+		pipe.Set(ctx, l.readyKey, "1", 0)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to swap leaderboard: %w", err)
+	}
+	return nil
+}
+
+// Remove takes a user off the sorted set.
+func (l *RedisLeaderboard) Remove(ctx context.Context, address string) error {
+	if err := l.client.ZRem(ctx, l.key, address).Err(); err != nil {
+		return fmt.Errorf("failed to remove from leaderboard: %w", err)
+	}
+	return nil
+}
+
+// SyncLeaderboard rebuilds the leaderboard mirror from Postgres. It should run before points
+// are accumulated, since increments made during the rebuild are overwritten.
+func (s *service) SyncLeaderboard(ctx context.Context) error {
+	if s.leaderboard == nil {
+		return nil
+	}
+
+	users, err := s.repo.GetLeaderboard(ctx, s.exclusion())
+	if err != nil {
+		return err
+	}
+	if err := s.leaderboard.Replace(ctx, users); err != nil {
+		return err
+	}
+
+	logger.Infof("Rebuilt leaderboard mirror with %d users", len(users))
+	return nil
+}
+
+// GetUserRank retrieves a user's position on the leaderboard, from the mirror when available.
+func (s *service) GetUserRank(ctx context.Context, address string) (*model.LeaderboardRank, error) {
+	if s.leaderboard != nil {
+		rank, err := s.leaderboard.Rank(ctx, address)
+		if err == nil || errors.Is(err, model.ErrUserNotFound) {
+			return rank, err
+		}
+		logLeaderboardFallback(err)
+	}
+	return s.repo.GetUserRank(ctx, address, s.exclusion())
+}
+
+// mirrorUserPoints applies committed points to the leaderboard mirror, unless the user is excluded
+// from the leaderboard. Failures are only logged: Postgres stays the source of truth and the mirror
+// is corrected by the next SyncLeaderboard.
+func (s *service) mirrorUserPoints(ctx context.Context, address string, points model.Decimal) {
+	if s.leaderboard == nil {
+		return
+	}
+	excluded, err := s.isExcluded(ctx, address)
+	if err != nil {
+		logger.Warnf("Failed to mirror points of %s to the leaderboard: %v", address, err)
+		return
+	}
+	if excluded {
+		return
+	}
+	if err := s.leaderboard.IncrBy(ctx, address, points); err != nil {
+		logger.Warnf("Failed to mirror points of %s to the leaderboard: %v", address, err)
+	}
+}
+
+// scoreToPoints converts a sorted set score back to points. Scores are float sums, so they are
+// rounded to the 3 decimals points are stored with in Postgres.
+func scoreToPoints(score float64) model.Decimal {
+	return model.NewDecimalFromFloat(math.Round(score*1000) / 1000)
+}
+
+func logLeaderboardFallback(err error) {
+	if !errors.Is(err, errLeaderboardNotReady) {
+		logger.Warnf("Falling back to Postgres for the leaderboard: %v", err)
+	}
+}
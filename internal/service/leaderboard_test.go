@@ -0,0 +1,259 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestRedisLeaderboard_Top tests reading the sorted set once it has been marked ready.
+func TestRedisLeaderboard_Top(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	store := service.NewRedisLeaderboard(db, "leaderboard")
+	ctx := context.Background()
+
+	mock.ExpectExists("leaderboard:ready").SetVal(1)
+	mock.ExpectZRevRangeWithScores("leaderboard", 0, -1).SetVal([]redis.Z{
+		{Member: "0xa", Score: 200},
+		{Member: "0xb", Score: 100},
+	})
+
+	users, err := store.Top(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.User{
+		{Address: "0xa", TotalPoints: model.NewDecimalFromFloat(200)},
+		{Address: "0xb", TotalPoints: model.NewDecimalFromFloat(100)},
+	}, users)
+
+	mock.ExpectExists("leaderboard:ready").SetVal(0)
+	mock.ExpectZRevRangeWithScores("leaderboard", 0, -1).SetVal(nil)
+
+	_, err = store.Top(ctx)
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRedisLeaderboard_Rank tests that users with more points than the user determine the rank.
+func TestRedisLeaderboard_Rank(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	store := service.NewRedisLeaderboard(db, "leaderboard")
+	ctx := context.Background()
+
+	mock.ExpectExists("leaderboard:ready").SetVal(1)
+	mock.ExpectZScore("leaderboard", "0xa").SetVal(42.5)
+	mock.ExpectZCount("leaderboard", "(42.5", "+inf").SetVal(2)
+
+	rank, err := store.Rank(ctx, "0xa")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.LeaderboardRank{Address: "0xa", Points: model.NewDecimalFromFloat(42.5), Rank: 3}, rank)
+
+	mock.ExpectExists("leaderboard:ready").SetVal(1)
+	mock.ExpectZScore("leaderboard", "0xb").RedisNil()
+
+	_, err = store.Rank(ctx, "0xb")
+
+	assert.Equal(t, model.ErrUserNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRedisLeaderboard_Replace tests that the rebuilt set is swapped in and marked ready.
+func TestRedisLeaderboard_Replace(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	store := service.NewRedisLeaderboard(db, "leaderboard")
+
+	mock.ExpectDel("leaderboard:rebuild").SetVal(0)
+	mock.ExpectZAdd("leaderboard:rebuild", redis.Z{Score: 10, Member: "0xa"}).SetVal(1)
+	mock.ExpectTxPipeline()
+	mock.ExpectRename("leaderboard:rebuild", "leaderboard").SetVal("OK")
+	mock.ExpectSet("leaderboard:ready", "1", 0).SetVal("OK")
+	mock.ExpectTxPipelineExec()
+
+	err := store.Replace(context.Background(), []model.User{{Address: "0xa", TotalPoints: model.NewDecimalFromFloat(10)}})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// fakeLeaderboard is an in-memory LeaderboardStore.
+type fakeLeaderboard struct {
+	points map[string]model.Decimal
+	err    error
+}
+
+func (l *fakeLeaderboard) IncrBy(ctx context.Context, address string, points model.Decimal) error {
+	l.points[address] = l.points[address].Add(points)
+	return nil
+}
+
+func (l *fakeLeaderboard) Top(ctx context.Context) ([]model.User, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	var users []model.User
+	for address, points := range l.points {
+		users = append(users, model.User{Address: address, TotalPoints: points})
+	}
+	return users, nil
+}
+
+func (l *fakeLeaderboard) Rank(ctx context.Context, address string) (*model.LeaderboardRank, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return &model.LeaderboardRank{Address: address, Points: l.points[address], Rank: 1}, nil
+}
+
+func (l *fakeLeaderboard) Remove(ctx context.Context, address string) error {
+	delete(l.points, address)
+	return nil
+}
+
+func (l *fakeLeaderboard) Replace(ctx context.Context, users []model.User) error {
+	l.points = make(map[string]model.Decimal)
+	for _, user := range users {
+		l.points[user.Address] = user.TotalPoints
+	}
+	return nil
+}
+
+// TestLeaderboardMirror tests that committed points reach the mirror, which then serves the leaderboard.
+func TestLeaderboardMirror(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	store := &fakeLeaderboard{}
+	svc := service.NewService(mockRepo, service.WithLeaderboardStore(store))
+
+	ctx := context.Background()
+	user := "0xa"
+
+	mockRepo.EXPECT().GetLeaderboard(ctx, model.Exclusion{}).Return([]model.User{{Address: user, TotalPoints: model.NewDecimalFromFloat(10)}}, nil)
+	assert.NoError(t, svc.SyncLeaderboard(ctx))
+
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().
+		CreatePointsHistory(ctx, gomock.AssignableToTypeOf(&model.PointsHistory{})).
+		DoAndReturn(func(ctx context.Context, ph *model.PointsHistory) error {
+			ph.ID = 1
+			return nil
+		})
+	mockRepo.EXPECT().UpsertUserPoints(ctx, user, model.NewDecimalFromFloat(5)).Return(nil)
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+
+	assert.NoError(t, svc.AccumulateUserPoints(ctx, "mainnet", "tokenABC", user, "swap", model.NewDecimalFromFloat(5)))
+
+	users, err := svc.GetLeaderboard(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.User{{Address: user, TotalPoints: model.NewDecimalFromFloat(15)}}, users)
+}
+
+// TestLeaderboardMirror_OtherProject tests that the leaderboard of another project than the one
+// mirrored is read from the repository of that project.
+func TestLeaderboardMirror_OtherProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	projectRepo := repositoryMock.NewMockRepository(ctrl)
+	store := &fakeLeaderboard{points: map[string]model.Decimal{"0xa": model.NewDecimalFromFloat(10)}}
+	svc := service.NewService(mockRepo, service.WithLeaderboardStore(store))
+
+	ctx := context.Background()
+	expected := []model.User{{Address: "0xb", TotalPoints: model.NewDecimalFromFloat(3)}}
+	mockRepo.EXPECT().ForProject(3).Return(projectRepo)
+	projectRepo.EXPECT().GetLeaderboard(ctx, model.Exclusion{}).Return(expected, nil)
+
+	users, err := svc.ForProject(3).GetLeaderboard(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, users)
+	assert.Same(t, svc, svc.ForProject(model.DefaultProjectID), "the service of its own project is itself")
+}
+
+// TestLeaderboardMirror_Fallback tests that Postgres serves the leaderboard when the mirror fails.
+func TestLeaderboardMirror_Fallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	store := &fakeLeaderboard{err: errors.New("connection refused")}
+	svc := service.NewService(mockRepo, service.WithLeaderboardStore(store))
+
+	ctx := context.Background()
+	users := []model.User{{Address: "0xa", TotalPoints: model.NewDecimalFromFloat(10)}}
+	rank := &model.LeaderboardRank{Address: "0xa", Points: model.NewDecimalFromFloat(10), Rank: 1}
+
+	mockRepo.EXPECT().GetLeaderboard(ctx, model.Exclusion{}).Return(users, nil)
+	mockRepo.EXPECT().GetUserRank(ctx, "0xa", model.Exclusion{}).Return(rank, nil)
+
+	result, err := svc.GetLeaderboard(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, users, result)
+
+	userRank, err := svc.GetUserRank(ctx, "0xa")
+	assert.NoError(t, err)
+	assert.Equal(t, rank, userRank)
+}
+
+// TestLeaderboardMirror_Exclusion tests that excluded users are left out of the mirror, by address
+// and by label, and taken off it when given an excluded label.
+func TestLeaderboardMirror_Exclusion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	store := &fakeLeaderboard{}
+	points := config.Points{ExcludeLabels: []string{model.LabelTeamWallet}, ExcludeAddresses: []string{"0xABC"}}
+	svc := service.NewService(mockRepo, service.WithLeaderboardStore(store), service.WithPoints(points))
+
+	ctx := context.Background()
+	exclude := model.Exclusion{Labels: []string{model.LabelTeamWallet}, Addresses: []string{"0xabc"}}
+
+	mockRepo.EXPECT().GetLeaderboard(ctx, exclude).Return([]model.User{{Address: "0xa", TotalPoints: model.NewDecimalFromFloat(10)}}, nil)
+	assert.NoError(t, svc.SyncLeaderboard(ctx))
+
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil).Times(3)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo).Times(3)
+	mockRepo.EXPECT().CreatePointsHistory(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, ph *model.PointsHistory) error {
+		ph.ID = 1
+		return nil
+	}).Times(3)
+	mockRepo.EXPECT().UpsertUserPoints(ctx, gomock.Any(), model.NewDecimalFromFloat(5)).Return(nil).Times(3)
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.Any()).Return(nil).Times(3)
+	mockTx.EXPECT().Commit(ctx).Return(nil).Times(3)
+	mockRepo.EXPECT().GetAddressLabels(ctx, "0xa", "").Return(nil, nil)
+	mockRepo.EXPECT().GetAddressLabels(ctx, "0xteam", "").Return([]model.AddressLabel{{Address: "0xteam", Label: model.LabelTeamWallet}}, nil)
+
+	for _, user := range []string{"0xa", "0xabc", "0xteam"} {
+		assert.NoError(t, svc.AccumulateUserPoints(ctx, "mainnet", "tokenABC", user, "swap", model.NewDecimalFromFloat(5)))
+	}
+
+	users, err := svc.GetLeaderboard(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.User{{Address: "0xa", TotalPoints: model.NewDecimalFromFloat(15)}}, users)
+
+	mockRepo.EXPECT().UpsertAddressLabel(ctx, gomock.Any()).Return(nil)
+	assert.NoError(t, svc.SetAddressLabel(ctx, &model.AddressLabel{Address: "0xa", Label: model.LabelTeamWallet}))
+
+	users, err = svc.GetLeaderboard(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/logger"
+)
+
+// LiveEventBuffer is the number of live events queued for a subscriber; the events published
+// while its queue is full are dropped for that subscriber.
+var LiveEventBuffer = 64
+
+// LiveEventsRetryInterval is how long ListenLiveEvents waits before listening again after the
+// listening connection failed.
+var LiveEventsRetryInterval = 5 * time.Second
+
+// WithLiveEvents publishes every recorded swap and credited points award as a live event.
+func WithLiveEvents() Option {
+	return func(s *service) {
+		s.publishLive = true
+	}
+}
+
+// liveBroker fans the live events received by an API server out to its subscribers.
+type liveBroker struct {
+	mutex       sync.RWMutex
+	subscribers map[*liveSubscriber]struct{}
+}
+
+type liveSubscriber struct {
+	filter model.LiveEventFilter
+	events chan model.LiveEvent
+}
+
+func newLiveBroker() *liveBroker {
+	return &liveBroker{subscribers: make(map[*liveSubscriber]struct{})}
+}
+
+// subscribe registers a subscriber to the events matching filter until unsubscribe is called.
+func (b *liveBroker) subscribe(filter model.LiveEventFilter) (<-chan model.LiveEvent, func()) {
+	sub := &liveSubscriber{filter: filter, events: make(chan model.LiveEvent, LiveEventBuffer)}
+	b.mutex.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mutex.Unlock()
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subscribers, sub)
+			b.mutex.Unlock()
+		})
+	}
+}
+
+// publish queues event for the matching subscribers without waiting for slow ones.
+func (b *liveBroker) publish(event model.LiveEvent) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for sub := range b.subscribers {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// SubscribeLiveEvents returns the live events matching filter received from now on, until
+// unsubscribe is called. Events are only received while ListenLiveEvents runs.
+// The points awards are those of the project of s.
+func (s *service) SubscribeLiveEvents(filter model.LiveEventFilter) (<-chan model.LiveEvent, func()) {
+	filter.Project = s.project
+	return s.live.subscribe(filter)
+}
+
+// ListenLiveEvents receives the live events published by the indexer and passes them to the
+// subscribers until ctx is done, listening again after LiveEventsRetryInterval when the
+// connection fails.
+func (s *service) ListenLiveEvents(ctx context.Context) error {
+	for {
+		err := s.repo.ListenLiveEvents(ctx, s.live.publish)
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.Warnf("Listening to live events failed, retrying in %s: %v", LiveEventsRetryInterval, err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(LiveEventsRetryInterval):
+		}
+	}
+}
+
+// publishLiveEvent publishes event when live events are enabled. Live events are best-effort:
+// a failure is logged, as the swap or points are already recorded.
+func (s *service) publishLiveEvent(ctx context.Context, event *model.LiveEvent) {
+	if !s.publishLive {
+		return
+	}
+	if err := s.repo.NotifyLiveEvent(ctx, event); err != nil && !errors.Is(err, context.Canceled) {
+		logger.Warnf("Failed to publish live %s event of %s: %v", event.Type, event.Account, err)
+	}
+}
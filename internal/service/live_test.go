@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestCreateSwapHistory_LiveEvent tests that a recorded swap is published as a live event, and
+// that failing to publish it does not fail the swap.
+func TestCreateSwapHistory_LiveEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo, service.WithLiveEvents())
+	ctx := context.Background()
+
+	history := &model.SwapHistory{
+		Network:         "mainnet",
+		Token:           "0xpool",
+		Account:         "0xuser",
+		TransactionHash: "0xtx",
+		UsdValue:        model.NewDecimalFromFloat(1200),
+		LastUpdated:     time.Unix(1727740800, 0),
+	}
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil).Times(2)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo).Times(2)
+	mockRepo.EXPECT().CreateSwapHistory(ctx, history).Return(nil).Times(2)
+	mockRepo.EXPECT().IncrementDailySwapRollup(ctx, history).Return(nil).Times(2)
+	mockTx.EXPECT().Commit(ctx).Return(nil).Times(2)
+	mockRepo.EXPECT().NotifyLiveEvent(ctx, &model.LiveEvent{
+		Type:            model.LiveEventSwap,
+		Network:         "mainnet",
+		Pool:            "0xpool",
+		Account:         "0xuser",
+		TransactionHash: "0xtx",
+		UsdValue:        &history.UsdValue,
+		Time:            history.LastUpdated,
+	}).Return(nil)
+	assert.NoError(t, svc.CreateSwapHistory(ctx, history))
+
+	mockRepo.EXPECT().NotifyLiveEvent(ctx, gomock.Any()).Return(errors.New("connection lost"))
+	assert.NoError(t, svc.CreateSwapHistory(ctx, history))
+}
+
+// TestListenLiveEvents tests that the live events received are passed to the subscribers whose
+// filter they match, until the context is done.
+func TestListenLiveEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	all, unsubscribeAll := svc.SubscribeLiveEvents(model.LiveEventFilter{})
+	defer unsubscribeAll()
+	user, unsubscribeUser := svc.SubscribeLiveEvents(model.LiveEventFilter{Account: "0xuser"})
+	defer unsubscribeUser()
+	unsubscribed, unsubscribe := svc.SubscribeLiveEvents(model.LiveEventFilter{})
+	unsubscribe()
+
+	swap := model.LiveEvent{Type: model.LiveEventSwap, Pool: "0xpool", Account: "0xother"}
+	points := model.LiveEvent{Type: model.LiveEventPoints, Pool: "0xpool", Account: "0xuser"}
+	mockRepo.EXPECT().ListenLiveEvents(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, handle func(model.LiveEvent)) error {
+		handle(swap)
+		handle(points)
+		cancel()
+		return ctx.Err()
+	})
+
+	assert.NoError(t, svc.ListenLiveEvents(ctx))
+	assert.Equal(t, []model.LiveEvent{swap, points}, drain(all))
+	assert.Equal(t, []model.LiveEvent{points}, drain(user))
+	assert.Empty(t, drain(unsubscribed))
+}
+
+// TestListenLiveEvents_Project tests that the subscribers of a project receive the swaps and the
+// points awards of their project only.
+func TestListenLiveEvents_Project(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	projectRepo := repositoryMock.NewMockRepository(ctrl)
+	mockRepo.EXPECT().ForProject(3).Return(projectRepo)
+	svc := service.NewService(mockRepo)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defaults, unsubscribeDefaults := svc.SubscribeLiveEvents(model.LiveEventFilter{})
+	defer unsubscribeDefaults()
+	project, unsubscribeProject := svc.ForProject(3).SubscribeLiveEvents(model.LiveEventFilter{})
+	defer unsubscribeProject()
+
+	swap := model.LiveEvent{Type: model.LiveEventSwap, Pool: "0xpool", Account: "0xuser"}
+	defaultPoints := model.LiveEvent{Type: model.LiveEventPoints, Pool: "0xpool", Account: "0xuser"}
+	projectPoints := model.LiveEvent{Type: model.LiveEventPoints, Pool: "0xpool", Account: "0xuser", Project: 3}
+	mockRepo.EXPECT().ListenLiveEvents(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, handle func(model.LiveEvent)) error {
+		handle(swap)
+		handle(defaultPoints)
+		handle(projectPoints)
+		cancel()
+		return ctx.Err()
+	})
+
+	assert.NoError(t, svc.ListenLiveEvents(ctx))
+	assert.Equal(t, []model.LiveEvent{swap, defaultPoints}, drain(defaults))
+	assert.Equal(t, []model.LiveEvent{swap, projectPoints}, drain(project))
+}
+
+// drain returns the events queued in events.
+func drain(events <-chan model.LiveEvent) []model.LiveEvent {
+	var drained []model.LiveEvent
+	for {
+		select {
+		case event := <-events:
+			drained = append(drained, event)
+		default:
+			return drained
+		}
+	}
+}
@@ -12,7 +12,9 @@ package mocks
 import (
 	context "context"
 	model "hw/internal/model"
+	service "hw/internal/service"
 	reflect "reflect"
+	time "time"
 
 	ethclient "github.com/ethereum/go-ethereum/ethclient"
 	gomock "go.uber.org/mock/gomock"
@@ -41,18 +43,163 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// AbortIdempotentRequest mocks base method.
+func (m *MockService) AbortIdempotentRequest(ctx context.Context, scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortIdempotentRequest", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AbortIdempotentRequest indicates an expected call of AbortIdempotentRequest.
+func (mr *MockServiceMockRecorder) AbortIdempotentRequest(ctx, scope, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortIdempotentRequest", reflect.TypeOf((*MockService)(nil).AbortIdempotentRequest), ctx, scope, key)
+}
+
+// AccumulateSwapPoints mocks base method.
+func (m *MockService) AccumulateSwapPoints(ctx context.Context, history *model.SwapHistory, campaign, description string, point model.Decimal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccumulateSwapPoints", ctx, history, campaign, description, point)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccumulateSwapPoints indicates an expected call of AccumulateSwapPoints.
+func (mr *MockServiceMockRecorder) AccumulateSwapPoints(ctx, history, campaign, description, point any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccumulateSwapPoints", reflect.TypeOf((*MockService)(nil).AccumulateSwapPoints), ctx, history, campaign, description, point)
+}
+
 // AccumulateUserPoints mocks base method.
-func (m *MockService) AccumulateUserPoints(ctx context.Context, token, user, description string, point float64) error {
+func (m *MockService) AccumulateUserPoints(ctx context.Context, network, token, user, description string, point model.Decimal) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AccumulateUserPoints", ctx, token, user, description, point)
+	ret := m.ctrl.Call(m, "AccumulateUserPoints", ctx, network, token, user, description, point)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AccumulateUserPoints indicates an expected call of AccumulateUserPoints.
-func (mr *MockServiceMockRecorder) AccumulateUserPoints(ctx, token, user, description, point any) *gomock.Call {
+func (mr *MockServiceMockRecorder) AccumulateUserPoints(ctx, network, token, user, description, point any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccumulateUserPoints", reflect.TypeOf((*MockService)(nil).AccumulateUserPoints), ctx, network, token, user, description, point)
+}
+
+// AccumulateUserPointsWithMetadata mocks base method.
+func (m *MockService) AccumulateUserPointsWithMetadata(ctx context.Context, network, token, user, description string, point model.Decimal, metadata *model.PointsMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccumulateUserPointsWithMetadata", ctx, network, token, user, description, point, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccumulateUserPointsWithMetadata indicates an expected call of AccumulateUserPointsWithMetadata.
+func (mr *MockServiceMockRecorder) AccumulateUserPointsWithMetadata(ctx, network, token, user, description, point, metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccumulateUserPointsWithMetadata", reflect.TypeOf((*MockService)(nil).AccumulateUserPointsWithMetadata), ctx, network, token, user, description, point, metadata)
+}
+
+// ApplyRetention mocks base method.
+func (m *MockService) ApplyRetention(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyRetention", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyRetention indicates an expected call of ApplyRetention.
+func (mr *MockServiceMockRecorder) ApplyRetention(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyRetention", reflect.TypeOf((*MockService)(nil).ApplyRetention), ctx, now)
+}
+
+// Authenticate mocks base method.
+func (m *MockService) Authenticate(token string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockServiceMockRecorder) Authenticate(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockService)(nil).Authenticate), token)
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockService) AuthenticateAPIKey(ctx context.Context, key string) (*model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, key)
+	ret0, _ := ret[0].(*model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockServiceMockRecorder) AuthenticateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockService)(nil).AuthenticateAPIKey), ctx, key)
+}
+
+// BeginIdempotentRequest mocks base method.
+func (m *MockService) BeginIdempotentRequest(ctx context.Context, scope, key, requestHash string) (*model.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginIdempotentRequest", ctx, scope, key, requestHash)
+	ret0, _ := ret[0].(*model.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginIdempotentRequest indicates an expected call of BeginIdempotentRequest.
+func (mr *MockServiceMockRecorder) BeginIdempotentRequest(ctx, scope, key, requestHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginIdempotentRequest", reflect.TypeOf((*MockService)(nil).BeginIdempotentRequest), ctx, scope, key, requestHash)
+}
+
+// ClaimPoints mocks base method.
+func (m *MockService) ClaimPoints(ctx context.Context, address string, timestamp int64, signature string) (*model.PointClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPoints", ctx, address, timestamp, signature)
+	ret0, _ := ret[0].(*model.PointClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPoints indicates an expected call of ClaimPoints.
+func (mr *MockServiceMockRecorder) ClaimPoints(ctx, address, timestamp, signature any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccumulateUserPoints", reflect.TypeOf((*MockService)(nil).AccumulateUserPoints), ctx, token, user, description, point)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPoints", reflect.TypeOf((*MockService)(nil).ClaimPoints), ctx, address, timestamp, signature)
+}
+
+// CompleteIdempotentRequest mocks base method.
+func (m *MockService) CompleteIdempotentRequest(ctx context.Context, scope, key string, response *model.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotentRequest", ctx, scope, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotentRequest indicates an expected call of CompleteIdempotentRequest.
+func (mr *MockServiceMockRecorder) CompleteIdempotentRequest(ctx, scope, key, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotentRequest", reflect.TypeOf((*MockService)(nil).CompleteIdempotentRequest), ctx, scope, key, response)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockService) CreateAPIKey(ctx context.Context, projectSlug string) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, projectSlug)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockServiceMockRecorder) CreateAPIKey(ctx, projectSlug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockService)(nil).CreateAPIKey), ctx, projectSlug)
 }
 
 // CreateAccount mocks base method.
@@ -69,6 +216,36 @@ func (mr *MockServiceMockRecorder) CreateAccount(ctx, account any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockService)(nil).CreateAccount), ctx, account)
 }
 
+// CreateProject mocks base method.
+func (m *MockService) CreateProject(ctx context.Context, slug, name string) (*model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProject", ctx, slug, name)
+	ret0, _ := ret[0].(*model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockServiceMockRecorder) CreateProject(ctx, slug, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockService)(nil).CreateProject), ctx, slug, name)
+}
+
+// CreateSignInNonce mocks base method.
+func (m *MockService) CreateSignInNonce(ctx context.Context) (*model.SignInNonce, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSignInNonce", ctx)
+	ret0, _ := ret[0].(*model.SignInNonce)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSignInNonce indicates an expected call of CreateSignInNonce.
+func (mr *MockServiceMockRecorder) CreateSignInNonce(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSignInNonce", reflect.TypeOf((*MockService)(nil).CreateSignInNonce), ctx)
+}
+
 // CreateSwapHistory mocks base method.
 func (m *MockService) CreateSwapHistory(ctx context.Context, history *model.SwapHistory) error {
 	m.ctrl.T.Helper()
@@ -97,6 +274,214 @@ func (mr *MockServiceMockRecorder) CreateToken(ctx, token any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockService)(nil).CreateToken), ctx, token)
 }
 
+// DeleteAddressLabel mocks base method.
+func (m *MockService) DeleteAddressLabel(ctx context.Context, address, label, operator string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddressLabel", ctx, address, label, operator)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddressLabel indicates an expected call of DeleteAddressLabel.
+func (mr *MockServiceMockRecorder) DeleteAddressLabel(ctx, address, label, operator any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddressLabel", reflect.TypeOf((*MockService)(nil).DeleteAddressLabel), ctx, address, label, operator)
+}
+
+// DistributePositionRewards mocks base method.
+func (m *MockService) DistributePositionRewards(ctx context.Context, network, contract string, from, to time.Time, totalPoints model.Decimal) ([]model.PositionReward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DistributePositionRewards", ctx, network, contract, from, to, totalPoints)
+	ret0, _ := ret[0].([]model.PositionReward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DistributePositionRewards indicates an expected call of DistributePositionRewards.
+func (mr *MockServiceMockRecorder) DistributePositionRewards(ctx, network, contract, from, to, totalPoints any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributePositionRewards", reflect.TypeOf((*MockService)(nil).DistributePositionRewards), ctx, network, contract, from, to, totalPoints)
+}
+
+// DistributeTWAVRewards mocks base method.
+func (m *MockService) DistributeTWAVRewards(ctx context.Context, network, pool string, from, to time.Time, totalPoints model.Decimal) ([]model.TWAVReward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DistributeTWAVRewards", ctx, network, pool, from, to, totalPoints)
+	ret0, _ := ret[0].([]model.TWAVReward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DistributeTWAVRewards indicates an expected call of DistributeTWAVRewards.
+func (mr *MockServiceMockRecorder) DistributeTWAVRewards(ctx, network, pool, from, to, totalPoints any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTWAVRewards", reflect.TypeOf((*MockService)(nil).DistributeTWAVRewards), ctx, network, pool, from, to, totalPoints)
+}
+
+// DryRun mocks base method.
+func (m *MockService) DryRun(record func(string, any)) service.Service {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRun", record)
+	ret0, _ := ret[0].(service.Service)
+	return ret0
+}
+
+// DryRun indicates an expected call of DryRun.
+func (mr *MockServiceMockRecorder) DryRun(record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRun", reflect.TypeOf((*MockService)(nil).DryRun), record)
+}
+
+// ExcludesWashTrades mocks base method.
+func (m *MockService) ExcludesWashTrades(campaign string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExcludesWashTrades", campaign)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ExcludesWashTrades indicates an expected call of ExcludesWashTrades.
+func (mr *MockServiceMockRecorder) ExcludesWashTrades(campaign any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExcludesWashTrades", reflect.TypeOf((*MockService)(nil).ExcludesWashTrades), campaign)
+}
+
+// FlagRoundTripSwaps mocks base method.
+func (m *MockService) FlagRoundTripSwaps(ctx context.Context, history *model.SwapHistory) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlagRoundTripSwaps", ctx, history)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlagRoundTripSwaps indicates an expected call of FlagRoundTripSwaps.
+func (mr *MockServiceMockRecorder) FlagRoundTripSwaps(ctx, history any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagRoundTripSwaps", reflect.TypeOf((*MockService)(nil).FlagRoundTripSwaps), ctx, history)
+}
+
+// ForProject mocks base method.
+func (m *MockService) ForProject(projectID int) service.Service {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForProject", projectID)
+	ret0, _ := ret[0].(service.Service)
+	return ret0
+}
+
+// ForProject indicates an expected call of ForProject.
+func (mr *MockServiceMockRecorder) ForProject(projectID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForProject", reflect.TypeOf((*MockService)(nil).ForProject), projectID)
+}
+
+// FundDistribution mocks base method.
+func (m *MockService) FundDistribution(ctx context.Context, distributionID int) (*model.SettlementTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FundDistribution", ctx, distributionID)
+	ret0, _ := ret[0].(*model.SettlementTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FundDistribution indicates an expected call of FundDistribution.
+func (mr *MockServiceMockRecorder) FundDistribution(ctx, distributionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FundDistribution", reflect.TypeOf((*MockService)(nil).FundDistribution), ctx, distributionID)
+}
+
+// GenerateDistribution mocks base method.
+func (m *MockService) GenerateDistribution(ctx context.Context, cutoff time.Time) (*model.MerkleDistribution, []model.MerkleProof, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateDistribution", ctx, cutoff)
+	ret0, _ := ret[0].(*model.MerkleDistribution)
+	ret1, _ := ret[1].([]model.MerkleProof)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GenerateDistribution indicates an expected call of GenerateDistribution.
+func (mr *MockServiceMockRecorder) GenerateDistribution(ctx, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateDistribution", reflect.TypeOf((*MockService)(nil).GenerateDistribution), ctx, cutoff)
+}
+
+// GetAddressLabels mocks base method.
+func (m *MockService) GetAddressLabels(ctx context.Context, address string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddressLabels", ctx, address)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddressLabels indicates an expected call of GetAddressLabels.
+func (mr *MockServiceMockRecorder) GetAddressLabels(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressLabels", reflect.TypeOf((*MockService)(nil).GetAddressLabels), ctx, address)
+}
+
+// GetBalanceSnapshot mocks base method.
+func (m *MockService) GetBalanceSnapshot(ctx context.Context, network, token string, blockNumber int64) (*model.BalanceSnapshot, []model.HolderBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceSnapshot", ctx, network, token, blockNumber)
+	ret0, _ := ret[0].(*model.BalanceSnapshot)
+	ret1, _ := ret[1].([]model.HolderBalance)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBalanceSnapshot indicates an expected call of GetBalanceSnapshot.
+func (mr *MockServiceMockRecorder) GetBalanceSnapshot(ctx, network, token, blockNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceSnapshot", reflect.TypeOf((*MockService)(nil).GetBalanceSnapshot), ctx, network, token, blockNumber)
+}
+
+// GetClaimProof mocks base method.
+func (m *MockService) GetClaimProof(ctx context.Context, address string) (*model.MerkleProof, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaimProof", ctx, address)
+	ret0, _ := ret[0].(*model.MerkleProof)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaimProof indicates an expected call of GetClaimProof.
+func (mr *MockServiceMockRecorder) GetClaimProof(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaimProof", reflect.TypeOf((*MockService)(nil).GetClaimProof), ctx, address)
+}
+
+// GetGasLeaderboard mocks base method.
+func (m *MockService) GetGasLeaderboard(ctx context.Context, network string, limit int) ([]model.GasTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGasLeaderboard", ctx, network, limit)
+	ret0, _ := ret[0].([]model.GasTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGasLeaderboard indicates an expected call of GetGasLeaderboard.
+func (mr *MockServiceMockRecorder) GetGasLeaderboard(ctx, network, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGasLeaderboard", reflect.TypeOf((*MockService)(nil).GetGasLeaderboard), ctx, network, limit)
+}
+
+// GetLatestTokenPrice mocks base method.
+func (m *MockService) GetLatestTokenPrice(ctx context.Context, token, network string, at time.Time, maxAge time.Duration) (*model.TokenPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestTokenPrice", ctx, token, network, at, maxAge)
+	ret0, _ := ret[0].(*model.TokenPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestTokenPrice indicates an expected call of GetLatestTokenPrice.
+func (mr *MockServiceMockRecorder) GetLatestTokenPrice(ctx, token, network, at, maxAge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestTokenPrice", reflect.TypeOf((*MockService)(nil).GetLatestTokenPrice), ctx, token, network, at, maxAge)
+}
+
 // GetLeaderboard mocks base method.
 func (m *MockService) GetLeaderboard(ctx context.Context) ([]model.User, error) {
 	m.ctrl.T.Helper()
@@ -112,6 +497,22 @@ func (mr *MockServiceMockRecorder) GetLeaderboard(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboard", reflect.TypeOf((*MockService)(nil).GetLeaderboard), ctx)
 }
 
+// GetLeaderboardPage mocks base method.
+func (m *MockService) GetLeaderboardPage(ctx context.Context, cursor string, limit int) ([]model.User, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardPage", ctx, cursor, limit)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLeaderboardPage indicates an expected call of GetLeaderboardPage.
+func (mr *MockServiceMockRecorder) GetLeaderboardPage(ctx, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardPage", reflect.TypeOf((*MockService)(nil).GetLeaderboardPage), ctx, cursor, limit)
+}
+
 // GetOrCreateAccount mocks base method.
 func (m *MockService) GetOrCreateAccount(ctx context.Context, accountId string) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +528,21 @@ func (mr *MockServiceMockRecorder) GetOrCreateAccount(ctx, accountId any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateAccount", reflect.TypeOf((*MockService)(nil).GetOrCreateAccount), ctx, accountId)
 }
 
+// GetOrCreateAccounts mocks base method.
+func (m *MockService) GetOrCreateAccounts(ctx context.Context, accountIds []string) (map[string]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateAccounts", ctx, accountIds)
+	ret0, _ := ret[0].(map[string]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreateAccounts indicates an expected call of GetOrCreateAccounts.
+func (mr *MockServiceMockRecorder) GetOrCreateAccounts(ctx, accountIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateAccounts", reflect.TypeOf((*MockService)(nil).GetOrCreateAccounts), ctx, accountIds)
+}
+
 // GetOrCreateToken mocks base method.
 func (m *MockService) GetOrCreateToken(ctx context.Context, client *ethclient.Client, tokenId string, blockNumber int64) (*model.Token, error) {
 	m.ctrl.T.Helper()
@@ -142,6 +558,21 @@ func (mr *MockServiceMockRecorder) GetOrCreateToken(ctx, client, tokenId, blockN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateToken", reflect.TypeOf((*MockService)(nil).GetOrCreateToken), ctx, client, tokenId, blockNumber)
 }
 
+// GetOrCreateTokens mocks base method.
+func (m *MockService) GetOrCreateTokens(ctx context.Context, client *ethclient.Client, tokenIds []string, blockNumber int64) (map[string]*model.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateTokens", ctx, client, tokenIds, blockNumber)
+	ret0, _ := ret[0].(map[string]*model.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreateTokens indicates an expected call of GetOrCreateTokens.
+func (mr *MockServiceMockRecorder) GetOrCreateTokens(ctx, client, tokenIds, blockNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateTokens", reflect.TypeOf((*MockService)(nil).GetOrCreateTokens), ctx, client, tokenIds, blockNumber)
+}
+
 // GetPointsHistory mocks base method.
 func (m *MockService) GetPointsHistory(ctx context.Context, account, token string) ([]model.PointsHistory, error) {
 	m.ctrl.T.Helper()
@@ -157,77 +588,685 @@ func (mr *MockServiceMockRecorder) GetPointsHistory(ctx, account, token any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistory", reflect.TypeOf((*MockService)(nil).GetPointsHistory), ctx, account, token)
 }
 
-// GetSwapTotalUsd mocks base method.
-func (m *MockService) GetSwapTotalUsd(ctx context.Context, account, token string) (float64, error) {
+// GetPointsHistoryByNetwork mocks base method.
+func (m *MockService) GetPointsHistoryByNetwork(ctx context.Context, account, token, network string) ([]model.PointsHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSwapTotalUsd", ctx, account, token)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "GetPointsHistoryByNetwork", ctx, account, token, network)
+	ret0, _ := ret[0].([]model.PointsHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSwapTotalUsd indicates an expected call of GetSwapTotalUsd.
-func (mr *MockServiceMockRecorder) GetSwapTotalUsd(ctx, account, token any) *gomock.Call {
+// GetPointsHistoryByNetwork indicates an expected call of GetPointsHistoryByNetwork.
+func (mr *MockServiceMockRecorder) GetPointsHistoryByNetwork(ctx, account, token, network any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwapTotalUsd", reflect.TypeOf((*MockService)(nil).GetSwapTotalUsd), ctx, account, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistoryByNetwork", reflect.TypeOf((*MockService)(nil).GetPointsHistoryByNetwork), ctx, account, token, network)
 }
 
-// GetTokenByAddress mocks base method.
-func (m *MockService) GetTokenByAddress(ctx context.Context, token string) (*model.Token, error) {
+// GetPointsHistoryByTokens mocks base method.
+func (m *MockService) GetPointsHistoryByTokens(ctx context.Context, account string, tokens []string, network string) (map[string][]model.PointsHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTokenByAddress", ctx, token)
-	ret0, _ := ret[0].(*model.Token)
+	ret := m.ctrl.Call(m, "GetPointsHistoryByTokens", ctx, account, tokens, network)
+	ret0, _ := ret[0].(map[string][]model.PointsHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTokenByAddress indicates an expected call of GetTokenByAddress.
-func (mr *MockServiceMockRecorder) GetTokenByAddress(ctx, token any) *gomock.Call {
+// GetPointsHistoryByTokens indicates an expected call of GetPointsHistoryByTokens.
+func (mr *MockServiceMockRecorder) GetPointsHistoryByTokens(ctx, account, tokens, network any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenByAddress", reflect.TypeOf((*MockService)(nil).GetTokenByAddress), ctx, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistoryByTokens", reflect.TypeOf((*MockService)(nil).GetPointsHistoryByTokens), ctx, account, tokens, network)
 }
 
-// GetUserSwapSummary mocks base method.
-func (m *MockService) GetUserSwapSummary(ctx context.Context, account string) (map[string]float64, error) {
+// GetPointsHistoryPage mocks base method.
+func (m *MockService) GetPointsHistoryPage(ctx context.Context, account, token, cursor string, limit int) ([]model.PointsHistory, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserSwapSummary", ctx, account)
-	ret0, _ := ret[0].(map[string]float64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "GetPointsHistoryPage", ctx, account, token, cursor, limit)
+	ret0, _ := ret[0].([]model.PointsHistory)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// GetUserSwapSummary indicates an expected call of GetUserSwapSummary.
-func (mr *MockServiceMockRecorder) GetUserSwapSummary(ctx, account any) *gomock.Call {
+// GetPointsHistoryPage indicates an expected call of GetPointsHistoryPage.
+func (mr *MockServiceMockRecorder) GetPointsHistoryPage(ctx, account, token, cursor, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummary", reflect.TypeOf((*MockService)(nil).GetUserSwapSummary), ctx, account)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsHistoryPage", reflect.TypeOf((*MockService)(nil).GetPointsHistoryPage), ctx, account, token, cursor, limit)
 }
 
-// GetUserSwapSummaryLast7Days mocks base method.
-func (m *MockService) GetUserSwapSummaryLast7Days(ctx context.Context, account string) ([]model.UserSwapPercentage, error) {
+// GetPoolStats mocks base method.
+func (m *MockService) GetPoolStats(ctx context.Context, pool string, topTradersLimit int) (*model.PoolStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserSwapSummaryLast7Days", ctx, account)
-	ret0, _ := ret[0].([]model.UserSwapPercentage)
+	ret := m.ctrl.Call(m, "GetPoolStats", ctx, pool, topTradersLimit)
+	ret0, _ := ret[0].(*model.PoolStats)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserSwapSummaryLast7Days indicates an expected call of GetUserSwapSummaryLast7Days.
-func (mr *MockServiceMockRecorder) GetUserSwapSummaryLast7Days(ctx, account any) *gomock.Call {
+// GetPoolStats indicates an expected call of GetPoolStats.
+func (mr *MockServiceMockRecorder) GetPoolStats(ctx, pool, topTradersLimit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummaryLast7Days", reflect.TypeOf((*MockService)(nil).GetUserSwapSummaryLast7Days), ctx, account)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoolStats", reflect.TypeOf((*MockService)(nil).GetPoolStats), ctx, pool, topTradersLimit)
 }
 
-// IsOnboardingTaskCompleted mocks base method.
-func (m *MockService) IsOnboardingTaskCompleted(ctx context.Context, account string) (bool, error) {
+// GetPoolTVL mocks base method.
+func (m *MockService) GetPoolTVL(ctx context.Context, pool, network string) (*model.PoolReserves, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsOnboardingTaskCompleted", ctx, account)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetPoolTVL", ctx, pool, network)
+	ret0, _ := ret[0].(*model.PoolReserves)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsOnboardingTaskCompleted indicates an expected call of IsOnboardingTaskCompleted.
-func (mr *MockServiceMockRecorder) IsOnboardingTaskCompleted(ctx, account any) *gomock.Call {
+// GetPoolTVL indicates an expected call of GetPoolTVL.
+func (mr *MockServiceMockRecorder) GetPoolTVL(ctx, pool, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoolTVL", reflect.TypeOf((*MockService)(nil).GetPoolTVL), ctx, pool, network)
+}
+
+// GetProject mocks base method.
+func (m *MockService) GetProject(ctx context.Context, slug string) (*model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject", ctx, slug)
+	ret0, _ := ret[0].(*model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockServiceMockRecorder) GetProject(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockService)(nil).GetProject), ctx, slug)
+}
+
+// GetSwapHistoryPage mocks base method.
+func (m *MockService) GetSwapHistoryPage(ctx context.Context, account, cursor string, limit int) ([]model.SwapHistory, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSwapHistoryPage", ctx, account, cursor, limit)
+	ret0, _ := ret[0].([]model.SwapHistory)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSwapHistoryPage indicates an expected call of GetSwapHistoryPage.
+func (mr *MockServiceMockRecorder) GetSwapHistoryPage(ctx, account, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwapHistoryPage", reflect.TypeOf((*MockService)(nil).GetSwapHistoryPage), ctx, account, cursor, limit)
+}
+
+// GetSwapTotalUsd mocks base method.
+func (m *MockService) GetSwapTotalUsd(ctx context.Context, account, token string) (model.SwapTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSwapTotalUsd", ctx, account, token)
+	ret0, _ := ret[0].(model.SwapTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSwapTotalUsd indicates an expected call of GetSwapTotalUsd.
+func (mr *MockServiceMockRecorder) GetSwapTotalUsd(ctx, account, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwapTotalUsd", reflect.TypeOf((*MockService)(nil).GetSwapTotalUsd), ctx, account, token)
+}
+
+// GetSwapVolume mocks base method.
+func (m *MockService) GetSwapVolume(ctx context.Context, network, pool string, from, to time.Time, interval time.Duration) ([]model.VolumeBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSwapVolume", ctx, network, pool, from, to, interval)
+	ret0, _ := ret[0].([]model.VolumeBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSwapVolume indicates an expected call of GetSwapVolume.
+func (mr *MockServiceMockRecorder) GetSwapVolume(ctx, network, pool, from, to, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwapVolume", reflect.TypeOf((*MockService)(nil).GetSwapVolume), ctx, network, pool, from, to, interval)
+}
+
+// GetTokenByAddress mocks base method.
+func (m *MockService) GetTokenByAddress(ctx context.Context, token string) (*model.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenByAddress", ctx, token)
+	ret0, _ := ret[0].(*model.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenByAddress indicates an expected call of GetTokenByAddress.
+func (mr *MockServiceMockRecorder) GetTokenByAddress(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenByAddress", reflect.TypeOf((*MockService)(nil).GetTokenByAddress), ctx, token)
+}
+
+// GetTokenHolderStats mocks base method.
+func (m *MockService) GetTokenHolderStats(ctx context.Context, network, token string) (*model.HolderStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenHolderStats", ctx, network, token)
+	ret0, _ := ret[0].(*model.HolderStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenHolderStats indicates an expected call of GetTokenHolderStats.
+func (mr *MockServiceMockRecorder) GetTokenHolderStats(ctx, network, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenHolderStats", reflect.TypeOf((*MockService)(nil).GetTokenHolderStats), ctx, network, token)
+}
+
+// GetTokenPrices mocks base method.
+func (m *MockService) GetTokenPrices(ctx context.Context, token, network string, from, to time.Time, interval time.Duration) ([]model.PriceCandle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenPrices", ctx, token, network, from, to, interval)
+	ret0, _ := ret[0].([]model.PriceCandle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenPrices indicates an expected call of GetTokenPrices.
+func (mr *MockServiceMockRecorder) GetTokenPrices(ctx, token, network, from, to, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenPrices", reflect.TypeOf((*MockService)(nil).GetTokenPrices), ctx, token, network, from, to, interval)
+}
+
+// GetTokenVolume mocks base method.
+func (m *MockService) GetTokenVolume(ctx context.Context, address string) (*model.TokenVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenVolume", ctx, address)
+	ret0, _ := ret[0].(*model.TokenVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenVolume indicates an expected call of GetTokenVolume.
+func (mr *MockServiceMockRecorder) GetTokenVolume(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenVolume", reflect.TypeOf((*MockService)(nil).GetTokenVolume), ctx, address)
+}
+
+// GetUserApprovals mocks base method.
+func (m *MockService) GetUserApprovals(ctx context.Context, owner, network string) ([]model.Allowance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserApprovals", ctx, owner, network)
+	ret0, _ := ret[0].([]model.Allowance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserApprovals indicates an expected call of GetUserApprovals.
+func (mr *MockServiceMockRecorder) GetUserApprovals(ctx, owner, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserApprovals", reflect.TypeOf((*MockService)(nil).GetUserApprovals), ctx, owner, network)
+}
+
+// GetUserGasStats mocks base method.
+func (m *MockService) GetUserGasStats(ctx context.Context, account string) ([]model.GasTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGasStats", ctx, account)
+	ret0, _ := ret[0].([]model.GasTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGasStats indicates an expected call of GetUserGasStats.
+func (mr *MockServiceMockRecorder) GetUserGasStats(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGasStats", reflect.TypeOf((*MockService)(nil).GetUserGasStats), ctx, account)
+}
+
+// GetUserNetworkSummary mocks base method.
+func (m *MockService) GetUserNetworkSummary(ctx context.Context, account string) (map[string]model.NetworkSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNetworkSummary", ctx, account)
+	ret0, _ := ret[0].(map[string]model.NetworkSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNetworkSummary indicates an expected call of GetUserNetworkSummary.
+func (mr *MockServiceMockRecorder) GetUserNetworkSummary(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNetworkSummary", reflect.TypeOf((*MockService)(nil).GetUserNetworkSummary), ctx, account)
+}
+
+// GetUserOverview mocks base method.
+func (m *MockService) GetUserOverview(ctx context.Context, account, network string) (*model.UserOverview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOverview", ctx, account, network)
+	ret0, _ := ret[0].(*model.UserOverview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserOverview indicates an expected call of GetUserOverview.
+func (mr *MockServiceMockRecorder) GetUserOverview(ctx, account, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOverview", reflect.TypeOf((*MockService)(nil).GetUserOverview), ctx, account, network)
+}
+
+// GetUserPositions mocks base method.
+func (m *MockService) GetUserPositions(ctx context.Context, account, network string) ([]model.Position, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPositions", ctx, account, network)
+	ret0, _ := ret[0].([]model.Position)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPositions indicates an expected call of GetUserPositions.
+func (mr *MockServiceMockRecorder) GetUserPositions(ctx, account, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPositions", reflect.TypeOf((*MockService)(nil).GetUserPositions), ctx, account, network)
+}
+
+// GetUserProfile mocks base method.
+func (m *MockService) GetUserProfile(ctx context.Context, address string) (*model.UserProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserProfile", ctx, address)
+	ret0, _ := ret[0].(*model.UserProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserProfile indicates an expected call of GetUserProfile.
+func (mr *MockServiceMockRecorder) GetUserProfile(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserProfile", reflect.TypeOf((*MockService)(nil).GetUserProfile), ctx, address)
+}
+
+// GetUserQuests mocks base method.
+func (m *MockService) GetUserQuests(ctx context.Context, account string) ([]model.UserQuest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserQuests", ctx, account)
+	ret0, _ := ret[0].([]model.UserQuest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserQuests indicates an expected call of GetUserQuests.
+func (mr *MockServiceMockRecorder) GetUserQuests(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserQuests", reflect.TypeOf((*MockService)(nil).GetUserQuests), ctx, account)
+}
+
+// GetUserRank mocks base method.
+func (m *MockService) GetUserRank(ctx context.Context, address string) (*model.LeaderboardRank, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRank", ctx, address)
+	ret0, _ := ret[0].(*model.LeaderboardRank)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRank indicates an expected call of GetUserRank.
+func (mr *MockServiceMockRecorder) GetUserRank(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRank", reflect.TypeOf((*MockService)(nil).GetUserRank), ctx, address)
+}
+
+// GetUserSwapSummary mocks base method.
+func (m *MockService) GetUserSwapSummary(ctx context.Context, account string) (map[string]model.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSwapSummary", ctx, account)
+	ret0, _ := ret[0].(map[string]model.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSwapSummary indicates an expected call of GetUserSwapSummary.
+func (mr *MockServiceMockRecorder) GetUserSwapSummary(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummary", reflect.TypeOf((*MockService)(nil).GetUserSwapSummary), ctx, account)
+}
+
+// GetUserSwapSummaryByNetwork mocks base method.
+func (m *MockService) GetUserSwapSummaryByNetwork(ctx context.Context, account, network string) (map[string]model.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSwapSummaryByNetwork", ctx, account, network)
+	ret0, _ := ret[0].(map[string]model.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSwapSummaryByNetwork indicates an expected call of GetUserSwapSummaryByNetwork.
+func (mr *MockServiceMockRecorder) GetUserSwapSummaryByNetwork(ctx, account, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummaryByNetwork", reflect.TypeOf((*MockService)(nil).GetUserSwapSummaryByNetwork), ctx, account, network)
+}
+
+// GetUserSwapSummaryLast7Days mocks base method.
+func (m *MockService) GetUserSwapSummaryLast7Days(ctx context.Context, token string, excludeWashTrades bool) ([]model.UserSwapPercentage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSwapSummaryLast7Days", ctx, token, excludeWashTrades)
+	ret0, _ := ret[0].([]model.UserSwapPercentage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSwapSummaryLast7Days indicates an expected call of GetUserSwapSummaryLast7Days.
+func (mr *MockServiceMockRecorder) GetUserSwapSummaryLast7Days(ctx, token, excludeWashTrades any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSwapSummaryLast7Days", reflect.TypeOf((*MockService)(nil).GetUserSwapSummaryLast7Days), ctx, token, excludeWashTrades)
+}
+
+// GetVolumeLeaderboard mocks base method.
+func (m *MockService) GetVolumeLeaderboard(ctx context.Context, network string, from, to time.Time, limit int) ([]model.TraderVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeLeaderboard", ctx, network, from, to, limit)
+	ret0, _ := ret[0].([]model.TraderVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeLeaderboard indicates an expected call of GetVolumeLeaderboard.
+func (mr *MockServiceMockRecorder) GetVolumeLeaderboard(ctx, network, from, to, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeLeaderboard", reflect.TypeOf((*MockService)(nil).GetVolumeLeaderboard), ctx, network, from, to, limit)
+}
+
+// IsOnboardingTaskCompleted mocks base method.
+func (m *MockService) IsOnboardingTaskCompleted(ctx context.Context, account string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOnboardingTaskCompleted", ctx, account)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsOnboardingTaskCompleted indicates an expected call of IsOnboardingTaskCompleted.
+func (mr *MockServiceMockRecorder) IsOnboardingTaskCompleted(ctx, account any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOnboardingTaskCompleted", reflect.TypeOf((*MockService)(nil).IsOnboardingTaskCompleted), ctx, account)
 }
+
+// ListAddressLabels mocks base method.
+func (m *MockService) ListAddressLabels(ctx context.Context, label string) ([]model.AddressLabel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAddressLabels", ctx, label)
+	ret0, _ := ret[0].([]model.AddressLabel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAddressLabels indicates an expected call of ListAddressLabels.
+func (mr *MockServiceMockRecorder) ListAddressLabels(ctx, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAddressLabels", reflect.TypeOf((*MockService)(nil).ListAddressLabels), ctx, label)
+}
+
+// ListTokens mocks base method.
+func (m *MockService) ListTokens(ctx context.Context, search, cursor string, limit int) ([]model.TokenVolume, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTokens", ctx, search, cursor, limit)
+	ret0, _ := ret[0].([]model.TokenVolume)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTokens indicates an expected call of ListTokens.
+func (mr *MockServiceMockRecorder) ListTokens(ctx, search, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTokens", reflect.TypeOf((*MockService)(nil).ListTokens), ctx, search, cursor, limit)
+}
+
+// ListenLiveEvents mocks base method.
+func (m *MockService) ListenLiveEvents(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenLiveEvents", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenLiveEvents indicates an expected call of ListenLiveEvents.
+func (mr *MockServiceMockRecorder) ListenLiveEvents(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenLiveEvents", reflect.TypeOf((*MockService)(nil).ListenLiveEvents), ctx)
+}
+
+// RecordApproval mocks base method.
+func (m *MockService) RecordApproval(ctx context.Context, allowance *model.Allowance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordApproval", ctx, allowance)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordApproval indicates an expected call of RecordApproval.
+func (mr *MockServiceMockRecorder) RecordApproval(ctx, allowance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordApproval", reflect.TypeOf((*MockService)(nil).RecordApproval), ctx, allowance)
+}
+
+// RecordGasSpend mocks base method.
+func (m *MockService) RecordGasSpend(ctx context.Context, spend *model.GasSpend) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordGasSpend", ctx, spend)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordGasSpend indicates an expected call of RecordGasSpend.
+func (mr *MockServiceMockRecorder) RecordGasSpend(ctx, spend any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordGasSpend", reflect.TypeOf((*MockService)(nil).RecordGasSpend), ctx, spend)
+}
+
+// RecordPoolReserves mocks base method.
+func (m *MockService) RecordPoolReserves(ctx context.Context, reserves *model.PoolReserves) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPoolReserves", ctx, reserves)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPoolReserves indicates an expected call of RecordPoolReserves.
+func (mr *MockServiceMockRecorder) RecordPoolReserves(ctx, reserves any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPoolReserves", reflect.TypeOf((*MockService)(nil).RecordPoolReserves), ctx, reserves)
+}
+
+// RecordPositionChange mocks base method.
+func (m *MockService) RecordPositionChange(ctx context.Context, change *model.PositionChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPositionChange", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPositionChange indicates an expected call of RecordPositionChange.
+func (mr *MockServiceMockRecorder) RecordPositionChange(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPositionChange", reflect.TypeOf((*MockService)(nil).RecordPositionChange), ctx, change)
+}
+
+// RecordTokenPrice mocks base method.
+func (m *MockService) RecordTokenPrice(ctx context.Context, price *model.TokenPrice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTokenPrice", ctx, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordTokenPrice indicates an expected call of RecordTokenPrice.
+func (mr *MockServiceMockRecorder) RecordTokenPrice(ctx, price any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTokenPrice", reflect.TypeOf((*MockService)(nil).RecordTokenPrice), ctx, price)
+}
+
+// ReplaceStuckSettlements mocks base method.
+func (m *MockService) ReplaceStuckSettlements(ctx context.Context, now time.Time) ([]model.SettlementTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceStuckSettlements", ctx, now)
+	ret0, _ := ret[0].([]model.SettlementTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceStuckSettlements indicates an expected call of ReplaceStuckSettlements.
+func (mr *MockServiceMockRecorder) ReplaceStuckSettlements(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceStuckSettlements", reflect.TypeOf((*MockService)(nil).ReplaceStuckSettlements), ctx, now)
+}
+
+// ResolvePointsBoost mocks base method.
+func (m *MockService) ResolvePointsBoost(ctx context.Context, network, account string, balanceOf service.BalanceReader) (*model.PointsBoost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolvePointsBoost", ctx, network, account, balanceOf)
+	ret0, _ := ret[0].(*model.PointsBoost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolvePointsBoost indicates an expected call of ResolvePointsBoost.
+func (mr *MockServiceMockRecorder) ResolvePointsBoost(ctx, network, account, balanceOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolvePointsBoost", reflect.TypeOf((*MockService)(nil).ResolvePointsBoost), ctx, network, account, balanceOf)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockService) RevokeAPIKey(ctx context.Context, prefix string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, prefix)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockServiceMockRecorder) RevokeAPIKey(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockService)(nil).RevokeAPIKey), ctx, prefix)
+}
+
+// SendNotifications mocks base method.
+func (m *MockService) SendNotifications(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendNotifications", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendNotifications indicates an expected call of SendNotifications.
+func (mr *MockServiceMockRecorder) SendNotifications(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendNotifications", reflect.TypeOf((*MockService)(nil).SendNotifications), ctx, now)
+}
+
+// SetAddressLabel mocks base method.
+func (m *MockService) SetAddressLabel(ctx context.Context, label *model.AddressLabel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAddressLabel", ctx, label)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAddressLabel indicates an expected call of SetAddressLabel.
+func (mr *MockServiceMockRecorder) SetAddressLabel(ctx, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAddressLabel", reflect.TypeOf((*MockService)(nil).SetAddressLabel), ctx, label)
+}
+
+// SignIn mocks base method.
+func (m *MockService) SignIn(ctx context.Context, message, signature string) (*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIn", ctx, message, signature)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIn indicates an expected call of SignIn.
+func (mr *MockServiceMockRecorder) SignIn(ctx, message, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockService)(nil).SignIn), ctx, message, signature)
+}
+
+// SubscribeLiveEvents mocks base method.
+func (m *MockService) SubscribeLiveEvents(filter model.LiveEventFilter) (<-chan model.LiveEvent, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeLiveEvents", filter)
+	ret0, _ := ret[0].(<-chan model.LiveEvent)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// SubscribeLiveEvents indicates an expected call of SubscribeLiveEvents.
+func (mr *MockServiceMockRecorder) SubscribeLiveEvents(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeLiveEvents", reflect.TypeOf((*MockService)(nil).SubscribeLiveEvents), filter)
+}
+
+// SyncLeaderboard mocks base method.
+func (m *MockService) SyncLeaderboard(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncLeaderboard", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncLeaderboard indicates an expected call of SyncLeaderboard.
+func (mr *MockServiceMockRecorder) SyncLeaderboard(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncLeaderboard", reflect.TypeOf((*MockService)(nil).SyncLeaderboard), ctx)
+}
+
+// TakeBalanceSnapshot mocks base method.
+func (m *MockService) TakeBalanceSnapshot(ctx context.Context, client *ethclient.Client, network, token string, blockNumber int64, holders []string) (*model.BalanceSnapshot, []model.HolderBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeBalanceSnapshot", ctx, client, network, token, blockNumber, holders)
+	ret0, _ := ret[0].(*model.BalanceSnapshot)
+	ret1, _ := ret[1].([]model.HolderBalance)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TakeBalanceSnapshot indicates an expected call of TakeBalanceSnapshot.
+func (mr *MockServiceMockRecorder) TakeBalanceSnapshot(ctx, client, network, token, blockNumber, holders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeBalanceSnapshot", reflect.TypeOf((*MockService)(nil).TakeBalanceSnapshot), ctx, client, network, token, blockNumber, holders)
+}
+
+// TrackSettlements mocks base method.
+func (m *MockService) TrackSettlements(ctx context.Context) ([]model.SettlementTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrackSettlements", ctx)
+	ret0, _ := ret[0].([]model.SettlementTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrackSettlements indicates an expected call of TrackSettlements.
+func (mr *MockServiceMockRecorder) TrackSettlements(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackSettlements", reflect.TypeOf((*MockService)(nil).TrackSettlements), ctx)
+}
+
+// UpdateDistributionRoot mocks base method.
+func (m *MockService) UpdateDistributionRoot(ctx context.Context, distributionID int) (*model.SettlementTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDistributionRoot", ctx, distributionID)
+	ret0, _ := ret[0].(*model.SettlementTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDistributionRoot indicates an expected call of UpdateDistributionRoot.
+func (mr *MockServiceMockRecorder) UpdateDistributionRoot(ctx, distributionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDistributionRoot", reflect.TypeOf((*MockService)(nil).UpdateDistributionRoot), ctx, distributionID)
+}
+
+// UpdateUserProfile mocks base method.
+func (m *MockService) UpdateUserProfile(ctx context.Context, profile *model.UserProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserProfile", ctx, profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserProfile indicates an expected call of UpdateUserProfile.
+func (mr *MockServiceMockRecorder) UpdateUserProfile(ctx, profile any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProfile", reflect.TypeOf((*MockService)(nil).UpdateUserProfile), ctx, profile)
+}
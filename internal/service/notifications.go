@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/notify"
+
+	"github.com/shopspring/decimal"
+)
+
+// WithNotifications configures the digests sent by SendNotifications and the sender delivering them.
+func WithNotifications(cfg config.Notifications, sender notify.Sender) Option {
+	return func(s *service) {
+		s.notifications = cfg
+		s.sender = sender
+	}
+}
+
+// weekStart returns the start of the week of t, Monday 00:00 UTC.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// SendNotifications sends the weekly summaries of the last complete week before now and the
+// big-swap alerts that are due. A message that fails to send is logged and retried on the next call.
+func (s *service) SendNotifications(ctx context.Context, now time.Time) error {
+	if s.sender == nil {
+		return errors.New("no notification sender configured")
+	}
+	return errors.Join(s.sendWeeklySummaries(ctx, now), s.sendBigSwapAlerts(ctx))
+}
+
+func (s *service) sendWeeklySummaries(ctx context.Context, now time.Time) error {
+	end := weekStart(now)
+	start := end.AddDate(0, 0, -7)
+	summaries, err := s.repo.GetWeeklySummaries(ctx, start, end)
+	if err != nil {
+		return err
+	}
+
+	for _, summary := range summaries {
+		s.deliver(ctx, summary.Address, model.NotificationWeeklySummary, start, weeklySummaryMessage(summary, end))
+	}
+	return nil
+}
+
+func (s *service) sendBigSwapAlerts(ctx context.Context) error {
+	minUSD := model.NewDecimal(decimal.NewFromInt(int64(s.notifications.BigSwapUSD)))
+	alerts, err := s.repo.GetBigSwapAlerts(ctx, minUSD)
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		// The alert covers swaps up to the last one, so the next alert starts after it
+		period := alert.Swaps[len(alert.Swaps)-1].CreatedAt
+		s.deliver(ctx, alert.Address, model.NotificationBigSwaps, period, bigSwapAlertMessage(alert, minUSD))
+	}
+	return nil
+}
+
+// deliver sends a message and records it, so it is not sent again for the same period.
+func (s *service) deliver(ctx context.Context, address, kind string, period time.Time, msg notify.Message) {
+	if err := s.sender.Send(ctx, msg); err != nil {
+		logger.Errorf("Failed to send %s notification to %s: %v", kind, address, err)
+		return
+	}
+	if err := s.repo.RecordNotification(ctx, address, kind, period); err != nil {
+		logger.Errorf("Failed to record %s notification to %s: %v", kind, address, err)
+	}
+}
+
+// weeklySummaryMessage composes the weekly summary of the week ending at end.
+func weeklySummaryMessage(summary model.WeeklySummary, end time.Time) notify.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Your activity from %s to %s for %s:\n\n", summary.WeekStart.Format(time.DateOnly), end.AddDate(0, 0, -1).Format(time.DateOnly), summary.Address)
+	fmt.Fprintf(&body, "Points earned: %s\n", summary.PointsEarned)
+	fmt.Fprintf(&body, "Total points: %s\n", summary.TotalPoints)
+	fmt.Fprintf(&body, "Swaps: %d, %s USD\n", summary.SwapCount, summary.SwapVolume.StringFixed(2))
+	return notify.Message{
+		To:      summary.Email,
+		Subject: fmt.Sprintf("Your weekly points summary: +%s points", summary.PointsEarned),
+		Body:    body.String(),
+	}
+}
+
+// bigSwapAlertMessage composes the alert of a user's swaps of at least minUSD.
+func bigSwapAlertMessage(alert model.BigSwapAlert, minUSD model.Decimal) notify.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Swaps of at least %s USD by %s:\n\n", minUSD, alert.Address)
+	for _, swap := range alert.Swaps {
+		fmt.Fprintf(&body, "- %s on %s: %s USD", swap.CreatedAt.UTC().Format(time.DateTime), swap.Network, swap.UsdValue.StringFixed(2))
+		if url := ExplorerTxURL(swap.Network, swap.TransactionHash); url != "" {
+			fmt.Fprintf(&body, " %s", url)
+		}
+		body.WriteString("\n")
+	}
+	return notify.Message{
+		To:      alert.Email,
+		Subject: fmt.Sprintf("Big swap alert: %d swaps of at least %s USD", len(alert.Swaps), minUSD),
+		Body:    body.String(),
+	}
+}
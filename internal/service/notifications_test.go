@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	"hw/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeSender records the messages it sends and fails those to failTo.
+type fakeSender struct {
+	sent   []notify.Message
+	failTo string
+}
+
+func (f *fakeSender) Send(_ context.Context, msg notify.Message) error {
+	if msg.To == f.failTo {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+// TestSendNotifications tests that the last complete week is summarized, alerts cover their swaps,
+// and only sent messages are recorded.
+func TestSendNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	sender := &fakeSender{failTo: "failing@example.com"}
+	svc := service.NewService(mockRepo, service.WithNotifications(config.Default().Notifications, sender))
+	ctx := context.Background()
+
+	// Wednesday, so the last complete week is Monday 7 to Sunday 13 October
+	now := time.Date(2024, 10, 16, 10, 0, 0, 0, time.UTC)
+	weekStart := time.Date(2024, 10, 7, 0, 0, 0, 0, time.UTC)
+	weekEnd := time.Date(2024, 10, 14, 0, 0, 0, 0, time.UTC)
+	swapTime := time.Date(2024, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().GetWeeklySummaries(ctx, weekStart, weekEnd).Return([]model.WeeklySummary{
+		{Address: "0xa", Email: "a@example.com", WeekStart: weekStart, PointsEarned: model.NewDecimalFromFloat(12.5), TotalPoints: model.NewDecimalFromFloat(100), SwapCount: 3, SwapVolume: model.NewDecimalFromFloat(1234.5)},
+		{Address: "0xb", Email: "failing@example.com", WeekStart: weekStart},
+	}, nil)
+	mockRepo.EXPECT().RecordNotification(ctx, "0xa", model.NotificationWeeklySummary, weekStart).Return(nil)
+	mockRepo.EXPECT().GetBigSwapAlerts(ctx, model.NewDecimalFromFloat(10000)).Return([]model.BigSwapAlert{
+		{Address: "0xa", Email: "a@example.com", Swaps: []model.SwapHistory{
+			{Network: "mainnet", TransactionHash: "0xhash1", UsdValue: model.NewDecimalFromFloat(15000), CreatedAt: swapTime.Add(-time.Hour)},
+			{Network: "base", TransactionHash: "0xhash2", UsdValue: model.NewDecimalFromFloat(20000), CreatedAt: swapTime},
+		}},
+	}, nil)
+	mockRepo.EXPECT().RecordNotification(ctx, "0xa", model.NotificationBigSwaps, swapTime).Return(nil)
+
+	err := svc.SendNotifications(ctx, now)
+
+	assert.NoError(t, err)
+	if assert.Len(t, sender.sent, 2) {
+		assert.Equal(t, "Your weekly points summary: +12.5 points", sender.sent[0].Subject)
+		assert.Contains(t, sender.sent[0].Body, "from 2024-10-07 to 2024-10-13")
+		assert.Contains(t, sender.sent[0].Body, "Swaps: 3, 1234.50 USD")
+		assert.Equal(t, "Big swap alert: 2 swaps of at least 10000 USD", sender.sent[1].Subject)
+		assert.Contains(t, sender.sent[1].Body, "- 2024-10-16 09:00:00 on base: 20000.00 USD https://basescan.org/tx/0xhash2\n")
+	}
+}
+
+// TestSendNotifications_NoSender tests that notifications need a sender.
+func TestSendNotifications_NoSender(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := service.NewService(repositoryMock.NewMockRepository(ctrl))
+
+	assert.Error(t, svc.SendNotifications(context.Background(), time.Now()))
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"hw/internal/model"
+)
+
+// GetUserOverview retrieves a user's swap volume and points by token and by network, creating the
+// user if not found. The pools with their tasks come from one query and the network breakdown from
+// another. With a network, the pools, the total USD value and the total points only cover that
+// network, while the network breakdown and the claims always cover every network.
+func (s *service) GetUserOverview(ctx context.Context, account, network string) (*model.UserOverview, error) {
+	user, err := s.GetOrCreateAccount(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	pools, err := s.repo.GetUserPools(ctx, account, network)
+	if err != nil {
+		return nil, err
+	}
+
+	networks, err := s.repo.GetUserNetworkSummary(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := s.GetAddressLabels(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &model.UserOverview{
+		Network:         network,
+		TotalPoints:     user.TotalPoints,
+		ClaimablePoints: user.ClaimablePoints(),
+		ClaimedPoints:   user.ClaimedPoints,
+		Pool:            pools,
+		Networks:        networks,
+		Labels:          labels,
+	}
+	if network != "" {
+		overview.TotalPoints = networks[network].Points
+	}
+
+	for _, pool := range pools {
+		overview.TotalUsdValue = overview.TotalUsdValue.Add(pool.TotalUsdValue)
+	}
+
+	return overview, nil
+}
@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetUserOverview tests that the overview of a user adds up the swap volume of its pools, read
+// with their points in one query.
+func TestGetUserOverview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	account := "0x00000000000000000000000000000000000000a1"
+	user := &model.User{Address: account, TotalPoints: model.NewDecimalFromFloat(150), ClaimedPoints: model.NewDecimalFromFloat(50)}
+	networks := map[string]model.NetworkSummary{"mainnet": {UsdValue: model.NewDecimalFromFloat(1500.75), Points: model.NewDecimalFromFloat(150)}}
+
+	pools := map[string]*model.UserPool{
+		"tokenABC": {
+			TotalUsdValue: model.NewDecimalFromFloat(1000.50),
+			Points:        model.NewDecimalFromFloat(110.5),
+			Tasks: []model.UserTask{
+				{Description: "swap_task", Points: model.NewDecimalFromFloat(10.5)},
+				{Description: "onboarding_task", Points: model.NewDecimalFromFloat(100)},
+			},
+		},
+		"tokenXYZ": {
+			TotalUsdValue: model.NewDecimalFromFloat(500.25),
+			Points:        model.NewDecimalFromFloat(5.25),
+			Tasks:         []model.UserTask{{Description: "swap_task", Points: model.NewDecimalFromFloat(5.25)}},
+		},
+	}
+
+	mockRepo.EXPECT().GetUserByAddress(ctx, account).Return(user, nil)
+	mockRepo.EXPECT().GetUserPools(ctx, account, "").Return(pools, nil)
+	mockRepo.EXPECT().GetUserNetworkSummary(ctx, account).Return(networks, nil)
+	mockRepo.EXPECT().GetAddressLabels(ctx, account, "").Return([]model.AddressLabel{{Address: account, Label: model.LabelTeamWallet}}, nil)
+
+	overview, err := svc.GetUserOverview(ctx, account, "")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.UserOverview{
+		TotalUsdValue:   model.NewDecimalFromFloat(1500.75),
+		TotalPoints:     model.NewDecimalFromFloat(150),
+		ClaimablePoints: model.NewDecimalFromFloat(100),
+		ClaimedPoints:   model.NewDecimalFromFloat(50),
+		Pool:            pools,
+		Networks:        networks,
+		Labels:          []string{model.LabelTeamWallet},
+	}, overview)
+}
+
+// TestGetUserOverview_Network tests that a network scopes the pools and the total points, but not
+// the network breakdown and the claims.
+func TestGetUserOverview_Network(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	account := "0x00000000000000000000000000000000000000a1"
+	user := &model.User{Address: account, TotalPoints: model.NewDecimalFromFloat(150)}
+	networks := map[string]model.NetworkSummary{
+		"mainnet":  {UsdValue: model.NewDecimalFromFloat(2000), Points: model.NewDecimalFromFloat(140)},
+		"arbitrum": {UsdValue: model.NewDecimalFromFloat(300.5), Points: model.NewDecimalFromFloat(10)},
+	}
+
+	mockRepo.EXPECT().GetUserByAddress(ctx, account).Return(user, nil)
+	mockRepo.EXPECT().GetUserPools(ctx, account, "arbitrum").Return(map[string]*model.UserPool{
+		"tokenABC": {
+			TotalUsdValue: model.NewDecimalFromFloat(300.5),
+			Points:        model.NewDecimalFromFloat(10),
+			Tasks:         []model.UserTask{{Description: "swap_task", Points: model.NewDecimalFromFloat(10)}},
+		},
+	}, nil)
+	mockRepo.EXPECT().GetUserNetworkSummary(ctx, account).Return(networks, nil)
+	mockRepo.EXPECT().GetAddressLabels(ctx, account, "").Return(nil, nil)
+
+	overview, err := svc.GetUserOverview(ctx, account, "arbitrum")
+	assert.NoError(t, err)
+	assert.Equal(t, "arbitrum", overview.Network)
+	assert.Equal(t, model.NewDecimalFromFloat(10), overview.TotalPoints)
+	assert.Equal(t, model.NewDecimalFromFloat(150), overview.ClaimablePoints)
+	assert.Equal(t, model.NewDecimalFromFloat(300.5), overview.TotalUsdValue)
+	assert.Equal(t, networks, overview.Networks)
+	assert.Equal(t, model.NewDecimalFromFloat(10), overview.Pool["tokenABC"].Points)
+}
+
+// TestGetUserOverview_NoSwaps tests that a user without swaps has no pools and no swap volume.
+func TestGetUserOverview_NoSwaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	account := "0x00000000000000000000000000000000000000a1"
+	mockRepo.EXPECT().GetUserByAddress(ctx, account).Return(nil, model.ErrUserNotFound)
+	mockRepo.EXPECT().CreateUser(ctx, account).Return(&model.User{Address: account}, nil)
+	mockRepo.EXPECT().GetUserPools(ctx, account, "").Return(map[string]*model.UserPool{}, nil)
+	mockRepo.EXPECT().GetUserNetworkSummary(ctx, account).Return(map[string]model.NetworkSummary{}, nil)
+	mockRepo.EXPECT().GetAddressLabels(ctx, account, "").Return(nil, nil)
+
+	overview, err := svc.GetUserOverview(ctx, account, "")
+	assert.NoError(t, err)
+	assert.Empty(t, overview.Pool)
+	assert.True(t, overview.TotalUsdValue.IsZero())
+}
+
+// TestGetUserOverview_Error tests that failing to read the pools fails the overview.
+func TestGetUserOverview_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	account := "0x00000000000000000000000000000000000000a1"
+	expectedErr := errors.New("failed to retrieve user pools")
+	mockRepo.EXPECT().GetUserByAddress(ctx, account).Return(&model.User{Address: account}, nil)
+	mockRepo.EXPECT().GetUserPools(ctx, account, "").Return(nil, expectedErr)
+
+	overview, err := svc.GetUserOverview(ctx, account, "")
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Nil(t, overview)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"hw/internal/model"
+)
+
+// RewardCampaign returns the campaign id of a reward distribution over the holders or traders of a
+// contract in [from, to), so that the awards of each run can be told apart.
+func RewardCampaign(contract string, from, to time.Time) string {
+	return contract + ":" + from.UTC().Format(time.RFC3339) + "/" + to.UTC().Format(time.RFC3339)
+}
+
+// AccumulateUserPointsWithMetadata adds points earned on a network to a user's account with a
+// description, keeping their provenance in the metadata of the points history entry.
+func (s *service) AccumulateUserPointsWithMetadata(ctx context.Context, network, token, user, description string, point model.Decimal, metadata *model.PointsMetadata) error {
+	return s.accumulatePoints(ctx, &model.PointsHistory{
+		Network:     network,
+		Token:       token,
+		Account:     user,
+		Points:      point,
+		Description: description,
+		Metadata:    metadata,
+	})
+}
+
+// AccumulateSwapPoints adds points earned with a swap to the account of its trader, multiplied by
+// the boost of the swap, if any. The campaign, the swap and the boost are kept in the metadata of
+// the points history entry.
+func (s *service) AccumulateSwapPoints(ctx context.Context, history *model.SwapHistory, campaign, description string, point model.Decimal) error {
+	return s.accumulatePoints(ctx, swapPointsHistory(history, campaign, description, point))
+}
+
+// swapPointsHistory returns the points history entry of points earned with a swap.
+func swapPointsHistory(history *model.SwapHistory, campaign, description string, point model.Decimal) *model.PointsHistory {
+	pointsHistory := &model.PointsHistory{
+		Network:     history.Network,
+		Token:       history.Token,
+		Account:     history.Account,
+		Points:      point,
+		Description: description,
+		Metadata: &model.PointsMetadata{
+			Campaign:        campaign,
+			TransactionHash: history.TransactionHash,
+			BlockNumber:     history.BlockNumber,
+		},
+	}
+	if boost := history.Boost; boost != nil {
+		multiplier, base := boost.Multiplier, point
+		pointsHistory.Points = BoostedPoints(point, boost)
+		pointsHistory.Metadata.Boost = boost.ID
+		pointsHistory.Metadata.Multiplier = &multiplier
+		pointsHistory.Metadata.BasePoints = &base
+	}
+	return pointsHistory
+}
@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestAccumulateSwapPoints tests that points earned with a swap are credited multiplied by its
+// boost, with the campaign, the swap, the boost and the points before it in their metadata.
+func TestAccumulateSwapPoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	history := &model.SwapHistory{
+		Network:         "mainnet",
+		Token:           "0xpool",
+		Account:         "0xuser",
+		TransactionHash: "0xabc",
+		BlockNumber:     20933200,
+		Boost:           &model.PointsBoost{ID: "og_nft", Multiplier: model.NewDecimalFromFloat(1.5)},
+	}
+	boosted := model.NewDecimalFromFloat(150)
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().CreatePointsHistory(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, ph *model.PointsHistory) error {
+		assert.Equal(t, "0xpool", ph.Token)
+		assert.Equal(t, "onboarding_task", ph.Description)
+		assert.True(t, ph.Points.Equal(boosted.Decimal))
+		if assert.NotNil(t, ph.Metadata) {
+			assert.Equal(t, "onboarding", ph.Metadata.Campaign)
+			assert.Equal(t, "0xabc", ph.Metadata.TransactionHash)
+			assert.Equal(t, int64(20933200), ph.Metadata.BlockNumber)
+			assert.Equal(t, "og_nft", ph.Metadata.Boost)
+			assert.True(t, ph.Metadata.Multiplier.Equal(model.NewDecimalFromFloat(1.5).Decimal))
+			assert.True(t, ph.Metadata.BasePoints.Equal(model.NewDecimalFromFloat(100).Decimal))
+		}
+		ph.ID = 1
+		return nil
+	})
+	mockRepo.EXPECT().UpsertUserPoints(ctx, "0xuser", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, points model.Decimal) error {
+		assert.True(t, points.Equal(boosted.Decimal))
+		return nil
+	})
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+
+	assert.NoError(t, svc.AccumulateSwapPoints(ctx, history, "onboarding", "onboarding_task", model.NewDecimalFromFloat(100)))
+}
+
+// TestRewardCampaign tests that the campaign id of a distribution names its contract and period in UTC.
+func TestRewardCampaign(t *testing.T) {
+	from := time.Date(2024, 10, 14, 2, 0, 0, 0, time.FixedZone("UTC+2", 2*3600))
+	to := time.Date(2024, 10, 21, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "0xpool:2024-10-14T00:00:00Z/2024-10-21T00:00:00Z", service.RewardCampaign("0xpool", from, to))
+}
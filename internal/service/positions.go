@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// LPRewardTask is the points history description of LP rewards.
+const LPRewardTask = "lp_reward_task"
+
+// PositionRewardTask returns the points history description of the rewards of a kind of position,
+// LPRewardTask for LP positions.
+func PositionRewardTask(kind string) string {
+	return kind + "_reward_task"
+}
+
+// RecordPositionChange records a change of a position and applies it to the current balance.
+func (s *service) RecordPositionChange(ctx context.Context, change *model.PositionChange) error {
+	return s.repo.ApplyPositionChange(ctx, change)
+}
+
+// GetUserPositions retrieves the open positions of a user, on every network when network is empty.
+func (s *service) GetUserPositions(ctx context.Context, account, network string) ([]model.Position, error) {
+	return s.repo.GetAccountPositions(ctx, account, network)
+}
+
+// GetTokenHolderStats retrieves the number of holders of a token and their distribution by order of
+// magnitude of their balance, from its indexed transfers.
+func (s *service) GetTokenHolderStats(ctx context.Context, network, token string) (*model.HolderStats, error) {
+	buckets, err := s.repo.GetTokenHolderBuckets(ctx, token, network)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &model.HolderStats{Network: network, Token: token, Buckets: buckets}
+	for i := range buckets {
+		buckets[i].MinBalance = model.NewDecimal(decimal.New(1, int32(buckets[i].Magnitude)))
+		buckets[i].MaxBalance = model.NewDecimal(decimal.New(1, int32(buckets[i].Magnitude+1)))
+		stats.Holders += buckets[i].Holders
+	}
+	return stats, nil
+}
+
+// DistributePositionRewards awards totalPoints to the accounts holding a position in a contract in
+// proportion to their time-weighted balance within [from, to), and returns the rewards ordered by
+// liquidity. Accounts excluded from points distributions get no share. The points are recorded
+// under the reward task of the kind of position, with the contract and period as campaign in their
+// metadata. Points are truncated to 3 decimals, so the awarded
+// total may be slightly below totalPoints. Running it twice for the same period awards the points
+// twice.
+func (s *service) DistributePositionRewards(ctx context.Context, network, contract string, from, to time.Time, totalPoints model.Decimal) ([]model.PositionReward, error) {
+	if !from.Before(to) {
+		return nil, model.NewError(model.ErrInvalid, "reward period must end after it starts")
+	}
+
+	changes, err := s.repo.GetPositionChanges(ctx, contract, network, to)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded, err := s.excludedAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	liquidity := timeWeightedLiquidity(changes, from, to)
+	for account := range liquidity {
+		if excluded[account] {
+			delete(liquidity, account)
+		}
+	}
+	total := decimal.Zero
+	for _, weight := range liquidity {
+		total = total.Add(weight)
+	}
+	if !total.IsPositive() {
+		return nil, model.ErrNothingToDistribute
+	}
+
+	rewards := make([]model.PositionReward, 0, len(liquidity))
+	for account, weight := range liquidity {
+		if !weight.IsPositive() {
+			continue
+		}
+		rewards = append(rewards, model.PositionReward{
+			Account:   account,
+			Liquidity: model.NewDecimal(weight),
+			Share:     model.NewDecimal(weight.DivRound(total, 18)),
+			Points:    model.NewDecimal(totalPoints.Mul(weight).Div(total).Truncate(3)),
+		})
+	}
+	sort.Slice(rewards, func(i, j int) bool {
+		if cmp := rewards[i].Liquidity.Cmp(rewards[j].Liquidity.Decimal); cmp != 0 {
+			return cmp > 0
+		}
+		return rewards[i].Account < rewards[j].Account
+	})
+
+	// A contract holds positions of a single kind, so any change tells it
+	task := PositionRewardTask(changes[0].Kind)
+	metadata := &model.PointsMetadata{Campaign: RewardCampaign(contract, from, to)}
+	for _, reward := range rewards {
+		if !reward.Points.IsPositive() {
+			continue
+		}
+		if err := s.AccumulateUserPointsWithMetadata(ctx, network, contract, reward.Account, task, reward.Points, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return rewards, nil
+}
+
+// timeWeightedLiquidity integrates the position balance of every account over [from, to), given
+// every position change before to in chain order.
+func timeWeightedLiquidity(changes []model.PositionChange, from, to time.Time) map[string]decimal.Decimal {
+	type position struct {
+		balance decimal.Decimal
+		since   time.Time
+	}
+	clamp := func(t time.Time) time.Time {
+		switch {
+		case t.Before(from):
+			return from
+		case t.After(to):
+			return to
+		default:
+			return t
+		}
+	}
+
+	positions := make(map[string]*position)
+	liquidity := make(map[string]decimal.Decimal)
+	accrue := func(account string, p *position, until time.Time) {
+		seconds := clamp(until).Sub(clamp(p.since)).Seconds()
+		if seconds > 0 && p.balance.IsPositive() {
+			liquidity[account] = liquidity[account].Add(p.balance.Mul(decimal.NewFromFloat(seconds)))
+		}
+	}
+
+	for _, change := range changes {
+		p, exists := positions[change.Account]
+		if !exists {
+			p = &position{since: change.BlockTime}
+			positions[change.Account] = p
+		}
+		accrue(change.Account, p, change.BlockTime)
+		p.balance = p.balance.Add(change.Delta.Decimal)
+		p.since = change.BlockTime
+	}
+	for account, p := range positions {
+		accrue(account, p, to)
+	}
+
+	return liquidity
+}
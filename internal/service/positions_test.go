@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestDistributePositionRewards tests that points are shared by the balances held within the period, weighted by time.
+func TestDistributePositionRewards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	from := time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC)
+	to := from.Add(100 * time.Second)
+
+	// 0xa provides 100 before the period and withdraws after 75s, 0xb provides 100 after 50s
+	mockRepo.EXPECT().GetPositionChanges(ctx, "0xpool", "mainnet", to).Return([]model.PositionChange{
+		{Kind: model.PositionLP, Account: "0xa", Delta: model.NewDecimalFromFloat(100), BlockTime: from.Add(-50 * time.Second)},
+		{Kind: model.PositionLP, Account: "0xb", Delta: model.NewDecimalFromFloat(100), BlockTime: from.Add(50 * time.Second)},
+		{Kind: model.PositionLP, Account: "0xa", Delta: model.NewDecimalFromFloat(-100), BlockTime: from.Add(75 * time.Second)},
+	}, nil)
+
+	awarded := make(map[string]model.Decimal)
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil).Times(2)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo).Times(2)
+	mockRepo.EXPECT().CreatePointsHistory(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, ph *model.PointsHistory) error {
+		assert.Equal(t, service.LPRewardTask, ph.Description)
+		assert.Equal(t, "0xpool", ph.Token)
+		awarded[ph.Account] = ph.Points
+		ph.ID = len(awarded)
+		return nil
+	}).Times(2)
+	mockRepo.EXPECT().UpsertUserPoints(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.Any()).Return(nil).Times(2)
+	mockTx.EXPECT().Commit(ctx).Return(nil).Times(2)
+
+	rewards, err := svc.DistributePositionRewards(ctx, "mainnet", "0xpool", from, to, model.NewDecimalFromFloat(1000))
+
+	assert.NoError(t, err)
+	if assert.Len(t, rewards, 2) {
+		assert.Equal(t, "0xa", rewards[0].Account)
+		assert.True(t, rewards[0].Liquidity.Equal(model.NewDecimalFromFloat(7500).Decimal))
+		assert.True(t, rewards[0].Share.Equal(model.NewDecimalFromFloat(0.6).Decimal))
+		assert.True(t, rewards[0].Points.Equal(model.NewDecimalFromFloat(600).Decimal))
+		assert.Equal(t, "0xb", rewards[1].Account)
+		assert.True(t, rewards[1].Points.Equal(model.NewDecimalFromFloat(400).Decimal))
+	}
+	assert.True(t, awarded["0xa"].Equal(model.NewDecimalFromFloat(600).Decimal))
+	assert.True(t, awarded["0xb"].Equal(model.NewDecimalFromFloat(400).Decimal))
+}
+
+// TestDistributePositionRewards_Exclusion tests that excluded accounts get no share of the points.
+func TestDistributePositionRewards_Exclusion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo, service.WithPoints(config.Points{ExcludeLabels: []string{model.LabelContract}}))
+	ctx := context.Background()
+
+	from := time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC)
+	to := from.Add(100 * time.Second)
+
+	mockRepo.EXPECT().GetPositionChanges(ctx, "0xpool", "mainnet", to).Return([]model.PositionChange{
+		{Kind: model.PositionLP, Account: "0xa", Delta: model.NewDecimalFromFloat(100), BlockTime: from.Add(-50 * time.Second)},
+		{Kind: model.PositionLP, Account: "0xvault", Delta: model.NewDecimalFromFloat(300), BlockTime: from.Add(-50 * time.Second)},
+	}, nil)
+	mockRepo.EXPECT().GetAddressLabels(ctx, "", model.LabelContract).Return([]model.AddressLabel{{Address: "0xvault", Label: model.LabelContract}}, nil)
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().CreatePointsHistory(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, ph *model.PointsHistory) error {
+		ph.ID = 1
+		return nil
+	})
+	mockRepo.EXPECT().UpsertUserPoints(ctx, "0xa", gomock.Any()).Return(nil)
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+
+	rewards, err := svc.DistributePositionRewards(ctx, "mainnet", "0xpool", from, to, model.NewDecimalFromFloat(1000))
+
+	assert.NoError(t, err)
+	if assert.Len(t, rewards, 1) {
+		assert.Equal(t, "0xa", rewards[0].Account)
+		assert.True(t, rewards[0].Share.Equal(model.NewDecimalFromFloat(1).Decimal))
+		assert.True(t, rewards[0].Points.Equal(model.NewDecimalFromFloat(1000).Decimal))
+	}
+}
+
+// TestDistributePositionRewards_NothingToDistribute tests that a period without liquidity awards nothing.
+func TestDistributePositionRewards_NothingToDistribute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	from := time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	_, err := svc.DistributePositionRewards(ctx, "mainnet", "0xpool", to, from, model.NewDecimalFromFloat(1000))
+	assert.ErrorIs(t, err, model.ErrInvalid)
+
+	mockRepo.EXPECT().GetPositionChanges(ctx, "0xpool", "mainnet", to).Return([]model.PositionChange{
+		{Kind: model.PositionLP, Account: "0xa", Delta: model.NewDecimalFromFloat(100), BlockTime: from.Add(-time.Hour)},
+		{Kind: model.PositionLP, Account: "0xa", Delta: model.NewDecimalFromFloat(-100), BlockTime: from.Add(-time.Minute)},
+	}, nil)
+
+	_, err = svc.DistributePositionRewards(ctx, "mainnet", "0xpool", from, to, model.NewDecimalFromFloat(1000))
+	assert.ErrorIs(t, err, model.ErrNothingToDistribute)
+}
+
+// TestPositionRewardTask tests that LP rewards keep their points history description.
+func TestPositionRewardTask(t *testing.T) {
+	assert.Equal(t, service.LPRewardTask, service.PositionRewardTask(model.PositionLP))
+	assert.Equal(t, "stake_reward_task", service.PositionRewardTask(model.PositionStake))
+}
+
+// TestGetTokenHolderStats tests that the buckets get their balance bounds and the holders are totalled.
+func TestGetTokenHolderStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetTokenHolderBuckets(ctx, "0xtoken", "mainnet").Return([]model.HolderBucket{
+		{Magnitude: 0, Holders: 3},
+		{Magnitude: 18, Holders: 7},
+	}, nil)
+
+	stats, err := svc.GetTokenHolderStats(ctx, "mainnet", "0xtoken")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), stats.Holders)
+	if assert.Len(t, stats.Buckets, 2) {
+		assert.True(t, stats.Buckets[0].MinBalance.Equal(model.NewDecimalFromFloat(1).Decimal))
+		assert.True(t, stats.Buckets[0].MaxBalance.Equal(model.NewDecimalFromFloat(10).Decimal))
+		assert.Equal(t, "1000000000000000000", stats.Buckets[1].MinBalance.String())
+		assert.Equal(t, "10000000000000000000", stats.Buckets[1].MaxBalance.String())
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+)
+
+// maxPriceCandles bounds the number of candles a single price query may return.
+const maxPriceCandles = 1000
+
+// RecordTokenPrice records a USD price of a token in its minute bucket.
+func (s *service) RecordTokenPrice(ctx context.Context, price *model.TokenPrice) error {
+	return s.handlerStore.UpsertTokenPrice(ctx, price)
+}
+
+// GetTokenPrices retrieves the OHLC candles of a token's USD price within [from, to).
+// Intervals finer than a minute, empty ranges and ranges of more than maxPriceCandles candles are invalid.
+func (s *service) GetTokenPrices(ctx context.Context, token, network string, from, to time.Time, interval time.Duration) ([]model.PriceCandle, error) {
+	if interval < time.Minute {
+		return nil, model.NewError(model.ErrInvalid, "price interval must be at least one minute")
+	}
+	if !from.Before(to) {
+		return nil, model.NewError(model.ErrInvalid, "price range must end after it starts")
+	}
+	if to.Sub(from)/interval > maxPriceCandles {
+		return nil, model.NewError(model.ErrInvalid, fmt.Sprintf("price range spans more than %d intervals", maxPriceCandles))
+	}
+	return s.handlerStore.GetTokenPriceCandles(ctx, token, network, from, to, interval)
+}
+
+// GetLatestTokenPrice retrieves the latest recorded USD price of a token at or before at, no older than maxAge.
+// It returns model.ErrPriceNotFound when no price was recorded in that period.
+func (s *service) GetLatestTokenPrice(ctx context.Context, token, network string, at time.Time, maxAge time.Duration) (*model.TokenPrice, error) {
+	return s.handlerStore.GetLatestTokenPrice(ctx, token, network, at.Add(-maxAge), at)
+}
@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetTokenPrices tests that price ranges are bounded before reaching the repository.
+func TestGetTokenPrices(t *testing.T) {
+	to := time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		interval time.Duration
+		queried  bool
+		err      error
+	}{
+		{name: "day of hours", from: to.Add(-24 * time.Hour), interval: time.Hour, queried: true},
+		{name: "sub-minute interval", from: to.Add(-time.Hour), interval: time.Second, err: model.ErrInvalid},
+		{name: "empty range", from: to, interval: time.Minute, err: model.ErrInvalid},
+		{name: "too many candles", from: to.Add(-30 * 24 * time.Hour), interval: time.Minute, err: model.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := repositoryMock.NewMockRepository(ctrl)
+			svc := service.NewService(mockRepo)
+			ctx := context.Background()
+
+			candles := []model.PriceCandle{{Time: tt.from, Open: model.NewDecimalFromFloat(2500)}}
+			if tt.queried {
+				mockRepo.EXPECT().GetTokenPriceCandles(ctx, "0xweth", "mainnet", tt.from, to, tt.interval).Return(candles, nil)
+			}
+
+			result, err := svc.GetTokenPrices(ctx, "0xweth", "mainnet", tt.from, to, tt.interval)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, candles, result)
+		})
+	}
+}
+
+// TestRecordTokenPrice tests that prices are recorded through the repository.
+func TestRecordTokenPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	price := &model.TokenPrice{Network: "mainnet", Token: "0xweth", Price: model.NewDecimalFromFloat(2500), Time: time.Now()}
+	mockRepo.EXPECT().UpsertTokenPrice(ctx, price).Return(errors.New("db error"))
+
+	assert.Error(t, svc.RecordTokenPrice(ctx, price))
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"hw/internal/model"
+	"hw/internal/repository"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// apiKeyScheme starts every API key, so a leaked key is recognizable, e.g. by secret scanners.
+const apiKeyScheme = "hwk_"
+
+// apiKeyPrefixLength is the number of characters after apiKeyScheme identifying a key.
+const apiKeyPrefixLength = 8
+
+// projectSlugPattern matches the slugs of projects: lowercase letters, digits and inner hyphens.
+var projectSlugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// WithProject keeps the points, claims, distributions, quests and labels of a project, the default
+// project unless set. The leaderboard mirror holds the points of this project.
+func WithProject(projectID int) Option {
+	return func(s *service) {
+		s.project = projectID
+		s.repo = s.repo.ForProject(projectID)
+	}
+}
+
+// ResolveProject returns the ID of the project of a slug, or model.DefaultProjectID for an empty
+// slug. It returns model.ErrProjectNotFound for an unknown slug.
+func ResolveProject(ctx context.Context, repo repository.Repository, slug string) (int, error) {
+	if slug == "" {
+		return model.DefaultProjectID, nil
+	}
+	project, err := repo.GetProjectBySlug(ctx, slug)
+	if err != nil {
+		return 0, err
+	}
+	return project.ID, nil
+}
+
+// ForProject returns a Service sharing the connections, caches and live events of s that reads and
+// writes the points, claims, distributions, quests and labels of a project. The leaderboard of
+// another project than the one of s is read from Postgres, as the mirror holds the points of s.
+func (s *service) ForProject(projectID int) Service {
+	if projectID == s.project {
+		return s
+	}
+	scoped := *s
+	scoped.group = &singleflight.Group{}
+	scoped.project = projectID
+	scoped.repo = s.repo.ForProject(projectID)
+	scoped.leaderboard = nil
+	return &scoped
+}
+
+// CreateProject creates a project with a unique slug.
+func (s *service) CreateProject(ctx context.Context, slug, name string) (*model.Project, error) {
+	if !projectSlugPattern.MatchString(slug) {
+		return nil, model.NewError(model.ErrInvalid, "project slug must be 1 to 64 lowercase letters, digits and inner hyphens")
+	}
+	if name == "" {
+		name = slug
+	}
+	project := &model.Project{Slug: slug, Name: name}
+	if err := s.repo.CreateProject(ctx, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// GetProject retrieves a project by its slug, or returns model.ErrProjectNotFound.
+func (s *service) GetProject(ctx context.Context, slug string) (*model.Project, error) {
+	return s.repo.GetProjectBySlug(ctx, slug)
+}
+
+// CreateAPIKey issues an API key for a project. The key is returned once, in the Key field: only
+// its hash is stored.
+func (s *service) CreateAPIKey(ctx context.Context, projectSlug string) (*model.APIKey, error) {
+	project, err := s.repo.GetProjectBySlug(ctx, projectSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	secret := hex.EncodeToString(buf)
+	key := &model.APIKey{
+		ProjectID: project.ID,
+		Prefix:    secret[:apiKeyPrefixLength],
+		Key:       apiKeyScheme + secret,
+	}
+	if err := s.repo.CreateAPIKey(ctx, key, hashAPIKey(key.Key)); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// AuthenticateAPIKey returns the project of an active API key, or an error of kind
+// model.ErrUnauthenticated for a malformed, unknown or revoked key.
+func (s *service) AuthenticateAPIKey(ctx context.Context, key string) (*model.Project, error) {
+	if !strings.HasPrefix(key, apiKeyScheme) || len(key) <= len(apiKeyScheme)+apiKeyPrefixLength {
+		return nil, model.ErrInvalidAPIKey
+	}
+	return s.repo.GetProjectByAPIKey(ctx, hashAPIKey(key))
+}
+
+// RevokeAPIKey revokes the active API key with a prefix, the 8 characters following "hwk_".
+func (s *service) RevokeAPIKey(ctx context.Context, prefix string) error {
+	return s.repo.RevokeAPIKey(ctx, strings.TrimPrefix(prefix, apiKeyScheme))
+}
+
+// hashAPIKey returns the hex SHA-256 hash of a key. The keys are random, so a fast unsalted hash
+// protects them as well as a password hash would, and lets a key be looked up by its hash.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestCreateAPIKey tests that an issued key authenticates its project while only its hash is stored.
+func TestCreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+	project := &model.Project{ID: 3, Slug: "acme", Name: "Acme rewards"}
+
+	var storedHash string
+	mockRepo.EXPECT().GetProjectBySlug(ctx, "acme").Return(project, nil)
+	mockRepo.EXPECT().CreateAPIKey(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key *model.APIKey, keyHash string) error {
+		assert.Equal(t, 3, key.ProjectID)
+		storedHash = keyHash
+		key.ID = 9
+		return nil
+	})
+
+	key, err := svc.CreateAPIKey(ctx, "acme")
+	assert.NoError(t, err)
+	assert.Equal(t, 9, key.ID)
+	assert.True(t, strings.HasPrefix(key.Key, "hwk_"+key.Prefix), "the prefix identifies the key")
+	assert.Len(t, key.Key, 52)
+	sum := sha256.Sum256([]byte(key.Key))
+	assert.Equal(t, hex.EncodeToString(sum[:]), storedHash)
+
+	mockRepo.EXPECT().GetProjectByAPIKey(ctx, storedHash).Return(project, nil)
+	authenticated, err := svc.AuthenticateAPIKey(ctx, key.Key)
+	assert.NoError(t, err)
+	assert.Equal(t, project, authenticated)
+
+	// Malformed keys are rejected without a query
+	for _, malformed := range []string{"", "hwk_", "hwk_1a2b", strings.TrimPrefix(key.Key, "hwk_")} {
+		_, err = svc.AuthenticateAPIKey(ctx, malformed)
+		assert.ErrorIs(t, err, model.ErrUnauthenticated, malformed)
+	}
+}
+
+// TestCreateProject tests that project slugs are validated.
+func TestCreateProject(t *testing.T) {
+	tests := []struct {
+		slug    string
+		wantErr error
+	}{
+		{slug: "acme"},
+		{slug: "acme-rewards-2"},
+		{slug: "Acme", wantErr: model.ErrInvalid},
+		{slug: "-acme", wantErr: model.ErrInvalid},
+		{slug: "acme_rewards", wantErr: model.ErrInvalid},
+		{slug: strings.Repeat("a", 65), wantErr: model.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := repositoryMock.NewMockRepository(ctrl)
+			svc := service.NewService(mockRepo)
+			ctx := context.Background()
+
+			if tt.wantErr == nil {
+				mockRepo.EXPECT().CreateProject(ctx, &model.Project{Slug: tt.slug, Name: tt.slug}).Return(nil)
+			}
+
+			_, err := svc.CreateProject(ctx, tt.slug, "")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestResolveProject tests that an empty slug is the default project and that an unknown slug fails.
+func TestResolveProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	id, err := service.ResolveProject(ctx, mockRepo, "")
+	assert.NoError(t, err)
+	assert.Equal(t, model.DefaultProjectID, id)
+
+	mockRepo.EXPECT().GetProjectBySlug(ctx, "acme").Return(&model.Project{ID: 3, Slug: "acme"}, nil)
+	id, err = service.ResolveProject(ctx, mockRepo, "acme")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+
+	mockRepo.EXPECT().GetProjectBySlug(ctx, "unknown").Return(nil, model.ErrProjectNotFound)
+	_, err = service.ResolveProject(ctx, mockRepo, "unknown")
+	assert.ErrorIs(t, err, model.ErrProjectNotFound)
+}
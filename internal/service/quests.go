@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/config"
+
+	"github.com/shopspring/decimal"
+)
+
+// QuestTask returns the points history description of the reward of a quest.
+func QuestTask(questID string) string {
+	return "quest_" + questID + "_task"
+}
+
+// WithQuests tracks the progress of every account on quests and awards their points on completion.
+func WithQuests(quests []config.Quest) Option {
+	return func(s *service) {
+		s.quests = quests
+	}
+}
+
+// advanceQuests counts a recorded swap towards the quests of its account and awards the points of
+// the quests it completes as swap points, with the quest id as campaign. A day counts towards days
+// and streak quests once the USD counted on it reaches their MinDailyUSD. Quests are marked
+// completed before their points are awarded, so a failed award is not retried and a quest never
+// pays twice.
+func (s *service) advanceQuests(ctx context.Context, history *model.SwapHistory) error {
+	if len(s.quests) == 0 || !history.CountedUsdValue.IsPositive() {
+		return nil
+	}
+
+	var daily *model.Decimal
+	for _, quest := range s.quests {
+		var progress *model.QuestProgress
+		if quest.Kind == config.QuestVolume {
+			var err error
+			if progress, err = s.repo.AddQuestVolume(ctx, quest.ID, history.Account, history.CountedUsdValue); err != nil {
+				return err
+			}
+		} else {
+			if daily == nil {
+				counted, err := s.repo.GetDailyCountedUsd(ctx, history.Account, history.LastUpdated)
+				if err != nil {
+					return err
+				}
+				daily = &counted
+			}
+			if daily.LessThan(decimal.NewFromInt(int64(quest.MinDailyUSD))) {
+				continue
+			}
+			var err error
+			if progress, err = s.repo.AdvanceQuestDays(ctx, quest.ID, history.Account, history.LastUpdated); err != nil {
+				return err
+			}
+		}
+		if progress == nil || questProgress(quest, progress).LessThan(decimal.NewFromInt(int64(quest.Target))) {
+			continue
+		}
+
+		completed, err := s.repo.CompleteQuest(ctx, quest.ID, history.Account, history.LastUpdated)
+		if err != nil {
+			return err
+		}
+		if !completed {
+			continue
+		}
+		points := model.NewDecimal(decimal.NewFromInt(int64(quest.Points)))
+		if err := s.AccumulateSwapPoints(ctx, history, quest.ID, QuestTask(quest.ID), points); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// questProgress returns the progress of an account towards the target of a quest: its distinct
+// days, its current streak or its USD counted.
+func questProgress(quest config.Quest, progress *model.QuestProgress) decimal.Decimal {
+	if quest.Kind == config.QuestStreak {
+		return decimal.NewFromInt(int64(progress.Streak))
+	}
+	return progress.Progress.Decimal
+}
+
+// GetUserQuests retrieves every quest with the progress of a user towards it. The streak of a
+// streak quest is broken, and shown as 0, once a whole UTC day passed without it growing.
+func (s *service) GetUserQuests(ctx context.Context, account string) ([]model.UserQuest, error) {
+	stored, err := s.repo.GetQuestProgress(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	byQuest := make(map[string]*model.QuestProgress, len(stored))
+	for i := range stored {
+		byQuest[stored[i].QuestID] = &stored[i]
+	}
+
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	quests := make([]model.UserQuest, 0, len(s.quests))
+	for _, quest := range s.quests {
+		userQuest := model.UserQuest{
+			ID:          quest.ID,
+			Description: quest.Description,
+			Kind:        quest.Kind,
+			Target:      quest.Target,
+			MinDailyUSD: quest.MinDailyUSD,
+			Points:      quest.Points,
+		}
+		if progress := byQuest[quest.ID]; progress != nil {
+			userQuest.Progress = model.NewDecimal(questProgress(quest, progress))
+			userQuest.Completed = progress.CompletedAt != nil
+			userQuest.CompletedAt = progress.CompletedAt
+			if quest.Kind == config.QuestStreak && !userQuest.Completed && progress.LastDay != nil && progress.LastDay.Before(yesterday) {
+				userQuest.Progress = model.Decimal{}
+			}
+		}
+		quests = append(quests, userQuest)
+	}
+	return quests, nil
+}
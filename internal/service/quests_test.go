@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var testQuests = []config.Quest{
+	{ID: "swap_2_days", Kind: config.QuestDays, Target: 2, MinDailyUSD: 100, Points: 500},
+	{ID: "streak_3", Kind: config.QuestStreak, Target: 3, MinDailyUSD: 100, Points: 700},
+	{ID: "volume_10k", Kind: config.QuestVolume, Target: 10000, Points: 1000},
+}
+
+// TestCreateSwapHistory_Quests tests that a swap counts its day towards the day quests once the USD
+// counted on it reaches their minimum, adds its USD to the volume quests, and that the quests it
+// completes award their points once.
+func TestCreateSwapHistory_Quests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo, service.WithQuests(testQuests))
+	ctx := context.Background()
+
+	day := time.Date(2024, 10, 21, 15, 0, 0, 0, time.UTC)
+	history := &model.SwapHistory{Network: "mainnet", Token: "0xpool", Account: "0xuser", UsdValue: model.NewDecimalFromFloat(150), CountedUsdValue: model.NewDecimalFromFloat(150), LastUpdated: day}
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil).Times(2)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo).Times(2)
+	mockRepo.EXPECT().CreateSwapHistory(ctx, history).Return(nil)
+	mockRepo.EXPECT().IncrementDailySwapRollup(ctx, history).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil).Times(2)
+	mockRepo.EXPECT().GetDailyCountedUsd(ctx, "0xuser", day).Return(model.NewDecimalFromFloat(150), nil)
+	mockRepo.EXPECT().AdvanceQuestDays(ctx, "swap_2_days", "0xuser", day).Return(&model.QuestProgress{Progress: model.NewDecimalFromFloat(2), Streak: 2}, nil)
+	mockRepo.EXPECT().CompleteQuest(ctx, "swap_2_days", "0xuser", day).Return(true, nil)
+	mockRepo.EXPECT().AdvanceQuestDays(ctx, "streak_3", "0xuser", day).Return(&model.QuestProgress{Progress: model.NewDecimalFromFloat(4), Streak: 2}, nil)
+	mockRepo.EXPECT().AddQuestVolume(ctx, "volume_10k", "0xuser", history.CountedUsdValue).Return(&model.QuestProgress{Progress: model.NewDecimalFromFloat(10050)}, nil)
+	mockRepo.EXPECT().CompleteQuest(ctx, "volume_10k", "0xuser", day).Return(false, nil)
+
+	mockRepo.EXPECT().CreatePointsHistory(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, ph *model.PointsHistory) error {
+		assert.Equal(t, service.QuestTask("swap_2_days"), ph.Description)
+		assert.True(t, ph.Points.Equal(model.NewDecimalFromFloat(500).Decimal))
+		ph.ID = 1
+		return nil
+	})
+	mockRepo.EXPECT().UpsertUserPoints(ctx, "0xuser", gomock.Any()).Return(nil)
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.Any()).Return(nil)
+
+	assert.NoError(t, svc.CreateSwapHistory(ctx, history))
+}
+
+// TestCreateSwapHistory_QuestsBelowMinimum tests that a day below the minimum of the day quests is
+// not counted, while the volume quests still are.
+func TestCreateSwapHistory_QuestsBelowMinimum(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo, service.WithQuests(testQuests))
+	ctx := context.Background()
+
+	day := time.Date(2024, 10, 21, 15, 0, 0, 0, time.UTC)
+	history := &model.SwapHistory{Account: "0xuser", UsdValue: model.NewDecimalFromFloat(40), CountedUsdValue: model.NewDecimalFromFloat(40), LastUpdated: day}
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().CreateSwapHistory(ctx, history).Return(nil)
+	mockRepo.EXPECT().IncrementDailySwapRollup(ctx, history).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+	mockRepo.EXPECT().GetDailyCountedUsd(ctx, "0xuser", day).Return(model.NewDecimalFromFloat(80), nil)
+	mockRepo.EXPECT().AddQuestVolume(ctx, "volume_10k", "0xuser", history.CountedUsdValue).Return(&model.QuestProgress{Progress: model.NewDecimalFromFloat(80)}, nil)
+
+	assert.NoError(t, svc.CreateSwapHistory(ctx, history))
+}
+
+// TestGetUserQuests tests that every quest is listed with the progress of the user, and that a
+// streak without a swap since before yesterday is shown broken.
+func TestGetUserQuests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo, service.WithQuests(testQuests))
+	ctx := context.Background()
+
+	completedAt := time.Date(2024, 10, 22, 9, 0, 0, 0, time.UTC)
+	lastWeek := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -7)
+	mockRepo.EXPECT().GetQuestProgress(ctx, "0xuser").Return([]model.QuestProgress{
+		{QuestID: "streak_3", Progress: model.NewDecimalFromFloat(2), Streak: 2, LastDay: &lastWeek},
+		{QuestID: "swap_2_days", Progress: model.NewDecimalFromFloat(2), Streak: 1, CompletedAt: &completedAt},
+	}, nil)
+
+	quests, err := svc.GetUserQuests(ctx, "0xuser")
+
+	assert.NoError(t, err)
+	if assert.Len(t, quests, 3) {
+		assert.Equal(t, "swap_2_days", quests[0].ID)
+		assert.True(t, quests[0].Completed)
+		assert.True(t, quests[0].Progress.Equal(model.NewDecimalFromFloat(2).Decimal))
+		assert.Equal(t, "streak_3", quests[1].ID)
+		assert.True(t, quests[1].Progress.IsZero(), "the streak is broken")
+		assert.Equal(t, "volume_10k", quests[2].ID)
+		assert.False(t, quests[2].Completed)
+		assert.True(t, quests[2].Progress.IsZero())
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/blobstore"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+)
+
+// HistoryPartitionsAhead is the number of months after the current one whose history partitions
+// are created in advance, so rows never wait for their partition.
+var HistoryPartitionsAhead = 1
+
+// RetentionArchiveBatch is the number of rows of a history table per archived object.
+var RetentionArchiveBatch = 10000
+
+// WithRetention configures how long the history tables keep their rows and the store expired
+// rows are archived to when the retention archives them.
+func WithRetention(cfg config.Retention, archive blobstore.Store) Option {
+	return func(s *service) {
+		s.retention = cfg
+		s.archive = archive
+	}
+}
+
+// retentionMonth truncates t to the UTC calendar month a history partition covers.
+func retentionMonth(t time.Time) time.Time {
+	year, month, _ := t.UTC().Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ApplyRetention creates the partitions of the history tables for the month of now and the
+// HistoryPartitionsAhead months after it, and for the months kept whose rows are in the default
+// partition, e.g. swaps of blocks indexed late. It then drops the months of each table past its
+// retention, oldest first: their rows are archived when configured, then added to the monthly
+// rollups and removed. A month failing to archive or drop stops its table until the next call.
+func (s *service) ApplyRetention(ctx context.Context, now time.Time) error {
+	if s.retention.Archive && s.archive == nil {
+		return errors.New("no retention archive configured")
+	}
+
+	current := retentionMonth(now)
+	tables := []struct {
+		name   string
+		months int
+	}{
+		{name: model.HistorySwaps, months: s.retention.SwapHistoryMonths},
+		{name: model.HistoryPoints, months: s.retention.PointsHistoryMonths},
+	}
+
+	var errs []error
+	for _, table := range tables {
+		var cutoff time.Time
+		if table.months > 0 {
+			cutoff = current.AddDate(0, -table.months, 0)
+		}
+		if err := s.partitionHistory(ctx, table.name, current, cutoff); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if table.months > 0 {
+			errs = append(errs, s.dropExpiredHistory(ctx, table.name, cutoff))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// partitionHistory creates the partitions of a history table for the current month and the
+// months ahead, and for the months from cutoff on with rows in the default partition. The rows of
+// the earlier months are dropped from the default partition by the retention instead.
+func (s *service) partitionHistory(ctx context.Context, table string, current, cutoff time.Time) error {
+	if err := s.repo.EnsureHistoryPartitions(ctx, table, current, current.AddDate(0, HistoryPartitionsAhead, 0)); err != nil {
+		return err
+	}
+
+	months, err := s.repo.GetDefaultPartitionMonths(ctx, table)
+	if err != nil {
+		return err
+	}
+	for _, month := range months {
+		if month.Before(cutoff) {
+			continue
+		}
+		if err := s.repo.EnsureHistoryPartitions(ctx, table, month, month); err != nil {
+			return err
+		}
+		logger.Infof("Created partition of %s for %s from its default partition", table, month.Format("2006-01"))
+	}
+	return nil
+}
+
+// dropExpiredHistory drops the months of a history table before cutoff.
+func (s *service) dropExpiredHistory(ctx context.Context, table string, cutoff time.Time) error {
+	oldest, err := s.repo.GetOldestHistoryTime(ctx, table)
+	if err != nil || oldest == nil {
+		return err
+	}
+
+	for month := retentionMonth(*oldest); month.Before(cutoff); month = month.AddDate(0, 1, 0) {
+		if s.retention.Archive {
+			if err := s.archiveHistoryMonth(ctx, table, month); err != nil {
+				return err
+			}
+		}
+		removed, err := s.repo.DropHistoryMonth(ctx, table, month)
+		if err != nil {
+			return err
+		}
+		if removed > 0 {
+			logger.Infof("Dropped %d rows of %s for %s", removed, table, month.Format("2006-01"))
+		}
+	}
+	return nil
+}
+
+// archiveHistoryMonth writes the rows of a month of a history table to the archive, one JSON row
+// per line in objects of up to RetentionArchiveBatch rows keyed
+// retention/<table>/<month>/<first ID>.jsonl with a zero-padded ID, so keys sort by ID.
+// Archiving a month again replaces its objects.
+func (s *service) archiveHistoryMonth(ctx context.Context, table string, month time.Time) error {
+	var afterID int64
+	for {
+		rows, err := s.repo.GetHistoryMonthRows(ctx, table, month, afterID, RetentionArchiveBatch)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		var data bytes.Buffer
+		for _, row := range rows {
+			data.Write(row.Data)
+			data.WriteByte('\n')
+		}
+		key := fmt.Sprintf("retention/%s/%s/%012d.jsonl", table, month.Format("2006-01"), rows[0].ID)
+		if err := s.archive.Put(ctx, key, data.Bytes(), "application/x-ndjson"); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", key, err)
+		}
+
+		if len(rows) < RetentionArchiveBatch {
+			return nil
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+}
@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/blobstore"
+	"hw/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestApplyRetention tests that the partitions of the current and next months and of the months
+// kept in the default partitions are created, and that the months past the retention of
+// swap_history are archived in batches and dropped while points_history is kept.
+func TestApplyRetention(t *testing.T) {
+	defer func(batch int) { service.RetentionArchiveBatch = batch }(service.RetentionArchiveBatch)
+	service.RetentionArchiveBatch = 2
+
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	store, err := blobstore.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	svc := service.NewService(mockRepo, service.WithRetention(config.Retention{SwapHistoryMonths: 3, Archive: true}, store))
+	ctx := context.Background()
+
+	now := time.Date(2024, 6, 12, 10, 0, 0, 0, time.UTC)
+	june := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	oldest := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().EnsureHistoryPartitions(ctx, model.HistorySwaps, june, june.AddDate(0, 1, 0)).Return(nil)
+	mockRepo.EXPECT().EnsureHistoryPartitions(ctx, model.HistoryPoints, june, june.AddDate(0, 1, 0)).Return(nil)
+
+	// Swaps of January, past the retention, and April were indexed into the default partition
+	april := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().GetDefaultPartitionMonths(ctx, model.HistorySwaps).Return([]time.Time{january, april}, nil)
+	mockRepo.EXPECT().EnsureHistoryPartitions(ctx, model.HistorySwaps, april, april).Return(nil)
+	mockRepo.EXPECT().GetDefaultPartitionMonths(ctx, model.HistoryPoints).Return([]time.Time{january}, nil)
+	mockRepo.EXPECT().EnsureHistoryPartitions(ctx, model.HistoryPoints, january, january).Return(nil)
+	mockRepo.EXPECT().GetOldestHistoryTime(ctx, model.HistorySwaps).Return(&oldest, nil)
+
+	// Months before March are past three months of retention
+	gomock.InOrder(
+		mockRepo.EXPECT().GetHistoryMonthRows(ctx, model.HistorySwaps, january, int64(0), 2).Return([]model.HistoryRow{
+			{ID: 1, Data: json.RawMessage(`{"id":1}`)},
+			{ID: 2, Data: json.RawMessage(`{"id":2}`)},
+		}, nil),
+		mockRepo.EXPECT().GetHistoryMonthRows(ctx, model.HistorySwaps, january, int64(2), 2).Return([]model.HistoryRow{
+			{ID: 5, Data: json.RawMessage(`{"id":5}`)},
+		}, nil),
+		mockRepo.EXPECT().DropHistoryMonth(ctx, model.HistorySwaps, january).Return(int64(3), nil),
+		mockRepo.EXPECT().GetHistoryMonthRows(ctx, model.HistorySwaps, february, int64(0), 2).Return(nil, nil),
+		mockRepo.EXPECT().DropHistoryMonth(ctx, model.HistorySwaps, february).Return(int64(0), nil),
+	)
+
+	assert.NoError(t, svc.ApplyRetention(ctx, now))
+
+	keys, err := store.List(ctx, "retention/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"retention/swap_history/2024-01/000000000001.jsonl", "retention/swap_history/2024-01/000000000005.jsonl"}, keys)
+	data, err := store.Get(ctx, keys[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", string(data))
+}
+
+// TestApplyRetention_NoArchive tests that archiving without a store fails before dropping anything.
+func TestApplyRetention_NoArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo, service.WithRetention(config.Retention{SwapHistoryMonths: 3, Archive: true}, nil))
+
+	assert.Error(t, svc.ApplyRetention(context.Background(), time.Now()))
+}
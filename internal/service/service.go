@@ -8,8 +8,11 @@ import (
 
 	"hw/internal/model"
 	"hw/internal/repository"
-	"hw/pkg/ethindexa/utils"
+	"hw/pkg/blobstore"
+	"hw/pkg/cache"
+	"hw/pkg/config"
 	"hw/pkg/logger"
+	"hw/pkg/notify"
 
 	"github.com/ethereum/go-ethereum/ethclient"
 	"golang.org/x/sync/singleflight"
@@ -19,103 +22,341 @@ import (
 
 // Service defines the interface for the service layer.
 type Service interface {
-	// AccumulateUserPoints adds points to a user's account with a description.
-	AccumulateUserPoints(ctx context.Context, token, user, description string, point float64) error
+	// AccumulateUserPoints adds points earned on a network to a user's account with a description.
+	AccumulateUserPoints(ctx context.Context, network, token, user, description string, point model.Decimal) error
+	// AccumulateUserPointsWithMetadata adds points earned on a network to a user's account, keeping their provenance in metadata.
+	AccumulateUserPointsWithMetadata(ctx context.Context, network, token, user, description string, point model.Decimal, metadata *model.PointsMetadata) error
+	// AccumulateSwapPoints adds points earned with a swap for a campaign to its trader's account, multiplied by the boost of the swap, if any.
+	AccumulateSwapPoints(ctx context.Context, history *model.SwapHistory, campaign, description string, point model.Decimal) error
+	// ResolvePointsBoost returns the largest boost an account holds on a network, or nil when it holds none.
+	ResolvePointsBoost(ctx context.Context, network, account string, balanceOf BalanceReader) (*model.PointsBoost, error)
 	// IsOnboardingTaskCompleted checks if the onboarding task is completed for an account.
 	IsOnboardingTaskCompleted(ctx context.Context, account string) (bool, error)
 	// GetOrCreateAccount retrieves an existing user or creates a new one if not found.
 	GetOrCreateAccount(ctx context.Context, accountId string) (*model.User, error)
+	// GetOrCreateAccounts retrieves or creates the users of several accounts at once, keyed by account.
+	GetOrCreateAccounts(ctx context.Context, accountIds []string) (map[string]*model.User, error)
 	// GetTokenByAddress retrieves a token by its address.
 	GetTokenByAddress(ctx context.Context, token string) (*model.Token, error)
-	// CreateSwapHistory records a new swap history entry.
+	// CreateSwapHistory records a new swap history entry, or returns model.ErrAlreadyExists when the swap was recorded.
 	CreateSwapHistory(ctx context.Context, history *model.SwapHistory) error
-	// GetSwapTotalUsd calculates the total USD value of swaps for an account and token.
-	GetSwapTotalUsd(ctx context.Context, account, token string) (float64, error)
+	// GetSwapTotalUsd calculates the total USD value and count of swaps for an account and token.
+	GetSwapTotalUsd(ctx context.Context, account, token string) (model.SwapTotal, error)
+	// FlagRoundTripSwaps flags the swaps of an account through a pool in one transaction as wash trades when there are several.
+	FlagRoundTripSwaps(ctx context.Context, history *model.SwapHistory) (bool, error)
+	// ExcludesWashTrades reports whether a campaign leaves the volume of wash trades out.
+	ExcludesWashTrades(campaign string) bool
 	// GetUserSwapSummary provides a summary of user swaps.
-	GetUserSwapSummary(ctx context.Context, account string) (map[string]float64, error)
+	GetUserSwapSummary(ctx context.Context, account string) (map[string]model.Decimal, error)
+	// GetUserSwapSummaryByNetwork provides a summary of user swaps on a single network.
+	GetUserSwapSummaryByNetwork(ctx context.Context, account, network string) (map[string]model.Decimal, error)
+	// GetUserNetworkSummary provides a user's swap volume and points grouped by network.
+	GetUserNetworkSummary(ctx context.Context, account string) (map[string]model.NetworkSummary, error)
+	// GetUserOverview retrieves a user's swap volume and points by token and by network, on a single network unless network is empty.
+	GetUserOverview(ctx context.Context, account, network string) (*model.UserOverview, error)
 	// GetUserSwapSummaryLast7Days retrieves the total USD and percentage of swaps for each user over the past seven days for a specific token.
-	GetUserSwapSummaryLast7Days(ctx context.Context, account string) ([]model.UserSwapPercentage, error)
+	// The USD of wash trades is reported separately, and left out of the totals when excludeWashTrades is set.
+	GetUserSwapSummaryLast7Days(ctx context.Context, token string, excludeWashTrades bool) ([]model.UserSwapPercentage, error)
 	// CreateToken creates a new token.
 	CreateToken(ctx context.Context, token *model.Token) error
+	// ListTokens retrieves one page of tokens with their swap volume, optionally filtered by symbol or name.
+	ListTokens(ctx context.Context, search, cursor string, limit int) ([]model.TokenVolume, string, error)
+	// GetTokenVolume retrieves a token with its swap volume.
+	GetTokenVolume(ctx context.Context, address string) (*model.TokenVolume, error)
 	// GetOrCreateToken retrieves an existing token or creates a new one if not found.
 	GetOrCreateToken(ctx context.Context, client *ethclient.Client, tokenId string, blockNumber int64) (*model.Token, error)
+	// GetOrCreateTokens retrieves or creates several tokens at once, keyed by token.
+	GetOrCreateTokens(ctx context.Context, client *ethclient.Client, tokenIds []string, blockNumber int64) (map[string]*model.Token, error)
 	// CreateAccount creates a new user account if it does not already exist.
 	CreateAccount(ctx context.Context, account *model.User) error
 	// GetPointsHistory retrieves the points history for a user and token.
 	GetPointsHistory(ctx context.Context, account, token string) ([]model.PointsHistory, error)
 	// GetLeaderboard retrieves the leaderboard data.
 	GetLeaderboard(ctx context.Context) ([]model.User, error)
+	// GetLeaderboardPage retrieves one page of the leaderboard and the cursor of the next page.
+	GetLeaderboardPage(ctx context.Context, cursor string, limit int) ([]model.User, string, error)
+	// GetUserRank retrieves a user's position on the leaderboard.
+	GetUserRank(ctx context.Context, address string) (*model.LeaderboardRank, error)
+	// SyncLeaderboard rebuilds the leaderboard mirror, if any, from Postgres.
+	SyncLeaderboard(ctx context.Context) error
+	// GetUserQuests retrieves every quest with the progress of a user towards it.
+	GetUserQuests(ctx context.Context, account string) ([]model.UserQuest, error)
+	// GetPointsHistoryByNetwork retrieves the points history for a user and token on a single network.
+	GetPointsHistoryByNetwork(ctx context.Context, account, token, network string) ([]model.PointsHistory, error)
+	// GetPointsHistoryByTokens retrieves the points history for a user and tokens, by token, on a single network unless network is empty.
+	GetPointsHistoryByTokens(ctx context.Context, account string, tokens []string, network string) (map[string][]model.PointsHistory, error)
+	// GetPointsHistoryPage retrieves one page of points history for a user and token.
+	GetPointsHistoryPage(ctx context.Context, account, token, cursor string, limit int) ([]model.PointsHistory, string, error)
+	// GetSwapHistoryPage retrieves one page of swap history for a user.
+	GetSwapHistoryPage(ctx context.Context, account, cursor string, limit int) ([]model.SwapHistory, string, error)
+	// GetSwapVolume aggregates the swaps on a network within [from, to) into buckets of the given interval, of a single
+	// pool unless pool is empty. It requires swap analytics, see WithSwapAnalytics.
+	GetSwapVolume(ctx context.Context, network, pool string, from, to time.Time, interval time.Duration) ([]model.VolumeBucket, error)
+	// GetVolumeLeaderboard retrieves the accounts that swapped the most USD on a network within [from, to). It requires
+	// swap analytics, see WithSwapAnalytics.
+	GetVolumeLeaderboard(ctx context.Context, network string, from, to time.Time, limit int) ([]model.TraderVolume, error)
+	// GetPoolStats retrieves the 24h/7d/30d volume statistics and top traders of a pool.
+	GetPoolStats(ctx context.Context, pool string, topTradersLimit int) (*model.PoolStats, error)
+	// RecordPoolReserves records a reserves snapshot of a pool.
+	RecordPoolReserves(ctx context.Context, reserves *model.PoolReserves) error
+	// GetPoolTVL retrieves the latest reserves and TVL of a pool.
+	GetPoolTVL(ctx context.Context, pool, network string) (*model.PoolReserves, error)
+	// RecordPositionChange records a change of a position and applies it to the current balance.
+	RecordPositionChange(ctx context.Context, change *model.PositionChange) error
+	// GetUserPositions retrieves the open positions of a user, on every network when network is empty.
+	GetUserPositions(ctx context.Context, account, network string) ([]model.Position, error)
+	// GetTokenHolderStats retrieves the number of holders of a token and their distribution by order of magnitude of
+	// their balance, from its indexed transfers.
+	GetTokenHolderStats(ctx context.Context, network, token string) (*model.HolderStats, error)
+	// DistributePositionRewards awards totalPoints to the accounts holding a position in a contract in
+	// proportion to their time-weighted balance within [from, to).
+	DistributePositionRewards(ctx context.Context, network, contract string, from, to time.Time, totalPoints model.Decimal) ([]model.PositionReward, error)
+	// DistributeTWAVRewards awards totalPoints to the accounts that swapped through a pool of network in proportion to
+	// their time-weighted average volume over the UTC days of [from, to).
+	DistributeTWAVRewards(ctx context.Context, network, pool string, from, to time.Time, totalPoints model.Decimal) ([]model.TWAVReward, error)
+	// RecordTokenPrice records a USD price of a token in its minute bucket.
+	RecordTokenPrice(ctx context.Context, price *model.TokenPrice) error
+	// GetTokenPrices retrieves the OHLC candles of a token's USD price within [from, to).
+	GetTokenPrices(ctx context.Context, token, network string, from, to time.Time, interval time.Duration) ([]model.PriceCandle, error)
+	// GetLatestTokenPrice retrieves the latest recorded USD price of a token at or before at, no older than maxAge.
+	GetLatestTokenPrice(ctx context.Context, token, network string, at time.Time, maxAge time.Duration) (*model.TokenPrice, error)
+	// ClaimPoints verifies a claim signed by the user and marks their claimable points as claimed.
+	ClaimPoints(ctx context.Context, address string, timestamp int64, signature string) (*model.PointClaim, error)
+	// RecordGasSpend records the gas fee paid by an account for an indexed transaction, once per transaction.
+	RecordGasSpend(ctx context.Context, spend *model.GasSpend) error
+	// GetGasLeaderboard retrieves the accounts that paid the most gas on a network, largest fees first.
+	GetGasLeaderboard(ctx context.Context, network string, limit int) ([]model.GasTotal, error)
+	// GetUserGasStats retrieves the gas a user paid for indexed transactions on each network.
+	GetUserGasStats(ctx context.Context, account string) ([]model.GasTotal, error)
+	// RecordApproval sets the allowance of a spender over an owner's tokens from an Approval event, unless a later
+	// approval set it already.
+	RecordApproval(ctx context.Context, allowance *model.Allowance) error
+	// GetUserApprovals retrieves the outstanding allowances granted by a user, on every network when network is empty.
+	GetUserApprovals(ctx context.Context, owner, network string) ([]model.Allowance, error)
+	// SetAddressLabel labels an address, or renames its label. The label must be one of model.AddressLabels.
+	SetAddressLabel(ctx context.Context, label *model.AddressLabel) error
+	// DeleteAddressLabel removes a label from an address by an admin operator.
+	DeleteAddressLabel(ctx context.Context, address, label, operator string) error
+	// ListAddressLabels retrieves the labelled addresses, of one label unless label is empty.
+	ListAddressLabels(ctx context.Context, label string) ([]model.AddressLabel, error)
+	// GetAddressLabels retrieves the labels of an address.
+	GetAddressLabels(ctx context.Context, address string) ([]string, error)
+	// TakeBalanceSnapshot computes the balance of every holder of a token at a block, from its indexed transfers or
+	// with balanceOf calls for the given holders, and stores it as a snapshot.
+	TakeBalanceSnapshot(ctx context.Context, client *ethclient.Client, network, token string, blockNumber int64, holders []string) (*model.BalanceSnapshot, []model.HolderBalance, error)
+	// GetBalanceSnapshot retrieves the latest snapshot of a token taken at a block, with the balance of every holder.
+	GetBalanceSnapshot(ctx context.Context, network, token string, blockNumber int64) (*model.BalanceSnapshot, []model.HolderBalance, error)
+	// GenerateDistribution snapshots total points at cutoff into a Merkle distribution and stores every proof.
+	GenerateDistribution(ctx context.Context, cutoff time.Time) (*model.MerkleDistribution, []model.MerkleProof, error)
+	// GetClaimProof retrieves an address's proof in the latest Merkle distribution.
+	GetClaimProof(ctx context.Context, address string) (*model.MerkleProof, error)
+	// FundDistribution transfers the token total of a distribution to the MerkleDistributor.
+	FundDistribution(ctx context.Context, distributionID int) (*model.SettlementTx, error)
+	// UpdateDistributionRoot sets the root of the MerkleDistributor to the root of a distribution.
+	UpdateDistributionRoot(ctx context.Context, distributionID int) (*model.SettlementTx, error)
+	// ReplaceStuckSettlements speeds up the settlement transactions pending for too long at now and returns their replacements.
+	ReplaceStuckSettlements(ctx context.Context, now time.Time) ([]model.SettlementTx, error)
+	// TrackSettlements records the receipts of the pending settlement transactions and returns those that got one.
+	TrackSettlements(ctx context.Context) ([]model.SettlementTx, error)
+	// CreateSignInNonce issues a single-use nonce for a Sign-In With Ethereum message.
+	CreateSignInNonce(ctx context.Context) (*model.SignInNonce, error)
+	// SignIn verifies a signed Sign-In With Ethereum message and issues a session for its address.
+	SignIn(ctx context.Context, message, signature string) (*model.Session, error)
+	// Authenticate verifies a session token and returns the address it was issued to.
+	Authenticate(token string) (string, error)
+	// GetUserProfile retrieves a user's profile, or an empty profile when none was saved.
+	GetUserProfile(ctx context.Context, address string) (*model.UserProfile, error)
+	// UpdateUserProfile replaces a user's profile.
+	UpdateUserProfile(ctx context.Context, profile *model.UserProfile) error
+	// SendNotifications sends the weekly summaries and big-swap alerts due at now.
+	SendNotifications(ctx context.Context, now time.Time) error
+	// ApplyRetention creates the upcoming partitions of the history tables and drops their months past the retention.
+	ApplyRetention(ctx context.Context, now time.Time) error
+	// CreateProject creates a project with a unique slug.
+	CreateProject(ctx context.Context, slug, name string) (*model.Project, error)
+	// GetProject retrieves a project by its slug.
+	GetProject(ctx context.Context, slug string) (*model.Project, error)
+	// CreateAPIKey issues an API key for a project, returned once in the Key field.
+	CreateAPIKey(ctx context.Context, projectSlug string) (*model.APIKey, error)
+	// AuthenticateAPIKey returns the project of an active API key.
+	AuthenticateAPIKey(ctx context.Context, key string) (*model.Project, error)
+	// RevokeAPIKey revokes the active API key with a prefix.
+	RevokeAPIKey(ctx context.Context, prefix string) error
+	// BeginIdempotentRequest reserves an idempotency key for a request, or returns the stored response of the key.
+	BeginIdempotentRequest(ctx context.Context, scope, key, requestHash string) (*model.IdempotentResponse, error)
+	// CompleteIdempotentRequest stores the response of a request begun with BeginIdempotentRequest.
+	CompleteIdempotentRequest(ctx context.Context, scope, key string, response *model.IdempotentResponse) error
+	// AbortIdempotentRequest releases the idempotency key of a request that failed without effect.
+	AbortIdempotentRequest(ctx context.Context, scope, key string) error
+	// SubscribeLiveEvents returns the live events matching filter from now on, until unsubscribe is called.
+	SubscribeLiveEvents(filter model.LiveEventFilter) (events <-chan model.LiveEvent, unsubscribe func())
+	// ListenLiveEvents passes the live events published by the indexer to the subscribers until ctx is done.
+	ListenLiveEvents(ctx context.Context) error
+	// DryRun returns a Service reading like this one but passing its writes to record instead of applying them.
+	DryRun(record func(method string, args any)) Service
+	// ForProject returns a Service of the points, claims, distributions, quests and labels of a project.
+	ForProject(projectID int) Service
+}
+
+// poolStatsWindows defines the time windows reported by GetPoolStats.
+var poolStatsWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{Name: "24h", Duration: 24 * time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
 }
 
 type service struct {
-	group singleflight.Group
-	repo  repository.Repository
+	group          *singleflight.Group
+	project        int // project of the reward data of repo; the leaderboard mirrors its points
+	repo           repository.Repository
+	handlerStore   HandlerStore // analytics outputs of handlers; the repository unless WithHandlerStore
+	tokenCache     cache.Cache
+	tokens         *tokenLRU
+	tokenBackoff   *tokenBackoff
+	fetchTokenInfo TokenInfoFetcher
+	fetchBalances  BalanceFetcher
+	leaderboard    LeaderboardStore
+	analytics      SwapAnalytics
+	quests         []config.Quest
+	boosts         []config.Boost
+	claims         config.Claims
+	points         config.Points
+	auth           config.Auth
+	sessionKey     []byte
+	notifications  config.Notifications
+	sender         notify.Sender
+	retention      config.Retention
+	archive        blobstore.Store
+	settlement     config.Settlement
+	transactor     Transactor
+	publishLive    bool
+	live           *liveBroker
 }
 
 // NewService creates a new instance of Service.
-func NewService(repo repository.Repository) Service {
-	return &service{repo: repo, group: singleflight.Group{}}
+// Token metadata lookups are cached locally unless another cache is given with WithTokenCache.
+func NewService(repo repository.Repository, opts ...Option) Service {
+	s := &service{
+		repo:           repo,
+		group:          &singleflight.Group{},
+		tokens:         newTokenLRU(TokenLRUSize),
+		tokenBackoff:   newTokenBackoff(),
+		fetchTokenInfo: defaultTokenInfoFetcher,
+		fetchBalances:  defaultBalanceFetcher,
+		claims:         config.Default().Claims,
+		auth:           config.Default().Auth,
+		notifications:  config.Default().Notifications,
+		live:           newLiveBroker(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.sessionKey = sessionKey(s.auth)
+	if s.handlerStore == nil {
+		s.handlerStore = repo
+	}
+	if s.tokenCache == nil {
+		s.tokenCache = cache.NewLocalCache(config.Default().Cache)
+	}
+	return s
 }
 
-// GetLeaderboard retrieves the leaderboard data and returns it as JSON.
+// GetLeaderboard retrieves the leaderboard data, from the mirror when available.
 func (s *service) GetLeaderboard(ctx context.Context) ([]model.User, error) {
-	return s.repo.GetLeaderboard(ctx)
+	if s.leaderboard != nil {
+		users, err := s.leaderboard.Top(ctx)
+		if err == nil {
+			return users, nil
+		}
+		logLeaderboardFallback(err)
+	}
+	return s.repo.GetLeaderboard(ctx, s.exclusion())
 }
 
-// AccumulateUserPoints adds points to a user's account with a description.
-func (s *service) AccumulateUserPoints(ctx context.Context, token, user, description string, point float64) error {
-	_, err, _ := s.group.Do(user, func() (interface{}, error) {
-		// Begin transaction
-		tx, err := s.repo.BeginTransaction(ctx)
-		if err != nil {
-			return nil, err
-		}
+// GetLeaderboardPage retrieves one page of the leaderboard and the cursor of the next page.
+func (s *service) GetLeaderboardPage(ctx context.Context, cursor string, limit int) ([]model.User, string, error) {
+	return s.repo.GetLeaderboardPage(ctx, cursor, limit, s.exclusion())
+}
 
-		// Use a closure to handle commit and rollback
-		err = func() error {
-			// Create points history record
-			pointsHistory := &model.PointsHistory{
-				Token:       token,
-				Account:     user,
-				Points:      point,
-				Description: description,
-			}
+// AccumulateUserPoints adds points earned on a network to a user's account with a description.
+// Every call is a separate award, also when concurrent with another award of the same user; only
+// the unique onboarding award of an account, kept in points_onboarding, skips an award.
+func (s *service) AccumulateUserPoints(ctx context.Context, network, token, user, description string, point model.Decimal) error {
+	return s.AccumulateUserPointsWithMetadata(ctx, network, token, user, description, point, nil)
+}
 
-			if err := s.repo.CreatePointsHistory(ctx, pointsHistory); err != nil {
-				return err
-			}
+// accumulatePoints records a points history entry and adds its points to the account's total.
+func (s *service) accumulatePoints(ctx context.Context, pointsHistory *model.PointsHistory) error {
+	credited := false
 
-			// Skip updating user points if points history was not created due to a conflict
-			if pointsHistory.ID == 0 {
-				return nil
-			}
+	// Begin transaction
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
 
-			// Atomically update the user's total points
-			if err := s.repo.UpsertUserPoints(ctx, user, point); err != nil {
-				return err
-			}
+	// Use a closure to handle commit and rollback
+	err = func() error {
+		repo := s.repo.WithTx(tx)
 
+		// Create points history record
+		if err := repo.CreatePointsHistory(ctx, pointsHistory); err != nil {
+			return err
+		}
+
+		// Skip updating user points if points history was not created due to a conflict
+		if pointsHistory.ID == 0 {
 			return nil
-		}()
-		if err != nil {
-			tx.Rollback(ctx)
-			return nil, err
 		}
 
-		// Commit transaction
-		if err := tx.Commit(ctx); err != nil {
-			return nil, err
+		// Atomically update the user's total points
+		if err := repo.UpsertUserPoints(ctx, pointsHistory.Account, pointsHistory.Points); err != nil {
+			return err
 		}
+		credited = true
 
-		return nil, nil
-	})
-	return err
+		// Keep the daily rollup in sync with the points history
+		if err := repo.IncrementDailyPointsRollup(ctx, pointsHistory); err != nil {
+			return err
+		}
+
+		return nil
+	}()
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if credited {
+		s.mirrorUserPoints(ctx, pointsHistory.Account, pointsHistory.Points)
+		s.publishLiveEvent(ctx, &model.LiveEvent{
+			Type:        model.LiveEventPoints,
+			Network:     pointsHistory.Network,
+			Pool:        pointsHistory.Token,
+			Account:     pointsHistory.Account,
+			Points:      &pointsHistory.Points,
+			Description: pointsHistory.Description,
+			Project:     s.project,
+			Time:        time.Now(),
+		})
+	}
+
+	return nil
 }
 
 // GetOrCreateAccount retrieves an existing user or creates a new one if not found.
 func (s *service) GetOrCreateAccount(ctx context.Context, accountId string) (*model.User, error) {
 	// singleflight is used to ensure that concurrent requests for the same accountId result in a single database query or creation.
-	v, err, _ := s.group.Do(accountId, func() (interface{}, error) {
+	// Keys are scoped by kind, as accounts and tokens share the group and an address can be both.
+	v, err, _ := s.group.Do("account:"+accountId, func() (interface{}, error) {
 		// Attempt to get the user first
 		user, err := s.repo.GetUserByAddress(ctx, accountId)
 		if err == nil {
@@ -143,9 +384,15 @@ func (s *service) GetOrCreateAccount(ctx context.Context, accountId string) (*mo
 }
 
 // GetOrCreateToken retrieves an existing token or creates a new one if not found.
+// The TokenLRUSize most recently used tokens are kept in memory, so the indexer reads a token
+// from the database once rather than once per event.
 func (s *service) GetOrCreateToken(ctx context.Context, client *ethclient.Client, tokenId string, blockNumber int64) (*model.Token, error) {
+	if token, ok := s.tokens.get(tokenId); ok {
+		return token, nil
+	}
+
 	// singleflight is utilized here to prevent multiple concurrent requests from fetching or creating the same token simultaneously.
-	v, err, _ := s.group.Do(tokenId, func() (interface{}, error) {
+	v, err, _ := s.group.Do("token:"+tokenId, func() (interface{}, error) {
 		// Try to get the token from the database
 		token, err := s.repo.GetTokenByAddress(ctx, tokenId)
 		if err == nil {
@@ -155,8 +402,8 @@ func (s *service) GetOrCreateToken(ctx context.Context, client *ethclient.Client
 			return nil, fmt.Errorf("failed to retrieve token %s from DB: %w", tokenId, err)
 		}
 
-		// Fetch token information from external source
-		tokenInfo, err := utils.GetTokenInfo(ctx, client, tokenId, blockNumber)
+		// Fetch token information from the cache or external source
+		tokenInfo, err := s.getTokenInfo(ctx, client, tokenId, blockNumber)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch token %s info: %w", tokenId, err)
 		}
@@ -183,7 +430,7 @@ func (s *service) GetOrCreateToken(ctx context.Context, client *ethclient.Client
 		}()
 
 		// Save the new token to the database
-		if err := s.repo.CreateToken(ctx, newToken); err != nil {
+		if err := s.repo.WithTx(tx).CreateToken(ctx, newToken); err != nil {
 			tx.Rollback(ctx)
 			return nil, fmt.Errorf("failed to create token %s in DB: %w", tokenId, err)
 		}
@@ -200,7 +447,113 @@ func (s *service) GetOrCreateToken(ctx context.Context, client *ethclient.Client
 		return nil, err
 	}
 
-	return v.(*model.Token), nil
+	token := v.(*model.Token)
+	s.tokens.add(token)
+	return token, nil
+}
+
+// GetOrCreateAccounts retrieves or creates the users of several accounts with one query for the
+// existing users and one for the missing ones, instead of one or two queries per account.
+func (s *service) GetOrCreateAccounts(ctx context.Context, accountIds []string) (map[string]*model.User, error) {
+	accountIds = uniqueIds(accountIds)
+	users := make(map[string]*model.User, len(accountIds))
+	if len(accountIds) == 0 {
+		return users, nil
+	}
+
+	existing, err := s.repo.GetUsersByAddresses(ctx, accountIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	for i := range existing {
+		users[existing[i].Address] = &existing[i]
+	}
+
+	missing := missingIds(accountIds, users)
+	if len(missing) == 0 {
+		return users, nil
+	}
+	created, err := s.repo.CreateUsers(ctx, missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create users: %w", err)
+	}
+	for i := range created {
+		users[created[i].Address] = &created[i]
+	}
+
+	// Users created concurrently since the first query were not returned by the insert
+	if raced := missingIds(missing, users); len(raced) > 0 {
+		existing, err := s.repo.GetUsersByAddresses(ctx, raced)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get users: %w", err)
+		}
+		for i := range existing {
+			users[existing[i].Address] = &existing[i]
+		}
+	}
+	return users, nil
+}
+
+// GetOrCreateTokens retrieves or creates several tokens. Tokens not yet in memory are read with
+// one query and kept in memory, so later GetOrCreateToken calls for them, e.g. by the handlers of
+// a block range, do not hit the database. Only the tokens missing from the database are created
+// one by one, as each needs its metadata fetched from the chain.
+func (s *service) GetOrCreateTokens(ctx context.Context, client *ethclient.Client, tokenIds []string, blockNumber int64) (map[string]*model.Token, error) {
+	tokenIds = uniqueIds(tokenIds)
+	tokens := make(map[string]*model.Token, len(tokenIds))
+	for _, tokenId := range tokenIds {
+		if token, ok := s.tokens.get(tokenId); ok {
+			tokens[tokenId] = token
+		}
+	}
+
+	unknown := missingIds(tokenIds, tokens)
+	if len(unknown) == 0 {
+		return tokens, nil
+	}
+	stored, err := s.repo.GetTokensByAddresses(ctx, unknown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tokens from DB: %w", err)
+	}
+	for i := range stored {
+		token := &stored[i]
+		s.tokens.add(token)
+		tokens[token.ID] = token
+	}
+
+	for _, tokenId := range missingIds(unknown, tokens) {
+		token, err := s.GetOrCreateToken(ctx, client, tokenId, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		tokens[tokenId] = token
+	}
+	return tokens, nil
+}
+
+// uniqueIds returns ids without duplicates, in their first order.
+func uniqueIds(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+// missingIds returns the ids that are not keys of found.
+func missingIds[T any](ids []string, found map[string]T) []string {
+	var missing []string
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
 }
 
 // GetTokenByAddress retrieves a token by its address.
@@ -208,9 +561,52 @@ func (s *service) GetTokenByAddress(ctx context.Context, token string) (*model.T
 	return s.repo.GetTokenByAddress(ctx, token)
 }
 
-// CreateSwapHistory records a new swap history entry.
+// CreateSwapHistory records a new swap history entry and updates the daily rollup. A swap already
+// recorded returns model.ErrAlreadyExists and is counted once.
 func (s *service) CreateSwapHistory(ctx context.Context, history *model.SwapHistory) error {
-	return s.repo.CreateSwapHistory(ctx, history)
+	counted, err := s.countedUsdValue(ctx, history)
+	if err != nil {
+		return err
+	}
+	history.CountedUsdValue = counted
+	if err := s.recordSwap(ctx, history); err != nil {
+		return err
+	}
+	if err := s.advanceQuests(ctx, history); err != nil {
+		return err
+	}
+	s.recordSwapAnalytics(ctx, history)
+	s.publishLiveEvent(ctx, &model.LiveEvent{
+		Type:            model.LiveEventSwap,
+		Network:         history.Network,
+		Pool:            history.Token,
+		Account:         history.Account,
+		TransactionHash: history.TransactionHash,
+		UsdValue:        &history.UsdValue,
+		Time:            history.LastUpdated,
+	})
+	return nil
+}
+
+// recordSwap inserts a swap history entry and adds it to the daily rollup in one transaction, so
+// the rollup never counts a swap that was not recorded or misses one that was.
+func (s *service) recordSwap(ctx context.Context, history *model.SwapHistory) error {
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	repo := s.repo.WithTx(tx)
+	if err := repo.CreateSwapHistory(ctx, history); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	if err := repo.IncrementDailySwapRollup(ctx, history); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // IsOnboardingTaskCompleted checks if the onboarding task is completed for an account.
@@ -218,19 +614,31 @@ func (s *service) IsOnboardingTaskCompleted(ctx context.Context, account string)
 	return s.repo.IsOnboardingTaskCompleted(ctx, account)
 }
 
-// GetSwapTotalUsd calculates the total USD value of swaps for an account and token.
-func (s *service) GetSwapTotalUsd(ctx context.Context, account, token string) (float64, error) {
+// GetSwapTotalUsd calculates the total USD value and count of swaps for an account and token.
+// An account without swaps has an empty total, not an error.
+func (s *service) GetSwapTotalUsd(ctx context.Context, account, token string) (model.SwapTotal, error) {
 	return s.repo.GetSwapTotalUsd(ctx, account, token)
 }
 
 // GetUserSwapSummary provides a summary of user swaps.
-func (s *service) GetUserSwapSummary(ctx context.Context, account string) (map[string]float64, error) {
+func (s *service) GetUserSwapSummary(ctx context.Context, account string) (map[string]model.Decimal, error) {
 	return s.repo.GetUserSwapSummary(ctx, account)
 }
 
+// GetUserSwapSummaryByNetwork provides a summary of user swaps on a single network.
+func (s *service) GetUserSwapSummaryByNetwork(ctx context.Context, account, network string) (map[string]model.Decimal, error) {
+	return s.repo.GetUserSwapSummaryByNetwork(ctx, account, network)
+}
+
+// GetUserNetworkSummary provides a user's swap volume and points grouped by network.
+func (s *service) GetUserNetworkSummary(ctx context.Context, account string) (map[string]model.NetworkSummary, error) {
+	return s.repo.GetUserNetworkSummary(ctx, account)
+}
+
 // GetUserSwapSummaryLast7Days retrieves the total USD and percentage of swaps for each user over the past seven days for a specific token.
-func (s *service) GetUserSwapSummaryLast7Days(ctx context.Context, account string) ([]model.UserSwapPercentage, error) {
-	return s.repo.GetUserSwapSummaryLast7Days(ctx, time.Now(), account)
+// The USD of wash trades is reported separately, and left out of the totals when excludeWashTrades is set.
+func (s *service) GetUserSwapSummaryLast7Days(ctx context.Context, token string, excludeWashTrades bool) ([]model.UserSwapPercentage, error) {
+	return s.repo.GetUserSwapSummaryLast7Days(ctx, time.Now(), token, excludeWashTrades, s.exclusion())
 }
 
 // GetPointsHistory retrieves the points history for a user and token.
@@ -238,6 +646,46 @@ func (s *service) GetPointsHistory(ctx context.Context, account, token string) (
 	return s.repo.GetPointsHistory(ctx, account, token)
 }
 
+// GetPointsHistoryByNetwork retrieves the points history for a user and token on a single network.
+func (s *service) GetPointsHistoryByNetwork(ctx context.Context, account, token, network string) ([]model.PointsHistory, error) {
+	return s.repo.GetPointsHistoryByNetwork(ctx, account, token, network)
+}
+
+// GetPointsHistoryByTokens retrieves the points history for a user and tokens in one query,
+// grouped by token, newest first, on a single network unless network is empty.
+func (s *service) GetPointsHistoryByTokens(ctx context.Context, account string, tokens []string, network string) (map[string][]model.PointsHistory, error) {
+	histories, err := s.repo.GetPointsHistoryByTokens(ctx, account, tokens, network)
+	if err != nil {
+		return nil, err
+	}
+
+	byToken := make(map[string][]model.PointsHistory, len(tokens))
+	for _, ph := range histories {
+		byToken[ph.Token] = append(byToken[ph.Token], ph)
+	}
+	return byToken, nil
+}
+
+// GetPointsHistoryPage retrieves one page of points history for a user and token.
+func (s *service) GetPointsHistoryPage(ctx context.Context, account, token, cursor string, limit int) ([]model.PointsHistory, string, error) {
+	return s.repo.GetPointsHistoryPage(ctx, account, token, cursor, limit)
+}
+
+// ListTokens retrieves one page of tokens with their swap volume, optionally filtered by symbol or name.
+func (s *service) ListTokens(ctx context.Context, search, cursor string, limit int) ([]model.TokenVolume, string, error) {
+	return s.repo.ListTokens(ctx, search, cursor, limit)
+}
+
+// GetTokenVolume retrieves a token with its swap volume.
+func (s *service) GetTokenVolume(ctx context.Context, address string) (*model.TokenVolume, error) {
+	return s.repo.GetTokenVolume(ctx, address)
+}
+
+// GetSwapHistoryPage retrieves one page of swap history for a user.
+func (s *service) GetSwapHistoryPage(ctx context.Context, account, cursor string, limit int) ([]model.SwapHistory, string, error) {
+	return s.repo.GetSwapHistoryPage(ctx, account, cursor, limit)
+}
+
 // CreateAccount creates a new user account if it does not already exist.
 func (s *service) CreateAccount(ctx context.Context, account *model.User) error {
 	existingUser, err := s.repo.GetUserByAddress(ctx, account.Address)
@@ -270,8 +718,47 @@ func (s *service) CreateToken(ctx context.Context, token *model.Token) error {
 		if err != nil {
 			return err
 		}
+		// Drop any token kept in memory under this address, so it is read back as stored
+		s.tokens.remove(token.ID)
 	} else {
 		logger.Infof("Token already exists: %s", existingToken.ID)
 	}
 	return nil
 }
+
+// GetPoolTVL retrieves the latest reserves and TVL of a pool.
+func (s *service) GetPoolTVL(ctx context.Context, pool, network string) (*model.PoolReserves, error) {
+	return s.handlerStore.GetLatestPoolReserves(ctx, pool, network)
+}
+
+// RecordPoolReserves records a reserves snapshot of a pool.
+func (s *service) RecordPoolReserves(ctx context.Context, reserves *model.PoolReserves) error {
+	return s.handlerStore.UpsertPoolReserves(ctx, reserves)
+}
+
+// GetPoolStats retrieves the 24h/7d/30d volume statistics and top traders of a pool.
+// Top traders are ranked over the widest window.
+func (s *service) GetPoolStats(ctx context.Context, pool string, topTradersLimit int) (*model.PoolStats, error) {
+	now := time.Now()
+	stats := &model.PoolStats{
+		Pool:    pool,
+		Windows: make(map[string]model.PoolVolumeStats, len(poolStatsWindows)),
+	}
+
+	for _, window := range poolStatsWindows {
+		volume, err := s.repo.GetPoolVolumeStats(ctx, pool, now.Add(-window.Duration))
+		if err != nil {
+			return nil, err
+		}
+		stats.Windows[window.Name] = *volume
+	}
+
+	widest := poolStatsWindows[len(poolStatsWindows)-1]
+	traders, err := s.repo.GetPoolTopTraders(ctx, pool, now.Add(-widest.Duration), topTradersLimit)
+	if err != nil {
+		return nil, err
+	}
+	stats.TopTraders = traders
+
+	return stats, nil
+}
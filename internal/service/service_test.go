@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"hw/internal/service"
 	pgMock "hw/pkg/pg/mocks"
 
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -26,10 +28,11 @@ func TestAccumulateUserPoints_Success(t *testing.T) {
 	svc := service.NewService(mockRepo)
 
 	ctx := context.Background()
+	network := "mainnet"
 	token := "tokenABC"
 	user := "userXYZ"
 	description := "Test Accumulation"
-	point := 100.0
+	point := model.NewDecimalFromFloat(100)
 
 	pointsHistory := &model.PointsHistory{
 		ID: 1,
@@ -37,6 +40,7 @@ func TestAccumulateUserPoints_Success(t *testing.T) {
 
 	// Set expectations for mockRepo
 	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
 	mockRepo.EXPECT().
 		CreatePointsHistory(ctx, gomock.AssignableToTypeOf(&model.PointsHistory{})).
 		DoAndReturn(func(ctx context.Context, ph *model.PointsHistory) error {
@@ -45,10 +49,11 @@ func TestAccumulateUserPoints_Success(t *testing.T) {
 			return nil
 		})
 	mockRepo.EXPECT().UpsertUserPoints(ctx, user, point).Return(nil)
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.AssignableToTypeOf(&model.PointsHistory{})).Return(nil)
 	mockTx.EXPECT().Commit(ctx).Return(nil)
 
 	// Execute service method
-	err := svc.AccumulateUserPoints(ctx, token, user, description, point)
+	err := svc.AccumulateUserPoints(ctx, network, token, user, description, point)
 
 	// Validate results
 	assert.NoError(t, err)
@@ -65,12 +70,14 @@ func TestAccumulateUserPoints_CreatePointsHistoryError(t *testing.T) {
 	svc := service.NewService(mockRepo)
 
 	ctx := context.Background()
+	network := "mainnet"
 	token := "tokenABC"
 	user := "userXYZ"
 	description := "Test Accumulation"
-	point := 100.0
+	point := model.NewDecimalFromFloat(100)
 
 	pointsHistory := &model.PointsHistory{
+		Network:     network,
 		Token:       token,
 		Account:     user,
 		Points:      point,
@@ -80,15 +87,83 @@ func TestAccumulateUserPoints_CreatePointsHistoryError(t *testing.T) {
 	expectedError := errors.New("failed to create points history")
 
 	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
 	mockRepo.EXPECT().CreatePointsHistory(ctx, pointsHistory).Return(expectedError)
 	mockTx.EXPECT().Rollback(ctx).Return(nil)
 
-	err := svc.AccumulateUserPoints(ctx, token, user, description, point)
+	err := svc.AccumulateUserPoints(ctx, network, token, user, description, point)
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
 }
 
+// TestAccumulateUserPoints_Concurrent tests that concurrent awards of the same user are all credited.
+func TestAccumulateUserPoints_Concurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	user := "userXYZ"
+	awards := []string{"onboarding_task", "sharepool_usdcweth_task", "lp_reward_task"}
+
+	// Hold every award in CreatePointsHistory until all of them are in flight
+	var inFlight sync.WaitGroup
+	inFlight.Add(len(awards))
+	var mutex sync.Mutex
+	credited := make(map[string]bool)
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil).Times(len(awards))
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo).Times(len(awards))
+	mockRepo.EXPECT().CreatePointsHistory(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, ph *model.PointsHistory) error {
+		inFlight.Done()
+		inFlight.Wait()
+		mutex.Lock()
+		defer mutex.Unlock()
+		credited[ph.Description] = true
+		ph.ID = len(credited)
+		return nil
+	}).Times(len(awards))
+	mockRepo.EXPECT().UpsertUserPoints(ctx, user, gomock.Any()).Return(nil).Times(len(awards))
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.Any()).Return(nil).Times(len(awards))
+	mockTx.EXPECT().Commit(ctx).Return(nil).Times(len(awards))
+
+	var wg sync.WaitGroup
+	for _, description := range awards {
+		wg.Add(1)
+		go func(description string) {
+			defer wg.Done()
+			assert.NoError(t, svc.AccumulateUserPoints(ctx, "mainnet", "tokenABC", user, description, model.NewDecimalFromFloat(100)))
+		}(description)
+	}
+	wg.Wait()
+
+	assert.Len(t, credited, len(awards))
+}
+
+// TestAccumulateUserPoints_Conflict tests that an award skipped by a unique constraint is not credited.
+func TestAccumulateUserPoints_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().CreatePointsHistory(ctx, gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+
+	err := svc.AccumulateUserPoints(ctx, "mainnet", "tokenABC", "userXYZ", "onboarding_task", model.NewDecimalFromFloat(100))
+
+	assert.NoError(t, err)
+}
+
 // TestGetOrCreateAccount_GetUserSuccess tests GetOrCreateAccount when the user exists.
 func TestGetOrCreateAccount_GetUserSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -102,7 +177,7 @@ func TestGetOrCreateAccount_GetUserSuccess(t *testing.T) {
 	existingUser := &model.User{
 		ID:          1,
 		Address:     accountId,
-		TotalPoints: 200.0,
+		TotalPoints: model.NewDecimalFromFloat(200.0),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -128,7 +203,7 @@ func TestGetOrCreateAccount_CreateUserSuccess(t *testing.T) {
 	newUser := &model.User{
 		ID:          2,
 		Address:     accountId,
-		TotalPoints: 0.0,
+		TotalPoints: model.NewDecimalFromFloat(0.0),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -164,6 +239,55 @@ func TestGetOrCreateAccount_CreateUserError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to create user")
 }
 
+// TestGetOrCreateAccounts tests that existing users are read and missing ones created with one query each,
+// and that users created concurrently are read back.
+func TestGetOrCreateAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	existing := model.User{ID: 1, Address: "account1", TotalPoints: model.NewDecimalFromFloat(100)}
+	created := model.User{ID: 2, Address: "account2"}
+	raced := model.User{ID: 3, Address: "account3"}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetUsersByAddresses(ctx, []string{"account1", "account2", "account3"}).Return([]model.User{existing}, nil),
+		mockRepo.EXPECT().CreateUsers(ctx, []string{"account2", "account3"}).Return([]model.User{created}, nil),
+		mockRepo.EXPECT().GetUsersByAddresses(ctx, []string{"account3"}).Return([]model.User{raced}, nil),
+	)
+
+	users, err := svc.GetOrCreateAccounts(ctx, []string{"account1", "account2", "account1", "account3"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*model.User{"account1": &existing, "account2": &created, "account3": &raced}, users)
+
+	users, err = svc.GetOrCreateAccounts(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+// TestGetOrCreateAccounts_CreateUsersError tests that a failed batch creation is reported.
+func TestGetOrCreateAccounts_CreateUsersError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetUsersByAddresses(ctx, []string{"account1"}).Return(nil, nil)
+	mockRepo.EXPECT().CreateUsers(ctx, []string{"account1"}).Return(nil, errors.New("insert failed"))
+
+	users, err := svc.GetOrCreateAccounts(ctx, []string{"account1"})
+
+	assert.Nil(t, users)
+	assert.ErrorContains(t, err, "insert failed")
+}
+
 // TestGetTokenByAddress_Success tests the successful retrieval of a token by address.
 func TestGetTokenByAddress_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -221,6 +345,7 @@ func TestCreateSwapHistory_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
 	svc := service.NewService(mockRepo)
 
 	ctx := context.Background()
@@ -228,23 +353,52 @@ func TestCreateSwapHistory_Success(t *testing.T) {
 		Token:           "tokenABC",
 		Account:         "accountXYZ",
 		TransactionHash: "tx123456",
-		UsdValue:        250.75,
+		UsdValue:        model.NewDecimalFromFloat(250.75),
 		LastUpdated:     time.Now(),
 	}
 
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
 	mockRepo.EXPECT().CreateSwapHistory(ctx, swapHistory).Return(nil)
+	mockRepo.EXPECT().IncrementDailySwapRollup(ctx, swapHistory).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
 
 	err := svc.CreateSwapHistory(ctx, swapHistory)
 
 	assert.NoError(t, err)
 }
 
+// TestCreateSwapHistory_RollupError tests that the swap is rolled back with the transaction when
+// the daily rollup cannot be updated.
+func TestCreateSwapHistory_RollupError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	swapHistory := &model.SwapHistory{Token: "tokenABC", Account: "accountXYZ", TransactionHash: "tx123456", LastUpdated: time.Now()}
+
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().CreateSwapHistory(ctx, swapHistory).Return(nil)
+	mockRepo.EXPECT().IncrementDailySwapRollup(ctx, swapHistory).Return(errors.New("failed to increment daily swap rollup"))
+	mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+	err := svc.CreateSwapHistory(ctx, swapHistory)
+
+	assert.ErrorContains(t, err, "failed to increment daily swap rollup")
+}
+
 // TestCreateSwapHistory_Error tests the scenario where creating swap history fails.
 func TestCreateSwapHistory_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
 	svc := service.NewService(mockRepo)
 
 	ctx := context.Background()
@@ -252,13 +406,16 @@ func TestCreateSwapHistory_Error(t *testing.T) {
 		Token:           "tokenABC",
 		Account:         "accountXYZ",
 		TransactionHash: "tx123456",
-		UsdValue:        250.75,
+		UsdValue:        model.NewDecimalFromFloat(250.75),
 		LastUpdated:     time.Now(),
 	}
 
 	expectedError := errors.New("failed to create swap history")
 
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
 	mockRepo.EXPECT().CreateSwapHistory(ctx, swapHistory).Return(expectedError)
+	mockTx.EXPECT().Rollback(ctx).Return(nil)
 
 	err := svc.CreateSwapHistory(ctx, swapHistory)
 
@@ -266,6 +423,26 @@ func TestCreateSwapHistory_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to create swap history")
 }
 
+// TestCreateSwapHistory_AlreadyRecorded tests that a swap already recorded does not update the daily rollup again.
+func TestCreateSwapHistory_AlreadyRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	swapHistory := &model.SwapHistory{Account: "accountXYZ", TransactionHash: "tx123456", LogIndex: 4, LastUpdated: time.Now()}
+
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().CreateSwapHistory(ctx, swapHistory).Return(model.ErrAlreadyExists)
+	mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+	assert.ErrorIs(t, svc.CreateSwapHistory(ctx, swapHistory), model.ErrAlreadyExists)
+}
+
 // TestGetLeaderboard_Success tests the successful retrieval of the leaderboard.
 func TestGetLeaderboard_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -279,20 +456,20 @@ func TestGetLeaderboard_Success(t *testing.T) {
 		{
 			ID:          1,
 			Address:     "user1",
-			TotalPoints: 300.0,
+			TotalPoints: model.NewDecimalFromFloat(300.0),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
 		{
 			ID:          2,
 			Address:     "user2",
-			TotalPoints: 200.0,
+			TotalPoints: model.NewDecimalFromFloat(200.0),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
 	}
 
-	mockRepo.EXPECT().GetLeaderboard(ctx).Return(expectedLeaderboard, nil)
+	mockRepo.EXPECT().GetLeaderboard(ctx, model.Exclusion{}).Return(expectedLeaderboard, nil)
 
 	leaderboard, err := svc.GetLeaderboard(ctx)
 
@@ -311,7 +488,7 @@ func TestGetLeaderboard_Error(t *testing.T) {
 	ctx := context.Background()
 	expectedError := errors.New("failed to get leaderboard")
 
-	mockRepo.EXPECT().GetLeaderboard(ctx).Return(nil, expectedError)
+	mockRepo.EXPECT().GetLeaderboard(ctx, model.Exclusion{}).Return(nil, expectedError)
 
 	leaderboard, err := svc.GetLeaderboard(ctx)
 
@@ -320,14 +497,219 @@ func TestGetLeaderboard_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to get leaderboard")
 }
 
+// fakeTokenInfo returns a TokenInfoFetcher serving token or err and counting its calls.
+func fakeTokenInfo(token *model.Token, err error, calls *int) service.TokenInfoFetcher {
+	return func(ctx context.Context, client *ethclient.Client, tokenId string, blockNumber int64) (*model.Token, error) {
+		*calls++
+		if err != nil {
+			return nil, err
+		}
+		return &model.Token{ID: tokenId, Name: token.Name, Symbol: token.Symbol, Decimals: token.Decimals}, nil
+	}
+}
+
 // TestGetOrCreateToken_Success tests the successful creation of a token when it does not exist.
-// TODO:
+func TestGetOrCreateToken_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	calls := 0
+	info := &model.Token{Name: "USD Coin", Symbol: "USDC", Decimals: 6}
+	svc := service.NewService(mockRepo, service.WithTokenInfoFetcher(fakeTokenInfo(info, nil, &calls)))
+
+	ctx := context.Background()
+	tokenId := "0xtoken-success"
+
+	mockRepo.EXPECT().GetTokenByAddress(ctx, tokenId).Return(nil, model.ErrTokenNotFound)
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().CreateToken(ctx, gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+
+	token, err := svc.GetOrCreateToken(ctx, nil, tokenId, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Token{ID: tokenId, Name: "USD Coin", Symbol: "USDC", Decimals: 6}, token)
+	assert.Equal(t, 1, calls)
+}
 
 // TestGetOrCreateToken_CreateTokenError tests the scenario where creating a token fails.
-// TODO:
+// The fetched metadata is cached so the retry does not hit the chain again.
+func TestGetOrCreateToken_CreateTokenError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	calls := 0
+	info := &model.Token{Name: "Wrapped Ether", Symbol: "WETH", Decimals: 18}
+	svc := service.NewService(mockRepo, service.WithTokenInfoFetcher(fakeTokenInfo(info, nil, &calls)))
+
+	ctx := context.Background()
+	tokenId := "0xtoken-create-error"
+
+	mockRepo.EXPECT().GetTokenByAddress(ctx, tokenId).Return(nil, model.ErrTokenNotFound).Times(2)
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil).Times(2)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo).Times(2)
+	mockRepo.EXPECT().CreateToken(ctx, gomock.Any()).Return(errors.New("insert failed")).Times(2)
+	mockTx.EXPECT().Rollback(ctx).Return(nil).Times(2)
+
+	token, err := svc.GetOrCreateToken(ctx, nil, tokenId, 100)
+	assert.Error(t, err)
+	assert.Nil(t, token)
+	assert.Contains(t, err.Error(), "insert failed")
+
+	_, err = svc.GetOrCreateToken(ctx, nil, tokenId, 100)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
 
 // TestGetOrCreateToken_Exist tests the scenario where the token already exists.
-// TODO:
+func TestGetOrCreateToken_Exist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	calls := 0
+	svc := service.NewService(mockRepo, service.WithTokenInfoFetcher(fakeTokenInfo(nil, errors.New("unexpected"), &calls)))
+
+	ctx := context.Background()
+	expectedToken := &model.Token{ID: "0xtoken-exist", Name: "USD Coin", Symbol: "USDC", Decimals: 6}
+
+	mockRepo.EXPECT().GetTokenByAddress(ctx, expectedToken.ID).Return(expectedToken, nil)
+
+	token, err := svc.GetOrCreateToken(ctx, nil, expectedToken.ID, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, 0, calls)
+}
+
+// TestGetOrCreateToken_FetchBackoff tests that a failing token is not looked up again while backing off.
+func TestGetOrCreateToken_FetchBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	calls := 0
+	svc := service.NewService(mockRepo, service.WithTokenInfoFetcher(fakeTokenInfo(nil, errors.New("execution reverted"), &calls)))
+
+	ctx := context.Background()
+	tokenId := "0xtoken-broken"
+
+	mockRepo.EXPECT().GetTokenByAddress(ctx, tokenId).Return(nil, model.ErrTokenNotFound).Times(2)
+
+	_, err := svc.GetOrCreateToken(ctx, nil, tokenId, 100)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "execution reverted")
+
+	_, err = svc.GetOrCreateToken(ctx, nil, tokenId, 100)
+	assert.ErrorIs(t, err, model.ErrTokenInfoUnavailable)
+	assert.Equal(t, 1, calls)
+}
+
+// TestGetOrCreateToken_Known tests that a token found once is not read from the database again.
+func TestGetOrCreateToken_Known(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	expectedToken := &model.Token{ID: "0xtoken-known", Name: "USD Coin", Symbol: "USDC", Decimals: 6}
+
+	mockRepo.EXPECT().GetTokenByAddress(ctx, expectedToken.ID).Return(expectedToken, nil).Times(1)
+
+	for i := 0; i < 3; i++ {
+		token, err := svc.GetOrCreateToken(ctx, nil, expectedToken.ID, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedToken, token)
+	}
+}
+
+// TestGetOrCreateToken_LRU tests that the least recently used token is evicted when the LRU is
+// full, and that creating a token drops it from the LRU.
+func TestGetOrCreateToken_LRU(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	size := service.TokenLRUSize
+	service.TokenLRUSize = 2
+	defer func() { service.TokenLRUSize = size }()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	usdc := &model.Token{ID: "0xusdc", Symbol: "USDC", Decimals: 6}
+	weth := &model.Token{ID: "0xweth", Symbol: "WETH", Decimals: 18}
+	dai := &model.Token{ID: "0xdai", Symbol: "DAI", Decimals: 18}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetTokenByAddress(ctx, usdc.ID).Return(usdc, nil),
+		mockRepo.EXPECT().GetTokenByAddress(ctx, weth.ID).Return(weth, nil),
+		mockRepo.EXPECT().GetTokenByAddress(ctx, dai.ID).Return(dai, nil),
+		// weth was evicted by dai, as usdc was used after it
+		mockRepo.EXPECT().GetTokenByAddress(ctx, weth.ID).Return(weth, nil),
+		// dai is kept, but CreateToken drops it
+		mockRepo.EXPECT().GetTokenByAddress(ctx, dai.ID).Return(nil, model.ErrTokenNotFound),
+		mockRepo.EXPECT().CreateToken(ctx, dai).Return(nil),
+		mockRepo.EXPECT().GetTokenByAddress(ctx, dai.ID).Return(dai, nil),
+	)
+
+	for _, token := range []*model.Token{usdc, weth, usdc, dai, weth, dai} {
+		got, err := svc.GetOrCreateToken(ctx, nil, token.ID, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, token, got)
+	}
+	assert.NoError(t, svc.CreateToken(ctx, dai))
+	got, err := svc.GetOrCreateToken(ctx, nil, dai.ID, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, dai, got)
+}
+
+// TestGetOrCreateTokens tests that stored tokens are read with one query, missing ones created,
+// and that the tokens are then served from memory.
+func TestGetOrCreateTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	calls := 0
+	info := &model.Token{Name: "Wrapped Ether", Symbol: "WETH", Decimals: 18}
+	svc := service.NewService(mockRepo, service.WithTokenInfoFetcher(fakeTokenInfo(info, nil, &calls)))
+
+	ctx := context.Background()
+	stored := model.Token{ID: "0xusdc", Name: "USD Coin", Symbol: "USDC", Decimals: 6}
+
+	mockRepo.EXPECT().GetTokensByAddresses(ctx, []string{"0xusdc", "0xweth"}).Return([]model.Token{stored}, nil)
+	mockRepo.EXPECT().GetTokenByAddress(ctx, "0xweth").Return(nil, model.ErrTokenNotFound)
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+	mockRepo.EXPECT().CreateToken(ctx, gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+
+	tokens, err := svc.GetOrCreateTokens(ctx, nil, []string{"0xusdc", "0xweth", "0xusdc"}, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*model.Token{
+		"0xusdc": &stored,
+		"0xweth": {ID: "0xweth", Name: "Wrapped Ether", Symbol: "WETH", Decimals: 18},
+	}, tokens)
+	assert.Equal(t, 1, calls)
+
+	// Both tokens are now known
+	tokens, err = svc.GetOrCreateTokens(ctx, nil, []string{"0xweth", "0xusdc"}, 101)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+	token, err := svc.GetOrCreateToken(ctx, nil, "0xusdc", 101)
+	assert.NoError(t, err)
+	assert.Equal(t, &stored, token)
+}
 
 // TestIsOnboardingTaskCompleted_Success tests the successful check of onboarding task completion.
 func TestIsOnboardingTaskCompleted_Success(t *testing.T) {
@@ -381,7 +763,7 @@ func TestGetSwapTotalUsd_Success(t *testing.T) {
 	ctx := context.Background()
 	account := "accountXYZ"
 	token := "tokenABC"
-	expectedTotalUsd := 1000.50
+	expectedTotalUsd := model.SwapTotal{UsdValue: model.NewDecimalFromFloat(1000.50), SwapCount: 3}
 
 	mockRepo.EXPECT().GetSwapTotalUsd(ctx, account, token).Return(expectedTotalUsd, nil)
 
@@ -405,13 +787,13 @@ func TestGetSwapTotalUsd_Failure(t *testing.T) {
 
 	expectedError := errors.New("repository error")
 
-	mockRepo.EXPECT().GetSwapTotalUsd(ctx, account, token).Return(0.0, expectedError)
+	mockRepo.EXPECT().GetSwapTotalUsd(ctx, account, token).Return(model.SwapTotal{}, expectedError)
 
 	totalUsd, err := svc.GetSwapTotalUsd(ctx, account, token)
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
-	assert.Equal(t, float64(0), totalUsd, "Total USD should be 0 due to error.")
+	assert.True(t, totalUsd.Empty(), "Total should be empty due to error.")
 }
 
 // TestGetUserSwapSummary_Success tests the successful retrieval of user swap summary.
@@ -425,9 +807,9 @@ func TestGetUserSwapSummary_Success(t *testing.T) {
 	ctx := context.Background()
 	account := "accountXYZ"
 
-	expectedSummary := map[string]float64{
-		"tokenABC": 1000.50,
-		"tokenXYZ": 500.25,
+	expectedSummary := map[string]model.Decimal{
+		"tokenABC": model.NewDecimalFromFloat(1000.50),
+		"tokenXYZ": model.NewDecimalFromFloat(500.25),
 	}
 
 	mockRepo.EXPECT().GetUserSwapSummary(ctx, account).Return(expectedSummary, nil)
@@ -474,19 +856,19 @@ func TestGetUserSwapSummaryLast7Days_Success(t *testing.T) {
 	expectedSummary := []model.UserSwapPercentage{
 		{
 			Account:    "user1",
-			TotalUSD:   1500.75,
-			Percentage: 0.60,
+			TotalUSD:   model.NewDecimalFromFloat(1500.75),
+			Percentage: model.NewDecimalFromFloat(0.60),
 		},
 		{
 			Account:    "user2",
-			TotalUSD:   1000.25,
-			Percentage: 0.40,
+			TotalUSD:   model.NewDecimalFromFloat(1000.25),
+			Percentage: model.NewDecimalFromFloat(0.40),
 		},
 	}
 
-	mockRepo.EXPECT().GetUserSwapSummaryLast7Days(ctx, gomock.Any(), gomock.Any()).Return(expectedSummary, nil)
+	mockRepo.EXPECT().GetUserSwapSummaryLast7Days(ctx, gomock.Any(), gomock.Any(), true, model.Exclusion{}).Return(expectedSummary, nil)
 
-	summary, err := svc.GetUserSwapSummaryLast7Days(ctx, account)
+	summary, err := svc.GetUserSwapSummaryLast7Days(ctx, account, true)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSummary, summary, "User swap summary last 7 days should match expected.")
@@ -505,9 +887,9 @@ func TestGetUserSwapSummaryLast7Days_Failure(t *testing.T) {
 
 	expectedError := errors.New("repository error")
 
-	mockRepo.EXPECT().GetUserSwapSummaryLast7Days(ctx, gomock.Any(), gomock.Any()).Return(nil, expectedError)
+	mockRepo.EXPECT().GetUserSwapSummaryLast7Days(ctx, gomock.Any(), gomock.Any(), true, model.Exclusion{}).Return(nil, expectedError)
 
-	summary, err := svc.GetUserSwapSummaryLast7Days(ctx, account)
+	summary, err := svc.GetUserSwapSummaryLast7Days(ctx, account, true)
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
@@ -532,7 +914,7 @@ func TestCreateAccount_Success(t *testing.T) {
 	createdUser := &model.User{
 		ID:          1,
 		Address:     "accountXYZ",
-		TotalPoints: 0.0,
+		TotalPoints: model.NewDecimalFromFloat(0.0),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -559,7 +941,7 @@ func TestCreateAccount_UserAlreadyExists(t *testing.T) {
 	existingUser := &model.User{
 		ID:          1,
 		Address:     "accountXYZ",
-		TotalPoints: 100.5,
+		TotalPoints: model.NewDecimalFromFloat(100.5),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -691,7 +1073,7 @@ func TestGetPointsHistory_Success(t *testing.T) {
 			ID:          1,
 			Token:       "tokenABC",
 			Account:     "accountXYZ",
-			Points:      100.0,
+			Points:      model.NewDecimalFromFloat(100.0),
 			Description: "Test Points",
 			CreatedAt:   time.Now(),
 		},
@@ -727,3 +1109,90 @@ func TestGetPointsHistory_Failure(t *testing.T) {
 	assert.Equal(t, expectedError, err)
 	assert.Nil(t, history, "Points history should be nil due to error.")
 }
+
+// TestGetPointsHistoryByTokens tests that the points history of several tokens is grouped by token.
+func TestGetPointsHistoryByTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	account := "accountXYZ"
+	tokens := []string{"tokenABC", "tokenDEF", "tokenGHI"}
+	histories := []model.PointsHistory{
+		{ID: 3, Network: "base", Token: "tokenABC", Points: model.NewDecimalFromFloat(3)},
+		{ID: 2, Network: "base", Token: "tokenDEF", Points: model.NewDecimalFromFloat(2)},
+		{ID: 1, Network: "base", Token: "tokenABC", Points: model.NewDecimalFromFloat(1)},
+	}
+	mockRepo.EXPECT().GetPointsHistoryByTokens(ctx, account, tokens, "base").Return(histories, nil)
+
+	byToken, err := svc.GetPointsHistoryByTokens(ctx, account, tokens, "base")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]model.PointsHistory{
+		"tokenABC": {histories[0], histories[2]},
+		"tokenDEF": {histories[1]},
+	}, byToken)
+}
+
+// TestGetPoolStats_Success tests the successful retrieval of pool statistics.
+func TestGetPoolStats_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	pool := "poolABC"
+
+	volume := &model.PoolVolumeStats{VolumeUsd: model.NewDecimalFromFloat(100.5), SwapCount: 2, UniqueAccounts: 1}
+	traders := []model.TraderVolume{{Account: "user1", TotalUSD: model.NewDecimalFromFloat(100.5), SwapCount: 2}}
+
+	mockRepo.EXPECT().GetPoolVolumeStats(ctx, pool, gomock.Any()).Return(volume, nil).Times(3)
+	mockRepo.EXPECT().GetPoolTopTraders(ctx, pool, gomock.Any(), 10).Return(traders, nil)
+
+	stats, err := svc.GetPoolStats(ctx, pool, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, pool, stats.Pool)
+	assert.Len(t, stats.Windows, 3)
+	assert.Equal(t, *volume, stats.Windows["24h"])
+	assert.Equal(t, *volume, stats.Windows["7d"])
+	assert.Equal(t, *volume, stats.Windows["30d"])
+	assert.Equal(t, traders, stats.TopTraders)
+}
+
+// TestGetPoolStats_Failure tests the scenario where retrieving pool statistics fails.
+func TestGetPoolStats_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	expectedError := errors.New("repository error")
+
+	mockRepo.EXPECT().GetPoolVolumeStats(ctx, "poolABC", gomock.Any()).Return(nil, expectedError)
+
+	stats, err := svc.GetPoolStats(ctx, "poolABC", 10)
+
+	assert.Error(t, err)
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, stats)
+}
+
+// TestExplorerURLs tests building explorer links for known and unknown networks.
+func TestExplorerURLs(t *testing.T) {
+	assert.Equal(t, "https://basescan.org/tx/0xabc", service.ExplorerTxURL("base", "0xabc"))
+	assert.Equal(t, "https://arbiscan.io/address/0xdef", service.ExplorerAddressURL("arbitrum", "0xdef"))
+	assert.Empty(t, service.ExplorerTxURL("unknown", "0xabc"))
+	assert.Empty(t, service.ExplorerAddressURL("mainnet", ""))
+
+	service.RegisterChain(service.Chain{Network: "devnet", ChainID: 1337, ExplorerURL: "http://localhost:4000/"})
+	assert.Equal(t, "http://localhost:4000/tx/0xabc", service.ExplorerTxURL("devnet", "0xabc"))
+
+	chain, exists := service.GetChain("devnet")
+	assert.True(t, exists)
+	assert.Equal(t, int64(1337), chain.ChainID)
+}
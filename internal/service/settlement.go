@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/config"
+	"hw/pkg/ethindexa/utils"
+	"hw/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Transactor sends the transactions settling distributions, implemented by transactor.Transactor.
+type Transactor interface {
+	// From returns the account sending the transactions.
+	From() common.Address
+	// Send signs and sends a transaction calling to with data and value.
+	Send(ctx context.Context, to common.Address, data []byte, value *big.Int) (*types.Transaction, error)
+	// Replace sends a transaction with the nonce of the transaction previous, calling to with data
+	// and value, with fees high enough to replace it, or returns nil when previous is mined.
+	Replace(ctx context.Context, previous common.Hash, nonce uint64, to common.Address, data []byte, value *big.Int) (*types.Transaction, error)
+	// Receipt returns the receipt of a transaction, or nil while it is not mined.
+	Receipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+}
+
+// WithSettlement configures the MerkleDistributor that distributions are settled with and the
+// transactor sending the settlement transactions.
+func WithSettlement(cfg config.Settlement, transactor Transactor) Option {
+	return func(s *service) {
+		s.settlement = cfg
+		s.transactor = transactor
+	}
+}
+
+// FundDistribution transfers the token total of a distribution from the settlement account to
+// the MerkleDistributor, and records the transaction as pending.
+func (s *service) FundDistribution(ctx context.Context, distributionID int) (*model.SettlementTx, error) {
+	return s.settle(ctx, distributionID, model.SettlementFund)
+}
+
+// UpdateDistributionRoot sets the root of the MerkleDistributor to the root of a distribution,
+// and records the transaction as pending.
+func (s *service) UpdateDistributionRoot(ctx context.Context, distributionID int) (*model.SettlementTx, error) {
+	return s.settle(ctx, distributionID, model.SettlementUpdateRoot)
+}
+
+// settlementCall returns the contract called by the transaction of an action for a distribution,
+// and its call data.
+func (s *service) settlementCall(action string, distribution *model.MerkleDistribution) (common.Address, []byte, error) {
+	switch action {
+	case model.SettlementFund:
+		if s.settlement.Token == "" {
+			return common.Address{}, nil, errors.New("no settlement token configured")
+		}
+		amount, ok := new(big.Int).SetString(distribution.TokenTotal, 10)
+		if !ok {
+			return common.Address{}, nil, fmt.Errorf("invalid token total %q", distribution.TokenTotal)
+		}
+		erc20, err := utils.LoadABI("erc20_usdc")
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+		data, err := erc20.Pack("transfer", common.HexToAddress(s.settlement.Distributor), amount)
+		return common.HexToAddress(s.settlement.Token), data, err
+	case model.SettlementUpdateRoot:
+		distributor, err := utils.LoadABI("merkleDistributor")
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+		data, err := distributor.Pack("setMerkleRoot", common.HexToHash(distribution.Root))
+		return common.HexToAddress(s.settlement.Distributor), data, err
+	default:
+		return common.Address{}, nil, fmt.Errorf("unknown settlement action %q", action)
+	}
+}
+
+// settle sends the transaction of an action for a distribution, unless the distribution has a
+// transaction of the action that is not failed or replaced. The transaction is reserved before
+// it is sent, so concurrent or repeated settlements of the action conflict on it instead of
+// sending the action twice.
+func (s *service) settle(ctx context.Context, distributionID int, action string) (*model.SettlementTx, error) {
+	if s.transactor == nil {
+		return nil, errors.New("no settlement transactor configured")
+	}
+	if s.settlement.Distributor == "" {
+		return nil, errors.New("no settlement distributor configured")
+	}
+
+	distribution, err := s.repo.GetMerkleDistribution(ctx, distributionID)
+	if err != nil {
+		return nil, err
+	}
+	to, data, err := s.settlementCall(action, distribution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %w", action, err)
+	}
+
+	tx := &model.SettlementTx{
+		DistributionID: distributionID,
+		Action:         action,
+		Network:        s.settlement.Network,
+		Sender:         strings.ToLower(s.transactor.From().Hex()),
+		Status:         model.SettlementSending,
+	}
+	if err := s.repo.ReserveSettlementTx(ctx, tx); err != nil {
+		if errors.Is(err, model.ErrAlreadyExists) {
+			return nil, fmt.Errorf("%w: %s of distribution %d", model.ErrAlreadySettled, action, distributionID)
+		}
+		return nil, err
+	}
+
+	sent, err := s.transactor.Send(ctx, to, data, nil)
+	if err != nil {
+		// The transaction may have reached the network anyway, so the action stays reserved until
+		// the account's transactions are checked by hand
+		if err := s.repo.UpdateSettlementTx(ctx, tx.ID, model.SettlementReconcile, 0, 0); err != nil {
+			logger.Errorf("Failed to mark %s transaction %d of distribution %d to reconcile: %v", action, tx.ID, distributionID, err)
+		}
+		return nil, err
+	}
+
+	tx.Hash, tx.Nonce, tx.Status = sent.Hash().Hex(), sent.Nonce(), model.SettlementPending
+	if err := s.repo.RecordSentSettlementTx(ctx, tx); err != nil {
+		// The transaction is sent anyway, so it is logged to be tracked by hand
+		logger.Errorf("Failed to record %s transaction %s of distribution %d: %v", action, tx.Hash, distributionID, err)
+		return nil, err
+	}
+	logger.Infof("Sent %s transaction %s of distribution %d with nonce %d", action, tx.Hash, distributionID, tx.Nonce)
+
+	return tx, nil
+}
+
+// newSettlementTx returns the pending record of a sent transaction.
+func (s *service) newSettlementTx(distributionID int, action string, sent *types.Transaction) *model.SettlementTx {
+	return &model.SettlementTx{
+		DistributionID: distributionID,
+		Action:         action,
+		Network:        s.settlement.Network,
+		Hash:           sent.Hash().Hex(),
+		Sender:         strings.ToLower(s.transactor.From().Hex()),
+		Nonce:          sent.Nonce(),
+		Status:         model.SettlementPending,
+	}
+}
+
+// ReplaceStuckSettlements speeds up the settlement transactions pending for longer than the
+// configured ReplaceAfter at now: each is replaced by a transaction with the same nonce and call
+// and higher fees, recorded as pending while it is recorded as replaced. It returns the
+// replacements. Transactions mined in the meantime are left to TrackSettlements.
+func (s *service) ReplaceStuckSettlements(ctx context.Context, now time.Time) ([]model.SettlementTx, error) {
+	if s.transactor == nil {
+		return nil, errors.New("no settlement transactor configured")
+	}
+	if s.settlement.ReplaceAfter <= 0 {
+		return nil, nil
+	}
+
+	pending, err := s.repo.GetPendingSettlementTxs(ctx, s.settlement.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	var replacements []model.SettlementTx
+	for _, tx := range pending {
+		if now.Sub(tx.CreatedAt) < s.settlement.ReplaceAfter {
+			continue
+		}
+
+		distribution, err := s.repo.GetMerkleDistribution(ctx, tx.DistributionID)
+		if err != nil {
+			return replacements, err
+		}
+		to, data, err := s.settlementCall(tx.Action, distribution)
+		if err != nil {
+			return replacements, fmt.Errorf("failed to pack %s call: %w", tx.Action, err)
+		}
+		sent, err := s.transactor.Replace(ctx, common.HexToHash(tx.Hash), tx.Nonce, to, data, nil)
+		if err != nil {
+			return replacements, fmt.Errorf("failed to replace %s transaction %s of distribution %d: %w", tx.Action, tx.Hash, tx.DistributionID, err)
+		}
+		if sent == nil {
+			continue
+		}
+
+		replacement := s.newSettlementTx(tx.DistributionID, tx.Action, sent)
+		if err := s.repo.ReplaceSettlementTx(ctx, tx.ID, replacement); err != nil {
+			logger.Errorf("Failed to record replacement %s of %s transaction %s of distribution %d: %v", replacement.Hash, tx.Action, tx.Hash, tx.DistributionID, err)
+			return replacements, err
+		}
+		logger.Infof("Replaced %s transaction %s of distribution %d, pending since %s, with %s", tx.Action, tx.Hash, tx.DistributionID, tx.CreatedAt.Format(time.RFC3339), replacement.Hash)
+		replacements = append(replacements, *replacement)
+	}
+
+	return replacements, nil
+}
+
+// TrackSettlements records the receipts of the pending settlement transactions, as confirmed or
+// failed, and returns the transactions that got one. Transactions not mined yet stay pending,
+// unless a transaction they replaced was mined instead, which is recorded in their place.
+func (s *service) TrackSettlements(ctx context.Context) ([]model.SettlementTx, error) {
+	if s.transactor == nil {
+		return nil, errors.New("no settlement transactor configured")
+	}
+
+	pending, err := s.repo.GetPendingSettlementTxs(ctx, s.settlement.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	var mined []model.SettlementTx
+	for _, tx := range pending {
+		receipt, err := s.transactor.Receipt(ctx, common.HexToHash(tx.Hash))
+		if err != nil {
+			return mined, err
+		}
+		if receipt == nil && tx.Replaces != nil {
+			replaced, err := s.minedReplacedSettlementTx(ctx, tx)
+			if err != nil {
+				return mined, err
+			}
+			if replaced != nil {
+				// The replaced transaction leaves the unique index of pending transactions first
+				if err := s.repo.UpdateSettlementTx(ctx, tx.ID, model.SettlementReplaced, 0, 0); err != nil {
+					return mined, err
+				}
+				tx, receipt = replaced.tx, replaced.receipt
+			}
+		}
+		if receipt == nil {
+			continue
+		}
+
+		tx.Status = model.SettlementConfirmed
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			tx.Status = model.SettlementFailed
+		}
+		blockNumber, gasUsed := receipt.BlockNumber.Int64(), int64(receipt.GasUsed)
+		if err := s.repo.UpdateSettlementTx(ctx, tx.ID, tx.Status, blockNumber, gasUsed); err != nil {
+			return mined, err
+		}
+		tx.BlockNumber, tx.GasUsed = &blockNumber, &gasUsed
+		logger.Infof("Settlement transaction %s of distribution %d %s in block %d", tx.Hash, tx.DistributionID, tx.Status, blockNumber)
+		mined = append(mined, tx)
+	}
+
+	return mined, nil
+}
+
+// minedSettlementTx is a settlement transaction with its receipt.
+type minedSettlementTx struct {
+	tx      model.SettlementTx
+	receipt *types.Receipt
+}
+
+// minedReplacedSettlementTx returns the transaction replaced by a pending replacement that was
+// mined instead of it, or nil.
+func (s *service) minedReplacedSettlementTx(ctx context.Context, replacement model.SettlementTx) (*minedSettlementTx, error) {
+	txs, err := s.repo.GetSettlementTxs(ctx, replacement.DistributionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		if tx.Status != model.SettlementReplaced || tx.Action != replacement.Action || tx.Nonce != replacement.Nonce {
+			continue
+		}
+		receipt, err := s.transactor.Receipt(ctx, common.HexToHash(tx.Hash))
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return &minedSettlementTx{tx: tx, receipt: receipt}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,280 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeTransactor records the calls it sends, numbered from nonce, and returns the receipts of
+// receipts. With sendErr it sends nothing and returns it instead.
+type fakeTransactor struct {
+	nonce    uint64
+	sent     []*types.Transaction
+	replaced []common.Hash
+	receipts map[common.Hash]*types.Receipt
+	sendErr  error
+}
+
+func (f *fakeTransactor) From() common.Address {
+	return common.HexToAddress("0x00000000000000000000000000000000000000f1")
+}
+
+func (f *fakeTransactor) Send(_ context.Context, to common.Address, data []byte, value *big.Int) (*types.Transaction, error) {
+	if f.sendErr != nil {
+		return nil, f.sendErr
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: f.nonce, To: &to, Data: data, Value: value})
+	f.nonce++
+	f.sent = append(f.sent, tx)
+	return tx, nil
+}
+
+func (f *fakeTransactor) Replace(_ context.Context, previous common.Hash, nonce uint64, to common.Address, data []byte, value *big.Int) (*types.Transaction, error) {
+	if _, ok := f.receipts[previous]; ok {
+		return nil, nil
+	}
+	// A higher tip than Send's keeps the hash of a replacement apart
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: nonce, To: &to, Data: data, Value: value, GasTipCap: big.NewInt(int64(len(f.replaced) + 1))})
+	f.replaced = append(f.replaced, previous)
+	f.sent = append(f.sent, tx)
+	return tx, nil
+}
+
+func (f *fakeTransactor) Receipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	return f.receipts[hash], nil
+}
+
+// settlementConfig returns a settlement configuration with a distributor and a token.
+func settlementConfig() config.Settlement {
+	cfg := config.Default().Settlement
+	cfg.Distributor = "0x00000000000000000000000000000000000000d1"
+	cfg.Token = "0x00000000000000000000000000000000000000e1"
+	return cfg
+}
+
+// TestFundDistribution tests that the token total of a distribution is transferred to the
+// distributor once its transaction is reserved, and the transaction recorded as pending.
+func TestFundDistribution(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	transactor := &fakeTransactor{nonce: 4}
+	svc := service.NewService(mockRepo, service.WithSettlement(settlementConfig(), transactor))
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetMerkleDistribution(ctx, 3).Return(&model.MerkleDistribution{ID: 3, Root: "0x" + common.Bytes2Hex(make([]byte, 32)), TokenTotal: "1500"}, nil)
+	mockRepo.EXPECT().ReserveSettlementTx(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, tx *model.SettlementTx) error {
+		assert.Equal(t, model.SettlementSending, tx.Status)
+		assert.Empty(t, transactor.sent, "reserved before it is sent")
+		tx.ID = 9
+		return nil
+	})
+	mockRepo.EXPECT().RecordSentSettlementTx(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, tx *model.SettlementTx) error {
+		assert.Equal(t, 9, tx.ID)
+		assert.Equal(t, model.SettlementPending, tx.Status)
+		return nil
+	})
+
+	tx, err := svc.FundDistribution(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, tx.ID)
+	assert.Equal(t, model.SettlementFund, tx.Action)
+	assert.Equal(t, model.SettlementPending, tx.Status)
+	assert.Equal(t, "mainnet", tx.Network)
+	assert.Equal(t, uint64(4), tx.Nonce)
+	assert.Equal(t, "0x00000000000000000000000000000000000000f1", tx.Sender)
+
+	if assert.Len(t, transactor.sent, 1) {
+		sent := transactor.sent[0]
+		assert.Equal(t, sent.Hash().Hex(), tx.Hash)
+		assert.Equal(t, common.HexToAddress("0xe1"), *sent.To())
+		// transfer(address,uint256)
+		assert.Equal(t, "a9059cbb", common.Bytes2Hex(sent.Data()[:4]))
+		assert.Equal(t, common.HexToAddress("0xd1").Bytes(), sent.Data()[16:36])
+		assert.Equal(t, big.NewInt(1500), new(big.Int).SetBytes(sent.Data()[36:68]))
+	}
+}
+
+// TestUpdateDistributionRoot tests that the root of a distribution is set on the distributor
+// once: a settlement conflicting with the reserved transaction of the action sends nothing.
+func TestUpdateDistributionRoot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	transactor := &fakeTransactor{}
+	svc := service.NewService(mockRepo, service.WithSettlement(settlementConfig(), transactor))
+	ctx := context.Background()
+
+	root := "0x1111111111111111111111111111111111111111111111111111111111111111"
+	mockRepo.EXPECT().GetMerkleDistribution(ctx, 3).Return(&model.MerkleDistribution{ID: 3, Root: root, TokenTotal: "1500"}, nil).Times(2)
+	gomock.InOrder(
+		mockRepo.EXPECT().ReserveSettlementTx(ctx, gomock.Any()).Return(nil),
+		mockRepo.EXPECT().ReserveSettlementTx(ctx, gomock.Any()).Return(fmt.Errorf("failed to reserve settlement transaction: %w", model.ErrAlreadyExists)),
+	)
+	mockRepo.EXPECT().RecordSentSettlementTx(ctx, gomock.Any()).Return(nil)
+
+	tx, err := svc.UpdateDistributionRoot(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, model.SettlementUpdateRoot, tx.Action)
+	if assert.Len(t, transactor.sent, 1) {
+		assert.Equal(t, common.HexToAddress("0xd1"), *transactor.sent[0].To())
+		assert.Equal(t, common.HexToHash(root).Bytes(), transactor.sent[0].Data()[4:])
+	}
+
+	_, err = svc.UpdateDistributionRoot(ctx, 3)
+	assert.ErrorIs(t, err, model.ErrAlreadySettled)
+	assert.Len(t, transactor.sent, 1)
+}
+
+// TestSettlement_SendError tests that a transaction that could not be sent keeps its action
+// reserved, marked to reconcile, as it may have reached the network anyway.
+func TestSettlement_SendError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	transactor := &fakeTransactor{sendErr: errors.New("connection reset")}
+	svc := service.NewService(mockRepo, service.WithSettlement(settlementConfig(), transactor))
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetMerkleDistribution(ctx, 3).Return(&model.MerkleDistribution{ID: 3, TokenTotal: "1500"}, nil)
+	mockRepo.EXPECT().ReserveSettlementTx(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, tx *model.SettlementTx) error {
+		tx.ID = 9
+		return nil
+	})
+	mockRepo.EXPECT().UpdateSettlementTx(ctx, 9, model.SettlementReconcile, int64(0), int64(0)).Return(nil)
+
+	_, err := svc.FundDistribution(ctx, 3)
+	assert.ErrorContains(t, err, "connection reset")
+}
+
+// TestSettlement_NotConfigured tests that settling needs a transactor.
+func TestSettlement_NotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := service.NewService(repositoryMock.NewMockRepository(ctrl))
+
+	_, err := svc.UpdateDistributionRoot(context.Background(), 1)
+	assert.Error(t, err)
+	_, err = svc.TrackSettlements(context.Background())
+	assert.Error(t, err)
+}
+
+// TestTrackSettlements tests that mined transactions are confirmed or failed by their receipt
+// and the others stay pending.
+func TestTrackSettlements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	confirmed, failed, pending := common.Hash{1}, common.Hash{2}, common.Hash{3}
+	transactor := &fakeTransactor{receipts: map[common.Hash]*types.Receipt{
+		confirmed: {Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100), GasUsed: 52000},
+		failed:    {Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(101), GasUsed: 30000},
+	}}
+	svc := service.NewService(mockRepo, service.WithSettlement(settlementConfig(), transactor))
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetPendingSettlementTxs(ctx, "mainnet").Return([]model.SettlementTx{
+		{ID: 1, Hash: confirmed.Hex(), Status: model.SettlementPending},
+		{ID: 2, Hash: failed.Hex(), Status: model.SettlementPending},
+		{ID: 3, Hash: pending.Hex(), Status: model.SettlementPending},
+	}, nil)
+	mockRepo.EXPECT().UpdateSettlementTx(ctx, 1, model.SettlementConfirmed, int64(100), int64(52000)).Return(nil)
+	mockRepo.EXPECT().UpdateSettlementTx(ctx, 2, model.SettlementFailed, int64(101), int64(30000)).Return(nil)
+
+	mined, err := svc.TrackSettlements(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, mined, 2) {
+		assert.Equal(t, model.SettlementConfirmed, mined[0].Status)
+		assert.Equal(t, int64(100), *mined[0].BlockNumber)
+		assert.Equal(t, model.SettlementFailed, mined[1].Status)
+	}
+}
+
+// TestReplaceStuckSettlements tests that transactions pending for longer than ReplaceAfter are
+// replaced with the same nonce and call, unless they were mined meanwhile.
+func TestReplaceStuckSettlements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mined := common.Hash{2}
+	transactor := &fakeTransactor{receipts: map[common.Hash]*types.Receipt{mined: {Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100)}}}
+	cfg := settlementConfig()
+	cfg.ReplaceAfter = 3 * time.Minute
+	svc := service.NewService(mockRepo, service.WithSettlement(cfg, transactor))
+	ctx := context.Background()
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	stuck := common.Hash{1}
+	root := "0x1111111111111111111111111111111111111111111111111111111111111111"
+	mockRepo.EXPECT().GetPendingSettlementTxs(ctx, "mainnet").Return([]model.SettlementTx{
+		{ID: 1, DistributionID: 3, Action: model.SettlementUpdateRoot, Hash: stuck.Hex(), Nonce: 5, Status: model.SettlementPending, CreatedAt: now.Add(-5 * time.Minute)},
+		{ID: 2, DistributionID: 3, Action: model.SettlementFund, Hash: mined.Hex(), Nonce: 4, Status: model.SettlementPending, CreatedAt: now.Add(-5 * time.Minute)},
+		{ID: 3, DistributionID: 4, Action: model.SettlementFund, Hash: common.Hash{3}.Hex(), Nonce: 6, Status: model.SettlementPending, CreatedAt: now.Add(-time.Minute)},
+	}, nil)
+	mockRepo.EXPECT().GetMerkleDistribution(ctx, 3).Return(&model.MerkleDistribution{ID: 3, Root: root, TokenTotal: "1500"}, nil).Times(2)
+	mockRepo.EXPECT().ReplaceSettlementTx(ctx, 1, gomock.Any()).DoAndReturn(func(_ context.Context, replaced int, tx *model.SettlementTx) error {
+		tx.ID, tx.Replaces = 4, &replaced
+		return nil
+	})
+
+	replacements, err := svc.ReplaceStuckSettlements(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Hash{stuck}, transactor.replaced)
+	if assert.Len(t, replacements, 1) {
+		assert.Equal(t, 4, replacements[0].ID)
+		assert.Equal(t, 1, *replacements[0].Replaces)
+		assert.Equal(t, uint64(5), replacements[0].Nonce)
+		assert.Equal(t, model.SettlementPending, replacements[0].Status)
+		assert.Equal(t, transactor.sent[0].Hash().Hex(), replacements[0].Hash)
+		assert.Equal(t, common.HexToHash(root).Bytes(), transactor.sent[0].Data()[4:])
+	}
+
+	// Without ReplaceAfter nothing is replaced
+	cfg.ReplaceAfter = 0
+	svc = service.NewService(mockRepo, service.WithSettlement(cfg, transactor))
+	replacements, err = svc.ReplaceStuckSettlements(ctx, now)
+	assert.NoError(t, err)
+	assert.Empty(t, replacements)
+}
+
+// TestTrackSettlements_Replaced tests that a transaction mined instead of its replacement is
+// recorded with its receipt, and the replacement as replaced.
+func TestTrackSettlements_Replaced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	original, replacement := common.Hash{1}, common.Hash{2}
+	transactor := &fakeTransactor{receipts: map[common.Hash]*types.Receipt{
+		original: {Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100), GasUsed: 45000},
+	}}
+	svc := service.NewService(mockRepo, service.WithSettlement(settlementConfig(), transactor))
+	ctx := context.Background()
+
+	replaces := 1
+	mockRepo.EXPECT().GetPendingSettlementTxs(ctx, "mainnet").Return([]model.SettlementTx{
+		{ID: 2, DistributionID: 3, Action: model.SettlementUpdateRoot, Hash: replacement.Hex(), Nonce: 5, Status: model.SettlementPending, Replaces: &replaces},
+	}, nil)
+	mockRepo.EXPECT().GetSettlementTxs(ctx, 3).Return([]model.SettlementTx{
+		{ID: 1, DistributionID: 3, Action: model.SettlementUpdateRoot, Hash: original.Hex(), Nonce: 5, Status: model.SettlementReplaced},
+		{ID: 2, DistributionID: 3, Action: model.SettlementUpdateRoot, Hash: replacement.Hex(), Nonce: 5, Status: model.SettlementPending, Replaces: &replaces},
+	}, nil)
+	gomock.InOrder(
+		mockRepo.EXPECT().UpdateSettlementTx(ctx, 2, model.SettlementReplaced, int64(0), int64(0)).Return(nil),
+		mockRepo.EXPECT().UpdateSettlementTx(ctx, 1, model.SettlementConfirmed, int64(100), int64(45000)).Return(nil),
+	)
+
+	mined, err := svc.TrackSettlements(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, mined, 1) {
+		assert.Equal(t, 1, mined[0].ID)
+		assert.Equal(t, original.Hex(), mined[0].Hash)
+		assert.Equal(t, model.SettlementConfirmed, mined[0].Status)
+	}
+}
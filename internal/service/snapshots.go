@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"hw/internal/model"
+	"hw/pkg/ethindexa/utils"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+)
+
+// BalanceFetcher reads the token balances of holders at a block from the chain, keyed by lowercase address.
+type BalanceFetcher func(ctx context.Context, client *ethclient.Client, token string, holders []string, blockNumber int64) (map[string]*big.Int, error)
+
+// WithBalanceFetcher replaces the on-chain balanceOf lookup of balance snapshots.
+func WithBalanceFetcher(fetch BalanceFetcher) Option {
+	return func(s *service) {
+		s.fetchBalances = fetch
+	}
+}
+
+// defaultBalanceFetcher reads balances with batched balanceOf calls to the token's ERC-20 contract.
+var defaultBalanceFetcher BalanceFetcher = utils.BalancesOf
+
+// TakeBalanceSnapshot computes the balance of every holder of a token at a block and stores it as
+// a snapshot. Without holders the balances are summed from the indexed transfers of the token,
+// which must be indexed as positions; with holders they are read from the chain with balanceOf at
+// the block, through client. Holders without a balance are left out. Balances are returned largest first.
+func (s *service) TakeBalanceSnapshot(ctx context.Context, client *ethclient.Client, network, token string, blockNumber int64, holders []string) (*model.BalanceSnapshot, []model.HolderBalance, error) {
+	if blockNumber <= 0 {
+		return nil, nil, model.NewError(model.ErrInvalid, "snapshot block must be positive")
+	}
+
+	snapshot := &model.BalanceSnapshot{Network: network, Token: token, BlockNumber: blockNumber}
+	var balances []model.HolderBalance
+	if len(holders) == 0 {
+		snapshot.Source = model.SnapshotSourceTransfers
+		var err error
+		if balances, err = s.repo.GetTransferBalances(ctx, token, network, blockNumber); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		if client == nil {
+			return nil, nil, model.NewError(model.ErrInvalid, "a client is required to read the balances of holders")
+		}
+		snapshot.Source = model.SnapshotSourceBalanceOf
+		onChain, err := s.fetchBalances(ctx, client, token, holders, blockNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		for account, balance := range onChain {
+			if balance.Sign() > 0 {
+				balances = append(balances, model.HolderBalance{Account: account, Balance: model.NewDecimal(decimal.NewFromBigInt(balance, 0))})
+			}
+		}
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		if cmp := balances[i].Balance.Cmp(balances[j].Balance.Decimal); cmp != 0 {
+			return cmp > 0
+		}
+		return balances[i].Account < balances[j].Account
+	})
+	total := decimal.Zero
+	for _, balance := range balances {
+		total = total.Add(balance.Balance.Decimal)
+	}
+	snapshot.Holders = len(balances)
+	snapshot.Total = model.NewDecimal(total)
+
+	if err := s.repo.CreateBalanceSnapshot(ctx, snapshot, balances); err != nil {
+		return nil, nil, err
+	}
+	return snapshot, balances, nil
+}
+
+// GetBalanceSnapshot retrieves the latest snapshot of a token taken at a block, with the balance of
+// every holder, largest first.
+func (s *service) GetBalanceSnapshot(ctx context.Context, network, token string, blockNumber int64) (*model.BalanceSnapshot, []model.HolderBalance, error) {
+	return s.repo.GetLatestBalanceSnapshot(ctx, token, network, blockNumber)
+}
@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestTakeBalanceSnapshot_Transfers tests that without holders the balances of the indexed transfers are stored, largest first.
+func TestTakeBalanceSnapshot_Transfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetTransferBalances(ctx, "0xtoken", "mainnet", int64(100)).Return([]model.HolderBalance{
+		{Account: "0xa", Balance: model.NewDecimalFromFloat(300)},
+		{Account: "0xb", Balance: model.NewDecimalFromFloat(500)},
+	}, nil)
+	mockRepo.EXPECT().CreateBalanceSnapshot(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, snapshot *model.BalanceSnapshot, balances []model.HolderBalance) error {
+		assert.Equal(t, model.SnapshotSourceTransfers, snapshot.Source)
+		assert.Equal(t, 2, snapshot.Holders)
+		assert.True(t, snapshot.Total.Equal(model.NewDecimalFromFloat(800).Decimal))
+		snapshot.ID = 1
+		return nil
+	})
+
+	snapshot, balances, err := svc.TakeBalanceSnapshot(ctx, nil, "mainnet", "0xtoken", 100, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, snapshot.ID)
+	if assert.Len(t, balances, 2) {
+		assert.Equal(t, "0xb", balances[0].Account)
+		assert.Equal(t, "0xa", balances[1].Account)
+	}
+}
+
+// TestTakeBalanceSnapshot_BalanceOf tests that the balances of the given holders are read from the chain
+// at the block, leaving out holders without a balance.
+func TestTakeBalanceSnapshot_BalanceOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	fetch := func(_ context.Context, _ *ethclient.Client, token string, holders []string, blockNumber int64) (map[string]*big.Int, error) {
+		assert.Equal(t, "0xtoken", token)
+		assert.Equal(t, []string{"0xa", "0xb"}, holders)
+		assert.Equal(t, int64(100), blockNumber)
+		return map[string]*big.Int{"0xa": big.NewInt(0), "0xb": big.NewInt(42)}, nil
+	}
+	svc := service.NewService(mockRepo, service.WithBalanceFetcher(fetch))
+	ctx := context.Background()
+
+	mockRepo.EXPECT().CreateBalanceSnapshot(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, snapshot *model.BalanceSnapshot, balances []model.HolderBalance) error {
+		assert.Equal(t, model.SnapshotSourceBalanceOf, snapshot.Source)
+		assert.Equal(t, 1, snapshot.Holders)
+		return nil
+	})
+
+	_, balances, err := svc.TakeBalanceSnapshot(ctx, &ethclient.Client{}, "mainnet", "0xtoken", 100, []string{"0xa", "0xb"})
+
+	assert.NoError(t, err)
+	if assert.Len(t, balances, 1) {
+		assert.Equal(t, "0xb", balances[0].Account)
+		assert.True(t, balances[0].Balance.Equal(model.NewDecimalFromFloat(42).Decimal))
+	}
+
+	_, _, err = svc.TakeBalanceSnapshot(ctx, nil, "mainnet", "0xtoken", 100, []string{"0xa"})
+	assert.ErrorIs(t, err, model.ErrInvalid)
+
+	_, _, err = svc.TakeBalanceSnapshot(ctx, nil, "mainnet", "0xtoken", 0, nil)
+	assert.ErrorIs(t, err, model.ErrInvalid)
+}
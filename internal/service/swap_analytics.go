@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/clickhouse"
+	"hw/pkg/config"
+	"hw/pkg/logger"
+)
+
+// SwapAnalytics keeps a copy of the swaps in an analytics database serving volume aggregations
+// over ranges too large for Postgres.
+type SwapAnalytics interface {
+	// RecordSwap copies a swap recorded in Postgres.
+	RecordSwap(ctx context.Context, swap *model.SwapHistory) error
+	// GetVolume aggregates the swaps on a network within [from, to) into buckets of the given interval,
+	// of a single pool unless pool is empty.
+	GetVolume(ctx context.Context, network, pool string, from, to time.Time, interval time.Duration) ([]model.VolumeBucket, error)
+	// GetVolumeLeaderboard retrieves the accounts that swapped the most USD on a network within [from, to).
+	GetVolumeLeaderboard(ctx context.Context, network string, from, to time.Time, limit int) ([]model.TraderVolume, error)
+}
+
+// WithSwapAnalytics copies every recorded swap to analytics and serves the analytics endpoints from it.
+func WithSwapAnalytics(analytics SwapAnalytics) Option {
+	return func(s *service) {
+		s.analytics = analytics
+	}
+}
+
+// recordSwapAnalytics copies a recorded swap to the analytics database, if any. Failures are only
+// logged: Postgres stays the source of truth.
+func (s *service) recordSwapAnalytics(ctx context.Context, history *model.SwapHistory) {
+	if s.analytics == nil {
+		return
+	}
+	if err := s.analytics.RecordSwap(ctx, history); err != nil {
+		logger.Warnf("Failed to copy swap %d to analytics: %v", history.ID, err)
+	}
+}
+
+// GetSwapVolume aggregates the swaps on a network within [from, to) into buckets of the given
+// interval, of a single pool unless pool is empty.
+func (s *service) GetSwapVolume(ctx context.Context, network, pool string, from, to time.Time, interval time.Duration) ([]model.VolumeBucket, error) {
+	if s.analytics == nil {
+		return nil, model.ErrAnalyticsUnavailable
+	}
+	return s.analytics.GetVolume(ctx, network, pool, from, to, interval)
+}
+
+// GetVolumeLeaderboard retrieves the accounts that swapped the most USD on a network within [from, to).
+func (s *service) GetVolumeLeaderboard(ctx context.Context, network string, from, to time.Time, limit int) ([]model.TraderVolume, error) {
+	if s.analytics == nil {
+		return nil, model.ErrAnalyticsUnavailable
+	}
+	return s.analytics.GetVolumeLeaderboard(ctx, network, from, to, limit)
+}
+
+// clickHouseSwapTable is the ClickHouse copy of swap_history. The swaps of a batch inserted again
+// after a timeout are merged away by id in the background; queries do not wait for it, as reading
+// with FINAL would not scale.
+const clickHouseSwapTable = `
+	CREATE TABLE IF NOT EXISTS swap_history (
+		id                UInt64,
+		network           LowCardinality(String),
+		token             String,
+		account           String,
+		transaction_hash  String,
+		usd_value         Decimal(24, 6),
+		counted_usd_value Decimal(24, 6),
+		last_updated      DateTime64(3, 'UTC'),
+		created_at        DateTime64(3, 'UTC')
+	)
+	ENGINE = ReplacingMergeTree
+	PARTITION BY toYYYYMM(last_updated)
+	ORDER BY (network, token, last_updated, id)`
+
+// clickHouseSwap is a row of the ClickHouse swap_history table.
+type clickHouseSwap struct {
+	ID              int           `json:"id"`
+	Network         string        `json:"network"`
+	Token           string        `json:"token"`
+	Account         string        `json:"account"`
+	TransactionHash string        `json:"transaction_hash"`
+	UsdValue        model.Decimal `json:"usd_value"`
+	CountedUsdValue model.Decimal `json:"counted_usd_value"`
+	LastUpdated     time.Time     `json:"last_updated"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+// ClickHouseSwapAnalytics is a SwapAnalytics on ClickHouse. Swaps are buffered and inserted in
+// batches by Run, as ClickHouse favours few large inserts; the batches failing to insert are kept
+// and retried, up to a maximum number of buffered swaps past which the oldest are dropped.
+type ClickHouseSwapAnalytics struct {
+	client        *clickhouse.Client
+	batchSize     int
+	flushInterval time.Duration
+	maxBuffered   int
+
+	mutex   sync.Mutex
+	flushes sync.Mutex // serializes Flush
+	buffer  []interface{}
+	dropped int           // swaps dropped from the front of buffer so far
+	full    chan struct{} // signalled when a batch is ready
+}
+
+// NewClickHouseSwapAnalytics creates a ClickHouseSwapAnalytics on the configured database.
+func NewClickHouseSwapAnalytics(cfg config.ClickHouse) *ClickHouseSwapAnalytics {
+	return &ClickHouseSwapAnalytics{
+		client:        clickhouse.New(cfg),
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		maxBuffered:   cfg.MaxBuffered,
+		full:          make(chan struct{}, 1),
+	}
+}
+
+// EnsureSchema creates the swap_history table unless it exists.
+func (a *ClickHouseSwapAnalytics) EnsureSchema(ctx context.Context) error {
+	if err := a.client.Exec(ctx, clickHouseSwapTable); err != nil {
+		return fmt.Errorf("failed to create ClickHouse swap_history table: %w", err)
+	}
+	return nil
+}
+
+// RecordSwap buffers a swap until the next batch is inserted.
+func (a *ClickHouseSwapAnalytics) RecordSwap(ctx context.Context, swap *model.SwapHistory) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.buffer = append(a.buffer, clickHouseSwap{
+		ID:              swap.ID,
+		Network:         swap.Network,
+		Token:           swap.Token,
+		Account:         swap.Account,
+		TransactionHash: swap.TransactionHash,
+		UsdValue:        swap.UsdValue,
+		CountedUsdValue: swap.CountedUsdValue,
+		LastUpdated:     swap.LastUpdated,
+		CreatedAt:       swap.CreatedAt,
+	})
+	if dropped := len(a.buffer) - a.maxBuffered; dropped > 0 {
+		logger.Warnf("Dropping the %d oldest swaps buffered for ClickHouse", dropped)
+		a.buffer = a.buffer[dropped:]
+		a.dropped += dropped
+	}
+	if len(a.buffer) >= a.batchSize {
+		select {
+		case a.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Run inserts the buffered swaps every flush interval, or as soon as a batch is full, until ctx is
+// done, then inserts the swaps left.
+func (a *ClickHouseSwapAnalytics) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return a.Flush(context.Background())
+		case <-ticker.C:
+		case <-a.full:
+		}
+		if err := a.Flush(ctx); err != nil && ctx.Err() == nil {
+			logger.Warnf("Failed to insert swaps into ClickHouse, retrying in %s: %v", a.flushInterval, err)
+		}
+	}
+}
+
+// Flush inserts the buffered swaps in batches. The swaps of a failed batch stay buffered.
+func (a *ClickHouseSwapAnalytics) Flush(ctx context.Context) error {
+	a.flushes.Lock()
+	defer a.flushes.Unlock()
+
+	for {
+		a.mutex.Lock()
+		batch := a.buffer[:min(len(a.buffer), a.batchSize)]
+		dropped := a.dropped
+		a.mutex.Unlock()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := a.client.Insert(ctx, "swap_history", batch); err != nil {
+			return err
+		}
+
+		// the swaps of the batch dropped by RecordSwap meanwhile are gone already
+		a.mutex.Lock()
+		if inserted := len(batch) - (a.dropped - dropped); inserted > 0 {
+			a.buffer = a.buffer[inserted:]
+		}
+		a.mutex.Unlock()
+	}
+}
+
+// Buffered returns the number of swaps waiting to be inserted.
+func (a *ClickHouseSwapAnalytics) Buffered() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return len(a.buffer)
+}
+
+var clickHouseVolumeQuery = `
+	SELECT
+		toUnixTimestamp(toStartOfInterval(last_updated, toIntervalSecond({interval:UInt32}))) AS time,
+		sum(usd_value) AS volume_usd,
+		count() AS swap_count,
+		uniqExact(account) AS unique_accounts
+	FROM swap_history
+	WHERE network = {network:String}
+		AND ({pool:String} = '' OR token = {pool:String})
+		AND last_updated >= fromUnixTimestamp64Milli({from:Int64})
+		AND last_updated < fromUnixTimestamp64Milli({to:Int64})
+	GROUP BY time
+	ORDER BY time`
+
+// GetVolume aggregates the swaps on a network within [from, to) into buckets of the given interval,
+// of a single pool unless pool is empty.
+func (a *ClickHouseSwapAnalytics) GetVolume(ctx context.Context, network, pool string, from, to time.Time, interval time.Duration) ([]model.VolumeBucket, error) {
+	var rows []struct {
+		Time           int64         `json:"time"`
+		VolumeUsd      model.Decimal `json:"volume_usd"`
+		SwapCount      int64         `json:"swap_count"`
+		UniqueAccounts int64         `json:"unique_accounts"`
+	}
+	err := a.client.Query(ctx, clickHouseVolumeQuery, map[string]string{
+		"network":  network,
+		"pool":     pool,
+		"from":     strconv.FormatInt(from.UnixMilli(), 10),
+		"to":       strconv.FormatInt(to.UnixMilli(), 10),
+		"interval": strconv.FormatInt(int64(interval/time.Second), 10),
+	}, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap volume: %w", err)
+	}
+
+	buckets := make([]model.VolumeBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, model.VolumeBucket{
+			Time:           time.Unix(row.Time, 0).UTC(),
+			VolumeUsd:      row.VolumeUsd,
+			SwapCount:      row.SwapCount,
+			UniqueAccounts: row.UniqueAccounts,
+		})
+	}
+	return buckets, nil
+}
+
+var clickHouseVolumeLeaderboardQuery = `
+	SELECT account, sum(usd_value) AS total_usd, count() AS swap_count
+	FROM swap_history
+	WHERE network = {network:String}
+		AND last_updated >= fromUnixTimestamp64Milli({from:Int64})
+		AND last_updated < fromUnixTimestamp64Milli({to:Int64})
+	GROUP BY account
+	ORDER BY total_usd DESC, account
+	LIMIT {limit:UInt32}`
+
+// GetVolumeLeaderboard retrieves the accounts that swapped the most USD on a network within [from, to).
+func (a *ClickHouseSwapAnalytics) GetVolumeLeaderboard(ctx context.Context, network string, from, to time.Time, limit int) ([]model.TraderVolume, error) {
+	traders := []model.TraderVolume{}
+	err := a.client.Query(ctx, clickHouseVolumeLeaderboardQuery, map[string]string{
+		"network": network,
+		"from":    strconv.FormatInt(from.UnixMilli(), 10),
+		"to":      strconv.FormatInt(to.UnixMilli(), 10),
+		"limit":   strconv.Itoa(limit),
+	}, &traders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume leaderboard: %w", err)
+	}
+	return traders, nil
+}
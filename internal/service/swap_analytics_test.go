@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeSwapAnalytics records the swaps copied to it and fails when err is set.
+type fakeSwapAnalytics struct {
+	service.SwapAnalytics
+	swaps []model.SwapHistory
+	err   error
+}
+
+func (a *fakeSwapAnalytics) RecordSwap(ctx context.Context, swap *model.SwapHistory) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.swaps = append(a.swaps, *swap)
+	return nil
+}
+
+// TestCreateSwapHistory_SwapAnalytics tests that recorded swaps are copied to the swap analytics,
+// and that failing to copy one does not fail the swap.
+func TestCreateSwapHistory_SwapAnalytics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	analytics := &fakeSwapAnalytics{}
+	svc := service.NewService(mockRepo, service.WithSwapAnalytics(analytics))
+	ctx := context.Background()
+
+	history := &model.SwapHistory{Network: "mainnet", Token: "0xpool", Account: "0xuser", UsdValue: model.NewDecimalFromFloat(1200)}
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil).Times(2)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo).Times(2)
+	mockRepo.EXPECT().CreateSwapHistory(ctx, history).DoAndReturn(func(ctx context.Context, history *model.SwapHistory) error {
+		history.ID = 7
+		return nil
+	}).Times(2)
+	mockRepo.EXPECT().IncrementDailySwapRollup(ctx, history).Return(nil).Times(2)
+	mockTx.EXPECT().Commit(ctx).Return(nil).Times(2)
+
+	assert.NoError(t, svc.CreateSwapHistory(ctx, history))
+	if assert.Len(t, analytics.swaps, 1) {
+		assert.Equal(t, 7, analytics.swaps[0].ID)
+	}
+
+	analytics.err = errors.New("connection refused")
+	assert.NoError(t, svc.CreateSwapHistory(ctx, history))
+}
+
+// TestGetSwapVolume_Unavailable tests that the analytics reads fail as unavailable without swap analytics.
+func TestGetSwapVolume_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := service.NewService(repositoryMock.NewMockRepository(ctrl))
+	ctx := context.Background()
+
+	_, err := svc.GetSwapVolume(ctx, "mainnet", "", time.Time{}, time.Now(), time.Hour)
+	assert.ErrorIs(t, err, model.ErrAnalyticsUnavailable)
+	_, err = svc.GetVolumeLeaderboard(ctx, "mainnet", time.Time{}, time.Now(), 10)
+	assert.ErrorIs(t, err, model.ErrAnalyticsUnavailable)
+}
+
+// fakeClickHouse records the rows of the inserts it accepts, and rejects them while down.
+type fakeClickHouse struct {
+	mutex   sync.Mutex
+	down    bool
+	batches [][]string
+}
+
+func (f *fakeClickHouse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	f.batches = append(f.batches, lines[1:])
+}
+
+// TestClickHouseSwapAnalytics_Flush tests that buffered swaps are inserted in batches, kept while
+// ClickHouse is down and dropped oldest first past the maximum.
+func TestClickHouseSwapAnalytics_Flush(t *testing.T) {
+	clickhouse := &fakeClickHouse{down: true}
+	server := httptest.NewServer(clickhouse)
+	defer server.Close()
+
+	analytics := service.NewClickHouseSwapAnalytics(config.ClickHouse{
+		URL:           server.URL,
+		Database:      "default",
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		MaxBuffered:   4,
+	})
+	ctx := context.Background()
+	for id := 1; id <= 5; id++ {
+		assert.NoError(t, analytics.RecordSwap(ctx, &model.SwapHistory{ID: id, Network: "mainnet"}))
+	}
+	assert.Equal(t, 4, analytics.Buffered(), "the oldest swap is dropped")
+
+	assert.Error(t, analytics.Flush(ctx))
+	assert.Equal(t, 4, analytics.Buffered(), "the swaps stay buffered while ClickHouse is down")
+
+	clickhouse.down = false
+	assert.NoError(t, analytics.Flush(ctx))
+	assert.Equal(t, 0, analytics.Buffered())
+	if assert.Len(t, clickhouse.batches, 2) {
+		assert.Len(t, clickhouse.batches[0], 2)
+		assert.True(t, strings.HasPrefix(clickhouse.batches[0][0], `{"id":2,"network":"mainnet"`))
+		assert.True(t, strings.HasPrefix(clickhouse.batches[1][1], `{"id":5,"network":"mainnet"`))
+	}
+}
+
+// TestClickHouseSwapAnalytics_Run tests that a full batch is inserted without waiting for the flush
+// interval, and the swaps left on stop are inserted.
+func TestClickHouseSwapAnalytics_Run(t *testing.T) {
+	clickhouse := &fakeClickHouse{}
+	server := httptest.NewServer(clickhouse)
+	defer server.Close()
+
+	analytics := service.NewClickHouseSwapAnalytics(config.ClickHouse{
+		URL:           server.URL,
+		Database:      "default",
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		MaxBuffered:   10,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- analytics.Run(ctx) }()
+
+	assert.NoError(t, analytics.RecordSwap(ctx, &model.SwapHistory{ID: 1}))
+	assert.NoError(t, analytics.RecordSwap(ctx, &model.SwapHistory{ID: 2}))
+	assert.Eventually(t, func() bool { return analytics.Buffered() == 0 }, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, analytics.RecordSwap(ctx, &model.SwapHistory{ID: 3}))
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, 0, analytics.Buffered())
+	assert.Len(t, clickhouse.batches, 2)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"hw/internal/model"
+	"hw/pkg/config"
+)
+
+// WithPoints configures the caps on the USD value of swaps counted towards points. Without it,
+// swaps count in full.
+func WithPoints(cfg config.Points) Option {
+	return func(s *service) {
+		s.points = cfg
+	}
+}
+
+// countedUsdValue returns the USD value of a swap counted towards points: its value up to the
+// per-swap cap, and up to what is left of the daily cap of its account on the UTC day of the
+// swap. Swaps recorded concurrently for the same account may exceed the daily cap by one swap.
+func (s *service) countedUsdValue(ctx context.Context, history *model.SwapHistory) (model.Decimal, error) {
+	counted := history.UsdValue
+	if s.points.MaxSwapUSD > 0 {
+		counted = minDecimal(counted, model.NewDecimalFromFloat(float64(s.points.MaxSwapUSD)))
+	}
+	if s.points.MaxDailySwapUSD > 0 {
+		countedToday, err := s.repo.GetDailyCountedUsd(ctx, history.Account, history.LastUpdated)
+		if err != nil {
+			return model.Decimal{}, err
+		}
+		left := model.NewDecimalFromFloat(float64(s.points.MaxDailySwapUSD)).Sub(countedToday)
+		counted = minDecimal(counted, maxDecimal(left, model.ZeroDecimal))
+	}
+	return counted, nil
+}
+
+func minDecimal(a, b model.Decimal) model.Decimal {
+	if a.LessThan(b.Decimal) {
+		return a
+	}
+	return b
+}
+
+func maxDecimal(a, b model.Decimal) model.Decimal {
+	if a.GreaterThan(b.Decimal) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestCreateSwapHistory_Caps tests that the USD value counted towards points is capped per swap
+// and by what is left of the daily cap of the account.
+func TestCreateSwapHistory_Caps(t *testing.T) {
+	tests := []struct {
+		name         string
+		points       config.Points
+		usdValue     float64
+		countedToday float64
+		counted      float64
+	}{
+		{name: "no caps", usdValue: 250_000, counted: 250_000},
+		{name: "under the swap cap", points: config.Points{MaxSwapUSD: 10_000}, usdValue: 2_500, counted: 2_500},
+		{name: "over the swap cap", points: config.Points{MaxSwapUSD: 10_000}, usdValue: 250_000, counted: 10_000},
+		{name: "daily cap left", points: config.Points{MaxDailySwapUSD: 50_000}, usdValue: 20_000, countedToday: 40_000, counted: 10_000},
+		{name: "daily cap reached", points: config.Points{MaxDailySwapUSD: 50_000}, usdValue: 20_000, countedToday: 50_000, counted: 0},
+		{name: "both caps", points: config.Points{MaxSwapUSD: 10_000, MaxDailySwapUSD: 50_000}, usdValue: 20_000, countedToday: 35_000, counted: 10_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := repositoryMock.NewMockRepository(ctrl)
+			mockTx := pgMock.NewMockPgxTx(ctrl)
+			svc := service.NewService(mockRepo, service.WithPoints(tt.points))
+
+			ctx := context.Background()
+			history := &model.SwapHistory{
+				Token:       "tokenABC",
+				Account:     "accountXYZ",
+				UsdValue:    model.NewDecimalFromFloat(tt.usdValue),
+				LastUpdated: time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC),
+			}
+
+			if tt.points.MaxDailySwapUSD > 0 {
+				mockRepo.EXPECT().GetDailyCountedUsd(ctx, history.Account, history.LastUpdated).Return(model.NewDecimalFromFloat(tt.countedToday), nil)
+			}
+			mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
+			mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo)
+			mockRepo.EXPECT().CreateSwapHistory(ctx, history).Return(nil)
+			mockRepo.EXPECT().IncrementDailySwapRollup(ctx, history).Return(nil)
+			mockTx.EXPECT().Commit(ctx).Return(nil)
+
+			assert.NoError(t, svc.CreateSwapHistory(ctx, history))
+			assert.True(t, model.NewDecimalFromFloat(tt.counted).Equal(history.CountedUsdValue.Decimal), "counted %s", history.CountedUsdValue)
+			assert.True(t, model.NewDecimalFromFloat(tt.usdValue).Equal(history.UsdValue.Decimal), "the USD value is kept")
+		})
+	}
+}
+
+// TestCreateSwapHistory_CapsError tests that a swap is not recorded when its daily cap cannot be checked.
+func TestCreateSwapHistory_CapsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo, service.WithPoints(config.Points{MaxDailySwapUSD: 50_000}))
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetDailyCountedUsd(ctx, gomock.Any(), gomock.Any()).Return(model.Decimal{}, errors.New("db error"))
+
+	err := svc.CreateSwapHistory(ctx, &model.SwapHistory{Account: "accountXYZ", UsdValue: model.NewDecimalFromFloat(100)})
+
+	assert.Error(t, err)
+}
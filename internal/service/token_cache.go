@@ -0,0 +1,213 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hw/internal/model"
+	"hw/pkg/cache"
+	"hw/pkg/ethindexa/utils"
+	"hw/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	// TokenInfoTTL is how long fetched token metadata is cached.
+	TokenInfoTTL = time.Hour
+	// TokenInfoMinBackoff is the wait before retrying a token whose metadata lookup failed once.
+	TokenInfoMinBackoff = 30 * time.Second
+	// TokenInfoMaxBackoff caps the wait between retries of a failing token.
+	TokenInfoMaxBackoff = time.Hour
+	// TokenLRUSize is the number of stored tokens kept in memory by GetOrCreateToken.
+	TokenLRUSize = 10000
+)
+
+// TokenInfoFetcher fetches the metadata of a token from the chain.
+type TokenInfoFetcher func(ctx context.Context, client *ethclient.Client, tokenId string, blockNumber int64) (*model.Token, error)
+
+// Option configures the service.
+type Option func(*service)
+
+// WithTokenCache sets the cache holding token metadata lookups.
+func WithTokenCache(c cache.Cache) Option {
+	return func(s *service) {
+		s.tokenCache = c
+	}
+}
+
+// WithTokenInfoFetcher replaces the on-chain token metadata lookup.
+func WithTokenInfoFetcher(fetch TokenInfoFetcher) Option {
+	return func(s *service) {
+		s.fetchTokenInfo = fetch
+	}
+}
+
+// tokenInfoFailure is the cached result of a failed metadata lookup.
+type tokenInfoFailure struct {
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error"`
+}
+
+// tokenBackoff tracks consecutive metadata lookup failures per token.
+type tokenBackoff struct {
+	mutex    sync.Mutex
+	attempts map[string]int
+	retryAt  map[string]time.Time
+	now      func() time.Time
+}
+
+func newTokenBackoff() *tokenBackoff {
+	return &tokenBackoff{
+		attempts: make(map[string]int),
+		retryAt:  make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// wait returns how long a token must still wait before its next lookup.
+func (b *tokenBackoff) wait(tokenId string) time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	retryAt, exists := b.retryAt[tokenId]
+	if !exists {
+		return 0
+	}
+	if wait := retryAt.Sub(b.now()); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// fail records a failed lookup and returns the number of consecutive failures and the backoff before the next one.
+func (b *tokenBackoff) fail(tokenId string) (int, time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.attempts[tokenId]++
+	attempts := b.attempts[tokenId]
+
+	backoff := TokenInfoMinBackoff
+	for i := 1; i < attempts && backoff < TokenInfoMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > TokenInfoMaxBackoff {
+		backoff = TokenInfoMaxBackoff
+	}
+	b.retryAt[tokenId] = b.now().Add(backoff)
+
+	return attempts, backoff
+}
+
+// reset clears the failures of a token after a successful lookup.
+func (b *tokenBackoff) reset(tokenId string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.attempts, tokenId)
+	delete(b.retryAt, tokenId)
+}
+
+// getTokenInfo returns the metadata of a token from the cache, or from the chain on a miss.
+// Failed lookups are cached and backed off exponentially so a broken token does not hammer the RPC.
+func (s *service) getTokenInfo(ctx context.Context, client *ethclient.Client, tokenId string, blockNumber int64) (*model.Token, error) {
+	infoKey := s.tokenCache.FormatKey("token_info", tokenId)
+	failureKey := s.tokenCache.FormatKey("token_info_failure", tokenId)
+
+	if cached, err := cache.Get[model.Token](ctx, s.tokenCache, infoKey); err == nil {
+		return &cached, nil
+	}
+
+	if wait := s.tokenBackoff.wait(tokenId); wait > 0 {
+		return nil, fmt.Errorf("%w: token %s retried in %s", model.ErrTokenInfoUnavailable, tokenId, wait.Round(time.Second))
+	}
+	if failure, err := cache.Get[tokenInfoFailure](ctx, s.tokenCache, failureKey); err == nil {
+		return nil, fmt.Errorf("%w: token %s failed %d times: %s", model.ErrTokenInfoUnavailable, tokenId, failure.Attempts, failure.Error)
+	}
+
+	tokenInfo, err := s.fetchTokenInfo(ctx, client, tokenId, blockNumber)
+	if err != nil {
+		attempts, backoff := s.tokenBackoff.fail(tokenId)
+		logger.Warnf("Token %s metadata lookup failed %d times, retrying in %s: %v", tokenId, attempts, backoff, err)
+		if err := s.tokenCache.Set(ctx, failureKey, tokenInfoFailure{Attempts: attempts, Error: err.Error()}, backoff); err != nil {
+			logger.Warnf("Failed to cache token %s lookup failure: %v", tokenId, err)
+		}
+		return nil, err
+	}
+
+	s.tokenBackoff.reset(tokenId)
+	if err := s.tokenCache.Del(ctx, failureKey); err != nil {
+		logger.Warnf("Failed to clear token %s lookup failure: %v", tokenId, err)
+	}
+	if err := s.tokenCache.Set(ctx, infoKey, *tokenInfo, TokenInfoTTL); err != nil {
+		logger.Warnf("Failed to cache token %s metadata: %v", tokenId, err)
+	}
+
+	return tokenInfo, nil
+}
+
+// tokenLRU keeps the most recently used stored tokens by address, so the indexer resolves the
+// tokens of its events without a database round trip.
+type tokenLRU struct {
+	size int
+
+	mutex   sync.Mutex
+	order   *list.List // of *model.Token, most recently used first
+	entries map[string]*list.Element
+}
+
+func newTokenLRU(size int) *tokenLRU {
+	if size < 1 {
+		size = 1
+	}
+	return &tokenLRU{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the token of an address and marks it as the most recently used.
+func (c *tokenLRU) get(tokenId string) (*model.Token, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, exists := c.entries[tokenId]
+	if !exists {
+		return nil, false
+	}
+	c.order.MoveToFront(entry)
+	return entry.Value.(*model.Token), true
+}
+
+// add stores a token, evicting the least recently used one when full.
+func (c *tokenLRU) add(token *model.Token) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if entry, exists := c.entries[token.ID]; exists {
+		entry.Value = token
+		c.order.MoveToFront(entry)
+		return
+	}
+	c.entries[token.ID] = c.order.PushFront(token)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*model.Token).ID)
+	}
+}
+
+// remove drops the token of an address, so its next lookup reads the database.
+func (c *tokenLRU) remove(tokenId string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if entry, exists := c.entries[tokenId]; exists {
+		c.order.Remove(entry)
+		delete(c.entries, tokenId)
+	}
+}
+
+// defaultTokenInfoFetcher looks token metadata up through the token's ERC-20 contract.
+var defaultTokenInfoFetcher TokenInfoFetcher = utils.GetTokenInfo
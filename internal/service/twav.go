@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// TWAVRewardTask is the points history description of time-weighted average volume rewards.
+const TWAVRewardTask = "twav_reward_task"
+
+// DistributeTWAVRewards awards totalPoints to the accounts that swapped through a pool of network in
+// proportion to their time-weighted average volume (TWAV) over the UTC days of [from, to), from
+// the daily rollups of their counted USD. The TWAV of an account is the square of the mean square
+// root of its daily volume over every day of the period: volume kept up across the days counts
+// fully, while the same volume swapped in a single day of a 7-day period counts for 1/49. Accounts
+// excluded from points distributions get no share. The points are recorded under TWAVRewardTask
+// and truncated to 3 decimals, with the pool and period as campaign in their metadata. Running it
+// twice for the same period awards the points twice.
+func (s *service) DistributeTWAVRewards(ctx context.Context, network, pool string, from, to time.Time, totalPoints model.Decimal) ([]model.TWAVReward, error) {
+	if !from.Before(to) {
+		return nil, model.NewError(model.ErrInvalid, "reward period must end after it starts")
+	}
+	if !isMidnightUTC(from) || !isMidnightUTC(to) {
+		return nil, model.NewError(model.ErrInvalid, "reward period must start and end at midnight UTC")
+	}
+
+	volumes, err := s.repo.GetDailyPoolVolumes(ctx, network, pool, from, to)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := s.excludedAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	days := int64(to.Sub(from) / (24 * time.Hour))
+	rewards := timeWeightedAverageVolume(volumes, days, excluded)
+	total := decimal.Zero
+	for _, reward := range rewards {
+		total = total.Add(reward.TWAV.Decimal)
+	}
+	if !total.IsPositive() {
+		return nil, model.ErrNothingToDistribute
+	}
+
+	for i := range rewards {
+		twav := rewards[i].TWAV.Decimal
+		rewards[i].Share = model.NewDecimal(twav.DivRound(total, 18))
+		rewards[i].Points = model.NewDecimal(totalPoints.Mul(twav).Div(total).Truncate(3))
+	}
+	metadata := &model.PointsMetadata{Campaign: RewardCampaign(pool, from, to)}
+	for _, reward := range rewards {
+		if !reward.Points.IsPositive() {
+			continue
+		}
+		if err := s.AccumulateUserPointsWithMetadata(ctx, network, pool, reward.Account, TWAVRewardTask, reward.Points, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return rewards, nil
+}
+
+// timeWeightedAverageVolume computes the TWAV over days of every account not excluded with daily
+// volumes, ordered by TWAV.
+func timeWeightedAverageVolume(volumes []model.DailyVolume, days int64, excluded map[string]bool) []model.TWAVReward {
+	type sum struct {
+		roots      decimal.Decimal
+		activeDays int
+	}
+	sums := make(map[string]*sum)
+	for _, volume := range volumes {
+		if excluded[volume.Account] || !volume.UsdValue.IsPositive() {
+			continue
+		}
+		if sums[volume.Account] == nil {
+			sums[volume.Account] = &sum{}
+		}
+		sums[volume.Account].roots = sums[volume.Account].roots.Add(sqrtDecimal(volume.UsdValue.Decimal))
+		sums[volume.Account].activeDays++
+	}
+
+	rewards := make([]model.TWAVReward, 0, len(sums))
+	for account, sum := range sums {
+		mean := sum.roots.DivRound(decimal.NewFromInt(days), 18)
+		rewards = append(rewards, model.TWAVReward{
+			Account:    account,
+			TWAV:       model.NewDecimal(mean.Mul(mean).Round(6)),
+			ActiveDays: sum.activeDays,
+		})
+	}
+	sort.Slice(rewards, func(i, j int) bool {
+		if cmp := rewards[i].TWAV.Cmp(rewards[j].TWAV.Decimal); cmp != 0 {
+			return cmp > 0
+		}
+		return rewards[i].Account < rewards[j].Account
+	})
+	return rewards
+}
+
+// sqrtDecimal returns the square root of a non-negative d to 18 decimals.
+func sqrtDecimal(d decimal.Decimal) decimal.Decimal {
+	f, _ := new(big.Float).SetPrec(256).SetString(d.String())
+	root, _ := decimal.NewFromString(new(big.Float).SetPrec(256).Sqrt(f).Text('f', 18))
+	return root
+}
+
+// isMidnightUTC reports whether t is the start of a UTC day.
+func isMidnightUTC(t time.Time) bool {
+	return t.Equal(t.UTC().Truncate(24 * time.Hour))
+}
@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestDistributeTWAVRewards tests that volume kept up across the days of the period earns more than
+// the same volume swapped in a single day, and that excluded accounts get no share.
+func TestDistributeTWAVRewards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	svc := service.NewService(mockRepo, service.WithPoints(config.Points{ExcludeAddresses: []string{"0xTeam"}}))
+	ctx := context.Background()
+
+	from := time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC)
+	to := from.Add(4 * 24 * time.Hour)
+	day := func(n int) time.Time { return from.Add(time.Duration(n) * 24 * time.Hour) }
+
+	// 0xa swaps 100 every day, 0xb swaps 400 in a single day
+	mockRepo.EXPECT().GetDailyPoolVolumes(ctx, "mainnet", "0xpool", from, to).Return([]model.DailyVolume{
+		{Day: day(0), Account: "0xa", UsdValue: model.NewDecimalFromFloat(100)},
+		{Day: day(1), Account: "0xa", UsdValue: model.NewDecimalFromFloat(100)},
+		{Day: day(2), Account: "0xa", UsdValue: model.NewDecimalFromFloat(100)},
+		{Day: day(3), Account: "0xa", UsdValue: model.NewDecimalFromFloat(100)},
+		{Day: day(2), Account: "0xb", UsdValue: model.NewDecimalFromFloat(400)},
+		{Day: day(2), Account: "0xteam", UsdValue: model.NewDecimalFromFloat(5000)},
+	}, nil)
+
+	awarded := make(map[string]model.Decimal)
+	mockRepo.EXPECT().BeginTransaction(ctx).Return(mockTx, nil).Times(2)
+	mockRepo.EXPECT().WithTx(mockTx).Return(mockRepo).Times(2)
+	mockRepo.EXPECT().CreatePointsHistory(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, ph *model.PointsHistory) error {
+		assert.Equal(t, service.TWAVRewardTask, ph.Description)
+		assert.Equal(t, "0xpool", ph.Token)
+		assert.Equal(t, &model.PointsMetadata{Campaign: "0xpool:2024-10-20T00:00:00Z/2024-10-24T00:00:00Z"}, ph.Metadata)
+		awarded[ph.Account] = ph.Points
+		ph.ID = len(awarded)
+		return nil
+	}).Times(2)
+	mockRepo.EXPECT().UpsertUserPoints(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockRepo.EXPECT().IncrementDailyPointsRollup(ctx, gomock.Any()).Return(nil).Times(2)
+	mockTx.EXPECT().Commit(ctx).Return(nil).Times(2)
+
+	rewards, err := svc.DistributeTWAVRewards(ctx, "mainnet", "0xpool", from, to, model.NewDecimalFromFloat(1000))
+
+	assert.NoError(t, err)
+	if assert.Len(t, rewards, 2) {
+		assert.Equal(t, "0xa", rewards[0].Account)
+		assert.True(t, rewards[0].TWAV.Equal(model.NewDecimalFromFloat(100).Decimal), rewards[0].TWAV.String())
+		assert.Equal(t, 4, rewards[0].ActiveDays)
+		assert.True(t, rewards[0].Share.Equal(model.NewDecimalFromFloat(0.8).Decimal))
+		assert.Equal(t, "0xb", rewards[1].Account)
+		assert.True(t, rewards[1].TWAV.Equal(model.NewDecimalFromFloat(25).Decimal), rewards[1].TWAV.String())
+		assert.Equal(t, 1, rewards[1].ActiveDays)
+	}
+	assert.True(t, awarded["0xa"].Equal(model.NewDecimalFromFloat(800).Decimal))
+	assert.True(t, awarded["0xb"].Equal(model.NewDecimalFromFloat(200).Decimal))
+}
+
+// TestDistributeTWAVRewards_InvalidPeriod tests that periods not made of whole UTC days are rejected.
+func TestDistributeTWAVRewards_InvalidPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := service.NewService(repositoryMock.NewMockRepository(ctrl))
+	ctx := context.Background()
+	from := time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.DistributeTWAVRewards(ctx, "mainnet", "0xpool", from, from, model.NewDecimalFromFloat(1000))
+	assert.ErrorIs(t, err, model.ErrInvalid)
+	_, err = svc.DistributeTWAVRewards(ctx, "mainnet", "0xpool", from, from.Add(36*time.Hour), model.NewDecimalFromFloat(1000))
+	assert.ErrorIs(t, err, model.ErrInvalid)
+}
+
+// TestDistributeTWAVRewards_NothingToDistribute tests that a period without volume awards nothing.
+func TestDistributeTWAVRewards_NothingToDistribute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+	ctx := context.Background()
+	from := time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().GetDailyPoolVolumes(ctx, "mainnet", "0xpool", from, from.Add(24*time.Hour)).Return(nil, nil)
+
+	_, err := svc.DistributeTWAVRewards(ctx, "mainnet", "0xpool", from, from.Add(24*time.Hour), model.NewDecimalFromFloat(1000))
+	assert.ErrorIs(t, err, model.ErrNothingToDistribute)
+}
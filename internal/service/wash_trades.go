@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"hw/internal/model"
+)
+
+// FlagRoundTripSwaps flags the swaps of the account of a swap history entry through its pool in
+// its transaction as wash trades when there are several, e.g. a swap and its reverse routed
+// through an aggregator, and reports whether they were flagged. The flag of the entry is set.
+func (s *service) FlagRoundTripSwaps(ctx context.Context, history *model.SwapHistory) (bool, error) {
+	flagged, err := s.repo.FlagRoundTripSwaps(ctx, history)
+	if err != nil {
+		return false, err
+	}
+	history.WashTrade = history.WashTrade || flagged
+	return flagged, nil
+}
+
+// ExcludesWashTrades reports whether a campaign, named as the task of its points, leaves the
+// volume of wash trades out.
+func (s *service) ExcludesWashTrades(campaign string) bool {
+	for _, excluded := range s.points.ExcludeWashTrades {
+		if excluded == campaign {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"hw/internal/model"
+	repositoryMock "hw/internal/repository/mocks"
+	"hw/internal/service"
+	"hw/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestFlagRoundTripSwaps tests that the swap closing a round trip is flagged along with its legs.
+func TestFlagRoundTripSwaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+	svc := service.NewService(mockRepo)
+
+	ctx := context.Background()
+	history := &model.SwapHistory{Network: "mainnet", Token: "tokenABC", Account: "accountXYZ", TransactionHash: "0xtx"}
+
+	mockRepo.EXPECT().FlagRoundTripSwaps(ctx, history).Return(true, nil)
+	flagged, err := svc.FlagRoundTripSwaps(ctx, history)
+	assert.NoError(t, err)
+	assert.True(t, flagged)
+	assert.True(t, history.WashTrade)
+
+	single := &model.SwapHistory{Network: "mainnet", Token: "tokenABC", Account: "accountXYZ", TransactionHash: "0xother"}
+	mockRepo.EXPECT().FlagRoundTripSwaps(ctx, single).Return(false, nil)
+	flagged, err = svc.FlagRoundTripSwaps(ctx, single)
+	assert.NoError(t, err)
+	assert.False(t, flagged)
+	assert.False(t, single.WashTrade)
+}
+
+// TestExcludesWashTrades tests that wash trades are left out of the configured campaigns only.
+func TestExcludesWashTrades(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repositoryMock.NewMockRepository(ctrl)
+
+	svc := service.NewService(mockRepo, service.WithPoints(config.Points{ExcludeWashTrades: []string{"sharepool_usdcweth_task"}}))
+	assert.True(t, svc.ExcludesWashTrades("sharepool_usdcweth_task"))
+	assert.False(t, svc.ExcludesWashTrades("onboarding_task"))
+
+	assert.False(t, service.NewService(mockRepo).ExcludesWashTrades("sharepool_usdcweth_task"), "wash trades count by default")
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"hw/pkg/logger"
+)
+
+type adminActorKey struct{}
+
+// AdminActor returns the operator of the admin token authenticated by RequireAdminToken, or "".
+func AdminActor(ctx context.Context) string {
+	actor, _ := ctx.Value(adminActorKey{}).(string)
+	return actor
+}
+
+// RequireAdminToken rejects the requests under /admin/ other than GET and HEAD without an
+// "Authorization: Bearer <token>" of one of tokens, given as operator:token, and passes the
+// operator of the token to next as its AdminActor. Without tokens every such request is rejected.
+func RequireAdminToken(tokens []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || !strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		actor := ""
+		if found {
+			actor = adminTokenOperator(tokens, token)
+		}
+		if actor == "" {
+			logger.Warnf("Rejected unauthenticated admin request %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "admin token required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminActorKey{}, actor)))
+	})
+}
+
+// adminTokenOperator returns the operator of token among tokens, or "". Every token is compared
+// in constant time, so the comparison does not tell how much of a token matched.
+func adminTokenOperator(tokens []string, token string) string {
+	operator := ""
+	for _, entry := range tokens {
+		name, secret, _ := strings.Cut(entry, ":")
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1 && token != "" {
+			operator = name
+		}
+	}
+	return operator
+}
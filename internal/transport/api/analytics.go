@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultVolumeInterval = "1d"
+	defaultAnalyticsRange = 30 * 24 * time.Hour
+	maxVolumeBuckets      = 10000
+)
+
+// volumeIntervals maps the bucket intervals accepted by GetVolume to their durations.
+var volumeIntervals = map[string]time.Duration{
+	"1h": time.Hour,
+	"4h": 4 * time.Hour,
+	"1d": 24 * time.Hour,
+	"1w": 7 * 24 * time.Hour,
+}
+
+// GetVolume handles retrieving the swap volume of a network, or of a single pool, bucketed by interval.
+func (s *Server) GetVolume(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	to := v.queryTime("to", time.Now().UTC())
+	from := v.queryTime("from", to.Add(-defaultAnalyticsRange))
+	pool := v.queryAddress("pool")
+	network := v.queryNetwork("network")
+	if network == "" {
+		network = defaultNetwork
+	}
+	name := r.URL.Query().Get("interval")
+	if name == "" {
+		name = defaultVolumeInterval
+	}
+	interval, ok := volumeIntervals[name]
+	if !ok {
+		v.fail("interval", "must be one of 1h, 4h, 1d or 1w")
+	} else if to.Sub(from)/interval > maxVolumeBuckets {
+		v.fail("interval", "must not split the range into more than %d buckets", maxVolumeBuckets)
+	}
+	if v.check(w) {
+		return
+	}
+
+	buckets, err := s.Service.GetSwapVolume(r.Context(), network, pool, from, to, interval)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, buckets)
+}
+
+// GetVolumeLeaderboard handles retrieving the accounts that swapped the most USD on a network
+// within a range, up to limit accounts.
+func (s *Server) GetVolumeLeaderboard(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	to := v.queryTime("to", time.Now().UTC())
+	from := v.queryTime("from", to.Add(-defaultAnalyticsRange))
+	network := v.queryNetwork("network")
+	if network == "" {
+		network = defaultNetwork
+	}
+	limit := v.queryInt("limit", defaultPageLimit, 1, maxPageLimit)
+	if v.check(w) {
+		return
+	}
+
+	traders, err := s.Service.GetVolumeLeaderboard(r.Context(), network, from, to, limit)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, traders)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetVolume_Success tests that the pool, range, interval and network reach the service.
+func TestGetVolume_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{Service: mockService}
+
+	pool := "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
+	from := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	buckets := []model.VolumeBucket{{Time: from, VolumeUsd: model.NewDecimalFromFloat(125000), SwapCount: 42, UniqueAccounts: 17}}
+	mockService.EXPECT().GetSwapVolume(gomock.Any(), "base", pool, from, to, 7*24*time.Hour).Return(buckets, nil)
+
+	r := chi.NewRouter()
+	r.Get("/analytics/volume", server.GetVolume)
+	req := httptest.NewRequest("GET", "/analytics/volume?pool=0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc&from=2024-10-01T00:00:00Z&to=2024-11-01T00:00:00Z&interval=1w&network=base", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []model.VolumeBucket
+	assert.NoError(t, render.DecodeJSON(rr.Body, &resp))
+	assert.Equal(t, buckets, resp)
+}
+
+// TestGetVolume_InvalidParams tests that invalid parameters are rejected before reaching the service.
+func TestGetVolume_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server := Server{Service: mocks.NewMockService(ctrl)}
+
+	r := chi.NewRouter()
+	r.Get("/analytics/volume", server.GetVolume)
+	for _, query := range []string{"interval=1m", "pool=0xpool", "network=unknown", "from=2020-01-01T00:00:00Z&interval=1h"} {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", "/analytics/volume?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+// TestGetVolumeLeaderboard_Unavailable tests that a 503 is returned without swap analytics.
+func TestGetVolumeLeaderboard_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{Service: mockService}
+
+	mockService.EXPECT().GetVolumeLeaderboard(gomock.Any(), "mainnet", gomock.Any(), gomock.Any(), 10).
+		DoAndReturn(func(_ interface{}, _ string, from, to time.Time, _ int) ([]model.TraderVolume, error) {
+			assert.Equal(t, 30*24*time.Hour, to.Sub(from))
+			return nil, model.ErrAnalyticsUnavailable
+		})
+
+	r := chi.NewRouter()
+	r.Get("/analytics/leaderboard", server.GetVolumeLeaderboard)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/analytics/leaderboard?limit=10", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// GetApprovals handles retrieving the outstanding allowances a user approved, optionally filtered by the network query parameter.
+func (s *Server) GetApprovals(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	id := v.pathAddress("id")
+	network := v.queryNetwork("network")
+	if v.check(w) {
+		return
+	}
+
+	allowances, err := s.Service.GetUserApprovals(r.Context(), id, network)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, allowances)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetApprovals tests that a user's outstanding approvals are served, on every network unless one is given.
+func TestGetApprovals(t *testing.T) {
+	userID := "0x00000000000000000000000000000000000000a1"
+	allowances := []model.Allowance{
+		{
+			Network:         "mainnet",
+			Token:           "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			Owner:           userID,
+			Spender:         "0x7a250d5630b4cf539739df2c5dacb4c659f2488d",
+			Amount:          model.NewDecimalFromFloat(2500000),
+			TransactionHash: "0xabc",
+			BlockNumber:     20933200,
+			UpdatedAt:       time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		network    string
+		allowances []model.Allowance
+		err        error
+		code       int
+	}{
+		{name: "every network", allowances: allowances, code: http.StatusOK},
+		{name: "one network", query: "?network=base", network: "base", allowances: []model.Allowance{}, code: http.StatusOK},
+		{name: "service error", err: errors.New("db down"), code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockService(ctrl)
+			server := Server{
+				Service: mockService,
+			}
+
+			mockService.EXPECT().GetUserApprovals(gomock.Any(), userID, tt.network).Return(tt.allowances, tt.err)
+
+			r := chi.NewRouter()
+			r.Get("/user/{id}/approvals", server.GetApprovals)
+
+			req, err := http.NewRequest("GET", "/user/"+userID+"/approvals"+tt.query, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+			if tt.allowances != nil {
+				var resp []model.Allowance
+				assert.NoError(t, render.DecodeJSON(rr.Body, &resp))
+				assert.Equal(t, tt.allowances, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"hw/internal/model"
+
+	"github.com/go-chi/render"
+)
+
+// signInRequest is the body of a sign-in: an EIP-4361 message with a nonce from /auth/nonce,
+// and its personal_sign signature.
+type signInRequest struct {
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// profileRequest is the body of a profile update, which replaces the whole profile.
+type profileRequest struct {
+	Email         string                        `json:"email"`
+	Notifications model.NotificationPreferences `json:"notifications"`
+}
+
+// maxEmailLength is the longest email address that can be delivered to (RFC 5321).
+const maxEmailLength = 254
+
+type sessionAddressKey struct{}
+
+// sessionAddress returns the address of the session authenticated by requireSession.
+func sessionAddress(r *http.Request) string {
+	address, _ := r.Context().Value(sessionAddressKey{}).(string)
+	return address
+}
+
+// requireSession rejects requests without a valid "Authorization: Bearer <token>" session
+// and passes the session's address to next.
+func (s *Server) requireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		var address string
+		if found {
+			address, _ = s.Service.Authenticate(token)
+		}
+		if address == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			render.Render(w, r, &errorResponse{Error: model.ErrInvalidSession.Error(), HTTPStatusCode: http.StatusUnauthorized})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionAddressKey{}, address)))
+	})
+}
+
+// GetSignInNonce handles issuing a nonce for a sign-in message.
+func (s *Server) GetSignInNonce(w http.ResponseWriter, r *http.Request) {
+	nonce, err := s.Service.CreateSignInNonce(r.Context())
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, nonce)
+}
+
+// PostSignIn handles verifying a signed sign-in message and issuing a session.
+func (s *Server) PostSignIn(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	var req signInRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		v.fail("body", "must be a JSON object with message and signature")
+	} else {
+		if req.Message == "" {
+			v.fail("message", "is required")
+		}
+		if req.Signature == "" {
+			v.fail("signature", "is required")
+		}
+	}
+	if v.check(w) {
+		return
+	}
+
+	session, err := s.Service.SignIn(r.Context(), req.Message, req.Signature)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, session)
+}
+
+// GetProfile handles retrieving the profile of the signed-in user.
+func (s *Server) GetProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := s.Service.GetUserProfile(r.Context(), sessionAddress(r))
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, profile)
+}
+
+// PutProfile handles replacing the profile of the signed-in user.
+func (s *Server) PutProfile(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	var req profileRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		v.fail("body", "must be a JSON object with email and notifications")
+	} else if req.Email != "" {
+		if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != req.Email || len(req.Email) > maxEmailLength {
+			v.fail("email", "must be an email address of at most %d characters", maxEmailLength)
+		}
+	}
+	if v.check(w) {
+		return
+	}
+
+	profile := &model.UserProfile{Address: sessionAddress(r), Email: req.Email, Notifications: req.Notifications}
+	if err := s.Service.UpdateUserProfile(r.Context(), profile); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, profile)
+}
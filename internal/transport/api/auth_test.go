@@ -0,0 +1,138 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestPostSignIn tests the status of a sign-in for each outcome of the service.
+func TestPostSignIn(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "signed in", body: `{"message":"siwe","signature":"0xsig"}`, wantStatus: http.StatusOK, wantBody: `"token":"session"`},
+		{
+			name: "invalid sign-in", body: `{"message":"siwe","signature":"0xsig"}`,
+			serviceErr: fmt.Errorf("%w: nonce is unknown, expired or used", model.ErrInvalidSignIn),
+			wantStatus: http.StatusUnauthorized, wantBody: "nonce is unknown",
+		},
+		{name: "missing fields", body: `{}`, wantStatus: http.StatusBadRequest, wantBody: "invalid parameters: message, signature"},
+		{name: "malformed body", body: `message`, wantStatus: http.StatusBadRequest, wantBody: `"field":"body"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockService(ctrl)
+			server := Server{Service: mockService}
+
+			if tt.wantStatus != http.StatusBadRequest {
+				session := &model.Session{Token: "session", Address: "0xabc"}
+				if tt.serviceErr != nil {
+					session = nil
+				}
+				mockService.EXPECT().SignIn(gomock.Any(), "siwe", "0xsig").Return(session, tt.serviceErr)
+			}
+
+			router := chi.NewRouter()
+			router.Post("/auth/verify", server.PostSignIn)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/auth/verify", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+// TestProfile tests that the profile endpoints require a session and act on the session's address.
+func TestProfile(t *testing.T) {
+	const address = "0x00000000000000000000000000000000000000a1"
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		body          string
+		setup         func(mockService *mocks.MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name: "no session", method: http.MethodGet,
+			setup:      func(mockService *mocks.MockService) {},
+			wantStatus: http.StatusUnauthorized, wantBody: "invalid session",
+		},
+		{
+			name: "expired session", method: http.MethodGet, authorization: "Bearer expired",
+			setup: func(mockService *mocks.MockService) {
+				mockService.EXPECT().Authenticate("expired").Return("", model.ErrInvalidSession)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "get", method: http.MethodGet, authorization: "Bearer session",
+			setup: func(mockService *mocks.MockService) {
+				mockService.EXPECT().Authenticate("session").Return(address, nil)
+				mockService.EXPECT().GetUserProfile(gomock.Any(), address).Return(&model.UserProfile{Address: address, Email: "user@example.com"}, nil)
+			},
+			wantStatus: http.StatusOK, wantBody: `"email":"user@example.com"`,
+		},
+		{
+			name: "put", method: http.MethodPut, authorization: "Bearer session",
+			body: `{"email":"user@example.com","notifications":{"weekly_summary":true}}`,
+			setup: func(mockService *mocks.MockService) {
+				mockService.EXPECT().Authenticate("session").Return(address, nil)
+				mockService.EXPECT().UpdateUserProfile(gomock.Any(), &model.UserProfile{
+					Address:       address,
+					Email:         "user@example.com",
+					Notifications: model.NotificationPreferences{WeeklySummary: true},
+				}).Return(nil)
+			},
+			wantStatus: http.StatusOK, wantBody: `"weekly_summary":true`,
+		},
+		{
+			name: "put invalid email", method: http.MethodPut, authorization: "Bearer session",
+			body: `{"email":"User <user@example.com>"}`,
+			setup: func(mockService *mocks.MockService) {
+				mockService.EXPECT().Authenticate("session").Return(address, nil)
+			},
+			wantStatus: http.StatusBadRequest, wantBody: `"field":"email"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockService(ctrl)
+			tt.setup(mockService)
+
+			router := chi.NewRouter()
+			ConfigureHTTPServer(router, Server{Logger: zap.NewNop(), Service: mockService})
+			req := httptest.NewRequest(tt.method, "/me/profile", strings.NewReader(tt.body))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.wantBody)
+		})
+	}
+}
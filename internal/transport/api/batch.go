@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"hw/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// maxBatchSize is the number of operations a batch may hold.
+const maxBatchSize = 20
+
+// batchConcurrency is the number of operations of a batch executed at once.
+const batchConcurrency = 4
+
+// batchRequest is a read operation of a batch: a method with its parameters, named as the
+// parameters of the endpoint it reads, e.g. {"method": "user", "params": {"id": "0x...", "network": "mainnet"}}.
+// ID is echoed in its response.
+type batchRequest struct {
+	ID     interface{}            `json:"id,omitempty"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// batchResponse is the outcome of an operation: the status and body its endpoint would have
+// responded with, as Result when successful and as Error otherwise.
+type batchResponse struct {
+	ID     interface{}     `json:"id,omitempty"`
+	Status int             `json:"status"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// batchMethod is a read endpoint callable in a batch. Its path parameters are taken from the
+// parameters of the operation, and the others are passed as query parameters.
+type batchMethod struct {
+	PathParams []string
+	Handler    http.HandlerFunc
+}
+
+// batchMethods returns the read endpoints callable in a batch by method name.
+func (s *Server) batchMethods() map[string]batchMethod {
+	return map[string]batchMethod{
+		"user":        {PathParams: []string{"id"}, Handler: s.GetUser},
+		"history":     {PathParams: []string{"id"}, Handler: s.GetHistory},
+		"swaps":       {PathParams: []string{"id"}, Handler: s.GetSwaps},
+		"positions":   {PathParams: []string{"id"}, Handler: s.GetPositions},
+		"leaderboard": {Handler: s.GetLeaderboard},
+		"rank":        {PathParams: []string{"address"}, Handler: s.GetLeaderboardRank},
+	}
+}
+
+// PostBatch executes an array of read operations concurrently and responds with their outcomes
+// in the same order, so a client fetches a page's data in one roundtrip. An operation failing
+// does not fail the batch: its outcome holds the error its endpoint would have responded with.
+func (s *Server) PostBatch(w http.ResponseWriter, r *http.Request) {
+	var requests []batchRequest
+	if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+		render.Render(w, r, &errorResponse{Error: "invalid batch: " + err.Error(), HTTPStatusCode: http.StatusBadRequest})
+		return
+	}
+	if len(requests) == 0 || len(requests) > maxBatchSize {
+		render.Render(w, r, &errorResponse{Error: fmt.Sprintf("a batch must hold between 1 and %d operations", maxBatchSize), HTTPStatusCode: http.StatusBadRequest})
+		return
+	}
+
+	methods := s.batchMethods()
+	responses := make([]batchResponse, len(requests))
+	semaphore := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req batchRequest) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() {
+				<-semaphore
+				// The recovery middleware does not cover this goroutine
+				if rec := recover(); rec != nil {
+					logger.Errorf("Panic in batch method %s: %v", req.Method, rec)
+					responses[i] = batchError(req.ID, http.StatusInternalServerError, "internal error")
+				}
+			}()
+			responses[i] = executeBatchRequest(r.Context(), methods, req)
+		}(i, req)
+	}
+	wg.Wait()
+
+	render.JSON(w, r, responses)
+}
+
+// executeBatchRequest runs the handler of an operation on a request built from its parameters.
+func executeBatchRequest(ctx context.Context, methods map[string]batchMethod, req batchRequest) batchResponse {
+	method, exists := methods[req.Method]
+	if !exists {
+		names := make([]string, 0, len(methods))
+		for name := range methods {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return batchError(req.ID, http.StatusBadRequest, fmt.Sprintf("unknown method %q, must be one of %s", req.Method, strings.Join(names, ", ")))
+	}
+
+	routeCtx := chi.NewRouteContext()
+	query := url.Values{}
+	for name, value := range req.Params {
+		query.Set(name, fmt.Sprint(value))
+	}
+	for _, name := range method.PathParams {
+		routeCtx.URLParams.Add(name, query.Get(name))
+		query.Del(name)
+	}
+
+	sub, err := http.NewRequestWithContext(context.WithValue(ctx, chi.RouteCtxKey, routeCtx), http.MethodGet, "/?"+query.Encode(), nil)
+	if err != nil {
+		return batchError(req.ID, http.StatusBadRequest, err.Error())
+	}
+	buffered := &bufferedResponse{header: make(http.Header)}
+	method.Handler(buffered, sub)
+	if buffered.status == 0 {
+		buffered.status = http.StatusOK
+	}
+
+	res := batchResponse{ID: req.ID, Status: buffered.status}
+	body := json.RawMessage(buffered.body.Bytes())
+	if !json.Valid(body) {
+		// e.g. an error written by http.Error
+		body, _ = json.Marshal(errorResponse{Error: strings.TrimSpace(buffered.body.String())})
+	}
+	if buffered.status == http.StatusOK {
+		res.Result = body
+	} else {
+		res.Error = body
+	}
+	return res
+}
+
+func batchError(id interface{}, status int, message string) batchResponse {
+	body, _ := json.Marshal(errorResponse{Error: message})
+	return batchResponse{ID: id, Status: status, Error: body}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestPostBatch tests that the operations of a batch are executed with their parameters and
+// answered in order, each with the status and body of its endpoint.
+func TestPostBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{Service: mockService}
+
+	address := "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
+	mockService.EXPECT().GetLeaderboardPage(gomock.Any(), "", 2).Return([]model.User{{Address: address, TotalPoints: model.NewDecimalFromFloat(40)}}, "next", nil)
+	mockService.EXPECT().GetUserRank(gomock.Any(), address).Return(nil, model.ErrUserNotFound)
+
+	r := chi.NewRouter()
+	r.Post("/batch", server.PostBatch)
+	body := `[
+		{"id": 1, "method": "leaderboard", "params": {"limit": 2}},
+		{"id": "rank", "method": "rank", "params": {"address": "0x` + strings.ToUpper(address[2:4]) + address[4:] + `"}},
+		{"id": 3, "method": "rank", "params": {"address": "0x1234"}},
+		{"id": 4, "method": "drop"}
+	]`
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responses []struct {
+		ID     interface{}     `json:"id"`
+		Status int             `json:"status"`
+		Result json.RawMessage `json:"result"`
+		Error  struct {
+			Error string `json:"error"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &responses))
+	if !assert.Len(t, responses, 4) {
+		return
+	}
+
+	assert.Equal(t, float64(1), responses[0].ID)
+	assert.Equal(t, http.StatusOK, responses[0].Status)
+	assert.JSONEq(t, `{"users": [{"address": "`+address+`", "points": 40}], "next_cursor": "next"}`, string(responses[0].Result))
+
+	assert.Equal(t, "rank", responses[1].ID)
+	assert.Equal(t, http.StatusNotFound, responses[1].Status, "the address is lowercased")
+	assert.Equal(t, "user not found", responses[1].Error.Error)
+
+	assert.Equal(t, http.StatusBadRequest, responses[2].Status)
+	assert.Equal(t, "invalid parameters: address", responses[2].Error.Error)
+
+	assert.Equal(t, http.StatusBadRequest, responses[3].Status)
+	assert.Contains(t, responses[3].Error.Error, `unknown method "drop"`)
+}
+
+// TestPostBatch_Invalid tests that malformed, empty and oversized batches are rejected.
+func TestPostBatch_Invalid(t *testing.T) {
+	server := Server{Service: mocks.NewMockService(gomock.NewController(t))}
+
+	for _, body := range []string{`{"method": "user"}`, `[]`, "[" + strings.Repeat(`{"method": "leaderboard"},`, maxBatchSize) + `{"method": "leaderboard"}]`} {
+		rr := httptest.NewRecorder()
+		server.PostBatch(rr, httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
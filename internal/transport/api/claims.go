@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// claimRequest is the body of a claim. Signature is the user's personal_sign signature of
+// service.ClaimMessage(id, timestamp).
+type claimRequest struct {
+	Timestamp int64  `json:"timestamp"`
+	Signature string `json:"signature"`
+}
+
+// PostClaim handles claiming every claimable point of a user.
+func (s *Server) PostClaim(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	id := v.pathAddress("id")
+	var req claimRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		v.fail("body", "must be a JSON object with timestamp and signature")
+	} else if req.Signature == "" {
+		v.fail("signature", "is required")
+	}
+	if v.check(w) {
+		return
+	}
+
+	claim, err := s.projectService(r).ClaimPoints(r.Context(), id, req.Timestamp, req.Signature)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, claim)
+}
+
+// GetClaimProof handles retrieving an address's proof in the latest Merkle distribution.
+func (s *Server) GetClaimProof(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	address := v.pathAddress("address")
+	if v.check(w) {
+		return
+	}
+
+	proof, err := s.projectService(r).GetClaimProof(r.Context(), address)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, proof)
+}
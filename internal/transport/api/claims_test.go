@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestPostClaim tests the status of a claim for each outcome of the service.
+func TestPostClaim(t *testing.T) {
+	const userID = "0x00000000000000000000000000000000000000a1"
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "claimed", body: `{"timestamp":1728000000,"signature":"0xsig"}`, wantStatus: http.StatusOK, wantBody: `"points":42`},
+		{name: "invalid signature", body: `{"timestamp":1728000000,"signature":"0xsig"}`, serviceErr: model.ErrInvalidClaimSignature, wantStatus: http.StatusUnauthorized},
+		{name: "nothing to claim", body: `{"timestamp":1728000000,"signature":"0xsig"}`, serviceErr: model.ErrNothingToClaim, wantStatus: http.StatusConflict},
+		{name: "missing signature", body: `{"timestamp":1728000000}`, wantStatus: http.StatusBadRequest, wantBody: `"field":"signature"`},
+		{name: "malformed body", body: `timestamp`, wantStatus: http.StatusBadRequest, wantBody: `"field":"body"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockService(ctrl)
+			server := Server{Service: mockService}
+
+			if tt.wantStatus != http.StatusBadRequest {
+				claim := &model.PointClaim{ID: 1, Address: userID, Points: model.NewDecimalFromFloat(42)}
+				if tt.serviceErr != nil {
+					claim = nil
+				}
+				mockService.EXPECT().ClaimPoints(gomock.Any(), userID, int64(1728000000), "0xsig").Return(claim, tt.serviceErr)
+			}
+
+			router := chi.NewRouter()
+			router.Post("/user/{id}/claim", server.PostClaim)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/user/"+userID+"/claim", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+// TestGetClaimProof tests that the proof of the latest distribution is served, and a 404 without one.
+func TestGetClaimProof(t *testing.T) {
+	const address = "0x00000000000000000000000000000000000000a1"
+
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{Service: mockService}
+	router := chi.NewRouter()
+	router.Get("/claims/{address}/proof", server.GetClaimProof)
+
+	mockService.EXPECT().GetClaimProof(gomock.Any(), address).Return(&model.MerkleProof{
+		DistributionID: 2,
+		Index:          4,
+		Address:        address,
+		Amount:         "12500000000000000000",
+		Proof:          []string{"0x01"},
+	}, nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/claims/"+address+"/proof", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"amount":"12500000000000000000","proof":["0x01"]`)
+
+	mockService.EXPECT().GetClaimProof(gomock.Any(), address).Return(nil, model.ErrProofNotFound)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/claims/"+address+"/proof", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/claims/0x1234/proof", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"hw/internal/model"
+	"hw/pkg/micro-tree/http/middleware"
+
+	"github.com/go-chi/render"
+)
+
+// retryAfterSeconds is the Retry-After sent with a 503 for an unavailable dependency.
+const retryAfterSeconds = "1"
+
+// errorStatus returns the HTTP status of an error returned by the service, from its model kind.
+func errorStatus(err error) int {
+	switch model.KindOf(err) {
+	case model.ErrNotFound:
+		return http.StatusNotFound
+	case model.ErrConflict:
+		return http.StatusConflict
+	case model.ErrInvalid:
+		return http.StatusBadRequest
+	case model.ErrUnauthenticated:
+		return http.StatusUnauthorized
+	case model.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// renderError responds with an error returned by the service. Errors of a kind get their status;
+// other errors are internal, logged and answered with 500. Retryable errors are flagged so clients
+// can tell them from permanent ones.
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError || errors.Is(err, model.ErrUnavailable) {
+		middleware.HTTPErrorLogging(w, r, err)
+	}
+	retryable := model.Retryable(err)
+	if retryable {
+		w.Header().Set("Retry-After", retryAfterSeconds)
+	}
+	render.Render(w, r, &errorResponse{Error: err.Error(), Retryable: retryable, HTTPStatusCode: status})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRenderError tests the status of each error kind and that only unavailable errors are retryable.
+func TestRenderError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{name: "not found", err: model.ErrUserNotFound, wantStatus: http.StatusNotFound},
+		{name: "conflict", err: fmt.Errorf("failed to claim: %w", model.ErrNothingToClaim), wantStatus: http.StatusConflict},
+		{name: "invalid", err: fmt.Errorf("%w: bad base64", model.ErrInvalidCursor), wantStatus: http.StatusBadRequest},
+		{name: "unauthenticated", err: model.ErrInvalidSession, wantStatus: http.StatusUnauthorized},
+		{
+			name: "unavailable", err: model.WrapError(model.ErrUnavailable, errors.New("connection refused")),
+			wantStatus: http.StatusServiceUnavailable, wantRetryable: true,
+		},
+		{name: "internal", err: errors.New("boom"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			renderError(rr, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantRetryable, rr.Header().Get("Retry-After") != "")
+			assert.Equal(t, tt.wantRetryable, strings.Contains(rr.Body.String(), `"retryable":true`))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestConditionalGET tests that the leaderboard is tagged with an ETag, that a request holding
+// the current ETag gets a 304 without body, and that a changed leaderboard gets a new ETag.
+func TestConditionalGET(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	router := chi.NewRouter()
+	ConfigureHTTPServer(router, Server{Logger: zap.NewNop(), Service: mockService})
+	mockService.EXPECT().AuthenticateAPIKey(gomock.Any(), "hwk_valid").Return(&model.Project{ID: 3, Slug: "acme"}, nil).AnyTimes()
+	mockService.EXPECT().ForProject(3).Return(mockService).AnyTimes()
+
+	users := []model.User{{Address: "0xuser1", TotalPoints: model.NewDecimalFromFloat(150)}}
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/leaderboard", nil)
+		req.Header.Set(apiKeyHeader, "hwk_valid")
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	mockService.EXPECT().GetLeaderboard(gomock.Any()).Return(users, nil).Times(4)
+	first := get("")
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Contains(t, first.Body.String(), "0xuser1")
+
+	for _, ifNoneMatch := range []string{etag, `"stale", W/` + etag, "*"} {
+		rr := get(ifNoneMatch)
+		assert.Equal(t, http.StatusNotModified, rr.Code, ifNoneMatch)
+		assert.Equal(t, etag, rr.Header().Get("ETag"))
+		assert.Empty(t, rr.Body.String())
+	}
+
+	users = append(users, model.User{Address: "0xuser2", TotalPoints: model.NewDecimalFromFloat(10)})
+	mockService.EXPECT().GetLeaderboard(gomock.Any()).Return(users, nil)
+	changed := get(etag)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+	assert.Contains(t, changed.Body.String(), "0xuser2")
+}
+
+// TestConditionalGET_Error tests that errors are neither tagged nor answered with a 304.
+func TestConditionalGET_Error(t *testing.T) {
+	handler := conditionalGET(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renderError(w, r, errors.New("database down"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/user/0x1", nil)
+	req.Header.Set("If-None-Match", "*")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Empty(t, rr.Header().Get("ETag"))
+	assert.Contains(t, rr.Body.String(), "error")
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// GetGasLeaderboard handles retrieving the accounts that paid the most gas on a network, given by
+// the network query parameter, up to limit accounts.
+func (s *Server) GetGasLeaderboard(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	network := v.queryNetwork("network")
+	if network == "" {
+		network = defaultNetwork
+	}
+	limit := v.queryInt("limit", defaultPageLimit, 1, maxPageLimit)
+	if v.check(w) {
+		return
+	}
+
+	totals, err := s.Service.GetGasLeaderboard(r.Context(), network, limit)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, totals)
+}
+
+// GetUserGas handles retrieving the gas a user paid for indexed transactions on each network.
+func (s *Server) GetUserGas(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	id := v.pathAddress("id")
+	if v.check(w) {
+		return
+	}
+
+	totals, err := s.Service.GetUserGasStats(r.Context(), id)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, totals)
+}
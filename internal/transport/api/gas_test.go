@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetGasLeaderboard tests that the gas leaderboard of a network is served, of mainnet by default.
+func TestGetGasLeaderboard(t *testing.T) {
+	totals := []model.GasTotal{
+		{Network: "mainnet", Account: "0x00000000000000000000000000000000000000a1", Transactions: 3, GasUsed: model.NewDecimalFromFloat(300000), Fee: model.NewDecimalFromFloat(6e15)},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		network string
+		limit   int
+		totals  []model.GasTotal
+		err     error
+		code    int
+	}{
+		{name: "default network", network: "mainnet", limit: defaultPageLimit, totals: totals, code: http.StatusOK},
+		{name: "one network", query: "?network=base&limit=10", network: "base", limit: 10, totals: []model.GasTotal{}, code: http.StatusOK},
+		{name: "invalid limit", query: "?limit=0", code: http.StatusBadRequest},
+		{name: "service error", network: "mainnet", limit: defaultPageLimit, err: errors.New("db down"), code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockService(ctrl)
+			server := Server{
+				Service: mockService,
+			}
+
+			if tt.network != "" {
+				mockService.EXPECT().GetGasLeaderboard(gomock.Any(), tt.network, tt.limit).Return(tt.totals, tt.err)
+			}
+
+			r := chi.NewRouter()
+			r.Get("/leaderboard/gas", server.GetGasLeaderboard)
+
+			req, err := http.NewRequest("GET", "/leaderboard/gas"+tt.query, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+			if tt.totals != nil {
+				var resp []model.GasTotal
+				assert.NoError(t, render.DecodeJSON(rr.Body, &resp))
+				assert.Equal(t, tt.totals, resp)
+			}
+		})
+	}
+}
+
+// TestGetUserGas tests that the gas totals of a user are served per network.
+func TestGetUserGas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := "0x00000000000000000000000000000000000000a1"
+	totals := []model.GasTotal{{Network: "base", Account: userID, Transactions: 1, GasUsed: model.NewDecimalFromFloat(21000), Fee: model.NewDecimalFromFloat(21e12)}}
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+	mockService.EXPECT().GetUserGasStats(gomock.Any(), userID).Return(totals, nil)
+
+	r := chi.NewRouter()
+	r.Get("/user/{id}/gas", server.GetUserGas)
+
+	req, err := http.NewRequest("GET", "/user/"+userID+"/gas", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []model.GasTotal
+	assert.NoError(t, render.DecodeJSON(rr.Body, &resp))
+	assert.Equal(t, totals, resp)
+
+	req, err = http.NewRequest("GET", "/user/not-an-address/gas", nil)
+	assert.NoError(t, err)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -3,58 +3,121 @@ package api
 import (
 	"net/http"
 
-	"hw/pkg/micro-tree/http/middleware"
+	"hw/internal/model"
+	"hw/internal/service"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
 
-// historyTask represents a single task with a description and points.
+// historyTask represents a single task with a description and points, and their provenance when recorded.
 type historyTask struct {
-	Description string  `json:"description"`
-	Points      float64 `json:"points"`
-	CreatedAt   string  `json:"created_at"`
+	Description    string                `json:"description"`
+	Points         model.Decimal         `json:"points"`
+	CreatedAt      string                `json:"created_at"`
+	Network        string                `json:"network,omitempty"`
+	TokenURL       string                `json:"token_url,omitempty"`
+	Metadata       *model.PointsMetadata `json:"metadata,omitempty"`
+	TransactionURL string                `json:"transaction_url,omitempty"` // of the swap earning the points
 }
 
 // historyResponse structures the JSON response with tasks categorized by tokens.
 type historyResponse struct {
-	Tasks map[string][]historyTask `json:"tasks"`
+	Tasks      map[string][]historyTask `json:"tasks"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
 }
 
 // GetHistory handles fetching the user's history.
+// When a limit or cursor query parameter is given, one keyset page of the history of a token is returned instead.
 func (s Server) GetHistory(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
+	if isPaginated(r) {
+		s.getHistoryPage(w, r)
+		return
+	}
+
+	v := newValidator(r)
+	id := v.pathAddress("id")
+	if v.check(w) {
+		return
+	}
 
 	res := &historyResponse{
 		Tasks: make(map[string][]historyTask),
 	}
 
 	// Get user swap summary
-	swapSummary, err := s.Service.GetUserSwapSummary(r.Context(), id)
+	swapSummary, err := s.projectService(r).GetUserSwapSummary(r.Context(), id)
 	if err != nil {
-		middleware.HTTPErrorLogging(w, r, err)
-		render.Render(w, r, &errorResponse{Error: err.Error()})
+		renderError(w, r, err)
+		return
+	}
+
+	if len(swapSummary) == 0 {
+		render.JSON(w, r, res)
 		return
 	}
 
-	// Iterate over each token in swap summary
+	// Get the points history of every token in swap summary at once
+	tokens := make([]string, 0, len(swapSummary))
 	for token := range swapSummary {
-		pointsHistory, err := s.Service.GetPointsHistory(r.Context(), id, token)
-		if err != nil {
-			middleware.HTTPErrorLogging(w, r, err)
-			// handleError(w, http.StatusInternalServerError, err)
-			render.Render(w, r, &errorResponse{Error: err.Error()})
-			return
-		}
+		tokens = append(tokens, token)
+	}
+	pointsHistory, err := s.projectService(r).GetPointsHistoryByTokens(r.Context(), id, tokens, "")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
 
-		for _, points := range pointsHistory {
-			res.Tasks[token] = append(res.Tasks[token], historyTask{
-				Description: points.Description,
-				Points:      points.Points,
-				CreatedAt:   points.CreatedAt.Format("2006-01-02 15:04:05"),
-			})
+	for token, history := range pointsHistory {
+		for _, points := range history {
+			res.Tasks[token] = append(res.Tasks[token], newHistoryTask(points))
 		}
 	}
 
 	render.JSON(w, r, res)
 }
+
+// getHistoryPage responds with one page of the user's history of the token query parameter.
+func (s Server) getHistoryPage(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	id := v.pathAddress("id")
+	token := v.queryAddress("token")
+	if token == "" {
+		v.fail("token", "is required with limit or cursor")
+	}
+	params := v.pageParams()
+	if v.check(w) {
+		return
+	}
+
+	history, next, err := s.projectService(r).GetPointsHistoryPage(r.Context(), id, token, params.Cursor, params.Limit)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	res := &historyResponse{
+		Tasks:      map[string][]historyTask{token: make([]historyTask, 0, len(history))},
+		NextCursor: next,
+	}
+	for _, points := range history {
+		res.Tasks[token] = append(res.Tasks[token], newHistoryTask(points))
+	}
+
+	render.JSON(w, r, res)
+}
+
+// newHistoryTask returns the task of a points history entry.
+func newHistoryTask(points model.PointsHistory) historyTask {
+	task := historyTask{
+		Description: points.Description,
+		Points:      points.Points,
+		CreatedAt:   points.CreatedAt.Format("2006-01-02 15:04:05"),
+		Network:     points.Network,
+		TokenURL:    service.ExplorerAddressURL(points.Network, points.Token),
+		Metadata:    points.Metadata,
+	}
+	if points.Metadata != nil {
+		task.TransactionURL = service.ExplorerTxURL(points.Network, points.Metadata.TransactionHash)
+	}
+	return task
+}
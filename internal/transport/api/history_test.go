@@ -26,15 +26,22 @@ func TestGetHistory_Success(t *testing.T) {
 		Service: mockService,
 	}
 
-	userID := "user123"
+	userID := "0x00000000000000000000000000000000000000a1"
 	token := "tokenABC"
-	swapSummary := map[string]float64{
-		token: 100.0,
+	swapSummary := map[string]model.Decimal{
+		token: model.NewDecimalFromFloat(100.0),
 	}
 	pointsHistory := []model.PointsHistory{
 		{
 			Description: "Task 1",
-			Points:      10.5,
+			Points:      model.NewDecimalFromFloat(10.5),
+			CreatedAt:   time.Now(),
+		},
+		{
+			Network:     "mainnet",
+			Description: "onboarding_task",
+			Points:      model.NewDecimalFromFloat(150),
+			Metadata:    &model.PointsMetadata{Campaign: "onboarding", TransactionHash: "0xabc", BlockNumber: 20933200},
 			CreatedAt:   time.Now(),
 		},
 	}
@@ -46,8 +53,8 @@ func TestGetHistory_Success(t *testing.T) {
 
 	mockService.
 		EXPECT().
-		GetPointsHistory(gomock.Any(), userID, token).
-		Return(pointsHistory, nil)
+		GetPointsHistoryByTokens(gomock.Any(), userID, []string{token}, "").
+		Return(map[string][]model.PointsHistory{token: pointsHistory}, nil)
 
 	r := chi.NewRouter()
 	r.Get("/history/{id}", server.GetHistory)
@@ -64,9 +71,12 @@ func TestGetHistory_Success(t *testing.T) {
 	err = json.NewDecoder(rr.Body).Decode(&response)
 	assert.NoError(t, err)
 	assert.Contains(t, response.Tasks, token)
-	assert.Equal(t, 1, len(response.Tasks[token]))
+	assert.Equal(t, 2, len(response.Tasks[token]))
 	assert.Equal(t, "Task 1", response.Tasks[token][0].Description)
-	assert.Equal(t, 10.5, response.Tasks[token][0].Points)
+	assert.Equal(t, model.NewDecimalFromFloat(10.5), response.Tasks[token][0].Points)
+	assert.Nil(t, response.Tasks[token][0].Metadata)
+	assert.Equal(t, pointsHistory[1].Metadata, response.Tasks[token][1].Metadata)
+	assert.Equal(t, "https://etherscan.io/tx/0xabc", response.Tasks[token][1].TransactionURL)
 }
 
 // TestGetHistory_NoTokens tests the scenario when the user has no swap summaries (i.e., no tokens).
@@ -79,8 +89,8 @@ func TestGetHistory_NoTokens(t *testing.T) {
 		Service: mockService,
 	}
 
-	userID := "user123"
-	swapSummary := map[string]float64{}
+	userID := "0x00000000000000000000000000000000000000a1"
+	swapSummary := map[string]model.Decimal{}
 
 	mockService.
 		EXPECT().
@@ -103,3 +113,77 @@ func TestGetHistory_NoTokens(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, response.Tasks)
 }
+
+// TestGetHistory_Page tests that a limit or cursor returns one page of the history of a token with the next cursor.
+func TestGetHistory_Page(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	userID := "0x00000000000000000000000000000000000000a1"
+	token := "0x00000000000000000000000000000000000000b2"
+	pointsHistory := []model.PointsHistory{
+		{
+			Network:     "mainnet",
+			Token:       token,
+			Description: "onboarding_task",
+			Points:      model.NewDecimalFromFloat(150),
+			Metadata:    &model.PointsMetadata{Campaign: "onboarding", TransactionHash: "0xabc", BlockNumber: 20933200},
+			CreatedAt:   time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	mockService.EXPECT().GetPointsHistoryPage(gomock.Any(), userID, token, "abc", 10).Return(pointsHistory, "next", nil)
+
+	r := chi.NewRouter()
+	r.Get("/user/{id}/history", server.GetHistory)
+
+	req, err := http.NewRequest("GET", "/user/"+userID+"/history?token="+token+"&cursor=abc&limit=10", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response historyResponse
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "next", response.NextCursor)
+	assert.Len(t, response.Tasks[token], 1)
+	assert.Equal(t, "onboarding_task", response.Tasks[token][0].Description)
+	assert.Equal(t, "2024-10-02 08:00:00", response.Tasks[token][0].CreatedAt)
+	assert.Equal(t, "https://etherscan.io/tx/0xabc", response.Tasks[token][0].TransactionURL)
+}
+
+// TestGetHistory_PageInvalid tests that a page request without a token or with an invalid cursor is rejected.
+func TestGetHistory_PageInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	userID := "0x00000000000000000000000000000000000000a1"
+	token := "0x00000000000000000000000000000000000000b2"
+	mockService.EXPECT().GetPointsHistoryPage(gomock.Any(), userID, token, "bad", defaultPageLimit).Return(nil, "", model.ErrInvalidCursor)
+
+	r := chi.NewRouter()
+	r.Get("/user/{id}/history", server.GetHistory)
+
+	for _, query := range []string{"limit=10", "token=" + token + "&cursor=bad"} {
+		req, err := http.NewRequest("GET", "/user/"+userID+"/history?"+query, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"hw/internal/model"
+	"hw/pkg/logger"
+
+	"github.com/go-chi/render"
+)
+
+// idempotencyKeyHeader is the header carrying the idempotency key of a request.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotentReplayedHeader flags a response replayed from the first request with its idempotency key.
+const idempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotentBodySize is the size of the bodies read to hash idempotent requests.
+const maxIdempotentBodySize = 1 << 20
+
+// idempotent makes the requests of next sent with an Idempotency-Key header safe to retry: the
+// response of the first request with a key is stored and replayed to the requests retrying it,
+// flagged with Idempotent-Replayed, without running next again. A key is scoped to the project
+// of the request and bound to its method, path and body; reusing it for another request is
+// rejected with a 400, and retrying while the first request is processed with a 409. Server
+// errors are not stored, so the request can be retried. Requests without a key are served as is.
+func (s *Server) idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			render.Render(w, r, &errorResponse{Error: "failed to read body: " + err.Error(), HTTPStatusCode: http.StatusBadRequest})
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			render.Render(w, r, &errorResponse{Error: "body too large", HTTPStatusCode: http.StatusRequestEntityTooLarge})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := ""
+		if project := requestProject(r); project != nil {
+			scope = project.Slug
+		}
+		requestHash := hashRequest(r, body)
+		stored, err := s.Service.BeginIdempotentRequest(r.Context(), scope, key, requestHash)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+		if stored != nil {
+			replayResponse(w, stored)
+			return
+		}
+
+		buffered := &bufferedResponse{header: w.Header()}
+		next.ServeHTTP(buffered, r)
+		if buffered.status == 0 {
+			buffered.status = http.StatusOK
+		}
+
+		// The outcome is recorded even when the client has left, as it will retry
+		ctx := context.WithoutCancel(r.Context())
+		if buffered.status >= http.StatusInternalServerError {
+			if err := s.Service.AbortIdempotentRequest(ctx, scope, key); err != nil {
+				logger.Errorf("Failed to release idempotency key %q: %v", key, err)
+			}
+		} else {
+			response := &model.IdempotentResponse{Status: buffered.status, ContentType: w.Header().Get("Content-Type"), Body: buffered.body.Bytes()}
+			if err := s.Service.CompleteIdempotentRequest(ctx, scope, key, response); err != nil {
+				logger.Errorf("Failed to store the response of idempotency key %q: %v", key, err)
+			}
+		}
+		w.WriteHeader(buffered.status)
+		w.Write(buffered.body.Bytes())
+	})
+}
+
+// hashRequest returns the hex SHA-256 hash of the method, path and body of a request.
+func hashRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replayResponse responds with a stored response.
+func replayResponse(w http.ResponseWriter, stored *model.IdempotentResponse) {
+	if stored.ContentType != "" {
+		w.Header().Set("Content-Type", stored.ContentType)
+	}
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestIdempotent tests that the first request with an idempotency key is served and stored, its
+// retries replayed, and the key released after a server error.
+func TestIdempotent(t *testing.T) {
+	const body = `{"amount":1}`
+	requestHash := hashRequest(httptest.NewRequest(http.MethodPost, "/claim", nil), []byte(body))
+	stored := &model.IdempotentResponse{Status: http.StatusCreated, ContentType: "application/json", Body: []byte(`{"id":7}`)}
+
+	tests := []struct {
+		name         string
+		key          string
+		setup        func(*mocks.MockService)
+		handlerCalls int
+		wantStatus   int
+		wantBody     string
+		wantReplayed bool
+	}{
+		{
+			name:         "without key",
+			handlerCalls: 1,
+			wantStatus:   http.StatusCreated,
+			wantBody:     `{"id":7}`,
+		},
+		{
+			name: "first request",
+			key:  "key-1",
+			setup: func(m *mocks.MockService) {
+				m.EXPECT().BeginIdempotentRequest(gomock.Any(), "", "key-1", requestHash).Return(nil, nil)
+				m.EXPECT().CompleteIdempotentRequest(gomock.Any(), "", "key-1", stored).Return(nil)
+			},
+			handlerCalls: 1,
+			wantStatus:   http.StatusCreated,
+			wantBody:     `{"id":7}`,
+		},
+		{
+			name: "retry",
+			key:  "key-1",
+			setup: func(m *mocks.MockService) {
+				m.EXPECT().BeginIdempotentRequest(gomock.Any(), "", "key-1", requestHash).Return(stored, nil)
+			},
+			wantStatus:   http.StatusCreated,
+			wantBody:     `{"id":7}`,
+			wantReplayed: true,
+		},
+		{
+			name: "in progress",
+			key:  "key-1",
+			setup: func(m *mocks.MockService) {
+				m.EXPECT().BeginIdempotentRequest(gomock.Any(), "", "key-1", requestHash).Return(nil, model.ErrIdempotencyKeyInUse)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   "in progress",
+		},
+		{
+			name: "reused for another request",
+			key:  "key-1",
+			setup: func(m *mocks.MockService) {
+				m.EXPECT().BeginIdempotentRequest(gomock.Any(), "", "key-1", requestHash).Return(nil, model.ErrIdempotencyKeyReused)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "another request",
+		},
+		{
+			name: "server error",
+			key:  "key-2",
+			setup: func(m *mocks.MockService) {
+				m.EXPECT().BeginIdempotentRequest(gomock.Any(), "", "key-2", requestHash).Return(nil, nil)
+				m.EXPECT().AbortIdempotentRequest(gomock.Any(), "", "key-2").Return(nil)
+			},
+			handlerCalls: 1,
+			wantStatus:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockService(ctrl)
+			if tt.setup != nil {
+				tt.setup(mockService)
+			}
+			server := Server{Service: mockService}
+
+			calls := 0
+			handler := server.idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				received, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, body, string(received), "the body is passed on")
+				if tt.key == "key-2" {
+					http.Error(w, "failed", http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":7}`))
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/claim", strings.NewReader(body))
+			if tt.key != "" {
+				req.Header.Set(idempotencyKeyHeader, tt.key)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.handlerCalls, calls)
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantReplayed, rr.Header().Get(idempotentReplayedHeader) == "true")
+		})
+	}
+}
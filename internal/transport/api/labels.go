@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"hw/internal/model"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// maxLabelNameLength is the longest name of an address label.
+const maxLabelNameLength = 128
+
+// addressLabelRequest is the body of an address label update.
+type addressLabelRequest struct {
+	Name string `json:"name"`
+}
+
+// LabelsHandler serves listing, setting and removing address labels under /admin/labels. The
+// labels exclude addresses from the leaderboard and points distributions, so it is served by the
+// indexer admin server rather than with the public routes. Labels belong to a project, given by
+// the slug of the project query parameter of every request.
+func LabelsHandler(srv Server) http.Handler {
+	router := chi.NewRouter()
+	router.Use(srv.adminProject)
+	router.Get("/admin/labels", srv.GetAddressLabels)
+	router.Put("/admin/labels/{address}/{label}", srv.PutAddressLabel)
+	router.Delete("/admin/labels/{address}/{label}", srv.DeleteAddressLabel)
+	return router
+}
+
+// adminProject passes the project of the project query parameter of an admin request to next as
+// the project of the request, rejecting a request without one.
+func (s *Server) adminProject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := newValidator(r)
+		slug := r.URL.Query().Get("project")
+		if slug == "" {
+			v.fail("project", "is required")
+		}
+		if v.check(w) {
+			return
+		}
+
+		project, err := s.Service.GetProject(r.Context(), slug)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), projectKey{}, project)))
+	})
+}
+
+// pathLabel reads a path parameter holding one of model.AddressLabels.
+func (v *validator) pathLabel(name string) string {
+	label := chi.URLParam(v.r, name)
+	if !slices.Contains(model.AddressLabels, label) {
+		v.fail(name, "must be one of %s", strings.Join(model.AddressLabels, ", "))
+	}
+	return label
+}
+
+// GetAddressLabels handles listing the labelled addresses, optionally of the label query parameter.
+func (s *Server) GetAddressLabels(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	label := r.URL.Query().Get("label")
+	if label != "" && !slices.Contains(model.AddressLabels, label) {
+		v.fail("label", "must be one of %s", strings.Join(model.AddressLabels, ", "))
+	}
+	if v.check(w) {
+		return
+	}
+
+	labels, err := s.projectService(r).ListAddressLabels(r.Context(), label)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, labels)
+}
+
+// PutAddressLabel handles labelling an address, or renaming its label, by the admin operator of the request.
+func (s *Server) PutAddressLabel(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	address := v.pathAddress("address")
+	label := v.pathLabel("label")
+	var req addressLabelRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		v.fail("body", "must be a JSON object with a name")
+	} else if len(req.Name) > maxLabelNameLength {
+		v.fail("name", "must be at most %d characters", maxLabelNameLength)
+	}
+	if v.check(w) {
+		return
+	}
+
+	addressLabel := &model.AddressLabel{Address: address, Label: label, Name: req.Name, UpdatedBy: AdminActor(r.Context())}
+	if err := s.projectService(r).SetAddressLabel(r.Context(), addressLabel); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, addressLabel)
+}
+
+// DeleteAddressLabel handles removing a label from an address by the admin operator of the request.
+func (s *Server) DeleteAddressLabel(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	address := v.pathAddress("address")
+	label := v.pathLabel("label")
+	if v.check(w) {
+		return
+	}
+
+	if err := s.projectService(r).DeleteAddressLabel(r.Context(), address, label, AdminActor(r.Context())); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
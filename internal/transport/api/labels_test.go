@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestAddressLabels tests labelling an address, listing labels and removing a label through the
+// admin handler, by the operator of the admin token.
+func TestAddressLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	address := "0x7a250d5630b4cf539739df2c5dacb4c659f2488d"
+	mockService.EXPECT().GetProject(gomock.Any(), "acme").Return(&model.Project{ID: 3, Slug: "acme"}, nil).AnyTimes()
+	mockService.EXPECT().ForProject(3).Return(mockService).AnyTimes()
+	mockService.EXPECT().SetAddressLabel(gomock.Any(), &model.AddressLabel{Address: address, Label: model.LabelRouter, Name: "Uniswap V2: Router 2", UpdatedBy: "alice"}).Return(nil)
+	mockService.EXPECT().ListAddressLabels(gomock.Any(), model.LabelRouter).Return([]model.AddressLabel{{Address: address, Label: model.LabelRouter, Name: "Uniswap V2: Router 2"}}, nil)
+	mockService.EXPECT().DeleteAddressLabel(gomock.Any(), address, model.LabelRouter, "alice").Return(nil)
+	mockService.EXPECT().DeleteAddressLabel(gomock.Any(), address, model.LabelExchange, "alice").Return(model.ErrAddressLabelNotFound)
+
+	token := strings.Repeat("a", 32)
+	r := RequireAdminToken([]string{"alice:" + token}, LabelsHandler(server))
+	request := func(method, target, body string) *http.Request {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req
+	}
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request("PUT", "/admin/labels/"+address+"/router?project=acme", `{"name": "Uniswap V2: Router 2"}`))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"label":"router"`)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, request("PUT", "/admin/labels/"+address+"/whale?project=acme", `{"name": "Whale"}`))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"label"`)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/admin/labels?project=acme&label=router", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), address)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, request("DELETE", "/admin/labels/"+address+"/router?project=acme", ""))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, request("DELETE", "/admin/labels/"+address+"/exchange?project=acme", ""))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+// TestAddressLabels_Project tests that the admin labels require a known project.
+func TestAddressLabels_Project(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	mockService.EXPECT().GetProject(gomock.Any(), "unknown").Return(nil, model.ErrProjectNotFound)
+	r := LabelsHandler(Server{Service: mockService})
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/admin/labels", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"project"`)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/admin/labels?project=unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+// TestAddressLabels_NotPublic tests that the public routes do not serve the address labels.
+func TestAddressLabels_NotPublic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	router := setupTestRouter(Server{Logger: zap.NewNop(), Service: mocks.NewMockService(ctrl)})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PUT", "/admin/labels/0x7a250d5630b4cf539739df2c5dacb4c659f2488d/router", strings.NewReader(`{"name": "Router"}`)))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"sort"
 
+	"hw/internal/model"
+
 	"github.com/go-chi/render"
 	"github.com/jackc/pgx/v5"
 	"github.com/pkg/errors"
@@ -11,19 +13,26 @@ import (
 
 // UserPoints represents a user's address and their points.
 type UserPoints struct {
-	Address string  `json:"address"`
-	Points  float64 `json:"points"`
+	Address string        `json:"address"`
+	Points  model.Decimal `json:"points"`
 }
 
 // LeaderboardResponse represents the response structure for the leaderboard.
 type LeaderboardResponse struct {
-	Users []UserPoints `json:"users"`
+	Users      []UserPoints `json:"users"`
+	NextCursor string       `json:"next_cursor,omitempty"`
 }
 
 // GetLeaderboard retrieves the leaderboard data and returns it as JSON.
+// When a limit or cursor query parameter is given, one keyset page is returned instead.
 func (s *Server) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if isPaginated(r) {
+		s.getLeaderboardPage(w, r)
+		return
+	}
+
 	// Fetch users from the domain
-	users, err := s.Service.GetLeaderboard(r.Context())
+	users, err := s.projectService(r).GetLeaderboard(r.Context())
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			// Return empty response if no rows are found
@@ -53,9 +62,54 @@ func (s *Server) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 
 	// Sort users by points in descending order
 	sort.Slice(res.Users, func(i, j int) bool {
-		return res.Users[i].Points > res.Users[j].Points
+		return res.Users[i].Points.GreaterThan(res.Users[j].Points.Decimal)
 	})
 
 	// Respond with the sorted leaderboard
 	render.JSON(w, r, res)
 }
+
+// getLeaderboardPage responds with one page of the leaderboard.
+func (s *Server) getLeaderboardPage(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	params := v.pageParams()
+	if v.check(w) {
+		return
+	}
+
+	users, next, err := s.projectService(r).GetLeaderboardPage(r.Context(), params.Cursor, params.Limit)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	res := LeaderboardResponse{
+		Users:      make([]UserPoints, 0, len(users)),
+		NextCursor: next,
+	}
+	for _, user := range users {
+		res.Users = append(res.Users, UserPoints{
+			Address: user.Address,
+			Points:  user.TotalPoints,
+		})
+	}
+
+	render.JSON(w, r, res)
+}
+
+// GetLeaderboardRank retrieves a user's rank and points on the leaderboard.
+func (s *Server) GetLeaderboardRank(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	address := v.pathAddress("address")
+	if v.check(w) {
+		return
+	}
+
+	rank, err := s.projectService(r).GetUserRank(r.Context(), address)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, rank)
+}
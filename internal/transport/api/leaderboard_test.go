@@ -31,15 +31,15 @@ func TestGetLeaderboard_Success(t *testing.T) {
 	users := []model.User{
 		{
 			Address:     "0xUser1",
-			TotalPoints: 150.0,
+			TotalPoints: model.NewDecimalFromFloat(150.0),
 		},
 		{
 			Address:     "0xUser2",
-			TotalPoints: 200.0,
+			TotalPoints: model.NewDecimalFromFloat(200.0),
 		},
 		{
 			Address:     "0xUser3",
-			TotalPoints: 100.0,
+			TotalPoints: model.NewDecimalFromFloat(100.0),
 		},
 	}
 
@@ -68,9 +68,9 @@ func TestGetLeaderboard_Success(t *testing.T) {
 	// Verify that user data is sorted in descending order of points
 	expected := LeaderboardResponse{
 		Users: []UserPoints{
-			{Address: "0xUser2", Points: 200.0},
-			{Address: "0xUser1", Points: 150.0},
-			{Address: "0xUser3", Points: 100.0},
+			{Address: "0xUser2", Points: model.NewDecimalFromFloat(200.0)},
+			{Address: "0xUser1", Points: model.NewDecimalFromFloat(150.0)},
+			{Address: "0xUser3", Points: model.NewDecimalFromFloat(100.0)},
 		},
 	}
 	assert.Equal(t, expected, response)
@@ -158,19 +158,19 @@ func TestGetLeaderboard_Sorting(t *testing.T) {
 	users := []model.User{
 		{
 			Address:     "0xUserA",
-			TotalPoints: 120.0,
+			TotalPoints: model.NewDecimalFromFloat(120.0),
 		},
 		{
 			Address:     "0xUserB",
-			TotalPoints: 300.0,
+			TotalPoints: model.NewDecimalFromFloat(300.0),
 		},
 		{
 			Address:     "0xUserC",
-			TotalPoints: 50.0,
+			TotalPoints: model.NewDecimalFromFloat(50.0),
 		},
 		{
 			Address:     "0xUserD",
-			TotalPoints: 200.0,
+			TotalPoints: model.NewDecimalFromFloat(200.0),
 		},
 	}
 
@@ -199,11 +199,44 @@ func TestGetLeaderboard_Sorting(t *testing.T) {
 	// Define the expected sorted result
 	expected := LeaderboardResponse{
 		Users: []UserPoints{
-			{Address: "0xUserB", Points: 300.0},
-			{Address: "0xUserD", Points: 200.0},
-			{Address: "0xUserA", Points: 120.0},
-			{Address: "0xUserC", Points: 50.0},
+			{Address: "0xUserB", Points: model.NewDecimalFromFloat(300.0)},
+			{Address: "0xUserD", Points: model.NewDecimalFromFloat(200.0)},
+			{Address: "0xUserA", Points: model.NewDecimalFromFloat(120.0)},
+			{Address: "0xUserC", Points: model.NewDecimalFromFloat(50.0)},
 		},
 	}
 	assert.Equal(t, expected, response)
 }
+
+// TestGetLeaderboardRank tests the rank endpoint for known and unknown users.
+func TestGetLeaderboardRank(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	known := "0x00000000000000000000000000000000000000a1"
+	unknown := "0x00000000000000000000000000000000000000a2"
+	mockService.EXPECT().GetUserRank(gomock.Any(), known).Return(&model.LeaderboardRank{Address: known, Points: model.NewDecimalFromFloat(42.5), Rank: 3}, nil)
+	mockService.EXPECT().GetUserRank(gomock.Any(), unknown).Return(nil, model.ErrUserNotFound)
+
+	r := chi.NewRouter()
+	r.Get("/leaderboard/rank/{address}", server.GetLeaderboardRank)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/leaderboard/rank/"+known, nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var rank model.LeaderboardRank
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&rank))
+	assert.Equal(t, int64(3), rank.Rank)
+	assert.Equal(t, model.NewDecimalFromFloat(42.5), rank.Points)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/leaderboard/rank/"+unknown, nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,457 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/go-chi/render"
+)
+
+// param describes a path or query parameter of a route.
+type param struct {
+	Name        string
+	In          string // "path", "query" or "header"
+	Type        string // "string" or "integer"
+	Required    bool
+	Description string
+}
+
+// route is a documented REST endpoint. The same table registers the handlers and builds
+// the OpenAPI document, so the spec cannot drift from the router.
+type route struct {
+	Method     string
+	Path       string // chi pattern, e.g. /user/{id}
+	Summary    string
+	Tag        string
+	Params     []param
+	Body       interface{} // zero value of the request body, if any
+	Response   interface{} // zero value of the 200 response body, nil for a 204 without body
+	Handler    http.Handler
+	Auth       bool // requires a session token from /auth/verify
+	Project    bool // requires an API key of a project in the X-API-Key header
+	ETag       bool // tags responses with an ETag and answers If-None-Match with a 304 when unchanged
+	Stream     bool // responds with Server-Sent Events whose data is Response
+	WebSocket  bool // upgrades to a WebSocket sending Response messages
+	Idempotent bool // replays the stored response to the retries of a request sent with an Idempotency-Key header
+}
+
+var (
+	pageParamsDoc = []param{
+		{Name: "limit", In: "query", Type: "integer", Description: "Page size (1-500); enables keyset pagination"},
+		{Name: "cursor", In: "query", Type: "string", Description: "Cursor returned as next_cursor by the previous page"},
+	}
+	userIDParam         = param{Name: "id", In: "path", Type: "string", Required: true, Description: "User address"}
+	idempotencyKeyParam = param{Name: idempotencyKeyHeader, In: "header", Type: "string", Description: "Unique key of the request, e.g. a UUID; retries sent with it within 24 hours get the response of the first request"}
+	ifNoneMatchParam    = param{Name: "If-None-Match", In: "header", Type: "string", Description: "ETag of a previous response; a 304 without body is returned while it is unchanged"}
+)
+
+// routes returns the documented REST endpoints of the server.
+func (srv Server) routes() []route {
+	return []route{
+		{
+			Method: http.MethodGet, Path: "/ping", Summary: "Health check", Tag: "system",
+			Response: "pong",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("pong"))
+			}),
+		},
+		{
+			Method: http.MethodGet, Path: "/user/{id}", Summary: "Get a user's swap volume and points", Tag: "users",
+			Params: []param{
+				userIDParam,
+				{Name: "network", In: "query", Type: "string", Description: "Restrict the view to one network"},
+				ifNoneMatchParam,
+			},
+			Response: model.UserOverview{}, Handler: http.HandlerFunc(srv.GetUser), ETag: true, Project: true,
+		},
+		{
+			Method: http.MethodPost, Path: "/user/{id}/claim", Summary: "Claim a user's claimable points with a signed message", Tag: "users",
+			Params: []param{userIDParam, idempotencyKeyParam},
+			Body:   claimRequest{}, Response: model.PointClaim{}, Handler: http.HandlerFunc(srv.PostClaim), Idempotent: true, Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/user/{id}/history", Summary: "Get a user's points history", Tag: "users",
+			Params: append([]param{
+				userIDParam,
+				{Name: "token", In: "query", Type: "string", Description: "Token whose history is paginated; required with limit or cursor"},
+			}, pageParamsDoc...),
+			Response: historyResponse{}, Handler: http.HandlerFunc(srv.GetHistory), Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/user/{id}/swaps", Summary: "Get a page of a user's swap history", Tag: "users",
+			Params:   append([]param{userIDParam}, pageParamsDoc...),
+			Response: swapsResponse{}, Handler: http.HandlerFunc(srv.GetSwaps),
+		},
+		{
+			Method: http.MethodGet, Path: "/user/{id}/positions", Summary: "Get a user's open LP, stake and vault positions", Tag: "users",
+			Params: []param{
+				userIDParam,
+				{Name: "network", In: "query", Type: "string", Description: "Restrict the positions to one network"},
+			},
+			Response: []model.Position{}, Handler: http.HandlerFunc(srv.GetPositions),
+		},
+		{
+			Method: http.MethodGet, Path: "/user/{id}/approvals", Summary: "Get the outstanding token allowances a user approved", Tag: "users",
+			Params: []param{
+				userIDParam,
+				{Name: "network", In: "query", Type: "string", Description: "Restrict the approvals to one network"},
+			},
+			Response: []model.Allowance{}, Handler: http.HandlerFunc(srv.GetApprovals),
+		},
+		{
+			Method: http.MethodGet, Path: "/user/{id}/gas", Summary: "Get the gas a user paid for indexed transactions on each network", Tag: "users",
+			Params:   []param{userIDParam},
+			Response: []model.GasTotal{}, Handler: http.HandlerFunc(srv.GetUserGas),
+		},
+		{
+			Method: http.MethodGet, Path: "/user/{id}/quests", Summary: "Get the quests with the progress of a user towards them", Tag: "users",
+			Params:   []param{userIDParam},
+			Response: []model.UserQuest{}, Handler: http.HandlerFunc(srv.GetUserQuests), Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/leaderboard", Summary: "Get the points leaderboard", Tag: "leaderboard",
+			Params:   append(pageParamsDoc, ifNoneMatchParam),
+			Response: LeaderboardResponse{}, Handler: http.HandlerFunc(srv.GetLeaderboard), ETag: true, Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/leaderboard/gas", Summary: "Get the accounts that paid the most gas for indexed transactions on a network", Tag: "leaderboard",
+			Params: []param{
+				{Name: "network", In: "query", Type: "string", Description: "Network of the transactions (default: mainnet)"},
+				{Name: "limit", In: "query", Type: "integer", Description: "Number of accounts (1-500)"},
+			},
+			Response: []model.GasTotal{}, Handler: http.HandlerFunc(srv.GetGasLeaderboard),
+		},
+		{
+			Method: http.MethodGet, Path: "/leaderboard/rank/{address}", Summary: "Get a user's rank on the points leaderboard", Tag: "leaderboard",
+			Params:   []param{{Name: "address", In: "path", Type: "string", Required: true, Description: "User address"}},
+			Response: model.LeaderboardRank{}, Handler: http.HandlerFunc(srv.GetLeaderboardRank), Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/claims/{address}/proof", Summary: "Get an address's proof in the latest Merkle distribution", Tag: "claims",
+			Params:   []param{{Name: "address", In: "path", Type: "string", Required: true, Description: "Claimer address"}},
+			Response: model.MerkleProof{}, Handler: http.HandlerFunc(srv.GetClaimProof), Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/auth/nonce", Summary: "Get a single-use nonce for a Sign-In With Ethereum message", Tag: "auth",
+			Response: model.SignInNonce{}, Handler: http.HandlerFunc(srv.GetSignInNonce),
+		},
+		{
+			Method: http.MethodPost, Path: "/auth/verify", Summary: "Sign in with a signed EIP-4361 message and get a session token", Tag: "auth",
+			Body: signInRequest{}, Response: model.Session{}, Handler: http.HandlerFunc(srv.PostSignIn),
+		},
+		{
+			Method: http.MethodGet, Path: "/me/profile", Summary: "Get the signed-in user's profile", Tag: "auth",
+			Response: model.UserProfile{}, Handler: http.HandlerFunc(srv.GetProfile), Auth: true,
+		},
+		{
+			Method: http.MethodPut, Path: "/me/profile", Summary: "Replace the signed-in user's email and notification preferences", Tag: "auth",
+			Body: profileRequest{}, Response: model.UserProfile{}, Handler: http.HandlerFunc(srv.PutProfile), Auth: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/project", Summary: "Get the project of the API key", Tag: "projects",
+			Response: model.Project{}, Handler: http.HandlerFunc(srv.GetProject), Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/tokens", Summary: "List tokens with their swap volume", Tag: "tokens",
+			Params: append([]param{
+				{Name: "search", In: "query", Type: "string", Description: "Case-insensitive substring of the symbol or name"},
+			}, pageParamsDoc...),
+			Response: tokensResponse{}, Handler: http.HandlerFunc(srv.GetTokens),
+		},
+		{
+			Method: http.MethodGet, Path: "/tokens/{address}", Summary: "Get a token with its swap volume", Tag: "tokens",
+			Params:   []param{{Name: "address", In: "path", Type: "string", Required: true, Description: "Token address"}},
+			Response: model.TokenVolume{}, Handler: http.HandlerFunc(srv.GetToken),
+		},
+		{
+			Method: http.MethodGet, Path: "/tokens/{address}/holders/stats", Summary: "Get the holder count and balance distribution of a token", Tag: "tokens",
+			Params: []param{
+				{Name: "address", In: "path", Type: "string", Required: true, Description: "Token address"},
+				{Name: "network", In: "query", Type: "string", Description: "Network of the token (default: mainnet)"},
+			},
+			Response: model.HolderStats{}, Handler: http.HandlerFunc(srv.GetTokenHolderStats),
+		},
+		{
+			Method: http.MethodGet, Path: "/pools/{address}/stats", Summary: "Get volume statistics and top traders of a pool", Tag: "pools",
+			Params: []param{
+				{Name: "address", In: "path", Type: "string", Required: true, Description: "Pool address"},
+				{Name: "limit", In: "query", Type: "integer", Description: "Number of top traders (1-100)"},
+			},
+			Response: model.PoolStats{}, Handler: http.HandlerFunc(srv.GetPoolStats),
+		},
+		{
+			Method: http.MethodGet, Path: "/pools/{address}/tvl", Summary: "Get the latest reserves and TVL of a pool", Tag: "pools",
+			Params: []param{
+				{Name: "address", In: "path", Type: "string", Required: true, Description: "Pool address"},
+				{Name: "network", In: "query", Type: "string", Description: "Network of the pool (default: mainnet)"},
+			},
+			Response: model.PoolReserves{}, Handler: http.HandlerFunc(srv.GetPoolTVL),
+		},
+		{
+			Method: http.MethodGet, Path: "/prices/{token}", Summary: "Get the OHLC USD price candles of a token", Tag: "prices",
+			Params: []param{
+				{Name: "token", In: "path", Type: "string", Required: true, Description: "Token address"},
+				{Name: "from", In: "query", Type: "string", Description: "Start of the range, RFC 3339 (default: 24 hours before to)"},
+				{Name: "to", In: "query", Type: "string", Description: "End of the range, RFC 3339 (default: now)"},
+				{Name: "interval", In: "query", Type: "string", Description: "Candle interval: 1m, 5m, 15m, 1h, 4h or 1d (default: 1h)"},
+				{Name: "network", In: "query", Type: "string", Description: "Network of the token (default: mainnet)"},
+			},
+			Response: []model.PriceCandle{}, Handler: http.HandlerFunc(srv.GetPrices),
+		},
+		{
+			Method: http.MethodGet, Path: "/analytics/volume", Summary: "Get the swap volume of a network or pool by interval, from the analytics database", Tag: "analytics",
+			Params: []param{
+				{Name: "pool", In: "query", Type: "string", Description: "Only aggregate the swaps of a pool address"},
+				{Name: "from", In: "query", Type: "string", Description: "Start of the range, RFC 3339 (default: 30 days before to)"},
+				{Name: "to", In: "query", Type: "string", Description: "End of the range, RFC 3339 (default: now)"},
+				{Name: "interval", In: "query", Type: "string", Description: "Bucket interval: 1h, 4h, 1d or 1w (default: 1d)"},
+				{Name: "network", In: "query", Type: "string", Description: "Network of the swaps (default: mainnet)"},
+			},
+			Response: []model.VolumeBucket{}, Handler: http.HandlerFunc(srv.GetVolume),
+		},
+		{
+			Method: http.MethodGet, Path: "/analytics/leaderboard", Summary: "Get the accounts that swapped the most USD on a network within a range, from the analytics database", Tag: "analytics",
+			Params: []param{
+				{Name: "from", In: "query", Type: "string", Description: "Start of the range, RFC 3339 (default: 30 days before to)"},
+				{Name: "to", In: "query", Type: "string", Description: "End of the range, RFC 3339 (default: now)"},
+				{Name: "network", In: "query", Type: "string", Description: "Network of the swaps (default: mainnet)"},
+				{Name: "limit", In: "query", Type: "integer", Description: "Number of accounts (1-500)"},
+			},
+			Response: []model.TraderVolume{}, Handler: http.HandlerFunc(srv.GetVolumeLeaderboard),
+		},
+		{
+			Method: http.MethodPost, Path: "/batch", Summary: "Execute up to 20 read operations (user, history, swaps, positions, leaderboard, rank) in one request", Tag: "batch",
+			Body: []batchRequest{}, Response: []batchResponse{}, Handler: http.HandlerFunc(srv.PostBatch), Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/stream", Summary: "Stream the live swaps and points awards as Server-Sent Events", Tag: "stream",
+			Params: []param{
+				{Name: "pool", In: "query", Type: "string", Description: "Only stream the events of a pool address"},
+				{Name: "account", In: "query", Type: "string", Description: "Only stream the events of an account address"},
+			},
+			Response: model.LiveEvent{}, Handler: http.HandlerFunc(srv.GetStream), Stream: true, Project: true,
+		},
+		{
+			Method: http.MethodGet, Path: "/ws", Summary: "Push leaderboard deltas and the points awards of subscribed users over a WebSocket", Tag: "stream",
+			Response: wsMessage{}, Handler: http.HandlerFunc(srv.GetWebSocket), WebSocket: true, Project: true,
+		},
+	}
+}
+
+// pathParamPattern matches chi path parameters, which OpenAPI writes the same way without regexps.
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// buildOpenAPI builds an OpenAPI 3 document from the routes.
+func buildOpenAPI(routes []route) map[string]interface{} {
+	paths := make(map[string]interface{})
+	for _, rt := range routes {
+		path := pathParamPattern.ReplaceAllString(rt.Path, "{$1}")
+		item, exists := paths[path].(map[string]interface{})
+		if !exists {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+
+		parameters := make([]interface{}, 0, len(rt.Params))
+		for _, p := range rt.Params {
+			parameters = append(parameters, map[string]interface{}{
+				"name":        p.Name,
+				"in":          p.In,
+				"required":    p.Required || p.In == "path",
+				"description": p.Description,
+				"schema":      map[string]interface{}{"type": p.Type},
+			})
+		}
+
+		responses := map[string]interface{}{
+			"400": errorResponseDoc("Invalid parameters"),
+			"500": errorResponseDoc("Internal error"),
+		}
+		if rt.Response != nil {
+			responses["200"] = map[string]interface{}{
+				"description": "OK",
+				"content":     mediaContent(rt.Response),
+			}
+		} else {
+			responses["204"] = map[string]interface{}{"description": "No Content"}
+		}
+		operation := map[string]interface{}{
+			"summary":     rt.Summary,
+			"operationId": operationID(rt),
+			"tags":        []string{rt.Tag},
+			"parameters":  parameters,
+			"responses":   responses,
+		}
+		if rt.Auth {
+			operation["security"] = []interface{}{map[string]interface{}{"bearerAuth": []string{}}}
+			operation["responses"].(map[string]interface{})["401"] = errorResponseDoc("Missing, invalid or expired session")
+		}
+		if rt.Project {
+			operation["security"] = []interface{}{map[string]interface{}{"apiKeyAuth": []string{}}}
+			operation["responses"].(map[string]interface{})["401"] = errorResponseDoc("Missing, unknown or revoked API key")
+		}
+		if rt.Stream {
+			operation["responses"].(map[string]interface{})["200"] = map[string]interface{}{
+				"description": "Server-Sent Events named after the event type, whose data is the event",
+				"content": map[string]interface{}{
+					"text/event-stream": map[string]interface{}{"schema": schemaOf(reflect.TypeOf(rt.Response))},
+				},
+			}
+		}
+		if rt.WebSocket {
+			responses := operation["responses"].(map[string]interface{})
+			delete(responses, "200")
+			responses["101"] = map[string]interface{}{
+				"description": "Switched to the WebSocket protocol, sending JSON messages",
+				"content":     mediaContent(rt.Response),
+			}
+		}
+		if rt.Idempotent {
+			operation["responses"].(map[string]interface{})["409"] = errorResponseDoc("A request with the same idempotency key is in progress")
+		}
+		if rt.ETag {
+			operation["responses"].(map[string]interface{})["304"] = map[string]interface{}{"description": "Not modified since the response tagged with If-None-Match"}
+		}
+		if rt.Body != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  mediaContent(rt.Body),
+			}
+		}
+
+		item[strings.ToLower(rt.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "eth-indexer API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKeyAuth": map[string]interface{}{"type": "apiKey", "in": "header", "name": apiKeyHeader},
+			},
+		},
+	}
+}
+
+// operationID derives a stable operation id such as getUserIdSwaps.
+func operationID(rt route) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(rt.Method))
+	for _, part := range strings.FieldsFunc(rt.Path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// mediaContent describes a body as JSON, or as plain text for string bodies.
+func mediaContent(body interface{}) map[string]interface{} {
+	if _, isText := body.(string); isText {
+		return map[string]interface{}{
+			"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+		}
+	}
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schemaOf(reflect.TypeOf(body))},
+	}
+}
+
+func errorResponseDoc(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content":     mediaContent(errorResponse{}),
+	}
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaOf derives a JSON schema from a Go type using its json tags.
+func schemaOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if t == rawMessageType {
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = schemaOf(field.Type)
+		}
+		return map[string]interface{}{"type": "object", "properties": properties}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// GetOpenAPI serves the OpenAPI document of the server.
+func (srv Server) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, buildOpenAPI(srv.routes()))
+}
+
+// swaggerUIPage renders Swagger UI from the public CDN against /openapi.json.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>eth-indexer API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`
+
+// GetSwaggerUI serves the Swagger UI page.
+func (srv Server) GetSwaggerUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerUIPage))
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// pageParams holds the keyset pagination parameters of a request.
+type pageParams struct {
+	Cursor string
+	Limit  int
+}
+
+// isPaginated reports whether the request asks for a paginated response.
+func isPaginated(r *http.Request) bool {
+	q := r.URL.Query()
+	return q.Has("limit") || q.Has("cursor")
+}
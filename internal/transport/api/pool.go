@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultTopTradersLimit = 10
+	maxTopTradersLimit     = 100
+)
+
+// GetPoolStats handles retrieving the volume statistics and top traders of a pool.
+func (s *Server) GetPoolStats(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	address := v.pathAddress("address")
+	limit := v.queryInt("limit", defaultTopTradersLimit, 1, maxTopTradersLimit)
+	if v.check(w) {
+		return
+	}
+
+	stats, err := s.Service.GetPoolStats(r.Context(), address, limit)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, stats)
+}
+
+// GetPoolTVL handles retrieving the latest reserves and TVL of a pool.
+func (s *Server) GetPoolTVL(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	address := v.pathAddress("address")
+	network := v.queryNetwork("network")
+	if network == "" {
+		network = defaultNetwork
+	}
+	if v.check(w) {
+		return
+	}
+
+	reserves, err := s.Service.GetPoolTVL(r.Context(), address, network)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, reserves)
+}
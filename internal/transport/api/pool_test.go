@@ -0,0 +1,164 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+	"hw/pkg/micro-tree/http/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetPoolStats_Success tests the successful retrieval of pool statistics.
+func TestGetPoolStats_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	pool := "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
+	stats := &model.PoolStats{
+		Pool: pool,
+		Windows: map[string]model.PoolVolumeStats{
+			"24h": {VolumeUsd: model.NewDecimalFromFloat(100), SwapCount: 1, UniqueAccounts: 1},
+		},
+		TopTraders: []model.TraderVolume{{Account: "0xuser1", TotalUSD: model.NewDecimalFromFloat(100), SwapCount: 1}},
+	}
+
+	// Mixed-case addresses are normalized before reaching the service
+	mockService.EXPECT().GetPoolStats(gomock.Any(), pool, 5).Return(stats, nil)
+
+	r := chi.NewRouter()
+	r.Get("/pools/{address}/stats", server.GetPoolStats)
+
+	req, err := http.NewRequest("GET", "/pools/0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc/stats?limit=5", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp model.PoolStats
+	err = render.DecodeJSON(rr.Body, &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, *stats, resp)
+}
+
+// TestGetPoolStats_InvalidLimit tests that an out-of-range limit is rejected.
+func TestGetPoolStats_InvalidLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := Server{
+		Service: mocks.NewMockService(ctrl),
+	}
+
+	r := chi.NewRouter()
+	r.Get("/pools/{address}/stats", server.GetPoolStats)
+
+	req, err := http.NewRequest("GET", "/pools/0x00000000000000000000000000000000000000b2/stats?limit=1000", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// TestGetPoolStats_ServiceError tests the scenario when the service returns an error.
+func TestGetPoolStats_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	expectedError := errors.New("database connection failed")
+	mockService.EXPECT().GetPoolStats(gomock.Any(), "0x00000000000000000000000000000000000000b2", defaultTopTradersLimit).Return(nil, expectedError)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestIDMiddleware())
+	r.Get("/pools/{address}/stats", server.GetPoolStats)
+
+	req, err := http.NewRequest("GET", "/pools/0x00000000000000000000000000000000000000b2/stats", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	var errResp errorResponse
+	err = render.DecodeJSON(rr.Body, &errResp)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedError.Error(), errResp.Error)
+}
+
+// TestGetPoolTVL tests the retrieval of the latest reserves of a pool and the response without any.
+func TestGetPoolTVL(t *testing.T) {
+	pool := "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
+	tvl := model.NewDecimalFromFloat(5200000)
+	reserves := &model.PoolReserves{
+		Network:     "base",
+		Pool:        pool,
+		BlockNumber: 20933200,
+		Reserve0:    model.NewDecimalFromFloat(2600000000000),
+		Reserve1:    model.NewDecimalFromFloat(1000000000000000000000),
+		TVLUsd:      &tvl,
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		network  string
+		reserves *model.PoolReserves
+		err      error
+		code     int
+	}{
+		{name: "found", query: "?network=base", network: "base", reserves: reserves, code: http.StatusOK},
+		{name: "never synced", network: "mainnet", err: model.ErrPoolReservesNotFound, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockService(ctrl)
+			server := Server{
+				Service: mockService,
+			}
+
+			mockService.EXPECT().GetPoolTVL(gomock.Any(), pool, tt.network).Return(tt.reserves, tt.err)
+
+			r := chi.NewRouter()
+			r.Get("/pools/{address}/tvl", server.GetPoolTVL)
+
+			req, err := http.NewRequest("GET", "/pools/"+pool+"/tvl"+tt.query, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+			if tt.reserves != nil {
+				var resp model.PoolReserves
+				assert.NoError(t, render.DecodeJSON(rr.Body, &resp))
+				assert.Equal(t, *tt.reserves, resp)
+			}
+		})
+	}
+}
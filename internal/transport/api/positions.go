@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// GetPositions handles retrieving a user's open positions, optionally filtered by the network query parameter.
+func (s *Server) GetPositions(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	id := v.pathAddress("id")
+	network := v.queryNetwork("network")
+	if v.check(w) {
+		return
+	}
+
+	positions, err := s.Service.GetUserPositions(r.Context(), id, network)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, positions)
+}
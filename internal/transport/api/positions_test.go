@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetPositions tests that a user's positions are served, on every network unless one is given.
+func TestGetPositions(t *testing.T) {
+	userID := "0x00000000000000000000000000000000000000a1"
+	positions := []model.Position{
+		{
+			Network:     "mainnet",
+			Protocol:    "uniswap_v2",
+			Kind:        model.PositionLP,
+			Contract:    "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc",
+			Account:     userID,
+			Balance:     model.NewDecimalFromFloat(1500),
+			BlockNumber: 20933200,
+			UpdatedAt:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		network   string
+		positions []model.Position
+		err       error
+		code      int
+	}{
+		{name: "every network", positions: positions, code: http.StatusOK},
+		{name: "one network", query: "?network=base", network: "base", positions: []model.Position{}, code: http.StatusOK},
+		{name: "service error", err: errors.New("db down"), code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockService(ctrl)
+			server := Server{
+				Service: mockService,
+			}
+
+			mockService.EXPECT().GetUserPositions(gomock.Any(), userID, tt.network).Return(tt.positions, tt.err)
+
+			r := chi.NewRouter()
+			r.Get("/user/{id}/positions", server.GetPositions)
+
+			req, err := http.NewRequest("GET", "/user/"+userID+"/positions"+tt.query, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+			if tt.positions != nil {
+				var resp []model.Position
+				assert.NoError(t, render.DecodeJSON(rr.Body, &resp))
+				assert.Equal(t, tt.positions, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultPriceInterval = "1h"
+	defaultPriceRange    = 24 * time.Hour
+)
+
+// priceIntervals maps the candle intervals accepted by GetPrices to their durations.
+var priceIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// GetPrices handles retrieving the OHLC USD price candles of a token.
+func (s *Server) GetPrices(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	token := v.pathAddress("token")
+	to := v.queryTime("to", time.Now().UTC())
+	from := v.queryTime("from", to.Add(-defaultPriceRange))
+	network := v.queryNetwork("network")
+	if network == "" {
+		network = defaultNetwork
+	}
+	name := r.URL.Query().Get("interval")
+	if name == "" {
+		name = defaultPriceInterval
+	}
+	interval, ok := priceIntervals[name]
+	if !ok {
+		v.fail("interval", "must be one of 1m, 5m, 15m, 1h, 4h or 1d")
+	}
+	if v.check(w) {
+		return
+	}
+
+	candles, err := s.Service.GetTokenPrices(r.Context(), token, network, from, to, interval)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, candles)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetPrices_Success tests that the range, interval and network reach the service.
+func TestGetPrices_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	token := "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	from := time.Date(2024, 10, 17, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)
+	candles := []model.PriceCandle{{
+		Time:  from,
+		Open:  model.NewDecimalFromFloat(2400),
+		High:  model.NewDecimalFromFloat(2550),
+		Low:   model.NewDecimalFromFloat(2380),
+		Close: model.NewDecimalFromFloat(2500),
+	}}
+
+	mockService.EXPECT().GetTokenPrices(gomock.Any(), token, "base", from, to, 4*time.Hour).Return(candles, nil)
+
+	r := chi.NewRouter()
+	r.Get("/prices/{token}", server.GetPrices)
+
+	req, err := http.NewRequest("GET", "/prices/0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2?from=2024-10-17T00:00:00Z&to=2024-10-18T00:00:00Z&interval=4h&network=base", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp []model.PriceCandle
+	err = render.DecodeJSON(rr.Body, &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, candles, resp)
+}
+
+// TestGetPrices_Defaults tests that the last 24 hours of hourly mainnet candles are returned by default.
+func TestGetPrices_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	mockService.EXPECT().GetTokenPrices(gomock.Any(), gomock.Any(), "mainnet", gomock.Any(), gomock.Any(), time.Hour).
+		DoAndReturn(func(_ interface{}, _, _ string, from, to time.Time, _ time.Duration) ([]model.PriceCandle, error) {
+			assert.Equal(t, 24*time.Hour, to.Sub(from))
+			return []model.PriceCandle{}, nil
+		})
+
+	r := chi.NewRouter()
+	r.Get("/prices/{token}", server.GetPrices)
+
+	req, err := http.NewRequest("GET", "/prices/0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[]`, rr.Body.String())
+}
+
+// TestGetPrices_InvalidParams tests that malformed parameters and ranges are rejected.
+func TestGetPrices_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	mockService.EXPECT().GetTokenPrices(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, model.NewError(model.ErrInvalid, "price range must end after it starts"))
+
+	tests := []struct {
+		name  string
+		query string
+		body  string
+	}{
+		{name: "interval", query: "interval=2h", body: `"field":"interval"`},
+		{name: "from", query: "from=yesterday", body: `"field":"from"`},
+		{name: "network", query: "network=nowhere", body: `"field":"network"`},
+		{name: "range", query: "from=2024-10-18T00:00:00Z&to=2024-10-17T00:00:00Z", body: "price range must end after it starts"},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/prices/{token}", server.GetPrices)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/prices/0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2?"+tt.query, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.body)
+		})
+	}
+}
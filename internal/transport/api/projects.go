@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"hw/internal/model"
+	"hw/internal/service"
+
+	"github.com/go-chi/render"
+)
+
+// apiKeyHeader is the header carrying the API key of a project.
+const apiKeyHeader = "X-API-Key"
+
+type projectKey struct{}
+
+// requestProject returns the project of the API key of a request, or nil for a request without one.
+func requestProject(r *http.Request) *model.Project {
+	project, _ := r.Context().Value(projectKey{}).(*model.Project)
+	return project
+}
+
+// projectService returns the service of the points, claims, distributions, quests and labels of
+// the project of a request's API key, or s.Service for a request without one.
+func (s *Server) projectService(r *http.Request) service.Service {
+	project := requestProject(r)
+	if project == nil {
+		return s.Service
+	}
+	return s.Service.ForProject(project.ID)
+}
+
+// identifyProject passes the project of the request's API key to next. Requests without a key
+// are served without a project, while an unknown or revoked key is rejected rather than ignored,
+// so a client whose key was revoked notices.
+func (s *Server) identifyProject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		project, err := s.Service.AuthenticateAPIKey(r.Context(), key)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), projectKey{}, project)))
+	})
+}
+
+// requireProject rejects requests without an API key.
+func requireProject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestProject(r) == nil {
+			render.Render(w, r, &errorResponse{Error: "API key required in the " + apiKeyHeader + " header", HTTPStatusCode: http.StatusUnauthorized})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetProject handles retrieving the project of the request's API key.
+func (s *Server) GetProject(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, requestProject(r))
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestIdentifyProject tests that requests are scoped to the project of their API key, that
+// unknown keys are rejected, and that public endpoints stay open without a key.
+func TestIdentifyProject(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		key        string
+		serviceErr error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "project", path: "/project", key: "hwk_valid", wantStatus: http.StatusOK, wantBody: `"slug":"acme"`},
+		{name: "revoked key", path: "/project", key: "hwk_revoked", serviceErr: model.ErrInvalidAPIKey, wantStatus: http.StatusUnauthorized, wantBody: "invalid API key"},
+		{name: "revoked key on public endpoint", path: "/ping", key: "hwk_revoked", serviceErr: model.ErrInvalidAPIKey, wantStatus: http.StatusUnauthorized},
+		{name: "missing key", path: "/project", wantStatus: http.StatusUnauthorized, wantBody: "API key required"},
+		{name: "missing key on reward endpoint", path: "/leaderboard/rank/0x7a250d5630b4cf539739df2c5dacb4c659f2488d", wantStatus: http.StatusUnauthorized, wantBody: "API key required"},
+		{name: "public endpoint", path: "/ping", wantStatus: http.StatusOK, wantBody: "pong"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockService(ctrl)
+			if tt.key != "" {
+				project := &model.Project{ID: 1, Slug: "acme", Name: "Acme rewards"}
+				if tt.serviceErr != nil {
+					project = nil
+				}
+				mockService.EXPECT().AuthenticateAPIKey(gomock.Any(), tt.key).Return(project, tt.serviceErr)
+			}
+
+			router := chi.NewRouter()
+			ConfigureHTTPServer(router, Server{Logger: zap.NewNop(), Service: mockService})
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+// TestProjectScoping tests that the reward endpoints are served by the service of the project of
+// the API key.
+func TestProjectScoping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	projectService := mocks.NewMockService(ctrl)
+	address := "0x7a250d5630b4cf539739df2c5dacb4c659f2488d"
+
+	mockService.EXPECT().AuthenticateAPIKey(gomock.Any(), "hwk_valid").Return(&model.Project{ID: 3, Slug: "acme"}, nil)
+	mockService.EXPECT().ForProject(3).Return(projectService)
+	projectService.EXPECT().GetUserRank(gomock.Any(), address).Return(&model.LeaderboardRank{Address: address, Rank: 2}, nil)
+
+	router := chi.NewRouter()
+	ConfigureHTTPServer(router, Server{Logger: zap.NewNop(), Service: mockService})
+	req := httptest.NewRequest(http.MethodGet, "/leaderboard/rank/"+address, nil)
+	req.Header.Set(apiKeyHeader, "hwk_valid")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"rank":2`)
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// GetUserQuests handles retrieving the quests with the progress of a user towards them.
+func (s *Server) GetUserQuests(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	id := v.pathAddress("id")
+	if v.check(w) {
+		return
+	}
+
+	quests, err := s.projectService(r).GetUserQuests(r.Context(), id)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, quests)
+}
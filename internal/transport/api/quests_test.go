@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetUserQuests tests that the quests of a user are served for the lowercased address.
+func TestGetUserQuests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{Service: mockService}
+
+	quests := []model.UserQuest{
+		{ID: "swap_5_days", Description: "Swap at least $100 on 5 distinct days", Kind: "days", Target: 5, MinDailyUSD: 100, Points: 500, Progress: model.NewDecimalFromFloat(2)},
+	}
+	mockService.EXPECT().GetUserQuests(gomock.Any(), "0x00000000000000000000000000000000000000ab").Return(quests, nil)
+
+	r := chi.NewRouter()
+	r.Get("/user/{id}/quests", server.GetUserQuests)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/user/0x00000000000000000000000000000000000000AB/quests", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []model.UserQuest
+	assert.NoError(t, render.DecodeJSON(rr.Body, &resp))
+	assert.Equal(t, quests, resp)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/user/0xnotanaddress/quests", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -8,7 +8,6 @@ import (
 	"hw/pkg/micro-tree/http/middleware"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/cors"
 	"github.com/go-chi/render"
 	"go.uber.org/zap"
 )
@@ -17,12 +16,20 @@ import (
 type Server struct {
 	Logger  *zap.Logger
 	Service service.Service
+	// WebSocketOrigins are the origins of the browser frontends allowed to open /ws besides the
+	// API's own, e.g. the CORS allowed origins.
+	WebSocketOrigins []string
 }
 
+// RequestHeaders are the request headers read by the API, to allow in cross-origin requests.
+var RequestHeaders = []string{"Accept", "Authorization", "Content-Type", apiKeyHeader, idempotencyKeyHeader}
+
 // errorResponse defines the error response structure
 type errorResponse struct {
-	Error          string `json:"error"`
-	HTTPStatusCode int    `json:"-"` // http response status code
+	Error          string       `json:"error"`
+	Fields         []fieldError `json:"fields,omitempty"`    // per-parameter validation errors
+	Retryable      bool         `json:"retryable,omitempty"` // the request may succeed when retried
+	HTTPStatusCode int          `json:"-"`                   // http response status code
 }
 
 // Render implements the render.Renderer interface
@@ -34,14 +41,9 @@ func (e *errorResponse) Render(_ http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-// ConfigureHTTPServer sets up the HTTP routes and middleware for the Chi router.
+// ConfigureHTTPServer sets up the HTTP routes and middleware for the Chi router. CORS and the
+// security headers are installed by the router, see server.NewHTTPServer.
 func ConfigureHTTPServer(router *chi.Mux, srv Server) {
-	// Configure CORS settings
-	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins: []string{"http://0.0.0.0:3000"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-	}))
-
 	// 添加 Request ID 中間件
 	router.Use(middleware.RequestIDMiddleware())
 
@@ -52,6 +54,9 @@ func ConfigureHTTPServer(router *chi.Mux, srv Server) {
 	// router.Use(middleware.ErrorHandler(s.Logger))
 	router.Use(middleware.RecoveryMiddleware(srv.Logger))
 
+	// Scope requests made with an API key to its project
+	router.Use(srv.identifyProject)
+
 	router.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
 		panic("panic test")
 	})
@@ -62,10 +67,24 @@ func ConfigureHTTPServer(router *chi.Mux, srv Server) {
 	})
 
 	// Define routes
-	router.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("pong"))
-	})
-	router.Get("/user/{id}", srv.GetUser)
-	router.Get("/user/{id}/history", srv.GetHistory)
-	router.Get("/leaderboard", srv.GetLeaderboard)
+	for _, rt := range srv.routes() {
+		handler := rt.Handler
+		if rt.Auth {
+			handler = srv.requireSession(handler)
+		}
+		if rt.Project {
+			handler = requireProject(handler)
+		}
+		if rt.ETag {
+			handler = conditionalGET(handler)
+		}
+		if rt.Idempotent {
+			handler = srv.idempotent(handler)
+		}
+		router.Method(rt.Method, rt.Path, handler)
+	}
+
+	// API documentation
+	router.Get("/openapi.json", srv.GetOpenAPI)
+	router.Get("/docs", srv.GetSwaggerUI)
 }
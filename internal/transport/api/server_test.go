@@ -1,8 +1,10 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"hw/internal/service/mocks"
@@ -98,3 +100,65 @@ func TestPanicHandler(t *testing.T) {
 		t.Errorf("Expected status 500, got %d", w.Code)
 	}
 }
+
+// TestOpenAPI tests that every registered API route is documented in /openapi.json.
+func TestOpenAPI(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := Server{
+		Logger:  zap.NewNop(),
+		Service: mockService,
+	}
+	router := setupTestRouter(srv)
+
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("Expected OpenAPI 3.0.3, got %s", doc.OpenAPI)
+	}
+
+	// Debug and documentation routes are intentionally left out of the spec
+	undocumented := map[string]bool{"/panic": true, "/error": true, "/openapi.json": true, "/docs": true}
+	err := chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if undocumented[route] {
+			return nil
+		}
+		if _, exists := doc.Paths[route][strings.ToLower(method)]; !exists {
+			t.Errorf("Route %s %s is not documented", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk routes: %v", err)
+	}
+
+	if !strings.Contains(string(doc.Paths["/user/{id}/swaps"]["get"]), `"transaction_url"`) {
+		t.Errorf("Expected swaps response schema to describe transaction_url")
+	}
+}
+
+// TestSwaggerUI tests that the Swagger UI page points at the OpenAPI document.
+func TestSwaggerUI(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	router := setupTestRouter(Server{Logger: zap.NewNop(), Service: mockService})
+
+	req := httptest.NewRequest("GET", "/docs", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "/openapi.json") {
+		t.Errorf("Expected Swagger UI page, got %d %s", w.Code, w.Body.String())
+	}
+}
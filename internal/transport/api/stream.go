@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hw/internal/model"
+)
+
+// streamHeartbeatInterval is how often a comment is sent on an idle stream, so proxies and
+// clients do not time the connection out.
+var streamHeartbeatInterval = 15 * time.Second
+
+// GetStream streams the live swaps and points awards as Server-Sent Events, whose event name is
+// the type of the live event and whose data is the event as JSON. The pool and account query
+// parameters restrict the stream to the events of a pool and of an account.
+func (s *Server) GetStream(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	filter := model.LiveEventFilter{
+		Pool:    v.queryAddress("pool"),
+		Account: v.queryAddress("account"),
+	}
+	if v.check(w) {
+		return
+	}
+
+	flusher := http.NewResponseController(w)
+	events, unsubscribe := s.projectService(r).SubscribeLiveEvents(filter)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // disable the response buffering of nginx
+	w.WriteHeader(http.StatusOK)
+	if err := flusher.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		}
+		if err := flusher.Flush(); err != nil {
+			return
+		}
+	}
+}
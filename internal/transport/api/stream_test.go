@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetStream tests that the live events of the subscription are streamed as Server-Sent Events.
+func TestGetStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{Service: mockService}
+
+	events := make(chan model.LiveEvent, 1)
+	unsubscribed := make(chan struct{})
+	mockService.EXPECT().
+		SubscribeLiveEvents(model.LiveEventFilter{Pool: "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"}).
+		Return(events, func() { close(unsubscribed) })
+
+	r := chi.NewRouter()
+	r.Get("/stream", server.GetStream)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/stream?pool=0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	usdValue := model.NewDecimalFromFloat(1200)
+	events <- model.LiveEvent{Type: model.LiveEventSwap, Network: "mainnet", Pool: "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc", Account: "0xuser", UsdValue: &usdValue}
+	reader := bufio.NewReader(resp.Body)
+	name, _ := reader.ReadString('\n')
+	data, _ := reader.ReadString('\n')
+	assert.Equal(t, "event: swap\n", name)
+	assert.True(t, strings.HasPrefix(data, `data: {"type":"swap","network":"mainnet","pool":"0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc","account":"0xuser","usd_value":1200,`), data)
+
+	// Disconnecting unsubscribes
+	resp.Body.Close()
+	<-unsubscribed
+}
+
+// TestGetStream_InvalidFilter tests that malformed filters are rejected before subscribing.
+func TestGetStream_InvalidFilter(t *testing.T) {
+	server := Server{Service: mocks.NewMockService(gomock.NewController(t))}
+
+	rr := httptest.NewRecorder()
+	server.GetStream(rr, httptest.NewRequest(http.MethodGet, "/stream?account=0x1234", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "account")
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+
+	"hw/internal/model"
+	"hw/internal/service"
+
+	"github.com/go-chi/render"
+)
+
+// swapItem represents a single swap in the swap history response.
+type swapItem struct {
+	Network         string        `json:"network"`
+	Token           string        `json:"token"`
+	TransactionHash string        `json:"transaction_hash"`
+	UsdValue        model.Decimal `json:"usd_value"`
+	CountedUsdValue model.Decimal `json:"counted_usd_value"` // part of UsdValue counted towards points
+	WashTrade       bool          `json:"wash_trade"`        // leg of a round trip, left out of some campaigns
+	Timestamp       string        `json:"timestamp"`
+	TransactionURL  string        `json:"transaction_url,omitempty"`
+	TokenURL        string        `json:"token_url,omitempty"`
+}
+
+// swapsResponse structures one page of a user's swap history.
+type swapsResponse struct {
+	Swaps      []swapItem `json:"swaps"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// GetSwaps handles fetching one page of the user's swap history.
+func (s *Server) GetSwaps(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	id := v.pathAddress("id")
+	params := v.pageParams()
+	if v.check(w) {
+		return
+	}
+
+	swaps, next, err := s.Service.GetSwapHistoryPage(r.Context(), id, params.Cursor, params.Limit)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	res := &swapsResponse{
+		Swaps:      make([]swapItem, 0, len(swaps)),
+		NextCursor: next,
+	}
+	for _, swap := range swaps {
+		res.Swaps = append(res.Swaps, swapItem{
+			Network:         swap.Network,
+			Token:           swap.Token,
+			TransactionHash: swap.TransactionHash,
+			UsdValue:        swap.UsdValue,
+			CountedUsdValue: swap.CountedUsdValue,
+			WashTrade:       swap.WashTrade,
+			Timestamp:       swap.LastUpdated.Format("2006-01-02 15:04:05"),
+			TransactionURL:  service.ExplorerTxURL(swap.Network, swap.TransactionHash),
+			TokenURL:        service.ExplorerAddressURL(swap.Network, swap.Token),
+		})
+	}
+
+	render.JSON(w, r, res)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetSwaps_Success tests the successful retrieval of a swap history page.
+func TestGetSwaps_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	userID := "0x00000000000000000000000000000000000000a1"
+	swaps := []model.SwapHistory{
+		{
+			Network:         "mainnet",
+			Token:           "tokenABC",
+			TransactionHash: "0xtx",
+			UsdValue:        model.NewDecimalFromFloat(250.75),
+			CountedUsdValue: model.NewDecimalFromFloat(100),
+			WashTrade:       true,
+			LastUpdated:     time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	mockService.EXPECT().GetSwapHistoryPage(gomock.Any(), userID, "abc", 10).Return(swaps, "next", nil)
+
+	r := chi.NewRouter()
+	r.Get("/user/{id}/swaps", server.GetSwaps)
+
+	req, err := http.NewRequest("GET", "/user/"+userID+"/swaps?cursor=abc&limit=10", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response swapsResponse
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "next", response.NextCursor)
+	assert.Len(t, response.Swaps, 1)
+	assert.Equal(t, "0xtx", response.Swaps[0].TransactionHash)
+	assert.Equal(t, "100", response.Swaps[0].CountedUsdValue.String(), "the capped value is served")
+	assert.True(t, response.Swaps[0].WashTrade)
+	assert.Equal(t, "2024-10-02 08:00:00", response.Swaps[0].Timestamp)
+	assert.Equal(t, "https://etherscan.io/tx/0xtx", response.Swaps[0].TransactionURL)
+	assert.Equal(t, "https://etherscan.io/address/tokenABC", response.Swaps[0].TokenURL)
+}
+
+// TestGetSwaps_InvalidCursor tests that an invalid cursor results in a bad request.
+func TestGetSwaps_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	mockService.EXPECT().GetSwapHistoryPage(gomock.Any(), "0x00000000000000000000000000000000000000a1", "bad", defaultPageLimit).Return(nil, "", model.ErrInvalidCursor)
+
+	r := chi.NewRouter()
+	r.Get("/user/{id}/swaps", server.GetSwaps)
+
+	req, err := http.NewRequest("GET", "/user/0x00000000000000000000000000000000000000a1/swaps?cursor=bad", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// TestGetLeaderboard_Paginated tests that limit switches the leaderboard to keyset pagination.
+func TestGetLeaderboard_Paginated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	users := []model.User{{Address: "0xUser1", TotalPoints: model.NewDecimalFromFloat(200)}}
+	mockService.EXPECT().GetLeaderboardPage(gomock.Any(), "", 1).Return(users, "next", nil)
+
+	r := chi.NewRouter()
+	r.Get("/leaderboard", server.GetLeaderboard)
+
+	req, err := http.NewRequest("GET", "/leaderboard?limit=1", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response LeaderboardResponse
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, LeaderboardResponse{
+		Users:      []UserPoints{{Address: "0xUser1", Points: model.NewDecimalFromFloat(200)}},
+		NextCursor: "next",
+	}, response)
+}
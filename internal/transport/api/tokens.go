@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+
+	"hw/internal/model"
+
+	"github.com/go-chi/render"
+)
+
+// maxTokenSearchLength bounds the search query parameter of the token list.
+const maxTokenSearchLength = 64
+
+// tokensResponse structures one page of the token list.
+type tokensResponse struct {
+	Tokens     []model.TokenVolume `json:"tokens"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// GetTokens handles fetching one page of tokens, optionally filtered by symbol or name.
+func (s *Server) GetTokens(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	search := v.queryString("search", maxTokenSearchLength)
+	params := v.pageParams()
+	if v.check(w) {
+		return
+	}
+
+	tokens, next, err := s.Service.ListTokens(r.Context(), search, params.Cursor, params.Limit)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	if tokens == nil {
+		tokens = []model.TokenVolume{}
+	}
+	render.JSON(w, r, &tokensResponse{Tokens: tokens, NextCursor: next})
+}
+
+// GetTokenHolderStats handles retrieving the holder count and balance distribution of a token on a network, mainnet by default.
+func (s *Server) GetTokenHolderStats(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	address := v.pathAddress("address")
+	network := v.queryNetwork("network")
+	if network == "" {
+		network = defaultNetwork
+	}
+	if v.check(w) {
+		return
+	}
+
+	stats, err := s.Service.GetTokenHolderStats(r.Context(), network, address)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, stats)
+}
+
+// GetToken handles fetching a single token with its swap volume.
+func (s *Server) GetToken(w http.ResponseWriter, r *http.Request) {
+	v := newValidator(r)
+	address := v.pathAddress("address")
+	if v.check(w) {
+		return
+	}
+
+	token, err := s.Service.GetTokenVolume(r.Context(), address)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, token)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetTokens_Success tests listing a page of tokens filtered by a search term.
+func TestGetTokens_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	tokens := []model.TokenVolume{
+		{Token: model.Token{ID: "0x00000000000000000000000000000000000000c3", Symbol: "USDC", Name: "USD Coin", Decimals: 6}, VolumeUsd: model.NewDecimalFromFloat(1500.5), SwapCount: 3},
+	}
+	mockService.EXPECT().ListTokens(gomock.Any(), "usd", "", 10).Return(tokens, "next", nil)
+
+	r := chi.NewRouter()
+	r.Get("/tokens", server.GetTokens)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/tokens?search=usd&limit=10", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response tokensResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Equal(t, tokens, response.Tokens)
+	assert.Equal(t, "next", response.NextCursor)
+}
+
+// TestGetTokens_SearchTooLong tests that oversized search terms are rejected.
+func TestGetTokens_SearchTooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := Server{
+		Service: mocks.NewMockService(ctrl),
+	}
+
+	r := chi.NewRouter()
+	r.Get("/tokens", server.GetTokens)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/tokens?search="+strings.Repeat("a", maxTokenSearchLength+1), nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"search"`)
+}
+
+// TestGetToken tests fetching a known and an unknown token.
+func TestGetToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	known := "0x00000000000000000000000000000000000000c3"
+	unknown := "0x00000000000000000000000000000000000000c4"
+	token := &model.TokenVolume{Token: model.Token{ID: known, Symbol: "USDC"}, VolumeUsd: model.NewDecimalFromFloat(1500.5), SwapCount: 3}
+	mockService.EXPECT().GetTokenVolume(gomock.Any(), known).Return(token, nil)
+	mockService.EXPECT().GetTokenVolume(gomock.Any(), unknown).Return(nil, model.ErrTokenNotFound)
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}", server.GetToken)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/tokens/"+known, nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"volume_usd":1500.5`)
+	assert.Contains(t, rr.Body.String(), `"symbol":"USDC"`)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/tokens/"+unknown, nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+// TestGetTokenHolderStats tests that holder stats default to mainnet and reject invalid networks.
+func TestGetTokenHolderStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{
+		Service: mockService,
+	}
+
+	token := "0x00000000000000000000000000000000000000c3"
+	stats := &model.HolderStats{Network: "mainnet", Token: token, Holders: 3, Buckets: []model.HolderBucket{{Magnitude: 6, Holders: 3}}}
+	mockService.EXPECT().GetTokenHolderStats(gomock.Any(), "mainnet", token).Return(stats, nil)
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders/stats", server.GetTokenHolderStats)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/tokens/"+token+"/holders/stats", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"holders":3`)
+	assert.Contains(t, rr.Body.String(), `"magnitude":6`)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/tokens/"+token+"/holders/stats?network=moon", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
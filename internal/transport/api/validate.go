@@ -0,0 +1,138 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hw/internal/service"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// defaultNetwork is the network of endpoints reading a single network when none is given.
+const defaultNetwork = "mainnet"
+
+// fieldError describes why a single request parameter was rejected.
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// validator collects field errors while reading the parameters of a request,
+// so a response lists every invalid parameter at once.
+type validator struct {
+	r      *http.Request
+	errors []fieldError
+}
+
+func newValidator(r *http.Request) *validator {
+	return &validator{r: r}
+}
+
+func (v *validator) fail(field, format string, args ...interface{}) {
+	v.errors = append(v.errors, fieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// pathAddress reads a path parameter holding an Ethereum address and returns it lowercased.
+func (v *validator) pathAddress(name string) string {
+	value := chi.URLParam(v.r, name)
+	if !common.IsHexAddress(value) || !strings.HasPrefix(value, "0x") {
+		v.fail(name, "must be a 0x-prefixed 20-byte hex address")
+		return ""
+	}
+	return strings.ToLower(value)
+}
+
+// queryAddress reads an optional query parameter holding an Ethereum address and returns it lowercased.
+func (v *validator) queryAddress(name string) string {
+	value := v.r.URL.Query().Get(name)
+	if value == "" {
+		return ""
+	}
+	if !common.IsHexAddress(value) || !strings.HasPrefix(value, "0x") {
+		v.fail(name, "must be a 0x-prefixed 20-byte hex address")
+		return ""
+	}
+	return strings.ToLower(value)
+}
+
+// queryInt reads an optional integer query parameter within [min, max].
+func (v *validator) queryInt(name string, defaultValue, min, max int) int {
+	raw := v.r.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < min || n > max {
+		v.fail(name, "must be between %d and %d", min, max)
+		return defaultValue
+	}
+	return n
+}
+
+// queryString reads an optional query parameter of at most maxLen characters.
+func (v *validator) queryString(name string, maxLen int) string {
+	value := v.r.URL.Query().Get(name)
+	if len(value) > maxLen {
+		v.fail(name, "must be at most %d characters", maxLen)
+		return ""
+	}
+	return value
+}
+
+// queryNetwork reads an optional network query parameter, which must be a known chain.
+func (v *validator) queryNetwork(name string) string {
+	network := v.r.URL.Query().Get(name)
+	if network == "" {
+		return ""
+	}
+	if _, exists := service.GetChain(network); !exists {
+		v.fail(name, "unknown network %q", network)
+	}
+	return network
+}
+
+// queryTime reads an optional RFC 3339 timestamp query parameter.
+func (v *validator) queryTime(name string, defaultValue time.Time) time.Time {
+	raw := v.r.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		v.fail(name, "must be an RFC 3339 timestamp")
+		return defaultValue
+	}
+	return t
+}
+
+// pageParams reads the keyset pagination parameters.
+func (v *validator) pageParams() pageParams {
+	return pageParams{
+		Cursor: v.r.URL.Query().Get("cursor"),
+		Limit:  v.queryInt("limit", defaultPageLimit, 1, maxPageLimit),
+	}
+}
+
+// check renders a 400 response listing the field errors, if any, and reports whether the request was rejected.
+func (v *validator) check(w http.ResponseWriter) bool {
+	if len(v.errors) == 0 {
+		return false
+	}
+
+	fields := make([]string, 0, len(v.errors))
+	for _, fieldErr := range v.errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	render.Render(w, v.r, &errorResponse{
+		Error:          "invalid parameters: " + strings.Join(fields, ", "),
+		Fields:         v.errors,
+		HTTPStatusCode: http.StatusBadRequest,
+	})
+	return true
+}
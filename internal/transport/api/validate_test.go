@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestValidation_FieldErrors tests that every invalid parameter is reported without calling the service.
+func TestValidation_FieldErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := Server{
+		Service: mocks.NewMockService(ctrl),
+	}
+
+	r := chi.NewRouter()
+	r.Get("/user/{id}/swaps", server.GetSwaps)
+
+	req, err := http.NewRequest("GET", "/user/user123/swaps?limit=abc", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var response errorResponse
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid parameters: id, limit", response.Error)
+	assert.Equal(t, []fieldError{
+		{Field: "id", Message: "must be a 0x-prefixed 20-byte hex address"},
+		{Field: "limit", Message: "must be between 1 and 500"},
+	}, response.Fields)
+}
+
+// TestValidation_UnknownNetwork tests that the network query parameter must be a known chain.
+func TestValidation_UnknownNetwork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := Server{
+		Service: mocks.NewMockService(ctrl),
+	}
+
+	r := chi.NewRouter()
+	r.Get("/user/{id}", server.GetUser)
+
+	req, err := http.NewRequest("GET", "/user/0x00000000000000000000000000000000000000a1?network=nowhere", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"network"`)
+}
+
+// TestValidation_AddressLowercased tests that checksummed addresses reach the service lowercased.
+func TestValidation_AddressLowercased(t *testing.T) {
+	req := httptest.NewRequest("GET", "/pools/0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc/stats", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("address", "0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	v := newValidator(req)
+
+	assert.Equal(t, "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc", v.pathAddress("address"))
+	assert.Empty(t, v.errors)
+}
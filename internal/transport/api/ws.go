@@ -0,0 +1,293 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"hw/internal/model"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket tunables, variables so tests can shorten them.
+var (
+	// wsPingInterval is how often the server pings a client; a client answering no ping within
+	// wsPongWait is disconnected.
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+	// wsWriteWait bounds every write to a client.
+	wsWriteWait = 10 * time.Second
+	// wsMaxQueued is the number of messages, other than leaderboard deltas, queued for a client
+	// that does not read them fast enough; it is disconnected when more are queued.
+	wsMaxQueued = 256
+	// wsMaxUserSubscriptions is the number of users a client can subscribe to.
+	wsMaxUserSubscriptions = 100
+)
+
+// Channels of the WebSocket API.
+const (
+	wsChannelLeaderboard = "leaderboard"
+	wsChannelUser        = "user"
+)
+
+// wsRequest is a message of a client, subscribing to or unsubscribing from a channel. Address
+// names the user of the user channel.
+type wsRequest struct {
+	Action  string `json:"action"` // subscribe or unsubscribe
+	Channel string `json:"channel"`
+	Address string `json:"address,omitempty"`
+}
+
+// wsMessage is a message to a client: the acknowledgement of a request (subscribed or
+// unsubscribed), the error of a request, the leaderboard deltas since the previous ones, or a
+// points award of a subscribed user.
+type wsMessage struct {
+	Type    string             `json:"type"`
+	Channel string             `json:"channel,omitempty"`
+	Address string             `json:"address,omitempty"`
+	Error   string             `json:"error,omitempty"`
+	Deltas  []leaderboardDelta `json:"deltas,omitempty"`
+	Event   *model.LiveEvent   `json:"event,omitempty"`
+}
+
+// leaderboardDelta is the points a user gained since the previous leaderboard message.
+type leaderboardDelta struct {
+	Address string        `json:"address"`
+	Points  model.Decimal `json:"points"`
+}
+
+// wsClient holds the subscriptions of a WebSocket client and the messages not yet sent to it.
+// Leaderboard deltas are added up per user while the client is behind, so a slow client gets
+// fewer, larger deltas; other messages are queued up to wsMaxQueued.
+type wsClient struct {
+	mutex       sync.Mutex
+	leaderboard bool
+	users       map[string]bool
+	deltas      map[string]model.Decimal
+	queue       []wsMessage
+	overflowed  bool
+	wake        chan struct{}
+}
+
+func newWSClient() *wsClient {
+	return &wsClient{
+		users:  make(map[string]bool),
+		deltas: make(map[string]model.Decimal),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// enqueue queues a message and wakes the writer, marking the client overflowed when it is too far behind.
+func (c *wsClient) enqueue(message wsMessage) {
+	if len(c.queue) >= wsMaxQueued {
+		c.overflowed = true
+	} else {
+		c.queue = append(c.queue, message)
+	}
+	c.signal()
+}
+
+func (c *wsClient) signal() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// handle applies a request of the client and queues its acknowledgement or error.
+func (c *wsClient) handle(req wsRequest) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	fail := func(message string) {
+		c.enqueue(wsMessage{Type: "error", Channel: req.Channel, Address: req.Address, Error: message})
+	}
+	if req.Action != "subscribe" && req.Action != "unsubscribe" {
+		fail("action must be subscribe or unsubscribe")
+		return
+	}
+	subscribe := req.Action == "subscribe"
+
+	switch req.Channel {
+	case wsChannelLeaderboard:
+		c.leaderboard = subscribe
+		if !subscribe {
+			c.deltas = make(map[string]model.Decimal)
+		}
+	case wsChannelUser:
+		if !common.IsHexAddress(req.Address) || !strings.HasPrefix(req.Address, "0x") {
+			fail("address must be a 0x-prefixed 20-byte hex address")
+			return
+		}
+		req.Address = strings.ToLower(req.Address)
+		if subscribe && !c.users[req.Address] && len(c.users) >= wsMaxUserSubscriptions {
+			fail("too many user subscriptions")
+			return
+		}
+		if subscribe {
+			c.users[req.Address] = true
+		} else {
+			delete(c.users, req.Address)
+		}
+	default:
+		fail("channel must be leaderboard or user")
+		return
+	}
+	c.enqueue(wsMessage{Type: req.Action + "d", Channel: req.Channel, Address: req.Address})
+}
+
+// publish adds a points award to the leaderboard deltas and queues it for the subscribers of its user.
+func (c *wsClient) publish(event model.LiveEvent) {
+	if event.Type != model.LiveEventPoints || event.Points == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.leaderboard {
+		c.deltas[event.Account] = c.deltas[event.Account].Add(*event.Points)
+		c.signal()
+	}
+	if c.users[event.Account] {
+		event := event
+		c.enqueue(wsMessage{Type: "points", Channel: wsChannelUser, Address: event.Account, Event: &event})
+	}
+}
+
+// take returns the messages to send, the leaderboard deltas last, and whether the client overflowed.
+func (c *wsClient) take() ([]wsMessage, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	messages := c.queue
+	c.queue = nil
+	if len(c.deltas) > 0 {
+		deltas := make([]leaderboardDelta, 0, len(c.deltas))
+		for address, points := range c.deltas {
+			deltas = append(deltas, leaderboardDelta{Address: address, Points: points})
+		}
+		sort.Slice(deltas, func(i, j int) bool { return deltas[i].Address < deltas[j].Address })
+		messages = append(messages, wsMessage{Type: "leaderboard", Channel: wsChannelLeaderboard, Deltas: deltas})
+		c.deltas = make(map[string]model.Decimal)
+	}
+	return messages, c.overflowed
+}
+
+// GetWebSocket upgrades the request to a WebSocket pushing the points gained on the leaderboard
+// and the points awards of users to the client, once subscribed to their channel with
+// {"action": "subscribe", "channel": "leaderboard"} or {"action": "subscribe", "channel": "user", "address": "0x..."}.
+func (s *Server) GetWebSocket(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || originAllowed(s.WebSocketOrigins, origin) || sameOrigin(r, origin)
+		},
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has responded with the error
+		return
+	}
+	defer conn.Close()
+
+	client := newWSClient()
+	events, unsubscribe := s.projectService(r).SubscribeLiveEvents(model.LiveEventFilter{})
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case event := <-events:
+				client.publish(event)
+			}
+		}
+	}()
+	go writeWebSocket(conn, client, done)
+
+	conn.SetReadLimit(4096)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		var req wsRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+				// The client left, timed out or the connection failed
+				return
+			}
+			client.mutex.Lock()
+			client.enqueue(wsMessage{Type: "error", Error: "malformed message: " + err.Error()})
+			client.mutex.Unlock()
+			continue
+		}
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		client.handle(req)
+	}
+}
+
+// writeWebSocket sends the messages of client and the pings until done or a write fails. A
+// client too far behind is closed with a try again later status.
+func writeWebSocket(conn *websocket.Conn, client *wsClient, done <-chan struct{}) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	defer conn.Close()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-client.wake:
+			messages, overflowed := client.take()
+			if overflowed {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"), time.Now().Add(wsWriteWait))
+				return
+			}
+			for _, message := range messages {
+				conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+				if err := conn.WriteJSON(message); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// originAllowed reports whether origin matches one of origins, which may be "*" or hold one
+// wildcard as in the CORS configuration.
+func originAllowed(origins []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range origins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if prefix, suffix, found := strings.Cut(allowed, "*"); found &&
+			len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOrigin reports whether origin is the host the request was sent to.
+func sameOrigin(r *http.Request, origin string) bool {
+	_, host, found := strings.Cut(origin, "://")
+	return found && strings.EqualFold(host, r.Host)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hw/internal/model"
+	"hw/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetWebSocket tests that a client gets the leaderboard deltas and the points awards of the
+// users it subscribed to, and errors for invalid requests.
+func TestGetWebSocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	server := Server{Service: mockService, WebSocketOrigins: []string{"https://app.example.com"}}
+	events := make(chan model.LiveEvent)
+	mockService.EXPECT().SubscribeLiveEvents(model.LiveEventFilter{}).Return(events, func() {})
+
+	r := chi.NewRouter()
+	r.Get("/ws", server.GetWebSocket)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://evil.example.com"}})
+	assert.Error(t, err, "other origins are rejected")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://app.example.com"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	requests := []wsRequest{
+		{Action: "subscribe", Channel: wsChannelLeaderboard},
+		{Action: "subscribe", Channel: wsChannelUser, Address: "0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc"},
+		{Action: "subscribe", Channel: wsChannelUser, Address: "0x1234"},
+		{Action: "watch", Channel: wsChannelLeaderboard},
+	}
+	for _, req := range requests {
+		assert.NoError(t, conn.WriteJSON(req))
+	}
+	for _, want := range []wsMessage{
+		{Type: "subscribed", Channel: wsChannelLeaderboard},
+		{Type: "subscribed", Channel: wsChannelUser, Address: "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"},
+		{Type: "error", Channel: wsChannelUser, Address: "0x1234", Error: "address must be a 0x-prefixed 20-byte hex address"},
+		{Type: "error", Channel: wsChannelLeaderboard, Error: "action must be subscribe or unsubscribe"},
+	} {
+		var message wsMessage
+		assert.NoError(t, conn.ReadJSON(&message))
+		assert.Equal(t, want, message)
+	}
+
+	user := "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
+	points := model.NewDecimalFromFloat(25)
+	events <- model.LiveEvent{Type: model.LiveEventPoints, Account: user, Pool: "0xpool", Points: &points}
+
+	var award, deltas wsMessage
+	assert.NoError(t, conn.ReadJSON(&award))
+	assert.NoError(t, conn.ReadJSON(&deltas))
+	assert.Equal(t, "points", award.Type)
+	if assert.NotNil(t, award.Event) {
+		assert.Equal(t, "0xpool", award.Event.Pool)
+	}
+	assert.Equal(t, wsMessage{Type: "leaderboard", Channel: wsChannelLeaderboard, Deltas: []leaderboardDelta{{Address: user, Points: points}}}, deltas)
+}
+
+// TestWSClient_Backpressure tests that leaderboard deltas are added up while a client is behind,
+// and that a client too far behind overflows.
+func TestWSClient_Backpressure(t *testing.T) {
+	client := newWSClient()
+	client.handle(wsRequest{Action: "subscribe", Channel: wsChannelLeaderboard})
+	client.take()
+
+	ten, five := model.NewDecimalFromFloat(10), model.NewDecimalFromFloat(5)
+	client.publish(model.LiveEvent{Type: model.LiveEventPoints, Account: "0xb", Points: &ten})
+	client.publish(model.LiveEvent{Type: model.LiveEventPoints, Account: "0xa", Points: &ten})
+	client.publish(model.LiveEvent{Type: model.LiveEventPoints, Account: "0xb", Points: &five})
+	client.publish(model.LiveEvent{Type: model.LiveEventSwap, Account: "0xb"})
+
+	messages, overflowed := client.take()
+	assert.False(t, overflowed)
+	assert.Equal(t, []wsMessage{{Type: "leaderboard", Channel: wsChannelLeaderboard, Deltas: []leaderboardDelta{
+		{Address: "0xa", Points: ten},
+		{Address: "0xb", Points: model.NewDecimalFromFloat(15)},
+	}}}, messages)
+
+	for i := 0; i <= wsMaxQueued; i++ {
+		client.handle(wsRequest{Action: "subscribe", Channel: "unknown"})
+	}
+	messages, overflowed = client.take()
+	assert.True(t, overflowed)
+	assert.Len(t, messages, wsMaxQueued)
+}
+
+// TestOriginAllowed tests the matching of origins, with wildcards.
+func TestOriginAllowed(t *testing.T) {
+	origins := []string{"https://app.example.com", "https://*.preview.example.com"}
+
+	assert.True(t, originAllowed(origins, "https://app.example.com"))
+	assert.True(t, originAllowed(origins, "https://APP.example.com"))
+	assert.True(t, originAllowed(origins, "https://pr-1.preview.example.com"))
+	assert.False(t, originAllowed(origins, "https://.preview.example.com"))
+	assert.False(t, originAllowed(origins, "http://app.example.com"))
+	assert.True(t, originAllowed([]string{"*"}, "https://anything.example"))
+	assert.False(t, originAllowed(nil, "https://app.example.com"))
+}
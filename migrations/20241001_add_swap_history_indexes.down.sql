@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_swap_history_token_last_updated";
+DROP INDEX IF EXISTS "idx_swap_history_token_account";
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS "idx_swap_history_token_last_updated" ON "swap_history" ("token", "last_updated");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_token_account" ON "swap_history" ("token", "account");
+
+COMMIT;
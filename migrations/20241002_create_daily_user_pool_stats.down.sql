@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "daily_user_pool_stats";
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE "daily_user_pool_stats"
+(
+    "day" date NOT NULL,
+    "token" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "usd_value" numeric(24, 6) NOT NULL DEFAULT 0,
+    "swap_count" integer NOT NULL DEFAULT 0,
+    "points" numeric(16, 3) NOT NULL DEFAULT 0,
+    PRIMARY KEY ("day", "token", "account")
+);
+
+CREATE INDEX "idx_daily_user_pool_stats_token_day" ON "daily_user_pool_stats" ("token", "day");
+
+-- Backfill rollups from existing history rows
+INSERT INTO "daily_user_pool_stats" ("day", "token", "account", "usd_value", "swap_count")
+SELECT (last_updated AT TIME ZONE 'UTC')::date, token, account, SUM(usd_value), COUNT(*)
+FROM "swap_history"
+GROUP BY 1, 2, 3;
+
+INSERT INTO "daily_user_pool_stats" ("day", "token", "account", "points")
+SELECT (created_at AT TIME ZONE 'UTC')::date, token, account, SUM(points)
+FROM "points_history"
+GROUP BY 1, 2, 3
+ON CONFLICT ("day", "token", "account") DO UPDATE SET
+    "points" = EXCLUDED."points";
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_points_history_account_token_created_at_id";
+DROP INDEX IF EXISTS "idx_swap_history_account_created_at_id";
+DROP INDEX IF EXISTS "idx_users_total_points_id";
+
+COMMIT;
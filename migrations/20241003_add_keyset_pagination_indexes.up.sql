@@ -0,0 +1,7 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS "idx_points_history_account_token_created_at_id" ON "points_history" ("account", "token", "created_at" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_swap_history_account_created_at_id" ON "swap_history" ("account", "created_at" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_users_total_points_id" ON "users" ("total_points" DESC, "id" DESC);
+
+COMMIT;
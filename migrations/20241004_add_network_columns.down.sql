@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_swap_history_account_network";
+DROP INDEX IF EXISTS "idx_points_history_account_network";
+
+ALTER TABLE "swap_history" DROP COLUMN IF EXISTS "network";
+ALTER TABLE "points_history" DROP COLUMN IF EXISTS "network";
+
+COMMIT;
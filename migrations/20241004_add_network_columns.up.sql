@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE "swap_history" ADD COLUMN "network" character varying(32) NOT NULL DEFAULT 'mainnet';
+ALTER TABLE "points_history" ADD COLUMN "network" character varying(32) NOT NULL DEFAULT 'mainnet';
+
+CREATE INDEX IF NOT EXISTS "idx_swap_history_account_network" ON "swap_history" ("account", "network");
+CREATE INDEX IF NOT EXISTS "idx_points_history_account_network" ON "points_history" ("account", "network");
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE "handler_jobs"
+(
+    "id" BIGSERIAL PRIMARY KEY,
+    "network" character varying(32) NOT NULL,
+    "handler_key" character varying(255) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "payload" jsonb NOT NULL,
+    "status" character varying(16) NOT NULL DEFAULT 'pending',
+    "last_error" text,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Workers claim the oldest pending job of a network
+CREATE INDEX "idx_handler_jobs_network_status_id" ON "handler_jobs" ("network", "status", "id");
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "indexer_leases";
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+CREATE TABLE "indexer_leases"
+(
+    "network" character varying(32) PRIMARY KEY,
+    "owner" character varying(255) NOT NULL,
+    "expires_at" timestamp with time zone NOT NULL
+);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "raw_logs";
+DROP TABLE IF EXISTS "raw_blocks";
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE "raw_blocks"
+(
+    "network" character varying(32) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "block_hash" character varying(66) NOT NULL,
+    "payload" jsonb NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("network", "block_number")
+);
+
+CREATE TABLE "raw_logs"
+(
+    "network" character varying(32) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "log_index" integer NOT NULL,
+    "transaction_hash" character varying(66) NOT NULL,
+    "payload" jsonb NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("network", "block_number", "log_index")
+);
+
+COMMIT;
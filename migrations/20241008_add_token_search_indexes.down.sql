@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_tokens_name_trgm";
+DROP INDEX IF EXISTS "idx_tokens_symbol_trgm";
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+CREATE TABLE "indexer_pauses"
+(
+    "network" character varying(32) NOT NULL DEFAULT '',
+    "contract" character varying(255) NOT NULL DEFAULT '',
+    "paused_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("network", "contract")
+);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+CREATE TABLE "indexer_ranges"
+(
+    "network" character varying(32) NOT NULL,
+    "from_block" bigint NOT NULL,
+    "to_block" bigint NOT NULL,
+    PRIMARY KEY ("network", "from_block")
+);
+
+COMMIT;
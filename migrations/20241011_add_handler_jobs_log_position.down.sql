@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_handler_jobs_network_status_position";
+CREATE INDEX "idx_handler_jobs_network_status_id" ON "handler_jobs" ("network", "status", "id");
+
+ALTER TABLE "handler_jobs"
+    DROP COLUMN IF EXISTS "log_index",
+    DROP COLUMN IF EXISTS "tx_index";
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE "handler_jobs"
+    ADD COLUMN "tx_index" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN "log_index" bigint NOT NULL DEFAULT 0;
+
+-- Workers claim the pending jobs of a network in chain order
+DROP INDEX IF EXISTS "idx_handler_jobs_network_status_id";
+CREATE INDEX "idx_handler_jobs_network_status_position" ON "handler_jobs" ("network", "status", "block_number", "tx_index", "log_index", "id");
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Code generated by entitygen. DO NOT EDIT.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "pair_day_data"
+(
+    "id" text PRIMARY KEY,
+    "network" text NOT NULL,
+    "pair" character varying(42) NOT NULL,
+    "day" bigint NOT NULL,
+    "volume_usd" numeric NOT NULL,
+    "swap_count" bigint NOT NULL,
+    "updated_at" timestamp with time zone NOT NULL
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "point_claims";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "claimed_points";
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE "users" ADD COLUMN "claimed_points" numeric(12, 3) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "point_claims"
+(
+    "id" SERIAL PRIMARY KEY,
+    "address" character(42) NOT NULL,
+    "points" numeric(12, 3) NOT NULL,
+    "signature" character(132) NOT NULL UNIQUE,
+    "leaf" character(66),
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_point_claims_address" ON "point_claims" ("address", "id");
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_points_history_created_at";
+
+DROP TABLE IF EXISTS "merkle_proofs";
+DROP TABLE IF EXISTS "merkle_distributions";
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "merkle_distributions"
+(
+    "id" SERIAL PRIMARY KEY,
+    "cutoff" timestamp with time zone NOT NULL,
+    "root" character(66) NOT NULL,
+    "token_total" numeric(78, 0) NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "merkle_proofs"
+(
+    "distribution_id" integer NOT NULL REFERENCES "merkle_distributions" ("id") ON DELETE CASCADE,
+    "address" character(42) NOT NULL,
+    "index" integer NOT NULL,
+    "amount" numeric(78, 0) NOT NULL,
+    "proof" jsonb NOT NULL,
+    PRIMARY KEY ("distribution_id", "address")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_points_history_created_at" ON "points_history" ("created_at");
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "user_profiles";
+
+DROP INDEX IF EXISTS "idx_sign_in_nonces_expires_at";
+DROP TABLE IF EXISTS "sign_in_nonces";
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "sign_in_nonces"
+(
+    "nonce" character varying(32) PRIMARY KEY,
+    "expires_at" timestamp with time zone NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_sign_in_nonces_expires_at" ON "sign_in_nonces" ("expires_at");
+
+CREATE TABLE IF NOT EXISTS "user_profiles"
+(
+    "address" character(42) PRIMARY KEY,
+    "email" character varying(254) NOT NULL DEFAULT '',
+    "notifications" jsonb NOT NULL DEFAULT '{}',
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
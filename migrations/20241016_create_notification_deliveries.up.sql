@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "notification_deliveries"
+(
+    "address" character(42) NOT NULL,
+    "kind" character varying(32) NOT NULL,
+    "period" timestamp with time zone NOT NULL,
+    "sent_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("address", "kind", "period")
+);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "audit_log"
+(
+    "id" bigserial PRIMARY KEY,
+    "actor" character varying(128) NOT NULL,
+    "method" character varying(8) NOT NULL,
+    "path" text NOT NULL,
+    "request" text NOT NULL DEFAULT '',
+    "status" integer NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_audit_log_created_at" ON "audit_log" ("created_at");
+
+COMMIT;
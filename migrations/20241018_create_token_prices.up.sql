@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE "token_prices"
+(
+    "network" character varying(32) NOT NULL,
+    "token" character(42) NOT NULL,
+    "bucket" timestamp with time zone NOT NULL,
+    "open" numeric(36, 18) NOT NULL,
+    "high" numeric(36, 18) NOT NULL,
+    "low" numeric(36, 18) NOT NULL,
+    "close" numeric(36, 18) NOT NULL,
+    "updated_at" timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY ("token", "network", "bucket")
+);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE "pool_reserves"
+(
+    "network" character varying(32) NOT NULL,
+    "pool" character(42) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "log_index" integer NOT NULL,
+    "reserve0" numeric(78, 0) NOT NULL,
+    "reserve1" numeric(78, 0) NOT NULL,
+    "tvl_usd" numeric(36, 6),
+    "block_time" timestamp with time zone NOT NULL,
+    PRIMARY KEY ("pool", "network", "block_number")
+);
+
+COMMIT;
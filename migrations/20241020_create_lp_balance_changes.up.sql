@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE "lp_balance_changes"
+(
+    "network" character varying(32) NOT NULL,
+    "pool" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "transaction_hash" character varying(66) NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" bigint NOT NULL,
+    "delta" numeric(78, 0) NOT NULL,
+    "block_time" timestamp with time zone NOT NULL,
+    PRIMARY KEY ("network", "transaction_hash", "log_index", "account")
+);
+
+CREATE INDEX "idx_lp_balance_changes_pool_network_block_time" ON "lp_balance_changes" ("pool", "network", "block_time");
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "positions";
+
+ALTER INDEX "idx_position_changes_contract_network_block_time" RENAME TO "idx_lp_balance_changes_pool_network_block_time";
+DELETE FROM "position_changes" WHERE "kind" <> 'lp';
+ALTER TABLE "position_changes" DROP COLUMN "kind";
+ALTER TABLE "position_changes" DROP COLUMN "protocol";
+ALTER TABLE "position_changes" RENAME COLUMN "contract" TO "pool";
+ALTER TABLE "position_changes" RENAME CONSTRAINT "position_changes_pkey" TO "lp_balance_changes_pkey";
+ALTER TABLE "position_changes" RENAME TO "lp_balance_changes";
+
+COMMIT;
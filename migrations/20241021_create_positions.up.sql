@@ -0,0 +1,34 @@
+BEGIN;
+
+-- LP balance changes become the changes of every kind of position
+ALTER TABLE "lp_balance_changes" RENAME TO "position_changes";
+ALTER TABLE "position_changes" RENAME CONSTRAINT "lp_balance_changes_pkey" TO "position_changes_pkey";
+ALTER TABLE "position_changes" RENAME COLUMN "pool" TO "contract";
+ALTER TABLE "position_changes" ADD COLUMN "protocol" character varying(32) NOT NULL DEFAULT 'uniswap_v2';
+ALTER TABLE "position_changes" ADD COLUMN "kind" character varying(16) NOT NULL DEFAULT 'lp';
+ALTER TABLE "position_changes" ALTER COLUMN "protocol" DROP DEFAULT;
+ALTER TABLE "position_changes" ALTER COLUMN "kind" DROP DEFAULT;
+ALTER INDEX "idx_lp_balance_changes_pool_network_block_time" RENAME TO "idx_position_changes_contract_network_block_time";
+
+CREATE TABLE "positions"
+(
+    "network" character varying(32) NOT NULL,
+    "protocol" character varying(32) NOT NULL,
+    "kind" character varying(16) NOT NULL,
+    "contract" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "balance" numeric(78, 0) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "updated_at" timestamp with time zone NOT NULL,
+    PRIMARY KEY ("network", "contract", "account")
+);
+
+CREATE INDEX "idx_positions_account_network" ON "positions" ("account", "network");
+
+-- Backfill current balances from the recorded changes
+INSERT INTO "positions" ("network", "protocol", "kind", "contract", "account", "balance", "block_number", "updated_at")
+SELECT network, protocol, kind, contract, account, SUM(delta), MAX(block_number), MAX(block_time)
+FROM "position_changes"
+GROUP BY network, protocol, kind, contract, account;
+
+COMMIT;
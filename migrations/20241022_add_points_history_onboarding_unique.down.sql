@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_points_history_onboarding_account";
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- An account is onboarded once; concurrent awards of the onboarding task must not both be credited
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_points_history_onboarding_account" ON "points_history" ("account") WHERE "description" = 'onboarding_task';
+
+COMMIT;
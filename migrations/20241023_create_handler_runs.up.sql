@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "handler_runs"
+(
+    "id" bigserial PRIMARY KEY,
+    "handler_key" character varying(255) NOT NULL,
+    "network" character varying(64) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "transaction_hash" character varying(66) NOT NULL DEFAULT '',
+    "log_index" integer NOT NULL DEFAULT 0,
+    "status" character varying(16) NOT NULL,
+    "error" text NOT NULL DEFAULT '',
+    "duration_ms" double precision NOT NULL,
+    "started_at" timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_handler_runs_handler_key_started_at" ON "handler_runs" ("handler_key", "started_at");
+CREATE INDEX IF NOT EXISTS "idx_handler_runs_started_at" ON "handler_runs" ("started_at");
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_api_keys_project_id";
+DROP TABLE IF EXISTS "api_keys";
+
+DROP TABLE IF EXISTS "projects";
+
+COMMIT;
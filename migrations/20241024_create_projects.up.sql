@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "projects"
+(
+    "id" serial PRIMARY KEY,
+    "slug" character varying(64) NOT NULL UNIQUE,
+    "name" character varying(255) NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "api_keys"
+(
+    "id" serial PRIMARY KEY,
+    "project_id" integer NOT NULL REFERENCES "projects" ("id") ON DELETE CASCADE,
+    "prefix" character varying(16) NOT NULL UNIQUE,
+    "key_hash" character(64) NOT NULL UNIQUE,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "revoked_at" timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS "idx_api_keys_project_id" ON "api_keys" ("project_id");
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "idempotency_keys"
+(
+    "scope" character varying(64) NOT NULL,
+    "key" character varying(255) NOT NULL,
+    "request_hash" character(64) NOT NULL,
+    "status" integer,
+    "content_type" character varying(255),
+    "body" bytea,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("scope", "key")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_idempotency_keys_created_at" ON "idempotency_keys" ("created_at");
+
+COMMIT;
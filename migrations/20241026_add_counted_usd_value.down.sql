@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_daily_user_pool_stats_account_day";
+ALTER TABLE "daily_user_pool_stats" DROP COLUMN IF EXISTS "counted_usd_value";
+ALTER TABLE "swap_history" DROP COLUMN IF EXISTS "counted_usd_value";
+
+COMMIT;
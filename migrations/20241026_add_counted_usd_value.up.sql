@@ -0,0 +1,13 @@
+BEGIN;
+
+-- USD value of a swap counted towards points, below usd_value when capped
+ALTER TABLE "swap_history" ADD COLUMN IF NOT EXISTS "counted_usd_value" numeric(24, 6);
+UPDATE "swap_history" SET "counted_usd_value" = "usd_value" WHERE "counted_usd_value" IS NULL;
+ALTER TABLE "swap_history" ALTER COLUMN "counted_usd_value" SET NOT NULL;
+
+ALTER TABLE "daily_user_pool_stats" ADD COLUMN IF NOT EXISTS "counted_usd_value" numeric(24, 6) NOT NULL DEFAULT 0;
+UPDATE "daily_user_pool_stats" SET "counted_usd_value" = "usd_value";
+
+CREATE INDEX IF NOT EXISTS "idx_daily_user_pool_stats_account_day" ON "daily_user_pool_stats" ("account", "day");
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_swap_history_wash_trade";
+DROP INDEX IF EXISTS "idx_swap_history_transaction_hash";
+ALTER TABLE "swap_history" DROP COLUMN IF EXISTS "wash_trade";
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Swap flagged as a leg of a round trip of its account through a pool within one transaction
+ALTER TABLE "swap_history" ADD COLUMN IF NOT EXISTS "wash_trade" boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS "idx_swap_history_transaction_hash" ON "swap_history" ("transaction_hash");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_wash_trade" ON "swap_history" ("token", "last_updated") WHERE "wash_trade";
+
+COMMIT;
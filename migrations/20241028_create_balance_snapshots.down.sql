@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_position_changes_contract_network_block_number";
+
+DROP TABLE IF EXISTS "balance_snapshot_holders";
+DROP TABLE IF EXISTS "balance_snapshots";
+
+COMMIT;
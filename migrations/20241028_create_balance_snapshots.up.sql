@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "balance_snapshots"
+(
+    "id" SERIAL PRIMARY KEY,
+    "network" character varying(32) NOT NULL,
+    "token" character(42) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "source" character varying(16) NOT NULL,
+    "holders" integer NOT NULL,
+    "total" numeric(78, 0) NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_balance_snapshots_token_network_block_number" ON "balance_snapshots" ("token", "network", "block_number");
+
+CREATE TABLE IF NOT EXISTS "balance_snapshot_holders"
+(
+    "snapshot_id" integer NOT NULL REFERENCES "balance_snapshots" ("id") ON DELETE CASCADE,
+    "account" character(42) NOT NULL,
+    "balance" numeric(78, 0) NOT NULL,
+    PRIMARY KEY ("snapshot_id", "account")
+);
+
+-- Balances of indexed transfers up to a block
+CREATE INDEX IF NOT EXISTS "idx_position_changes_contract_network_block_number" ON "position_changes" ("contract", "network", "block_number");
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "gas_spend"
+(
+    "network" character varying(32) NOT NULL,
+    "transaction_hash" character(66) NOT NULL,
+    "account" character(42) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "gas_used" bigint NOT NULL,
+    "gas_price" numeric(78, 0) NOT NULL,
+    "fee" numeric(78, 0) NOT NULL,
+    "block_time" timestamp with time zone NOT NULL,
+    PRIMARY KEY ("network", "transaction_hash")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_gas_spend_account_network" ON "gas_spend" ("account", "network");
+
+COMMIT;
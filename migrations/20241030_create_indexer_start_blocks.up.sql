@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "indexer_start_blocks"
+(
+    "network" character varying(32) NOT NULL,
+    "address" character(42) NOT NULL,
+    "start_block" bigint NOT NULL,
+    "source" character varying(16) NOT NULL,
+    "resolved_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("network", "address")
+);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "allowances"
+(
+    "network" character varying(32) NOT NULL,
+    "token" character(42) NOT NULL,
+    "owner" character(42) NOT NULL,
+    "spender" character(42) NOT NULL,
+    "amount" numeric(78, 0) NOT NULL,
+    "transaction_hash" character(66) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "log_index" integer NOT NULL,
+    "updated_at" timestamp with time zone NOT NULL,
+    PRIMARY KEY ("network", "token", "owner", "spender")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_allowances_owner_network" ON "allowances" ("owner", "network");
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- Holders of a token by the order of magnitude of their balance: magnitude 3 counts the holders of
+-- 1000 to 9999 of the token's smallest unit
+CREATE TABLE IF NOT EXISTS "token_holder_buckets"
+(
+    "network" character varying(32) NOT NULL,
+    "token" character(42) NOT NULL,
+    "magnitude" smallint NOT NULL,
+    "holders" bigint NOT NULL,
+    PRIMARY KEY ("network", "token", "magnitude")
+);
+
+-- Backfill from the current positions
+INSERT INTO "token_holder_buckets" ("network", "token", "magnitude", "holders")
+SELECT network, contract, length(balance::text) - 1, COUNT(*)
+FROM "positions"
+WHERE balance > 0
+GROUP BY network, contract, length(balance::text) - 1
+ON CONFLICT DO NOTHING;
+
+COMMIT;
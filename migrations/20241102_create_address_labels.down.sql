@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "address_labels";
+
+COMMIT;
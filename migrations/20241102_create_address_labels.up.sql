@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "address_labels"
+(
+    "address" character(42) NOT NULL,
+    "label" character varying(32) NOT NULL,
+    "name" character varying(128) NOT NULL DEFAULT '',
+    "created_at" timestamp with time zone NOT NULL DEFAULT now(),
+    "updated_at" timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY ("address", "label")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_address_labels_label" ON "address_labels" ("label");
+
+COMMIT;
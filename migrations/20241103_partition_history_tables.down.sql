@@ -0,0 +1,69 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "points_history_rollups";
+DROP TABLE IF EXISTS "swap_history_rollups";
+
+-- points_history, back to a single table
+ALTER TABLE "points_history" RENAME TO "points_history_partitioned";
+ALTER TABLE "points_history_partitioned" RENAME CONSTRAINT "points_history_pkey" TO "points_history_partitioned_pkey";
+
+CREATE TABLE "points_history"
+(
+    "id" integer PRIMARY KEY DEFAULT nextval('points_history_id_seq'),
+    "token" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "points" numeric(12, 3) NOT NULL,
+    "description" character varying(255) NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "network" character varying(32) NOT NULL DEFAULT 'mainnet'
+);
+
+INSERT INTO "points_history" ("id", "token", "account", "points", "description", "created_at", "network")
+SELECT "id", "token", "account", "points", "description", "created_at", "network"
+FROM "points_history_partitioned";
+
+ALTER SEQUENCE "points_history_id_seq" OWNED BY "points_history"."id";
+DROP TABLE "points_history_partitioned";
+DROP TABLE IF EXISTS "points_onboarding";
+
+CREATE INDEX IF NOT EXISTS "idx_points_history_account_token_created_at_id" ON "points_history" ("account", "token", "created_at" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_points_history_account_network" ON "points_history" ("account", "network");
+CREATE INDEX IF NOT EXISTS "idx_points_history_created_at" ON "points_history" ("created_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_points_history_onboarding_account" ON "points_history" ("account") WHERE "description" = 'onboarding_task';
+
+-- swap_history, back to a single table
+ALTER TABLE "swap_history" RENAME TO "swap_history_partitioned";
+ALTER TABLE "swap_history_partitioned" RENAME CONSTRAINT "swap_history_pkey" TO "swap_history_partitioned_pkey";
+
+CREATE TABLE "swap_history"
+(
+    "id" integer PRIMARY KEY DEFAULT nextval('swap_history_id_seq'),
+    "token" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "transaction_hash" character(66) NOT NULL,
+    "usd_value" numeric(20, 6) NOT NULL,
+    "last_updated" timestamp with time zone NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "network" character varying(32) NOT NULL DEFAULT 'mainnet',
+    "counted_usd_value" numeric(24, 6) NOT NULL,
+    "wash_trade" boolean NOT NULL DEFAULT false
+);
+
+INSERT INTO "swap_history" ("id", "token", "account", "transaction_hash", "usd_value", "last_updated", "created_at", "network", "counted_usd_value", "wash_trade")
+SELECT "id", "token", "account", "transaction_hash", "usd_value", "last_updated", "created_at", "network", "counted_usd_value", "wash_trade"
+FROM "swap_history_partitioned";
+
+ALTER SEQUENCE "swap_history_id_seq" OWNED BY "swap_history"."id";
+DROP TABLE "swap_history_partitioned";
+
+CREATE INDEX IF NOT EXISTS "idx_swap_history_token_last_updated" ON "swap_history" ("token", "last_updated");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_token_account" ON "swap_history" ("token", "account");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_account_created_at_id" ON "swap_history" ("account", "created_at" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_swap_history_account_network" ON "swap_history" ("account", "network");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_transaction_hash" ON "swap_history" ("transaction_hash");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_wash_trade" ON "swap_history" ("token", "last_updated") WHERE "wash_trade";
+
+DROP FUNCTION IF EXISTS "drop_monthly_partition"(text, text, date);
+DROP FUNCTION IF EXISTS "create_monthly_partition"(text, text, date);
+
+COMMIT;
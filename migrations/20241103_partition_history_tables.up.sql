@@ -0,0 +1,166 @@
+BEGIN;
+
+-- Creates the partition of a month of a table partitioned by range of key, moving the rows of the
+-- month out of the default partition first. Reports whether the partition was created.
+CREATE OR REPLACE FUNCTION "create_monthly_partition"(parent text, key text, month date) RETURNS boolean AS $$
+DECLARE
+    partition text := parent || '_p' || to_char(month, 'YYYYMM');
+    lower_bound timestamptz := date_trunc('month', month::timestamp) AT TIME ZONE 'UTC';
+    upper_bound timestamptz := (date_trunc('month', month::timestamp) + interval '1 month') AT TIME ZONE 'UTC';
+BEGIN
+    IF to_regclass(partition) IS NOT NULL THEN
+        RETURN false;
+    END IF;
+    EXECUTE format('CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS)', partition, parent);
+    EXECUTE format('WITH moved AS (DELETE FROM %I WHERE %I >= %L AND %I < %L RETURNING *) INSERT INTO %I SELECT * FROM moved',
+        parent || '_default', key, lower_bound, key, upper_bound, partition);
+    EXECUTE format('ALTER TABLE %I ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)', parent, partition, lower_bound, upper_bound);
+    RETURN true;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Removes the rows of a month of a table partitioned by range of key: drops the partition of the
+-- month and deletes the rows of the month left in the default partition. Returns the rows removed.
+CREATE OR REPLACE FUNCTION "drop_monthly_partition"(parent text, key text, month date) RETURNS bigint AS $$
+DECLARE
+    partition text := parent || '_p' || to_char(month, 'YYYYMM');
+    lower_bound timestamptz := date_trunc('month', month::timestamp) AT TIME ZONE 'UTC';
+    upper_bound timestamptz := (date_trunc('month', month::timestamp) + interval '1 month') AT TIME ZONE 'UTC';
+    removed bigint;
+    dropped bigint := 0;
+BEGIN
+    EXECUTE format('DELETE FROM %I WHERE %I >= %L AND %I < %L', parent || '_default', key, lower_bound, key, upper_bound);
+    GET DIAGNOSTICS removed = ROW_COUNT;
+    IF to_regclass(partition) IS NOT NULL THEN
+        EXECUTE format('SELECT COUNT(*) FROM %I', partition) INTO dropped;
+        EXECUTE format('DROP TABLE %I', partition);
+    END IF;
+    RETURN removed + dropped;
+END;
+$$ LANGUAGE plpgsql;
+
+-- swap_history, partitioned by month of the block time
+ALTER TABLE "swap_history" RENAME TO "swap_history_unpartitioned";
+ALTER TABLE "swap_history_unpartitioned" RENAME CONSTRAINT "swap_history_pkey" TO "swap_history_unpartitioned_pkey";
+
+CREATE TABLE "swap_history"
+(
+    "id" integer NOT NULL DEFAULT nextval('swap_history_id_seq'),
+    "token" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "transaction_hash" character(66) NOT NULL,
+    "usd_value" numeric(20, 6) NOT NULL,
+    "last_updated" timestamp with time zone NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "network" character varying(32) NOT NULL DEFAULT 'mainnet',
+    "counted_usd_value" numeric(24, 6) NOT NULL,
+    "wash_trade" boolean NOT NULL DEFAULT false,
+    PRIMARY KEY ("id", "last_updated")
+) PARTITION BY RANGE ("last_updated");
+
+CREATE TABLE "swap_history_default" PARTITION OF "swap_history" DEFAULT;
+
+SELECT "create_monthly_partition"('swap_history', 'last_updated', month::date)
+FROM generate_series(
+    date_trunc('month', LEAST((SELECT MIN("last_updated") FROM "swap_history_unpartitioned"), CURRENT_TIMESTAMP) AT TIME ZONE 'UTC'),
+    date_trunc('month', CURRENT_TIMESTAMP AT TIME ZONE 'UTC') + interval '1 month',
+    interval '1 month'
+) AS month;
+
+INSERT INTO "swap_history" ("id", "token", "account", "transaction_hash", "usd_value", "last_updated", "created_at", "network", "counted_usd_value", "wash_trade")
+SELECT "id", "token", "account", "transaction_hash", "usd_value", "last_updated", "created_at", "network", "counted_usd_value", "wash_trade"
+FROM "swap_history_unpartitioned";
+
+ALTER SEQUENCE "swap_history_id_seq" OWNED BY "swap_history"."id";
+DROP TABLE "swap_history_unpartitioned";
+
+CREATE INDEX IF NOT EXISTS "idx_swap_history_last_updated" ON "swap_history" ("last_updated");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_token_last_updated" ON "swap_history" ("token", "last_updated");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_token_account" ON "swap_history" ("token", "account");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_account_created_at_id" ON "swap_history" ("account", "created_at" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_swap_history_account_network" ON "swap_history" ("account", "network");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_transaction_hash" ON "swap_history" ("transaction_hash");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_wash_trade" ON "swap_history" ("token", "last_updated") WHERE "wash_trade";
+
+-- points_history, partitioned by month of the award. A unique index of a partitioned table must
+-- include the partition key, so the onboarding awards are kept unique in their own table.
+CREATE TABLE "points_onboarding"
+(
+    "account" character(42) PRIMARY KEY,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO "points_onboarding" ("account", "created_at")
+SELECT "account", MIN("created_at")
+FROM "points_history"
+WHERE "description" = 'onboarding_task'
+GROUP BY "account";
+
+ALTER TABLE "points_history" RENAME TO "points_history_unpartitioned";
+ALTER TABLE "points_history_unpartitioned" RENAME CONSTRAINT "points_history_pkey" TO "points_history_unpartitioned_pkey";
+
+CREATE TABLE "points_history"
+(
+    "id" integer NOT NULL DEFAULT nextval('points_history_id_seq'),
+    "token" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "points" numeric(12, 3) NOT NULL,
+    "description" character varying(255) NOT NULL,
+    "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "network" character varying(32) NOT NULL DEFAULT 'mainnet',
+    PRIMARY KEY ("id", "created_at")
+) PARTITION BY RANGE ("created_at");
+
+CREATE TABLE "points_history_default" PARTITION OF "points_history" DEFAULT;
+
+SELECT "create_monthly_partition"('points_history', 'created_at', month::date)
+FROM generate_series(
+    date_trunc('month', LEAST((SELECT MIN("created_at") FROM "points_history_unpartitioned"), CURRENT_TIMESTAMP) AT TIME ZONE 'UTC'),
+    date_trunc('month', CURRENT_TIMESTAMP AT TIME ZONE 'UTC') + interval '1 month',
+    interval '1 month'
+) AS month;
+
+INSERT INTO "points_history" ("id", "token", "account", "points", "description", "created_at", "network")
+SELECT "id", "token", "account", "points", "description", "created_at", "network"
+FROM "points_history_unpartitioned";
+
+ALTER SEQUENCE "points_history_id_seq" OWNED BY "points_history"."id";
+DROP TABLE "points_history_unpartitioned";
+
+CREATE INDEX IF NOT EXISTS "idx_points_history_account_token_created_at_id" ON "points_history" ("account", "token", "created_at" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_points_history_account_network" ON "points_history" ("account", "network");
+CREATE INDEX IF NOT EXISTS "idx_points_history_created_at" ON "points_history" ("created_at");
+
+-- Monthly totals of the rows dropped by the retention, so all-time totals survive it
+CREATE TABLE "swap_history_rollups"
+(
+    "month" date NOT NULL,
+    "network" character varying(32) NOT NULL,
+    "token" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "usd_value" numeric(24, 6) NOT NULL DEFAULT 0,
+    "counted_usd_value" numeric(24, 6) NOT NULL DEFAULT 0,
+    "wash_usd_value" numeric(24, 6) NOT NULL DEFAULT 0,
+    "wash_counted_usd_value" numeric(24, 6) NOT NULL DEFAULT 0,
+    "swap_count" integer NOT NULL DEFAULT 0,
+    PRIMARY KEY ("month", "network", "token", "account")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_swap_history_rollups_account_token" ON "swap_history_rollups" ("account", "token");
+CREATE INDEX IF NOT EXISTS "idx_swap_history_rollups_token" ON "swap_history_rollups" ("token");
+
+CREATE TABLE "points_history_rollups"
+(
+    "month" date NOT NULL,
+    "network" character varying(32) NOT NULL,
+    "token" character(42) NOT NULL,
+    "account" character(42) NOT NULL,
+    "description" character varying(255) NOT NULL,
+    "points" numeric(16, 3) NOT NULL DEFAULT 0,
+    "awards" integer NOT NULL DEFAULT 0,
+    PRIMARY KEY ("month", "network", "token", "account", "description")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_points_history_rollups_account" ON "points_history_rollups" ("account");
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "settlement_transactions"
+(
+    "id" SERIAL PRIMARY KEY,
+    "distribution_id" integer NOT NULL REFERENCES "merkle_distributions" ("id") ON DELETE CASCADE,
+    "action" character varying(32) NOT NULL,
+    "network" character varying(32) NOT NULL,
+    "hash" character(66) NOT NULL UNIQUE,
+    "sender" character(42) NOT NULL,
+    "nonce" bigint NOT NULL,
+    "status" character varying(16) NOT NULL DEFAULT 'pending',
+    "block_number" bigint,
+    "gas_used" bigint,
+    "created_at" timestamp with time zone NOT NULL DEFAULT now(),
+    "updated_at" timestamp with time zone NOT NULL DEFAULT now()
+);
+
+-- A distribution is funded and gets its root once, unless the transaction failed
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_settlement_transactions_distribution_action" ON "settlement_transactions" ("distribution_id", "action") WHERE "status" <> 'failed';
+CREATE INDEX IF NOT EXISTS "idx_settlement_transactions_pending" ON "settlement_transactions" ("network", "id") WHERE "status" = 'pending';
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DELETE FROM "settlement_transactions" WHERE "status" = 'replaced';
+
+DROP INDEX IF EXISTS "idx_settlement_transactions_distribution_action";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_settlement_transactions_distribution_action" ON "settlement_transactions" ("distribution_id", "action") WHERE "status" <> 'failed';
+
+ALTER TABLE "settlement_transactions" DROP COLUMN IF EXISTS "replaces";
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- A replacement is sent with the nonce of the transaction it replaces, which becomes 'replaced'
+ALTER TABLE "settlement_transactions" ADD COLUMN IF NOT EXISTS "replaces" integer REFERENCES "settlement_transactions" ("id");
+
+DROP INDEX IF EXISTS "idx_settlement_transactions_distribution_action";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_settlement_transactions_distribution_action" ON "settlement_transactions" ("distribution_id", "action") WHERE "status" NOT IN ('failed', 'replaced');
+
+COMMIT;
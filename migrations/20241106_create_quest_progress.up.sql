@@ -0,0 +1,19 @@
+BEGIN;
+
+-- Progress of the accounts on the quests of the configuration, by quest id: the distinct days, or
+-- the USD counted, and the current run of consecutive days ending on last_day
+CREATE TABLE IF NOT EXISTS "quest_progress"
+(
+    "quest_id" character varying(64) NOT NULL,
+    "account" character(42) NOT NULL,
+    "progress" numeric(24, 6) NOT NULL DEFAULT 0,
+    "streak" integer NOT NULL DEFAULT 0,
+    "last_day" date,
+    "completed_at" timestamp with time zone,
+    "updated_at" timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY ("quest_id", "account")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_quest_progress_account" ON "quest_progress" ("account");
+
+COMMIT;
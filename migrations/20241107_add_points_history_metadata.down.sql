@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE "points_history" DROP COLUMN IF EXISTS "metadata";
+
+COMMIT;
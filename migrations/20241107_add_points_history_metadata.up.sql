@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Where the points of an award came from: its campaign, source swap and boost
+ALTER TABLE "points_history" ADD COLUMN "metadata" jsonb;
+
+COMMIT;
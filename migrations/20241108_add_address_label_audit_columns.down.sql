@@ -0,0 +1,10 @@
+BEGIN;
+
+DELETE FROM "address_labels" WHERE "deleted_at" IS NOT NULL;
+
+ALTER TABLE "address_labels"
+    DROP COLUMN IF EXISTS "created_by",
+    DROP COLUMN IF EXISTS "updated_by",
+    DROP COLUMN IF EXISTS "deleted_at";
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Operators who created and last changed a label, and when it was removed: removed labels are kept
+-- for review and ignored by every read
+ALTER TABLE "address_labels"
+    ADD COLUMN "created_by" character varying(128) NOT NULL DEFAULT '',
+    ADD COLUMN "updated_by" character varying(128) NOT NULL DEFAULT '',
+    ADD COLUMN "deleted_at" timestamp with time zone;
+
+COMMIT;
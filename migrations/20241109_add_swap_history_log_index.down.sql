@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "idx_swap_history_log";
+ALTER TABLE "swap_history" DROP COLUMN IF EXISTS "log_index";
+
+COMMIT;
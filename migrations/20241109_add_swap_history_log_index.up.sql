@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Position of the Swap log in its transaction, so a redelivered log is recorded once. Swaps recorded
+-- before it was kept get the negated id, which never collides with a log index
+ALTER TABLE "swap_history" ADD COLUMN IF NOT EXISTS "log_index" integer;
+UPDATE "swap_history" SET "log_index" = -"id" WHERE "log_index" IS NULL;
+ALTER TABLE "swap_history" ALTER COLUMN "log_index" SET NOT NULL;
+
+-- The partition key is part of every unique index of a partitioned table
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_swap_history_log" ON "swap_history" ("network", "transaction_hash", "log_index", "last_updated");
+
+COMMIT;
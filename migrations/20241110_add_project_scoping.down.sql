@@ -0,0 +1,52 @@
+BEGIN;
+
+-- The rows of the projects other than the default one have no place without the scoping
+DELETE FROM "address_labels" WHERE "project_id" <> 0;
+ALTER TABLE "address_labels" DROP CONSTRAINT "address_labels_pkey";
+ALTER TABLE "address_labels" DROP COLUMN "project_id";
+ALTER TABLE "address_labels" ADD PRIMARY KEY ("address", "label");
+
+DELETE FROM "quest_progress" WHERE "project_id" <> 0;
+ALTER TABLE "quest_progress" DROP CONSTRAINT "quest_progress_pkey";
+ALTER TABLE "quest_progress" DROP COLUMN "project_id";
+ALTER TABLE "quest_progress" ADD PRIMARY KEY ("quest_id", "account");
+
+DELETE FROM "merkle_distributions" WHERE "project_id" <> 0;
+DROP INDEX IF EXISTS "idx_merkle_distributions_project_id";
+ALTER TABLE "merkle_distributions" DROP COLUMN "project_id";
+
+DELETE FROM "point_claims" WHERE "project_id" <> 0;
+DROP INDEX IF EXISTS "idx_point_claims_project_id_address";
+ALTER TABLE "point_claims" DROP COLUMN "project_id";
+CREATE INDEX IF NOT EXISTS "idx_point_claims_address" ON "point_claims" ("address", "id");
+
+DELETE FROM "daily_user_pool_stats" WHERE "project_id" <> 0;
+ALTER TABLE "daily_user_pool_stats" DROP CONSTRAINT "daily_user_pool_stats_pkey";
+ALTER TABLE "daily_user_pool_stats" DROP COLUMN "project_id";
+ALTER TABLE "daily_user_pool_stats" ADD PRIMARY KEY ("day", "token", "account");
+
+DELETE FROM "points_history_rollups" WHERE "project_id" <> 0;
+ALTER TABLE "points_history_rollups" DROP CONSTRAINT "points_history_rollups_pkey";
+ALTER TABLE "points_history_rollups" DROP COLUMN "project_id";
+ALTER TABLE "points_history_rollups" ADD PRIMARY KEY ("month", "network", "token", "account", "description");
+
+DELETE FROM "points_onboarding" WHERE "project_id" <> 0;
+ALTER TABLE "points_onboarding" DROP CONSTRAINT "points_onboarding_pkey";
+ALTER TABLE "points_onboarding" DROP COLUMN "project_id";
+ALTER TABLE "points_onboarding" ADD PRIMARY KEY ("account");
+
+DELETE FROM "points_history" WHERE "project_id" <> 0;
+DROP INDEX IF EXISTS "idx_points_history_project_id_account_token_created_at_id";
+ALTER TABLE "points_history" DROP COLUMN "project_id";
+CREATE INDEX IF NOT EXISTS "idx_points_history_account_token_created_at_id" ON "points_history" ("account", "token", "created_at" DESC, "id" DESC);
+
+DELETE FROM "users" WHERE "project_id" <> 0;
+DROP INDEX IF EXISTS "idx_users_project_id_total_points_id";
+ALTER TABLE "users" DROP CONSTRAINT "users_project_id_address_key";
+ALTER TABLE "users" DROP COLUMN "project_id";
+ALTER TABLE "users" ADD CONSTRAINT "users_address_key" UNIQUE ("address");
+CREATE INDEX IF NOT EXISTS "idx_users_total_points_id" ON "users" ("total_points" DESC, "id" DESC);
+
+DELETE FROM "projects" WHERE "id" = 0;
+
+COMMIT;
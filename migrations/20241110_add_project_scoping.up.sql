@@ -0,0 +1,46 @@
+BEGIN;
+
+-- The project of the data kept before projects scoped it. Serial ids start at 1, so 0 never
+-- collides with a created project
+INSERT INTO "projects" ("id", "slug", "name") VALUES (0, 'default', 'Default') ON CONFLICT DO NOTHING;
+
+-- Points, claims, distributions, quests and labels belong to the reward program of a project; the
+-- indexed chain data stays shared by every project
+ALTER TABLE "users" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0 REFERENCES "projects" ("id");
+ALTER TABLE "users" DROP CONSTRAINT "users_address_key";
+ALTER TABLE "users" ADD CONSTRAINT "users_project_id_address_key" UNIQUE ("project_id", "address");
+DROP INDEX IF EXISTS "idx_users_total_points_id";
+CREATE INDEX IF NOT EXISTS "idx_users_project_id_total_points_id" ON "users" ("project_id", "total_points" DESC, "id" DESC);
+
+ALTER TABLE "points_history" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0;
+DROP INDEX IF EXISTS "idx_points_history_account_token_created_at_id";
+CREATE INDEX IF NOT EXISTS "idx_points_history_project_id_account_token_created_at_id" ON "points_history" ("project_id", "account", "token", "created_at" DESC, "id" DESC);
+
+ALTER TABLE "points_onboarding" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0 REFERENCES "projects" ("id");
+ALTER TABLE "points_onboarding" DROP CONSTRAINT "points_onboarding_pkey";
+ALTER TABLE "points_onboarding" ADD PRIMARY KEY ("project_id", "account");
+
+ALTER TABLE "points_history_rollups" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0;
+ALTER TABLE "points_history_rollups" DROP CONSTRAINT "points_history_rollups_pkey";
+ALTER TABLE "points_history_rollups" ADD PRIMARY KEY ("project_id", "month", "network", "token", "account", "description");
+
+ALTER TABLE "daily_user_pool_stats" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0;
+ALTER TABLE "daily_user_pool_stats" DROP CONSTRAINT "daily_user_pool_stats_pkey";
+ALTER TABLE "daily_user_pool_stats" ADD PRIMARY KEY ("project_id", "day", "token", "account");
+
+ALTER TABLE "point_claims" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0 REFERENCES "projects" ("id");
+DROP INDEX IF EXISTS "idx_point_claims_address";
+CREATE INDEX IF NOT EXISTS "idx_point_claims_project_id_address" ON "point_claims" ("project_id", "address", "id");
+
+ALTER TABLE "merkle_distributions" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0 REFERENCES "projects" ("id");
+CREATE INDEX IF NOT EXISTS "idx_merkle_distributions_project_id" ON "merkle_distributions" ("project_id", "id");
+
+ALTER TABLE "quest_progress" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0 REFERENCES "projects" ("id");
+ALTER TABLE "quest_progress" DROP CONSTRAINT "quest_progress_pkey";
+ALTER TABLE "quest_progress" ADD PRIMARY KEY ("project_id", "quest_id", "account");
+
+ALTER TABLE "address_labels" ADD COLUMN "project_id" integer NOT NULL DEFAULT 0 REFERENCES "projects" ("id");
+ALTER TABLE "address_labels" DROP CONSTRAINT "address_labels_pkey";
+ALTER TABLE "address_labels" ADD PRIMARY KEY ("project_id", "address", "label");
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DELETE FROM "settlement_transactions" WHERE "hash" IS NULL OR "nonce" IS NULL;
+
+ALTER TABLE "settlement_transactions" ALTER COLUMN "hash" SET NOT NULL;
+ALTER TABLE "settlement_transactions" ALTER COLUMN "nonce" SET NOT NULL;
+
+COMMIT;
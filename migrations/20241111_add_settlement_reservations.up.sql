@@ -0,0 +1,7 @@
+BEGIN;
+
+-- A settlement transaction is recorded as 'sending' before it is sent, without a hash and nonce yet
+ALTER TABLE "settlement_transactions" ALTER COLUMN "hash" DROP NOT NULL;
+ALTER TABLE "settlement_transactions" ALTER COLUMN "nonce" DROP NOT NULL;
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+ALTER TABLE "daily_user_pool_stats" DROP CONSTRAINT "daily_user_pool_stats_pkey";
+
+-- The rollups of a pool on several networks are merged back into one
+CREATE TEMPORARY TABLE "daily_user_pool_stats_merged" ON COMMIT DROP AS
+SELECT "project_id", "day", "token", "account", SUM("usd_value") AS "usd_value", SUM("counted_usd_value") AS "counted_usd_value",
+    SUM("swap_count") AS "swap_count", SUM("points") AS "points"
+FROM "daily_user_pool_stats"
+GROUP BY "project_id", "day", "token", "account";
+
+DELETE FROM "daily_user_pool_stats";
+ALTER TABLE "daily_user_pool_stats" DROP COLUMN "network";
+INSERT INTO "daily_user_pool_stats" ("project_id", "day", "token", "account", "usd_value", "counted_usd_value", "swap_count", "points")
+SELECT "project_id", "day", "token", "account", "usd_value", "counted_usd_value", "swap_count", "points" FROM "daily_user_pool_stats_merged";
+ALTER TABLE "daily_user_pool_stats" ADD PRIMARY KEY ("project_id", "day", "token", "account");
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Rollups are kept per network, so the volume of a pool on one chain is not counted on another
+ALTER TABLE "daily_user_pool_stats" ADD COLUMN "network" character varying(32) NOT NULL DEFAULT 'mainnet';
+
+-- The rollups kept so far take the network of the swaps and points of their pool. A pool address
+-- recorded on several networks cannot be split and stays on mainnet
+UPDATE "daily_user_pool_stats" SET "network" = "pools"."network"
+FROM (
+    SELECT "token", MIN("network") AS "network"
+    FROM (
+        SELECT DISTINCT "token", "network" FROM "swap_history"
+        UNION
+        SELECT DISTINCT "token", "network" FROM "points_history"
+    ) AS "networks"
+    GROUP BY "token"
+    HAVING COUNT(*) = 1
+) AS "pools"
+WHERE "daily_user_pool_stats"."token" = "pools"."token";
+
+ALTER TABLE "daily_user_pool_stats" DROP CONSTRAINT "daily_user_pool_stats_pkey";
+ALTER TABLE "daily_user_pool_stats" ADD PRIMARY KEY ("project_id", "day", "network", "token", "account");
+
+COMMIT;
@@ -6,7 +6,6 @@ import (
 	"math/big"
 	"runtime"
 	"strings"
-	"sync"
 
 	"hw/pkg/logger"
 
@@ -14,193 +13,274 @@ import (
 	"github.com/spf13/cast"
 )
 
-// BigN represents a high-precision number and its possible error.
+// BigN represents a high-precision number and the first error of the chain of operations
+// that produced it. BigN is immutable: every operation returns a new value, so a BigN can be
+// copied and shared between goroutines without locking. Once an operation fails, the error is
+// carried through the rest of the chain and reported by Error.
 type BigN struct {
 	num decimal.Decimal
 	err error
-	mu  sync.Mutex
 }
 
-// NewBigN creates a new instance of BigN.
-func NewBigN(num interface{}) *BigN {
+// New creates a BigN from an int, int64, uint8, uint64, float64, decimal or hex string,
+// *big.Int, decimal.Decimal or another BigN.
+func New(num interface{}) BigN {
 	dec, err := coverToDecimal(num)
-	bn := &BigN{num: dec, err: nil}
 	if err != nil {
-		bn.err = err
-		return bn
+		return BigN{err: err}
 	}
-	return bn
+	return BigN{num: dec}
 }
 
-// Add adds the given number to BigN.
-func (bn *BigN) Add(n interface{}) *BigN {
-	newBN := &BigN{}
-
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
+// NewBigN creates a new instance of BigN. It is kept for callers holding *BigN; New returns the value.
+func NewBigN(num interface{}) *BigN {
+	bn := New(num)
+	return &bn
+}
 
+// apply combines BigN with the given number, carrying the first error of the chain.
+func (bn BigN) apply(n interface{}, op func(a, b decimal.Decimal) decimal.Decimal) BigN {
 	if bn.err != nil {
-		newBN.err = bn.err
-		return newBN
+		return bn
 	}
 
 	dec, err := coverToDecimal(n)
 	if err != nil {
-		newBN.err = err
-		return newBN
+		return BigN{err: err}
 	}
+	return BigN{num: op(bn.num, dec)}
+}
 
-	newBN.num = bn.num.Add(dec)
-	return newBN
+// Add adds the given number to BigN.
+func (bn BigN) Add(n interface{}) BigN {
+	return bn.apply(n, decimal.Decimal.Add)
 }
 
 // Sub subtracts the given number from BigN.
-func (bn *BigN) Sub(n interface{}) *BigN {
-	newBN := &BigN{}
-
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
-
-	if bn.err != nil {
-		newBN.err = bn.err
-		return newBN
-	}
-
-	dec, err := coverToDecimal(n)
-	if err != nil {
-		newBN.err = err
-		return newBN
-	}
-
-	newBN.num = bn.num.Sub(dec)
-	return newBN
+func (bn BigN) Sub(n interface{}) BigN {
+	return bn.apply(n, decimal.Decimal.Sub)
 }
 
 // Mul multiplies BigN by the given number.
-func (bn *BigN) Mul(n interface{}) *BigN {
-	newBN := &BigN{}
-
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
-
-	if bn.err != nil {
-		newBN.err = bn.err
-		return newBN
-	}
-
-	dec, err := coverToDecimal(n)
-	if err != nil {
-		newBN.err = err
-		return newBN
-	}
-
-	newBN.num = bn.num.Mul(dec)
-	return newBN
+func (bn BigN) Mul(n interface{}) BigN {
+	return bn.apply(n, decimal.Decimal.Mul)
 }
 
 // Pow raises BigN to the given exponent.
-func (bn *BigN) Pow(n int64) *BigN {
-	newBN := &BigN{}
-
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
+func (bn BigN) Pow(n int64) BigN {
+	if bn.err != nil {
+		return bn
+	}
 
 	if n < 0 {
-		newBN.err = fmt.Errorf("invalid exponent: negative value")
-		return newBN
+		return BigN{err: fmt.Errorf("invalid exponent: negative value")}
 	}
-
-	exponent := decimal.NewFromInt(n)
-	newBN.num = bn.num.Pow(exponent)
-	return newBN
+	return BigN{num: bn.num.Pow(decimal.NewFromInt(n))}
 }
 
 // Div divides BigN by the given number.
-func (bn *BigN) Div(n interface{}) *BigN {
-	newBN := &BigN{}
-
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
-
+func (bn BigN) Div(n interface{}) BigN {
 	if bn.err != nil {
-		newBN.err = bn.err
-		return newBN
+		return bn
 	}
 
 	d, err := coverToDecimal(n)
 	if err != nil {
-		newBN.err = err
-		return newBN
+		return BigN{err: err}
 	}
 
 	if d.IsZero() {
 		pc, file, line, ok := runtime.Caller(1)
 		if !ok {
-			newBN.err = fmt.Errorf("no caller information")
-			return newBN
+			return BigN{err: fmt.Errorf("no caller information")}
 		}
 		fn := runtime.FuncForPC(pc)
-		newBN.err = fmt.Errorf("division by zero at %s - %s:%d", fn.Name(), file, line)
-		logger.Warnf("b.num %+v, div num is zero %+v %+v", bn.num.String(), n, newBN.err)
-		return newBN
+		err := fmt.Errorf("division by zero at %s - %s:%d", fn.Name(), file, line)
+		logger.Warnf("b.num %+v, div num is zero %+v %+v", bn.num.String(), n, err)
+		return BigN{err: err}
 	}
 
-	newBN.num = bn.num.Div(d)
-	return newBN
+	return BigN{num: bn.num.Div(d)}
 }
 
-// ToTruncateString truncates BigN to the specified number of decimal places and returns it as a string.
-func (bn *BigN) ToTruncateString(d int32) string {
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
+// Truncate truncates BigN to the specified number of decimal places.
+func (bn BigN) Truncate(d int32) BigN {
+	if bn.err != nil {
+		return bn
+	}
+
+	if d < 0 {
+		return BigN{err: fmt.Errorf("invalid decimal places: negative value")}
+	}
+	return BigN{num: bn.num.Truncate(d)}
+}
 
+// ToTruncateString truncates BigN to the specified number of decimal places and returns it as a string.
+// Negative decimal places return the number untruncated; Truncate(d).Error() reports them.
+func (bn BigN) ToTruncateString(d int32) string {
 	if d < 0 {
-		bn.err = fmt.Errorf("invalid decimal places: negative value")
 		return bn.num.String()
 	}
 	return bn.num.Truncate(d).StringFixed(d)
 }
 
 // ToTruncateInt64 truncates BigN to the specified number of decimal places and returns it as int64.
-func (bn *BigN) ToTruncateInt64(d int32) int64 {
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
-
+// Negative decimal places return 0.
+func (bn BigN) ToTruncateInt64(d int32) int64 {
 	if d < 0 {
-		bn.err = fmt.Errorf("invalid decimal places: negative value")
 		return 0
 	}
 	return bn.num.Truncate(d).IntPart()
 }
 
 // ToTruncateFloat64 truncates BigN to the specified number of decimal places and returns it as float64.
-func (bn *BigN) ToTruncateFloat64(d int32) float64 {
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
-
+// Negative decimal places return 0.
+func (bn BigN) ToTruncateFloat64(d int32) float64 {
 	if d < 0 {
-		bn.err = fmt.Errorf("invalid decimal places: negative value")
 		return 0.0
 	}
 	f64, _ := bn.num.Truncate(d).Float64()
-	// if !exact {
-	// 	logger.Warnf("bn.num %+v, truncate to float64 %+v, not exact", bn.num.String(), f64)
-	// }
 	return f64
 }
 
-// Error returns the error in BigN.
-func (bn *BigN) Error() error {
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
+// ToTruncateDecimal truncates BigN to the specified number of decimal places and returns it as a decimal.
+// Negative decimal places return zero.
+func (bn BigN) ToTruncateDecimal(d int32) decimal.Decimal {
+	if d < 0 {
+		return decimal.Zero
+	}
+	return bn.num.Truncate(d)
+}
+
+// RoundingMode selects how Round discards digits beyond the requested decimal places.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, halves away from zero (1.25 -> 1.3, -1.25 -> -1.3).
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, halves to the even digit (1.25 -> 1.2, 1.35 -> 1.4).
+	RoundHalfEven
+	// RoundDown rounds toward zero, the same as truncating (1.29 -> 1.2, -1.29 -> -1.2).
+	RoundDown
+	// RoundUp rounds away from zero (1.21 -> 1.3, -1.21 -> -1.3).
+	RoundUp
+	// RoundCeiling rounds toward positive infinity (1.21 -> 1.3, -1.29 -> -1.2).
+	RoundCeiling
+	// RoundFloor rounds toward negative infinity (1.29 -> 1.2, -1.21 -> -1.3).
+	RoundFloor
+)
+
+// Round rounds BigN to the specified number of decimal places using the given mode.
+func (bn BigN) Round(d int32, mode RoundingMode) BigN {
+	if bn.err != nil {
+		return bn
+	}
+
+	if d < 0 {
+		return BigN{err: fmt.Errorf("invalid decimal places: negative value")}
+	}
+
+	switch mode {
+	case RoundHalfUp:
+		return BigN{num: bn.num.Round(d)}
+	case RoundHalfEven:
+		return BigN{num: bn.num.RoundBank(d)}
+	case RoundDown:
+		return BigN{num: bn.num.Truncate(d)}
+	case RoundUp:
+		if bn.num.Sign() < 0 {
+			return BigN{num: bn.num.Shift(d).Floor().Shift(-d)}
+		}
+		return BigN{num: bn.num.Shift(d).Ceil().Shift(-d)}
+	case RoundCeiling:
+		return BigN{num: bn.num.Shift(d).Ceil().Shift(-d)}
+	case RoundFloor:
+		return BigN{num: bn.num.Shift(d).Floor().Shift(-d)}
+	default:
+		return BigN{err: fmt.Errorf("invalid rounding mode: %d", mode)}
+	}
+}
+
+// Ceil rounds BigN toward positive infinity to the specified number of decimal places.
+func (bn BigN) Ceil(d int32) BigN {
+	return bn.Round(d, RoundCeiling)
+}
+
+// Floor rounds BigN toward negative infinity to the specified number of decimal places.
+func (bn BigN) Floor(d int32) BigN {
+	return bn.Round(d, RoundFloor)
+}
+
+// Abs returns the absolute value of BigN.
+func (bn BigN) Abs() BigN {
+	if bn.err != nil {
+		return bn
+	}
+	return BigN{num: bn.num.Abs()}
+}
+
+// Neg returns BigN with its sign flipped.
+func (bn BigN) Neg() BigN {
+	if bn.err != nil {
+		return bn
+	}
+	return BigN{num: bn.num.Neg()}
+}
+
+// Min returns the smaller of BigN and the given number.
+func (bn BigN) Min(n interface{}) BigN {
+	return bn.apply(n, func(a, b decimal.Decimal) decimal.Decimal { return decimal.Min(a, b) })
+}
+
+// Max returns the larger of BigN and the given number.
+func (bn BigN) Max(n interface{}) BigN {
+	return bn.apply(n, func(a, b decimal.Decimal) decimal.Decimal { return decimal.Max(a, b) })
+}
+
+// Cmp compares BigN with the given number and returns -1, 0 or +1. It returns 0 if either side is invalid.
+func (bn BigN) Cmp(n interface{}) int {
+	result, _ := bn.cmp(n)
+	return result
+}
+
+// Eq reports whether BigN equals the given number. It is false if either side is invalid.
+func (bn BigN) Eq(n interface{}) bool {
+	result, ok := bn.cmp(n)
+	return ok && result == 0
+}
+
+// Lt reports whether BigN is less than the given number. It is false if either side is invalid.
+func (bn BigN) Lt(n interface{}) bool {
+	result, ok := bn.cmp(n)
+	return ok && result < 0
+}
+
+// Gt reports whether BigN is greater than the given number. It is false if either side is invalid.
+func (bn BigN) Gt(n interface{}) bool {
+	result, ok := bn.cmp(n)
+	return ok && result > 0
+}
+
+// cmp compares BigN with the given number, reporting false if either side is invalid.
+func (bn BigN) cmp(n interface{}) (int, bool) {
+	if bn.err != nil {
+		return 0, false
+	}
+
+	dec, err := coverToDecimal(n)
+	if err != nil {
+		return 0, false
+	}
+	return bn.num.Cmp(dec), true
+}
+
+// Error returns the first error of the chain that produced BigN.
+func (bn BigN) Error() error {
 	return bn.err
 }
 
 // ToMoneyString formats BigN as a currency string with thousand separators.
-func (bn *BigN) ToMoneyString(decimals int32) string {
-	bn.mu.Lock()
-	defer bn.mu.Unlock()
-
+func (bn BigN) ToMoneyString(decimals int32) string {
 	// Truncate the number to the specified decimal places
 	truncated := bn.num.Truncate(decimals)
 
@@ -267,8 +347,12 @@ func coverToDecimal(num interface{}) (decimal.Decimal, error) {
 		return decimal.Zero, fmt.Errorf("coverToDecimal: unsupported string format")
 	case *big.Int:
 		return decimal.NewFromBigInt(v, 0), nil
+	case decimal.Decimal:
+		return v, nil
+	case BigN:
+		return v.num, v.err
 	case *BigN:
-		return v.num, nil
+		return v.num, v.err
 	default:
 		return decimal.Zero, fmt.Errorf("coverToDecimal: unsupported type %T", num)
 	}
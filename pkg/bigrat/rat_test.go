@@ -263,3 +263,187 @@ func TestToMoneyString(t *testing.T) {
 		})
 	}
 }
+
+func TestRoundOperations(t *testing.T) {
+	testCases := []struct {
+		input       string
+		decimals    int32
+		mode        RoundingMode
+		expected    string
+		description string
+	}{
+		{"1.25", 1, RoundHalfUp, "1.3", "Round half up 1.25"},
+		{"-1.25", 1, RoundHalfUp, "-1.3", "Round half up -1.25"},
+		{"1.25", 1, RoundHalfEven, "1.2", "Round half even 1.25"},
+		{"1.35", 1, RoundHalfEven, "1.4", "Round half even 1.35"},
+		{"1.29", 1, RoundDown, "1.2", "Round down 1.29"},
+		{"-1.29", 1, RoundDown, "-1.2", "Round down -1.29"},
+		{"1.21", 1, RoundUp, "1.3", "Round up 1.21"},
+		{"-1.21", 1, RoundUp, "-1.3", "Round up -1.21"},
+		{"1.2", 1, RoundUp, "1.2", "Round up exact 1.2"},
+		{"1.21", 1, RoundCeiling, "1.3", "Round ceiling 1.21"},
+		{"-1.29", 1, RoundCeiling, "-1.2", "Round ceiling -1.29"},
+		{"1.29", 1, RoundFloor, "1.2", "Round floor 1.29"},
+		{"-1.21", 1, RoundFloor, "-1.3", "Round floor -1.21"},
+		{"99.9995", 3, RoundHalfUp, "100.000", "Round half up carries into the integer part"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			result := NewBigN(tc.input).Round(tc.decimals, tc.mode).ToTruncateString(tc.decimals)
+			if result != tc.expected {
+				t.Errorf("Round failed: got %v, want %v", result, tc.expected)
+			}
+		})
+	}
+
+	t.Run("Ceil and Floor", func(t *testing.T) {
+		if result := NewBigN("0.0001").Ceil(2).ToTruncateString(2); result != "0.01" {
+			t.Errorf("Ceil failed: got %v, want 0.01", result)
+		}
+		if result := NewBigN("-0.0001").Floor(2).ToTruncateString(2); result != "-0.01" {
+			t.Errorf("Floor failed: got %v, want -0.01", result)
+		}
+	})
+
+	t.Run("Negative decimals", func(t *testing.T) {
+		if err := NewBigN("1.5").Round(-1, RoundHalfUp).Error(); err == nil {
+			t.Errorf("Expected error for negative decimal places, got nil")
+		}
+	})
+
+	t.Run("Unknown mode", func(t *testing.T) {
+		if err := NewBigN("1.5").Round(0, RoundingMode(99)).Error(); err == nil {
+			t.Errorf("Expected error for unknown rounding mode, got nil")
+		}
+	})
+}
+
+func TestComparisonOperations(t *testing.T) {
+	t.Run("Cmp", func(t *testing.T) {
+		testCases := []struct {
+			a        BigN
+			b        interface{}
+			expected int
+		}{
+			{New("1.5"), 2, -1},
+			{New("1000"), "1000.000", 0},
+			{New("0.1").Add(0.2), "0.3", 0},
+			{New(-3), NewBigN(-4), 1},
+		}
+		for _, tc := range testCases {
+			if result := tc.a.Cmp(tc.b); result != tc.expected {
+				t.Errorf("Cmp(%v) failed: got %v, want %v", tc.b, result, tc.expected)
+			}
+		}
+	})
+
+	t.Run("Eq, Lt and Gt", func(t *testing.T) {
+		n := NewBigN("999.999")
+		if n.Eq(1000) || !n.Lt(1000) || n.Gt(1000) {
+			t.Errorf("999.999 compared to 1000: Eq=%v Lt=%v Gt=%v", n.Eq(1000), n.Lt(1000), n.Gt(1000))
+		}
+		if !n.Eq("999.999") {
+			t.Errorf("Expected 999.999 to equal itself")
+		}
+	})
+
+	t.Run("Invalid operand", func(t *testing.T) {
+		n := NewBigN(1)
+		if n.Eq("invalid") || n.Lt("invalid") || n.Gt("invalid") {
+			t.Errorf("Expected comparisons with an invalid operand to be false")
+		}
+		if n.Cmp("invalid") != 0 {
+			t.Errorf("Expected Cmp with an invalid operand to be 0")
+		}
+		if n.Error() != nil {
+			t.Errorf("Expected the receiver to be unchanged, got %v", n.Error())
+		}
+	})
+}
+
+func TestSignAndBoundOperations(t *testing.T) {
+	testCases := []struct {
+		input       func() BigN
+		expected    string
+		description string
+	}{
+		{func() BigN { return NewBigN("-12.5").Abs() }, "12.5", "Abs of -12.5"},
+		{func() BigN { return NewBigN("12.5").Abs() }, "12.5", "Abs of 12.5"},
+		{func() BigN { return NewBigN("12.5").Neg() }, "-12.5", "Neg of 12.5"},
+		{func() BigN { return NewBigN("-12.5").Neg() }, "12.5", "Neg of -12.5"},
+		{func() BigN { return NewBigN("3").Min("2.5") }, "2.5", "Min of 3 and 2.5"},
+		{func() BigN { return NewBigN("3").Max("2.5") }, "3", "Max of 3 and 2.5"},
+		{func() BigN { return NewBigN("-1").Min(NewBigN("-1.5")) }, "-1.5", "Min of -1 and -1.5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			result := tc.input()
+			if result.Error() != nil {
+				t.Fatalf("Unexpected error: %v", result.Error())
+			}
+			if result.num.String() != tc.expected {
+				t.Errorf("%s failed: got %v, want %v", tc.description, result.num.String(), tc.expected)
+			}
+		})
+	}
+
+	t.Run("Error propagation", func(t *testing.T) {
+		if err := NewBigN("invalid").Abs().Error(); err == nil {
+			t.Errorf("Expected error to propagate through Abs, got nil")
+		}
+		if err := NewBigN(1).Max("invalid").Error(); err == nil {
+			t.Errorf("Expected error for invalid Max operand, got nil")
+		}
+	})
+}
+
+func TestValueSemantics(t *testing.T) {
+	t.Run("operations leave the receiver unchanged", func(t *testing.T) {
+		base := New("1.5")
+		sum := base.Add(1)
+		if base.ToTruncateString(1) != "1.5" || sum.ToTruncateString(1) != "2.5" {
+			t.Errorf("Add changed the receiver: base %v, sum %v", base.ToTruncateString(1), sum.ToTruncateString(1))
+		}
+	})
+
+	t.Run("operand errors are carried through the chain", func(t *testing.T) {
+		invalid := New(1).Div(0)
+		result := New(10).Add(invalid).Mul(2)
+		if result.Error() == nil {
+			t.Errorf("Expected error from the invalid operand, got nil")
+		}
+	})
+
+	t.Run("shared between goroutines", func(t *testing.T) {
+		shared := New("1000.123456")
+		results := make(chan string, 8)
+		for i := 0; i < cap(results); i++ {
+			go func(i int) {
+				results <- shared.Mul(i).Div(i + 1).ToTruncateString(6)
+			}(i)
+		}
+		for i := 0; i < cap(results); i++ {
+			<-results
+		}
+		if shared.ToTruncateString(6) != "1000.123456" {
+			t.Errorf("Shared value changed: got %v", shared.ToTruncateString(6))
+		}
+	})
+}
+
+func BenchmarkChain(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = New("1234.5678").Mul("0.997").Div(3).Round(6, RoundHalfEven).ToTruncateString(6)
+	}
+}
+
+func BenchmarkChainParallel(b *testing.B) {
+	shared := New("1234.5678")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = shared.Mul("0.997").Div(3).Round(6, RoundHalfEven).ToTruncateString(6)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hw/pkg/config"
+)
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// Supported providers.
+const (
+	ProviderS3   = "s3"
+	ProviderGCS  = "gcs"
+	ProviderFile = "file"
+)
+
+// Store defines the methods for reading and writing objects in a bucket.
+type Store interface {
+	// Put creates or replaces an object.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get returns the content of an object, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// List returns the keys starting with prefix in lexical order.
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates a Store for the configured provider.
+func New(cfg config.Blobstore) (Store, error) {
+	switch cfg.Provider {
+	case ProviderS3:
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("blobstore bucket is required for provider %s", cfg.Provider)
+		}
+		return NewS3Store(S3Config{
+			Endpoint:        cfg.Endpoint,
+			Region:          cfg.Region,
+			Bucket:          cfg.Bucket,
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+		}), nil
+	case ProviderGCS:
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("blobstore bucket is required for provider %s", cfg.Provider)
+		}
+		return NewGCSStore(cfg.Bucket, cfg.AccessKeyID, cfg.SecretAccessKey), nil
+	case ProviderFile:
+		return NewFileStore(cfg.Dir)
+	default:
+		return nil, fmt.Errorf("unknown blobstore provider: %s", cfg.Provider)
+	}
+}
@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tagTTL is how long Redis tracks the keys of a tag after a key was last tagged with it.
+const tagTTL = 24 * time.Hour
+
+// maxLocalTagKeys is the number of keys of a tag tracked in memory. Past it, the keys of the tag
+// are invalidated rather than tracked, bounding the memory held for keys long evicted.
+const maxLocalTagKeys = 10000
+
+// MGet decodes the values of keys stored with Set or MSet into objects, a pointer to a map keyed
+// by string, in one Redis round trip for the keys missing from the local cache. Missing keys are
+// left out of the map.
+func (c *cacheImpl) MGet(ctx context.Context, keys []string, objects interface{}) error {
+	target := reflect.ValueOf(objects)
+	if target.Kind() != reflect.Pointer || target.Elem().Kind() != reflect.Map || target.Elem().Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("objects must be a pointer to a map keyed by string, got %T", objects)
+	}
+	values := target.Elem()
+	if values.IsNil() {
+		values.Set(reflect.MakeMap(values.Type()))
+	}
+
+	local, remote := c.localStore(), c.remote()
+	found := make(map[string][]byte, len(keys))
+	var remoteKeys, remoteFormatted []string
+	for _, key := range keys {
+		formatted := c.FormatKey(key)
+		if local != nil {
+			if b, ok := local.Get(formatted); ok {
+				found[key] = b
+				continue
+			}
+		}
+		remoteKeys = append(remoteKeys, key)
+		remoteFormatted = append(remoteFormatted, formatted)
+	}
+	if remote != nil && len(remoteKeys) > 0 {
+		results, err := remote.MGet(ctx, remoteFormatted...).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get keys: %w", c.observe(err))
+		}
+		for i, result := range results {
+			// Missing keys are nil
+			if data, ok := result.(string); ok {
+				found[remoteKeys[i]] = []byte(data)
+				if local != nil {
+					local.Set(remoteFormatted[i], []byte(data))
+				}
+			}
+		}
+	}
+
+	for key, data := range found {
+		value := reflect.New(values.Type().Elem())
+		if err := c.cache.Unmarshal(data, value.Interface()); err != nil {
+			return fmt.Errorf("failed to decode key %s: %w", key, err)
+		}
+		values.SetMapIndex(reflect.ValueOf(key).Convert(values.Type().Key()), value.Elem())
+	}
+	return nil
+}
+
+// MSet stores values by key with the same TTL, in one Redis round trip.
+func (c *cacheImpl) MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	if ttl < 0 {
+		return fmt.Errorf("TTL cannot be negative")
+	}
+	if ttl == 0 {
+		ttl = c.defaultTTL
+	}
+
+	encoded := make(map[string][]byte, len(values))
+	for key, value := range values {
+		if value == nil {
+			return fmt.Errorf("value of %s cannot be nil", key)
+		}
+		data, err := c.cache.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode key %s: %w", key, err)
+		}
+		encoded[c.FormatKey(key)] = data
+	}
+
+	local := c.localStore()
+	var pipe redis.Pipeliner
+	if remote := c.remote(); remote != nil {
+		pipe = remote.Pipeline()
+	}
+	for key, data := range encoded {
+		if local != nil {
+			local.Set(key, data)
+		}
+		if pipe != nil {
+			pipe.Set(ctx, key, data, c.jitter(ttl))
+		}
+	}
+	if pipe != nil {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to set keys: %w", c.observe(err))
+		}
+	}
+	return nil
+}
+
+// Tag attaches tags to a key, e.g. the namespace of the data it holds, so that InvalidateTags
+// deletes it. With Redis the keys of a tag are shared by every process; otherwise, and while
+// Redis is unreachable, they are tracked in memory.
+func (c *cacheImpl) Tag(ctx context.Context, key string, tags ...string) error {
+	formatted := c.FormatKey(key)
+	remote := c.remote()
+	if remote == nil {
+		c.tagMutex.Lock()
+		defer c.tagMutex.Unlock()
+		if c.tags == nil {
+			c.tags = make(map[string]map[string]struct{})
+		}
+		for _, tag := range tags {
+			keys, exists := c.tags[tag]
+			if !exists {
+				keys = make(map[string]struct{})
+				c.tags[tag] = keys
+			}
+			keys[formatted] = struct{}{}
+			if len(keys) > maxLocalTagKeys {
+				c.invalidateLocalTag(tag)
+			}
+		}
+		return nil
+	}
+
+	pipe := remote.Pipeline()
+	for _, tag := range tags {
+		tagKey := c.tagKey(tag)
+		pipe.SAdd(ctx, tagKey, formatted)
+		pipe.Expire(ctx, tagKey, tagTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to tag key %s: %w", key, c.observe(err))
+	}
+	return nil
+}
+
+// InvalidateTags deletes the keys tagged with any of tags, e.g. every leaderboard key after
+// points were written. In a hybrid cache, the local copies held by other processes expire with
+// the TTL of the local cache, as do the keys tagged in Redis while it is unreachable.
+func (c *cacheImpl) InvalidateTags(ctx context.Context, tags ...string) error {
+	remote := c.remote()
+	if remote == nil {
+		c.tagMutex.Lock()
+		defer c.tagMutex.Unlock()
+		for _, tag := range tags {
+			c.invalidateLocalTag(tag)
+		}
+		return nil
+	}
+
+	for _, tag := range tags {
+		tagKey := c.tagKey(tag)
+		// Read and drop the keys of the tag atomically, so no key tagged meanwhile is missed
+		var members *redis.StringSliceCmd
+		_, err := remote.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			members = pipe.SMembers(ctx, tagKey)
+			pipe.Del(ctx, tagKey)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get the keys of tag %s: %w", tag, c.observe(err))
+		}
+		keys := members.Val()
+		if len(keys) == 0 {
+			continue
+		}
+		if c.local != nil {
+			for _, key := range keys {
+				c.local.Del(key)
+			}
+		}
+		if err := remote.Del(ctx, keys...).Err(); err != nil {
+			return fmt.Errorf("failed to invalidate tag %s: %w", tag, c.observe(err))
+		}
+	}
+	return nil
+}
+
+// invalidateLocalTag deletes the keys of a tag tracked in memory. The caller holds tagMutex.
+func (c *cacheImpl) invalidateLocalTag(tag string) {
+	local := c.localStore()
+	for key := range c.tags[tag] {
+		if local != nil {
+			local.Del(key)
+		}
+	}
+	delete(c.tags, tag)
+}
+
+// tagKey returns the Redis key of the set of keys of a tag.
+func (c *cacheImpl) tagKey(tag string) string {
+	return c.FormatKey("tag", tag)
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hw/pkg/config"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type leaderboardPage struct {
+	Addresses []string
+	Total     int
+}
+
+// TestMGetMSet tests that values stored together are read together, missing keys left out.
+func TestMGetMSet(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(config.Cache{Prefix: "test", DefaultTTL: time.Minute})
+
+	assert.NoError(t, MSet(ctx, c, map[string]leaderboardPage{
+		"page:1": {Addresses: []string{"0xa1", "0xa2"}, Total: 2},
+		"page:2": {Total: 2},
+	}, time.Minute))
+
+	pages, err := MGet[leaderboardPage](ctx, c, "page:1", "page:2", "page:3")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]leaderboardPage{
+		"page:1": {Addresses: []string{"0xa1", "0xa2"}, Total: 2},
+		"page:2": {Total: 2},
+	}, pages)
+
+	assert.ErrorContains(t, c.MSet(ctx, map[string]interface{}{"page:4": nil}, time.Minute), "cannot be nil")
+	var notAMap []string
+	assert.ErrorContains(t, c.MGet(ctx, []string{"page:1"}, &notAMap), "pointer to a map")
+}
+
+// TestMGetRedis tests that the keys missing from the local cache are read from Redis in one call.
+func TestMGetRedis(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	c := newCache(config.Cache{Prefix: "test", DefaultTTL: time.Minute}, nil, db)
+	ctx := context.Background()
+
+	encoded, err := c.cache.Marshal(leaderboardPage{Total: 5})
+	assert.NoError(t, err)
+	mock.ExpectMGet("test:page:1", "test:page:2").SetVal([]interface{}{string(encoded), nil})
+
+	pages, err := MGet[leaderboardPage](ctx, c, "page:1", "page:2")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]leaderboardPage{"page:1": {Total: 5}}, pages)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestInvalidateTags tests that invalidating a tag deletes its keys only.
+func TestInvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(config.Cache{DefaultTTL: time.Minute})
+
+	assert.NoError(t, c.Set(ctx, "leaderboard:mainnet", "top", time.Minute))
+	assert.NoError(t, c.Set(ctx, "leaderboard:base", "top", time.Minute))
+	assert.NoError(t, c.Set(ctx, "token:usdc", "USDC", time.Minute))
+	assert.NoError(t, c.Tag(ctx, "leaderboard:mainnet", "leaderboard"))
+	assert.NoError(t, c.Tag(ctx, "leaderboard:base", "leaderboard", "base"))
+	assert.NoError(t, c.Tag(ctx, "token:usdc", "tokens"))
+
+	assert.NoError(t, c.InvalidateTags(ctx, "leaderboard"))
+
+	var value string
+	assert.Error(t, c.Get(ctx, "leaderboard:mainnet", &value))
+	assert.Error(t, c.Get(ctx, "leaderboard:base", &value))
+	assert.NoError(t, c.Get(ctx, "token:usdc", &value))
+	assert.Equal(t, "USDC", value)
+	assert.NoError(t, c.InvalidateTags(ctx, "unknown"))
+}
+
+// TestInvalidateTagsRedis tests that the keys of a tag are tracked in a Redis set, read and
+// dropped atomically on invalidation.
+func TestInvalidateTagsRedis(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	c := newCache(config.Cache{Prefix: "test", DefaultTTL: time.Minute}, nil, db)
+	ctx := context.Background()
+
+	mock.ExpectSAdd("test:tag:leaderboard", "test:leaderboard:mainnet").SetVal(1)
+	mock.ExpectExpire("test:tag:leaderboard", tagTTL).SetVal(true)
+	assert.NoError(t, c.Tag(ctx, "leaderboard:mainnet", "leaderboard"))
+
+	mock.ExpectTxPipeline()
+	mock.ExpectSMembers("test:tag:leaderboard").SetVal([]string{"test:leaderboard:mainnet"})
+	mock.ExpectDel("test:tag:leaderboard").SetVal(1)
+	mock.ExpectTxPipelineExec()
+	mock.ExpectDel("test:leaderboard:mainnet").SetVal(1)
+	assert.NoError(t, c.InvalidateTags(ctx, "leaderboard"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,14 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"reflect"
-	"strconv"
+	"math/rand"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/singleflight"
 
-	"hw/pkg/common"
+	"hw/pkg/config"
+	"hw/pkg/logger"
 
 	"github.com/go-redis/cache/v9"
 	"github.com/redis/go-redis/v9"
@@ -23,85 +25,94 @@ import (
 type Cache interface {
 	Get(ctx context.Context, key string, object interface{}) error
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// Deprecated: use GetOrLoad, which checks the type of the value at compile time.
 	GetFunc(ctx context.Context, key string, obj interface{}, ttl time.Duration, fn func(ctx context.Context) (interface{}, error)) error
 	FormatKey(args ...interface{}) string
 	Del(ctx context.Context, key string) error
+	MGet(ctx context.Context, keys []string, objects interface{}) error
+	MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error
+	Tag(ctx context.Context, key string, tags ...string) error
+	InvalidateTags(ctx context.Context, tags ...string) error
+	Stats() Stats
 }
 
 // cacheImpl implements the Cache interface.
 type cacheImpl struct {
 	prefix     string
 	cache      *cache.Cache
+	local      cache.LocalCache // nil without a local cache
+	redis      *redis.Client    // nil without Redis
 	defaultTTL time.Duration
+	staleTTL   time.Duration
+	ttlJitter  float64
 	sf         *singleflight.Group
+
+	hits, misses, stale, refreshErrors atomic.Uint64
+
+	// Local-only cache serving the operations while Redis is unreachable, nil without Redis
+	fallback      *cache.Cache
+	fallbackLocal cache.LocalCache
+	degraded      atomic.Bool
+	checking      atomic.Bool
+	fallbacks     atomic.Uint64
+
+	// Keys by tag, tracked in memory without Redis
+	tagMutex sync.Mutex
+	tags     map[string]map[string]struct{}
 }
 
-// NewLocalCache creates a new local cache instance.
-func NewLocalCache() Cache {
-	prefix := common.GetEnv("CACHE_PREFIX", "")
-	defaultTTL := common.MustParseDuration(common.GetEnv("CACHE_DEFAULT_TTL", "1m"))
-	return &cacheImpl{
-		prefix: prefix,
-		cache: cache.New(&cache.Options{
-			LocalCache: cache.NewTinyLFU(1000, defaultTTL),
-		}),
-		defaultTTL: defaultTTL,
+// newCache creates a cache storing values in local, redisClient or both.
+func newCache(cfg config.Cache, local cache.LocalCache, redisClient *redis.Client) *cacheImpl {
+	c := &cacheImpl{
+		prefix:     cfg.Prefix,
+		defaultTTL: cfg.DefaultTTL,
+		staleTTL:   cfg.StaleTTL,
+		ttlJitter:  float64(cfg.TTLJitter) / 100,
 		sf:         &singleflight.Group{},
 	}
+	options := &cache.Options{}
+	if local != nil {
+		c.local, options.LocalCache = local, local
+	}
+	if redisClient != nil {
+		c.redis, options.Redis = redisClient, redisClient
+		c.fallbackLocal = local
+		if c.fallbackLocal == nil {
+			c.fallbackLocal = cache.NewTinyLFU(1000, cfg.DefaultTTL)
+		}
+		c.fallback = cache.New(&cache.Options{LocalCache: c.fallbackLocal})
+	}
+	c.cache = cache.New(options)
+	return c
+}
+
+// NewLocalCache creates a new local cache instance.
+func NewLocalCache(cfg config.Cache) Cache {
+	return newCache(cfg, cache.NewTinyLFU(1000, cfg.DefaultTTL), nil)
+}
+
+// NewRedisClient creates a client of the configured Redis instance.
+func NewRedisClient(cfg config.Cache) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
 }
 
 // NewRedisCache creates a new Redis cache instance.
-func NewRedisCache() Cache {
-	prefix := common.GetEnv("CACHE_PREFIX", "")
-	redisAddr := common.GetEnv("CACHE_REDIS_ADDR", "localhost:6379")
-	redisPassword := common.GetEnv("CACHE_REDIS_PASSWORD", "")
-	redisDB, _ := strconv.Atoi(common.GetEnv("CACHE_REDIS_DB", "0"))
-	defaultTTL := common.MustParseDuration(common.GetEnv("CACHE_DEFAULT_TTL", "1m"))
-
-	redisOpts := &redis.Options{
-		Addr:     redisAddr,
-		Password: redisPassword,
-		DB:       redisDB,
-	}
-
-	redisClient := redis.NewClient(redisOpts)
-	return &cacheImpl{
-		prefix:     prefix,
-		cache:      cache.New(&cache.Options{Redis: redisClient}),
-		defaultTTL: defaultTTL,
-		sf:         &singleflight.Group{},
-	}
+func NewRedisCache(cfg config.Cache) Cache {
+	return newCache(cfg, nil, NewRedisClient(cfg))
 }
 
 // NewHybridCache creates a new hybrid cache instance combining local and Redis caches.
-func NewHybridCache() Cache {
-	prefix := common.GetEnv("CACHE_PREFIX", "")
-	redisAddr := common.GetEnv("CACHE_REDIS_ADDR", "localhost:6379")
-	redisPassword := common.GetEnv("CACHE_REDIS_PASSWORD", "")
-	redisDB, _ := strconv.Atoi(common.GetEnv("CACHE_REDIS_DB", "0"))
-	defaultTTL := common.MustParseDuration(common.GetEnv("CACHE_DEFAULT_TTL", "1m"))
-
-	redisOpts := &redis.Options{
-		Addr:     redisAddr,
-		Password: redisPassword,
-		DB:       redisDB,
-	}
-
-	redisClient := redis.NewClient(redisOpts)
-	return &cacheImpl{
-		prefix: prefix,
-		cache: cache.New(&cache.Options{
-			LocalCache: cache.NewTinyLFU(1000, defaultTTL),
-			Redis:      redisClient,
-		}),
-		defaultTTL: defaultTTL,
-		sf:         &singleflight.Group{},
-	}
+func NewHybridCache(cfg config.Cache) Cache {
+	return newCache(cfg, cache.NewTinyLFU(1000, cfg.DefaultTTL), NewRedisClient(cfg))
 }
 
 // Get retrieves a value from the cache.
 func (c *cacheImpl) Get(ctx context.Context, key string, object interface{}) error {
-	return c.cache.Get(ctx, c.FormatKey(key), object)
+	return c.observe(c.store().Get(ctx, c.FormatKey(key), object))
 }
 
 // Set stores a value in the cache with the specified TTL.
@@ -115,88 +126,161 @@ func (c *cacheImpl) Set(ctx context.Context, key string, value interface{}, ttl
 	if ttl == 0 {
 		ttl = c.defaultTTL
 	}
-	return c.cache.Set(&cache.Item{
+	return c.observe(c.store().Set(&cache.Item{
 		Ctx:   ctx,
 		Key:   c.FormatKey(key),
 		Value: value,
-		TTL:   ttl,
-	})
+		TTL:   c.jitter(ttl),
+	}))
 }
 
-// ErrDataNotFound is returned when the requested data is not found in the cache.
+// ErrDataNotFound is returned by GetFunc when the value was not found. The function computing
+// a value returns it, or a nil value, to have the absence cached like a value.
 var ErrDataNotFound = errors.New("data not found")
 
-type NullObject struct{}
+// entry is a value cached by GetFunc. It is served as is until FreshUntil, then as a stale copy
+// while it is recomputed until StaleUntil, unix nanoseconds.
+type entry struct {
+	Value      []byte `msgpack:"v"`
+	NotFound   bool   `msgpack:"n"`
+	FreshUntil int64  `msgpack:"f"`
+	StaleUntil int64  `msgpack:"s"`
+}
+
+// Stats counts the lookups of GetFunc by outcome: fresh hits, misses computed while the caller
+// waits and stale copies served while recomputed in the background. Degraded tells whether Redis
+// is unreachable and Fallbacks counts the operations served by the local cache only meanwhile.
+type Stats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Stale         uint64 `json:"stale"`
+	RefreshErrors uint64 `json:"refresh_errors"`
+	Degraded      bool   `json:"degraded"`
+	Fallbacks     uint64 `json:"fallbacks"`
+}
 
 // GetFunc retrieves a value from the cache or computes it using the provided function.
-// This method ensures that only one goroutine computes the value while others wait for the result.
+// Only one goroutine computes a key at a time while the others wait for its result. A value
+// older than its TTL, jittered so keys cached together do not expire together, is served for
+// the stale TTL more while one goroutine recomputes it in the background, so callers only wait
+// on a miss. Absent values, returned as nil or ErrDataNotFound, are cached too.
 func (c *cacheImpl) GetFunc(ctx context.Context, key string, obj interface{}, ttl time.Duration, fn func(ctx context.Context) (interface{}, error)) error {
 	if ttl == 0 {
 		ttl = c.defaultTTL
 	}
 
+	var cached entry
+	if err := c.observe(c.store().Get(ctx, c.FormatKey(key), &cached)); err == nil {
+		now := time.Now().UnixNano()
+		switch {
+		case now < cached.FreshUntil:
+			c.hits.Add(1)
+			return cached.decode(obj)
+		case now < cached.StaleUntil:
+			c.stale.Add(1)
+			c.refresh(ctx, key, ttl, fn)
+			return cached.decode(obj)
+		}
+	}
+
+	c.misses.Add(1)
 	v, err, _ := c.sf.Do(key, func() (interface{}, error) {
 		if err := ctx.Err(); err != nil {
 			return nil, fmt.Errorf("context cancelled before execution: %w", err)
 		}
+		return c.load(ctx, key, ttl, fn)
+	})
+	if err != nil {
+		return err
+	}
+	return v.(*entry).decode(obj)
+}
 
-		result, err := fn(ctx)
+// refresh recomputes a stale key in the background, unless it is already being computed.
+func (c *cacheImpl) refresh(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) (interface{}, error)) {
+	// The refresh outlives the request serving the stale copy
+	ctx = context.WithoutCancel(ctx)
+	c.sf.DoChan(key, func() (interface{}, error) {
+		computed, err := c.load(ctx, key, ttl, fn)
 		if err != nil {
-			return nil, fmt.Errorf("error executing function: %w", err)
+			c.refreshErrors.Add(1)
+			logger.Warnf("Failed to refresh cache key %s: %v", key, err)
 		}
+		return computed, err
+	})
+}
 
-		if result == nil {
-			if err := c.Set(ctx, key, NullObject{}, ttl); err != nil {
-				return nil, fmt.Errorf("error setting null object in cache: %w", err)
-			}
-			return nil, ErrDataNotFound
-		}
+// load computes the value of a key and caches it. The computed entry is returned even when
+// caching it fails.
+func (c *cacheImpl) load(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) (interface{}, error)) (*entry, error) {
+	result, err := fn(ctx)
+	if err != nil && !errors.Is(err, ErrDataNotFound) {
+		return nil, fmt.Errorf("error executing function: %w", err)
+	}
 
-		var cacheValue interface{}
+	computed := &entry{NotFound: err != nil || result == nil}
+	if !computed.NotFound {
 		switch val := result.(type) {
 		case []byte:
-			cacheValue = val
+			computed.Value = val
 		case string:
-			cacheValue = []byte(val)
+			computed.Value = []byte(val)
 		default:
-			jsonData, err := json.Marshal(result)
-			if err != nil {
+			if computed.Value, err = json.Marshal(result); err != nil {
 				return nil, fmt.Errorf("error marshaling result to JSON: %w", err)
 			}
-			if err := ctx.Err(); err != nil {
-				return nil, fmt.Errorf("context cancelled after JSON marshaling: %w", err)
-			}
-			cacheValue = jsonData
-		}
-
-		if err := c.Set(ctx, key, cacheValue, ttl); err != nil {
-			return result, fmt.Errorf("error setting value in cache (result still returned): %w", err)
 		}
+	}
 
-		return result, nil
-	})
+	freshTTL := c.jitter(ttl)
+	now := time.Now()
+	computed.FreshUntil = now.Add(freshTTL).UnixNano()
+	computed.StaleUntil = now.Add(freshTTL + c.staleTTL).UnixNano()
+	if err := c.observe(c.store().Set(&cache.Item{Ctx: ctx, Key: c.FormatKey(key), Value: computed, TTL: freshTTL + c.staleTTL})); err != nil {
+		logger.Warnf("Failed to cache key %s: %v", key, err)
+	}
+	return computed, nil
+}
 
-	if err != nil {
-		if errors.Is(err, ErrDataNotFound) {
-			return ErrDataNotFound
-		}
-		return err
+// decode sets obj, a pointer, to the cached value: the raw value for a *[]byte or a *string, the
+// JSON-decoded value otherwise.
+func (e *entry) decode(obj interface{}) error {
+	if e.NotFound {
+		return ErrDataNotFound
+	}
+	switch target := obj.(type) {
+	case *[]byte:
+		*target = e.Value
+		return nil
+	case *string:
+		*target = string(e.Value)
+		return nil
+	}
+	if err := json.Unmarshal(e.Value, obj); err != nil {
+		return fmt.Errorf("error unmarshaling cached value: %w", err)
 	}
+	return nil
+}
 
-	if v == nil {
-		reflect.ValueOf(obj).Elem().Set(reflect.Zero(reflect.TypeOf(obj).Elem()))
-	} else {
-		switch val := v.(type) {
-		case []byte:
-			reflect.ValueOf(obj).Elem().SetBytes(val)
-		case string:
-			reflect.ValueOf(obj).Elem().SetString(val)
-		default:
-			reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(v))
-		}
+// jitter spreads ttl randomly by up to the configured fraction either way.
+func (c *cacheImpl) jitter(ttl time.Duration) time.Duration {
+	if c.ttlJitter <= 0 {
+		return ttl
 	}
+	spread := float64(ttl) * c.ttlJitter
+	return ttl + time.Duration(spread*(2*rand.Float64()-1))
+}
 
-	return nil
+// Stats returns the counters of the lookups of GetFunc.
+func (c *cacheImpl) Stats() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Stale:         c.stale.Load(),
+		RefreshErrors: c.refreshErrors.Load(),
+		Degraded:      c.degraded.Load(),
+		Fallbacks:     c.fallbacks.Load(),
+	}
 }
 
 // FormatKey generates a formatted cache key with an optional prefix.
@@ -209,7 +293,7 @@ func (c *cacheImpl) FormatKey(args ...interface{}) string {
 
 // Del removes a value from the cache.
 func (c *cacheImpl) Del(ctx context.Context, key string) error {
-	return c.cache.Delete(ctx, c.FormatKey(key))
+	return c.observe(c.store().Delete(ctx, c.FormatKey(key)))
 }
 
 // BuildKeys constructs a slice of interface{} from a base string and optional string parameters.
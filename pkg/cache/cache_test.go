@@ -3,14 +3,18 @@ package cache
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"hw/pkg/config"
+
 	"github.com/go-redis/cache/v9"
 	"github.com/go-redis/redismock/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"golang.org/x/sync/singleflight"
 )
 
 // mockCache is a mock implementation of the Cache interface
@@ -48,9 +52,39 @@ func (m *mockCache) Del(ctx context.Context, key string) error {
 	return args.Error(0)
 }
 
+// MGet retrieves the items of several keys.
+func (m *mockCache) MGet(ctx context.Context, keys []string, objects interface{}) error {
+	args := m.Called(ctx, keys, objects)
+	return args.Error(0)
+}
+
+// MSet adds several items to the cache with a specified TTL.
+func (m *mockCache) MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	args := m.Called(ctx, values, ttl)
+	return args.Error(0)
+}
+
+// Tag attaches tags to an item.
+func (m *mockCache) Tag(ctx context.Context, key string, tags ...string) error {
+	args := m.Called(ctx, key, tags)
+	return args.Error(0)
+}
+
+// InvalidateTags removes the items of tags from the cache.
+func (m *mockCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	args := m.Called(ctx, tags)
+	return args.Error(0)
+}
+
+// Stats returns the lookup counters of the cache.
+func (m *mockCache) Stats() Stats {
+	args := m.Called()
+	return args.Get(0).(Stats)
+}
+
 // TestNewLocalCache verifies the creation of a new local cache instance.
 func TestNewLocalCache(t *testing.T) {
-	c := NewLocalCache()
+	c := NewLocalCache(config.Default().Cache)
 	assert.NotNil(t, c)
 	assert.IsType(t, &cacheImpl{}, c)
 	impl := c.(*cacheImpl)
@@ -60,7 +94,7 @@ func TestNewLocalCache(t *testing.T) {
 
 // TestNewRedisCache verifies the creation of a new Redis cache instance.
 func TestNewRedisCache(t *testing.T) {
-	c := NewRedisCache()
+	c := NewRedisCache(config.Default().Cache)
 	assert.NotNil(t, c)
 	assert.IsType(t, &cacheImpl{}, c)
 	impl := c.(*cacheImpl)
@@ -70,7 +104,7 @@ func TestNewRedisCache(t *testing.T) {
 
 // TestNewHybridCache verifies the creation of a new hybrid cache instance.
 func TestNewHybridCache(t *testing.T) {
-	c := NewHybridCache()
+	c := NewHybridCache(config.Default().Cache)
 	assert.NotNil(t, c)
 	assert.IsType(t, &cacheImpl{}, c)
 	impl := c.(*cacheImpl)
@@ -164,39 +198,148 @@ func TestSet(t *testing.T) {
 	})
 }
 
-// TestGetFunc tests the GetFunc method of the cache implementation.
+// TestGetFunc tests that GetFunc computes a missing value once and serves it from the cache afterwards.
 func TestGetFunc(t *testing.T) {
-	t.Run("Success - String Value", func(t *testing.T) {
-		db, mock := redismock.NewClientMock()
-
-		c := &cacheImpl{
-			cache:      cache.New(&cache.Options{Redis: db}),
-			prefix:     "test",
-			defaultTTL: time.Minute,
-			sf:         &singleflight.Group{},
+	type block struct {
+		Number uint64            `json:"number"`
+		Logs   map[string]string `json:"logs"`
+	}
+
+	t.Run("String Value", func(t *testing.T) {
+		c := NewLocalCache(config.Cache{DefaultTTL: time.Minute})
+		ctx := context.Background()
+		calls := 0
+		fn := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return "test_value", nil
+		}
+
+		for i := 0; i < 2; i++ {
+			var result string
+			assert.NoError(t, c.GetFunc(ctx, "test_key", &result, time.Minute, fn))
+			assert.Equal(t, "test_value", result)
+		}
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, Stats{Hits: 1, Misses: 1}, c.Stats())
+	})
+
+	t.Run("Struct Value", func(t *testing.T) {
+		c := NewLocalCache(config.Cache{DefaultTTL: time.Minute})
+		ctx := context.Background()
+		want := block{Number: 7, Logs: map[string]string{"0x01": "Transfer"}}
+		fn := func(ctx context.Context) (interface{}, error) {
+			return want, nil
 		}
 
+		for i := 0; i < 2; i++ {
+			var result block
+			assert.NoError(t, c.GetFunc(ctx, "block", &result, time.Minute, fn))
+			assert.Equal(t, want, result)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		c := NewLocalCache(config.Cache{DefaultTTL: time.Minute})
 		ctx := context.Background()
-		key := "test_key"
-		formattedKey := c.FormatKey(key)
-		expectedValue := "test_value"
+		calls := 0
+		fn := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, ErrDataNotFound
+		}
 
-		mock.ExpectSet(formattedKey, []byte(expectedValue), time.Minute).SetVal("OK")
+		for i := 0; i < 2; i++ {
+			var result block
+			assert.ErrorIs(t, c.GetFunc(ctx, "missing", &result, time.Minute, fn), ErrDataNotFound)
+		}
+		assert.Equal(t, 1, calls, "the absence is cached")
+	})
+
+	t.Run("Error Not Cached", func(t *testing.T) {
+		c := NewLocalCache(config.Cache{DefaultTTL: time.Minute})
+		ctx := context.Background()
+		calls := 0
+		fn := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, errors.New("rpc down")
+		}
+
+		for i := 0; i < 2; i++ {
+			var result string
+			assert.ErrorContains(t, c.GetFunc(ctx, "failing", &result, time.Minute, fn), "rpc down")
+		}
+		assert.Equal(t, 2, calls)
+	})
 
+	t.Run("Stale While Revalidate", func(t *testing.T) {
+		c := NewLocalCache(config.Cache{DefaultTTL: time.Minute, StaleTTL: time.Minute})
+		ctx := context.Background()
+		var version atomic.Int32
+		release := make(chan struct{})
 		fn := func(ctx context.Context) (interface{}, error) {
-			return expectedValue, nil
+			if version.Add(1) > 1 {
+				<-release
+			}
+			return fmt.Sprint("v", version.Load()), nil
 		}
 
 		var result string
-		err := c.GetFunc(ctx, key, &result, time.Minute, fn)
+		assert.NoError(t, c.GetFunc(ctx, "key", &result, time.Millisecond, fn))
+		assert.Equal(t, "v1", result)
+		time.Sleep(5 * time.Millisecond)
+
+		// Expired: the stale value is served without waiting for the refresh, started once
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, c.GetFunc(ctx, "key", &result, time.Minute, fn))
+			assert.Equal(t, "v1", result)
+		}
+		close(release)
+		assert.Eventually(t, func() bool {
+			return c.GetFunc(ctx, "key", &result, time.Minute, fn) == nil && result == "v2"
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, int32(2), version.Load())
+		assert.GreaterOrEqual(t, c.Stats().Stale, uint64(3))
+	})
 
-		assert.NoError(t, err)
-		assert.Equal(t, expectedValue, result)
+	t.Run("Concurrent Misses", func(t *testing.T) {
+		c := NewLocalCache(config.Cache{DefaultTTL: time.Minute})
+		ctx := context.Background()
+		var calls atomic.Int32
+		fn := func(ctx context.Context) (interface{}, error) {
+			calls.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			return "value", nil
+		}
 
-		assert.NoError(t, mock.ExpectationsWereMet())
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var result string
+				assert.NoError(t, c.GetFunc(ctx, "key", &result, time.Minute, fn))
+				assert.Equal(t, "value", result)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
 	})
 }
 
+// TestJitter tests that TTLs are spread within the configured percentage.
+func TestJitter(t *testing.T) {
+	c := &cacheImpl{ttlJitter: 0.1}
+	spread := false
+	for i := 0; i < 100; i++ {
+		ttl := c.jitter(time.Minute)
+		assert.InDelta(t, float64(time.Minute), float64(ttl), float64(6*time.Second))
+		spread = spread || ttl != time.Minute
+	}
+	assert.True(t, spread)
+
+	c.ttlJitter = 0
+	assert.Equal(t, time.Minute, c.jitter(time.Minute))
+}
+
 // TestFormatKey tests the FormatKey method of the cache implementation.
 func TestFormatKey(t *testing.T) {
 	c := &cacheImpl{prefix: "test"}
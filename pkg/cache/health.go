@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hw/pkg/logger"
+
+	"github.com/go-redis/cache/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+// Health check tunables, variables so tests can shorten them.
+var (
+	// HealthCheckInterval is how often an unreachable Redis is pinged until it answers again.
+	HealthCheckInterval = 5 * time.Second
+	// HealthCheckTimeout bounds every ping.
+	HealthCheckTimeout = time.Second
+)
+
+// store returns the cache serving the operations: the local fallback while Redis is unreachable.
+func (c *cacheImpl) store() *cache.Cache {
+	if c.degraded.Load() {
+		c.fallbacks.Add(1)
+		return c.fallback
+	}
+	return c.cache
+}
+
+// remote returns the Redis client, or nil without Redis or while it is unreachable.
+func (c *cacheImpl) remote() *redis.Client {
+	if c.degraded.Load() {
+		return nil
+	}
+	return c.redis
+}
+
+// localStore returns the local cache, the fallback one while Redis is unreachable, or nil.
+func (c *cacheImpl) localStore() cache.LocalCache {
+	if c.degraded.Load() {
+		return c.fallbackLocal
+	}
+	return c.local
+}
+
+// observe checks the health of Redis when an operation failed with err, other than a miss, and
+// returns err.
+func (c *cacheImpl) observe(err error) error {
+	if err == nil || c.redis == nil || c.fallback == nil || errors.Is(err, cache.ErrCacheMiss) || errors.Is(err, context.Canceled) {
+		return err
+	}
+	c.checkHealth()
+	return err
+}
+
+// checkHealth pings Redis in the background, unless a check is running. While Redis does not
+// answer, the cache runs degraded: operations are served by the local cache only, without
+// waiting on Redis, and Redis is pinged every HealthCheckInterval until it answers again.
+func (c *cacheImpl) checkHealth() {
+	if !c.checking.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer c.checking.Store(false)
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), HealthCheckTimeout)
+			err := c.redis.Ping(ctx).Err()
+			cancel()
+			if err == nil {
+				if c.degraded.CompareAndSwap(true, false) {
+					logger.Infof("Redis at %s answers again, the cache leaves degraded mode", c.redis.Options().Addr)
+				}
+				return
+			}
+			if c.degraded.CompareAndSwap(false, true) {
+				logger.Warnf("Redis at %s is unreachable, the cache runs degraded on its local cache: %v", c.redis.Options().Addr, err)
+			}
+			time.Sleep(HealthCheckInterval)
+		}
+	}()
+}
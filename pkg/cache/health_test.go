@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hw/pkg/config"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDegradedMode tests that the cache falls back to its local cache when Redis stops answering,
+// and uses Redis again once it answers.
+func TestDegradedMode(t *testing.T) {
+	interval := HealthCheckInterval
+	HealthCheckInterval = 200 * time.Millisecond
+	defer func() { HealthCheckInterval = interval }()
+
+	db, mock := redismock.NewClientMock()
+	c := newCache(config.Cache{Prefix: "test", DefaultTTL: time.Minute}, nil, db)
+	ctx := context.Background()
+
+	mock.ExpectSet("test:key", []byte("value"), time.Minute).SetErr(errors.New("connection refused"))
+	mock.ExpectPing().SetErr(errors.New("connection refused"))
+	mock.ExpectPing().SetVal("PONG")
+
+	assert.ErrorContains(t, c.Set(ctx, "key", "value", time.Minute), "connection refused")
+	assert.Eventually(t, func() bool { return c.Stats().Degraded }, time.Second, time.Millisecond)
+
+	// Served by the local cache without calling Redis
+	assert.NoError(t, c.Set(ctx, "key", "value", time.Minute))
+	var value string
+	assert.NoError(t, c.Get(ctx, "key", &value))
+	assert.Equal(t, "value", value)
+	assert.Equal(t, uint64(2), c.Stats().Fallbacks)
+
+	assert.Eventually(t, func() bool { return !c.Stats().Degraded }, time.Second, time.Millisecond)
+	mock.ExpectGet("test:key").RedisNil()
+	assert.Error(t, c.Get(ctx, "key", &value), "Redis is read again")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDegradedModeMiss tests that a miss is not taken for a failure of Redis.
+func TestDegradedModeMiss(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	c := newCache(config.Cache{DefaultTTL: time.Minute}, nil, db)
+
+	mock.ExpectGet("key").RedisNil()
+	var value string
+	assert.Error(t, c.Get(context.Background(), "key", &value))
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, c.Stats().Degraded)
+	assert.NoError(t, mock.ExpectationsWereMet(), "Redis is not pinged")
+}
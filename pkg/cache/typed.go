@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Get returns the value of a key stored with Set, decoded as a T.
+func Get[T any](ctx context.Context, c Cache, key string) (T, error) {
+	var value T
+	err := c.Get(ctx, key, &value)
+	return value, err
+}
+
+// GetOrLoad returns the value of a key, computed by load on a miss, with the stampede protection
+// and stale-while-revalidate of GetFunc. Values are cached as JSON, except []byte and string
+// values which are cached as is. load returns ErrDataNotFound, which is returned and cached, for
+// a missing value.
+func GetOrLoad[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := c.GetFunc(ctx, key, &value, ttl, func(ctx context.Context) (interface{}, error) {
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return loaded, nil
+	})
+	return value, err
+}
+
+// MGet returns the values of the keys stored with Set or MSet, decoded as T, by key. Missing
+// keys are left out.
+func MGet[T any](ctx context.Context, c Cache, keys ...string) (map[string]T, error) {
+	values := make(map[string]T, len(keys))
+	err := c.MGet(ctx, keys, &values)
+	return values, err
+}
+
+// MSet stores values by key with the same TTL.
+func MSet[T any](ctx context.Context, c Cache, values map[string]T, ttl time.Duration) error {
+	untyped := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		untyped[key] = value
+	}
+	return c.MSet(ctx, untyped, ttl)
+}
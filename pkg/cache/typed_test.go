@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"hw/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetOrLoad tests that values of any type are round-tripped through the cache.
+func TestGetOrLoad(t *testing.T) {
+	type swap struct {
+		Pool   string   `json:"pool"`
+		Amount *big.Int `json:"amount"`
+	}
+	ctx := context.Background()
+	c := NewLocalCache(config.Cache{DefaultTTL: time.Minute})
+
+	t.Run("Struct", func(t *testing.T) {
+		want := swap{Pool: "0xpool", Amount: big.NewInt(1500)}
+		for i := 0; i < 2; i++ {
+			got, err := GetOrLoad(ctx, c, "struct", time.Minute, func(ctx context.Context) (swap, error) {
+				return want, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		want := map[string][]int{"a": {1, 2}, "b": {3}}
+		for i := 0; i < 2; i++ {
+			got, err := GetOrLoad(ctx, c, "map", time.Minute, func(ctx context.Context) (map[string][]int, error) {
+				return want, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("Pointer", func(t *testing.T) {
+		want := &swap{Pool: "0xpool", Amount: big.NewInt(7)}
+		for i := 0; i < 2; i++ {
+			got, err := GetOrLoad(ctx, c, "pointer", time.Minute, func(ctx context.Context) (*swap, error) {
+				return want, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("Bytes And Strings", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			raw, err := GetOrLoad(ctx, c, "bytes", time.Minute, func(ctx context.Context) ([]byte, error) {
+				return []byte("not json"), nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("not json"), raw)
+
+			text, err := GetOrLoad(ctx, c, "string", time.Minute, func(ctx context.Context) (string, error) {
+				return "not json", nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "not json", text)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		got, err := GetOrLoad(ctx, c, "missing", time.Minute, func(ctx context.Context) (*swap, error) {
+			return nil, ErrDataNotFound
+		})
+		assert.ErrorIs(t, err, ErrDataNotFound)
+		assert.Nil(t, got)
+	})
+}
+
+// TestGetTyped tests that Get decodes a value stored with Set.
+func TestGetTyped(t *testing.T) {
+	type token struct {
+		Symbol   string
+		Decimals int
+	}
+	ctx := context.Background()
+	c := NewLocalCache(config.Cache{DefaultTTL: time.Minute})
+
+	assert.NoError(t, c.Set(ctx, "token", token{Symbol: "USDC", Decimals: 6}, time.Minute))
+	got, err := Get[token](ctx, c, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, token{Symbol: "USDC", Decimals: 6}, got)
+
+	_, err = Get[token](ctx, c, "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,125 @@
+// Package clickhouse is a minimal client of the ClickHouse HTTP interface, inserting rows as
+// JSONEachRow and decoding query results from the JSON format.
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"hw/pkg/config"
+)
+
+// Client sends queries to a database of a ClickHouse server over its HTTP interface.
+type Client struct {
+	endpoint string
+	database string
+	username string
+	password string
+	client   *http.Client
+}
+
+// New creates a Client for the configured server and database.
+func New(cfg config.ClickHouse) *Client {
+	return &Client{
+		endpoint: strings.TrimSuffix(cfg.URL, "/") + "/",
+		database: cfg.Database,
+		username: cfg.Username,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: time.Minute},
+	}
+}
+
+// Exec runs a statement without result, such as a CREATE TABLE.
+func (c *Client) Exec(ctx context.Context, query string) error {
+	resp, err := c.do(ctx, url.Values{}, strings.NewReader(query))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Insert inserts rows into table, each encoded as a JSON object whose keys are the columns.
+// Times may be sent as RFC 3339 strings.
+func (c *Client) Insert(ctx context.Context, table string, rows []interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "INSERT INTO %s FORMAT JSONEachRow\n", table)
+	encoder := json.NewEncoder(&body)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode row: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("date_time_input_format", "best_effort")
+	resp, err := c.do(ctx, params, &body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Query runs a SELECT and decodes its rows into out, a pointer to a slice of structs whose json
+// tags name the columns. Values are bound to the {name:Type} placeholders of query from params,
+// so they need no escaping.
+func (c *Client) Query(ctx context.Context, query string, params map[string]string, out interface{}) error {
+	values := url.Values{}
+	for name, value := range params {
+		values.Set("param_"+name, value)
+	}
+	values.Set("output_format_json_quote_64bit_integers", "0")
+
+	resp, err := c.do(ctx, values, strings.NewReader(query+" FORMAT JSON"))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	result := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode query result: %w", err)
+	}
+	return nil
+}
+
+// do posts a query in body to the database, returning the response of a successful query.
+func (c *Client) do(ctx context.Context, params url.Values, body io.Reader) (*http.Response, error) {
+	params.Set("database", c.database)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"?"+params.Encode(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ClickHouse: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to query ClickHouse: %w", responseError(resp))
+	}
+	return resp, nil
+}
+
+// responseError describes a failed response including the start of its body.
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,73 @@
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInsert tests that rows are sent as JSONEachRow to the configured database with basic auth.
+func TestInsert(t *testing.T) {
+	var query, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "indexer", username)
+		assert.Equal(t, "secret", password)
+		query = r.URL.RawQuery
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	client := New(config.ClickHouse{URL: server.URL, Database: "analytics", Username: "indexer", Password: "secret"})
+	rows := []interface{}{
+		map[string]interface{}{"id": 1, "network": "mainnet"},
+		map[string]interface{}{"id": 2, "network": "base"},
+	}
+	assert.NoError(t, client.Insert(context.Background(), "swap_history", rows))
+	assert.Equal(t, "database=analytics&date_time_input_format=best_effort", query)
+	assert.Equal(t, "INSERT INTO swap_history FORMAT JSONEachRow\n{\"id\":1,\"network\":\"mainnet\"}\n{\"id\":2,\"network\":\"base\"}\n", body)
+}
+
+// TestQuery tests that parameters are bound and the rows of the JSON result decoded.
+func TestQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "mainnet", r.URL.Query().Get("param_network"))
+		data, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "SELECT account, count() AS swaps FROM swap_history WHERE network = {network:String} GROUP BY account FORMAT JSON", string(data))
+		io.WriteString(w, `{"meta":[],"data":[{"account":"0xa","swaps":3}],"rows":1}`)
+	}))
+	defer server.Close()
+
+	client := New(config.ClickHouse{URL: server.URL, Database: "default"})
+	var rows []struct {
+		Account string `json:"account"`
+		Swaps   int64  `json:"swaps"`
+	}
+	err := client.Query(context.Background(), "SELECT account, count() AS swaps FROM swap_history WHERE network = {network:String} GROUP BY account",
+		map[string]string{"network": "mainnet"}, &rows)
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 1) {
+		assert.Equal(t, "0xa", rows[0].Account)
+		assert.Equal(t, int64(3), rows[0].Swaps)
+	}
+}
+
+// TestExec_Error tests that the status and message of a failed query are returned.
+func TestExec_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, "Code: 62. DB::Exception: Syntax error\n")
+	}))
+	defer server.Close()
+
+	client := New(config.ClickHouse{URL: server.URL, Database: "default"})
+	err := client.Exec(context.Background(), "CREATE TABLE")
+	assert.EqualError(t, err, "failed to query ClickHouse: unexpected status 400: Code: 62. DB::Exception: Syntax error")
+}
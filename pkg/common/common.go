@@ -56,14 +56,14 @@ func GetRegexMap(re *regexp.Regexp, msg string) map[string]string {
 func FormatHashrate(hashrate string, decimal int32) string {
 	zero := math.Log10(cast.ToFloat64(hashrate))
 	if zero >= 12 {
-		return bigrat.NewBigN(hashrate).Div("1e12").ToTruncateString(decimal) + "T"
+		return bigrat.New(hashrate).Div("1e12").ToTruncateString(decimal) + "T"
 	} else if zero >= 9 {
-		return bigrat.NewBigN(hashrate).Div("1e9").ToTruncateString(decimal) + "G"
+		return bigrat.New(hashrate).Div("1e9").ToTruncateString(decimal) + "G"
 	} else if zero >= 6 {
-		return bigrat.NewBigN(hashrate).Div("1e6").ToTruncateString(decimal) + "M"
+		return bigrat.New(hashrate).Div("1e6").ToTruncateString(decimal) + "M"
 	} else if zero >= 3 {
-		return bigrat.NewBigN(hashrate).Div("1e3").ToTruncateString(decimal) + "K"
+		return bigrat.New(hashrate).Div("1e3").ToTruncateString(decimal) + "K"
 	} else {
-		return bigrat.NewBigN(hashrate).ToTruncateString(decimal)
+		return bigrat.New(hashrate).ToTruncateString(decimal)
 	}
 }
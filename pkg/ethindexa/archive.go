@@ -0,0 +1,322 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"hw/pkg/blobstore"
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// ArchivePostgres stores raw blocks and logs in the raw_blocks and raw_logs tables.
+	ArchivePostgres = "postgres"
+	// ArchiveBlobstore stores one object per fetched range in the blobstore configured via env.
+	ArchiveBlobstore = "blobstore"
+)
+
+// ReplayBatchSize is the number of blocks loaded from the archive per replayed events task.
+var ReplayBatchSize uint64 = 1000
+
+// ArchiveConfig enables persisting fetched blocks and logs so they can be replayed without the RPC.
+type ArchiveConfig struct {
+	Enabled bool   `json:"enabled"`
+	Storage string `json:"storage"` // "postgres" (default) or "blobstore"
+}
+
+// Archive persists the raw blocks and logs fetched by the indexer.
+// Blocks are keyed by their decimal number, like EventsTask.Blocks.
+type Archive interface {
+	// Store saves the blocks and logs of a fetched range. Entries already archived are kept.
+	Store(ctx context.Context, network string, blocks map[string]*ethclient.GetBlockResponse, logs []types.Log) error
+	// Load returns the archived blocks and logs of a network between fromBlock and toBlock inclusive.
+	Load(ctx context.Context, network string, fromBlock, toBlock uint64) (map[string]*ethclient.GetBlockResponse, []types.Log, error)
+}
+
+// PostgresArchive is an Archive backed by Postgres.
+type PostgresArchive struct {
+	db pg.PgxPool
+}
+
+// NewPostgresArchive creates a PostgresArchive on top of the given pool.
+func NewPostgresArchive(db pg.PgxPool) *PostgresArchive {
+	return &PostgresArchive{db: db}
+}
+
+// Store saves the blocks and logs of a fetched range in a single transaction.
+func (a *PostgresArchive) Store(ctx context.Context, network string, blocks map[string]*ethclient.GetBlockResponse, logs []types.Log) error {
+	const blockQuery = `
+		INSERT INTO raw_blocks (network, block_number, block_hash, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (network, block_number) DO NOTHING
+	`
+	const logQuery = `
+		INSERT INTO raw_logs (network, block_number, log_index, transaction_hash, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (network, block_number, log_index) DO NOTHING
+	`
+
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, block := range blocks {
+		if block == nil {
+			continue
+		}
+		payload, err := json.Marshal(trimBlock(block, logs))
+		if err != nil {
+			return fmt.Errorf("failed to marshal block %s: %w", block.Result.Hash, err)
+		}
+		if _, err := tx.Exec(ctx, blockQuery, network, block.Number().Int64(), block.Result.Hash, payload); err != nil {
+			return fmt.Errorf("failed to archive block %s: %w", block.Result.Hash, err)
+		}
+	}
+
+	for _, logEntry := range logs {
+		payload, err := json.Marshal(logEntry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal log %s:%d: %w", logEntry.TxHash.Hex(), logEntry.Index, err)
+		}
+		if _, err := tx.Exec(ctx, logQuery, network, int64(logEntry.BlockNumber), int64(logEntry.Index), logEntry.TxHash.Hex(), payload); err != nil {
+			return fmt.Errorf("failed to archive log %s:%d: %w", logEntry.TxHash.Hex(), logEntry.Index, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit archive: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns the archived blocks and logs of a range, with logs in chain order.
+func (a *PostgresArchive) Load(ctx context.Context, network string, fromBlock, toBlock uint64) (map[string]*ethclient.GetBlockResponse, []types.Log, error) {
+	const blockQuery = `
+		SELECT block_number, payload
+		FROM raw_blocks
+		WHERE network = $1 AND block_number BETWEEN $2 AND $3
+	`
+	const logQuery = `
+		SELECT payload
+		FROM raw_logs
+		WHERE network = $1 AND block_number BETWEEN $2 AND $3
+		ORDER BY block_number, log_index
+	`
+
+	blockRows, err := a.db.Query(ctx, blockQuery, network, int64(fromBlock), int64(toBlock))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query archived blocks: %w", err)
+	}
+	defer blockRows.Close()
+
+	blocks := make(map[string]*ethclient.GetBlockResponse)
+	for blockRows.Next() {
+		var number int64
+		var payload []byte
+		if err := blockRows.Scan(&number, &payload); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan archived block: %w", err)
+		}
+		var block ethclient.GetBlockResponse
+		if err := json.Unmarshal(payload, &block); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal archived block %d: %w", number, err)
+		}
+		blocks[strconv.FormatInt(number, 10)] = &block
+	}
+	if err := blockRows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate archived blocks: %w", err)
+	}
+
+	logRows, err := a.db.Query(ctx, logQuery, network, int64(fromBlock), int64(toBlock))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query archived logs: %w", err)
+	}
+	defer logRows.Close()
+
+	var logs []types.Log
+	for logRows.Next() {
+		var payload []byte
+		if err := logRows.Scan(&payload); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan archived log: %w", err)
+		}
+		var logEntry types.Log
+		if err := json.Unmarshal(payload, &logEntry); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal archived log: %w", err)
+		}
+		logs = append(logs, logEntry)
+	}
+	if err := logRows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate archived logs: %w", err)
+	}
+
+	return blocks, logs, nil
+}
+
+// archiveObject is the content of a range archived in a blobstore.
+type archiveObject struct {
+	Blocks map[string]*ethclient.GetBlockResponse `json:"blocks"`
+	Logs   []types.Log                            `json:"logs"`
+}
+
+// BlobArchive is an Archive writing one JSON object per fetched range, keyed
+// raw/<network>/<first block>-<last block>.json with zero-padded numbers so keys sort by block.
+type BlobArchive struct {
+	store blobstore.Store
+}
+
+// NewBlobArchive creates a BlobArchive on top of the given store.
+func NewBlobArchive(store blobstore.Store) *BlobArchive {
+	return &BlobArchive{store: store}
+}
+
+// Store writes the blocks and logs of a fetched range. Ranges without logs are skipped.
+func (a *BlobArchive) Store(ctx context.Context, network string, blocks map[string]*ethclient.GetBlockResponse, logs []types.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	firstBlock, lastBlock := logs[0].BlockNumber, logs[0].BlockNumber
+	for _, logEntry := range logs {
+		firstBlock = min(firstBlock, logEntry.BlockNumber)
+		lastBlock = max(lastBlock, logEntry.BlockNumber)
+	}
+
+	object := archiveObject{Blocks: make(map[string]*ethclient.GetBlockResponse, len(blocks)), Logs: logs}
+	for number, block := range blocks {
+		if block == nil {
+			continue
+		}
+		trimmed := trimBlock(block, logs)
+		object.Blocks[number] = &trimmed
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return fmt.Errorf("failed to marshal archive object: %w", err)
+	}
+
+	key := fmt.Sprintf("%s%012d-%012d.json", blobArchivePrefix(network), firstBlock, lastBlock)
+	if err := a.store.Put(ctx, key, data, "application/json"); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", key, err)
+	}
+	return nil
+}
+
+// Load reads every archived object overlapping the range and keeps the blocks and logs inside it.
+func (a *BlobArchive) Load(ctx context.Context, network string, fromBlock, toBlock uint64) (map[string]*ethclient.GetBlockResponse, []types.Log, error) {
+	prefix := blobArchivePrefix(network)
+	keys, err := a.store.List(ctx, prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list archive: %w", err)
+	}
+
+	blocks := make(map[string]*ethclient.GetBlockResponse)
+	var logs []types.Log
+	for _, key := range keys {
+		var firstBlock, lastBlock uint64
+		if _, err := fmt.Sscanf(strings.TrimPrefix(key, prefix), "%d-%d.json", &firstBlock, &lastBlock); err != nil {
+			logger.Warnf("Skipping unexpected archive object %s", key)
+			continue
+		}
+		if lastBlock < fromBlock || firstBlock > toBlock {
+			continue
+		}
+
+		data, err := a.store.Get(ctx, key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read archive object %s: %w", key, err)
+		}
+		var object archiveObject
+		if err := json.Unmarshal(data, &object); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal archive object %s: %w", key, err)
+		}
+
+		for _, logEntry := range object.Logs {
+			if logEntry.BlockNumber >= fromBlock && logEntry.BlockNumber <= toBlock {
+				logs = append(logs, logEntry)
+			}
+		}
+		for number, block := range object.Blocks {
+			if blockNumber := block.Number().Uint64(); blockNumber >= fromBlock && blockNumber <= toBlock {
+				blocks[number] = block
+			}
+		}
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	return blocks, logs, nil
+}
+
+func blobArchivePrefix(network string) string {
+	return "raw/" + network + "/"
+}
+
+// trimBlock returns a copy of a block keeping only the transactions that emitted one of the logs.
+// Handlers only look up the transaction of their event, so the rest of the body is not archived.
+func trimBlock(block *ethclient.GetBlockResponse, logs []types.Log) ethclient.GetBlockResponse {
+	txHashes := make(map[string]struct{})
+	for _, logEntry := range logs {
+		txHashes[logEntry.TxHash.Hex()] = struct{}{}
+	}
+
+	trimmed := *block
+	trimmed.Result.Transactions = nil
+	for _, tx := range block.Result.Transactions {
+		if _, exists := txHashes[tx.Hash]; exists {
+			trimmed.Result.Transactions = append(trimmed.Result.Transactions, tx)
+		}
+	}
+	return trimmed
+}
+
+// Replay feeds archived blocks and logs of a network between fromBlock and toBlock inclusive
+// through the log processor, so newly registered handlers can process history without the RPC.
+// The network's consumers must be running.
+func (indexer *IndexerImpl) Replay(ctx context.Context, networkName string, fromBlock, toBlock uint64) error {
+	if indexer.Archive == nil {
+		return fmt.Errorf("archive is not enabled")
+	}
+	queue, exists := indexer.EventQueues[networkName]
+	if !exists {
+		return fmt.Errorf("unknown network: %s", networkName)
+	}
+
+	for start := fromBlock; start <= toBlock; start += ReplayBatchSize {
+		end := start + ReplayBatchSize - 1
+		if end > toBlock {
+			end = toBlock
+		}
+
+		blocks, logs, err := indexer.Archive.Load(ctx, networkName, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to load archive for network %s from #%d to #%d: %w", networkName, start, end, err)
+		}
+		if len(logs) > 0 {
+			select {
+			case queue <- &EventsTask{Network: networkName, Blocks: blocks, Logs: logs}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		logger.Infof("Replayed %s blocks %d to %d from archive (%d logs)", networkName, start, end, len(logs))
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package ethindexa
+
+import (
+	"context"
+	"testing"
+
+	"hw/pkg/blobstore"
+	"hw/pkg/ethindexa/ethclient"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeArchive is an in-memory Archive returning logs of the requested range.
+type fakeArchive struct {
+	logs  []types.Log
+	loads int
+}
+
+func (a *fakeArchive) Store(ctx context.Context, network string, blocks map[string]*ethclient.GetBlockResponse, logs []types.Log) error {
+	a.logs = append(a.logs, logs...)
+	return nil
+}
+
+func (a *fakeArchive) Load(ctx context.Context, network string, fromBlock, toBlock uint64) (map[string]*ethclient.GetBlockResponse, []types.Log, error) {
+	a.loads++
+	var logs []types.Log
+	for _, logEntry := range a.logs {
+		if logEntry.BlockNumber >= fromBlock && logEntry.BlockNumber <= toBlock {
+			logs = append(logs, logEntry)
+		}
+	}
+	return map[string]*ethclient.GetBlockResponse{}, logs, nil
+}
+
+// testBlock returns a block response with the given number and transaction hashes.
+func testBlock(number string, txHashes ...string) *ethclient.GetBlockResponse {
+	block := &ethclient.GetBlockResponse{}
+	block.Result.Number = number
+	block.Result.Hash = "0xblock" + number
+	for _, hash := range txHashes {
+		block.Result.Transactions = append(block.Result.Transactions, ethclient.GetTransactionResponse{Hash: hash})
+	}
+	return block
+}
+
+// TestTrimBlock tests that only transactions emitting logs are archived.
+func TestTrimBlock(t *testing.T) {
+	matching := common.HexToHash("0x01").Hex()
+	other := common.HexToHash("0x02").Hex()
+	block := testBlock("0x64", matching, other)
+
+	trimmed := trimBlock(block, []types.Log{{TxHash: common.HexToHash("0x01")}})
+
+	assert.Len(t, trimmed.Result.Transactions, 1)
+	assert.Equal(t, matching, trimmed.Result.Transactions[0].Hash)
+	assert.Len(t, block.Result.Transactions, 2)
+}
+
+// TestPostgresArchive_Store tests that blocks and logs are inserted in one transaction.
+func TestPostgresArchive_Store(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+
+	ctx := context.Background()
+	archive := NewPostgresArchive(mockDB)
+
+	logEntry := types.Log{BlockNumber: 100, Index: 3, TxHash: common.HexToHash("0x01")}
+
+	mockDB.EXPECT().Begin(ctx).Return(mockTx, nil)
+	mockTx.EXPECT().Exec(ctx, gomock.Any(), "mainnet", int64(100), "0xblock0x64", gomock.Any()).Return(pgconn.CommandTag{}, nil)
+	mockTx.EXPECT().Exec(ctx, gomock.Any(), "mainnet", int64(100), int64(3), logEntry.TxHash.Hex(), gomock.Any()).Return(pgconn.CommandTag{}, nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+	mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+	err := archive.Store(ctx, "mainnet", map[string]*ethclient.GetBlockResponse{"100": testBlock("0x64")}, []types.Log{logEntry})
+
+	assert.NoError(t, err)
+}
+
+// TestReplay tests that archived logs are fed to the event queue in batches.
+func TestReplay(t *testing.T) {
+	defer func(size uint64) { ReplayBatchSize = size }(ReplayBatchSize)
+	ReplayBatchSize = 10
+
+	archive := &fakeArchive{logs: []types.Log{{BlockNumber: 5}, {BlockNumber: 25}}}
+	indexer := &IndexerImpl{
+		Archive:     archive,
+		EventQueues: map[string]chan *EventsTask{"mainnet": make(chan *EventsTask, 10)},
+	}
+
+	err := indexer.Replay(context.Background(), "mainnet", 0, 29)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, archive.loads)
+	assert.Len(t, indexer.EventQueues["mainnet"], 2)
+
+	assert.Error(t, indexer.Replay(context.Background(), "base", 0, 10))
+}
+
+// TestBlobArchive tests that archived ranges are read back filtered to the requested blocks.
+func TestBlobArchive(t *testing.T) {
+	store, err := blobstore.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	archive := NewBlobArchive(store)
+
+	txHash := common.HexToHash("0x01")
+	topics := []common.Hash{common.HexToHash("0x02")}
+	err = archive.Store(ctx, "mainnet", map[string]*ethclient.GetBlockResponse{
+		"100": testBlock("0x64", txHash.Hex()),
+		"110": testBlock("0x6e", txHash.Hex()),
+	}, []types.Log{
+		{BlockNumber: 110, Index: 1, TxHash: txHash, Topics: topics},
+		{BlockNumber: 100, Index: 0, TxHash: txHash, Topics: topics},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, archive.Store(ctx, "mainnet", nil, nil))
+
+	blocks, logs, err := archive.Load(ctx, "mainnet", 105, 200)
+	assert.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, uint64(110), logs[0].BlockNumber)
+	}
+	assert.Contains(t, blocks, "110")
+	assert.NotContains(t, blocks, "100")
+
+	_, logs, err = archive.Load(ctx, "base", 0, 200)
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+}
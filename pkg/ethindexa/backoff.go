@@ -0,0 +1,69 @@
+package ethindexa
+
+import (
+	"math/rand"
+	"time"
+)
+
+var (
+	// FetchMinBackoff is the wait before retrying after the first failure of a block fetcher.
+	FetchMinBackoff = time.Second
+	// FetchMaxBackoff caps the wait between retries of a failing block fetcher.
+	FetchMaxBackoff = 2 * time.Minute
+	// FetchRetryWindow is how long a block fetcher keeps failing before it is logged as an error;
+	// it goes on retrying every FetchMaxBackoff.
+	FetchRetryWindow = 15 * time.Minute
+)
+
+// fetchBackoffJitter is the fraction of a backoff spread randomly either way, so the fetchers of
+// networks sharing an RPC provider do not retry in lockstep.
+const fetchBackoffJitter = 0.2
+
+// fetchBackoff paces the retries of a block fetcher: the wait doubles with every consecutive
+// failure from FetchMinBackoff up to FetchMaxBackoff, and a success starts over.
+type fetchBackoff struct {
+	failures  int
+	since     time.Time // first failure of the current streak
+	escalated bool      // the streak outlasted FetchRetryWindow and was reported
+	now       func() time.Time
+	jitter    func(time.Duration) time.Duration
+}
+
+func newFetchBackoff() *fetchBackoff {
+	return &fetchBackoff{
+		now: time.Now,
+		jitter: func(wait time.Duration) time.Duration {
+			return wait + time.Duration(fetchBackoffJitter*float64(wait)*(2*rand.Float64()-1))
+		},
+	}
+}
+
+// fail records a failure and returns the wait before the next attempt, the number of consecutive
+// failures, and whether the streak has just outlasted FetchRetryWindow.
+func (b *fetchBackoff) fail() (wait time.Duration, failures int, outlasted bool) {
+	now := b.now()
+	if b.failures == 0 {
+		b.since = now
+	}
+	b.failures++
+
+	wait = FetchMinBackoff
+	for i := 1; i < b.failures && wait < FetchMaxBackoff; i++ {
+		wait *= 2
+	}
+	wait = b.jitter(min(wait, FetchMaxBackoff))
+
+	if !b.escalated && now.Sub(b.since) >= FetchRetryWindow {
+		b.escalated = true
+		outlasted = true
+	}
+	return wait, b.failures, outlasted
+}
+
+// succeed ends the current streak of failures and returns how many there were and since when.
+func (b *fetchBackoff) succeed() (failures int, since time.Time) {
+	failures, since = b.failures, b.since
+	b.failures = 0
+	b.escalated = false
+	return failures, since
+}
@@ -0,0 +1,55 @@
+package ethindexa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFetchBackoff tests that the wait doubles up to FetchMaxBackoff, that a streak outlasting
+// FetchRetryWindow is reported once, and that a success starts over.
+func TestFetchBackoff(t *testing.T) {
+	now := time.Unix(1727740800, 0)
+	backoff := newFetchBackoff()
+	backoff.now = func() time.Time { return now }
+	backoff.jitter = func(wait time.Duration) time.Duration { return wait }
+
+	var waits []time.Duration
+	for i := 0; i < 9; i++ {
+		wait, failures, outlasted := backoff.fail()
+		assert.Equal(t, i+1, failures)
+		assert.False(t, outlasted)
+		waits = append(waits, wait)
+		now = now.Add(wait)
+	}
+	assert.Equal(t, []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second,
+		32 * time.Second, 64 * time.Second, 2 * time.Minute, 2 * time.Minute,
+	}, waits)
+
+	now = now.Add(FetchRetryWindow)
+	_, _, outlasted := backoff.fail()
+	assert.True(t, outlasted)
+	_, _, outlasted = backoff.fail()
+	assert.False(t, outlasted, "the streak is reported once")
+
+	failures, since := backoff.succeed()
+	assert.Equal(t, 11, failures)
+	assert.Equal(t, time.Unix(1727740800, 0), since)
+
+	wait, failures, _ := backoff.fail()
+	assert.Equal(t, time.Second, wait)
+	assert.Equal(t, 1, failures)
+}
+
+// TestFetchBackoff_Jitter tests that waits are spread within the jitter fraction.
+func TestFetchBackoff_Jitter(t *testing.T) {
+	backoff := newFetchBackoff()
+	for i := 0; i < 20; i++ {
+		backoff.succeed()
+		wait, _, _ := backoff.fail()
+		assert.GreaterOrEqual(t, wait, time.Duration(float64(FetchMinBackoff)*(1-fetchBackoffJitter)))
+		assert.LessOrEqual(t, wait, time.Duration(float64(FetchMinBackoff)*(1+fetchBackoffJitter)))
+	}
+}
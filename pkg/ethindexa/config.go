@@ -0,0 +1,326 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/ethindexa/utils"
+	"hw/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ConfigProblem is a single invalid setting, located by its dotted path in config.json,
+// e.g. "contracts.USDC.network.base.address".
+type ConfigProblem struct {
+	Path    string
+	Message string
+}
+
+// ConfigError lists every problem found in a configuration, so all of them can be fixed at once.
+type ConfigError struct {
+	Problems []ConfigProblem
+}
+
+func (e *ConfigError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("invalid config:")
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&sb, "\n  %s: %s", problem.Path, problem.Message)
+	}
+	return sb.String()
+}
+
+// configProblems collects problems while walking a configuration.
+type configProblems []ConfigProblem
+
+func (p *configProblems) add(path, format string, args ...interface{}) {
+	*p = append(*p, ConfigProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the collected problems as a *ConfigError, or nil if there are none.
+func (p configProblems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ConfigError{Problems: p}
+}
+
+// DefaultConfigPath returns internal/indexer/config.json under the working directory.
+func DefaultConfigPath() (string, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	return filepath.Join(workingDir, "internal", "indexer", "config.json"), nil
+}
+
+// LoadConfig reads the configuration file at path and validates it with ParseConfig.
+func LoadConfig(path string) (*Config, error) {
+	configFile, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return ParseConfig(configFile)
+}
+
+// ParseConfig decodes a configuration, rejecting fields Config does not define, and validates it.
+// Problems are returned together as a *ConfigError.
+func ParseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	var problems configProblems
+	unknownFields(data, reflect.TypeOf(config), "", &problems)
+	if err := config.Validate(); err != nil {
+		problems = append(problems, err.(*ConfigError).Problems...)
+	}
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// unknownFields reports the object keys in data that the type t has no field for. Keys are
+// matched case-insensitively, like encoding/json does.
+func unknownFields(data []byte, t reflect.Type, path string, problems *configProblems) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			return
+		}
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			fields[strings.ToLower(name)] = field.Type
+		}
+		for _, key := range sortedKeys(object) {
+			fieldType, exists := fields[strings.ToLower(key)]
+			if !exists {
+				problems.add(joinPath(path, key), "unknown field")
+				continue
+			}
+			unknownFields(object[key], fieldType, joinPath(path, key), problems)
+		}
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			return
+		}
+		for _, key := range sortedKeys(object) {
+			unknownFields(object[key], t.Elem(), joinPath(path, key), problems)
+		}
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return
+		}
+		for i, item := range items {
+			unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	}
+}
+
+// Validate checks the contracts of the configuration against the networks and ABIs, without
+// contacting any network. Problems are returned together as a *ConfigError.
+func (c *Config) Validate() error {
+	var problems configProblems
+
+	for _, networkName := range sortedKeys(c.Networks) {
+		network := c.Networks[networkName]
+		path := joinPath("networks", networkName)
+
+		if network.FetchConcurrency < 0 {
+			problems.add(joinPath(path, "fetchConcurrency"), "must not be negative")
+		}
+		if network.MaxBlockRange < 0 {
+			problems.add(joinPath(path, "maxBlockRange"), "must not be negative")
+		}
+		if network.SlowFetchLatency != "" {
+			latency, err := time.ParseDuration(network.SlowFetchLatency)
+			if err != nil || latency <= 0 {
+				problems.add(joinPath(path, "slowFetchLatency"), "invalid duration %q", network.SlowFetchLatency)
+			}
+		}
+	}
+
+	// The first contract seen at each network and address, to report duplicates
+	seen := make(map[string]string)
+
+	for _, contractName := range sortedKeys(c.Contracts) {
+		contract := c.Contracts[contractName]
+		path := joinPath("contracts", contractName)
+
+		if contract.HandlerTimeout != "" {
+			timeout, err := time.ParseDuration(contract.HandlerTimeout)
+			if err != nil || timeout <= 0 {
+				problems.add(joinPath(path, "handlerTimeout"), "invalid duration %q", contract.HandlerTimeout)
+			}
+		}
+
+		for _, networkName := range sortedKeys(contract.Networks) {
+			network := contract.Networks[networkName]
+			networkPath := joinPath(path, "network", networkName)
+
+			if _, exists := c.Networks[networkName]; !exists {
+				problems.add(networkPath, "network %q is not defined in networks", networkName)
+			}
+			if !common.IsHexAddress(network.Address) {
+				problems.add(joinPath(networkPath, "address"), "invalid address %q", network.Address)
+			} else if address := common.HexToAddress(network.Address); address == (common.Address{}) {
+				problems.add(joinPath(networkPath, "address"), "zero address")
+			} else {
+				key := networkName + ":" + address.Hex()
+				if first, exists := seen[key]; exists {
+					problems.add(joinPath(networkPath, "address"), "%s is already configured by %s", network.Address, first)
+				} else {
+					seen[key] = networkPath
+				}
+			}
+			if problem := network.StartBlock.validate(); problem != "" {
+				problems.add(joinPath(networkPath, "startBlock"), "%s", problem)
+			}
+		}
+
+		if len(contract.Events) == 0 {
+			problems.add(joinPath(path, "events"), "no events configured")
+		}
+		if contract.ABI == "" {
+			problems.add(joinPath(path, "abi"), "missing ABI")
+			continue
+		}
+		parsedABI, err := utils.LoadABI(contract.ABI)
+		if err != nil {
+			problems.add(joinPath(path, "abi"), "ABI %q not found", contract.ABI)
+			continue
+		}
+
+		events := make(map[string]bool, len(contract.Events))
+		for i, eventName := range contract.Events {
+			if _, exists := parsedABI.Events[eventName]; !exists {
+				problems.add(fmt.Sprintf("%s[%d]", joinPath(path, "events"), i), "event %q not found in ABI %q", eventName, contract.ABI)
+			}
+			events[eventName] = true
+		}
+		for _, eventName := range sortedKeys(contract.Filters) {
+			filterPath := joinPath(path, "filters", eventName)
+			if !events[eventName] {
+				problems.add(filterPath, "event %q is not in events", eventName)
+				continue
+			}
+			if _, exists := parsedABI.Events[eventName]; !exists {
+				continue
+			}
+			if _, err := compileFilters(parsedABI, eventName, contract.Filters[eventName]); err != nil {
+				problems.add(filterPath, "%v", err)
+			}
+		}
+	}
+
+	return problems.err()
+}
+
+// ValidateStartBlocks checks that no contract starts after the head block of its network.
+// Networks missing from heads are skipped.
+func (c *Config) ValidateStartBlocks(heads map[string]uint64) error {
+	var problems configProblems
+	for _, contractName := range sortedKeys(c.Contracts) {
+		contract := c.Contracts[contractName]
+		for _, networkName := range sortedKeys(contract.Networks) {
+			head, exists := heads[networkName]
+			if !exists {
+				continue
+			}
+			// Aliases resolve at or before the head
+			if startBlock := contract.Networks[networkName].StartBlock; startBlock.Alias == "" && startBlock.Number > 0 && uint64(startBlock.Number) > head {
+				problems.add(joinPath("contracts", contractName, "network", networkName, "startBlock"),
+					"start block %d is after the head block %d of %s", startBlock.Number, head, networkName)
+			}
+		}
+	}
+	return problems.err()
+}
+
+// fetchHeads returns the head block number of every client. Networks whose head cannot be
+// fetched are logged and left out, so an unreachable RPC does not fail validation.
+func fetchHeads(ctx context.Context, clients map[string]*ethclient.Client) map[string]uint64 {
+	heads := make(map[string]uint64, len(clients))
+	for networkName, client := range clients {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			logger.Warnf("Failed to get head block of network %s, skipping start block checks: %v", networkName, err)
+			continue
+		}
+		heads[networkName] = header.Number.Uint64()
+	}
+	return heads
+}
+
+// CheckConfig loads and validates the configuration file at path, then connects to every
+// network used by a contract to check start blocks against the head blocks.
+func CheckConfig(ctx context.Context, path string) (*Config, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make(map[string]*ethclient.Client)
+	for _, contract := range config.Contracts {
+		for networkName := range contract.Networks {
+			if _, exists := clients[networkName]; exists {
+				continue
+			}
+			client, err := ethclient.NewClient(networkName, config.Networks[networkName].RPCURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to network %s: %w", networkName, err)
+			}
+			clients[networkName] = client
+		}
+	}
+
+	if err := config.ValidateStartBlocks(fetchHeads(ctx, clients)); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func joinPath(parts ...string) string {
+	nonEmpty := parts[:0:0]
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ".")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
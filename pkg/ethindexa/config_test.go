@@ -0,0 +1,89 @@
+package ethindexa
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseConfig_Example tests that the example configuration is valid.
+func TestParseConfig_Example(t *testing.T) {
+	data, err := os.ReadFile("../../internal/indexer/config.example.json")
+	assert.NoError(t, err)
+
+	config, err := ParseConfig(data)
+	assert.NoError(t, err)
+	assert.Contains(t, config.Contracts, "UniswapV2")
+}
+
+// TestParseConfig_Problems tests that every problem is reported at once with its path.
+func TestParseConfig_Problems(t *testing.T) {
+	data := []byte(`{
+		"networks": {
+			"mainnet": {"chainId": 1, "rpc_url": "http://localhost:8545", "finalityBlock": 20},
+			"arbitrum": {"chainId": 42161, "rpc_url": "http://localhost:8546", "fetchConcurrency": -1, "slowFetchLatency": "fast"}
+		},
+		"contracts": {
+			"USDC": {
+				"abi": "erc20_usdc",
+				"network": {
+					"mainnet": {"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "startBlock": 1},
+					"base": {"address": "0x0000000000000000000000000000000000000000"}
+				},
+				"events": ["Transfer", "Swap"],
+				"filters": {"Approval": []}
+			},
+			"USDC2": {
+				"abi": "erc20_usdc",
+				"network": {
+					"mainnet": {"address": "0xA0b86991c6218b36c1D19D4a2e9Eb0cE3606eB48", "startBlock": "soon"}
+				},
+				"events": ["Transfer"]
+			},
+			"Pool": {
+				"abi": "missing",
+				"network": {"mainnet": {"address": "not-an-address"}},
+				"events": ["Swap"],
+				"handlerTimeout": "soon"
+			}
+		}
+	}`)
+
+	_, err := ParseConfig(data)
+
+	configErr, ok := err.(*ConfigError)
+	assert.True(t, ok, "expected *ConfigError, got %v", err)
+	assert.Equal(t, []ConfigProblem{
+		{Path: "networks.mainnet.finalityBlock", Message: "unknown field"},
+		{Path: "networks.arbitrum.fetchConcurrency", Message: "must not be negative"},
+		{Path: "networks.arbitrum.slowFetchLatency", Message: `invalid duration "fast"`},
+		{Path: "contracts.Pool.handlerTimeout", Message: `invalid duration "soon"`},
+		{Path: "contracts.Pool.network.mainnet.address", Message: `invalid address "not-an-address"`},
+		{Path: "contracts.Pool.abi", Message: `ABI "missing" not found`},
+		{Path: "contracts.USDC.network.base", Message: `network "base" is not defined in networks`},
+		{Path: "contracts.USDC.network.base.address", Message: "zero address"},
+		{Path: "contracts.USDC.events[1]", Message: `event "Swap" not found in ABI "erc20_usdc"`},
+		{Path: "contracts.USDC.filters.Approval", Message: `event "Approval" is not in events`},
+		{Path: "contracts.USDC2.network.mainnet.address", Message: "0xA0b86991c6218b36c1D19D4a2e9Eb0cE3606eB48 is already configured by contracts.USDC.network.mainnet"},
+		{Path: "contracts.USDC2.network.mainnet.startBlock", Message: `invalid start block "soon", must be a block number, "latest" or "genesis"`},
+	}, configErr.Problems)
+}
+
+// TestValidateStartBlocks tests that start blocks after the network head are rejected.
+func TestValidateStartBlocks(t *testing.T) {
+	config := &Config{
+		Contracts: map[string]ContractConfig{
+			"USDC": {Networks: map[string]ContractNetworkConfig{
+				"mainnet": {StartBlock: StartBlockAt(200)},
+				"base":    {StartBlock: StartBlockAt(100)},
+			}},
+		},
+	}
+
+	assert.NoError(t, config.ValidateStartBlocks(map[string]uint64{"mainnet": 200, "base": 150}))
+	assert.NoError(t, config.ValidateStartBlocks(map[string]uint64{}))
+
+	err := config.ValidateStartBlocks(map[string]uint64{"mainnet": 150, "base": 150})
+	assert.EqualError(t, err, "invalid config:\n  contracts.USDC.network.mainnet.startBlock: start block 200 is after the head block 150 of mainnet")
+}
@@ -0,0 +1,356 @@
+// Package entitygen generates typed entity structs, Postgres repositories and migrations
+// from an entity schema file.
+package entitygen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"hw/pkg/ethindexa/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is the content of an entity schema file.
+type Schema struct {
+	Package  string   `yaml:"package"`
+	Entities []Entity `yaml:"entities"`
+}
+
+// Entity is a stored entity. Every entity has a string id primary key in addition to its fields.
+type Entity struct {
+	Name   string  `yaml:"name"`
+	Table  string  `yaml:"table"` // defaults to the snake_case name
+	Fields []Field `yaml:"fields"`
+}
+
+// Field is a column of an entity.
+type Field struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+// fieldType maps a schema type to its Go type, the import it needs and its column type.
+type fieldType struct {
+	goType   string
+	goImport string
+	sqlType  string
+}
+
+var fieldTypes = map[string]fieldType{
+	"string":    {goType: "string", sqlType: "text"},
+	"int":       {goType: "int64", sqlType: "bigint"},
+	"bool":      {goType: "bool", sqlType: "boolean"},
+	"decimal":   {goType: "model.Decimal", goImport: "hw/internal/model", sqlType: "numeric"},
+	"address":   {goType: "string", sqlType: "character varying(42)"},
+	"hash":      {goType: "string", sqlType: "character varying(66)"},
+	"timestamp": {goType: "time.Time", goImport: "time", sqlType: "timestamp with time zone"},
+	"json":      {goType: "json.RawMessage", goImport: "encoding/json", sqlType: "jsonb"},
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// Parse parses and validates a YAML entity schema.
+func Parse(data []byte) (*Schema, error) {
+	var schema Schema
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	if schema.Package == "" {
+		return nil, fmt.Errorf("schema package is required")
+	}
+	entities := make(map[string]bool)
+	for i := range schema.Entities {
+		entity := &schema.Entities[i]
+		if !namePattern.MatchString(entity.Name) {
+			return nil, fmt.Errorf("invalid entity name %q", entity.Name)
+		}
+		if entities[entity.Name] {
+			return nil, fmt.Errorf("duplicate entity %s", entity.Name)
+		}
+		entities[entity.Name] = true
+		if entity.Table == "" {
+			entity.Table = utils.SnakeCase(entity.Name)
+		}
+
+		fields := map[string]bool{"id": true}
+		for _, field := range entity.Fields {
+			if !namePattern.MatchString(field.Name) {
+				return nil, fmt.Errorf("entity %s: invalid field name %q", entity.Name, field.Name)
+			}
+			if fields[strings.ToLower(field.Name)] {
+				return nil, fmt.Errorf("entity %s: duplicate field %s", entity.Name, field.Name)
+			}
+			fields[strings.ToLower(field.Name)] = true
+			if _, exists := fieldTypes[field.Type]; !exists {
+				return nil, fmt.Errorf("entity %s: field %s has unknown type %q", entity.Name, field.Name, field.Type)
+			}
+		}
+	}
+	return &schema, nil
+}
+
+// GoName returns the exported Go name of the field.
+func (f Field) GoName() string {
+	runes := []rune(f.Name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// Column returns the column of the field.
+func (f Field) Column() string {
+	return utils.SnakeCase(f.Name)
+}
+
+// GoType returns the Go type of the field.
+func (f Field) GoType() string {
+	return fieldTypes[f.Type].goType
+}
+
+// SQLType returns the column type of the field.
+func (f Field) SQLType() string {
+	return fieldTypes[f.Type].sqlType
+}
+
+// Columns returns the columns of the entity, starting with id.
+func (e Entity) Columns() []string {
+	columns := []string{"id"}
+	for _, field := range e.Fields {
+		columns = append(columns, field.Column())
+	}
+	return columns
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"placeholders": func(n int) string {
+		placeholders := make([]string, n)
+		for i := range placeholders {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+		}
+		return strings.Join(placeholders, ", ")
+	},
+	"updates": func(columns []string) string {
+		updates := make([]string, 0, len(columns))
+		for _, column := range columns[1:] {
+			updates = append(updates, column+" = EXCLUDED."+column)
+		}
+		return strings.Join(updates, ",\n\t\t\t")
+	},
+}
+
+var goTemplate = template.Must(template.New("go").Funcs(templateFuncs).Parse(`// Code generated by entitygen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{if .}}"{{.}}"{{end}}
+{{- end}}
+)
+{{range .Entities}}
+// {{.Name}} is a row of {{.Table}}.
+type {{.Name}} struct {
+	ID string ` + "`json:\"id\"`" + `
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`json:\"{{.Name}}\"`" + `
+{{- end}}
+}
+
+// {{.Name}}Repository reads and writes {{.Name}} entities.
+type {{.Name}}Repository struct {
+	db pg.PgxPool
+}
+
+// New{{.Name}}Repository creates a {{.Name}}Repository on top of the given pool.
+func New{{.Name}}Repository(db pg.PgxPool) *{{.Name}}Repository {
+	return &{{.Name}}Repository{db: db}
+}
+
+// Get returns the {{.Name}} with the given id, or ethindexa.ErrEntityNotFound.
+func (r *{{.Name}}Repository) Get(ctx context.Context, id string) (*{{.Name}}, error) {
+	const query = ` + "`SELECT {{join .Columns \", \"}} FROM {{.Table}} WHERE id = $1`" + `
+
+	var entity {{.Name}}
+	err := r.db.QueryRow(ctx, query, id).Scan(&entity.ID{{range .Fields}}, &entity.{{.GoName}}{{end}})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ethindexa.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to get {{.Name}} %s: %w", id, err)
+	}
+	return &entity, nil
+}
+
+// List returns up to limit {{.Name}} entities ordered by id, skipping the first offset.
+func (r *{{.Name}}Repository) List(ctx context.Context, limit, offset int) ([]*{{.Name}}, error) {
+	const query = ` + "`SELECT {{join .Columns \", \"}} FROM {{.Table}} ORDER BY id LIMIT $1 OFFSET $2`" + `
+
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list {{.Name}}: %w", err)
+	}
+	defer rows.Close()
+
+	entities := []*{{.Name}}{}
+	for rows.Next() {
+		var entity {{.Name}}
+		if err := rows.Scan(&entity.ID{{range .Fields}}, &entity.{{.GoName}}{{end}}); err != nil {
+			return nil, fmt.Errorf("failed to scan {{.Name}}: %w", err)
+		}
+		entities = append(entities, &entity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list {{.Name}}: %w", err)
+	}
+	return entities, nil
+}
+
+// Upsert creates the {{.Name}} or replaces its fields.
+func (r *{{.Name}}Repository) Upsert(ctx context.Context, entity *{{.Name}}) error {
+	const query = ` + "`" + `
+		INSERT INTO {{.Table}} ({{join .Columns ", "}})
+		VALUES ({{placeholders (len .Columns)}})
+		ON CONFLICT (id) DO {{if .Fields}}UPDATE SET
+			{{updates .Columns}}{{else}}NOTHING{{end}}
+	` + "`" + `
+
+	if _, err := r.db.Exec(ctx, query, entity.ID{{range .Fields}}, entity.{{.GoName}}{{end}}); err != nil {
+		return fmt.Errorf("failed to upsert {{.Name}} %s: %w", entity.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the {{.Name}} with the given id.
+func (r *{{.Name}}Repository) Delete(ctx context.Context, id string) error {
+	const query = ` + "`DELETE FROM {{.Table}} WHERE id = $1`" + `
+
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete {{.Name}} %s: %w", id, err)
+	}
+	return nil
+}
+{{end}}
+// Repositories holds the repository of every entity.
+type Repositories struct {
+{{- range .Entities}}
+	{{.Name}} *{{.Name}}Repository
+{{- end}}
+}
+
+// NewRepositories creates the repositories of every entity on top of the given pool.
+func NewRepositories(db pg.PgxPool) *Repositories {
+	return &Repositories{
+{{- range .Entities}}
+		{{.Name}}: New{{.Name}}Repository(db),
+{{- end}}
+	}
+}
+`))
+
+var upTemplate = template.Must(template.New("up").Parse(`-- Code generated by entitygen. DO NOT EDIT.
+
+BEGIN;
+{{range .Entities}}
+CREATE TABLE IF NOT EXISTS "{{.Table}}"
+(
+    "id" text PRIMARY KEY{{range .Fields}},
+    "{{.Column}}" {{.SQLType}} NOT NULL{{end}}
+);
+{{end}}
+COMMIT;
+`))
+
+var downTemplate = template.Must(template.New("down").Parse(`-- Code generated by entitygen. DO NOT EDIT.
+
+BEGIN;
+{{range .Entities}}
+DROP TABLE IF EXISTS "{{.Table}}";
+{{- end}}
+
+COMMIT;
+`))
+
+// GenerateGo returns the Go source of the entity structs and repositories.
+func GenerateGo(schema *Schema) ([]byte, error) {
+	imports := map[string]bool{
+		"context":                 true,
+		"errors":                  true,
+		"fmt":                     true,
+		"hw/pkg/ethindexa":        true,
+		"hw/pkg/pg":               true,
+		"github.com/jackc/pgx/v5": true,
+	}
+	for _, entity := range schema.Entities {
+		for _, field := range entity.Fields {
+			if goImport := fieldTypes[field.Type].goImport; goImport != "" {
+				imports[goImport] = true
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	err := goTemplate.Execute(&buf, struct {
+		*Schema
+		Imports []string
+	}{schema, groupImports(imports)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Go code: %w", err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go code: %w", err)
+	}
+	return source, nil
+}
+
+// groupImports orders imports like goimports: standard library, then module packages,
+// then third-party packages, separated by blank lines.
+func groupImports(imports map[string]bool) []string {
+	var std, module, thirdParty []string
+	for path := range imports {
+		switch {
+		case strings.HasPrefix(path, "hw/"):
+			module = append(module, path)
+		case strings.Contains(path, "."):
+			thirdParty = append(thirdParty, path)
+		default:
+			std = append(std, path)
+		}
+	}
+
+	var grouped []string
+	for _, group := range [][]string{std, module, thirdParty} {
+		if len(group) == 0 {
+			continue
+		}
+		sort.Strings(group)
+		if len(grouped) > 0 {
+			grouped = append(grouped, "")
+		}
+		grouped = append(grouped, group...)
+	}
+	return grouped
+}
+
+// GenerateMigration returns the up and down migrations creating the entity tables.
+func GenerateMigration(schema *Schema) (up, down []byte, err error) {
+	var upBuf, downBuf bytes.Buffer
+	if err := upTemplate.Execute(&upBuf, schema); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate up migration: %w", err)
+	}
+	if err := downTemplate.Execute(&downBuf, schema); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate down migration: %w", err)
+	}
+	return upBuf.Bytes(), downBuf.Bytes(), nil
+}
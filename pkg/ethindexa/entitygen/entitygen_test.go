@@ -0,0 +1,85 @@
+package entitygen
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParse_Invalid tests that invalid schemas are rejected with the offending entity and field.
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "missing package", schema: "entities: []", wantErr: "schema package is required"},
+		{name: "unknown key", schema: "package: entities\nentites: []", wantErr: "field entites not found"},
+		{name: "invalid entity name", schema: "package: entities\nentities:\n  - name: pair-day", wantErr: `invalid entity name "pair-day"`},
+		{
+			name:    "duplicate field",
+			schema:  "package: entities\nentities:\n  - name: Pair\n    fields:\n      - {name: id, type: string}",
+			wantErr: "entity Pair: duplicate field id",
+		},
+		{
+			name:    "unknown type",
+			schema:  "package: entities\nentities:\n  - name: Pair\n    fields:\n      - {name: reserve, type: uint256}",
+			wantErr: `entity Pair: field reserve has unknown type "uint256"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.schema))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGenerate tests the generated columns, statements and migration of an entity.
+func TestGenerate(t *testing.T) {
+	schema, err := Parse([]byte(`
+package: entities
+entities:
+  - name: TokenHolder
+    table: holders
+    fields:
+      - {name: token, type: address}
+      - {name: balance, type: decimal}
+  - name: Marker
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "token", "balance"}, schema.Entities[0].Columns())
+	assert.Equal(t, "marker", schema.Entities[1].Table)
+
+	source, err := GenerateGo(schema)
+	assert.NoError(t, err)
+	assert.Contains(t, string(source), "Balance model.Decimal `json:\"balance\"`")
+	assert.Contains(t, string(source), "INSERT INTO holders (id, token, balance)\n\t\tVALUES ($1, $2, $3)\n\t\tON CONFLICT (id) DO UPDATE SET\n\t\t\ttoken = EXCLUDED.token,\n\t\t\tbalance = EXCLUDED.balance")
+	assert.Contains(t, string(source), "ON CONFLICT (id) DO NOTHING")
+	assert.Contains(t, string(source), `"hw/internal/model"`)
+	assert.NotContains(t, string(source), `"time"`)
+
+	up, down, err := GenerateMigration(schema)
+	assert.NoError(t, err)
+	assert.Contains(t, string(up), "CREATE TABLE IF NOT EXISTS \"holders\"\n(\n    \"id\" text PRIMARY KEY,\n    \"token\" character varying(42) NOT NULL,\n    \"balance\" numeric NOT NULL\n);")
+	assert.Contains(t, string(down), `DROP TABLE IF EXISTS "marker";`)
+}
+
+// TestGenerate_UpToDate tests that the committed entities match internal/indexer/schema.yaml.
+func TestGenerate_UpToDate(t *testing.T) {
+	data, err := os.ReadFile("../../../internal/indexer/schema.yaml")
+	assert.NoError(t, err)
+	schema, err := Parse(data)
+	assert.NoError(t, err)
+
+	source, err := GenerateGo(schema)
+	assert.NoError(t, err)
+
+	generated, err := os.ReadFile("../../../internal/indexer/entities/entities_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(generated), string(source), "run make entities")
+}
@@ -0,0 +1,183 @@
+package ethclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"hw/pkg/logger"
+)
+
+// ErrCircuitOpen is returned without calling the RPC while the endpoint's breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+var (
+	// DefaultBreakerThreshold is the number of consecutive failures that opens a breaker.
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerCooldown is how long an open breaker short-circuits calls before probing again.
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerStats is a snapshot of a breaker's state and counters.
+type BreakerStats struct {
+	State         string `json:"state"`
+	Failures      int    `json:"failures"`
+	Opens         uint64 `json:"opens"`
+	ShortCircuits uint64 `json:"short_circuits"`
+}
+
+// CircuitBreaker stops calling an RPC endpoint after consecutive failures.
+// After the cooldown a single probe call is let through; its outcome closes or re-opens the breaker.
+type CircuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mutex         sync.Mutex
+	state         BreakerState
+	failures      int
+	openedAt      time.Time
+	probing       bool
+	opens         uint64
+	shortCircuits uint64
+}
+
+// NewCircuitBreaker creates a closed breaker. name is used in logs and must not contain credentials.
+func NewCircuitBreaker(name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may be made, returning ErrCircuitOpen otherwise.
+func (b *CircuitBreaker) Allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			b.shortCircuits++
+			return ErrCircuitOpen
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			b.shortCircuits++
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record reports the outcome of an allowed call.
+func (b *CircuitBreaker) Record(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.setState(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.threshold) {
+		b.openedAt = b.now()
+		b.opens++
+		b.setState(BreakerOpen)
+	}
+}
+
+// Abort releases an allowed call without recording an outcome.
+func (b *CircuitBreaker) Abort() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.probing = false
+}
+
+// Stats returns a snapshot of the breaker.
+func (b *CircuitBreaker) Stats() BreakerStats {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return BreakerStats{
+		State:         b.state.String(),
+		Failures:      b.failures,
+		Opens:         b.opens,
+		ShortCircuits: b.shortCircuits,
+	}
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	if state == BreakerOpen {
+		logger.Warnf("RPC circuit breaker for %s changed %s -> %s after %d consecutive failures", b.name, b.state, state, b.failures)
+	} else {
+		logger.Infof("RPC circuit breaker for %s changed %s -> %s", b.name, b.state, state)
+	}
+	b.state = state
+}
+
+// breakers holds one breaker per RPC URL, shared by every client using that URL.
+var breakers = struct {
+	sync.Mutex
+	byURL map[string]*CircuitBreaker
+}{byURL: make(map[string]*CircuitBreaker)}
+
+// breakerFor returns the breaker of an RPC URL, creating it on first use.
+func breakerFor(name, rpcURL string) *CircuitBreaker {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	b, exists := breakers.byURL[rpcURL]
+	if !exists {
+		b = NewCircuitBreaker(name, DefaultBreakerThreshold, DefaultBreakerCooldown)
+		breakers.byURL[rpcURL] = b
+	}
+	return b
+}
+
+// BreakerStatsByName returns a snapshot of every RPC breaker keyed by its name.
+func BreakerStatsByName() map[string]BreakerStats {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	stats := make(map[string]BreakerStats, len(breakers.byURL))
+	for _, b := range breakers.byURL {
+		stats[b.name] = b.Stats()
+	}
+	return stats
+}
@@ -0,0 +1,73 @@
+package ethclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestBreaker creates a breaker with a controllable clock.
+func newTestBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, *time.Time) {
+	now := time.Now()
+	b := NewCircuitBreaker("mainnet", threshold, cooldown)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+// TestCircuitBreaker_OpensAfterThreshold tests that consecutive failures open the breaker and short-circuit calls.
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+	failure := errors.New("dial tcp: connection refused")
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, b.Allow())
+		b.Record(failure)
+	}
+
+	assert.ErrorIs(t, b.Allow(), ErrCircuitOpen)
+	stats := b.Stats()
+	assert.Equal(t, "open", stats.State)
+	assert.Equal(t, uint64(1), stats.Opens)
+	assert.Equal(t, uint64(1), stats.ShortCircuits)
+}
+
+// TestCircuitBreaker_SuccessResetsFailures tests that a success in between keeps the breaker closed.
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute)
+	failure := errors.New("timeout")
+
+	b.Record(failure)
+	b.Record(nil)
+	b.Record(failure)
+
+	assert.NoError(t, b.Allow())
+	assert.Equal(t, "closed", b.Stats().State)
+}
+
+// TestCircuitBreaker_HalfOpenProbe tests that a single probe is let through after the cooldown.
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+
+	b.Record(errors.New("timeout"))
+	assert.ErrorIs(t, b.Allow(), ErrCircuitOpen)
+
+	*now = now.Add(time.Minute)
+	assert.NoError(t, b.Allow())
+	assert.Equal(t, "half-open", b.Stats().State)
+	// Only one probe at a time
+	assert.ErrorIs(t, b.Allow(), ErrCircuitOpen)
+
+	// A failing probe re-opens the breaker
+	b.Record(errors.New("timeout"))
+	assert.Equal(t, "open", b.Stats().State)
+	assert.Equal(t, uint64(2), b.Stats().Opens)
+
+	// A successful probe closes it
+	*now = now.Add(time.Minute)
+	assert.NoError(t, b.Allow())
+	b.Record(nil)
+	assert.Equal(t, "closed", b.Stats().State)
+	assert.NoError(t, b.Allow())
+}
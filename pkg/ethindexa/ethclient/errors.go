@@ -0,0 +1,77 @@
+package ethclient
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrRangeTooLarge is returned when a provider rejects the logs of a single block as too large,
+// so the range cannot be split any further.
+var ErrRangeTooLarge = errors.New("block range too large")
+
+// RangeLimit is what a provider tells about its limits when it rejects an eth_getLogs range.
+type RangeLimit struct {
+	MaxBlocks   uint64 // blocks per call the provider accepts, 0 when it does not say
+	SuggestedTo uint64 // end of a range from the same start the provider suggests, 0 when it does not
+}
+
+// rangeLimitMessages are parts of the errors providers return when the logs or blocks of an
+// eth_getLogs call exceed their limits.
+var rangeLimitMessages = []string{
+	"query returned more than",   // Infura, Geth
+	"log response size exceeded", // Alchemy
+	"block range is too wide",    // Ankr
+	"block range too large",
+	"exceed maximum block range", // BSC
+	"is limited to a",            // QuickNode: "eth_getLogs is limited to a 10,000 range"
+}
+
+var (
+	// suggestedRangePattern matches the range Infura and Alchemy suggest, e.g. "[0x1a2b, 0x1a40]".
+	suggestedRangePattern = regexp.MustCompile(`\[(0x[0-9a-f]+),\s*(0x[0-9a-f]+)\]`)
+	// maxBlocksPatterns match the block range limits QuickNode and BSC report.
+	maxBlocksPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`is limited to a ([0-9,]+) range`),
+		regexp.MustCompile(`exceed maximum block range:?\s*([0-9,]+)`),
+	}
+)
+
+// ParseRangeLimit reports whether err is a provider rejecting an eth_getLogs range as too large,
+// and the limits it tells about.
+func ParseRangeLimit(err error) (RangeLimit, bool) {
+	if err == nil {
+		return RangeLimit{}, false
+	}
+	message := strings.ToLower(err.Error())
+
+	matched := false
+	for _, part := range rangeLimitMessages {
+		if strings.Contains(message, part) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return RangeLimit{}, false
+	}
+
+	var limit RangeLimit
+	if match := suggestedRangePattern.FindStringSubmatch(message); match != nil {
+		if to, err := hexutil.DecodeUint64(match[2]); err == nil {
+			limit.SuggestedTo = to
+		}
+	}
+	for _, pattern := range maxBlocksPatterns {
+		if match := pattern.FindStringSubmatch(message); match != nil {
+			if blocks, err := strconv.ParseUint(strings.ReplaceAll(match[1], ",", ""), 10, 64); err == nil {
+				limit.MaxBlocks = blocks
+			}
+			break
+		}
+	}
+	return limit, true
+}
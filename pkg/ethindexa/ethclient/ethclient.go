@@ -1,11 +1,18 @@
 package ethclient
 
 import (
-	"os"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"hw/pkg/cache"
+	"hw/pkg/config"
+	"hw/pkg/request"
 
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // Client represents an Ethereum client with caching capabilities.
@@ -14,8 +21,14 @@ type Client struct {
 	RPCURL     string
 	Client     *ethclient.Client
 	localCache cache.Cache
+	// DebugRequests logs the JSON-RPC request and response bodies of this client.
+	DebugRequests bool
+	breaker       *CircuitBreaker
 }
 
+// debugBodyLimit caps the size of logged JSON-RPC bodies; blocks with full transactions get large.
+const debugBodyLimit = 4096
+
 // ErrorResponse represents the structure of an error response from the Ethereum JSON-RPC.
 type ErrorResponse struct {
 	Jsonrpc string      `json:"jsonrpc"`
@@ -33,13 +46,86 @@ func NewClient(network, rpcURL string) (*Client, error) {
 		return nil, err
 	}
 
-	os.Setenv("CACHE_DEFAULT_TTL", "10s")
-	cache := cache.NewLocalCache()
+	cacheConfig := config.Default().Cache
+	cacheConfig.DefaultTTL = 10 * time.Second
+	// A pending transaction is mined within seconds, so expired copies are not served
+	cacheConfig.StaleTTL = 0
+	cache := cache.NewLocalCache(cacheConfig)
 
 	return &Client{
 		Name:       network,
 		Client:     client,
 		RPCURL:     rpcURL,
 		localCache: cache,
+		breaker:    breakerFor(network, rpcURL),
 	}, nil
 }
+
+// guard runs call through the circuit breaker of the client's RPC URL.
+// Only transport and server failures count; JSON-RPC errors mean the endpoint is alive.
+func (c *Client) guard(call func() error) error {
+	if c.breaker == nil {
+		return call()
+	}
+	if err := c.breaker.Allow(); err != nil {
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+
+	err := call()
+	var rpcErr *rpcError
+	var gethErr rpc.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller gave up, which says nothing about the endpoint
+		c.breaker.Abort()
+	case errors.As(err, &rpcErr), errors.As(err, &gethErr):
+		c.breaker.Record(nil)
+	default:
+		c.breaker.Record(err)
+	}
+	return err
+}
+
+// rpcError is a JSON-RPC error returned by a reachable endpoint.
+type rpcError struct {
+	Code    int
+	Message string
+}
+
+// Error implements the error interface.
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("API error code %d: %s", e.Code, e.Message)
+}
+
+// callRPC posts a JSON-RPC request and decodes the reply into T.
+// JSON-RPC errors are reported with a 200 status, so the reply is checked for an error member first.
+func callRPC[T any](ctx context.Context, c *Client, reqBody string, opts ...request.Option) (T, error) {
+	var res T
+
+	err := c.guard(func() error {
+		raw, err := request.PostJSON[json.RawMessage](ctx, request.NewClient(c.requestOptions(opts...)...), c.RPCURL, reqBody)
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+
+		var errResp ErrorResponse
+		if err := json.Unmarshal(raw, &errResp); err == nil && (errResp.Error.Code != 0 || errResp.Error.Message != "") {
+			return &rpcError{Code: errResp.Error.Code, Message: errResp.Error.Message}
+		}
+
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return fmt.Errorf("failed to decode RPC response: %w", err)
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+// requestOptions appends the client-wide request options to opts.
+func (c *Client) requestOptions(opts ...request.Option) []request.Option {
+	if c.DebugRequests {
+		opts = append(opts, request.DebugLogging(debugBodyLimit))
+	}
+	return opts
+}
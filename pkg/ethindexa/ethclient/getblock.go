@@ -4,10 +4,9 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"reflect"
 	"time"
 
-	"hw/pkg/logger"
+	"hw/pkg/cache"
 	"hw/pkg/request"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -67,33 +66,12 @@ func (block GetBlockResponse) Time() int64 {
 
 // GetBlockByNumber retrieves a block by its number.
 func (c *Client) GetBlockByNumber(ctx context.Context, number *big.Int) (*GetBlockResponse, error) {
-	var res GetBlockResponse
-	var errResp ErrorResponse
-
 	// Format the request parameters by converting the block number to a hexadecimal string.
 	reqBody := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["0x%s", true],"id":1}`, number.Text(16))
 
-	response, reqErr := request.NewClient(
-		request.Timeout("5s"),
-		request.SetRetryCount(0),
-		request.Header(map[string]string{
-			"Content-Type": "application/json",
-		}),
-	).
-		SetResult(&res).
-		SetError(&errResp).
-		SetBody(reqBody).
-		Do("POST", c.RPCURL)
-
-	// Check for API response errors.
-	if !reflect.DeepEqual(errResp, ErrorResponse{}) {
-		return nil, fmt.Errorf("API error code %d: %s", errResp.Error.Code, errResp.Error.Message)
-	}
-
-	// Check for request execution errors.
-	if reqErr != nil {
-		logger.Warnf("request response data: %+v", response)
-		return nil, fmt.Errorf("request failed: %w", reqErr)
+	res, err := callRPC[GetBlockResponse](ctx, c, reqBody, request.Timeout("5s"), request.SetRetryCount(0))
+	if err != nil {
+		return nil, err
 	}
 
 	return &res, nil
@@ -101,37 +79,11 @@ func (c *Client) GetBlockByNumber(ctx context.Context, number *big.Int) (*GetBlo
 
 // GetBlockByHash retrieves a block by its hash.
 func (c *Client) GetBlockByHash(ctx context.Context, hash string) (*GetBlockResponse, error) {
-	var res GetBlockResponse
-	var errResp ErrorResponse
-
-	// Look for the user in the cache, if not found, query from the database
-	err := c.localCache.GetFunc(ctx, c.localCache.FormatKey(c.Name, "eth_getBlockByHash", hash), &res, time.Second*5, func(ctx context.Context) (interface{}, error) {
-		var res GetBlockResponse
+	// Look for the block in the cache, if not found, query it from the RPC
+	res, err := cache.GetOrLoad(ctx, c.localCache, c.localCache.FormatKey(c.Name, "eth_getBlockByHash", hash), time.Second*5, func(ctx context.Context) (GetBlockResponse, error) {
 		reqBody := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getBlockByHash","params":["%s", true],"id":1}`, hash)
 
-		_, reqErr := request.NewClient(
-			request.Timeout("12s"),
-			request.SetRetryCount(2),
-			request.Header(map[string]string{
-				"Content-Type": "application/json",
-			}),
-		).
-			SetResult(&res).
-			SetError(&errResp).
-			SetBody(reqBody).
-			Do("POST", c.RPCURL)
-
-		// Check for API response errors.
-		if !reflect.DeepEqual(errResp, ErrorResponse{}) {
-			return nil, fmt.Errorf("API error code %d: %s", errResp.Error.Code, errResp.Error.Message)
-		}
-
-		// Check for request execution errors.
-		if reqErr != nil {
-			return nil, fmt.Errorf("request failed: %w", reqErr)
-		}
-
-		return res, nil
+		return callRPC[GetBlockResponse](ctx, c, reqBody, request.Timeout("12s"), request.SetRetryCount(2))
 	})
 
 	return &res, err
@@ -139,5 +91,30 @@ func (c *Client) GetBlockByHash(ctx context.Context, hash string) (*GetBlockResp
 
 // HeaderByNumber retrieves a block header by its number.
 func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
-	return c.Client.HeaderByNumber(ctx, number)
+	var header *types.Header
+	err := c.guard(func() (err error) {
+		header, err = c.Client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+// ChainID retrieves the chain ID of the network served by the RPC.
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	var chainID *big.Int
+	err := c.guard(func() (err error) {
+		chainID, err = c.Client.ChainID(ctx)
+		return err
+	})
+	return chainID, err
+}
+
+// CodeAt retrieves the code of an address at a block, or at the latest block when number is nil.
+func (c *Client) CodeAt(ctx context.Context, account common.Address, number *big.Int) ([]byte, error) {
+	var code []byte
+	err := c.guard(func() (err error) {
+		code, err = c.Client.CodeAt(ctx, account, number)
+		return err
+	})
+	return code, err
 }
@@ -3,9 +3,9 @@ package ethclient
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"time"
 
+	"hw/pkg/cache"
 	"hw/pkg/request"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -51,37 +51,9 @@ func (tx GetTransactionResponse) GetHash() common.Hash {
 
 // GetTransactionByHash retrieves a transaction by its hash, using local cache if available.
 func (c *Client) GetTransactionByHash(ctx context.Context, hash string) (AutoGenerated, error) {
-	var res AutoGenerated
-	var errResp ErrorResponse
-
-	err := c.localCache.GetFunc(ctx, c.localCache.FormatKey(c.Name, "eth_getTransactionByHash", hash), &res, 3*time.Second, func(ctx context.Context) (interface{}, error) {
-		var res AutoGenerated
+	return cache.GetOrLoad(ctx, c.localCache, c.localCache.FormatKey(c.Name, "eth_getTransactionByHash", hash), 3*time.Second, func(ctx context.Context) (AutoGenerated, error) {
 		reqBody := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getTransactionByHash","params":["%s"],"id":1}`, hash)
 
-		_, reqErr := request.NewClient(
-			request.Timeout("8s"),
-			request.SetRetryCount(0),
-			request.Header(map[string]string{
-				"Content-Type": "application/json",
-			}),
-		).
-			SetResult(&res).
-			SetError(&errResp).
-			SetBody(reqBody).
-			Do("POST", c.RPCURL)
-
-		// Check for API response errors.
-		if !reflect.DeepEqual(errResp, ErrorResponse{}) {
-			return nil, fmt.Errorf("API error code %d: %s", errResp.Error.Code, errResp.Error.Message)
-		}
-
-		// Check for request execution errors.
-		if reqErr != nil {
-			return nil, fmt.Errorf("request failed: %w", reqErr)
-		}
-
-		return res, nil // Return the value itself to avoid double pointer
+		return callRPC[AutoGenerated](ctx, c, reqBody, request.Timeout("8s"), request.SetRetryCount(0))
 	})
-
-	return res, err
 }
@@ -2,6 +2,7 @@ package ethclient
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
@@ -20,10 +21,55 @@ func (c *Client) GetLogsByBlockNumber(ctx context.Context, fromNumber, endNumber
 	}
 
 	// Use the provided context instead of creating a new one
-	logs, err := c.Client.FilterLogs(ctx, query)
+	var logs []types.Log
+	err := c.guard(func() (err error) {
+		logs, err = c.Client.FilterLogs(ctx, query)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return logs, nil
 }
+
+// GetLogsSplitting retrieves the logs between fromBlock and toBlock inclusive like
+// GetLogsByBlockNumber. When the provider rejects the range as too large it is split, at the end
+// the provider suggests or its block limit when it tells one and in halves otherwise, and the parts
+// are fetched in turn. onSplit, when set, is called with every rejected range. A single block
+// rejected as too large fails with ErrRangeTooLarge.
+func (c *Client) GetLogsSplitting(ctx context.Context, fromBlock, toBlock uint64, addresses []common.Address, onSplit func(fromBlock, toBlock uint64, limit RangeLimit)) ([]types.Log, error) {
+	logs, err := c.GetLogsByBlockNumber(ctx, new(big.Int).SetUint64(fromBlock), new(big.Int).SetUint64(toBlock), addresses)
+	if err == nil {
+		return logs, nil
+	}
+
+	limit, ok := ParseRangeLimit(err)
+	if !ok {
+		return nil, err
+	}
+	if fromBlock >= toBlock {
+		return nil, fmt.Errorf("%w: logs of block %d: %v", ErrRangeTooLarge, fromBlock, err)
+	}
+
+	splitBlock := fromBlock + (toBlock-fromBlock)/2
+	switch {
+	case limit.SuggestedTo != 0 && limit.SuggestedTo >= fromBlock && limit.SuggestedTo < toBlock:
+		splitBlock = limit.SuggestedTo
+	case limit.MaxBlocks > 0 && limit.MaxBlocks <= toBlock-fromBlock:
+		splitBlock = fromBlock + limit.MaxBlocks - 1
+	}
+	if onSplit != nil {
+		onSplit(fromBlock, toBlock, limit)
+	}
+
+	logs, err = c.GetLogsSplitting(ctx, fromBlock, splitBlock, addresses, onSplit)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := c.GetLogsSplitting(ctx, splitBlock+1, toBlock, addresses, onSplit)
+	if err != nil {
+		return nil, err
+	}
+	return append(logs, rest...), nil
+}
@@ -0,0 +1,121 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseRangeLimit tests that the range limit errors of providers are recognized with the limits
+// they tell about.
+func TestParseRangeLimit(t *testing.T) {
+	tests := []struct {
+		message string
+		want    RangeLimit
+	}{
+		{message: "query returned more than 10000 results. Try with this block range [0x1406f40, 0x1406fa2].", want: RangeLimit{SuggestedTo: 0x1406fa2}},
+		{message: "Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range and no limit on the response size, or you can request any block range with a cap of 10K logs in the response. Based on your parameters, this block range should work: [0x1406f40, 0x1407710]", want: RangeLimit{SuggestedTo: 0x1407710}},
+		{message: "eth_getLogs is limited to a 10,000 range", want: RangeLimit{MaxBlocks: 10000}},
+		{message: "exceed maximum block range: 5000", want: RangeLimit{MaxBlocks: 5000}},
+		{message: "block range is too wide", want: RangeLimit{}},
+	}
+
+	for _, tt := range tests {
+		limit, ok := ParseRangeLimit(errors.New(tt.message))
+		assert.True(t, ok, tt.message)
+		assert.Equal(t, tt.want, limit, tt.message)
+	}
+
+	_, ok := ParseRangeLimit(errors.New("request failed: connection refused"))
+	assert.False(t, ok)
+	_, ok = ParseRangeLimit(nil)
+	assert.False(t, ok)
+}
+
+// fakeLogsRPC serves eth_getLogs with one log per block, rejecting ranges of more than maxBlocks
+// blocks with message, and records the requested ranges.
+func fakeLogsRPC(t *testing.T, maxBlocks uint64, message func(from, to uint64) string) (*Client, *[][2]uint64) {
+	var ranges [][2]uint64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Params []struct {
+				FromBlock string `json:"fromBlock"`
+				ToBlock   string `json:"toBlock"`
+			} `json:"params"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		from, _ := hexutil.DecodeUint64(req.Params[0].FromBlock)
+		to, _ := hexutil.DecodeUint64(req.Params[0].ToBlock)
+		ranges = append(ranges, [2]uint64{from, to})
+
+		w.Header().Set("Content-Type", "application/json")
+		if to-from+1 > maxBlocks {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32005,"message":%q}}`, req.ID, message(from, to))
+			return
+		}
+		logs := []map[string]interface{}{}
+		for block := from; block <= to; block++ {
+			logs = append(logs, map[string]interface{}{
+				"address":          "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc",
+				"topics":           []string{},
+				"data":             "0x",
+				"blockNumber":      hexutil.EncodeUint64(block),
+				"transactionHash":  common.Hash{}.Hex(),
+				"transactionIndex": "0x0",
+				"blockHash":        common.Hash{}.Hex(),
+				"logIndex":         "0x0",
+				"removed":          false,
+			})
+		}
+		result, _ := json.Marshal(logs)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("test", server.URL)
+	assert.NoError(t, err)
+	client.breaker = nil
+	return client, &ranges
+}
+
+// TestGetLogsSplitting tests that rejected ranges are split at the suggested end, at the block
+// limit or in halves, and that a rejected single block fails.
+func TestGetLogsSplitting(t *testing.T) {
+	ctx := context.Background()
+
+	client, ranges := fakeLogsRPC(t, 10, func(from, to uint64) string {
+		return fmt.Sprintf("query returned more than 10000 results. Try with this block range [%s, %s].", hexutil.EncodeUint64(from), hexutil.EncodeUint64(from+9))
+	})
+	var splits int
+	logs, err := client.GetLogsSplitting(ctx, 100, 124, nil, func(fromBlock, toBlock uint64, limit RangeLimit) { splits++ })
+	assert.NoError(t, err)
+	assert.Len(t, logs, 25)
+	assert.Equal(t, uint64(124), logs[24].BlockNumber)
+	assert.Equal(t, 2, splits)
+	assert.Equal(t, [][2]uint64{{100, 124}, {100, 109}, {110, 124}, {110, 119}, {120, 124}}, *ranges)
+
+	client, ranges = fakeLogsRPC(t, 4, func(from, to uint64) string { return "eth_getLogs is limited to a 4 range" })
+	logs, err = client.GetLogsSplitting(ctx, 0, 9, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 10)
+	assert.Equal(t, [][2]uint64{{0, 9}, {0, 3}, {4, 9}, {4, 7}, {8, 9}}, *ranges)
+
+	client, ranges = fakeLogsRPC(t, 2, func(from, to uint64) string { return "block range is too wide" })
+	logs, err = client.GetLogsSplitting(ctx, 0, 7, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 8)
+	assert.Equal(t, [][2]uint64{{0, 7}, {0, 3}, {0, 1}, {2, 3}, {4, 7}, {4, 5}, {6, 7}}, *ranges)
+
+	client, _ = fakeLogsRPC(t, 0, func(from, to uint64) string { return "block range is too wide" })
+	_, err = client.GetLogsSplitting(ctx, 5, 6, nil, nil)
+	assert.ErrorIs(t, err, ErrRangeTooLarge)
+}
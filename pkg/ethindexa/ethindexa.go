@@ -2,17 +2,20 @@ package ethindexa
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"hw/internal/service"
+	"hw/pkg/blobstore"
+	"hw/pkg/config"
 	"hw/pkg/logger"
 	"hw/pkg/pg"
 
@@ -29,6 +32,22 @@ import (
 type Config struct {
 	Networks  map[string]NetworkConfig  `json:"networks"`
 	Contracts map[string]ContractConfig `json:"contracts"`
+	Queue     string                    `json:"queue"` // "memory" (default) or "postgres"
+	Sharding  ShardingConfig            `json:"sharding"`
+	Leader    LeaderConfig              `json:"leader"`
+	Archive   ArchiveConfig             `json:"archive"`
+}
+
+// LeaderConfig enables single-writer mode where only the elected leader indexes.
+type LeaderConfig struct {
+	Enabled bool  `json:"enabled"`
+	LockID  int64 `json:"lockId"` // advisory lock key; defaults to DefaultLeaderLockID
+}
+
+// ShardingConfig enables splitting networks across indexer instances through leases.
+type ShardingConfig struct {
+	Enabled  bool   `json:"enabled"`
+	LeaseTTL string `json:"leaseTTL"` // e.g. "30s"; defaults to DefaultLeaseTTL
 }
 
 // NetworkConfig defines the configuration for a network.
@@ -38,30 +57,40 @@ type NetworkConfig struct {
 	Address            string `json:"address"`
 	StartBlock         int64  `json:"startBlock"`
 	FinalityBlockCount int64  `json:"finalityBlockCount"`
+	DebugRequests      bool   `json:"debugRequests"`    // log RPC request/response bodies
+	FetchConcurrency   int    `json:"fetchConcurrency"` // concurrent block requests; defaults to DefaultFetchConcurrency
+	SlowFetchLatency   string `json:"slowFetchLatency"` // e.g. "2s"; defaults to DefaultSlowFetchLatency
+	MaxBlockRange      int64  `json:"maxBlockRange"`    // blocks after the first one per range; defaults to DefaultMaxBlockRange
 }
 
 // ContractConfig defines the configuration for each contract.
 type ContractConfig struct {
-	ABI      string                           `json:"abi"`
-	Networks map[string]ContractNetworkConfig `json:"network"`
-	Events   []string                         `json:"events"`
+	ABI            string                           `json:"abi"`
+	Networks       map[string]ContractNetworkConfig `json:"network"`
+	Events         []string                         `json:"events"`
+	HandlerTimeout string                           `json:"handlerTimeout"` // e.g. "45s"; defaults to DefaultHandlerTimeout
+	Filters        map[string][]ArgFilterConfig     `json:"filters"`        // per event name; all filters must match
 }
 
 // ContractNetworkConfig defines the contract configuration on a specific network.
 type ContractNetworkConfig struct {
-	Address    string `json:"address"`
-	StartBlock int64  `json:"startBlock"`
+	Address    string     `json:"address"`
+	StartBlock StartBlock `json:"startBlock"` // a block number, "latest" or "genesis"
 }
 
 // EventConfig defines the structure of event configuration.
 type EventConfig struct {
 	ContractName       string
+	NetworkName        string
 	ContractAddress    common.Address
 	ContractABI        abi.ABI
 	StartBlock         *big.Int
 	FinalityBlockCount *big.Int
 	EventName          string
-	Handler            EventHandler
+	Handler            EventHandler // overrides the indexer's handler registry when set
+	HandlerKey         string
+	HandlerTimeout     time.Duration
+	Filters            []ArgFilter
 }
 
 // BlockTask defines the structure for block data.
@@ -77,6 +106,7 @@ type EventsTask struct {
 	Network string
 	Blocks  map[string]*ethclient.GetBlockResponse
 	Logs    []types.Log
+	Range   *BlockRange // recorded as processed once its logs are handed to the handlers; nil for replays
 	mutex   sync.RWMutex
 }
 
@@ -84,6 +114,8 @@ type EventsTask struct {
 type HandlerTask struct {
 	Network        string
 	BlockNumber    int64
+	HandlerKey     string
+	Timeout        time.Duration
 	EventHandler   EventHandler
 	IndexerService *IndexerService
 	Event          Event
@@ -99,29 +131,45 @@ type IndexerImpl struct {
 	Wg            sync.WaitGroup
 	HandlerQueues map[string]chan HandlerTask
 	EventQueues   map[string]chan *EventsTask
+	JobQueue      *JobQueue      // set when handler tasks are persisted in Postgres
+	Leases        *LeaseManager  // set when networks are sharded across instances
+	Leader        *LeaderElector // set in single-writer mode
+	Archive       Archive        // set when fetched blocks and logs are archived
+	Metrics       *HandlerMetrics
+	Pipeline      *PipelineMetrics   // time spent per pipeline stage
+	Stats         *StatusTracker     // progress reported by Status
+	Pauses        *Pauses            // paused networks and contracts
+	Quarantine    *HandlerQuarantine // handlers skipped after repeated failures
+	Store         EntityStore        // entity store handed to handlers
+	DB            pg.PgxPool         // database handed to handlers; nil without a database
+	Ranges        *RangeTracker      // set when processed block ranges are tracked for gap repair
+	Handlers      *HandlerRegistry
+	Runs          *HandlerRuns              // set when handler runs are recorded in handler_runs
+	Throttles     map[string]*FetchThrottle // block request limits per network
+	RangeSizes    map[string]*RangeSizer    // eth_getLogs range sizes per network
+	StartBlocks   *StartBlockResolver       // resolves the start blocks of contracts
 }
 
 var (
 	MaxBatchEventSize   = 10
 	MaxBatchHandlerSize = 200
+	// BlockRangeSize is the number of blocks after the first one fetched per range.
+	BlockRangeSize uint64 = 37
+	// DefaultHandlerTimeout bounds a handler run when its contract does not configure handlerTimeout.
+	DefaultHandlerTimeout = 30 * time.Second
 )
 
 // NewIndexer creates a new instance of IndexerImpl and injects necessary dependencies.
-func NewIndexer(db *pg.PostgresDB, service service.Service, handlers map[string]EventHandler) (*IndexerImpl, error) {
-	workingDir, err := os.Getwd()
+// blobstoreConfig configures the store of the blobstore archive storage.
+func NewIndexer(db *pg.PostgresDB, service service.Service, handlers map[string]EventHandler, blobstoreConfig config.Blobstore) (_ *IndexerImpl, err error) {
+	configPath, err := DefaultConfigPath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+		return nil, err
 	}
 
-	configPath := filepath.Join(workingDir, "internal", "indexer", "config.json")
-	configFile, err := os.ReadFile(configPath)
+	config, err := LoadConfig(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	var config Config
-	if err := json.Unmarshal(configFile, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+		return nil, err
 	}
 
 	// Initialize main context and cancel function.
@@ -135,86 +183,236 @@ func NewIndexer(db *pg.PostgresDB, service service.Service, handlers map[string]
 		CancelFunc:    cancel,
 		HandlerQueues: make(map[string]chan HandlerTask),
 		EventQueues:   make(map[string]chan *EventsTask),
+		Metrics:       NewHandlerMetrics(),
+		Pipeline:      NewPipelineMetrics(),
+		Stats:         NewStatusTracker(),
+		Quarantine:    NewHandlerQuarantine(DefaultQuarantineThreshold),
+		Throttles:     make(map[string]*FetchThrottle),
+		RangeSizes:    make(map[string]*RangeSizer),
+		Handlers:      NewHandlerRegistry(),
+		StartBlocks:   NewStartBlockResolver(nil),
+	}
+	if db != nil {
+		indexer.StartBlocks = NewStartBlockResolver(db)
+	}
+
+	// A failed setup stops the goroutines started so far, e.g. the pause refresher
+	defer func() {
+		if err != nil {
+			cancel()
+			indexer.Wg.Wait()
+		}
+	}()
+
+	for key, handler := range handlers {
+		if err := indexer.RegisterHandler(key, handler); err != nil {
+			return nil, err
+		}
+	}
+
+	switch config.Queue {
+	case "", QueueMemory:
+	case QueuePostgres:
+		if db == nil {
+			return nil, fmt.Errorf("queue %q requires a database", config.Queue)
+		}
+		indexer.JobQueue = NewJobQueue(db)
+	default:
+		return nil, fmt.Errorf("unknown queue type: %s", config.Queue)
+	}
+
+	if config.Archive.Enabled {
+		switch config.Archive.Storage {
+		case "", ArchivePostgres:
+			if db == nil {
+				return nil, fmt.Errorf("archive storage %q requires a database", ArchivePostgres)
+			}
+			indexer.Archive = NewPostgresArchive(db)
+		case ArchiveBlobstore:
+			store, err := blobstore.New(blobstoreConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create archive blobstore: %w", err)
+			}
+			indexer.Archive = NewBlobArchive(store)
+		default:
+			return nil, fmt.Errorf("unknown archive storage: %s", config.Archive.Storage)
+		}
+	}
+
+	if err := indexer.configureEvents(mainContext, config); err != nil {
+		return nil, err
+	}
+
+	if err := config.ValidateStartBlocks(fetchHeads(mainContext, indexer.Clients)); err != nil {
+		return nil, err
+	}
+
+	// Restore the pauses, so a restart keeps paused networks and contracts paused
+	if db != nil {
+		indexer.Pauses = NewPauses(db)
+		if err := indexer.Pauses.Load(mainContext); err != nil {
+			return nil, err
+		}
+		indexer.Wg.Add(1)
+		go indexer.startPauseRefresher(DefaultPauseRefreshInterval)
+	} else {
+		indexer.Pauses = NewPauses(nil)
 	}
 
-	// Initialize configuration as map[network][topic0][]*EventConfig
+	if db != nil {
+		indexer.Ranges = NewRangeTracker(db)
+		indexer.Store = NewPostgresEntityStore(db)
+		indexer.DB = db
+
+		indexer.Runs = NewHandlerRuns(db)
+		indexer.Wg.Add(1)
+		go func() {
+			defer indexer.Wg.Done()
+			indexer.Runs.Run(mainContext)
+		}()
+	} else {
+		indexer.Store = NewMemoryEntityStore()
+	}
+
+	// Initialize handlerQueue and eventQueue for each network
+	for networkName := range indexer.Events {
+		indexer.HandlerQueues[networkName] = make(chan HandlerTask, MaxBatchHandlerSize)
+		indexer.EventQueues[networkName] = make(chan *EventsTask, MaxBatchEventSize)
+	}
+
+	if config.Sharding.Enabled {
+		if db == nil {
+			return nil, fmt.Errorf("sharding requires a database")
+		}
+		leaseTTL := DefaultLeaseTTL
+		if config.Sharding.LeaseTTL != "" {
+			leaseTTL, err = time.ParseDuration(config.Sharding.LeaseTTL)
+			if err != nil || leaseTTL <= 0 {
+				return nil, fmt.Errorf("invalid sharding leaseTTL %q", config.Sharding.LeaseTTL)
+			}
+		}
+		indexer.Leases = NewLeaseManager(db, instanceID(), leaseTTL)
+	}
+
+	if config.Leader.Enabled {
+		if db == nil {
+			return nil, fmt.Errorf("leader election requires a database")
+		}
+		if indexer.Leases != nil {
+			return nil, fmt.Errorf("leader election and sharding cannot be enabled together")
+		}
+		lockID := config.Leader.LockID
+		if lockID == 0 {
+			lockID = DefaultLeaderLockID
+		}
+		indexer.Leader = NewLeaderElector(db, lockID, instanceID())
+
+		// Standbys keep clients and configuration loaded and only start consumers once elected
+		indexer.Wg.Add(1)
+		go indexer.startLeaderLoop(DefaultLeaderCheckInterval)
+		return indexer, nil
+	}
+
+	// Start event consumers for each network
+	for networkName := range indexer.Events {
+		if _, exists := indexer.Clients[networkName]; !exists {
+			log.Printf("No client found for network %s", networkName)
+			continue
+		}
+		indexer.Wg.Add(1)
+		if indexer.Leases != nil {
+			// Only run the network while this instance holds its lease
+			go indexer.startLeaseKeeper(networkName)
+			continue
+		}
+		go func() {
+			defer indexer.Wg.Done()
+			indexer.runNetwork(indexer.MainCtx, networkName)
+		}()
+	}
+
+	return indexer, nil
+}
+
+// configureEvents connects to the networks used by the contracts of config and indexes their
+// events as map[network][topic0][]*EventConfig.
+func (indexer *IndexerImpl) configureEvents(ctx context.Context, config *Config) error {
 	for contractName, contractConfig := range config.Contracts {
+		handlerTimeout := DefaultHandlerTimeout
+		if contractConfig.HandlerTimeout != "" {
+			timeout, err := time.ParseDuration(contractConfig.HandlerTimeout)
+			if err != nil || timeout <= 0 {
+				return fmt.Errorf("invalid handlerTimeout %q for contract %s", contractConfig.HandlerTimeout, contractName)
+			}
+			handlerTimeout = timeout
+		}
+
 		for networkName, networkConfig := range contractConfig.Networks {
 			// Check network configuration
 			netConfig, exists := config.Networks[networkName]
 			if !exists {
-				return nil, fmt.Errorf("network configuration not found: %s", networkName)
+				return fmt.Errorf("network configuration not found: %s", networkName)
 			}
 
 			// If the client for the network is not yet created, create and store it.
 			if _, exists := indexer.Clients[networkName]; !exists {
 				client, err := ethclient.NewClient(networkName, netConfig.RPCURL)
 				if err != nil {
-					return nil, fmt.Errorf("failed to connect to network %s: %w", networkName, err)
+					return fmt.Errorf("failed to connect to network %s: %w", networkName, err)
 				}
+				client.DebugRequests = netConfig.DebugRequests
 				indexer.Clients[networkName] = client
+
+				// Validate checked the duration, so an empty or invalid one falls back to the default
+				slowFetchLatency, _ := time.ParseDuration(netConfig.SlowFetchLatency)
+				indexer.Throttles[networkName] = NewFetchThrottle(netConfig.FetchConcurrency, slowFetchLatency)
+				indexer.RangeSizes[networkName] = NewRangeSizer(uint64(netConfig.MaxBlockRange))
 			}
 
 			contractAddress := common.HexToAddress(networkConfig.Address)
-			startBlockNumber := networkConfig.StartBlock
+			startBlockNumber, err := indexer.StartBlocks.Resolve(ctx, indexer.Clients[networkName], networkName, contractAddress, networkConfig.StartBlock)
+			if err != nil {
+				return fmt.Errorf("failed to resolve start block of contract %s on %s: %w", contractName, networkName, err)
+			}
 
 			if _, exists := indexer.Events[networkName]; !exists {
 				indexer.Events[networkName] = make(map[common.Hash][]*EventConfig)
 			}
 
 			for _, eventName := range contractConfig.Events {
-				handlerKey := fmt.Sprintf("%s:%s:%s", contractName, networkName, eventName)
-				eventHandler, hasHandler := handlers[handlerKey]
-				if !hasHandler {
-					eventHandler = nil
-				}
-
 				parsedABI, err := utils.LoadABI(contractConfig.ABI)
 				if err != nil {
-					return nil, fmt.Errorf("failed to load ABI for contract %s: %w", contractName, err)
+					return fmt.Errorf("failed to load ABI for contract %s: %w", contractName, err)
 				}
 
 				topic0, err := GetEventTopic0(parsedABI, eventName)
 				if err != nil {
-					return nil, fmt.Errorf("failed to get Topic0 for event %s: %w", eventName, err)
+					return fmt.Errorf("failed to get Topic0 for event %s: %w", eventName, err)
+				}
+
+				filters, err := compileFilters(parsedABI, eventName, contractConfig.Filters[eventName])
+				if err != nil {
+					return fmt.Errorf("failed to compile filters for contract %s: %w", contractName, err)
 				}
 
 				eventConfig := &EventConfig{
 					ContractName:       contractName,
+					NetworkName:        networkName,
 					ContractAddress:    contractAddress,
 					ContractABI:        parsedABI,
-					StartBlock:         big.NewInt(startBlockNumber),
+					StartBlock:         new(big.Int).SetUint64(startBlockNumber),
 					FinalityBlockCount: big.NewInt(netConfig.FinalityBlockCount),
 					EventName:          eventName,
-					Handler:            eventHandler,
+					HandlerKey:         handlerKey(contractName, networkName, eventName),
+					HandlerTimeout:     handlerTimeout,
+					Filters:            filters,
 				}
 
 				indexer.Events[networkName][topic0] = append(indexer.Events[networkName][topic0], eventConfig)
 			}
 		}
 	}
-
-	// Initialize handlerQueue and eventQueue for each network
-	for networkName := range indexer.Events {
-		indexer.HandlerQueues[networkName] = make(chan HandlerTask, MaxBatchHandlerSize)
-		indexer.EventQueues[networkName] = make(chan *EventsTask, MaxBatchEventSize)
-	}
-
-	// Start event consumers for each network
-	for networkName, eventConfigs := range indexer.Events {
-		client, exists := indexer.Clients[networkName]
-		if !exists {
-			log.Printf("No client found for network %s", networkName)
-			continue
-		}
-		indexer.Wg.Add(3)
-		logger.Infof("Starting event consumers for network %s with configurations %+v", networkName, eventConfigs)
-		go indexer.startBlockFetcher(networkName, client, eventConfigs)
-		go indexer.startLogProcessor(networkName)
-		go indexer.startTaskHandler(networkName)
-	}
-
-	return indexer, nil
+	return nil
 }
 
 // GetEventTopic0 calculates the topic[0] signature for the specified event.
@@ -249,140 +447,339 @@ func loadABI(abiName string) (abi.ABI, error) {
 	return parsedABI, nil
 }
 
+// runAllNetworks runs the event consumers of every network until ctx is cancelled.
+func (indexer *IndexerImpl) runAllNetworks(ctx context.Context) {
+	var wg sync.WaitGroup
+	for networkName := range indexer.Events {
+		if _, exists := indexer.Clients[networkName]; !exists {
+			log.Printf("No client found for network %s", networkName)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			indexer.runNetwork(ctx, networkName)
+		}()
+	}
+	wg.Wait()
+}
+
+// runNetwork runs the event consumers of a network until ctx is cancelled.
+func (indexer *IndexerImpl) runNetwork(ctx context.Context, networkName string) {
+	eventConfigs := indexer.Events[networkName]
+	logger.Infof("Starting event consumers for network %s with configurations %+v", networkName, eventConfigs)
+
+	var wg sync.WaitGroup
+	if indexer.Ranges != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			indexer.startGapRepairer(ctx, networkName, DefaultGapCheckInterval)
+		}()
+	}
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		indexer.startBlockFetcher(ctx, networkName, indexer.Clients[networkName], eventConfigs)
+	}()
+	go func() {
+		defer wg.Done()
+		indexer.startLogProcessor(ctx, networkName)
+	}()
+	go func() {
+		defer wg.Done()
+		indexer.startTaskHandler(ctx, networkName)
+	}()
+	wg.Wait()
+
+	logger.Infof("Stopped event consumers for network %s", networkName)
+}
+
 // startBlockFetcher starts the block fetching consumer.
-func (indexer *IndexerImpl) startBlockFetcher(networkName string, client *ethclient.Client, eventConfigs map[common.Hash][]*EventConfig) {
-	defer indexer.Wg.Done()
+func (indexer *IndexerImpl) startBlockFetcher(ctx context.Context, networkName string, client *ethclient.Client, eventConfigs map[common.Hash][]*EventConfig) {
 
 	// Get the minimum start block from the configuration
-	minStartBlock := big.NewInt(0)
+	minStartBlock := new(big.Int).SetUint64(fetchStartBlock(eventConfigs))
 	finalityBlockCount := big.NewInt(0)
 	for _, eventConfigList := range eventConfigs {
 		for _, config := range eventConfigList {
-			if minStartBlock.Cmp(config.StartBlock) < 0 {
-				minStartBlock.Set(config.StartBlock)
-			}
 			if finalityBlockCount.Cmp(config.FinalityBlockCount) < 0 {
 				finalityBlockCount.Set(config.FinalityBlockCount)
 			}
 		}
 	}
 
+	// Failures are retried with a growing wait, so a failing provider is not hammered
+	backoff := newFetchBackoff()
+
+	// Carry on after the blocks recorded by a previous run or by the replica that held the network
+	if indexer.Ranges != nil {
+		for {
+			resumeBlock, err := indexer.Ranges.Resume(ctx, networkName, minStartBlock.Uint64())
+			if err == nil {
+				if resumeBlock > minStartBlock.Uint64() {
+					logger.Infof("Resuming network %s at block %d", networkName, resumeBlock)
+					indexer.Stats.SetProcessed(networkName, resumeBlock-1)
+					minStartBlock.SetUint64(resumeBlock)
+				}
+				indexer.fetchSucceeded(networkName, backoff)
+				break
+			}
+			wait := indexer.fetchFailed(networkName, backoff, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}
+
 	// Main block fetching loop
 	for {
 		select {
-		case <-indexer.MainCtx.Done():
+		case <-ctx.Done():
 			return
 		default:
+			if indexer.Pauses.Paused(networkName, "") {
+				logger.Infof("Network %s is paused, waiting to resume at block %d", networkName, minStartBlock.Uint64())
+				if !indexer.Pauses.waitResumed(ctx, networkName, "") {
+					return
+				}
+				logger.Infof("Network %s resumed", networkName)
+			}
+
 			latestBlockHeader, err := client.HeaderByNumber(context.Background(), nil)
 			if err != nil {
-				log.Printf("Failed to get latest block for network %s: %v", networkName, err)
+				wait := indexer.fetchFailed(networkName, backoff, fmt.Errorf("failed to get latest block: %w", err))
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(wait):
+				}
 				continue
 			}
+			indexer.Stats.SetHead(networkName, latestBlockHeader.Number.Uint64())
 			latestBlockNumber := big.NewInt(0).Sub(latestBlockHeader.Number, finalityBlockCount).Uint64()
 
 			startBlock := minStartBlock.Uint64()
 			endBlock := latestBlockNumber
 
 			currentBlock := startBlock
+			var fetchErr error
 
-			// Process 37 blocks at a time
+			// Process ranges sized to the density of the logs
+			sizer := indexer.RangeSizes[networkName]
 			for currentBlock <= endBlock {
-				eg, ctx := errgroup.WithContext(indexer.MainCtx)
-
-				startTime := time.Now()
+				// Stop before the next range of a paused network; it resumes from currentBlock
+				if indexer.Pauses.Paused(networkName, "") {
+					break
+				}
 
-				processingEndBlock := currentBlock + 37
+				processingEndBlock := currentBlock + sizer.Size()
 				if processingEndBlock >= endBlock {
 					processingEndBlock = endBlock
 				}
 
-				logEntries, err := client.GetLogsByBlockNumber(context.Background(), big.NewInt(int64(currentBlock)), big.NewInt(int64(processingEndBlock)), getUniqueAddresses(eventConfigs))
+				eventsTask, err := indexer.fetchRange(ctx, networkName, client, eventConfigs, currentBlock, processingEndBlock)
 				if err != nil {
-					log.Printf("Failed to get logs for network %s from #%d to #%d: %v", networkName, currentBlock, processingEndBlock, err)
+					fetchErr = fmt.Errorf("failed to fetch blocks %d to %d: %w", currentBlock, processingEndBlock, err)
 					break
 				}
-
-				eventsTask := EventsTask{
-					Network: networkName,
-					Blocks:  make(map[string]*ethclient.GetBlockResponse),
-					Logs:    logEntries,
+				sizer.Observe(len(eventsTask.Logs))
+				if !indexer.dispatchRange(ctx, networkName, eventsTask, currentBlock, processingEndBlock) {
+					return
 				}
+				indexer.Stats.SetProcessed(networkName, processingEndBlock)
+				currentBlock = processingEndBlock + 1
+			}
 
-				for _, logEntry := range logEntries {
-					blockNumberKey := fmt.Sprintf("%d", logEntry.BlockNumber)
-					_, exists := eventsTask.Blocks[blockNumberKey]
-					if exists {
-						continue
-					}
+			// Continue after the last processed block; ranges that failed or were cut short by a pause are fetched again
+			minStartBlock.SetUint64(currentBlock)
 
-					eventsTask.mutex.Lock()
-					eventsTask.Blocks[blockNumberKey] = nil
-					eventsTask.mutex.Unlock()
+			// Wait before checking for new blocks again, or before retrying a failed range
+			wait := 20 * time.Second
+			if fetchErr != nil {
+				wait = indexer.fetchFailed(networkName, backoff, fetchErr)
+			} else {
+				indexer.fetchSucceeded(networkName, backoff)
+				logger.Infof("Processed blocks %d to %d... waiting for new blocks", startBlock, endBlock)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}
+}
 
-					eg.Go(func() error {
-						ctxLog, cancel := context.WithCancel(ctx)
-						defer cancel()
+// fetchFailed records a failure of the block fetcher of a network and returns the wait before
+// retrying. A fetcher failing for longer than FetchRetryWindow is logged as an error once.
+func (indexer *IndexerImpl) fetchFailed(networkName string, backoff *fetchBackoff, err error) time.Duration {
+	indexer.Stats.NetworkError(networkName, err)
+	wait, failures, outlasted := backoff.fail()
+	if outlasted {
+		logger.Errorf("Block fetcher of network %s has been failing since %s (%d attempts), retrying every %s: %v", networkName, backoff.since.Format(time.RFC3339), failures, FetchMaxBackoff, err)
+	} else {
+		logger.Warnf("Block fetcher of network %s failed (attempt %d), retrying in %s: %v", networkName, failures, wait.Round(time.Millisecond), err)
+	}
+	return wait
+}
 
-						blockResponse, err := client.GetBlockByHash(ctxLog, logEntry.BlockHash.Hex())
-						if err != nil {
-							log.Printf("Failed to get block by hash %s: %v", logEntry.BlockHash.Hex(), err)
-							return fmt.Errorf("failed to get block by hash %s: %w", logEntry.BlockHash.Hex(), err)
-						}
-						eventsTask.mutex.Lock()
-						eventsTask.Blocks[blockNumberKey] = blockResponse
-						eventsTask.mutex.Unlock()
-						return nil
-					})
-				}
+// fetchSucceeded ends the failures of the block fetcher of a network.
+func (indexer *IndexerImpl) fetchSucceeded(networkName string, backoff *fetchBackoff) {
+	if failures, since := backoff.succeed(); failures > 0 {
+		logger.Infof("Block fetcher of network %s recovered after %d failures in %s", networkName, failures, time.Since(since).Round(time.Second))
+		indexer.Stats.NetworkRecovered(networkName)
+	}
+}
 
-				// Wait for all goroutines to finish
-				if err := eg.Wait(); err != nil {
-					logger.Errorf("Error fetching blocks for network %s: %v", networkName, err)
-					break
-				}
+// fetchRange fetches the logs of a network between fromBlock and toBlock inclusive together with
+// the blocks that emitted them, and archives them when an archive is set. Ranges the provider
+// rejects as too large are split and narrow the network's RangeSizer. Blocks are fetched
+// concurrently up to the limit of the network's FetchThrottle.
+func (indexer *IndexerImpl) fetchRange(ctx context.Context, networkName string, client *ethclient.Client, eventConfigs map[common.Hash][]*EventConfig, fromBlock, toBlock uint64) (*EventsTask, error) {
+	throttle := indexer.Throttles[networkName]
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(throttle.Limit())
 
-				logger.Infof("Fetched %s blocks %d to %d (%s)", networkName, currentBlock, processingEndBlock, time.Since(startTime))
+	startTime := time.Now()
 
-				indexer.EventQueues[networkName] <- &eventsTask
-				currentBlock = processingEndBlock + 1
+	onSplit := func(from, to uint64, limit ethclient.RangeLimit) {
+		previous, size, _ := indexer.RangeSizes[networkName].Narrow(limit.MaxBlocks)
+		logger.Warnf("Provider rejected %s blocks %d to %d as too large, splitting it; ranges narrowed to %d blocks (was %d)", networkName, from, to, size+1, previous+1)
+	}
+	logEntries, err := client.GetLogsSplitting(context.Background(), fromBlock, toBlock, getUniqueAddresses(eventConfigs), onSplit)
+	indexer.Pipeline.Observe(networkName, StageFetchLogs, time.Since(startTime))
+	if err != nil {
+		log.Printf("Failed to get logs for network %s from #%d to #%d: %v", networkName, fromBlock, toBlock, err)
+		return nil, err
+	}
+
+	eventsTask := &EventsTask{
+		Network: networkName,
+		Blocks:  make(map[string]*ethclient.GetBlockResponse),
+		Logs:    logEntries,
+	}
+
+	for _, logEntry := range logEntries {
+		blockNumberKey := fmt.Sprintf("%d", logEntry.BlockNumber)
+		_, exists := eventsTask.Blocks[blockNumberKey]
+		if exists {
+			continue
+		}
+
+		eventsTask.mutex.Lock()
+		eventsTask.Blocks[blockNumberKey] = nil
+		eventsTask.mutex.Unlock()
+
+		eg.Go(func() error {
+			ctxLog, cancel := context.WithCancel(egCtx)
+			defer cancel()
+
+			requestTime := time.Now()
+			blockResponse, err := client.GetBlockByHash(ctxLog, logEntry.BlockHash.Hex())
+			throttle.Observe(time.Since(requestTime))
+			if err != nil {
+				log.Printf("Failed to get block by hash %s: %v", logEntry.BlockHash.Hex(), err)
+				return fmt.Errorf("failed to get block by hash %s: %w", logEntry.BlockHash.Hex(), err)
 			}
+			eventsTask.mutex.Lock()
+			eventsTask.Blocks[blockNumberKey] = blockResponse
+			eventsTask.mutex.Unlock()
+			return nil
+		})
+	}
 
-			logger.Infof("Processed blocks %d to %d... waiting for new blocks", startBlock, endBlock)
+	// Wait for all goroutines to finish
+	blocksTime := time.Now()
+	err = eg.Wait()
+	indexer.Pipeline.Observe(networkName, StageFetchBlocks, time.Since(blocksTime))
+	if previous, limit := throttle.Adjust(); limit != previous {
+		logger.Warnf("Block requests of network %s limited to %d concurrent (was %d) as RPC latency changed", networkName, limit, previous)
+	}
+	if err != nil {
+		logger.Errorf("Error fetching blocks for network %s: %v", networkName, err)
+		return nil, err
+	}
 
-			// Update the minimum start block to the last processed block
-			minStartBlock.SetUint64(endBlock + 1)
+	logger.Infof("Fetched %s blocks %d to %d (%s)", networkName, fromBlock, toBlock, time.Since(startTime))
 
-			// Wait before checking for new blocks again
-			time.Sleep(20 * time.Second)
+	if indexer.Archive != nil {
+		archiveTime := time.Now()
+		if err := indexer.Archive.Store(ctx, networkName, eventsTask.Blocks, eventsTask.Logs); err != nil {
+			logger.Errorf("Failed to archive %s blocks %d to %d: %v", networkName, fromBlock, toBlock, err)
 		}
+		indexer.Pipeline.Observe(networkName, StageWrite, time.Since(archiveTime))
+	}
+
+	return eventsTask, nil
+}
+
+// dispatchRange hands a fetched range to the log processor, which records it as processed once
+// it handed its logs to the handlers. It returns false if ctx is done before the log processor
+// takes the range.
+func (indexer *IndexerImpl) dispatchRange(ctx context.Context, networkName string, eventsTask *EventsTask, fromBlock, toBlock uint64) bool {
+	eventsTask.Range = &BlockRange{From: fromBlock, To: toBlock}
+	select {
+	case indexer.EventQueues[networkName] <- eventsTask:
+	case <-ctx.Done():
+		return false
 	}
+	return true
+}
+
+// recordRange records the range of a task as processed and returns the time it took. A range
+// whose logs are still buffered in memory is not recorded, so a restarted fetcher fetches it again.
+func (indexer *IndexerImpl) recordRange(ctx context.Context, networkName string, blocks *BlockRange) time.Duration {
+	if indexer.Ranges == nil || blocks == nil {
+		return 0
+	}
+
+	recordTime := time.Now()
+	if err := indexer.Ranges.Record(ctx, networkName, blocks.From, blocks.To); err != nil {
+		logger.Errorf("Failed to record %s blocks %d to %d: %v", networkName, blocks.From, blocks.To, err)
+	}
+	recorded := time.Since(recordTime)
+	indexer.Pipeline.Observe(networkName, StageWrite, recorded)
+	return recorded
 }
 
 // startLogProcessor starts the log processing consumer.
-func (indexer *IndexerImpl) startLogProcessor(networkName string) {
-	defer indexer.Wg.Done()
+func (indexer *IndexerImpl) startLogProcessor(ctx context.Context, networkName string) {
 	for {
 		select {
-		case <-indexer.MainCtx.Done():
-			// Close the eventQueue when the main context is canceled
-			close(indexer.EventQueues[networkName])
+		case <-ctx.Done():
+			// The eventQueue stays open so the network can be restarted after a lease handover
 			return
 		default:
 			for {
 				select {
-				case <-indexer.MainCtx.Done():
+				case <-ctx.Done():
 					return
 				case eventTask, ok := <-indexer.EventQueues[networkName]:
 					if !ok {
 						logger.Errorf("EventQueue for network %s is closed", networkName)
 						return
 					}
+					// Time spent writing jobs or waiting for the handler queue is recorded apart from decoding
+					decodeTime := time.Now()
+					var queued time.Duration
+
+					// Handlers see the events of a network in chain order
+					sortLogs(eventTask.Logs)
+
 					// Parse and filter events
 					for _, logEntry := range eventTask.Logs {
 						if len(logEntry.Topics) == 0 {
 							logger.Warnf("No topics found")
 							continue
 						}
+						// Logs reverted by a reorganization were never part of the chain
+						if logEntry.Removed {
+							logger.Warnf("Skipping removed log %s:%d on %s", logEntry.TxHash.Hex(), logEntry.Index, networkName)
+							continue
+						}
 						topic0 := logEntry.Topics[0]
 						eventConfigs, exists := indexer.Events[networkName][topic0]
 						if !exists {
@@ -390,8 +787,9 @@ func (indexer *IndexerImpl) startLogProcessor(networkName string) {
 						}
 
 						for _, eventConfig := range eventConfigs {
-							// Skip if handler is not set
-							if eventConfig.Handler == nil {
+							// Skip if no handler is registered
+							eventHandler := indexer.handlerFor(eventConfig)
+							if eventHandler == nil {
 								continue
 							}
 
@@ -412,6 +810,11 @@ func (indexer *IndexerImpl) startLogProcessor(networkName string) {
 								continue
 							}
 
+							// Skip events rejected by the argument filters
+							if !matchFilters(eventConfig.Filters, eventArgs) {
+								continue
+							}
+
 							blockResponse, exists := eventTask.Blocks[fmt.Sprintf("%d", logEntry.BlockNumber)]
 							if !exists {
 								logger.Errorf("Block %d not found", logEntry.BlockNumber)
@@ -428,59 +831,293 @@ func (indexer *IndexerImpl) startLogProcessor(networkName string) {
 								return ethclient.GetTransactionResponse{}
 							}
 
-							// Create event context
-							eventContext, cancel := context.WithCancel(indexer.MainCtx)
-							event := Event{
-								Block:           *blockResponse,
-								Transaction:     getTransaction(blockResponse.Result.Transactions, logEntry.TxHash.Hex()),
-								NetworkName:     eventTask.Network,
-								ContractName:    eventConfig.ContractName,
-								EventName:       eventConfig.EventName,
-								ContractAddress: eventConfig.ContractAddress,
-								Args:            eventArgs,
-								TransactionHash: logEntry.TxHash,
-								BlockHash:       logEntry.BlockHash,
-								Ctx:             eventContext,
-								Cancel:          cancel,
-							}
-
-							indexerService := &IndexerService{
-								Client:  indexer.Clients[eventTask.Network].Client,
-								Service: indexer.Service,
+							transaction := getTransaction(blockResponse.Result.Transactions, logEntry.TxHash.Hex())
+
+							if indexer.JobQueue != nil {
+								job := &HandlerJob{
+									Network:     eventTask.Network,
+									HandlerKey:  eventConfig.HandlerKey,
+									BlockNumber: int64(logEntry.BlockNumber),
+									Payload: JobPayload{
+										Log:         logEntry,
+										Block:       *blockResponse,
+										Transaction: transaction,
+									},
+								}
+								enqueueTime := time.Now()
+								if !indexer.enqueueJob(ctx, networkName, job) {
+									return
+								}
+								enqueued := time.Since(enqueueTime)
+								queued += enqueued
+								indexer.Pipeline.Observe(networkName, StageWrite, enqueued)
+								continue
 							}
 
 							// Add handling task to handlerQueue
-							indexer.HandlerQueues[networkName] <- HandlerTask{
-								Network:        eventTask.Network,
-								BlockNumber:    int64(logEntry.BlockNumber),
-								EventHandler:   eventConfig.Handler,
-								IndexerService: indexerService,
-								Event:          event,
+							task := indexer.newHandlerTask(eventTask.Network, eventConfig, eventHandler, logEntry, *blockResponse, transaction, eventArgs)
+							waitTime := time.Now()
+							select {
+							case indexer.HandlerQueues[networkName] <- task:
+							case <-ctx.Done():
+								return
 							}
+							waited := time.Since(waitTime)
+							queued += waited
+							indexer.Pipeline.Observe(networkName, StageQueueWait, waited)
 						}
 					}
+					// Every log of the range is enqueued or with the handlers
+					queued += indexer.recordRange(ctx, networkName, eventTask.Range)
+					indexer.Pipeline.Observe(networkName, StageDecode, time.Since(decodeTime)-queued)
 				}
 			}
 		}
 	}
 }
 
+// enqueueJob stores a handler job, retrying with a growing wait while the job queue fails, so
+// the event is not lost when Postgres is briefly unavailable. It returns false when ctx is done first.
+func (indexer *IndexerImpl) enqueueJob(ctx context.Context, networkName string, job *HandlerJob) bool {
+	backoff := newFetchBackoff()
+	for {
+		err := indexer.JobQueue.Enqueue(ctx, job)
+		if err == nil {
+			if failures, since := backoff.succeed(); failures > 0 {
+				logger.Infof("Job queue of network %s recovered after %d failures in %s", networkName, failures, time.Since(since).Round(time.Second))
+			}
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		wait, failures, outlasted := backoff.fail()
+		if outlasted {
+			logger.Errorf("Failed to enqueue job for %s tx %s since %s (%d attempts), retrying every %s: %v", job.HandlerKey, job.Payload.Log.TxHash.Hex(), backoff.since.Format(time.RFC3339), failures, FetchMaxBackoff, err)
+		} else {
+			logger.Warnf("Failed to enqueue job for %s tx %s (attempt %d), retrying in %s: %v", job.HandlerKey, job.Payload.Log.TxHash.Hex(), failures, wait.Round(time.Millisecond), err)
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(wait):
+		}
+	}
+}
+
+// newHandlerTask builds the handler task for a decoded log entry.
+// The event context is created by runHandler right before the handler runs,
+// so queued tasks do not hold timers or leak cancel funcs.
+func (indexer *IndexerImpl) newHandlerTask(networkName string, eventConfig *EventConfig, eventHandler EventHandler, logEntry types.Log, block ethclient.GetBlockResponse, transaction ethclient.GetTransactionResponse, eventArgs map[string]interface{}) HandlerTask {
+	return HandlerTask{
+		Network:      networkName,
+		BlockNumber:  int64(logEntry.BlockNumber),
+		HandlerKey:   eventConfig.HandlerKey,
+		Timeout:      eventConfig.HandlerTimeout,
+		EventHandler: eventHandler,
+		IndexerService: &IndexerService{
+			Client:  indexer.Clients[networkName].Client,
+			Service: indexer.Service,
+			Store:   indexer.Store,
+			DB:      indexer.DB,
+		},
+		Event: Event{
+			Block:           block,
+			Transaction:     transaction,
+			NetworkName:     networkName,
+			ContractName:    eventConfig.ContractName,
+			EventName:       eventConfig.EventName,
+			ContractAddress: eventConfig.ContractAddress,
+			Args:            eventArgs,
+			TransactionHash: logEntry.TxHash,
+			BlockHash:       logEntry.BlockHash,
+			TxIndex:         logEntry.TxIndex,
+			LogIndex:        logEntry.Index,
+			Removed:         logEntry.Removed,
+			Topics:          logEntry.Topics,
+			Data:            logEntry.Data,
+		},
+	}
+}
+
+// taskFromJob rebuilds a handler task from a persisted job.
+func (indexer *IndexerImpl) taskFromJob(job *HandlerJob) (HandlerTask, error) {
+	logEntry := job.Payload.Log
+	if len(logEntry.Topics) == 0 {
+		return HandlerTask{}, fmt.Errorf("job %d has no topics", job.ID)
+	}
+
+	for _, eventConfig := range indexer.Events[job.Network][logEntry.Topics[0]] {
+		if eventConfig.HandlerKey != job.HandlerKey {
+			continue
+		}
+		eventHandler := indexer.handlerFor(eventConfig)
+		if eventHandler == nil {
+			continue
+		}
+
+		eventArgs, err := eventConfig.extractEventArgs(logEntry)
+		if err != nil {
+			return HandlerTask{}, fmt.Errorf("failed to extract event args for job %d: %w", job.ID, err)
+		}
+
+		return indexer.newHandlerTask(job.Network, eventConfig, eventHandler, logEntry, job.Payload.Block, job.Payload.Transaction, eventArgs), nil
+	}
+
+	return HandlerTask{}, fmt.Errorf("no handler registered for %s", job.HandlerKey)
+}
+
+// startJobWorker drains the Postgres job queue of a network until ctx is cancelled.
+func (indexer *IndexerImpl) startJobWorker(ctx context.Context, networkName string) {
+	for {
+		if !indexer.Pauses.waitResumed(ctx, networkName, "") {
+			return
+		}
+
+		// Jobs of paused contracts stay pending while the other contracts keep going
+		processed, err := indexer.JobQueue.ProcessExcept(ctx, networkName, indexer.Pauses.PausedContracts(networkName), func(job *HandlerJob) error {
+			task, err := indexer.taskFromJob(job)
+			if err != nil {
+				return err
+			}
+			indexer.runHandler(ctx, task)
+			return nil
+		})
+		if err != nil {
+			logger.Errorf("Failed to process job for network %s: %v", networkName, err)
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(indexer.JobQueue.PollInterval):
+		}
+	}
+}
+
 // startTaskHandler starts the task handling consumer.
-func (indexer *IndexerImpl) startTaskHandler(networkName string) {
-	defer indexer.Wg.Done()
+func (indexer *IndexerImpl) startTaskHandler(ctx context.Context, networkName string) {
+	if indexer.JobQueue != nil {
+		indexer.startJobWorker(ctx, networkName)
+		return
+	}
 	for {
 		select {
-		case <-indexer.MainCtx.Done():
+		case <-ctx.Done():
 			return
 		case task, ok := <-indexer.HandlerQueues[networkName]:
 			if !ok {
 				return
 			}
-			task.EventHandler(task.IndexerService, task.Event)
+			// Events are handled in order, so later events of the network wait behind a paused contract
+			if !indexer.Pauses.waitResumed(ctx, networkName, task.Event.ContractName) {
+				return
+			}
+			indexer.runHandler(ctx, task)
 		}
 	}
 }
 
+// runHandler runs a single handler task under a deadline derived from ctx.
+// The event context is always cancelled once the handler returns, and every run is recorded in
+// the handler metrics and handler_runs with its outcome. A returned error, a timeout or a
+// recovered panic counts as a failure; tasks of a handler quarantined after repeated failures
+// are skipped.
+func (indexer *IndexerImpl) runHandler(ctx context.Context, task HandlerTask) {
+	if indexer.Quarantine.Quarantined(task.HandlerKey) {
+		indexer.Quarantine.Skip(task.HandlerKey)
+		return
+	}
+
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHandlerTimeout
+	}
+
+	eventCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	task.Event.Ctx = eventCtx
+	task.Event.Cancel = cancel
+
+	startTime := time.Now()
+	panicked, err := callHandler(task)
+
+	timedOut := errors.Is(eventCtx.Err(), context.DeadlineExceeded)
+	duration := time.Since(startTime)
+
+	status := RunOK
+	var failure error
+	switch {
+	case panicked:
+		status, failure = RunPanic, err
+	case timedOut:
+		logger.Warnf("Handler %s timed out after %s at block %d (tx %s)", task.HandlerKey, timeout, task.BlockNumber, task.Event.TransactionHash.Hex())
+		status, failure = RunTimeout, fmt.Errorf("handler %s timed out at block %d", task.HandlerKey, task.BlockNumber)
+	case err != nil:
+		logger.Errorf("Handler %s failed at block %d (tx %s): %v", task.HandlerKey, task.BlockNumber, task.Event.TransactionHash.Hex(), err)
+		status, failure = RunError, fmt.Errorf("handler %s failed at block %d: %w", task.HandlerKey, task.BlockNumber, err)
+	}
+
+	indexer.Metrics.Observe(task.HandlerKey, duration, status)
+	indexer.Pipeline.Observe(task.Network, StageHandle, duration)
+	indexer.Stats.Event(task.Network, task.Event.ContractName, uint64(task.BlockNumber))
+	run := HandlerRun{
+		HandlerKey:      task.HandlerKey,
+		Network:         task.Network,
+		BlockNumber:     uint64(task.BlockNumber),
+		TransactionHash: task.Event.TransactionHash.Hex(),
+		LogIndex:        task.Event.LogIndex,
+		Status:          status,
+		Duration:        duration,
+		StartedAt:       startTime,
+	}
+	if failure != nil {
+		run.Error = failure.Error()
+	}
+	indexer.Runs.Record(run)
+
+	if failure == nil {
+		indexer.Quarantine.Succeeded(task.HandlerKey)
+		return
+	}
+
+	indexer.Stats.ContractError(task.Network, task.Event.ContractName, failure)
+	if indexer.Quarantine.Failed(task.HandlerKey, failure) {
+		logger.Errorf("Quarantined handler %s after %d consecutive failures, its events are skipped until it is released: %v",
+			task.HandlerKey, indexer.Quarantine.threshold, failure)
+	}
+}
+
+// callHandler runs the handler of a task and returns its error. A recovered panic is returned
+// as an error with panicked set, so a panicking handler does not take down the indexer.
+func callHandler(task HandlerTask) (panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ErrorfWithStack("Handler %s panicked at block %d (tx %s): %v", task.HandlerKey, task.BlockNumber, task.Event.TransactionHash.Hex(), r)
+			panicked, err = true, fmt.Errorf("handler %s panicked at block %d: %v", task.HandlerKey, task.BlockNumber, r)
+		}
+	}()
+	return false, task.EventHandler(task.IndexerService, task.Event)
+}
+
+// sortLogs orders logs by block number, transaction index and log index.
+func sortLogs(logs []types.Log) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		if logs[i].TxIndex != logs[j].TxIndex {
+			return logs[i].TxIndex < logs[j].TxIndex
+		}
+		return logs[i].Index < logs[j].Index
+	})
+}
+
 // getUniqueAddresses extracts unique contract addresses from event configurations.
 func getUniqueAddresses(eventConfigs map[common.Hash][]*EventConfig) []common.Address {
 	addressMap := make(map[common.Address]struct{})
@@ -0,0 +1,186 @@
+package ethindexa
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/ethindexa/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRunHandler_Timeout tests that a handler exceeding its deadline sees a cancelled context and is counted as a timeout.
+func TestRunHandler_Timeout(t *testing.T) {
+	mainCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	indexer := &IndexerImpl{
+		Metrics: NewHandlerMetrics(),
+	}
+
+	var handlerErr error
+	indexer.runHandler(mainCtx, HandlerTask{
+		HandlerKey: "UniswapV2:mainnet:Swap",
+		Timeout:    10 * time.Millisecond,
+		EventHandler: func(idx *IndexerService, event Event) error {
+			<-event.Ctx.Done()
+			handlerErr = event.Ctx.Err()
+			return handlerErr
+		},
+	})
+
+	assert.ErrorIs(t, handlerErr, context.DeadlineExceeded)
+
+	stats := indexer.Metrics.Snapshot()["UniswapV2:mainnet:Swap"]
+	assert.Equal(t, uint64(1), stats.Runs)
+	assert.Equal(t, uint64(1), stats.Timeouts)
+}
+
+// TestRunHandler_CancelsContext tests that the event context is cancelled once the handler returns.
+func TestRunHandler_CancelsContext(t *testing.T) {
+	indexer := &IndexerImpl{
+		Metrics: NewHandlerMetrics(),
+	}
+
+	var eventCtx context.Context
+	indexer.runHandler(context.Background(), HandlerTask{
+		HandlerKey: "USDC:mainnet:Transfer",
+		EventHandler: func(idx *IndexerService, event Event) error {
+			eventCtx = event.Ctx
+			deadline, ok := event.Ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(DefaultHandlerTimeout), deadline, time.Second)
+			return nil
+		},
+	})
+
+	assert.ErrorIs(t, eventCtx.Err(), context.Canceled)
+
+	stats := indexer.Metrics.Snapshot()["USDC:mainnet:Transfer"]
+	assert.Equal(t, uint64(1), stats.Runs)
+	assert.Equal(t, uint64(0), stats.Timeouts)
+}
+
+// TestRunHandler_Quarantine tests that panics are recovered and counted, a handler failing repeatedly
+// is quarantined and its tasks skipped, and a success resets the failures.
+func TestRunHandler_Quarantine(t *testing.T) {
+	indexer := &IndexerImpl{
+		Metrics:    NewHandlerMetrics(),
+		Quarantine: NewHandlerQuarantine(3),
+	}
+
+	fail := true
+	calls := 0
+	task := HandlerTask{
+		HandlerKey: "UniswapV2:mainnet:Swap",
+		EventHandler: func(idx *IndexerService, event Event) error {
+			calls++
+			if fail {
+				panic("nil pair")
+			}
+			return nil
+		},
+	}
+
+	indexer.runHandler(context.Background(), task)
+	indexer.runHandler(context.Background(), task)
+	fail = false
+	indexer.runHandler(context.Background(), task)
+	assert.False(t, indexer.Quarantine.Quarantined(task.HandlerKey))
+
+	fail = true
+	for i := 0; i < 3; i++ {
+		indexer.runHandler(context.Background(), task)
+	}
+	assert.True(t, indexer.Quarantine.Quarantined(task.HandlerKey))
+
+	indexer.runHandler(context.Background(), task)
+	assert.Equal(t, 6, calls)
+
+	stats := indexer.Metrics.Snapshot()[task.HandlerKey]
+	assert.Equal(t, uint64(6), stats.Runs)
+	assert.Equal(t, uint64(5), stats.Panics)
+
+	quarantined := indexer.Quarantine.List()
+	if assert.Len(t, quarantined, 1) {
+		assert.Equal(t, 3, quarantined[0].Failures)
+		assert.Equal(t, uint64(1), quarantined[0].Skipped)
+		assert.Contains(t, quarantined[0].LastError, "panicked at block 0: nil pair")
+	}
+}
+
+// TestSortLogs tests that logs are ordered by block, transaction and log index.
+func TestSortLogs(t *testing.T) {
+	logs := []types.Log{
+		{BlockNumber: 11, TxIndex: 0, Index: 0},
+		{BlockNumber: 10, TxIndex: 2, Index: 9},
+		{BlockNumber: 10, TxIndex: 1, Index: 5},
+		{BlockNumber: 10, TxIndex: 1, Index: 4},
+	}
+
+	sortLogs(logs)
+
+	assert.Equal(t, []types.Log{
+		{BlockNumber: 10, TxIndex: 1, Index: 4},
+		{BlockNumber: 10, TxIndex: 1, Index: 5},
+		{BlockNumber: 10, TxIndex: 2, Index: 9},
+		{BlockNumber: 11, TxIndex: 0, Index: 0},
+	}, logs)
+}
+
+// TestStartLogProcessor_SkipsRemovedLogs tests that a log reverted by a reorganization never reaches the handler queue.
+func TestStartLogProcessor_SkipsRemovedLogs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	parsedABI, err := utils.LoadABI("erc20_usdc")
+	assert.NoError(t, err)
+	transfer := parsedABI.Events["Transfer"]
+	value, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(1))
+	assert.NoError(t, err)
+	topics := []common.Hash{transfer.ID, common.BytesToHash(traceFrom.Bytes()), common.BytesToHash(traceTo.Bytes())}
+
+	client, err := ethclient.NewClient("mainnet", "http://127.0.0.1:1")
+	assert.NoError(t, err)
+	indexer := &IndexerImpl{
+		Clients: map[string]*ethclient.Client{"mainnet": client},
+		Events: map[string]map[common.Hash][]*EventConfig{
+			"mainnet": {transfer.ID: {{
+				ContractName: "USDC", NetworkName: "mainnet", ContractAddress: traceUSDC, ContractABI: parsedABI, EventName: "Transfer",
+				HandlerKey: "USDC:mainnet:Transfer", StartBlock: big.NewInt(1),
+				Handler: func(idx *IndexerService, event Event) error { return nil },
+			}}},
+		},
+		EventQueues:   map[string]chan *EventsTask{"mainnet": make(chan *EventsTask, 1)},
+		HandlerQueues: map[string]chan HandlerTask{"mainnet": make(chan HandlerTask, 2)},
+		Pipeline:      NewPipelineMetrics(),
+	}
+	go indexer.startLogProcessor(ctx, "mainnet")
+
+	indexer.EventQueues["mainnet"] <- &EventsTask{
+		Network: "mainnet",
+		Logs: []types.Log{
+			{Address: traceUSDC, Topics: topics, Data: value, BlockNumber: 100, TxHash: common.HexToHash("0x01"), Index: 1, Removed: true},
+			{Address: traceUSDC, Topics: topics, Data: value, BlockNumber: 100, TxHash: common.HexToHash("0x02"), Index: 2},
+		},
+		Blocks: map[string]*ethclient.GetBlockResponse{"100": {}},
+	}
+
+	select {
+	case task := <-indexer.HandlerQueues["mainnet"]:
+		assert.Equal(t, uint(2), task.Event.LogIndex)
+		assert.False(t, task.Event.Removed)
+	case <-time.After(time.Second):
+		t.Fatal("the log was not queued")
+	}
+	select {
+	case task := <-indexer.HandlerQueues["mainnet"]:
+		t.Fatalf("the removed log %d was queued", task.Event.LogIndex)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,254 @@
+// Package ethindexatest provides fakes for unit testing event handlers without an Ethereum node.
+package ethindexatest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"hw/internal/service"
+	"hw/pkg/ethindexa"
+	myclient "hw/pkg/ethindexa/ethclient"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ContractCall records a single ReadContract call made by a handler.
+type ContractCall struct {
+	Address  common.Address
+	Function string
+	Block    *big.Int
+	Params   []interface{}
+}
+
+// FakeChain is an in-memory ethindexa.ChainReader serving canned blocks, transactions, receipts and contract reads.
+type FakeChain struct {
+	mutex        sync.Mutex
+	blocks       map[common.Hash]*types.Block
+	transactions map[common.Hash]ethindexa.TransactionInfo
+	receipts     map[common.Hash]ethindexa.ReceiptInfo
+	stubs        map[string]func(params ...interface{}) (interface{}, error)
+	calls        []ContractCall
+}
+
+// NewFakeChain creates an empty fake chain.
+func NewFakeChain() *FakeChain {
+	return &FakeChain{
+		blocks:       make(map[common.Hash]*types.Block),
+		transactions: make(map[common.Hash]ethindexa.TransactionInfo),
+		receipts:     make(map[common.Hash]ethindexa.ReceiptInfo),
+		stubs:        make(map[string]func(params ...interface{}) (interface{}, error)),
+	}
+}
+
+// NewIndexerService returns an IndexerService whose chain reads are served by a new FakeChain.
+func NewIndexerService(svc service.Service) (*ethindexa.IndexerService, *FakeChain) {
+	chain := NewFakeChain()
+	return &ethindexa.IndexerService{Service: svc, Chain: chain, Store: ethindexa.NewMemoryEntityStore()}, chain
+}
+
+// AddBlock makes a block available by its hash.
+func (c *FakeChain) AddBlock(block *types.Block) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.blocks[block.Hash()] = block
+}
+
+// AddTransaction makes a transaction available by its hash.
+func (c *FakeChain) AddTransaction(tx ethindexa.TransactionInfo) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.transactions[tx.TxHash] = tx
+}
+
+// AddReceipt makes the receipt of a transaction available by its hash.
+func (c *FakeChain) AddReceipt(receipt ethindexa.ReceiptInfo) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.receipts[receipt.TxHash] = receipt
+}
+
+// StubCall returns result and err for every call of function on the contract.
+func (c *FakeChain) StubCall(contractAddress common.Address, function string, result interface{}, err error) {
+	c.StubCallFunc(contractAddress, function, func(params ...interface{}) (interface{}, error) {
+		return result, err
+	})
+}
+
+// StubCallFunc serves calls of function on the contract with fn, which receives the call parameters.
+func (c *FakeChain) StubCallFunc(contractAddress common.Address, function string, fn func(params ...interface{}) (interface{}, error)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stubs[stubKey(contractAddress, function)] = fn
+}
+
+// Calls returns the contract reads made so far.
+func (c *FakeChain) Calls() []ContractCall {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]ContractCall(nil), c.calls...)
+}
+
+// ReadContract serves a stubbed contract read; unstubbed calls return an error.
+func (c *FakeChain) ReadContract(contractAddress common.Address, contractABI abi.ABI, startBlock *big.Int, functionName string, functionParams ...interface{}) (interface{}, error) {
+	c.mutex.Lock()
+	c.calls = append(c.calls, ContractCall{Address: contractAddress, Function: functionName, Block: startBlock, Params: functionParams})
+	stub, exists := c.stubs[stubKey(contractAddress, functionName)]
+	c.mutex.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no stub for %s on %s", functionName, contractAddress.Hex())
+	}
+	return stub(functionParams...)
+}
+
+// GetBlockByHash returns a block added with AddBlock.
+func (c *FakeChain) GetBlockByHash(blockHash common.Hash) (*types.Block, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	block, exists := c.blocks[blockHash]
+	if !exists {
+		return nil, fmt.Errorf("block %s not found", blockHash.Hex())
+	}
+	return block, nil
+}
+
+// GetTransactionByHash returns a transaction added with AddTransaction.
+func (c *FakeChain) GetTransactionByHash(txHash common.Hash) (ethindexa.TransactionInfo, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	tx, exists := c.transactions[txHash]
+	if !exists {
+		return ethindexa.TransactionInfo{}, fmt.Errorf("transaction %s not found", txHash.Hex())
+	}
+	return tx, nil
+}
+
+// GetTransactionReceipt returns a receipt added with AddReceipt.
+func (c *FakeChain) GetTransactionReceipt(txHash common.Hash) (ethindexa.ReceiptInfo, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	receipt, exists := c.receipts[txHash]
+	if !exists {
+		return ethindexa.ReceiptInfo{}, fmt.Errorf("receipt of %s not found", txHash.Hex())
+	}
+	return receipt, nil
+}
+
+func stubKey(contractAddress common.Address, function string) string {
+	return strings.ToLower(contractAddress.Hex()) + ":" + function
+}
+
+// EventBuilder builds ethindexa.Event values for handler tests.
+type EventBuilder struct {
+	event ethindexa.Event
+}
+
+// NewEvent starts building an event of a contract on a network. The event context is context.Background().
+func NewEvent(contractName, networkName, eventName string) *EventBuilder {
+	return &EventBuilder{
+		event: ethindexa.Event{
+			ContractName: contractName,
+			NetworkName:  networkName,
+			EventName:    eventName,
+			Args:         make(map[string]interface{}),
+			Ctx:          context.Background(),
+		},
+	}
+}
+
+// Arg sets a decoded event argument.
+func (b *EventBuilder) Arg(name string, value interface{}) *EventBuilder {
+	b.event.Args[name] = value
+	return b
+}
+
+// ContractAddress sets the address of the emitting contract.
+func (b *EventBuilder) ContractAddress(address string) *EventBuilder {
+	b.event.ContractAddress = common.HexToAddress(address)
+	return b
+}
+
+// TxHash sets the transaction hash on the event and its transaction.
+func (b *EventBuilder) TxHash(hash string) *EventBuilder {
+	b.event.TransactionHash = common.HexToHash(hash)
+	b.event.Transaction.Hash = b.event.TransactionHash.Hex()
+	return b
+}
+
+// From sets the sender of the transaction.
+func (b *EventBuilder) From(address string) *EventBuilder {
+	b.event.Transaction.From = address
+	return b
+}
+
+// To sets the recipient of the transaction.
+func (b *EventBuilder) To(address string) *EventBuilder {
+	b.event.Transaction.To = address
+	return b
+}
+
+// Block sets the number and timestamp of the block containing the event.
+func (b *EventBuilder) Block(number, timestamp int64) *EventBuilder {
+	b.event.Block.Result.Number = hexutilInt(number)
+	b.event.Block.Result.Timestamp = hexutilInt(timestamp)
+	b.event.Transaction.BlockNumber = b.event.Block.Result.Number
+	return b
+}
+
+// BlockHash sets the hash of the block containing the event.
+func (b *EventBuilder) BlockHash(hash string) *EventBuilder {
+	b.event.BlockHash = common.HexToHash(hash)
+	b.event.Block.Result.Hash = b.event.BlockHash.Hex()
+	b.event.Transaction.BlockHash = b.event.Block.Result.Hash
+	return b
+}
+
+// Index sets the position of the transaction and of the log in the block.
+func (b *EventBuilder) Index(txIndex, logIndex uint) *EventBuilder {
+	b.event.TxIndex = txIndex
+	b.event.LogIndex = logIndex
+	return b
+}
+
+// Removed marks the log as reverted by a chain reorganization.
+func (b *EventBuilder) Removed() *EventBuilder {
+	b.event.Removed = true
+	return b
+}
+
+// Raw sets the raw topics and data of the log.
+func (b *EventBuilder) Raw(topics []common.Hash, data []byte) *EventBuilder {
+	b.event.Topics = topics
+	b.event.Data = data
+	return b
+}
+
+// Context sets the event context.
+func (b *EventBuilder) Context(ctx context.Context) *EventBuilder {
+	b.event.Ctx = ctx
+	return b
+}
+
+// Build returns the event. The builder can keep being used to derive further events.
+func (b *EventBuilder) Build() ethindexa.Event {
+	event := b.event
+	event.Args = make(map[string]interface{}, len(b.event.Args))
+	for name, value := range b.event.Args {
+		event.Args[name] = value
+	}
+	event.Block.Result.Transactions = []myclient.GetTransactionResponse{event.Transaction}
+	return event
+}
+
+// hexutilInt encodes an integer as a 0x-prefixed quantity like the JSON-RPC API does.
+func hexutilInt(n int64) string {
+	return fmt.Sprintf("0x%x", n)
+}
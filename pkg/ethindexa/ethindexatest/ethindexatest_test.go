@@ -0,0 +1,68 @@
+package ethindexatest
+
+import (
+	"math/big"
+	"testing"
+
+	"hw/pkg/ethindexa"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFakeChain_ReadContract tests that stubbed reads are served through the IndexerService and recorded.
+func TestFakeChain_ReadContract(t *testing.T) {
+	idx, chain := NewIndexerService(nil)
+	feed := common.HexToAddress("0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419")
+	chain.StubCall(feed, "latestAnswer", []interface{}{big.NewInt(250000000000)}, nil)
+
+	result, err := idx.ReadContract(feed, abi.ABI{}, big.NewInt(100), "latestAnswer")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(250000000000)}, result)
+
+	_, err = idx.ReadContract(feed, abi.ABI{}, big.NewInt(100), "decimals")
+	assert.Error(t, err)
+
+	calls := chain.Calls()
+	assert.Len(t, calls, 2)
+	assert.Equal(t, "latestAnswer", calls[0].Function)
+	assert.Equal(t, big.NewInt(100), calls[0].Block)
+}
+
+// TestFakeChain_Transaction tests that canned transactions are returned by hash.
+func TestFakeChain_Transaction(t *testing.T) {
+	idx, chain := NewIndexerService(nil)
+	hash := common.HexToHash("0xabc")
+	chain.AddTransaction(ethindexa.TransactionInfo{TxHash: hash, Value: big.NewInt(1)})
+
+	tx, err := idx.GetTransactionByHash(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), tx.Value)
+
+	_, err = idx.GetTransactionByHash(common.HexToHash("0xdef"))
+	assert.Error(t, err)
+}
+
+// TestEventBuilder tests that built events carry the block, transaction and arguments.
+func TestEventBuilder(t *testing.T) {
+	builder := NewEvent("USDC", "base", "Transfer").
+		TxHash("0x01").
+		From("0xsender").
+		Block(42, 1700000000).
+		Index(3, 7).
+		Arg("value", big.NewInt(5))
+
+	event := builder.Build()
+	builder.Arg("value", big.NewInt(6))
+
+	assert.Equal(t, int64(42), event.Block.Number().Int64())
+	assert.Equal(t, int64(1700000000), event.Block.Time())
+	assert.Equal(t, "0xsender", event.Transaction.From)
+	assert.Equal(t, uint(3), event.TxIndex)
+	assert.Equal(t, uint(7), event.LogIndex)
+	assert.Equal(t, "base:0x0000000000000000000000000000000000000000000000000000000000000001:7", event.LogKey())
+	assert.False(t, event.Removed)
+	assert.Equal(t, big.NewInt(5), event.Args["value"])
+	assert.NotNil(t, event.Ctx)
+}
@@ -0,0 +1,222 @@
+package ethindexa
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Argument filter operators.
+const (
+	FilterEq    = "eq"
+	FilterNe    = "ne"
+	FilterGt    = "gt"
+	FilterGte   = "gte"
+	FilterLt    = "lt"
+	FilterLte   = "lte"
+	FilterIn    = "in"
+	FilterNotIn = "not_in"
+)
+
+// ArgFilterConfig defines a condition on a decoded event argument, e.g.
+// {"arg": "value", "op": "gt", "value": "1000000000"} or {"arg": "to", "op": "in", "values": ["0x..."]}.
+type ArgFilterConfig struct {
+	Arg    string   `json:"arg"`
+	Op     string   `json:"op"`
+	Value  string   `json:"value"`
+	Values []string `json:"values"` // used by "in" and "not_in"
+}
+
+// ArgFilter is a compiled argument condition. Numeric arguments are compared as big integers,
+// everything else as case-insensitive strings so addresses match regardless of checksum casing.
+type ArgFilter struct {
+	Arg     string
+	Op      string
+	numeric bool
+	numbers []*big.Int
+	strings map[string]struct{}
+}
+
+// compileFilters validates the filters of an event against its ABI and compiles them.
+func compileFilters(contractABI abi.ABI, eventName string, configs []ArgFilterConfig) ([]ArgFilter, error) {
+	event, exists := contractABI.Events[eventName]
+	if !exists {
+		return nil, fmt.Errorf("event %s not found in ABI", eventName)
+	}
+
+	filters := make([]ArgFilter, 0, len(configs))
+	for _, config := range configs {
+		var input *abi.Argument
+		for i := range event.Inputs {
+			if event.Inputs[i].Name == config.Arg {
+				input = &event.Inputs[i]
+				break
+			}
+		}
+		if input == nil {
+			return nil, fmt.Errorf("event %s has no argument %q", eventName, config.Arg)
+		}
+
+		filter, err := compileFilter(config, input.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter on %s.%s: %w", eventName, config.Arg, err)
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// compileFilter parses the operands of a single filter according to the argument type.
+func compileFilter(config ArgFilterConfig, argType abi.Type) (ArgFilter, error) {
+	filter := ArgFilter{
+		Arg:     config.Arg,
+		Op:      config.Op,
+		numeric: argType.T == abi.IntTy || argType.T == abi.UintTy,
+	}
+
+	var operands []string
+	switch config.Op {
+	case FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte:
+		operands = []string{config.Value}
+	case FilterIn, FilterNotIn:
+		if len(config.Values) == 0 {
+			return ArgFilter{}, fmt.Errorf("operator %s requires values", config.Op)
+		}
+		operands = config.Values
+	default:
+		return ArgFilter{}, fmt.Errorf("unknown operator %q", config.Op)
+	}
+
+	switch config.Op {
+	case FilterGt, FilterGte, FilterLt, FilterLte:
+		if !filter.numeric {
+			return ArgFilter{}, fmt.Errorf("operator %s requires a numeric argument, got %s", config.Op, argType.String())
+		}
+	}
+
+	if filter.numeric {
+		for _, operand := range operands {
+			number, ok := new(big.Int).SetString(operand, 0)
+			if !ok {
+				return ArgFilter{}, fmt.Errorf("invalid number %q", operand)
+			}
+			filter.numbers = append(filter.numbers, number)
+		}
+		return filter, nil
+	}
+
+	filter.strings = make(map[string]struct{}, len(operands))
+	for _, operand := range operands {
+		if argType.T == abi.AddressTy && !common.IsHexAddress(operand) {
+			return ArgFilter{}, fmt.Errorf("invalid address %q", operand)
+		}
+		filter.strings[strings.ToLower(operand)] = struct{}{}
+	}
+
+	return filter, nil
+}
+
+// Match reports whether the decoded event arguments satisfy the filter.
+// A missing or unconvertible argument never matches.
+func (f ArgFilter) Match(args map[string]interface{}) bool {
+	value, exists := args[f.Arg]
+	if !exists {
+		return false
+	}
+
+	if f.numeric {
+		number, ok := toBigInt(value)
+		if !ok {
+			return false
+		}
+		switch f.Op {
+		case FilterEq:
+			return number.Cmp(f.numbers[0]) == 0
+		case FilterNe:
+			return number.Cmp(f.numbers[0]) != 0
+		case FilterGt:
+			return number.Cmp(f.numbers[0]) > 0
+		case FilterGte:
+			return number.Cmp(f.numbers[0]) >= 0
+		case FilterLt:
+			return number.Cmp(f.numbers[0]) < 0
+		case FilterLte:
+			return number.Cmp(f.numbers[0]) <= 0
+		case FilterIn, FilterNotIn:
+			found := false
+			for _, candidate := range f.numbers {
+				if number.Cmp(candidate) == 0 {
+					found = true
+					break
+				}
+			}
+			return found == (f.Op == FilterIn)
+		}
+		return false
+	}
+
+	_, found := f.strings[strings.ToLower(argString(value))]
+	switch f.Op {
+	case FilterEq, FilterIn:
+		return found
+	case FilterNe, FilterNotIn:
+		return !found
+	}
+	return false
+}
+
+// matchFilters reports whether the arguments satisfy every filter.
+func matchFilters(filters []ArgFilter, args map[string]interface{}) bool {
+	for _, filter := range filters {
+		if !filter.Match(args) {
+			return false
+		}
+	}
+	return true
+}
+
+// toBigInt converts a decoded integer argument to a big integer.
+func toBigInt(value interface{}) (*big.Int, bool) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, v != nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), true
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), true
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), true
+	case uint64:
+		return new(big.Int).SetUint64(v), true
+	case int8:
+		return big.NewInt(int64(v)), true
+	case int16:
+		return big.NewInt(int64(v)), true
+	case int32:
+		return big.NewInt(int64(v)), true
+	case int64:
+		return big.NewInt(v), true
+	default:
+		return nil, false
+	}
+}
+
+// argString renders a decoded non-numeric argument for comparison.
+func argString(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case [32]byte:
+		return common.Hash(v).Hex()
+	case []byte:
+		return common.Bytes2Hex(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,60 @@
+package ethindexa
+
+import (
+	"math/big"
+	"testing"
+
+	"hw/pkg/ethindexa/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompileFilters_Match tests numeric and address filters against decoded Transfer arguments.
+func TestCompileFilters_Match(t *testing.T) {
+	parsedABI, err := utils.LoadABI("erc20_usdc")
+	assert.NoError(t, err)
+
+	filters, err := compileFilters(parsedABI, "Transfer", []ArgFilterConfig{
+		{Arg: "value", Op: FilterGt, Value: "1000000"},
+		{Arg: "to", Op: FilterIn, Values: []string{"0xB4E16D0168E52D35CACD2C6185B44281EC28C9DC"}},
+	})
+	assert.NoError(t, err)
+
+	pool := common.HexToAddress("0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	assert.True(t, matchFilters(filters, map[string]interface{}{"to": pool, "value": big.NewInt(2000000)}))
+	assert.False(t, matchFilters(filters, map[string]interface{}{"to": pool, "value": big.NewInt(1000000)}))
+	assert.False(t, matchFilters(filters, map[string]interface{}{"to": other, "value": big.NewInt(2000000)}))
+	assert.False(t, matchFilters(filters, map[string]interface{}{"value": big.NewInt(2000000)}))
+}
+
+// TestCompileFilters_NoFilters tests that events without filters always match.
+func TestCompileFilters_NoFilters(t *testing.T) {
+	parsedABI, err := utils.LoadABI("erc20_usdc")
+	assert.NoError(t, err)
+
+	filters, err := compileFilters(parsedABI, "Transfer", nil)
+	assert.NoError(t, err)
+	assert.True(t, matchFilters(filters, map[string]interface{}{}))
+}
+
+// TestCompileFilters_Invalid tests that filters are validated against the event ABI at load time.
+func TestCompileFilters_Invalid(t *testing.T) {
+	parsedABI, err := utils.LoadABI("erc20_usdc")
+	assert.NoError(t, err)
+
+	tests := []ArgFilterConfig{
+		{Arg: "amount", Op: FilterEq, Value: "1"},
+		{Arg: "value", Op: "between", Value: "1"},
+		{Arg: "value", Op: FilterGt, Value: "lots"},
+		{Arg: "to", Op: FilterGt, Value: "1"},
+		{Arg: "to", Op: FilterEq, Value: "not-an-address"},
+		{Arg: "to", Op: FilterIn},
+	}
+	for _, config := range tests {
+		_, err := compileFilters(parsedABI, "Transfer", []ArgFilterConfig{config})
+		assert.Error(t, err, "filter %+v", config)
+	}
+}
@@ -0,0 +1,206 @@
+package ethindexa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultGapCheckInterval is how often the processed block ranges of a network are checked for gaps.
+var DefaultGapCheckInterval = time.Minute
+
+// BlockRange is a range of blocks from From to To inclusive.
+type BlockRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// RangeTracker records the block ranges whose logs the log processor handed to the handlers in indexer_ranges.
+// Overlapping and adjacent ranges are merged, so a network indexed without gaps has a
+// single row and every further row starts after a gap.
+type RangeTracker struct {
+	db pg.PgxPool
+}
+
+// NewRangeTracker creates a RangeTracker on top of the given pool.
+func NewRangeTracker(db pg.PgxPool) *RangeTracker {
+	return &RangeTracker{db: db}
+}
+
+// Record marks the blocks of a network between fromBlock and toBlock inclusive as processed.
+func (r *RangeTracker) Record(ctx context.Context, network string, fromBlock, toBlock uint64) error {
+	const query = `
+		WITH merged AS (
+			DELETE FROM indexer_ranges
+			WHERE network = $1 AND from_block <= $3 + 1 AND to_block + 1 >= $2
+			RETURNING from_block, to_block
+		)
+		INSERT INTO indexer_ranges (network, from_block, to_block)
+		SELECT $1, LEAST($2, MIN(from_block)), GREATEST($3, MAX(to_block)) FROM merged
+		ON CONFLICT (network, from_block) DO UPDATE SET
+			to_block = GREATEST(indexer_ranges.to_block, EXCLUDED.to_block)
+	`
+
+	if _, err := r.db.Exec(ctx, query, network, int64(fromBlock), int64(toBlock)); err != nil {
+		return fmt.Errorf("failed to record %s blocks %d to %d: %w", network, fromBlock, toBlock, err)
+	}
+	return nil
+}
+
+// Resume returns the block after the recorded range of a network covering fromBlock, or fromBlock
+// when none does, so a restarted fetcher carries on where the contiguous history ends. Ranges past
+// a gap are left to the gap repairer.
+func (r *RangeTracker) Resume(ctx context.Context, network string, fromBlock uint64) (uint64, error) {
+	const query = `
+		SELECT to_block FROM indexer_ranges
+		WHERE network = $1 AND from_block <= $2 AND to_block >= $2
+	`
+
+	var to int64
+	if err := r.db.QueryRow(ctx, query, network, int64(fromBlock)).Scan(&to); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fromBlock, nil
+		}
+		return 0, fmt.Errorf("failed to load %s block ranges: %w", network, err)
+	}
+	return uint64(to) + 1, nil
+}
+
+// Gaps returns the blocks of a network between fromBlock and toBlock inclusive that were never recorded.
+func (r *RangeTracker) Gaps(ctx context.Context, network string, fromBlock, toBlock uint64) ([]BlockRange, error) {
+	const query = `
+		SELECT from_block, to_block FROM indexer_ranges
+		WHERE network = $1 AND to_block >= $2 AND from_block <= $3
+		ORDER BY from_block
+	`
+
+	rows, err := r.db.Query(ctx, query, network, int64(fromBlock), int64(toBlock))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s block ranges: %w", network, err)
+	}
+	defer rows.Close()
+
+	var ranges []BlockRange
+	for rows.Next() {
+		var from, to int64
+		if err := rows.Scan(&from, &to); err != nil {
+			return nil, fmt.Errorf("failed to scan block range: %w", err)
+		}
+		ranges = append(ranges, BlockRange{From: uint64(from), To: uint64(to)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load %s block ranges: %w", network, err)
+	}
+
+	return findGaps(ranges, fromBlock, toBlock), nil
+}
+
+// findGaps returns the blocks between fromBlock and toBlock inclusive not covered by ranges,
+// which must be ordered by From.
+func findGaps(ranges []BlockRange, fromBlock, toBlock uint64) []BlockRange {
+	var gaps []BlockRange
+	next := fromBlock
+	for _, r := range ranges {
+		if next > toBlock {
+			return gaps
+		}
+		if r.From > next {
+			gaps = append(gaps, BlockRange{From: next, To: min(r.From-1, toBlock)})
+		}
+		if r.To >= next {
+			next = r.To + 1
+		}
+	}
+	if next <= toBlock {
+		gaps = append(gaps, BlockRange{From: next, To: toBlock})
+	}
+	return gaps
+}
+
+// fetchStartBlock returns the block the fetcher of a network starts from.
+func fetchStartBlock(eventConfigs map[common.Hash][]*EventConfig) uint64 {
+	var startBlock uint64
+	for _, eventConfigList := range eventConfigs {
+		for _, config := range eventConfigList {
+			if config.StartBlock.Uint64() > startBlock {
+				startBlock = config.StartBlock.Uint64()
+			}
+		}
+	}
+	return startBlock
+}
+
+// startGapRepairer periodically refetches the blocks of a network that were skipped below the
+// last block handed to the log processor, until ctx is cancelled.
+func (indexer *IndexerImpl) startGapRepairer(ctx context.Context, networkName string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if indexer.Pauses.Paused(networkName, "") {
+				continue
+			}
+			if err := indexer.repairGaps(ctx, networkName); err != nil {
+				logger.Errorf("Failed to repair gaps of network %s: %v", networkName, err)
+			}
+		}
+	}
+}
+
+// repairGaps refetches the gaps of a network up to the last block its fetcher handed to the
+// log processor and feeds them through the log processor. Blocks ahead of the fetcher are
+// left to it, so a restarted fetcher does not race the repair.
+func (indexer *IndexerImpl) repairGaps(ctx context.Context, networkName string) error {
+	processed := indexer.Stats.Processed(networkName)
+	if processed == 0 {
+		return nil
+	}
+
+	eventConfigs := indexer.Events[networkName]
+	gaps, err := indexer.Ranges.Gaps(ctx, networkName, fetchStartBlock(eventConfigs), processed)
+	if err != nil {
+		return err
+	}
+	// The blocks after the last recorded range are still queued for the log processor
+	if n := len(gaps); n > 0 && gaps[n-1].To == processed {
+		gaps = gaps[:n-1]
+	}
+
+	client := indexer.Clients[networkName]
+	for _, gap := range gaps {
+		logger.Warnf("Repairing gap of network %s from #%d to #%d", networkName, gap.From, gap.To)
+		if err := indexer.refetchRange(ctx, networkName, client, eventConfigs, gap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refetchRange fetches and dispatches a range in BlockRangeSize chunks.
+func (indexer *IndexerImpl) refetchRange(ctx context.Context, networkName string, client *ethclient.Client, eventConfigs map[common.Hash][]*EventConfig, blocks BlockRange) error {
+	for currentBlock := blocks.From; currentBlock <= blocks.To; {
+		endBlock := min(currentBlock+BlockRangeSize, blocks.To)
+
+		eventsTask, err := indexer.fetchRange(ctx, networkName, client, eventConfigs, currentBlock, endBlock)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s blocks %d to %d: %w", networkName, currentBlock, endBlock, err)
+		}
+		if !indexer.dispatchRange(ctx, networkName, eventsTask, currentBlock, endBlock) {
+			return ctx.Err()
+		}
+		currentBlock = endBlock + 1
+	}
+	return nil
+}
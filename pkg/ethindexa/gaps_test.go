@@ -0,0 +1,212 @@
+package ethindexa
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/ethindexa/utils"
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestFindGaps tests that uncovered blocks between the bounds are reported, including overlapping ranges.
+func TestFindGaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges []BlockRange
+		from   uint64
+		to     uint64
+		want   []BlockRange
+	}{
+		{name: "no ranges", from: 100, to: 200, want: []BlockRange{{From: 100, To: 200}}},
+		{name: "covered", ranges: []BlockRange{{From: 90, To: 250}}, from: 100, to: 200},
+		{
+			name:   "gaps between ranges",
+			ranges: []BlockRange{{From: 100, To: 137}, {From: 176, To: 190}, {From: 195, To: 260}},
+			from:   100,
+			to:     200,
+			want:   []BlockRange{{From: 138, To: 175}, {From: 191, To: 194}},
+		},
+		{
+			name:   "leading and trailing gaps",
+			ranges: []BlockRange{{From: 120, To: 150}},
+			from:   100,
+			to:     200,
+			want:   []BlockRange{{From: 100, To: 119}, {From: 151, To: 200}},
+		},
+		{
+			name:   "overlapping ranges",
+			ranges: []BlockRange{{From: 100, To: 180}, {From: 120, To: 150}, {From: 182, To: 200}},
+			from:   100,
+			to:     200,
+			want:   []BlockRange{{From: 181, To: 181}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findGaps(tt.ranges, tt.from, tt.to))
+		})
+	}
+}
+
+// TestRangeTracker_Record tests that a processed range is written as signed block numbers.
+func TestRangeTracker_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+
+	ctx := context.Background()
+	ranges := NewRangeTracker(mockDB)
+
+	mockDB.EXPECT().Exec(ctx, gomock.Any(), "mainnet", int64(100), int64(137)).Return(pgconn.CommandTag{}, nil)
+	mockDB.EXPECT().Exec(ctx, gomock.Any(), "mainnet", int64(138), int64(175)).Return(pgconn.CommandTag{}, errors.New("connection reset"))
+
+	assert.NoError(t, ranges.Record(ctx, "mainnet", 100, 137))
+	assert.EqualError(t, ranges.Record(ctx, "mainnet", 138, 175), "failed to record mainnet blocks 138 to 175: connection reset")
+}
+
+// TestRepairGaps_NothingProcessed tests that no repair is attempted before the fetcher hands over its first range.
+func TestRepairGaps_NothingProcessed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+
+	indexer := &IndexerImpl{
+		Ranges: NewRangeTracker(mockDB),
+		Stats:  NewStatusTracker(),
+	}
+
+	assert.NoError(t, indexer.repairGaps(context.Background(), "mainnet"))
+}
+
+// TestRangeTracker_Resume tests that the fetcher resumes after the recorded range covering its start block, and
+// at the start block when none does.
+func TestRangeTracker_Resume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	ranges := NewRangeTracker(mockDB)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", int64(100)).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*dest[0].(*int64) = 175
+		return nil
+	})
+	resume, err := ranges.Resume(ctx, "mainnet", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(176), resume)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "base", int64(100)).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+	resume, err = ranges.Resume(ctx, "base", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), resume)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", int64(100)).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).Return(errors.New("connection reset"))
+	_, err = ranges.Resume(ctx, "mainnet", 100)
+	assert.EqualError(t, err, "failed to load mainnet block ranges: connection reset")
+}
+
+// TestRepairGaps_InFlight tests that the blocks after the last recorded range, still queued for the log processor, are
+// not repaired.
+func TestRepairGaps_InFlight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	indexer := &IndexerImpl{
+		Ranges: NewRangeTracker(mockDB),
+		Stats:  NewStatusTracker(),
+	}
+	indexer.Stats.SetProcessed("mainnet", 300)
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "mainnet", int64(0), int64(300)).Return(mockRows, nil)
+	gomock.InOrder(
+		mockRows.EXPECT().Next().Return(true),
+		mockRows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int64), *dest[1].(*int64) = 0, 100
+			return nil
+		}),
+		mockRows.EXPECT().Next().Return(false),
+	)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	// Refetching would need a client
+	assert.NoError(t, indexer.repairGaps(ctx, "mainnet"))
+}
+
+// TestStartLogProcessor_RecordsRange tests that a range is recorded as processed only once its logs are handed to the
+// handlers, so a range still buffered in memory is fetched again after a restart.
+func TestStartLogProcessor_RecordsRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	parsedABI, err := utils.LoadABI("erc20_usdc")
+	assert.NoError(t, err)
+	transfer := parsedABI.Events["Transfer"]
+	value, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(1))
+	assert.NoError(t, err)
+	topics := []common.Hash{transfer.ID, common.BytesToHash(traceFrom.Bytes()), common.BytesToHash(traceTo.Bytes())}
+
+	client, err := ethclient.NewClient("mainnet", "http://127.0.0.1:1")
+	assert.NoError(t, err)
+	indexer := &IndexerImpl{
+		Clients: map[string]*ethclient.Client{"mainnet": client},
+		Events: map[string]map[common.Hash][]*EventConfig{
+			"mainnet": {transfer.ID: {{
+				ContractName: "USDC", NetworkName: "mainnet", ContractAddress: traceUSDC, ContractABI: parsedABI, EventName: "Transfer",
+				HandlerKey: "USDC:mainnet:Transfer", StartBlock: big.NewInt(1),
+				Handler: func(idx *IndexerService, event Event) error { return nil },
+			}}},
+		},
+		EventQueues:   map[string]chan *EventsTask{"mainnet": make(chan *EventsTask, 1)},
+		HandlerQueues: map[string]chan HandlerTask{"mainnet": make(chan HandlerTask)},
+		Ranges:        NewRangeTracker(mockDB),
+		Pipeline:      NewPipelineMetrics(),
+	}
+	go indexer.startLogProcessor(ctx, "mainnet")
+
+	recorded := make(chan struct{})
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), "mainnet", int64(100), int64(137)).DoAndReturn(func(context.Context, string, ...any) (pgconn.CommandTag, error) {
+		close(recorded)
+		return pgconn.CommandTag{}, nil
+	})
+
+	assert.True(t, indexer.dispatchRange(ctx, "mainnet", &EventsTask{
+		Network: "mainnet",
+		Logs:    []types.Log{{Address: traceUSDC, Topics: topics, Data: value, BlockNumber: 100, TxHash: common.HexToHash("0x01")}},
+		Blocks:  map[string]*ethclient.GetBlockResponse{"100": {}},
+	}, 100, 137))
+
+	select {
+	case <-recorded:
+		t.Fatal("the range was recorded before its log was handed to the handlers")
+	case <-time.After(50 * time.Millisecond):
+	}
+	<-indexer.HandlerQueues["mainnet"]
+	select {
+	case <-recorded:
+	case <-time.After(time.Second):
+		t.Fatal("the range was not recorded")
+	}
+}
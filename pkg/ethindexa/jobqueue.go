@@ -0,0 +1,145 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/pg"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// QueueMemory keeps handler tasks in per-network channels (default).
+	QueueMemory = "memory"
+	// QueuePostgres persists handler tasks in the handler_jobs table.
+	QueuePostgres = "postgres"
+)
+
+// JobPayload is the serialized part of a handler task needed to rebuild its Event.
+type JobPayload struct {
+	Log         types.Log                        `json:"log"`
+	Block       ethclient.GetBlockResponse       `json:"block"`
+	Transaction ethclient.GetTransactionResponse `json:"transaction"`
+}
+
+// HandlerJob is a handler task persisted in the job queue.
+type HandlerJob struct {
+	ID          int64
+	Network     string
+	HandlerKey  string
+	BlockNumber int64
+	Payload     JobPayload
+}
+
+// JobQueue is a Postgres-backed handler queue. Jobs are claimed with FOR UPDATE SKIP LOCKED,
+// so several indexer replicas can drain the same queue and a job whose worker crashes is
+// released with its transaction and picked up again (at-least-once delivery).
+type JobQueue struct {
+	db           pg.PgxPool
+	PollInterval time.Duration
+}
+
+// NewJobQueue creates a JobQueue on top of the given pool.
+func NewJobQueue(db pg.PgxPool) *JobQueue {
+	return &JobQueue{
+		db:           db,
+		PollInterval: time.Second,
+	}
+}
+
+// Enqueue stores a new pending job.
+func (q *JobQueue) Enqueue(ctx context.Context, job *HandlerJob) error {
+	const query = `
+		INSERT INTO handler_jobs (network, handler_key, block_number, tx_index, log_index, payload)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	// Only the transaction of the event is kept, the rest of the block body is not needed by handlers.
+	job.Payload.Block.Result.Transactions = nil
+
+	payload, err := json.Marshal(job.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	logEntry := job.Payload.Log
+	if _, err := q.db.Exec(ctx, query, job.Network, job.HandlerKey, job.BlockNumber, int64(logEntry.TxIndex), int64(logEntry.Index), payload); err != nil {
+		return fmt.Errorf("failed to enqueue handler job: %w", err)
+	}
+
+	return nil
+}
+
+// Process claims the first pending job of a network in chain order and runs fn on it. The job is deleted when fn
+// succeeds and marked failed otherwise. It reports whether a job was claimed.
+func (q *JobQueue) Process(ctx context.Context, network string, fn func(job *HandlerJob) error) (bool, error) {
+	return q.ProcessExcept(ctx, network, nil, fn)
+}
+
+// ProcessExcept is Process leaving the jobs of the given contracts pending.
+func (q *JobQueue) ProcessExcept(ctx context.Context, network string, contracts []string, fn func(job *HandlerJob) error) (bool, error) {
+	const claimQuery = `
+		SELECT id, network, handler_key, block_number, payload
+		FROM handler_jobs
+		WHERE network = $1 AND status = 'pending'
+			AND split_part(handler_key, ':', 1) <> ALL($2::text[])
+		ORDER BY block_number, tx_index, log_index, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	if contracts == nil {
+		contracts = []string{}
+	}
+
+	const deleteQuery = `DELETE FROM handler_jobs WHERE id = $1`
+	const failQuery = `
+		UPDATE handler_jobs
+		SET status = 'failed', last_error = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	tx, err := q.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var job HandlerJob
+	var payload []byte
+	err = tx.QueryRow(ctx, claimQuery, network, contracts).Scan(&job.ID, &job.Network, &job.HandlerKey, &job.BlockNumber, &payload)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim handler job: %w", err)
+	}
+
+	if err = json.Unmarshal(payload, &job.Payload); err != nil {
+		err = fmt.Errorf("failed to unmarshal job payload: %w", err)
+	} else {
+		err = fn(&job)
+	}
+
+	if err != nil {
+		if _, execErr := tx.Exec(ctx, failQuery, job.ID, err.Error()); execErr != nil {
+			return true, fmt.Errorf("failed to mark handler job %d as failed: %w", job.ID, execErr)
+		}
+	} else {
+		if _, execErr := tx.Exec(ctx, deleteQuery, job.ID); execErr != nil {
+			return true, fmt.Errorf("failed to delete handler job %d: %w", job.ID, execErr)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return true, fmt.Errorf("failed to commit handler job %d: %w", job.ID, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,166 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestJobQueue_Process_Empty tests that Process reports no work when no job is pending.
+func TestJobQueue_Process_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	queue := NewJobQueue(mockDB)
+
+	mockDB.EXPECT().Begin(ctx).Return(mockTx, nil)
+	mockTx.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", []string{}).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+	mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+	processed, err := queue.Process(ctx, "mainnet", func(job *HandlerJob) error {
+		t.Fatal("fn must not be called without a job")
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, processed)
+}
+
+// TestJobQueue_Process_Success tests that a successfully handled job is deleted and committed.
+func TestJobQueue_Process_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	queue := NewJobQueue(mockDB)
+
+	payload, err := json.Marshal(JobPayload{
+		Log: types.Log{
+			Address: common.HexToAddress("0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"),
+			Topics:  []common.Hash{common.HexToHash("0x01")},
+			TxHash:  common.HexToHash("0x02"),
+		},
+	})
+	assert.NoError(t, err)
+
+	mockDB.EXPECT().Begin(ctx).Return(mockTx, nil)
+	mockTx.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", []string{}).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*int64)) = 7
+		*(dest[1].(*string)) = "mainnet"
+		*(dest[2].(*string)) = "UniswapV2:mainnet:Swap"
+		*(dest[3].(*int64)) = 100
+		*(dest[4].(*[]byte)) = payload
+		return nil
+	})
+	mockTx.EXPECT().Exec(ctx, `DELETE FROM handler_jobs WHERE id = $1`, int64(7)).Return(pgconn.CommandTag{}, nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+	mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+	var handled *HandlerJob
+	processed, err := queue.Process(ctx, "mainnet", func(job *HandlerJob) error {
+		handled = job
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, "UniswapV2:mainnet:Swap", handled.HandlerKey)
+	assert.Equal(t, common.HexToHash("0x02"), handled.Payload.Log.TxHash)
+}
+
+// TestJobQueue_Process_Failure tests that a job whose handler fails is marked as failed.
+func TestJobQueue_Process_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockTx := pgMock.NewMockPgxTx(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	queue := NewJobQueue(mockDB)
+
+	mockDB.EXPECT().Begin(ctx).Return(mockTx, nil)
+	mockTx.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", []string{}).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*int64)) = 8
+		*(dest[4].(*[]byte)) = []byte(`{}`)
+		return nil
+	})
+	mockTx.EXPECT().Exec(ctx, gomock.Any(), int64(8), "no handler registered").Return(pgconn.CommandTag{}, nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+	mockTx.EXPECT().Rollback(ctx).Return(nil)
+
+	processed, err := queue.Process(ctx, "mainnet", func(job *HandlerJob) error {
+		return errors.New("no handler registered")
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+}
+
+// TestJobQueue_Enqueue tests that jobs are stored with the position of their log for ordered claiming.
+func TestJobQueue_Enqueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+
+	ctx := context.Background()
+	queue := NewJobQueue(mockDB)
+
+	job := &HandlerJob{
+		Network:     "mainnet",
+		HandlerKey:  "UniswapV2:mainnet:Swap",
+		BlockNumber: 100,
+		Payload:     JobPayload{Log: types.Log{BlockNumber: 100, TxIndex: 4, Index: 12}},
+	}
+	mockDB.EXPECT().Exec(ctx, gomock.Any(), "mainnet", "UniswapV2:mainnet:Swap", int64(100), int64(4), int64(12), gomock.Any()).Return(pgconn.CommandTag{}, nil)
+
+	assert.NoError(t, queue.Enqueue(ctx, job))
+}
+
+// TestEnqueueJob_RetriesFailures tests that a job the queue fails to store is retried until it is stored, and that
+// the retries stop when the context is cancelled.
+func TestEnqueueJob_RetriesFailures(t *testing.T) {
+	minBackoff := FetchMinBackoff
+	FetchMinBackoff = time.Millisecond
+	defer func() { FetchMinBackoff = minBackoff }()
+
+	ctrl := gomock.NewController(t)
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	indexer := &IndexerImpl{JobQueue: NewJobQueue(mockDB)}
+	job := &HandlerJob{Network: "mainnet", HandlerKey: "UniswapV2:mainnet:Swap", BlockNumber: 100}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gomock.InOrder(
+		mockDB.EXPECT().Exec(ctx, gomock.Any(), gomock.Any()).Return(pgconn.CommandTag{}, errors.New("connection refused")).Times(2),
+		mockDB.EXPECT().Exec(ctx, gomock.Any(), gomock.Any()).Return(pgconn.CommandTag{}, nil),
+	)
+	assert.True(t, indexer.enqueueJob(ctx, "mainnet", job))
+
+	mockDB.EXPECT().Exec(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, string, ...any) (pgconn.CommandTag, error) {
+		cancel()
+		return pgconn.CommandTag{}, errors.New("connection refused")
+	})
+	assert.False(t, indexer.enqueueJob(ctx, "mainnet", job))
+}
@@ -0,0 +1,195 @@
+package ethindexa
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DefaultLeaderLockID is the advisory lock key used when none is configured.
+const DefaultLeaderLockID int64 = 7264810
+
+// DefaultLeaderCheckInterval is how often standbys try to take over and the leader checks its lock.
+var DefaultLeaderCheckInterval = 5 * time.Second
+
+// lockConn is the part of a dedicated connection needed to hold a session advisory lock.
+type lockConn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Release()
+}
+
+// LeaderState describes the leadership of this instance.
+type LeaderState struct {
+	Leader bool      `json:"leader"`
+	Owner  string    `json:"owner"`
+	Since  time.Time `json:"since"`
+}
+
+// LeaderElector elects a single writer through a Postgres session advisory lock.
+// The lock lives as long as the dedicated connection, so it is released by Postgres when the
+// leader process dies and a standby takes over on its next check.
+type LeaderElector struct {
+	acquire func(ctx context.Context) (lockConn, error)
+	lockID  int64
+	owner   string
+
+	mutex sync.RWMutex
+	conn  lockConn
+	state LeaderState
+}
+
+// NewLeaderElector creates a LeaderElector on top of the given pool.
+func NewLeaderElector(db pg.PgxPool, lockID int64, owner string) *LeaderElector {
+	return &LeaderElector{
+		acquire: func(ctx context.Context) (lockConn, error) {
+			conn, err := db.Acquire(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return conn, nil
+		},
+		lockID: lockID,
+		owner:  owner,
+		state:  LeaderState{Owner: owner, Since: time.Now()},
+	}
+}
+
+// TryLead takes the leader lock if it is free, or verifies that the held lock is still alive.
+// It reports whether this instance is the leader.
+func (e *LeaderElector) TryLead(ctx context.Context) (bool, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.conn != nil {
+		// The lock is held for as long as the connection is alive
+		if _, err := e.conn.Exec(ctx, "SELECT 1"); err != nil {
+			e.conn.Release()
+			e.conn = nil
+			e.setLeader(false)
+			return false, fmt.Errorf("lost leader connection: %w", err)
+		}
+		return true, nil
+	}
+
+	conn, err := e.acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", e.lockID).Scan(&locked); err != nil {
+		conn.Release()
+		return false, fmt.Errorf("failed to try leader lock: %w", err)
+	}
+	if !locked {
+		conn.Release()
+		return false, nil
+	}
+
+	e.conn = conn
+	e.setLeader(true)
+	return true, nil
+}
+
+// Resign releases the leader lock if this instance holds it.
+func (e *LeaderElector) Resign(ctx context.Context) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+
+	_, err := e.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", e.lockID)
+	e.conn.Release()
+	e.conn = nil
+	e.setLeader(false)
+	if err != nil {
+		return fmt.Errorf("failed to release leader lock: %w", err)
+	}
+
+	return nil
+}
+
+// State returns the current leadership state.
+func (e *LeaderElector) State() LeaderState {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.state
+}
+
+// IsLeader reports whether this instance is currently the leader.
+func (e *LeaderElector) IsLeader() bool {
+	return e.State().Leader
+}
+
+func (e *LeaderElector) setLeader(leader bool) {
+	if e.state.Leader == leader {
+		return
+	}
+	e.state.Leader = leader
+	e.state.Since = time.Now()
+}
+
+// startLeaderLoop runs all networks while this instance is the leader and keeps it on standby otherwise.
+func (indexer *IndexerImpl) startLeaderLoop(interval time.Duration) {
+	defer indexer.Wg.Done()
+
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	stop := func() {
+		if cancel == nil {
+			return
+		}
+		cancel()
+		<-done
+		cancel = nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		leader, err := indexer.Leader.TryLead(indexer.MainCtx)
+		if err != nil {
+			logger.Errorf("Leader check failed: %v", err)
+		}
+
+		switch {
+		case leader && cancel == nil:
+			logger.Infof("Instance %s became leader", indexer.Leader.State().Owner)
+			runCtx, runCancel := context.WithCancel(indexer.MainCtx)
+			cancel = runCancel
+			done = make(chan struct{})
+			go func(ctx context.Context, done chan struct{}) {
+				defer close(done)
+				indexer.runAllNetworks(ctx)
+			}(runCtx, done)
+		case !leader && cancel != nil:
+			logger.Warnf("Instance %s lost leadership, switching to standby", indexer.Leader.State().Owner)
+			stop()
+		}
+
+		select {
+		case <-indexer.MainCtx.Done():
+			stop()
+			resignCtx, resignCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := indexer.Leader.Resign(resignCtx); err != nil {
+				logger.Errorf("%v", err)
+			}
+			resignCancel()
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ethindexa
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeLockConn is a lockConn backed by a mocked row and a configurable Exec error.
+type fakeLockConn struct {
+	row      pgx.Row
+	execErr  error
+	released bool
+}
+
+func (c *fakeLockConn) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, c.execErr
+}
+
+func (c *fakeLockConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return c.row
+}
+
+func (c *fakeLockConn) Release() {
+	c.released = true
+}
+
+// newTestElector creates a LeaderElector whose connections come from conns in order.
+func newTestElector(conns ...*fakeLockConn) *LeaderElector {
+	elector := NewLeaderElector(nil, DefaultLeaderLockID, "indexer-1")
+	elector.acquire = func(ctx context.Context) (lockConn, error) {
+		conn := conns[0]
+		conns = conns[1:]
+		return conn, nil
+	}
+	return elector
+}
+
+// lockRow returns a mocked row that scans the given pg_try_advisory_lock result.
+func lockRow(ctrl *gomock.Controller, locked bool) pgx.Row {
+	row := pgMock.NewMockPgxRows(ctrl)
+	row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*bool)) = locked
+		return nil
+	})
+	return row
+}
+
+// TestLeaderElector_TryLead_Elected tests that taking the advisory lock makes the instance leader and keeps the connection.
+func TestLeaderElector_TryLead_Elected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	conn := &fakeLockConn{row: lockRow(ctrl, true)}
+	elector := newTestElector(conn)
+
+	leader, err := elector.TryLead(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, leader)
+	assert.True(t, elector.IsLeader())
+	assert.False(t, conn.released)
+
+	// Subsequent checks reuse the held connection
+	leader, err = elector.TryLead(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, leader)
+}
+
+// TestLeaderElector_TryLead_Standby tests that an instance stays on standby while another one holds the lock.
+func TestLeaderElector_TryLead_Standby(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	conn := &fakeLockConn{row: lockRow(ctrl, false)}
+	elector := newTestElector(conn)
+
+	leader, err := elector.TryLead(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, leader)
+	assert.False(t, elector.IsLeader())
+	assert.True(t, conn.released)
+}
+
+// TestLeaderElector_TryLead_LostConnection tests that leadership is dropped when the lock connection dies.
+func TestLeaderElector_TryLead_LostConnection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	conn := &fakeLockConn{row: lockRow(ctrl, true)}
+	elector := newTestElector(conn)
+
+	leader, err := elector.TryLead(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, leader)
+
+	conn.execErr = errors.New("connection reset")
+	leader, err = elector.TryLead(context.Background())
+
+	assert.Error(t, err)
+	assert.False(t, leader)
+	assert.False(t, elector.State().Leader)
+	assert.True(t, conn.released)
+}
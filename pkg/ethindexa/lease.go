@@ -0,0 +1,169 @@
+package ethindexa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultLeaseTTL is how long a network lease stays valid without renewal.
+var DefaultLeaseTTL = 30 * time.Second
+
+// LeaseManager claims networks for one indexer instance through rows in indexer_leases.
+// A lease is renewed by its owner and can be taken over by any instance once it expires,
+// so networks of a dead replica move to the surviving ones after at most one TTL.
+type LeaseManager struct {
+	db    pg.PgxPool
+	owner string
+	ttl   time.Duration
+
+	mutex sync.RWMutex
+	held  map[string]bool
+}
+
+// NewLeaseManager creates a LeaseManager for the given owner.
+func NewLeaseManager(db pg.PgxPool, owner string, ttl time.Duration) *LeaseManager {
+	return &LeaseManager{
+		db:    db,
+		owner: owner,
+		ttl:   ttl,
+		held:  make(map[string]bool),
+	}
+}
+
+// Owner returns the identifier this instance uses for its leases.
+func (m *LeaseManager) Owner() string {
+	return m.owner
+}
+
+// TryAcquire claims or renews the lease of a network. It reports whether this instance holds the lease.
+func (m *LeaseManager) TryAcquire(ctx context.Context, network string) (bool, error) {
+	const query = `
+		INSERT INTO indexer_leases (network, owner, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP + $3 * INTERVAL '1 millisecond')
+		ON CONFLICT (network) DO UPDATE SET
+			owner = EXCLUDED.owner,
+			expires_at = EXCLUDED.expires_at
+		WHERE indexer_leases.owner = EXCLUDED.owner OR indexer_leases.expires_at < CURRENT_TIMESTAMP
+		RETURNING owner
+	`
+
+	var owner string
+	err := m.db.QueryRow(ctx, query, network, m.owner, m.ttl.Milliseconds()).Scan(&owner)
+	if err != nil {
+		m.setHeld(network, false)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire lease for network %s: %w", network, err)
+	}
+
+	m.setHeld(network, true)
+	return true, nil
+}
+
+// Release gives up the lease of a network if this instance holds it.
+func (m *LeaseManager) Release(ctx context.Context, network string) error {
+	const query = `DELETE FROM indexer_leases WHERE network = $1 AND owner = $2`
+
+	m.setHeld(network, false)
+	if _, err := m.db.Exec(ctx, query, network, m.owner); err != nil {
+		return fmt.Errorf("failed to release lease for network %s: %w", network, err)
+	}
+
+	return nil
+}
+
+// Held returns the networks whose lease this instance currently holds.
+func (m *LeaseManager) Held() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	networks := make([]string, 0, len(m.held))
+	for network, held := range m.held {
+		if held {
+			networks = append(networks, network)
+		}
+	}
+
+	return networks
+}
+
+func (m *LeaseManager) setHeld(network string, held bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.held[network] = held
+}
+
+// instanceID identifies this process as a lease owner.
+func instanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "indexer"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// startLeaseKeeper keeps trying to hold the lease of a network and runs its consumers while it does.
+// The consumers are stopped as soon as the lease cannot be renewed.
+func (indexer *IndexerImpl) startLeaseKeeper(networkName string) {
+	defer indexer.Wg.Done()
+
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	stop := func() {
+		if cancel == nil {
+			return
+		}
+		cancel()
+		<-done
+		cancel = nil
+	}
+
+	ticker := time.NewTicker(indexer.Leases.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		acquired, err := indexer.Leases.TryAcquire(indexer.MainCtx, networkName)
+		if err != nil {
+			logger.Errorf("Lease check for network %s failed: %v", networkName, err)
+		}
+
+		switch {
+		case acquired && cancel == nil:
+			logger.Infof("Instance %s acquired network %s", indexer.Leases.Owner(), networkName)
+			networkCtx, networkCancel := context.WithCancel(indexer.MainCtx)
+			cancel = networkCancel
+			done = make(chan struct{})
+			go func(ctx context.Context, done chan struct{}) {
+				defer close(done)
+				indexer.runNetwork(ctx, networkName)
+			}(networkCtx, done)
+		case !acquired && cancel != nil:
+			logger.Warnf("Instance %s lost network %s", indexer.Leases.Owner(), networkName)
+			stop()
+		}
+
+		select {
+		case <-indexer.MainCtx.Done():
+			stop()
+			releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := indexer.Leases.Release(releaseCtx, networkName); err != nil {
+				logger.Errorf("%v", err)
+			}
+			releaseCancel()
+			return
+		case <-ticker.C:
+		}
+	}
+}
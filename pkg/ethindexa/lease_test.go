@@ -0,0 +1,77 @@
+package ethindexa
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestLeaseManager_TryAcquire_Acquired tests that a returned row means the lease is held.
+func TestLeaseManager_TryAcquire_Acquired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	leases := NewLeaseManager(mockDB, "indexer-1", 30*time.Second)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", "indexer-1", int64(30000)).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*(dest[0].(*string)) = "indexer-1"
+		return nil
+	})
+
+	acquired, err := leases.TryAcquire(ctx, "mainnet")
+
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, []string{"mainnet"}, leases.Held())
+}
+
+// TestLeaseManager_TryAcquire_HeldElsewhere tests that a lease owned by another live instance is not taken.
+func TestLeaseManager_TryAcquire_HeldElsewhere(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	leases := NewLeaseManager(mockDB, "indexer-2", 30*time.Second)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", "indexer-2", int64(30000)).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+
+	acquired, err := leases.TryAcquire(ctx, "mainnet")
+
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+	assert.Empty(t, leases.Held())
+}
+
+// TestLeaseManager_TryAcquire_Error tests that a database error is returned and the lease is treated as lost.
+func TestLeaseManager_TryAcquire_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	leases := NewLeaseManager(mockDB, "indexer-1", 30*time.Second)
+
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", "indexer-1", int64(30000)).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).Return(errors.New("connection reset"))
+
+	acquired, err := leases.TryAcquire(ctx, "mainnet")
+
+	assert.Error(t, err)
+	assert.False(t, acquired)
+	assert.Contains(t, err.Error(), "failed to acquire lease for network mainnet")
+}
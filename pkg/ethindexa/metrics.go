@@ -0,0 +1,182 @@
+package ethindexa
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HandlerStats holds the counters recorded for a single handler. Failures counts the runs that
+// returned an error, timed out or panicked, and FailureRate is their share of Runs.
+type HandlerStats struct {
+	Runs          uint64        `json:"runs"`
+	Errors        uint64        `json:"errors"`
+	Timeouts      uint64        `json:"timeouts"`
+	Panics        uint64        `json:"panics"`
+	Failures      uint64        `json:"failures"`
+	FailureRate   float64       `json:"failure_rate"`
+	TotalDuration time.Duration `json:"total_duration"`
+	MaxDuration   time.Duration `json:"max_duration"`
+}
+
+// HandlerMetrics collects run, error, timeout and panic counters keyed by handler ({contract}:{network}:{event}).
+// A nil HandlerMetrics records nothing.
+type HandlerMetrics struct {
+	mutex sync.RWMutex
+	stats map[string]*HandlerStats
+}
+
+// NewHandlerMetrics creates an empty HandlerMetrics.
+func NewHandlerMetrics() *HandlerMetrics {
+	return &HandlerMetrics{
+		stats: make(map[string]*HandlerStats),
+	}
+}
+
+// Observe records a completed handler run.
+func (m *HandlerMetrics) Observe(handlerKey string, duration time.Duration, status HandlerRunStatus) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	stats, exists := m.stats[handlerKey]
+	if !exists {
+		stats = &HandlerStats{}
+		m.stats[handlerKey] = stats
+	}
+
+	stats.Runs++
+	stats.TotalDuration += duration
+	if duration > stats.MaxDuration {
+		stats.MaxDuration = duration
+	}
+	switch status {
+	case RunError:
+		stats.Errors++
+	case RunTimeout:
+		stats.Timeouts++
+	case RunPanic:
+		stats.Panics++
+	}
+	if status != RunOK {
+		stats.Failures++
+	}
+	stats.FailureRate = float64(stats.Failures) / float64(stats.Runs)
+}
+
+// Snapshot returns a copy of the current counters.
+func (m *HandlerMetrics) Snapshot() map[string]HandlerStats {
+	snapshot := make(map[string]HandlerStats)
+	if m == nil {
+		return snapshot
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for key, stats := range m.stats {
+		snapshot[key] = *stats
+	}
+
+	return snapshot
+}
+
+// Stages of the indexing pipeline timed by PipelineMetrics.
+const (
+	StageFetchLogs   = "fetch_logs"   // eth_getLogs of a range
+	StageFetchBlocks = "fetch_blocks" // blocks emitting the logs of a range
+	StageDecode      = "decode"       // sorting, decoding and filtering the logs of a range
+	StageQueueWait   = "queue_wait"   // a decoded event waiting for room in the handler queue
+	StageHandle      = "handle"       // a handler run
+	StageWrite       = "write"        // archiving a range, recording it, or enqueueing a handler job
+)
+
+// StageStats holds the timings recorded for a pipeline stage.
+type StageStats struct {
+	Count         uint64        `json:"count"`
+	TotalDuration time.Duration `json:"total_duration"`
+	MaxDuration   time.Duration `json:"max_duration"`
+}
+
+// PipelineMetrics collects the time spent in each pipeline stage per network, to locate the
+// bottleneck between fetching and handling. A nil PipelineMetrics records nothing.
+type PipelineMetrics struct {
+	mutex sync.Mutex
+	stats map[string]map[string]*StageStats // map[network][stage]
+}
+
+// NewPipelineMetrics creates an empty PipelineMetrics.
+func NewPipelineMetrics() *PipelineMetrics {
+	return &PipelineMetrics{
+		stats: make(map[string]map[string]*StageStats),
+	}
+}
+
+// Observe records the time a network spent in a stage.
+func (m *PipelineMetrics) Observe(network, stage string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	stages, exists := m.stats[network]
+	if !exists {
+		stages = make(map[string]*StageStats)
+		m.stats[network] = stages
+	}
+	stats, exists := stages[stage]
+	if !exists {
+		stats = &StageStats{}
+		stages[stage] = stats
+	}
+
+	stats.Count++
+	stats.TotalDuration += duration
+	if duration > stats.MaxDuration {
+		stats.MaxDuration = duration
+	}
+}
+
+// Snapshot returns a copy of the current timings.
+func (m *PipelineMetrics) Snapshot() map[string]map[string]StageStats {
+	snapshot := make(map[string]map[string]StageStats)
+	if m == nil {
+		return snapshot
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for network, stages := range m.stats {
+		snapshot[network] = make(map[string]StageStats, len(stages))
+		for stage, stats := range stages {
+			snapshot[network][stage] = *stats
+		}
+	}
+	return snapshot
+}
+
+// MetricsPath is the path of the indexer metrics endpoint served by MetricsHandler.
+const MetricsPath = "/admin/indexer/metrics"
+
+// IndexerMetrics is the JSON served at MetricsPath. Durations are in nanoseconds.
+type IndexerMetrics struct {
+	Handlers map[string]HandlerStats          `json:"handlers"`
+	Pipeline map[string]map[string]StageStats `json:"pipeline"`
+}
+
+// MetricsHandler serves the handler and pipeline metrics as JSON.
+func MetricsHandler(indexer *IndexerImpl) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		metrics := IndexerMetrics{
+			Handlers: indexer.Metrics.Snapshot(),
+			Pipeline: indexer.Pipeline.Snapshot(),
+		}
+		if err := json.NewEncoder(w).Encode(metrics); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMetricsHandler tests that handler runs are timed both per handler and as the handle stage of their network.
+func TestMetricsHandler(t *testing.T) {
+	indexer := &IndexerImpl{
+		Metrics:  NewHandlerMetrics(),
+		Pipeline: NewPipelineMetrics(),
+	}
+	indexer.Pipeline.Observe("mainnet", StageFetchLogs, 3*time.Second)
+	indexer.Pipeline.Observe("mainnet", StageFetchLogs, time.Second)
+	indexer.runHandler(context.Background(), HandlerTask{
+		Network:      "mainnet",
+		HandlerKey:   "USDC:mainnet:Transfer",
+		EventHandler: func(idx *IndexerService, event Event) error { return nil },
+	})
+
+	rr := httptest.NewRecorder()
+	MetricsHandler(indexer).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+
+	var metrics IndexerMetrics
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&metrics))
+	assert.Equal(t, StageStats{Count: 2, TotalDuration: 4 * time.Second, MaxDuration: 3 * time.Second}, metrics.Pipeline["mainnet"][StageFetchLogs])
+	assert.Equal(t, uint64(1), metrics.Pipeline["mainnet"][StageHandle].Count)
+	assert.Equal(t, uint64(1), metrics.Handlers["USDC:mainnet:Transfer"].Runs)
+}
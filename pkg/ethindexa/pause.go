@@ -0,0 +1,339 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+)
+
+// DefaultPauseRefreshInterval is how often pauses are reloaded from Postgres, so pauses made
+// through another instance take effect without a restart.
+var DefaultPauseRefreshInterval = 5 * time.Second
+
+// PauseTarget selects what a pause applies to: a whole network, a contract on every network,
+// or a contract on one network.
+type PauseTarget struct {
+	Network  string `json:"network,omitempty"`
+	Contract string `json:"contract,omitempty"`
+}
+
+// Pause is a paused target and when it was paused.
+type Pause struct {
+	PauseTarget
+	PausedAt time.Time `json:"paused_at"`
+}
+
+// Pauses holds the paused networks and contracts. A paused network stops fetching blocks and
+// handling events; a paused contract stops handling its events. Pauses are persisted in
+// indexer_pauses when a database is set, so a restart keeps them. A nil Pauses pauses nothing.
+type Pauses struct {
+	db pg.PgxPool // nil keeps pauses in memory only
+
+	mutex   sync.RWMutex
+	paused  map[PauseTarget]time.Time
+	changed chan struct{} // closed and replaced whenever pauses change
+}
+
+// NewPauses creates an empty Pauses persisted in db, which may be nil.
+func NewPauses(db pg.PgxPool) *Pauses {
+	return &Pauses{
+		db:      db,
+		paused:  make(map[PauseTarget]time.Time),
+		changed: make(chan struct{}),
+	}
+}
+
+// Load replaces the pauses with those stored in Postgres.
+func (p *Pauses) Load(ctx context.Context) error {
+	const query = `SELECT network, contract, paused_at FROM indexer_pauses`
+
+	if p.db == nil {
+		return nil
+	}
+
+	rows, err := p.db.Query(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to load pauses: %w", err)
+	}
+	defer rows.Close()
+
+	paused := make(map[PauseTarget]time.Time)
+	for rows.Next() {
+		var target PauseTarget
+		var pausedAt time.Time
+		if err := rows.Scan(&target.Network, &target.Contract, &pausedAt); err != nil {
+			return fmt.Errorf("failed to scan pause: %w", err)
+		}
+		paused[target] = pausedAt
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load pauses: %w", err)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if !samePauses(p.paused, paused) {
+		p.paused = paused
+		p.notifyLocked()
+	}
+	return nil
+}
+
+// Pause pauses a target. Pausing a paused target keeps its original time.
+func (p *Pauses) Pause(ctx context.Context, target PauseTarget) error {
+	const query = `
+		INSERT INTO indexer_pauses (network, contract)
+		VALUES ($1, $2)
+		ON CONFLICT (network, contract) DO NOTHING
+	`
+
+	if target == (PauseTarget{}) {
+		return fmt.Errorf("pause target needs a network or a contract")
+	}
+	if p.db != nil {
+		if _, err := p.db.Exec(ctx, query, target.Network, target.Contract); err != nil {
+			return fmt.Errorf("failed to pause %s: %w", target, err)
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, exists := p.paused[target]; !exists {
+		p.paused[target] = time.Now()
+		p.notifyLocked()
+	}
+	logger.Infof("Paused %s", target)
+	return nil
+}
+
+// Resume lifts the pause of a target.
+func (p *Pauses) Resume(ctx context.Context, target PauseTarget) error {
+	const query = `DELETE FROM indexer_pauses WHERE network = $1 AND contract = $2`
+
+	if p.db != nil {
+		if _, err := p.db.Exec(ctx, query, target.Network, target.Contract); err != nil {
+			return fmt.Errorf("failed to resume %s: %w", target, err)
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, exists := p.paused[target]; exists {
+		delete(p.paused, target)
+		p.notifyLocked()
+	}
+	logger.Infof("Resumed %s", target)
+	return nil
+}
+
+// List returns the current pauses ordered by network and contract.
+func (p *Pauses) List() []Pause {
+	if p == nil {
+		return []Pause{}
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	pauses := make([]Pause, 0, len(p.paused))
+	for target, pausedAt := range p.paused {
+		pauses = append(pauses, Pause{PauseTarget: target, PausedAt: pausedAt})
+	}
+	sort.Slice(pauses, func(i, j int) bool {
+		if pauses[i].Network != pauses[j].Network {
+			return pauses[i].Network < pauses[j].Network
+		}
+		return pauses[i].Contract < pauses[j].Contract
+	})
+	return pauses
+}
+
+// Paused reports whether events of a contract on a network must wait. With an empty contract
+// it reports whether the network itself is paused.
+func (p *Pauses) Paused(network, contract string) bool {
+	if p == nil {
+		return false
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.pausedLocked(network, contract)
+}
+
+// PausedContracts returns the contracts paused on a network, including those paused on every network.
+func (p *Pauses) PausedContracts(network string) []string {
+	contracts := []string{}
+	if p == nil {
+		return contracts
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for target := range p.paused {
+		if target.Contract != "" && (target.Network == "" || target.Network == network) {
+			contracts = append(contracts, target.Contract)
+		}
+	}
+	sort.Strings(contracts)
+	return contracts
+}
+
+// waitResumed blocks while Paused(network, contract) holds. It returns false if ctx is done first.
+func (p *Pauses) waitResumed(ctx context.Context, network, contract string) bool {
+	if p == nil {
+		return true
+	}
+	for {
+		p.mutex.RLock()
+		paused := p.pausedLocked(network, contract)
+		changed := p.changed
+		p.mutex.RUnlock()
+
+		if !paused {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-changed:
+		}
+	}
+}
+
+func (p *Pauses) pausedLocked(network, contract string) bool {
+	if _, exists := p.paused[PauseTarget{Network: network}]; exists {
+		return true
+	}
+	if contract == "" {
+		return false
+	}
+	_, onNetwork := p.paused[PauseTarget{Network: network, Contract: contract}]
+	_, everywhere := p.paused[PauseTarget{Contract: contract}]
+	return onNetwork || everywhere
+}
+
+// notifyLocked wakes the waiters. The caller must hold the write lock.
+func (p *Pauses) notifyLocked() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+func (t PauseTarget) String() string {
+	switch {
+	case t.Contract == "":
+		return "network " + t.Network
+	case t.Network == "":
+		return "contract " + t.Contract
+	default:
+		return fmt.Sprintf("contract %s on network %s", t.Contract, t.Network)
+	}
+}
+
+func samePauses(a, b map[PauseTarget]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for target := range a {
+		if _, exists := b[target]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// startPauseRefresher reloads the pauses from Postgres until the indexer stops.
+func (indexer *IndexerImpl) startPauseRefresher(interval time.Duration) {
+	defer indexer.Wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-indexer.MainCtx.Done():
+			return
+		case <-ticker.C:
+			if err := indexer.Pauses.Load(indexer.MainCtx); err != nil {
+				logger.Errorf("Failed to refresh pauses: %v", err)
+			}
+		}
+	}
+}
+
+// PauseHandler pauses, or resumes, the PauseTarget given as the JSON body and responds with
+// the current pauses. Targets must name a configured network or contract.
+func PauseHandler(indexer *IndexerImpl, resume bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var target PauseTarget
+		if err := json.NewDecoder(r.Body).Decode(&target); err != nil {
+			http.Error(w, "invalid pause target: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := indexer.checkPauseTarget(target); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var err error
+		if resume {
+			err = indexer.Pauses.Resume(r.Context(), target)
+		} else {
+			err = indexer.Pauses.Pause(r.Context(), target)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writePauses(w, indexer)
+	})
+}
+
+// PausesHandler serves the current pauses as JSON.
+func PausesHandler(indexer *IndexerImpl) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writePauses(w, indexer)
+	})
+}
+
+func writePauses(w http.ResponseWriter, indexer *IndexerImpl) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(indexer.Pauses.List()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// checkPauseTarget checks that a target names a configured network and contract.
+func (indexer *IndexerImpl) checkPauseTarget(target PauseTarget) error {
+	if target == (PauseTarget{}) {
+		return fmt.Errorf("pause target needs a network or a contract")
+	}
+	if target.Network != "" {
+		if _, exists := indexer.Events[target.Network]; !exists {
+			return fmt.Errorf("unknown network %q", target.Network)
+		}
+	}
+	if target.Contract == "" {
+		return nil
+	}
+	for networkName, eventConfigs := range indexer.Events {
+		if target.Network != "" && networkName != target.Network {
+			continue
+		}
+		for _, configList := range eventConfigs {
+			for _, config := range configList {
+				if config.ContractName == target.Contract {
+					return nil
+				}
+			}
+		}
+	}
+	if target.Network != "" {
+		return fmt.Errorf("contract %q is not configured on network %q", target.Contract, target.Network)
+	}
+	return fmt.Errorf("unknown contract %q", target.Contract)
+}
@@ -0,0 +1,115 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPauses_Paused tests network, contract and every-network contract pauses.
+func TestPauses_Paused(t *testing.T) {
+	ctx := context.Background()
+	pauses := NewPauses(nil)
+
+	assert.NoError(t, pauses.Pause(ctx, PauseTarget{Network: "base", Contract: "USDC"}))
+	assert.NoError(t, pauses.Pause(ctx, PauseTarget{Contract: "AAVE"}))
+
+	assert.False(t, pauses.Paused("base", ""))
+	assert.True(t, pauses.Paused("base", "USDC"))
+	assert.False(t, pauses.Paused("mainnet", "USDC"))
+	assert.True(t, pauses.Paused("mainnet", "AAVE"))
+	assert.Equal(t, []string{"AAVE", "USDC"}, pauses.PausedContracts("base"))
+	assert.Equal(t, []string{"AAVE"}, pauses.PausedContracts("mainnet"))
+
+	assert.NoError(t, pauses.Pause(ctx, PauseTarget{Network: "mainnet"}))
+	assert.True(t, pauses.Paused("mainnet", ""))
+	assert.True(t, pauses.Paused("mainnet", "USDC"))
+
+	assert.NoError(t, pauses.Resume(ctx, PauseTarget{Network: "mainnet"}))
+	assert.NoError(t, pauses.Resume(ctx, PauseTarget{Contract: "AAVE"}))
+	assert.False(t, pauses.Paused("mainnet", "USDC"))
+	assert.Equal(t, []string{}, pauses.PausedContracts("mainnet"))
+	assert.Len(t, pauses.List(), 1)
+
+	assert.Error(t, pauses.Pause(ctx, PauseTarget{}))
+}
+
+// TestPauses_WaitResumed tests that waiters are released on resume or cancellation.
+func TestPauses_WaitResumed(t *testing.T) {
+	ctx := context.Background()
+	pauses := NewPauses(nil)
+	assert.NoError(t, pauses.Pause(ctx, PauseTarget{Network: "base"}))
+
+	resumed := make(chan bool)
+	go func() { resumed <- pauses.waitResumed(ctx, "base", "USDC") }()
+
+	select {
+	case <-resumed:
+		t.Fatal("waitResumed returned while the network is paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	assert.NoError(t, pauses.Resume(ctx, PauseTarget{Network: "base"}))
+	select {
+	case ok := <-resumed:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("waitResumed did not return after resume")
+	}
+
+	assert.NoError(t, pauses.Pause(ctx, PauseTarget{Contract: "USDC"}))
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, pauses.waitResumed(cancelCtx, "base", "USDC"))
+}
+
+// TestPauseHandler tests pausing and resuming through the admin endpoints.
+func TestPauseHandler(t *testing.T) {
+	indexer := &IndexerImpl{
+		Events: map[string]map[common.Hash][]*EventConfig{
+			"mainnet": {common.HexToHash("0x1"): {{ContractName: "USDC"}}},
+		},
+		Pauses: NewPauses(nil),
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		resume bool
+		code   int
+	}{
+		{name: "unknown network", body: `{"network":"base"}`, code: http.StatusBadRequest},
+		{name: "unknown contract", body: `{"network":"mainnet","contract":"AAVE"}`, code: http.StatusBadRequest},
+		{name: "empty target", body: `{}`, code: http.StatusBadRequest},
+		{name: "invalid body", body: `network`, code: http.StatusBadRequest},
+		{name: "pause contract", body: `{"network":"mainnet","contract":"USDC"}`, code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/indexer/pause", strings.NewReader(tt.body))
+			PauseHandler(indexer, tt.resume).ServeHTTP(rr, req)
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+
+	assert.True(t, indexer.Status().Networks[0].Contracts[0].Paused)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/indexer/resume", strings.NewReader(`{"network":"mainnet","contract":"USDC"}`))
+	PauseHandler(indexer, true).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var pauses []Pause
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&pauses))
+	assert.Empty(t, pauses)
+	assert.False(t, indexer.Status().Networks[0].Contracts[0].Paused)
+}
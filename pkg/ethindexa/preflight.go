@@ -0,0 +1,224 @@
+package ethindexa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/ethindexa/utils"
+)
+
+// PreflightStatus is the outcome of a readiness check.
+type PreflightStatus string
+
+const (
+	PreflightOK   PreflightStatus = "ok"
+	PreflightWarn PreflightStatus = "warn" // the indexer starts, but may not behave as intended
+	PreflightFail PreflightStatus = "fail" // the indexer cannot start or index correctly
+)
+
+// PreflightCheck is a single readiness check, e.g. "rpc mainnet".
+type PreflightCheck struct {
+	Name   string          `json:"name"`
+	Status PreflightStatus `json:"status"`
+	Detail string          `json:"detail"`
+}
+
+// PreflightReport lists the readiness checks run before starting the indexer.
+type PreflightReport struct {
+	Checks []PreflightCheck `json:"checks"`
+}
+
+// Add records a check.
+func (r *PreflightReport) Add(name string, status PreflightStatus, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, PreflightCheck{Name: name, Status: status, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Ready reports whether no check failed.
+func (r *PreflightReport) Ready() bool {
+	for _, check := range r.Checks {
+		if check.Status == PreflightFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Print writes the report as a table followed by a readiness line.
+func (r *PreflightReport) Print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+	for _, check := range r.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", check.Name, check.Status, check.Detail)
+	}
+	tw.Flush()
+
+	if r.Ready() {
+		fmt.Fprintln(w, "\nready")
+	} else {
+		fmt.Fprintln(w, "\nnot ready")
+	}
+}
+
+// Preflight checks the configuration file at path without starting the indexer: the ABIs,
+// events and filters of every contract, the reachability and chain ID of every network used by
+// a contract, start blocks against the head blocks, and the handler keys against the events.
+func Preflight(ctx context.Context, path string, handlers map[string]EventHandler) *PreflightReport {
+	report := &PreflightReport{}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		report.addConfigError("config", err)
+		return report
+	}
+	report.Add("config", PreflightOK, "%s", path)
+
+	heads := config.preflightNetworks(ctx, report)
+	if err := config.ValidateStartBlocks(heads); err != nil {
+		report.addConfigError("config", err)
+	} else {
+		report.Add("start blocks", PreflightOK, "checked against %d network heads", len(heads))
+	}
+
+	config.preflightEvents(report)
+	config.PreflightHandlers(report, handlers)
+	return report
+}
+
+// preflightNetworks connects to every network used by a contract, checks its chain ID when
+// configured, and returns the head blocks of the reachable networks.
+func (c *Config) preflightNetworks(ctx context.Context, report *PreflightReport) map[string]uint64 {
+	used := make(map[string]bool)
+	for _, contract := range c.Contracts {
+		for networkName := range contract.Networks {
+			used[networkName] = true
+		}
+	}
+
+	heads := make(map[string]uint64, len(used))
+	for _, networkName := range sortedKeys(used) {
+		name := "rpc " + networkName
+		network := c.Networks[networkName]
+
+		client, err := ethclient.NewClient(networkName, network.RPCURL)
+		if err != nil {
+			report.Add(name, PreflightFail, "failed to connect: %v", err)
+			continue
+		}
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			report.Add(name, PreflightFail, "failed to get head block: %v", err)
+			continue
+		}
+		heads[networkName] = header.Number.Uint64()
+
+		chainID, err := client.ChainID(ctx)
+		switch {
+		case err != nil:
+			report.Add(name, PreflightFail, "failed to get chain ID: %v", err)
+		case network.ChainID == 0:
+			report.Add(name, PreflightWarn, "head %d, chain ID %s not configured", header.Number, chainID)
+		case chainID.Int64() != int64(network.ChainID):
+			report.Add(name, PreflightFail, "chain ID %s, configured %d", chainID, network.ChainID)
+		default:
+			report.Add(name, PreflightOK, "head %d, chain ID %s", header.Number, chainID)
+		}
+	}
+	return heads
+}
+
+// preflightEvents resolves the configured events of every contract to their topics.
+func (c *Config) preflightEvents(report *PreflightReport) {
+	for _, contractName := range sortedKeys(c.Contracts) {
+		contract := c.Contracts[contractName]
+		name := "abi " + contractName
+
+		parsedABI, err := utils.LoadABI(contract.ABI)
+		if err != nil {
+			report.Add(name, PreflightFail, "failed to load ABI %q: %v", contract.ABI, err)
+			continue
+		}
+		resolved := make([]string, 0, len(contract.Events))
+		for _, eventName := range contract.Events {
+			topic0, err := GetEventTopic0(parsedABI, eventName)
+			if err != nil {
+				report.Add(name, PreflightFail, "%v", err)
+				continue
+			}
+			resolved = append(resolved, fmt.Sprintf("%s %s", eventName, topic0.Hex()[:10]))
+		}
+		if len(resolved) == len(contract.Events) {
+			report.Add(name, PreflightOK, "%s", strings.Join(resolved, ", "))
+		}
+	}
+}
+
+// PreflightHandlers matches the handler keys against the configured events: an event without a
+// handler is skipped by the indexer, and a key matching no event is most likely misspelled.
+func (c *Config) PreflightHandlers(report *PreflightReport, handlers map[string]EventHandler) {
+	registry := NewHandlerRegistry()
+	for _, key := range sortedKeys(handlers) {
+		if err := registry.Register(key, handlers[key]); err != nil {
+			report.Add("handler "+key, PreflightFail, "%v", err)
+			continue
+		}
+		if !c.matchesEvent(key) {
+			report.Add("handler "+key, PreflightFail, "matches no configured event")
+		}
+	}
+
+	for _, contractName := range sortedKeys(c.Contracts) {
+		contract := c.Contracts[contractName]
+		for _, networkName := range sortedKeys(contract.Networks) {
+			for _, eventName := range contract.Events {
+				name := "event " + handlerKey(contractName, networkName, eventName)
+				if registry.Resolve(contractName, networkName, eventName) == nil {
+					report.Add(name, PreflightWarn, "no handler, the event is skipped")
+				} else {
+					report.Add(name, PreflightOK, "handled")
+				}
+			}
+		}
+	}
+}
+
+// matchesEvent reports whether a valid handler key, which may contain wildcards, matches a
+// configured event.
+func (c *Config) matchesEvent(key string) bool {
+	parts := strings.Split(key, ":")
+	matches := func(part, name string) bool { return part == handlerWildcard || part == name }
+
+	for contractName, contract := range c.Contracts {
+		if !matches(parts[0], contractName) {
+			continue
+		}
+		for networkName := range contract.Networks {
+			if !matches(parts[1], networkName) {
+				continue
+			}
+			for _, eventName := range contract.Events {
+				if matches(parts[2], eventName) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// addConfigError records the problems of a *ConfigError as failed checks named after their
+// paths, or any other error as a single failed check.
+func (r *PreflightReport) addConfigError(name string, err error) {
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		r.Add(name, PreflightFail, "%v", err)
+		return
+	}
+	for _, problem := range configErr.Problems {
+		r.Add(name+" "+problem.Path, PreflightFail, "%s", problem.Message)
+	}
+}
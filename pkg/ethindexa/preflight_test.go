@@ -0,0 +1,124 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRPC serves eth_chainId and eth_getBlockByNumber for a chain at head.
+func fakeRPC(t *testing.T, chainID int64, head int64) *httptest.Server {
+	header, err := json.Marshal(&types.Header{Number: big.NewInt(head), Difficulty: big.NewInt(0)})
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		result := json.RawMessage(`null`)
+		switch req.Method {
+		case "eth_chainId":
+			result, _ = json.Marshal("0x" + big.NewInt(chainID).Text(16))
+		case "eth_getBlockByNumber":
+			result = header
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// checkStatuses returns the status of every check by name.
+func checkStatuses(report *PreflightReport) map[string]PreflightStatus {
+	statuses := make(map[string]PreflightStatus, len(report.Checks))
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+// TestPreflightNetworks tests that networks are checked for reachability and their chain ID.
+func TestPreflightNetworks(t *testing.T) {
+	mainnet := fakeRPC(t, 1, 100)
+	base := fakeRPC(t, 8453, 200)
+	config := &Config{
+		Networks: map[string]NetworkConfig{
+			"mainnet":  {ChainID: 1, RPCURL: mainnet.URL},
+			"base":     {ChainID: 1, RPCURL: base.URL},
+			"sepolia":  {RPCURL: mainnet.URL},
+			"polygon":  {ChainID: 137, RPCURL: "http://127.0.0.1:1"},
+			"optimism": {ChainID: 10, RPCURL: mainnet.URL},
+		},
+		Contracts: map[string]ContractConfig{
+			"USDC": {Networks: map[string]ContractNetworkConfig{"mainnet": {}, "base": {}, "sepolia": {}, "polygon": {}}},
+		},
+	}
+
+	report := &PreflightReport{}
+	heads := config.preflightNetworks(context.Background(), report)
+
+	assert.Equal(t, map[string]uint64{"mainnet": 100, "base": 200, "sepolia": 100}, heads)
+	assert.Equal(t, map[string]PreflightStatus{
+		"rpc mainnet": PreflightOK,
+		"rpc base":    PreflightFail,
+		"rpc sepolia": PreflightWarn,
+		"rpc polygon": PreflightFail,
+	}, checkStatuses(report), "optimism is not used by a contract")
+	assert.False(t, report.Ready())
+}
+
+// TestPreflightHandlers tests that handler keys are matched against the configured events.
+func TestPreflightHandlers(t *testing.T) {
+	handler := func(*IndexerService, Event) error { return nil }
+	config := &Config{
+		Contracts: map[string]ContractConfig{
+			"USDC": {
+				Networks: map[string]ContractNetworkConfig{"mainnet": {}, "base": {}},
+				Events:   []string{"Transfer", "Approval"},
+			},
+		},
+	}
+
+	report := &PreflightReport{}
+	config.PreflightHandlers(report, map[string]EventHandler{
+		"USDC:*:Transfer":      handler,
+		"USDC:base:Approval":   handler,
+		"USDC:mainnet:Tranfer": handler,
+		"USDC:mainnet":         handler,
+	})
+
+	assert.Equal(t, map[string]PreflightStatus{
+		"handler USDC:mainnet:Tranfer": PreflightFail,
+		"handler USDC:mainnet":         PreflightFail,
+		"event USDC:base:Transfer":     PreflightOK,
+		"event USDC:base:Approval":     PreflightOK,
+		"event USDC:mainnet:Transfer":  PreflightOK,
+		"event USDC:mainnet:Approval":  PreflightWarn,
+	}, checkStatuses(report))
+	assert.False(t, report.Ready())
+}
+
+// TestPreflightReport_Print tests that the report is printed as a table with its readiness.
+func TestPreflightReport_Print(t *testing.T) {
+	report := &PreflightReport{}
+	report.Add("database", PreflightOK, "connected")
+	report.Add("event USDC:mainnet:Approval", PreflightWarn, "no handler, the event is skipped")
+
+	var sb strings.Builder
+	report.Print(&sb)
+
+	assert.True(t, report.Ready())
+	assert.Contains(t, sb.String(), "event USDC:mainnet:Approval  warn    no handler, the event is skipped")
+	assert.True(t, strings.HasSuffix(sb.String(), "\nready\n"))
+}
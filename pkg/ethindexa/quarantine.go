@@ -0,0 +1,172 @@
+package ethindexa
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"hw/pkg/logger"
+)
+
+// DefaultQuarantineThreshold is the number of consecutive failed runs (panics and timeouts)
+// after which a handler is quarantined.
+var DefaultQuarantineThreshold = 5
+
+// QuarantinedHandler is a handler whose events are skipped, and why.
+type QuarantinedHandler struct {
+	Handler       string    `json:"handler"`
+	Failures      int       `json:"failures"`
+	LastError     string    `json:"last_error"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+	Skipped       uint64    `json:"skipped"`
+}
+
+// HandlerQuarantine counts the consecutive failures of each handler ({contract}:{network}:{event})
+// and quarantines a handler once they reach the threshold: its events are skipped until it is
+// released. A successful run resets the count. Quarantines live in memory, so a restart retries
+// every handler. A nil HandlerQuarantine quarantines nothing.
+type HandlerQuarantine struct {
+	threshold int
+
+	mutex       sync.Mutex
+	failures    map[string]int
+	quarantined map[string]*QuarantinedHandler
+}
+
+// NewHandlerQuarantine creates a HandlerQuarantine that quarantines a handler after threshold
+// consecutive failures. A threshold below 1 never quarantines.
+func NewHandlerQuarantine(threshold int) *HandlerQuarantine {
+	return &HandlerQuarantine{
+		threshold:   threshold,
+		failures:    make(map[string]int),
+		quarantined: make(map[string]*QuarantinedHandler),
+	}
+}
+
+// Quarantined reports whether the events of a handler are skipped.
+func (q *HandlerQuarantine) Quarantined(handlerKey string) bool {
+	if q == nil {
+		return false
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	_, exists := q.quarantined[handlerKey]
+	return exists
+}
+
+// Skip counts an event skipped because its handler is quarantined.
+func (q *HandlerQuarantine) Skip(handlerKey string) {
+	if q == nil {
+		return
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if quarantined, exists := q.quarantined[handlerKey]; exists {
+		quarantined.Skipped++
+	}
+}
+
+// Succeeded resets the consecutive failures of a handler.
+func (q *HandlerQuarantine) Succeeded(handlerKey string) {
+	if q == nil {
+		return
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	delete(q.failures, handlerKey)
+}
+
+// Failed records a failed run of a handler and reports whether it quarantined the handler.
+func (q *HandlerQuarantine) Failed(handlerKey string, err error) bool {
+	if q == nil {
+		return false
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.failures[handlerKey]++
+	if q.threshold < 1 || q.failures[handlerKey] < q.threshold {
+		return false
+	}
+	if _, exists := q.quarantined[handlerKey]; exists {
+		return false
+	}
+	q.quarantined[handlerKey] = &QuarantinedHandler{
+		Handler:       handlerKey,
+		Failures:      q.failures[handlerKey],
+		LastError:     err.Error(),
+		QuarantinedAt: time.Now(),
+	}
+	return true
+}
+
+// Release lifts the quarantine of a handler and resets its failures. It reports whether the
+// handler was quarantined.
+func (q *HandlerQuarantine) Release(handlerKey string) bool {
+	if q == nil {
+		return false
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	delete(q.failures, handlerKey)
+	if _, exists := q.quarantined[handlerKey]; !exists {
+		return false
+	}
+	delete(q.quarantined, handlerKey)
+	logger.Infof("Released handler %s from quarantine", handlerKey)
+	return true
+}
+
+// List returns the quarantined handlers ordered by handler key.
+func (q *HandlerQuarantine) List() []QuarantinedHandler {
+	if q == nil {
+		return []QuarantinedHandler{}
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	list := make([]QuarantinedHandler, 0, len(q.quarantined))
+	for _, quarantined := range q.quarantined {
+		list = append(list, *quarantined)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Handler < list[j].Handler
+	})
+	return list
+}
+
+// UnquarantineHandler releases the handler named by {"handler": "{contract}:{network}:{event}"}
+// and responds with the handlers still quarantined.
+func UnquarantineHandler(indexer *IndexerImpl) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Handler string `json:"handler"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid handler: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !indexer.Quarantine.Release(body.Handler) {
+			http.Error(w, "handler "+body.Handler+" is not quarantined", http.StatusNotFound)
+			return
+		}
+		writeQuarantine(w, indexer)
+	})
+}
+
+// QuarantineHandler serves the quarantined handlers as JSON.
+func QuarantineHandler(indexer *IndexerImpl) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeQuarantine(w, indexer)
+	})
+}
+
+func writeQuarantine(w http.ResponseWriter, indexer *IndexerImpl) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(indexer.Quarantine.List()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
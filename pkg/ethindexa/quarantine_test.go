@@ -0,0 +1,47 @@
+package ethindexa
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnquarantineHandler tests that a quarantined handler is released, and that releasing an
+// unknown handler is rejected.
+func TestUnquarantineHandler(t *testing.T) {
+	indexer := &IndexerImpl{Quarantine: NewHandlerQuarantine(1)}
+	assert.True(t, indexer.Quarantine.Failed("UniswapV2:mainnet:Swap", errors.New("handler panicked")))
+	assert.Len(t, indexer.Status().Quarantined, 1)
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{name: "invalid body", body: `handler`, code: http.StatusBadRequest},
+		{name: "not quarantined", body: `{"handler":"USDC:mainnet:Transfer"}`, code: http.StatusNotFound},
+		{name: "released", body: `{"handler":"UniswapV2:mainnet:Swap"}`, code: http.StatusOK},
+		{name: "already released", body: `{"handler":"UniswapV2:mainnet:Swap"}`, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/indexer/unquarantine", strings.NewReader(tt.body))
+			UnquarantineHandler(indexer).ServeHTTP(rr, req)
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	QuarantineHandler(indexer).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/indexer/quarantine", nil))
+	var quarantined []QuarantinedHandler
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&quarantined))
+	assert.Empty(t, quarantined)
+	assert.False(t, indexer.Quarantine.Quarantined("UniswapV2:mainnet:Swap"))
+}
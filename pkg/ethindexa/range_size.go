@@ -0,0 +1,84 @@
+package ethindexa
+
+import (
+	"sync"
+)
+
+var (
+	// DefaultMaxBlockRange bounds the blocks after the first one fetched per range when the network
+	// does not configure maxBlockRange.
+	DefaultMaxBlockRange uint64 = 2000
+	// TargetRangeLogs is the number of logs per range the range size aims for: ranges returning
+	// fewer than half of it widen, ranges returning more narrow.
+	TargetRangeLogs = 1000
+)
+
+// RangeSizer adapts the number of blocks fetched per eth_getLogs call of a network to the density
+// of its logs. Ranges start at BlockRangeSize blocks after the first one, double while they return
+// few logs, up to the max, so sparse contracts skip empty history in a few calls, and halve when
+// they return many logs or the provider rejects them as too large. A nil RangeSizer always
+// fetches BlockRangeSize blocks.
+type RangeSizer struct {
+	max uint64
+
+	mutex sync.Mutex
+	size  uint64
+}
+
+// NewRangeSizer creates a RangeSizer fetching up to max blocks after the first one per range.
+func NewRangeSizer(max uint64) *RangeSizer {
+	if max == 0 {
+		max = DefaultMaxBlockRange
+	}
+	return &RangeSizer{max: max, size: min(BlockRangeSize, max)}
+}
+
+// Size returns the number of blocks after the first one to fetch in the next range.
+func (s *RangeSizer) Size() uint64 {
+	if s == nil {
+		return BlockRangeSize
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.size
+}
+
+// Observe updates the size from the number of logs of a fetched range and returns the previous
+// and new sizes.
+func (s *RangeSizer) Observe(logs int) (previous, size uint64) {
+	if s == nil {
+		return BlockRangeSize, BlockRangeSize
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous = s.size
+	switch {
+	case logs > TargetRangeLogs:
+		s.size /= 2
+	case logs < TargetRangeLogs/2:
+		s.size = min(s.size*2+1, s.max)
+	}
+	return previous, s.size
+}
+
+// Narrow halves the size after the provider rejected a range as too large, and caps it below
+// maxBlocks when the provider told its limit. It returns false when ranges are down to a single
+// block already.
+func (s *RangeSizer) Narrow(maxBlocks uint64) (previous, size uint64, narrowed bool) {
+	if s == nil {
+		return BlockRangeSize, BlockRangeSize, false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if maxBlocks > 0 {
+		s.max = min(s.max, maxBlocks-1)
+	}
+	previous = s.size
+	if s.size == 0 {
+		return previous, 0, false
+	}
+	s.size = min(s.size/2, s.max)
+	return previous, s.size, true
+}
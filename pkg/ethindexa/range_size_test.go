@@ -0,0 +1,52 @@
+package ethindexa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRangeSizer tests that ranges widen up to the max while they return few logs, and narrow when
+// they return many or are rejected, down to a single block and within the provider's limit.
+func TestRangeSizer(t *testing.T) {
+	sizer := NewRangeSizer(200)
+	assert.Equal(t, BlockRangeSize, sizer.Size())
+
+	previous, size := sizer.Observe(0)
+	assert.Equal(t, uint64(37), previous)
+	assert.Equal(t, uint64(75), size)
+	sizer.Observe(10)
+	_, size = sizer.Observe(10)
+	assert.Equal(t, uint64(200), size)
+
+	_, size = sizer.Observe(TargetRangeLogs)
+	assert.Equal(t, uint64(200), size, "a range near the target keeps its size")
+	_, size = sizer.Observe(TargetRangeLogs + 1)
+	assert.Equal(t, uint64(100), size)
+
+	for i := 0; i < 6; i++ {
+		_, _, narrowed := sizer.Narrow(0)
+		assert.True(t, narrowed)
+	}
+	previous, size, narrowed := sizer.Narrow(0)
+	assert.Equal(t, uint64(1), previous)
+	assert.Equal(t, uint64(0), size)
+	assert.True(t, narrowed)
+	_, _, narrowed = sizer.Narrow(0)
+	assert.False(t, narrowed, "a single block cannot be narrowed")
+
+	sizer = NewRangeSizer(2000)
+	sizer.Observe(0)
+	sizer.Observe(0)
+	previous, size, _ = sizer.Narrow(100)
+	assert.Equal(t, uint64(151), previous)
+	assert.Equal(t, uint64(75), size)
+	for i := 0; i < 5; i++ {
+		sizer.Observe(0)
+	}
+	assert.Equal(t, uint64(99), sizer.Size(), "ranges stay within the limit the provider told")
+
+	var unset *RangeSizer
+	assert.Equal(t, BlockRangeSize, unset.Size())
+	assert.Equal(t, DefaultMaxBlockRange, NewRangeSizer(0).max)
+}
@@ -0,0 +1,105 @@
+package ethindexa
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// handlerWildcard matches any contract, network or event in a handler key.
+const handlerWildcard = "*"
+
+// HandlerRegistry resolves "Contract:network:Event" keys to event handlers.
+// Any part of a registered key may be the wildcard "*", e.g. "USDC:*:Transfer" or "*:mainnet:Approval".
+// When several keys match, the most specific one wins; contract is more specific than network, and network than event.
+type HandlerRegistry struct {
+	mutex    sync.RWMutex
+	handlers map[string]EventHandler
+}
+
+// NewHandlerRegistry creates an empty registry.
+func NewHandlerRegistry() *HandlerRegistry {
+	return &HandlerRegistry{handlers: make(map[string]EventHandler)}
+}
+
+// Register adds or replaces the handler of a key.
+func (r *HandlerRegistry) Register(key string, handler EventHandler) error {
+	if handler == nil {
+		return fmt.Errorf("handler for %s is nil", key)
+	}
+	if err := validateHandlerKey(key); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers[key] = handler
+	return nil
+}
+
+// Unregister removes the handler of a key.
+func (r *HandlerRegistry) Unregister(key string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.handlers, key)
+}
+
+// Resolve returns the most specific handler registered for an event, or nil.
+func (r *HandlerRegistry) Resolve(contractName, networkName, eventName string) EventHandler {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// Candidates ordered from most to least specific
+	for _, contract := range []string{contractName, handlerWildcard} {
+		for _, network := range []string{networkName, handlerWildcard} {
+			for _, event := range []string{eventName, handlerWildcard} {
+				if handler, exists := r.handlers[handlerKey(contract, network, event)]; exists {
+					return handler
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// handlerKey builds the "Contract:network:Event" key of an event.
+func handlerKey(contractName, networkName, eventName string) string {
+	return fmt.Sprintf("%s:%s:%s", contractName, networkName, eventName)
+}
+
+// validateHandlerKey checks that a key has a non-empty contract, network and event part.
+func validateHandlerKey(key string) error {
+	parts := strings.Split(key, ":")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid handler key %q: expected Contract:network:Event", key)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return fmt.Errorf("invalid handler key %q: empty part", key)
+		}
+	}
+	return nil
+}
+
+// RegisterHandler registers a handler for a key that may contain wildcards.
+// It can be called while the indexer runs; events dispatched afterwards use the new handler.
+func (indexer *IndexerImpl) RegisterHandler(key string, handler EventHandler) error {
+	return indexer.Handlers.Register(key, handler)
+}
+
+// UnregisterHandler removes the handler registered for a key.
+func (indexer *IndexerImpl) UnregisterHandler(key string) {
+	indexer.Handlers.Unregister(key)
+}
+
+// handlerFor returns the handler of an event configuration.
+// A handler set on the configuration takes precedence over the registry.
+func (indexer *IndexerImpl) handlerFor(eventConfig *EventConfig) EventHandler {
+	if eventConfig.Handler != nil {
+		return eventConfig.Handler
+	}
+	if indexer.Handlers == nil {
+		return nil
+	}
+	return indexer.Handlers.Resolve(eventConfig.ContractName, eventConfig.NetworkName, eventConfig.EventName)
+}
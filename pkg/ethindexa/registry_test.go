@@ -0,0 +1,76 @@
+package ethindexa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// namedHandler returns a handler that records its name when called.
+func namedHandler(name string, called *string) EventHandler {
+	return func(idx *IndexerService, event Event) error {
+		*called = name
+		return nil
+	}
+}
+
+// TestHandlerRegistry_Resolve tests that the most specific matching key wins.
+func TestHandlerRegistry_Resolve(t *testing.T) {
+	registry := NewHandlerRegistry()
+	var called string
+
+	assert.NoError(t, registry.Register("USDC:mainnet:Transfer", namedHandler("exact", &called)))
+	assert.NoError(t, registry.Register("USDC:*:Transfer", namedHandler("contract", &called)))
+	assert.NoError(t, registry.Register("*:mainnet:Approval", namedHandler("network", &called)))
+
+	tests := []struct {
+		contract, network, event string
+		expected                 string
+	}{
+		{"USDC", "mainnet", "Transfer", "exact"},
+		{"USDC", "base", "Transfer", "contract"},
+		{"USDC", "mainnet", "Approval", "network"},
+		{"AAVE", "mainnet", "Approval", "network"},
+	}
+	for _, test := range tests {
+		called = ""
+		handler := registry.Resolve(test.contract, test.network, test.event)
+		if assert.NotNil(t, handler, "%s:%s:%s", test.contract, test.network, test.event) {
+			handler(nil, Event{})
+			assert.Equal(t, test.expected, called)
+		}
+	}
+
+	assert.Nil(t, registry.Resolve("AAVE", "base", "Approval"))
+
+	registry.Unregister("USDC:mainnet:Transfer")
+	called = ""
+	registry.Resolve("USDC", "mainnet", "Transfer")(nil, Event{})
+	assert.Equal(t, "contract", called)
+}
+
+// TestHandlerRegistry_InvalidKey tests that malformed keys are rejected.
+func TestHandlerRegistry_InvalidKey(t *testing.T) {
+	registry := NewHandlerRegistry()
+	handler := func(idx *IndexerService, event Event) error { return nil }
+
+	assert.Error(t, registry.Register("USDC:Transfer", handler))
+	assert.Error(t, registry.Register("USDC::Transfer", handler))
+	assert.Error(t, registry.Register("USDC:mainnet:Transfer", nil))
+}
+
+// TestHandlerFor tests that a handler set on the event configuration overrides the registry.
+func TestHandlerFor(t *testing.T) {
+	indexer := &IndexerImpl{Handlers: NewHandlerRegistry()}
+	var called string
+
+	assert.NoError(t, indexer.RegisterHandler("*:*:Transfer", namedHandler("registry", &called)))
+
+	eventConfig := &EventConfig{ContractName: "USDC", NetworkName: "base", EventName: "Transfer"}
+	indexer.handlerFor(eventConfig)(nil, Event{})
+	assert.Equal(t, "registry", called)
+
+	eventConfig.Handler = namedHandler("config", &called)
+	indexer.handlerFor(eventConfig)(nil, Event{})
+	assert.Equal(t, "config", called)
+}
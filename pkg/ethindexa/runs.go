@@ -0,0 +1,178 @@
+package ethindexa
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+)
+
+// HandlerRunStatus is the outcome of a handler run.
+type HandlerRunStatus string
+
+const (
+	RunOK      HandlerRunStatus = "ok"
+	RunError   HandlerRunStatus = "error"   // the handler returned an error
+	RunTimeout HandlerRunStatus = "timeout" // the handler exceeded its deadline
+	RunPanic   HandlerRunStatus = "panic"   // the handler panicked
+)
+
+var (
+	// HandlerRunBufferSize is the number of runs waiting to be written before new ones are dropped.
+	HandlerRunBufferSize = 10000
+	// HandlerRunFlushInterval is how often buffered runs are written to handler_runs.
+	HandlerRunFlushInterval = time.Second
+	// HandlerRunRetention is how long runs are kept in handler_runs.
+	HandlerRunRetention = 7 * 24 * time.Hour
+)
+
+// handlerRunBatchSize is the maximum number of runs written by a single insert.
+const handlerRunBatchSize = 1000
+
+// HandlerRun is the outcome of a handler on an event.
+type HandlerRun struct {
+	HandlerKey      string
+	Network         string
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint
+	Status          HandlerRunStatus
+	Error           string
+	Duration        time.Duration
+	StartedAt       time.Time
+}
+
+// HandlerRuns writes handler runs to handler_runs in batches, off the handling path: Record only
+// buffers a run, and runs are dropped, and counted, when the buffer is full. A nil HandlerRuns
+// records nothing.
+type HandlerRuns struct {
+	db      pg.PgxPool
+	runs    chan HandlerRun
+	dropped atomic.Uint64
+}
+
+// NewHandlerRuns creates a HandlerRuns writing to db once Run is started.
+func NewHandlerRuns(db pg.PgxPool) *HandlerRuns {
+	return &HandlerRuns{db: db, runs: make(chan HandlerRun, HandlerRunBufferSize)}
+}
+
+// Record buffers a run to be written.
+func (r *HandlerRuns) Record(run HandlerRun) {
+	if r == nil {
+		return
+	}
+	select {
+	case r.runs <- run:
+	default:
+		if r.dropped.Add(1)%1000 == 1 {
+			logger.Warnf("Handler run buffer is full, %d runs dropped so far", r.dropped.Load())
+		}
+	}
+}
+
+// Dropped returns the number of runs dropped because the buffer was full.
+func (r *HandlerRuns) Dropped() uint64 {
+	if r == nil {
+		return 0
+	}
+	return r.dropped.Load()
+}
+
+// Run writes the buffered runs every HandlerRunFlushInterval and deletes the runs older than
+// HandlerRunRetention every hour, until ctx is cancelled; the runs still buffered are then written.
+func (r *HandlerRuns) Run(ctx context.Context) {
+	flush := time.NewTicker(HandlerRunFlushInterval)
+	defer flush.Stop()
+	prune := time.NewTicker(time.Hour)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Write what is left with a fresh deadline, as ctx is already cancelled
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			r.flush(flushCtx)
+			cancel()
+			return
+		case <-flush.C:
+			r.flush(ctx)
+		case <-prune.C:
+			if err := r.Prune(ctx, time.Now().Add(-HandlerRunRetention)); err != nil {
+				logger.Warnf("Failed to prune handler runs: %v", err)
+			}
+		}
+	}
+}
+
+// flush writes the buffered runs in batches of up to handlerRunBatchSize.
+func (r *HandlerRuns) flush(ctx context.Context) {
+	for {
+		batch := make([]HandlerRun, 0, handlerRunBatchSize)
+	fill:
+		for len(batch) < handlerRunBatchSize {
+			select {
+			case run := <-r.runs:
+				batch = append(batch, run)
+			default:
+				break fill
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := r.Write(ctx, batch); err != nil {
+			logger.Warnf("Failed to write %d handler runs: %v", len(batch), err)
+			return
+		}
+		if len(batch) < handlerRunBatchSize {
+			return
+		}
+	}
+}
+
+// Write inserts runs into handler_runs with a single statement.
+func (r *HandlerRuns) Write(ctx context.Context, runs []HandlerRun) error {
+	const query = `
+		INSERT INTO handler_runs (handler_key, network, block_number, transaction_hash, log_index, status, error, duration_ms, started_at)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::int8[], $4::text[], $5::int4[], $6::text[], $7::text[], $8::float8[], $9::timestamptz[])
+	`
+
+	keys := make([]string, len(runs))
+	networks := make([]string, len(runs))
+	blocks := make([]int64, len(runs))
+	hashes := make([]string, len(runs))
+	logIndexes := make([]int32, len(runs))
+	statuses := make([]string, len(runs))
+	errs := make([]string, len(runs))
+	durations := make([]float64, len(runs))
+	startedAt := make([]time.Time, len(runs))
+	for i, run := range runs {
+		keys[i] = run.HandlerKey
+		networks[i] = run.Network
+		blocks[i] = int64(run.BlockNumber)
+		hashes[i] = run.TransactionHash
+		logIndexes[i] = int32(run.LogIndex)
+		statuses[i] = string(run.Status)
+		errs[i] = run.Error
+		durations[i] = float64(run.Duration) / float64(time.Millisecond)
+		startedAt[i] = run.StartedAt
+	}
+
+	if _, err := r.db.Exec(ctx, query, keys, networks, blocks, hashes, logIndexes, statuses, errs, durations, startedAt); err != nil {
+		return fmt.Errorf("failed to insert handler runs: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes the runs started before cutoff.
+func (r *HandlerRuns) Prune(ctx context.Context, cutoff time.Time) error {
+	const query = `DELETE FROM handler_runs WHERE started_at < $1`
+
+	if _, err := r.db.Exec(ctx, query, cutoff); err != nil {
+		return fmt.Errorf("failed to prune handler runs: %w", err)
+	}
+	return nil
+}
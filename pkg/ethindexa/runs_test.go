@@ -0,0 +1,109 @@
+package ethindexa
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestRunHandler_Error tests that a handler returning an error is counted as a failure, recorded
+// as a run and reported with its failure rate.
+func TestRunHandler_Error(t *testing.T) {
+	indexer := &IndexerImpl{
+		Metrics: NewHandlerMetrics(),
+		Runs:    NewHandlerRuns(nil),
+	}
+
+	fail := true
+	task := HandlerTask{
+		Network:     "mainnet",
+		HandlerKey:  "UniswapV2:mainnet:Swap",
+		BlockNumber: 100,
+		EventHandler: func(idx *IndexerService, event Event) error {
+			if fail {
+				return errors.New("no pair")
+			}
+			return nil
+		},
+	}
+	task.Event.LogIndex = 4
+
+	indexer.runHandler(context.Background(), task)
+	fail = false
+	indexer.runHandler(context.Background(), task)
+	indexer.runHandler(context.Background(), task)
+	indexer.runHandler(context.Background(), task)
+
+	stats := indexer.Metrics.Snapshot()[task.HandlerKey]
+	assert.Equal(t, uint64(4), stats.Runs)
+	assert.Equal(t, uint64(1), stats.Errors)
+	assert.Equal(t, uint64(1), stats.Failures)
+	assert.Equal(t, 0.25, stats.FailureRate)
+
+	if assert.Len(t, indexer.Runs.runs, 4) {
+		run := <-indexer.Runs.runs
+		assert.Equal(t, RunError, run.Status)
+		assert.Equal(t, "handler UniswapV2:mainnet:Swap failed at block 100: no pair", run.Error)
+		assert.Equal(t, uint64(100), run.BlockNumber)
+		assert.Equal(t, uint(4), run.LogIndex)
+		assert.Equal(t, RunOK, (<-indexer.Runs.runs).Status)
+	}
+}
+
+// TestHandlerRuns_Record tests that runs are dropped and counted once the buffer is full.
+func TestHandlerRuns_Record(t *testing.T) {
+	size := HandlerRunBufferSize
+	HandlerRunBufferSize = 2
+	defer func() { HandlerRunBufferSize = size }()
+
+	runs := NewHandlerRuns(nil)
+	for i := 0; i < 5; i++ {
+		runs.Record(HandlerRun{HandlerKey: "USDC:mainnet:Transfer"})
+	}
+	assert.Len(t, runs.runs, 2)
+	assert.Equal(t, uint64(3), runs.Dropped())
+
+	var nilRuns *HandlerRuns
+	nilRuns.Record(HandlerRun{})
+	assert.Equal(t, uint64(0), nilRuns.Dropped())
+}
+
+// TestHandlerRuns_Flush tests that buffered runs are written by a single insert, and kept in
+// the buffer's order.
+func TestHandlerRuns_Flush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	runs := NewHandlerRuns(mockDB)
+
+	startedAt := time.Unix(1727740800, 0)
+	runs.Record(HandlerRun{HandlerKey: "USDC:mainnet:Transfer", Network: "mainnet", BlockNumber: 100, TransactionHash: "0x01", LogIndex: 2, Status: RunOK, Duration: 1500 * time.Microsecond, StartedAt: startedAt})
+	runs.Record(HandlerRun{HandlerKey: "UniswapV2:mainnet:Swap", Network: "mainnet", BlockNumber: 101, TransactionHash: "0x02", LogIndex: 0, Status: RunTimeout, Error: "timed out", Duration: 30 * time.Second, StartedAt: startedAt})
+
+	mockDB.EXPECT().Exec(ctx, gomock.Any(),
+		[]string{"USDC:mainnet:Transfer", "UniswapV2:mainnet:Swap"},
+		[]string{"mainnet", "mainnet"},
+		[]int64{100, 101},
+		[]string{"0x01", "0x02"},
+		[]int32{2, 0},
+		[]string{"ok", "timeout"},
+		[]string{"", "timed out"},
+		[]float64{1.5, 30000},
+		[]time.Time{startedAt, startedAt},
+	).Return(pgconn.CommandTag{}, nil)
+
+	runs.flush(ctx)
+	assert.Empty(t, runs.runs)
+
+	// Nothing buffered, nothing written
+	runs.flush(ctx)
+}
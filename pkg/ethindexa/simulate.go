@@ -0,0 +1,168 @@
+package ethindexa
+
+import (
+	"context"
+	"fmt"
+
+	"hw/internal/service"
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/pg"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SimulatedEvent is the outcome of a log of a simulated block range.
+type SimulatedEvent struct {
+	BlockNumber uint64 `json:"block_number"`
+	Transaction string `json:"transaction"`
+	TracedEvent
+}
+
+// Simulation is the outcome of the logs of a simulated block range, in chain order.
+type Simulation struct {
+	Network   string           `json:"network"`
+	FromBlock uint64           `json:"from_block"`
+	ToBlock   uint64           `json:"to_block"`
+	Events    []SimulatedEvent `json:"events"`
+}
+
+// NewSimulator creates an indexer for the contracts of a single network of config, reading the
+// chain from forkURL, e.g. an Anvil node forked from the network at a chosen block, so handlers
+// calling contracts see the state of the fork. No consumer is started: blocks are only run by
+// Simulate. db should be bound to a scratch schema (see pg.CreateScratchSchema and
+// pg.WithSearchPath), as handlers write to it, and service should use the same database.
+func NewSimulator(ctx context.Context, config *Config, networkName, forkURL string, db *pg.PostgresDB, service service.Service, handlers map[string]EventHandler) (*IndexerImpl, error) {
+	netConfig, exists := config.Networks[networkName]
+	if !exists {
+		return nil, fmt.Errorf("network configuration not found: %s", networkName)
+	}
+	netConfig.RPCURL = forkURL
+
+	// Only the contracts deployed on the network are kept
+	forked := &Config{
+		Networks:  map[string]NetworkConfig{networkName: netConfig},
+		Contracts: make(map[string]ContractConfig),
+	}
+	for contractName, contractConfig := range config.Contracts {
+		networkConfig, exists := contractConfig.Networks[networkName]
+		if !exists {
+			continue
+		}
+		contractConfig.Networks = map[string]ContractNetworkConfig{networkName: networkConfig}
+		forked.Contracts[contractName] = contractConfig
+	}
+
+	indexer := &IndexerImpl{
+		Clients:     make(map[string]*ethclient.Client),
+		Events:      make(map[string]map[common.Hash][]*EventConfig),
+		Service:     service,
+		MainCtx:     ctx,
+		CancelFunc:  func() {},
+		Metrics:     NewHandlerMetrics(),
+		Pipeline:    NewPipelineMetrics(),
+		Stats:       NewStatusTracker(),
+		Quarantine:  NewHandlerQuarantine(DefaultQuarantineThreshold),
+		Throttles:   make(map[string]*FetchThrottle),
+		RangeSizes:  make(map[string]*RangeSizer),
+		Handlers:    NewHandlerRegistry(),
+		StartBlocks: NewStartBlockResolver(nil),
+		Pauses:      NewPauses(nil),
+		Store:       NewMemoryEntityStore(),
+	}
+	if db != nil {
+		indexer.StartBlocks = NewStartBlockResolver(db)
+		indexer.Store = NewPostgresEntityStore(db)
+		indexer.DB = db
+	}
+
+	for key, handler := range handlers {
+		if err := indexer.RegisterHandler(key, handler); err != nil {
+			return nil, err
+		}
+	}
+	if err := indexer.configureEvents(ctx, forked); err != nil {
+		return nil, err
+	}
+	if _, exists := indexer.Clients[networkName]; !exists {
+		return nil, fmt.Errorf("no contract is configured on network %s", networkName)
+	}
+	return indexer, nil
+}
+
+// Simulate fetches the logs of a network between fromBlock and toBlock inclusive and runs them
+// through decoding and the registered handlers in chain order, one at a time. Unlike TraceTx, the
+// writes of the handlers are applied, to the database of the indexer, so a simulator bound to a
+// scratch schema leaves the writes there to be inspected. Quarantined and paused handlers are
+// run too, and nothing is counted in the metrics or recorded in handler_runs.
+func (indexer *IndexerImpl) Simulate(ctx context.Context, networkName string, fromBlock, toBlock uint64) (*Simulation, error) {
+	client, exists := indexer.Clients[networkName]
+	if !exists {
+		return nil, fmt.Errorf("unknown network: %s", networkName)
+	}
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("invalid block range: %d to %d", fromBlock, toBlock)
+	}
+
+	eventsTask, err := indexer.fetchRange(ctx, networkName, client, indexer.Events[networkName], fromBlock, toBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blocks %d to %d: %w", fromBlock, toBlock, err)
+	}
+	sortLogs(eventsTask.Logs)
+
+	simulation := &Simulation{Network: networkName, FromBlock: fromBlock, ToBlock: toBlock, Events: []SimulatedEvent{}}
+	for _, logEntry := range eventsTask.Logs {
+		if len(logEntry.Topics) == 0 {
+			continue
+		}
+		block := eventsTask.Blocks[fmt.Sprintf("%d", logEntry.BlockNumber)]
+
+		for _, eventConfig := range indexer.Events[networkName][logEntry.Topics[0]] {
+			if logEntry.Address != eventConfig.ContractAddress {
+				continue
+			}
+			event := SimulatedEvent{
+				BlockNumber: logEntry.BlockNumber,
+				Transaction: logEntry.TxHash.Hex(),
+				TracedEvent: TracedEvent{
+					LogIndex: logEntry.Index,
+					Address:  logEntry.Address.Hex(),
+					Contract: eventConfig.ContractName,
+					Event:    eventConfig.EventName,
+					Handler:  eventConfig.HandlerKey,
+				},
+			}
+
+			eventArgs, err := eventConfig.extractEventArgs(logEntry)
+			if err != nil {
+				event.Skipped = "decoding failed: " + err.Error()
+				simulation.Events = append(simulation.Events, event)
+				continue
+			}
+			event.Args = eventArgs
+
+			eventHandler := indexer.handlerFor(eventConfig)
+			switch {
+			case logEntry.BlockNumber < eventConfig.StartBlock.Uint64():
+				event.Skipped = fmt.Sprintf("before start block %d", eventConfig.StartBlock)
+			case eventHandler == nil:
+				event.Skipped = "no handler registered"
+			case !matchFilters(eventConfig.Filters, eventArgs):
+				event.Skipped = "rejected by filters"
+			case block == nil:
+				event.Skipped = fmt.Sprintf("block %d not found", logEntry.BlockNumber)
+			default:
+				transaction := ethclient.GetTransactionResponse{}
+				for _, tx := range block.Result.Transactions {
+					if tx.Hash == logEntry.TxHash.Hex() {
+						transaction = tx
+						break
+					}
+				}
+				task := indexer.newHandlerTask(networkName, eventConfig, eventHandler, logEntry, *block, transaction, eventArgs)
+				traceHandler(ctx, task, &event.TracedEvent)
+			}
+			simulation.Events = append(simulation.Events, event)
+		}
+	}
+	return simulation, nil
+}
@@ -0,0 +1,102 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/ethindexa/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSimulate tests that the logs of a block range are run through their handlers in chain
+// order and that their writes are applied.
+func TestSimulate(t *testing.T) {
+	parsedABI, err := utils.LoadABI("erc20_usdc")
+	assert.NoError(t, err)
+	transfer := parsedABI.Events["Transfer"]
+	topics := []common.Hash{transfer.ID, common.BytesToHash(traceFrom.Bytes()), common.BytesToHash(traceTo.Bytes())}
+	pack := func(value int64) []byte {
+		data, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(value))
+		assert.NoError(t, err)
+		return data
+	}
+
+	blockHash := common.HexToHash("0xb1")
+	otherTx := common.HexToHash("0x02")
+	logsJSON, err := json.Marshal([]types.Log{
+		{Address: traceUSDC, Topics: topics, Data: pack(2), BlockNumber: 101, BlockHash: blockHash, TxHash: otherTx, Index: 1},
+		{Address: traceUSDC, Topics: topics, Data: pack(1), BlockNumber: 100, BlockHash: blockHash, TxHash: traceTxHash, Index: 4},
+		{Address: traceUSDC, Topics: []common.Hash{common.HexToHash("0xdead")}, BlockNumber: 100, BlockHash: blockHash, TxHash: traceTxHash, Index: 3},
+	})
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		result := json.RawMessage(`null`)
+		switch req.Method {
+		case "eth_getLogs":
+			result = logsJSON
+		case "eth_getBlockByHash":
+			result = json.RawMessage(`{"number":"0x64","timestamp":"0x66fb3c00","transactions":[{"hash":"` + traceTxHash.Hex() + `","from":"` + traceFrom.Hex() + `"}]}`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer server.Close()
+	client, err := ethclient.NewClient("mainnet", server.URL)
+	assert.NoError(t, err)
+
+	store := NewMemoryEntityStore()
+	indexer := &IndexerImpl{
+		Clients: map[string]*ethclient.Client{"mainnet": client},
+		Events: map[string]map[common.Hash][]*EventConfig{
+			"mainnet": {transfer.ID: {
+				{ContractName: "USDC", NetworkName: "mainnet", ContractAddress: traceUSDC, ContractABI: parsedABI, EventName: "Transfer", HandlerKey: "USDC:mainnet:Transfer", StartBlock: big.NewInt(1)},
+			}},
+		},
+		Store:      store,
+		Metrics:    NewHandlerMetrics(),
+		Pipeline:   NewPipelineMetrics(),
+		Throttles:  map[string]*FetchThrottle{"mainnet": NewFetchThrottle(0, 0)},
+		RangeSizes: map[string]*RangeSizer{"mainnet": NewRangeSizer(0)},
+		Handlers:   NewHandlerRegistry(),
+	}
+	var order []string
+	assert.NoError(t, indexer.RegisterHandler("USDC:mainnet:Transfer", func(idx *IndexerService, event Event) error {
+		order = append(order, event.TransactionHash.Hex())
+		assert.Equal(t, idx.Store, store, "handlers write to the store of the simulator")
+		return idx.Store.Upsert(event.Ctx, "Transfer", event.LogKey(), Fields{"value": event.Args["value"]})
+	}))
+
+	_, err = indexer.Simulate(context.Background(), "mainnet", 101, 100)
+	assert.ErrorContains(t, err, "invalid block range")
+
+	simulation, err := indexer.Simulate(context.Background(), "mainnet", 100, 101)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{traceTxHash.Hex(), otherTx.Hex()}, order, "logs are run in chain order")
+	if assert.Len(t, simulation.Events, 2, "the log of no configured event is left out") {
+		assert.Equal(t, uint64(100), simulation.Events[0].BlockNumber)
+		assert.Equal(t, traceTxHash.Hex(), simulation.Events[0].Transaction)
+		assert.Equal(t, RunOK, simulation.Events[0].Status)
+		assert.Equal(t, big.NewInt(1), simulation.Events[0].Args["value"])
+		assert.Equal(t, RunOK, simulation.Events[1].Status)
+	}
+
+	fields, err := store.Get(context.Background(), "Transfer", "mainnet:"+traceTxHash.Hex()+":4")
+	assert.NoError(t, err, "the writes are applied")
+	assert.Equal(t, json.Number("1"), fields["value"])
+	assert.Empty(t, indexer.Metrics.Snapshot(), "simulated runs are not counted")
+}
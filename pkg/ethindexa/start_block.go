@@ -0,0 +1,222 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"hw/pkg/ethindexa/utils"
+	"hw/pkg/logger"
+	"hw/pkg/pg"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Aliases of the startBlock of a contract.
+const (
+	// StartBlockLatest starts a contract at the head block of its network when it is first indexed.
+	StartBlockLatest = "latest"
+	// StartBlockGenesis starts a contract at block 0.
+	StartBlockGenesis = "genesis"
+)
+
+// startBlockDeployment is the source of start blocks found by deployment block discovery.
+const startBlockDeployment = "deployment"
+
+// StartBlock is the startBlock of a contract on a network: a block number, or one of the aliases
+// StartBlockLatest and StartBlockGenesis. It is written in config.json as a number or a string.
+// Without one, or at 0, the contract starts at the block it was deployed in.
+type StartBlock struct {
+	Number int64
+	Alias  string // empty for a block number
+}
+
+// StartBlockAt returns the start block of a block number.
+func StartBlockAt(number int64) StartBlock {
+	return StartBlock{Number: number}
+}
+
+// UnmarshalJSON decodes a block number or an alias. Unknown aliases are kept and reported by Validate.
+func (b *StartBlock) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*b = StartBlock{}
+		return json.Unmarshal(data, &b.Alias)
+	}
+	*b = StartBlock{}
+	return json.Unmarshal(data, &b.Number)
+}
+
+// MarshalJSON encodes the alias, or the block number without one.
+func (b StartBlock) MarshalJSON() ([]byte, error) {
+	if b.Alias != "" {
+		return json.Marshal(b.Alias)
+	}
+	return json.Marshal(b.Number)
+}
+
+func (b StartBlock) String() string {
+	if b.Alias != "" {
+		return b.Alias
+	}
+	return strconv.FormatInt(b.Number, 10)
+}
+
+// validate returns why the start block is invalid, or an empty string.
+func (b StartBlock) validate() string {
+	switch {
+	case b.Alias != "" && b.Alias != StartBlockLatest && b.Alias != StartBlockGenesis:
+		return fmt.Sprintf("invalid start block %q, must be a block number, %q or %q", b.Alias, StartBlockLatest, StartBlockGenesis)
+	case b.Number < 0:
+		return "must not be negative"
+	}
+	return ""
+}
+
+// startBlockReader reads the head block of a network and the code of contracts.
+type startBlockReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	utils.CodeReader
+}
+
+// StartBlockResolver resolves the start blocks of contracts to block numbers. The head block a
+// contract starting at StartBlockLatest was first indexed from is stored in indexer_start_blocks,
+// so after a restart it resumes from there instead of skipping the blocks mined meanwhile. So is the
+// deployment block of a contract without a start block, which is only searched for once.
+// Without a database they are kept for the life of the resolver.
+type StartBlockResolver struct {
+	db       pg.PgxPool
+	mutex    sync.Mutex
+	resolved map[string]uint64
+}
+
+// NewStartBlockResolver creates a StartBlockResolver storing resolved blocks in db, which may be nil.
+func NewStartBlockResolver(db pg.PgxPool) *StartBlockResolver {
+	return &StartBlockResolver{db: db, resolved: make(map[string]uint64)}
+}
+
+// Resolve returns the block number a contract at address on a network starts from.
+func (r *StartBlockResolver) Resolve(ctx context.Context, client startBlockReader, network string, address common.Address, startBlock StartBlock) (uint64, error) {
+	switch startBlock.Alias {
+	case "":
+		if startBlock.Number == 0 {
+			return r.resolveDeployment(ctx, client, network, address)
+		}
+		return uint64(startBlock.Number), nil
+	case StartBlockGenesis:
+		return 0, nil
+	case StartBlockLatest:
+		return r.resolveStored(ctx, network, address, StartBlockLatest, func() (uint64, error) {
+			return headBlock(ctx, client, network)
+		})
+	}
+	return 0, fmt.Errorf("invalid start block %q", startBlock.Alias)
+}
+
+// resolveDeployment returns the deployment block of a contract. When it cannot be found the
+// contract starts at genesis, and the search is tried again on the next start.
+func (r *StartBlockResolver) resolveDeployment(ctx context.Context, client startBlockReader, network string, address common.Address) (uint64, error) {
+	block, err := r.resolveStored(ctx, network, address, startBlockDeployment, func() (uint64, error) {
+		head, err := headBlock(ctx, client, network)
+		if err != nil {
+			return 0, err
+		}
+		return utils.DeploymentBlock(ctx, client, address, head)
+	})
+	if err != nil && ctx.Err() == nil {
+		logger.Warnf("Failed to find the deployment block of %s on %s, starting at genesis: %v", address.Hex(), network, err)
+		return 0, nil
+	}
+	return block, err
+}
+
+// headBlock returns the number of the head block of a network.
+func headBlock(ctx context.Context, client startBlockReader, network string) (uint64, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get head block of %s: %w", network, err)
+	}
+	return header.Number.Uint64(), nil
+}
+
+// resolveStored returns the start block of a contract stored from source, or stores the one
+// resolve returns.
+func (r *StartBlockResolver) resolveStored(ctx context.Context, network string, address common.Address, source string, resolve func() (uint64, error)) (uint64, error) {
+	key := network + ":" + address.Hex() + ":" + source
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if block, exists := r.resolved[key]; exists {
+		return block, nil
+	}
+
+	if r.db != nil {
+		block, found, err := r.load(ctx, network, address, source)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			r.resolved[key] = block
+			return block, nil
+		}
+	}
+
+	block, err := resolve()
+	if err != nil {
+		return 0, err
+	}
+
+	if r.db != nil {
+		// Another instance may have stored its block first; every instance starts from the same block
+		if block, err = r.store(ctx, network, address, block, source); err != nil {
+			return 0, err
+		}
+	}
+	r.resolved[key] = block
+	return block, nil
+}
+
+// load reads the start block of a contract stored from source.
+func (r *StartBlockResolver) load(ctx context.Context, network string, address common.Address, source string) (uint64, bool, error) {
+	const query = `SELECT start_block FROM indexer_start_blocks WHERE network = $1 AND address = $2 AND source = $3`
+
+	rows, err := r.db.Query(ctx, query, network, address.Hex(), source)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to load start block of %s on %s: %w", address.Hex(), network, err)
+	}
+	defer rows.Close()
+
+	var block int64
+	found := rows.Next()
+	if found {
+		if err := rows.Scan(&block); err != nil {
+			return 0, false, fmt.Errorf("failed to scan start block: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, false, fmt.Errorf("failed to load start block of %s on %s: %w", address.Hex(), network, err)
+	}
+	return uint64(block), found, nil
+}
+
+// store stores the start block of a contract unless one from the same source is stored already,
+// and returns the stored one. A block stored from another source is replaced.
+func (r *StartBlockResolver) store(ctx context.Context, network string, address common.Address, block uint64, source string) (uint64, error) {
+	const query = `
+		INSERT INTO indexer_start_blocks (network, address, start_block, source)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (network, address) DO UPDATE SET
+			start_block = CASE WHEN indexer_start_blocks.source = EXCLUDED.source THEN indexer_start_blocks.start_block ELSE EXCLUDED.start_block END,
+			resolved_at = CASE WHEN indexer_start_blocks.source = EXCLUDED.source THEN indexer_start_blocks.resolved_at ELSE EXCLUDED.resolved_at END,
+			source = EXCLUDED.source
+		RETURNING start_block
+	`
+
+	var stored int64
+	if err := r.db.QueryRow(ctx, query, network, address.Hex(), int64(block), source).Scan(&stored); err != nil {
+		return 0, fmt.Errorf("failed to store start block of %s on %s: %w", address.Hex(), network, err)
+	}
+	return uint64(stored), nil
+}
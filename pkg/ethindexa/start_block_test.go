@@ -0,0 +1,187 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeHead serves a head block and the code of contracts deployed at a block, and counts the
+// head requests.
+type fakeHead struct {
+	number   int64
+	deployed map[common.Address]int64
+	calls    int
+}
+
+func (h *fakeHead) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	h.calls++
+	return &types.Header{Number: big.NewInt(h.number)}, nil
+}
+
+func (h *fakeHead) CodeAt(ctx context.Context, account common.Address, number *big.Int) ([]byte, error) {
+	if block, exists := h.deployed[account]; exists && number.Int64() >= block {
+		return []byte{0x60, 0x80}, nil
+	}
+	return nil, nil
+}
+
+// TestStartBlock_JSON tests that start blocks are decoded from numbers and aliases and encoded back.
+func TestStartBlock_JSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    StartBlock
+		problem string
+	}{
+		{json: `20933132`, want: StartBlockAt(20933132)},
+		{json: `"latest"`, want: StartBlock{Alias: StartBlockLatest}},
+		{json: `"genesis"`, want: StartBlock{Alias: StartBlockGenesis}},
+		{json: `"soon"`, want: StartBlock{Alias: "soon"}, problem: `invalid start block "soon", must be a block number, "latest" or "genesis"`},
+		{json: `-1`, want: StartBlockAt(-1), problem: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var startBlock StartBlock
+			assert.NoError(t, json.Unmarshal([]byte(tt.json), &startBlock))
+			assert.Equal(t, tt.want, startBlock)
+			assert.Equal(t, tt.problem, startBlock.validate())
+
+			data, err := json.Marshal(startBlock)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(data))
+		})
+	}
+}
+
+// TestStartBlockResolver tests that numbers and genesis resolve without the chain, and latest to the
+// head block once.
+func TestStartBlockResolver(t *testing.T) {
+	ctx := context.Background()
+	head := &fakeHead{number: 21000000}
+	resolver := NewStartBlockResolver(nil)
+	address := common.HexToAddress("0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc")
+
+	block, err := resolver.Resolve(ctx, head, "mainnet", address, StartBlockAt(20933132))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20933132), block)
+
+	block, err = resolver.Resolve(ctx, head, "mainnet", address, StartBlock{Alias: StartBlockGenesis})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), block)
+	assert.Equal(t, 0, head.calls)
+
+	block, err = resolver.Resolve(ctx, head, "mainnet", address, StartBlock{Alias: StartBlockLatest})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(21000000), block)
+
+	head.number = 21000100
+	block, err = resolver.Resolve(ctx, head, "mainnet", address, StartBlock{Alias: StartBlockLatest})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(21000000), block, "latest is resolved once")
+	assert.Equal(t, 1, head.calls)
+}
+
+// TestStartBlockResolver_Stored tests that latest resumes from the stored block, and that the head is
+// stored when none is.
+func TestStartBlockResolver_Stored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	head := &fakeHead{number: 21000000}
+	stored := common.HexToAddress("0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc")
+	added := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "mainnet", stored.Hex(), StartBlockLatest).Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(true)
+	mockRows.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*int64)) = 20990000
+		return nil
+	})
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+
+	resolver := NewStartBlockResolver(mockDB)
+	block, err := resolver.Resolve(ctx, head, "mainnet", stored, StartBlock{Alias: StartBlockLatest})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20990000), block)
+	assert.Equal(t, 0, head.calls)
+
+	emptyRows := pgMock.NewMockPgxRows(ctrl)
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "mainnet", added.Hex(), StartBlockLatest).Return(emptyRows, nil)
+	emptyRows.EXPECT().Next().Return(false)
+	emptyRows.EXPECT().Err().Return(nil)
+	emptyRows.EXPECT().Close()
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", added.Hex(), int64(21000000), StartBlockLatest).Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*int64)) = 21000000
+		return nil
+	})
+
+	block, err = resolver.Resolve(ctx, head, "mainnet", added, StartBlock{Alias: StartBlockLatest})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(21000000), block)
+	assert.Equal(t, 1, head.calls)
+}
+
+// TestStartBlockResolver_Deployment tests that contracts without a start block start at their
+// deployment block, found once, and at genesis when there is no code at the address.
+func TestStartBlockResolver_Deployment(t *testing.T) {
+	ctx := context.Background()
+	address := common.HexToAddress("0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc")
+	head := &fakeHead{number: 21000000, deployed: map[common.Address]int64{address: 10008355}}
+	resolver := NewStartBlockResolver(nil)
+
+	block, err := resolver.Resolve(ctx, head, "mainnet", address, StartBlock{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10008355), block)
+
+	block, err = resolver.Resolve(ctx, head, "mainnet", address, StartBlockAt(0))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10008355), block)
+	assert.Equal(t, 1, head.calls, "the deployment block is searched once")
+
+	block, err = resolver.Resolve(ctx, head, "mainnet", common.HexToAddress("0x1"), StartBlock{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), block)
+}
+
+// TestStartBlockResolver_StoredDeployment tests that a found deployment block is stored.
+func TestStartBlockResolver_StoredDeployment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRows := pgMock.NewMockPgxRows(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	address := common.HexToAddress("0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc")
+	head := &fakeHead{number: 21000000, deployed: map[common.Address]int64{address: 10008355}}
+
+	mockDB.EXPECT().Query(ctx, gomock.Any(), "mainnet", address.Hex(), "deployment").Return(mockRows, nil)
+	mockRows.EXPECT().Next().Return(false)
+	mockRows.EXPECT().Err().Return(nil)
+	mockRows.EXPECT().Close()
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "mainnet", address.Hex(), int64(10008355), "deployment").Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+		*(dest[0].(*int64)) = 10008355
+		return nil
+	})
+
+	resolver := NewStartBlockResolver(mockDB)
+	block, err := resolver.Resolve(ctx, head, "mainnet", address, StartBlock{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10008355), block)
+}
@@ -0,0 +1,345 @@
+package ethindexa
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatusPath is the path of the indexer status endpoint served by StatusHandler.
+const StatusPath = "/admin/indexer/status"
+
+// IndexerStatus is the live indexing progress of every network.
+type IndexerStatus struct {
+	Networks    []NetworkStatus      `json:"networks"`
+	Quarantined []QuarantinedHandler `json:"quarantined"`
+	Leader      *LeaderState         `json:"leader,omitempty"` // set when leader election is enabled
+}
+
+// NetworkStatus is the indexing progress of a network. LastProcessedBlock is the end of the last
+// block range handed to the log processor, so Lag includes the network's finalityBlockCount.
+type NetworkStatus struct {
+	Network            string           `json:"network"`
+	LastProcessedBlock uint64           `json:"last_processed_block"`
+	HeadBlock          uint64           `json:"head_block"`
+	Lag                uint64           `json:"lag"`
+	Paused             bool             `json:"paused"`
+	EventQueueDepth    int              `json:"event_queue_depth"`
+	HandlerQueueDepth  int              `json:"handler_queue_depth"`
+	FetchConcurrency   int              `json:"fetch_concurrency"` // current limit of concurrent block requests
+	BlockRangeSize     uint64           `json:"block_range_size"`  // blocks after the first one of the next range
+	LastError          string           `json:"last_error,omitempty"`
+	LastErrorAt        *time.Time       `json:"last_error_at,omitempty"`
+	FetchErrors        uint64           `json:"fetch_errors"`            // fetch failures since the indexer started
+	FetchErrorsPerMin  uint64           `json:"fetch_errors_per_minute"` // fetch failures over the last minute
+	FailingSince       *time.Time       `json:"failing_since,omitempty"` // first failure of the fetcher's current retries
+	Contracts          []ContractStatus `json:"contracts"`
+}
+
+// ContractStatus is the handling progress of a contract on a network.
+type ContractStatus struct {
+	Contract        string     `json:"contract"`
+	LastEventBlock  uint64     `json:"last_event_block"`
+	EventsPerMinute uint64     `json:"events_per_minute"`
+	Paused          bool       `json:"paused"`
+	LastError       string     `json:"last_error,omitempty"`
+	LastErrorAt     *time.Time `json:"last_error_at,omitempty"`
+	// Handlers are the runs of the contract's handlers on the network since the indexer started.
+	Handlers []HandlerStatus `json:"handlers"`
+}
+
+// HandlerStatus is the outcome of the runs of a handler.
+type HandlerStatus struct {
+	Handler     string  `json:"handler"`
+	Runs        uint64  `json:"runs"`
+	Failures    uint64  `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// rateWindow is the number of one-second buckets events per minute are counted over.
+const rateWindow = 60
+
+// rateCounter counts events over the last minute in one-second buckets.
+type rateCounter struct {
+	buckets [rateWindow]uint64
+	seconds [rateWindow]int64
+}
+
+func (c *rateCounter) add(now time.Time) {
+	second := now.Unix()
+	i := second % rateWindow
+	if c.seconds[i] != second {
+		c.seconds[i] = second
+		c.buckets[i] = 0
+	}
+	c.buckets[i]++
+}
+
+func (c *rateCounter) perMinute(now time.Time) uint64 {
+	second := now.Unix()
+	var total uint64
+	for i := range c.buckets {
+		if second-c.seconds[i] < rateWindow {
+			total += c.buckets[i]
+		}
+	}
+	return total
+}
+
+type networkProgress struct {
+	processed    uint64
+	head         uint64
+	lastError    string
+	lastErrorAt  time.Time
+	fetchErrors  uint64
+	errorRate    rateCounter
+	failingSince time.Time
+	contracts    map[string]*contractProgress
+}
+
+type contractProgress struct {
+	lastBlock   uint64
+	rate        rateCounter
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// StatusTracker collects the progress published by the block fetchers and handlers.
+// A nil tracker records nothing.
+type StatusTracker struct {
+	mutex    sync.Mutex
+	networks map[string]*networkProgress
+	now      func() time.Time
+}
+
+// NewStatusTracker creates an empty StatusTracker.
+func NewStatusTracker() *StatusTracker {
+	return &StatusTracker{
+		networks: make(map[string]*networkProgress),
+		now:      time.Now,
+	}
+}
+
+// network returns the progress of a network, creating it. The caller must hold the mutex.
+func (t *StatusTracker) network(networkName string) *networkProgress {
+	progress, exists := t.networks[networkName]
+	if !exists {
+		progress = &networkProgress{contracts: make(map[string]*contractProgress)}
+		t.networks[networkName] = progress
+	}
+	return progress
+}
+
+// contract returns the progress of a contract, creating it. The caller must hold the mutex.
+func (t *StatusTracker) contract(networkName, contractName string) *contractProgress {
+	network := t.network(networkName)
+	progress, exists := network.contracts[contractName]
+	if !exists {
+		progress = &contractProgress{}
+		network.contracts[contractName] = progress
+	}
+	return progress
+}
+
+// SetHead records the latest block of a network.
+func (t *StatusTracker) SetHead(networkName string, head uint64) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.network(networkName).head = head
+}
+
+// SetProcessed records the last block handed to the log processor.
+func (t *StatusTracker) SetProcessed(networkName string, block uint64) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.network(networkName).processed = block
+}
+
+// Processed returns the last block handed to the log processor, or 0 before the first range.
+func (t *StatusTracker) Processed(networkName string) uint64 {
+	if t == nil {
+		return 0
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if network, exists := t.networks[networkName]; exists {
+		return network.processed
+	}
+	return 0
+}
+
+// NetworkError records a fetch failure of a network.
+func (t *StatusTracker) NetworkError(networkName string, err error) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	network := t.network(networkName)
+	network.lastError = err.Error()
+	network.lastErrorAt = t.now()
+	network.fetchErrors++
+	network.errorRate.add(network.lastErrorAt)
+	if network.failingSince.IsZero() {
+		network.failingSince = network.lastErrorAt
+	}
+}
+
+// NetworkRecovered records a fetch success of a network after failures.
+func (t *StatusTracker) NetworkRecovered(networkName string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.network(networkName).failingSince = time.Time{}
+}
+
+// Event records an event of a contract handled at block.
+func (t *StatusTracker) Event(networkName, contractName string, block uint64) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	contract := t.contract(networkName, contractName)
+	contract.rate.add(t.now())
+	if block > contract.lastBlock {
+		contract.lastBlock = block
+	}
+}
+
+// ContractError records a handling failure of a contract.
+func (t *StatusTracker) ContractError(networkName, contractName string, err error) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	contract := t.contract(networkName, contractName)
+	contract.lastError = err.Error()
+	contract.lastErrorAt = t.now()
+}
+
+// Snapshot returns the status of the given networks and their contracts, sorted by name.
+// Networks and contracts without recorded progress are included with zero values.
+func (t *StatusTracker) Snapshot(contracts map[string][]string) IndexerStatus {
+	if t == nil {
+		t = NewStatusTracker()
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	status := IndexerStatus{Networks: make([]NetworkStatus, 0, len(contracts))}
+	for _, networkName := range sortedKeys(contracts) {
+		network := t.network(networkName)
+		networkStatus := NetworkStatus{
+			Network:            networkName,
+			LastProcessedBlock: network.processed,
+			HeadBlock:          network.head,
+			LastError:          network.lastError,
+			LastErrorAt:        timePtr(network.lastErrorAt),
+			FetchErrors:        network.fetchErrors,
+			FetchErrorsPerMin:  network.errorRate.perMinute(now),
+			FailingSince:       timePtr(network.failingSince),
+			Contracts:          make([]ContractStatus, 0, len(contracts[networkName])),
+		}
+		if network.head > network.processed {
+			networkStatus.Lag = network.head - network.processed
+		}
+
+		contractNames := append([]string(nil), contracts[networkName]...)
+		sort.Strings(contractNames)
+		for _, contractName := range contractNames {
+			contract := t.contract(networkName, contractName)
+			networkStatus.Contracts = append(networkStatus.Contracts, ContractStatus{
+				Contract:        contractName,
+				LastEventBlock:  contract.lastBlock,
+				EventsPerMinute: contract.rate.perMinute(now),
+				LastError:       contract.lastError,
+				LastErrorAt:     timePtr(contract.lastErrorAt),
+			})
+		}
+		status.Networks = append(status.Networks, networkStatus)
+	}
+	return status
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// Status returns the live indexing status of every configured network and contract.
+func (indexer *IndexerImpl) Status() IndexerStatus {
+	contracts := make(map[string][]string, len(indexer.Events))
+	for networkName, eventConfigs := range indexer.Events {
+		seen := make(map[string]bool)
+		for _, configList := range eventConfigs {
+			for _, config := range configList {
+				if !seen[config.ContractName] {
+					seen[config.ContractName] = true
+					contracts[networkName] = append(contracts[networkName], config.ContractName)
+				}
+			}
+		}
+	}
+
+	status := indexer.Stats.Snapshot(contracts)
+	handlers := indexer.Metrics.Snapshot()
+	for i := range status.Networks {
+		networkName := status.Networks[i].Network
+		status.Networks[i].EventQueueDepth = len(indexer.EventQueues[networkName])
+		status.Networks[i].HandlerQueueDepth = len(indexer.HandlerQueues[networkName])
+		status.Networks[i].Paused = indexer.Pauses.Paused(networkName, "")
+		status.Networks[i].FetchConcurrency = indexer.Throttles[networkName].Limit()
+		status.Networks[i].BlockRangeSize = indexer.RangeSizes[networkName].Size()
+		for j := range status.Networks[i].Contracts {
+			contract := &status.Networks[i].Contracts[j]
+			contract.Paused = indexer.Pauses.Paused(networkName, contract.Contract)
+			contract.Handlers = handlerStatuses(handlers, contract.Contract, networkName)
+		}
+	}
+	status.Quarantined = indexer.Quarantine.List()
+	if indexer.Leader != nil {
+		leader := indexer.Leader.State()
+		status.Leader = &leader
+	}
+	return status
+}
+
+// handlerStatuses returns the runs of the handlers of a contract on a network, sorted by key.
+func handlerStatuses(handlers map[string]HandlerStats, contractName, networkName string) []HandlerStatus {
+	prefix := contractName + ":" + networkName + ":"
+	statuses := []HandlerStatus{}
+	for _, key := range sortedKeys(handlers) {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		stats := handlers[key]
+		statuses = append(statuses, HandlerStatus{Handler: key, Runs: stats.Runs, Failures: stats.Failures, FailureRate: stats.FailureRate})
+	}
+	return statuses
+}
+
+// StatusHandler serves the indexer status as JSON.
+func StatusHandler(indexer *IndexerImpl) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(indexer.Status()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestStatusTracker_EventsPerMinute tests that only events of the last minute are counted.
+func TestStatusTracker_EventsPerMinute(t *testing.T) {
+	now := time.Unix(1727740800, 0)
+	tracker := NewStatusTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.Event("mainnet", "USDC", 100)
+	tracker.Event("mainnet", "USDC", 101)
+	now = now.Add(30 * time.Second)
+	tracker.Event("mainnet", "USDC", 99)
+
+	status := tracker.Snapshot(map[string][]string{"mainnet": {"USDC"}})
+	assert.Equal(t, uint64(3), status.Networks[0].Contracts[0].EventsPerMinute)
+	assert.Equal(t, uint64(101), status.Networks[0].Contracts[0].LastEventBlock)
+
+	now = now.Add(45 * time.Second)
+	status = tracker.Snapshot(map[string][]string{"mainnet": {"USDC"}})
+	assert.Equal(t, uint64(1), status.Networks[0].Contracts[0].EventsPerMinute)
+}
+
+// TestStatusTracker_FetchErrors tests that fetch failures are counted over the last minute and
+// since the indexer started, and that a recovery ends the failing streak.
+func TestStatusTracker_FetchErrors(t *testing.T) {
+	now := time.Unix(1727740800, 0)
+	tracker := NewStatusTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.NetworkError("mainnet", errors.New("rpc unavailable"))
+	failingSince := now
+	now = now.Add(50 * time.Second)
+	tracker.NetworkError("mainnet", errors.New("rpc unavailable"))
+
+	status := tracker.Snapshot(map[string][]string{"mainnet": {}})
+	assert.Equal(t, uint64(2), status.Networks[0].FetchErrors)
+	assert.Equal(t, uint64(2), status.Networks[0].FetchErrorsPerMin)
+	assert.Equal(t, failingSince, *status.Networks[0].FailingSince)
+
+	now = now.Add(30 * time.Second)
+	tracker.NetworkRecovered("mainnet")
+	status = tracker.Snapshot(map[string][]string{"mainnet": {}})
+	assert.Equal(t, uint64(2), status.Networks[0].FetchErrors)
+	assert.Equal(t, uint64(1), status.Networks[0].FetchErrorsPerMin)
+	assert.Nil(t, status.Networks[0].FailingSince)
+}
+
+// TestStatusHandler tests the status endpoint with progress, queue depths and errors.
+func TestStatusHandler(t *testing.T) {
+	indexer := &IndexerImpl{
+		Events: map[string]map[common.Hash][]*EventConfig{
+			"mainnet": {
+				common.HexToHash("0x1"): {{ContractName: "USDC"}, {ContractName: "AAVE"}},
+				common.HexToHash("0x2"): {{ContractName: "USDC"}},
+			},
+		},
+		EventQueues:   map[string]chan *EventsTask{"mainnet": make(chan *EventsTask, 2)},
+		HandlerQueues: map[string]chan HandlerTask{"mainnet": make(chan HandlerTask, 2)},
+		Stats:         NewStatusTracker(),
+		Metrics:       NewHandlerMetrics(),
+	}
+	indexer.EventQueues["mainnet"] <- &EventsTask{}
+	indexer.Metrics.Observe("USDC:mainnet:Transfer", time.Millisecond, RunOK)
+	indexer.Metrics.Observe("USDC:mainnet:Transfer", time.Millisecond, RunError)
+	indexer.Metrics.Observe("USDC:base:Transfer", time.Millisecond, RunOK)
+	indexer.Stats.SetHead("mainnet", 1200)
+	indexer.Stats.SetProcessed("mainnet", 1180)
+	indexer.Stats.NetworkError("mainnet", errors.New("rpc unavailable"))
+	indexer.Stats.Event("mainnet", "USDC", 1170)
+
+	rr := httptest.NewRecorder()
+	StatusHandler(indexer).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, StatusPath, nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var status IndexerStatus
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&status))
+	assert.Len(t, status.Networks, 1)
+
+	network := status.Networks[0]
+	assert.Equal(t, "mainnet", network.Network)
+	assert.Equal(t, uint64(1180), network.LastProcessedBlock)
+	assert.Equal(t, uint64(20), network.Lag)
+	assert.Equal(t, 1, network.EventQueueDepth)
+	assert.Equal(t, 0, network.HandlerQueueDepth)
+	assert.Equal(t, "rpc unavailable", network.LastError)
+	assert.NotNil(t, network.LastErrorAt)
+
+	assert.Equal(t, []string{"AAVE", "USDC"}, []string{network.Contracts[0].Contract, network.Contracts[1].Contract})
+	assert.Equal(t, uint64(0), network.Contracts[0].EventsPerMinute)
+	assert.Equal(t, uint64(1), network.Contracts[1].EventsPerMinute)
+	assert.Equal(t, uint64(1170), network.Contracts[1].LastEventBlock)
+	assert.Empty(t, network.Contracts[0].Handlers)
+	assert.Equal(t, []HandlerStatus{{Handler: "USDC:mainnet:Transfer", Runs: 2, Failures: 1, FailureRate: 0.5}}, network.Contracts[1].Handlers)
+	assert.Nil(t, status.Leader, "no leadership without leader election")
+}
+
+// TestStatusHandler_Leader tests that the status reports the leadership of the instance under leader election.
+func TestStatusHandler_Leader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	indexer := &IndexerImpl{
+		Stats:   NewStatusTracker(),
+		Metrics: NewHandlerMetrics(),
+		Leader:  newTestElector(&fakeLockConn{row: lockRow(ctrl, false)}, &fakeLockConn{row: lockRow(ctrl, true)}),
+	}
+
+	status := func() IndexerStatus {
+		rr := httptest.NewRecorder()
+		StatusHandler(indexer).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, StatusPath, nil))
+		var status IndexerStatus
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&status))
+		return status
+	}
+
+	_, err := indexer.Leader.TryLead(context.Background())
+	assert.NoError(t, err)
+	if standby := status(); assert.NotNil(t, standby.Leader) {
+		assert.False(t, standby.Leader.Leader)
+		assert.Equal(t, "indexer-1", standby.Leader.Owner)
+	}
+
+	_, err = indexer.Leader.TryLead(context.Background())
+	assert.NoError(t, err)
+	if leader := status(); assert.NotNil(t, leader.Leader) {
+		assert.True(t, leader.Leader.Leader)
+		assert.False(t, leader.Leader.Since.IsZero())
+	}
+}
@@ -0,0 +1,349 @@
+package ethindexa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"hw/pkg/ethindexa/utils"
+	"hw/pkg/pg"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrEntityNotFound is returned when an entity cannot be found.
+var ErrEntityNotFound = errors.New("entity not found")
+
+// entityNamePattern restricts entity names to identifiers that map to a table name.
+var entityNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// maxEntityTableLength keeps entity table and index names within the 63 bytes Postgres allows.
+const maxEntityTableLength = 50
+
+// Fields are the stored fields of an entity. Values round-trip through JSON, so numbers are
+// returned as json.Number and *big.Int or model.Decimal values come back as json.Number or string.
+type Fields map[string]interface{}
+
+// EntityRecord is a stored entity with its id.
+type EntityRecord struct {
+	ID     string
+	Fields Fields
+}
+
+// EntityStore stores schemaless entities for handlers, so new handlers can persist data
+// without repository methods or migrations. Entities of a name share a collection and are
+// identified by an id unique within it.
+type EntityStore interface {
+	// Upsert creates an entity or merges fields into its stored fields.
+	Upsert(ctx context.Context, entity, id string, fields Fields) error
+	// Get returns the fields of an entity, or ErrEntityNotFound.
+	Get(ctx context.Context, entity, id string) (Fields, error)
+	// Find returns up to limit entities whose fields contain all of match, ordered by id.
+	// A limit of 0 returns every match.
+	Find(ctx context.Context, entity string, match Fields, limit int) ([]EntityRecord, error)
+	// Delete removes an entity. Deleting a missing entity is not an error.
+	Delete(ctx context.Context, entity, id string) error
+}
+
+// entityTable returns the table of an entity, e.g. entity_pair_day_data for PairDayData.
+func entityTable(entity string) (string, error) {
+	if !entityNamePattern.MatchString(entity) {
+		return "", fmt.Errorf("invalid entity name %q", entity)
+	}
+
+	table := "entity_" + utils.SnakeCase(entity)
+	if len(table) > maxEntityTableLength {
+		return "", fmt.Errorf("entity name %q is too long", entity)
+	}
+	return table, nil
+}
+
+// decodeFields decodes stored JSON fields, keeping numbers exact.
+func decodeFields(data []byte) (Fields, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var fields Fields
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to decode entity fields: %w", err)
+	}
+	return fields, nil
+}
+
+// PostgresEntityStore is an EntityStore keeping each entity in its own JSONB table,
+// created on first use.
+type PostgresEntityStore struct {
+	db pg.PgxPool
+
+	mutex   sync.Mutex
+	created map[string]bool
+}
+
+// NewPostgresEntityStore creates a PostgresEntityStore on top of the given pool.
+func NewPostgresEntityStore(db pg.PgxPool) *PostgresEntityStore {
+	return &PostgresEntityStore{
+		db:      db,
+		created: make(map[string]bool),
+	}
+}
+
+// table returns the quoted table of an entity, creating the table if this store has not yet done so.
+func (s *PostgresEntityStore) table(ctx context.Context, entity string) (string, error) {
+	name, err := entityTable(entity)
+	if err != nil {
+		return "", err
+	}
+	table := pgx.Identifier{name}.Sanitize()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.created[name] {
+		return table, nil
+	}
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			"id" text PRIMARY KEY,
+			"data" jsonb NOT NULL,
+			"created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			"updated_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`, table)
+	if _, err := s.db.Exec(ctx, query); err != nil {
+		return "", fmt.Errorf("failed to create table for entity %s: %w", entity, err)
+	}
+	indexQuery := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING GIN ("data" jsonb_path_ops)`, pgx.Identifier{"idx_" + name + "_data"}.Sanitize(), table)
+	if _, err := s.db.Exec(ctx, indexQuery); err != nil {
+		return "", fmt.Errorf("failed to create index for entity %s: %w", entity, err)
+	}
+
+	s.created[name] = true
+	return table, nil
+}
+
+// Upsert creates an entity or merges fields into its stored fields.
+func (s *PostgresEntityStore) Upsert(ctx context.Context, entity, id string, fields Fields) error {
+	table, err := s.table(ctx, entity)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s %s: %w", entity, id, err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (id, data)
+		VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET
+			data = %[1]s.data || EXCLUDED.data,
+			updated_at = CURRENT_TIMESTAMP
+	`, table)
+	if _, err := s.db.Exec(ctx, query, id, data); err != nil {
+		return fmt.Errorf("failed to upsert %s %s: %w", entity, id, err)
+	}
+	return nil
+}
+
+// Get returns the fields of an entity, or ErrEntityNotFound.
+func (s *PostgresEntityStore) Get(ctx context.Context, entity, id string) (Fields, error) {
+	table, err := s.table(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	query := fmt.Sprintf(`SELECT data FROM %s WHERE id = $1`, table)
+	if err := s.db.QueryRow(ctx, query, id).Scan(&data); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to get %s %s: %w", entity, id, err)
+	}
+	return decodeFields(data)
+}
+
+// Find returns up to limit entities whose fields contain all of match, ordered by id.
+func (s *PostgresEntityStore) Find(ctx context.Context, entity string, match Fields, limit int) ([]EntityRecord, error) {
+	table, err := s.table(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	if match == nil {
+		match = Fields{}
+	}
+	filter, err := json.Marshal(match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s filter: %w", entity, err)
+	}
+
+	query := fmt.Sprintf(`SELECT id, data FROM %s WHERE data @> $1 ORDER BY id`, table)
+	args := []interface{}{filter}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", entity, err)
+	}
+	defer rows.Close()
+
+	records := []EntityRecord{}
+	for rows.Next() {
+		var record EntityRecord
+		var data []byte
+		if err := rows.Scan(&record.ID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", entity, err)
+		}
+		if record.Fields, err = decodeFields(data); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", entity, err)
+	}
+	return records, nil
+}
+
+// Delete removes an entity.
+func (s *PostgresEntityStore) Delete(ctx context.Context, entity, id string) error {
+	table, err := s.table(ctx, entity)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, table)
+	if _, err := s.db.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete %s %s: %w", entity, id, err)
+	}
+	return nil
+}
+
+// MemoryEntityStore is an EntityStore kept in memory, used when the indexer has no database
+// and in handler tests. Fields are stored as JSON, so values read back like from Postgres.
+type MemoryEntityStore struct {
+	mutex    sync.RWMutex
+	entities map[string]map[string][]byte // map[entity][id]data
+}
+
+// NewMemoryEntityStore creates an empty MemoryEntityStore.
+func NewMemoryEntityStore() *MemoryEntityStore {
+	return &MemoryEntityStore{entities: make(map[string]map[string][]byte)}
+}
+
+// Upsert creates an entity or merges fields into its stored fields.
+func (s *MemoryEntityStore) Upsert(ctx context.Context, entity, id string, fields Fields) error {
+	if _, err := entityTable(entity); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	merged := Fields{}
+	if data, exists := s.entities[entity][id]; exists {
+		var err error
+		if merged, err = decodeFields(data); err != nil {
+			return err
+		}
+	}
+	for name, value := range fields {
+		merged[name] = value
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s %s: %w", entity, id, err)
+	}
+	if s.entities[entity] == nil {
+		s.entities[entity] = make(map[string][]byte)
+	}
+	s.entities[entity][id] = data
+	return nil
+}
+
+// Get returns the fields of an entity, or ErrEntityNotFound.
+func (s *MemoryEntityStore) Get(ctx context.Context, entity, id string) (Fields, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	data, exists := s.entities[entity][id]
+	if !exists {
+		return nil, ErrEntityNotFound
+	}
+	return decodeFields(data)
+}
+
+// Find returns up to limit entities whose top-level fields equal those of match, ordered by id.
+func (s *MemoryEntityStore) Find(ctx context.Context, entity string, match Fields, limit int) ([]EntityRecord, error) {
+	// Normalize match like the stored fields, so e.g. ints compare equal to stored numbers
+	filterData, err := json.Marshal(match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s filter: %w", entity, err)
+	}
+	filter := Fields{}
+	if match != nil {
+		if filter, err = decodeFields(filterData); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ids := make([]string, 0, len(s.entities[entity]))
+	for id := range s.entities[entity] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	records := []EntityRecord{}
+	for _, id := range ids {
+		fields, err := decodeFields(s.entities[entity][id])
+		if err != nil {
+			return nil, err
+		}
+		if !containsFields(fields, filter) {
+			continue
+		}
+		records = append(records, EntityRecord{ID: id, Fields: fields})
+		if limit > 0 && len(records) == limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+// Delete removes an entity.
+func (s *MemoryEntityStore) Delete(ctx context.Context, entity, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.entities[entity], id)
+	return nil
+}
+
+// containsFields reports whether fields has every top-level value of match.
+func containsFields(fields, match Fields) bool {
+	for name, want := range match {
+		got, exists := fields[name]
+		if !exists {
+			return false
+		}
+		gotData, _ := json.Marshal(got)
+		wantData, _ := json.Marshal(want)
+		if !bytes.Equal(gotData, wantData) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	pgMock "hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestEntityTable tests that entity names map to snake_case tables and invalid names are rejected.
+func TestEntityTable(t *testing.T) {
+	tests := []struct {
+		entity  string
+		want    string
+		wantErr bool
+	}{
+		{entity: "PairDayData", want: "entity_pair_day_data"},
+		{entity: "USDCPrice", want: "entity_usdc_price"},
+		{entity: "pool_v2", want: "entity_pool_v2"},
+		{entity: "Token2Pair", want: "entity_token2_pair"},
+		{entity: "pair; DROP TABLE users", wantErr: true},
+		{entity: "1Pair", wantErr: true},
+		{entity: strings.Repeat("A", 50), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entity, func(t *testing.T) {
+			table, err := entityTable(tt.entity)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, table)
+		})
+	}
+}
+
+// TestMemoryEntityStore tests upserting, merging, finding and deleting entities.
+func TestMemoryEntityStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryEntityStore()
+
+	assert.NoError(t, store.Upsert(ctx, "PairDayData", "pair-1", Fields{"pair": "0x01", "day": 19997, "volume": big.NewInt(10)}))
+	assert.NoError(t, store.Upsert(ctx, "PairDayData", "pair-1", Fields{"volume": big.NewInt(25)}))
+	assert.NoError(t, store.Upsert(ctx, "PairDayData", "pair-2", Fields{"pair": "0x02", "day": 19997}))
+
+	fields, err := store.Get(ctx, "PairDayData", "pair-1")
+	assert.NoError(t, err)
+	assert.Equal(t, Fields{"pair": "0x01", "day": json.Number("19997"), "volume": json.Number("25")}, fields)
+
+	_, err = store.Get(ctx, "PairDayData", "pair-3")
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+
+	records, err := store.Find(ctx, "PairDayData", Fields{"day": 19997}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pair-1", "pair-2"}, []string{records[0].ID, records[1].ID})
+
+	records, err = store.Find(ctx, "PairDayData", Fields{"pair": "0x02"}, 1)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "pair-2", records[0].ID)
+
+	assert.NoError(t, store.Delete(ctx, "PairDayData", "pair-1"))
+	_, err = store.Get(ctx, "PairDayData", "pair-1")
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+
+	assert.Error(t, store.Upsert(ctx, "bad name", "1", Fields{}))
+}
+
+// TestPostgresEntityStore tests that the entity table is created once and fields are merged on conflict.
+func TestPostgresEntityStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockDB := pgMock.NewMockPgxPool(ctrl)
+	mockRow := pgMock.NewMockPgxRows(ctrl)
+
+	ctx := context.Background()
+	store := NewPostgresEntityStore(mockDB)
+
+	createTable := mockDB.EXPECT().Exec(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+		assert.Contains(t, sql, `CREATE TABLE IF NOT EXISTS "entity_pair_day_data"`)
+		return pgconn.CommandTag{}, nil
+	})
+	createIndex := mockDB.EXPECT().Exec(ctx, gomock.Any()).Return(pgconn.CommandTag{}, nil).After(createTable)
+	mockDB.EXPECT().Exec(ctx, gomock.Any(), "pair-1", []byte(`{"day":19997}`)).DoAndReturn(func(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+		assert.Contains(t, sql, `"entity_pair_day_data".data || EXCLUDED.data`)
+		return pgconn.CommandTag{}, nil
+	}).After(createIndex)
+	mockDB.EXPECT().QueryRow(ctx, gomock.Any(), "pair-2").Return(mockRow)
+	mockRow.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+
+	assert.NoError(t, store.Upsert(ctx, "PairDayData", "pair-1", Fields{"day": 19997}))
+
+	_, err := store.Get(ctx, "PairDayData", "pair-2")
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+}
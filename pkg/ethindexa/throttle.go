@@ -0,0 +1,85 @@
+package ethindexa
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultFetchConcurrency bounds the concurrent block requests of a range when the network
+	// does not configure fetchConcurrency.
+	DefaultFetchConcurrency = 8
+	// DefaultSlowFetchLatency is the block request latency above which the fetcher is throttled
+	// when the network does not configure slowFetchLatency.
+	DefaultSlowFetchLatency = 2 * time.Second
+)
+
+// fetchLatencyWeight is the weight of a new sample in the moving average of block request latencies.
+const fetchLatencyWeight = 0.2
+
+// FetchThrottle adapts the number of concurrent block requests of a network to the latency of its
+// RPC provider: the limit halves after a range whose average latency is above the slow latency,
+// and grows back by one request per range once the latency falls below half of it. A nil
+// FetchThrottle allows DefaultFetchConcurrency requests.
+type FetchThrottle struct {
+	max  int
+	slow time.Duration
+
+	mutex   sync.Mutex
+	limit   int
+	latency time.Duration // moving average of block request latencies
+}
+
+// NewFetchThrottle creates a FetchThrottle allowing up to max concurrent requests, starting at max.
+func NewFetchThrottle(max int, slow time.Duration) *FetchThrottle {
+	if max < 1 {
+		max = DefaultFetchConcurrency
+	}
+	if slow <= 0 {
+		slow = DefaultSlowFetchLatency
+	}
+	return &FetchThrottle{max: max, slow: slow, limit: max}
+}
+
+// Limit returns the number of concurrent block requests currently allowed.
+func (t *FetchThrottle) Limit() int {
+	if t == nil {
+		return DefaultFetchConcurrency
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.limit
+}
+
+// Observe records the latency of a block request.
+func (t *FetchThrottle) Observe(latency time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.latency == 0 {
+		t.latency = latency
+		return
+	}
+	t.latency += time.Duration(fetchLatencyWeight * float64(latency-t.latency))
+}
+
+// Adjust updates the limit from the observed latency once a range is fetched and returns the
+// previous and new limits.
+func (t *FetchThrottle) Adjust() (previous, limit int) {
+	if t == nil {
+		return DefaultFetchConcurrency, DefaultFetchConcurrency
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	previous = t.limit
+	switch {
+	case t.latency > t.slow:
+		t.limit = max(1, t.limit/2)
+	case t.latency < t.slow/2 && t.limit < t.max:
+		t.limit++
+	}
+	return previous, t.limit
+}
@@ -0,0 +1,37 @@
+package ethindexa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFetchThrottle tests that the limit halves while requests are slow and grows back one request
+// per range once they are fast again.
+func TestFetchThrottle(t *testing.T) {
+	throttle := NewFetchThrottle(8, time.Second)
+	assert.Equal(t, 8, throttle.Limit())
+
+	throttle.Observe(3 * time.Second)
+	previous, limit := throttle.Adjust()
+	assert.Equal(t, 8, previous)
+	assert.Equal(t, 4, limit)
+	throttle.Adjust()
+	throttle.Adjust()
+	_, limit = throttle.Adjust()
+	assert.Equal(t, 1, limit)
+
+	for i := 0; i < 20; i++ {
+		throttle.Observe(100 * time.Millisecond)
+	}
+	_, limit = throttle.Adjust()
+	assert.Equal(t, 2, limit)
+	for i := 0; i < 10; i++ {
+		throttle.Adjust()
+	}
+	assert.Equal(t, 8, throttle.Limit())
+
+	var unset *FetchThrottle
+	assert.Equal(t, DefaultFetchConcurrency, unset.Limit())
+}
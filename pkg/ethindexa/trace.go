@@ -0,0 +1,297 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"hw/pkg/pg"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TracePath is the path of the transaction trace endpoint served by TraceHandler.
+const TracePath = "/admin/indexer/trace"
+
+// ErrTxNotFound is returned when tracing a transaction the network does not know, or has not mined yet.
+var ErrTxNotFound = errors.New("transaction not found")
+
+// TracedWrite is a write made by a handler during a trace, recorded instead of applied.
+type TracedWrite struct {
+	Target string `json:"target"` // service method, "entity <Name>" or "sql"
+	Args   any    `json:"args"`
+}
+
+// TracedEvent is the outcome of a log of a traced transaction. Skipped tells why no handler ran
+// for it; otherwise Status is the outcome of the handler and Writes the writes it made.
+type TracedEvent struct {
+	LogIndex uint                   `json:"log_index"`
+	Address  string                 `json:"address"`
+	Contract string                 `json:"contract,omitempty"`
+	Event    string                 `json:"event,omitempty"`
+	Handler  string                 `json:"handler,omitempty"`
+	Args     map[string]interface{} `json:"args,omitempty"`
+	Skipped  string                 `json:"skipped,omitempty"`
+	Status   HandlerRunStatus       `json:"status,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+	Duration time.Duration          `json:"duration,omitempty"`
+	Writes   []TracedWrite          `json:"writes,omitempty"`
+}
+
+// TxTrace is the outcome of the logs of a transaction, in log order.
+type TxTrace struct {
+	Network     string        `json:"network"`
+	Transaction string        `json:"transaction"`
+	BlockNumber uint64        `json:"block_number"`
+	Events      []TracedEvent `json:"events"`
+}
+
+// TraceTx fetches the logs of a transaction and runs them through decoding and the registered
+// handlers in dry-run mode: the handlers read the database and the chain as usual, while their
+// writes through the service, the entity store and the database are recorded instead of applied.
+// Quarantined and paused handlers are run too, and nothing is counted in the metrics.
+func (indexer *IndexerImpl) TraceTx(ctx context.Context, networkName, txHash string) (*TxTrace, error) {
+	client, exists := indexer.Clients[networkName]
+	if !exists {
+		return nil, fmt.Errorf("unknown network: %s", networkName)
+	}
+	hash := common.HexToHash(txHash)
+
+	tx, err := client.GetTransactionByHash(ctx, hash.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", hash.Hex(), err)
+	}
+	if tx.Result.BlockNumber == "" {
+		return nil, fmt.Errorf("%w: %s on %s", ErrTxNotFound, hash.Hex(), networkName)
+	}
+	blockNumber := new(big.Int).SetBytes(common.FromHex(tx.Result.BlockNumber))
+
+	block, err := client.GetBlockByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", blockNumber, err)
+	}
+	logEntries, err := client.GetLogsByBlockNumber(ctx, blockNumber, blockNumber, getUniqueAddresses(indexer.Events[networkName]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of block %d: %w", blockNumber, err)
+	}
+	sortLogs(logEntries)
+
+	recorder := &traceRecorder{}
+	idx := &IndexerService{
+		Client: client.Client,
+		Store:  &dryRunStore{store: indexer.Store, record: recorder.record},
+	}
+	if indexer.Service != nil {
+		idx.Service = indexer.Service.DryRun(recorder.record)
+	}
+	if indexer.DB != nil {
+		idx.DB = &dryRunDB{PgxPool: indexer.DB, record: recorder.record}
+	}
+
+	trace := &TxTrace{Network: networkName, Transaction: hash.Hex(), BlockNumber: blockNumber.Uint64(), Events: []TracedEvent{}}
+	for _, logEntry := range logEntries {
+		if logEntry.TxHash != hash {
+			continue
+		}
+
+		traced := false
+		if len(logEntry.Topics) > 0 {
+			for _, eventConfig := range indexer.Events[networkName][logEntry.Topics[0]] {
+				if logEntry.Address != eventConfig.ContractAddress {
+					continue
+				}
+				traced = true
+				event := TracedEvent{
+					LogIndex: logEntry.Index,
+					Address:  logEntry.Address.Hex(),
+					Contract: eventConfig.ContractName,
+					Event:    eventConfig.EventName,
+					Handler:  eventConfig.HandlerKey,
+				}
+
+				eventArgs, err := eventConfig.extractEventArgs(logEntry)
+				if err != nil {
+					event.Skipped = "decoding failed: " + err.Error()
+					trace.Events = append(trace.Events, event)
+					continue
+				}
+				event.Args = eventArgs
+
+				eventHandler := indexer.handlerFor(eventConfig)
+				switch {
+				case logEntry.BlockNumber < eventConfig.StartBlock.Uint64():
+					event.Skipped = fmt.Sprintf("before start block %d", eventConfig.StartBlock)
+				case eventHandler == nil:
+					event.Skipped = "no handler registered"
+				case !matchFilters(eventConfig.Filters, eventArgs):
+					event.Skipped = "rejected by filters"
+				default:
+					task := indexer.newHandlerTask(networkName, eventConfig, eventHandler, logEntry, *block, tx.Result, eventArgs)
+					task.IndexerService = idx
+					traceHandler(ctx, task, &event)
+					event.Writes = recorder.take()
+				}
+				trace.Events = append(trace.Events, event)
+			}
+		}
+		if !traced {
+			trace.Events = append(trace.Events, TracedEvent{LogIndex: logEntry.Index, Address: logEntry.Address.Hex(), Skipped: "no event configured"})
+		}
+	}
+	return trace, nil
+}
+
+// traceHandler runs the handler of a task and sets the outcome of the traced event.
+func traceHandler(ctx context.Context, task HandlerTask, event *TracedEvent) {
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHandlerTimeout
+	}
+	eventCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	task.Event.Ctx = eventCtx
+	task.Event.Cancel = cancel
+
+	startTime := time.Now()
+	panicked, err := callHandler(task)
+	event.Duration = time.Since(startTime)
+
+	switch {
+	case panicked:
+		event.Status = RunPanic
+	case errors.Is(eventCtx.Err(), context.DeadlineExceeded):
+		event.Status = RunTimeout
+	case err != nil:
+		event.Status = RunError
+	default:
+		event.Status = RunOK
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+}
+
+// traceRecorder collects the writes of the handler being traced.
+type traceRecorder struct {
+	mutex  sync.Mutex
+	writes []TracedWrite
+}
+
+func (r *traceRecorder) record(target string, args any) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.writes = append(r.writes, TracedWrite{Target: target, Args: args})
+}
+
+// take returns the writes recorded since the previous call.
+func (r *traceRecorder) take() []TracedWrite {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	writes := r.writes
+	r.writes = nil
+	return writes
+}
+
+// dryRunStore is an EntityStore reading from store and recording its writes.
+type dryRunStore struct {
+	store  EntityStore
+	record func(target string, args any)
+}
+
+func (s *dryRunStore) Upsert(ctx context.Context, entity, id string, fields Fields) error {
+	s.record("entity "+entity, map[string]any{"upsert": id, "fields": fields})
+	return nil
+}
+
+func (s *dryRunStore) Get(ctx context.Context, entity, id string) (Fields, error) {
+	if s.store == nil {
+		return nil, ErrEntityNotFound
+	}
+	return s.store.Get(ctx, entity, id)
+}
+
+func (s *dryRunStore) Find(ctx context.Context, entity string, match Fields, limit int) ([]EntityRecord, error) {
+	if s.store == nil {
+		return nil, nil
+	}
+	return s.store.Find(ctx, entity, match, limit)
+}
+
+func (s *dryRunStore) Delete(ctx context.Context, entity, id string) error {
+	s.record("entity "+entity, map[string]any{"delete": id})
+	return nil
+}
+
+// dryRunDB is a database whose queries run, and whose Exec statements, the writes of the
+// generated entity repositories, are recorded instead of executed.
+type dryRunDB struct {
+	pg.PgxPool
+	record func(target string, args any)
+}
+
+func (db *dryRunDB) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	db.record("sql", map[string]any{"sql": strings.Join(strings.Fields(sql), " "), "args": arguments})
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *dryRunDB) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	return nil, errors.New("connections are not available in a trace")
+}
+
+func (db *dryRunDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return nil, errors.New("transactions are not available in a trace")
+}
+
+func (db *dryRunDB) Close() {}
+
+// TraceHandler serves the trace of the transaction given by the tx query parameter on the
+// network given by the network query parameter, as JSON. Durations are in nanoseconds.
+func TraceHandler(indexer *IndexerImpl) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		networkName, txHash := r.URL.Query().Get("network"), r.URL.Query().Get("tx")
+		if _, exists := indexer.Clients[networkName]; !exists {
+			http.Error(w, "unknown network: "+networkName, http.StatusBadRequest)
+			return
+		}
+		if !isTxHash(txHash) {
+			http.Error(w, "invalid transaction hash: "+txHash, http.StatusBadRequest)
+			return
+		}
+
+		trace, err := indexer.TraceTx(r.Context(), networkName, txHash)
+		if errors.Is(err, ErrTxNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(trace); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// isTxHash reports whether s is a 0x-prefixed 32-byte hex hash.
+func isTxHash(s string) bool {
+	if len(s) != 66 || !strings.HasPrefix(s, "0x") {
+		return false
+	}
+	for _, c := range s[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+package ethindexa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hw/internal/model"
+	"hw/internal/service"
+	"hw/internal/service/mocks"
+	"hw/pkg/ethindexa/ethclient"
+	"hw/pkg/ethindexa/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	traceTxHash = common.HexToHash("0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060")
+	traceUSDC   = common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	traceFrom   = common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	traceTo     = common.HexToAddress("0x00000000000000000000000000000000000000f2")
+)
+
+// fakeTraceRPC serves a transaction mined in block 100 with the given logs, and another
+// transaction's log in the same block.
+func fakeTraceRPC(t *testing.T, logs []types.Log) *httptest.Server {
+	other := types.Log{Address: traceUSDC, Topics: logs[0].Topics, Data: logs[0].Data, BlockNumber: 100, TxHash: common.HexToHash("0x01"), Index: 9}
+	logsJSON, err := json.Marshal(append(logs, other))
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		result := json.RawMessage(`null`)
+		switch req.Method {
+		case "eth_getTransactionByHash":
+			result = json.RawMessage(`{"hash":"` + traceTxHash.Hex() + `","blockNumber":"0x64","from":"` + traceFrom.Hex() + `"}`)
+		case "eth_getBlockByNumber":
+			result = json.RawMessage(`{"number":"0x64","timestamp":"0x66fb3c00","transactions":[{"hash":"` + traceTxHash.Hex() + `"}]}`)
+		case "eth_getLogs":
+			result = logsJSON
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestTraceTx tests that the logs of a transaction are decoded and run through their handlers,
+// whose writes are recorded instead of applied, and that skipped logs are reported with a reason.
+func TestTraceTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parsedABI, err := utils.LoadABI("erc20_usdc")
+	assert.NoError(t, err)
+	transfer := parsedABI.Events["Transfer"]
+	value, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(2500_000000))
+	assert.NoError(t, err)
+	topics := []common.Hash{transfer.ID, common.BytesToHash(traceFrom.Bytes()), common.BytesToHash(traceTo.Bytes())}
+
+	server := fakeTraceRPC(t, []types.Log{
+		{Address: traceUSDC, Topics: topics, Data: value, BlockNumber: 100, TxHash: traceTxHash, Index: 4},
+		{Address: traceUSDC, Topics: []common.Hash{common.HexToHash("0xdead")}, BlockNumber: 100, TxHash: traceTxHash, Index: 3},
+	})
+	client, err := ethclient.NewClient("mainnet", server.URL)
+	assert.NoError(t, err)
+
+	// The dry run records the writes of the service in place of the service
+	mockService := mocks.NewMockService(ctrl)
+	mockService.EXPECT().DryRun(gomock.Any()).DoAndReturn(func(record func(string, any)) service.Service {
+		return &recordingService{MockService: mockService, record: record}
+	})
+
+	store := NewMemoryEntityStore()
+	indexer := &IndexerImpl{
+		Clients: map[string]*ethclient.Client{"mainnet": client},
+		Events: map[string]map[common.Hash][]*EventConfig{
+			"mainnet": {transfer.ID: {
+				{ContractName: "USDC", NetworkName: "mainnet", ContractAddress: traceUSDC, ContractABI: parsedABI, EventName: "Transfer", HandlerKey: "USDC:mainnet:Transfer", StartBlock: big.NewInt(1)},
+				{ContractName: "USDC", NetworkName: "mainnet", ContractAddress: traceUSDC, ContractABI: parsedABI, EventName: "Transfer", HandlerKey: "USDC:mainnet:Transfer:late", StartBlock: big.NewInt(200)},
+			}},
+		},
+		Service:  mockService,
+		Store:    store,
+		Metrics:  NewHandlerMetrics(),
+		Handlers: NewHandlerRegistry(),
+	}
+	assert.NoError(t, indexer.RegisterHandler("USDC:mainnet:Transfer", func(idx *IndexerService, event Event) error {
+		if err := idx.Store.Upsert(event.Ctx, "Transfer", event.LogKey(), Fields{"value": event.Args["value"]}); err != nil {
+			return err
+		}
+		return idx.Service.CreateToken(event.Ctx, nil)
+	}))
+
+	trace, err := indexer.TraceTx(context.Background(), "mainnet", traceTxHash.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), trace.BlockNumber)
+	if assert.Len(t, trace.Events, 3, "the log of the other transaction is left out") {
+		assert.Equal(t, TracedEvent{LogIndex: 3, Address: traceUSDC.Hex(), Skipped: "no event configured"}, trace.Events[0])
+
+		handled := trace.Events[1]
+		assert.Equal(t, RunError, handled.Status)
+		assert.Equal(t, "no token", handled.Error)
+		assert.Equal(t, traceFrom, handled.Args["from"])
+		assert.Equal(t, big.NewInt(2500_000000), handled.Args["value"])
+		assert.Equal(t, []TracedWrite{
+			{Target: "entity Transfer", Args: map[string]any{"upsert": "mainnet:" + traceTxHash.Hex() + ":4", "fields": Fields{"value": big.NewInt(2500_000000)}}},
+			{Target: "CreateToken"},
+		}, handled.Writes)
+
+		assert.Equal(t, "before start block 200", trace.Events[2].Skipped)
+	}
+
+	_, err = store.Get(context.Background(), "Transfer", "mainnet:"+traceTxHash.Hex()+":4")
+	assert.ErrorIs(t, err, ErrEntityNotFound, "the entity store is left untouched")
+	assert.Empty(t, indexer.Metrics.Snapshot(), "traced runs are not counted")
+}
+
+// recordingService is a mocked service whose CreateToken is recorded as a dry run would, and fails.
+type recordingService struct {
+	*mocks.MockService
+	record func(string, any)
+}
+
+func (s *recordingService) CreateToken(ctx context.Context, token *model.Token) error {
+	s.record("CreateToken", nil)
+	return errors.New("no token")
+}
+
+// TestTraceHandler tests that the trace endpoint validates its parameters.
+func TestTraceHandler(t *testing.T) {
+	indexer := &IndexerImpl{Clients: map[string]*ethclient.Client{"mainnet": nil}}
+
+	tests := []struct {
+		name  string
+		query string
+		code  int
+	}{
+		{name: "unknown network", query: "?network=base&tx=" + traceTxHash.Hex(), code: http.StatusBadRequest},
+		{name: "missing hash", query: "?network=mainnet", code: http.StatusBadRequest},
+		{name: "invalid hash", query: "?network=mainnet&tx=0x5c504ed4", code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			TraceHandler(indexer).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, TracePath+tt.query, nil))
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
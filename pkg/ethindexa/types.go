@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"hw/internal/service"
+	"hw/pkg/pg"
 
 	myclient "hw/pkg/ethindexa/ethclient"
 
@@ -23,6 +24,11 @@ type Event struct {
 	Transaction     myclient.GetTransactionResponse
 	TransactionHash common.Hash
 	BlockHash       common.Hash
+	TxIndex         uint // position of the transaction in its block
+	LogIndex        uint // position of the log in its block
+	Removed         bool // the log was reverted by a chain reorganization; such logs are never dispatched to handlers
+	Topics          []common.Hash
+	Data            []byte // raw non-indexed arguments, as decoded into Args
 	ContractAddress common.Address
 	ContractName    string
 	NetworkName     string
@@ -30,24 +36,51 @@ type Event struct {
 	Cancel          context.CancelFunc
 }
 
+// LogKey identifies the log of the event on its network, e.g. to deduplicate redelivered events.
+func (e Event) LogKey() string {
+	return fmt.Sprintf("%s:%s:%d", e.NetworkName, e.TransactionHash.Hex(), e.LogIndex)
+}
+
+// ChainReader reads chain state on behalf of event handlers.
+// It lets tests replace the Ethereum client with canned data.
+type ChainReader interface {
+	ReadContract(contractAddress common.Address, contractABI abi.ABI, startBlock *big.Int, functionName string, functionParams ...interface{}) (interface{}, error)
+	GetBlockByHash(blockHash common.Hash) (*types.Block, error)
+	GetTransactionByHash(txHash common.Hash) (TransactionInfo, error)
+	GetTransactionReceipt(txHash common.Hash) (ReceiptInfo, error)
+}
+
 // IndexerService provides access to the Ethereum client and the PostgreSQL database.
 type IndexerService struct {
 	Client  *ethclient.Client
 	Service service.Service
+	Chain   ChainReader // when set, chain reads go through it instead of Client
+	Store   EntityStore // entities persisted by handlers
+	DB      pg.PgxPool  // database of the generated entity repositories; nil without a database
 }
 
 // ReadContract is a method of IndexerService used to read contract data.
 func (s *IndexerService) ReadContract(contractAddress common.Address, contractABI abi.ABI, startBlock *big.Int, functionName string, functionParams ...interface{}) (interface{}, error) {
+	if s.Chain != nil {
+		return s.Chain.ReadContract(contractAddress, contractABI, startBlock, functionName, functionParams...)
+	}
 	return ReadContract(s.Client, contractAddress, contractABI, startBlock, functionName, functionParams...)
 }
 
 // GetBlockByHash retrieves a block by its hash.
 func (s *IndexerService) GetBlockByHash(blockHash common.Hash) (*types.Block, error) {
+	if s.Chain != nil {
+		return s.Chain.GetBlockByHash(blockHash)
+	}
 	return s.Client.BlockByHash(context.Background(), blockHash)
 }
 
 // GetTransactionByHash retrieves transaction details based on the transaction hash.
 func (s *IndexerService) GetTransactionByHash(txHash common.Hash) (txInfo TransactionInfo, err error) {
+	if s.Chain != nil {
+		return s.Chain.GetTransactionByHash(txHash)
+	}
+
 	tx, _, err := s.Client.TransactionByHash(context.Background(), txHash)
 	if err != nil {
 		return
@@ -89,8 +122,28 @@ func (s *IndexerService) GetTransactionByHash(txHash common.Hash) (txInfo Transa
 	return
 }
 
-// EventHandler is a function type for handling events.
-type EventHandler func(idx *IndexerService, event Event)
+// GetTransactionReceipt retrieves the receipt of a mined transaction, e.g. to know the gas it paid.
+func (s *IndexerService) GetTransactionReceipt(txHash common.Hash) (ReceiptInfo, error) {
+	if s.Chain != nil {
+		return s.Chain.GetTransactionReceipt(txHash)
+	}
+
+	receipt, err := s.Client.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		return ReceiptInfo{}, err
+	}
+	return ReceiptInfo{
+		TxHash:            txHash,
+		Status:            receipt.Status,
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: receipt.EffectiveGasPrice,
+	}, nil
+}
+
+// EventHandler is a function type for handling events. A returned error marks the run as failed:
+// it is logged, counted in the handler metrics and towards quarantine, and recorded in handler_runs.
+// Events a handler deliberately skips, such as removed logs, are not failures.
+type EventHandler func(idx *IndexerService, event Event) error
 
 // TransactionInfo represents transaction information.
 type TransactionInfo struct {
@@ -101,6 +154,23 @@ type TransactionInfo struct {
 	Timestamp int64
 }
 
+// ReceiptInfo represents the outcome of a mined transaction.
+type ReceiptInfo struct {
+	TxHash            common.Hash
+	Status            uint64 // 1 for success, 0 for a reverted transaction
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int // wei per gas paid by the sender, base fee and tip included
+}
+
+// GasFee returns the fee paid by the sender of the transaction in wei, the gas used at the
+// effective gas price. L1 data fees of rollups are not included.
+func (r ReceiptInfo) GasFee() *big.Int {
+	if r.EffectiveGasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(r.GasUsed), r.EffectiveGasPrice)
+}
+
 // BlockInfo represents block information.
 type BlockInfo struct {
 	BlockNumber int64
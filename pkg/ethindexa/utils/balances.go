@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BalanceOfBatchSize is the number of balanceOf calls sent in one JSON-RPC batch.
+var BalanceOfBatchSize = 100
+
+// BalancesOf reads the ERC-20 balance of every holder of a token at a block, sending the
+// balanceOf calls in JSON-RPC batches of BalanceOfBatchSize. The result is keyed by the lowercase
+// address of the holder. A failed call fails the whole read, so a snapshot never misses a holder.
+func BalancesOf(ctx context.Context, client *ethclient.Client, token string, holders []string, blockNumber int64) (map[string]*big.Int, error) {
+	erc20, err := LoadABI("erc20_usdc")
+	if err != nil {
+		return nil, err
+	}
+	method := erc20.Methods["balanceOf"]
+	tokenAddress := common.HexToAddress(token)
+	block := hexutil.EncodeBig(big.NewInt(blockNumber))
+
+	balances := make(map[string]*big.Int, len(holders))
+	for start := 0; start < len(holders); start += BalanceOfBatchSize {
+		batch := holders[start:min(start+BalanceOfBatchSize, len(holders))]
+		elems := make([]rpc.BatchElem, len(batch))
+		results := make([]hexutil.Bytes, len(batch))
+		for i, holder := range batch {
+			data, err := erc20.Pack("balanceOf", common.HexToAddress(holder))
+			if err != nil {
+				return nil, fmt.Errorf("error packing balanceOf call data: %w", err)
+			}
+			elems[i] = rpc.BatchElem{
+				Method: "eth_call",
+				Args:   []interface{}{map[string]interface{}{"to": tokenAddress, "data": hexutil.Bytes(data)}, block},
+				Result: &results[i],
+			}
+		}
+
+		if err := client.Client().BatchCallContext(ctx, elems); err != nil {
+			return nil, fmt.Errorf("error calling balanceOf batch: %w", err)
+		}
+		for i, holder := range batch {
+			if elems[i].Error != nil {
+				return nil, fmt.Errorf("error calling balanceOf of %s: %w", holder, elems[i].Error)
+			}
+			unpacked, err := method.Outputs.Unpack(results[i])
+			if err != nil {
+				return nil, fmt.Errorf("error unpacking balanceOf of %s: %w", holder, err)
+			}
+			balances[strings.ToLower(common.HexToAddress(holder).Hex())] = unpacked[0].(*big.Int)
+		}
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBalancesOf tests that balanceOf calls are batched at the block and decoded per holder.
+func TestBalancesOf(t *testing.T) {
+	defer func(size int) { BalanceOfBatchSize = size }(BalanceOfBatchSize)
+	BalanceOfBatchSize = 2
+
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+		batches = append(batches, len(reqs))
+
+		responses := make([]string, len(reqs))
+		for i, req := range reqs {
+			assert.Equal(t, "eth_call", req.Method)
+			assert.Equal(t, `"0x64"`, string(req.Params[1]))
+			var call struct {
+				Data hexutil.Bytes `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(req.Params[0], &call))
+			// The balance of a holder is the last byte of its address
+			balance := common.LeftPadBytes(call.Data[len(call.Data)-1:], 32)
+			responses[i] = `{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":"` + hexutil.Encode(balance) + `"}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[" + strings.Join(responses, ",") + "]"))
+	}))
+	defer server.Close()
+
+	client, err := ethclient.Dial(server.URL)
+	assert.NoError(t, err)
+
+	holders := []string{
+		"0x0000000000000000000000000000000000000001",
+		"0x0000000000000000000000000000000000000002",
+		"0x00000000000000000000000000000000000000Ff",
+	}
+	balances, err := BalancesOf(context.Background(), client, "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", holders, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, batches)
+	assert.Equal(t, map[string]*big.Int{
+		"0x0000000000000000000000000000000000000001": big.NewInt(1),
+		"0x0000000000000000000000000000000000000002": big.NewInt(2),
+		"0x00000000000000000000000000000000000000ff": big.NewInt(255),
+	}, balances)
+}
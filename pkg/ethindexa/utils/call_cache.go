@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"hw/pkg/cache"
+	"hw/pkg/config"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// callCache holds the raw results of contract calls at a block, which never change, so repeated
+// reads such as decimals() and name() of the tokens of a batch reach the node once.
+var callCache = struct {
+	mutex sync.RWMutex
+	cache cache.Cache
+	ttl   time.Duration
+}{
+	cache: cache.NewLocalCache(config.Default().Cache),
+	ttl:   config.Default().Indexer.CallCacheTTL,
+}
+
+// chainIDs holds the chain ID of each client, which scopes its cached calls.
+var chainIDs sync.Map
+
+// SetCallCache replaces the cache of contract call results, e.g. with a hybrid cache sharing
+// them between indexers, and how long they are kept. A nil cache or a zero TTL disables it.
+func SetCallCache(c cache.Cache, ttl time.Duration) {
+	callCache.mutex.Lock()
+	defer callCache.mutex.Unlock()
+	callCache.cache = c
+	callCache.ttl = ttl
+}
+
+// callContract calls a contract, reading the result from the call cache when the call is made
+// at a block. Calls at the latest block and failed calls are not cached.
+func callContract(ctx context.Context, client *ethclient.Client, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	callCache.mutex.RLock()
+	c, ttl := callCache.cache, callCache.ttl
+	callCache.mutex.RUnlock()
+	if c == nil || ttl <= 0 || blockNumber == nil {
+		return client.CallContract(ctx, msg, blockNumber)
+	}
+
+	chainID, err := clientChainID(ctx, client)
+	if err != nil {
+		return client.CallContract(ctx, msg, blockNumber)
+	}
+	key := c.FormatKey("eth_call", chainID, msg.To.Hex(), hexutil.Encode(msg.Data), blockNumber)
+	return cache.GetOrLoad(ctx, c, key, ttl, func(ctx context.Context) ([]byte, error) {
+		return client.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+// clientChainID returns the chain ID of a client, asked once.
+func clientChainID(ctx context.Context, client *ethclient.Client) (string, error) {
+	if chainID, exists := chainIDs.Load(client); exists {
+		return chainID.(string), nil
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+	chainIDs.Store(client, chainID.String())
+	return chainID.String(), nil
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"hw/pkg/cache"
+	"hw/pkg/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCallRPC answers eth_call with decimals() of 6 and counts the requests by method.
+func fakeCallRPC(t *testing.T) (*ethclient.Client, func(method string) int) {
+	var mutex sync.Mutex
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mutex.Lock()
+		calls[req.Method]++
+		mutex.Unlock()
+
+		result := `"0x1"`
+		if req.Method == "eth_call" {
+			result = `"0x0000000000000000000000000000000000000000000000000000000000000006"`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":` + result + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := ethclient.Dial(server.URL)
+	assert.NoError(t, err)
+	return client, func(method string) int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return calls[method]
+	}
+}
+
+// TestReadContract_CallCache tests that calls at a block are read once, and calls at the latest
+// block and with the cache disabled every time.
+func TestReadContract_CallCache(t *testing.T) {
+	defer SetCallCache(cache.NewLocalCache(config.Default().Cache), config.Default().Indexer.CallCacheTTL)
+	SetCallCache(cache.NewLocalCache(config.Default().Cache), time.Hour)
+
+	client, calls := fakeCallRPC(t)
+	parsedABI, err := LoadABI("erc20_usdc")
+	assert.NoError(t, err)
+	token := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+
+	for i := 0; i < 3; i++ {
+		result, err := ReadContract(client, token, parsedABI, big.NewInt(100), "decimals")
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(6), result.([]interface{})[0])
+	}
+	assert.Equal(t, 1, calls("eth_call"), "a call at a block is cached")
+	assert.Equal(t, 1, calls("eth_chainId"), "the chain ID is asked once")
+
+	_, err = ReadContract(client, token, parsedABI, big.NewInt(101), "decimals")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls("eth_call"), "another block is another call")
+
+	_, err = ReadContract(client, token, parsedABI, nil, "decimals")
+	assert.NoError(t, err)
+	_, err = ReadContract(client, token, parsedABI, nil, "decimals")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls("eth_call"), "calls at the latest block are not cached")
+
+	SetCallCache(nil, time.Hour)
+	_, err = ReadContract(client, token, parsedABI, big.NewInt(100), "decimals")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, calls("eth_call"), "a nil cache disables caching")
+}
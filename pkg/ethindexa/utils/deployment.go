@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrNotDeployed is returned by DeploymentBlock when there is no contract code at the address.
+var ErrNotDeployed = errors.New("no contract code at address")
+
+// CodeReader reads the code of an address at a block.
+type CodeReader interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// DeploymentBlock returns the block a contract was deployed in: the first block up to head at
+// which the address has code. It binary-searches eth_getCode over block history, so it takes
+// about log2(head) calls and needs an RPC serving historic state.
+func DeploymentBlock(ctx context.Context, client CodeReader, address common.Address, head uint64) (uint64, error) {
+	deployed := func(block uint64) (bool, error) {
+		code, err := client.CodeAt(ctx, address, new(big.Int).SetUint64(block))
+		if err != nil {
+			return false, fmt.Errorf("failed to get code of %s at block %d: %w", address.Hex(), block, err)
+		}
+		return len(code) > 0, nil
+	}
+
+	found, err := deployed(head)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("%s at block %d: %w", address.Hex(), head, ErrNotDeployed)
+	}
+
+	// The code is there at high and not before low
+	low, high := uint64(0), head
+	for low < high {
+		mid := low + (high-low)/2
+		found, err := deployed(mid)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	return low, nil
+}
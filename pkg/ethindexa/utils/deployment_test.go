@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCode serves code from the block a contract was deployed in, or none when deployed is negative.
+type fakeCode struct {
+	deployed int64
+	calls    int
+	err      error
+}
+
+func (c *fakeCode) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.deployed >= 0 && blockNumber.Int64() >= c.deployed {
+		return []byte{0x60, 0x80}, nil
+	}
+	return nil, nil
+}
+
+// TestDeploymentBlock tests that the first block with code is found in about log2(head) calls.
+func TestDeploymentBlock(t *testing.T) {
+	ctx := context.Background()
+	address := common.HexToAddress("0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc")
+
+	for _, deployed := range []int64{0, 1, 10008355, 20999999, 21000000} {
+		client := &fakeCode{deployed: deployed}
+		block, err := DeploymentBlock(ctx, client, address, 21000000)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(deployed), block)
+		assert.LessOrEqual(t, client.calls, 26)
+	}
+
+	_, err := DeploymentBlock(ctx, &fakeCode{deployed: -1}, address, 21000000)
+	assert.ErrorIs(t, err, ErrNotDeployed)
+
+	_, err = DeploymentBlock(ctx, &fakeCode{err: errors.New("missing trie node")}, address, 21000000)
+	assert.EqualError(t, err, "failed to get code of 0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc at block 21000000: missing trie node")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"unicode"
 
 	"hw/internal/indexer/abis"
 	"hw/internal/model"
@@ -18,6 +19,28 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// SnakeCase converts a camelCase or PascalCase name to snake_case, e.g. PairDayData to
+// pair_day_data and USDCPrice to usdc_price.
+func SnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a word after a lowercase letter or digit, and at the last capital of an acronym
+			if i > 0 && runes[i-1] != '_' {
+				afterWord := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+				beforeWord := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if afterWord || beforeWord {
+					b.WriteByte('_')
+				}
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 // LoadABI loads and parses the specified ABI file.
 //
 //	abiName: the name of the ABI file without the .json extension.
@@ -56,7 +79,7 @@ func GetTokenInfo(ctx context.Context, client *ethclient.Client, tokenId string,
 		if err != nil {
 			return fmt.Errorf("error reading token contract(decimals): %+v", err)
 		}
-		token.Decimals = bigrat.NewBigN(result.([]interface{})[0]).ToTruncateInt64(0)
+		token.Decimals = bigrat.New(result.([]interface{})[0]).ToTruncateInt64(0)
 		return nil
 	})
 	g.Go(func() error {
@@ -94,7 +117,8 @@ func GetTokenInfo(ctx context.Context, client *ethclient.Client, tokenId string,
 	return token, nil
 }
 
-// ReadContract reads data from the specified contract.
+// ReadContract reads data from the specified contract. Results of calls at a block are cached,
+// see SetCallCache.
 func ReadContract(client *ethclient.Client, contractAddress common.Address, contractABI abi.ABI, startBlock *big.Int, functionName string, functionParams ...interface{}) (interface{}, error) {
 	method, exists := contractABI.Methods[functionName]
 	if !exists {
@@ -122,7 +146,7 @@ func ReadContract(client *ethclient.Client, contractAddress common.Address, cont
 		Data: data,
 	}
 
-	result, err := client.CallContract(context.Background(), msg, startBlock)
+	result, err := callContract(context.Background(), client, msg, startBlock)
 	if err != nil {
 		return nil, fmt.Errorf("error calling contract: %w", err)
 	}
@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignVerify tests that a signed token verifies until it expires, and only with its secret.
+func TestSignVerify(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Unix(1728000000, 0)
+	claims := Claims{Subject: "0xabc", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()}
+
+	token, err := Sign(claims, secret)
+	assert.NoError(t, err)
+
+	got, err := Verify(token, secret, now)
+	assert.NoError(t, err)
+	assert.Equal(t, &claims, got)
+
+	_, err = Verify(token, secret, now.Add(time.Hour))
+	assert.Equal(t, ErrExpiredToken, err)
+
+	_, err = Verify(token, []byte("another secret"), now)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+// TestVerify_Invalid tests that tampered and unsigned tokens are rejected.
+func TestVerify_Invalid(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Unix(1728000000, 0)
+	token, err := Sign(Claims{Subject: "0xabc", ExpiresAt: now.Add(time.Hour).Unix()}, secret)
+	assert.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"0xdef","exp":1828000000}`))
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	for name, token := range map[string]string{
+		"tampered payload": parts[0] + "." + forged + "." + parts[2],
+		"alg none":         unsigned + "." + parts[1] + ".",
+		"missing part":     parts[0] + "." + parts[1],
+		"empty":            "",
+	} {
+		_, err := Verify(token, secret, now)
+		assert.Equal(t, ErrInvalidToken, err, name)
+	}
+}
@@ -0,0 +1,11 @@
+package logger
+
+import (
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+)
+
+// NewFxLogger returns an fx event logger writing the application lifecycle events to l.
+func NewFxLogger(l *zap.Logger) fxevent.Logger {
+	return &fxevent.ZapLogger{Logger: l}
+}
@@ -1,9 +1,13 @@
 package logger
 
 import (
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"hw/pkg/config"
+
 	"github.com/golang-module/carbon/v2"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -56,46 +60,83 @@ func GetLogger() *zap.Logger {
 	return zap.L()
 }
 
-// Init initializes the global logger with the specified configuration.
-func Init() *zap.Logger {
-	cfg := zap.NewProductionEncoderConfig()
-	cfg.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString(carbon.CreateFromTimestamp(t.Unix()).ToDateTimeString())
+// Options configures the logger output.
+type Options struct {
+	Level            string // debug, info, warn, error
+	Format           string // console or json
+	SampleInitial    int    // entries per second logged in full for each message; 0 disables sampling
+	SampleThereafter int    // after SampleInitial, only every Nth entry is logged
+}
+
+// level is shared by every logger built by Init so it can be changed at runtime.
+var level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
+// OptionsFromConfig returns the options of the log configuration.
+func OptionsFromConfig(cfg config.Log) Options {
+	return Options{
+		Level:            cfg.Level,
+		Format:           cfg.Format,
+		SampleInitial:    cfg.SampleInitial,
+		SampleThereafter: cfg.SampleThereafter,
+	}
+}
+
+// New builds a logger writing to ws with the given options.
+// An unknown level falls back to debug and an unknown format to console.
+func New(opts Options, ws zapcore.WriteSyncer) *zap.Logger {
+	if err := SetLevel(opts.Level); err != nil {
+		level.SetLevel(zapcore.DebugLevel)
 	}
-	cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
-
-	levelEnabler := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= zapcore.DebugLevel
-	})
-
-	core := zapcore.NewCore(
-		zapcore.NewConsoleEncoder(cfg),
-		os.Stderr,
-		levelEnabler,
-		// zapcore.AddSync(&lumberjack.Logger{
-		// 	Filename:   fmt.Sprintf("%s/%s.log", targetLogFolder, name),
-		// 	MaxSize:    600,
-		// 	MaxBackups: 3,
-		// 	MaxAge:     3,
-		// 	Compress:   true,
-		// 	LocalTime:  true,
-		// }),
-	)
-
-	options := []zap.Option{
-		// zap.AddCaller(),
-		// zap.Hooks(func(e zapcore.Entry) error {
-		// 	// log.Printf("%-5s| %s %+v", strings.ToUpper(e.Level.String()), e.Time.UTC().Format("2006-01-02T15:04:05"), e.Message)
-		// 	if e.Level > zapcore.DebugLevel {
-		// 		log.Printf("%-5s| %+v", strings.ToUpper(e.Level.String()), e.Message)
-		// 	}
-		// 	return nil
-		// }),
+
+	var encoder zapcore.Encoder
+	if strings.EqualFold(opts.Format, "json") {
+		cfg := zap.NewProductionEncoderConfig()
+		cfg.TimeKey = "timestamp"
+		cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewJSONEncoder(cfg)
+	} else {
+		cfg := zap.NewProductionEncoderConfig()
+		cfg.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendString(carbon.CreateFromTimestamp(t.Unix()).ToDateTimeString())
+		}
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(cfg)
 	}
 
-	logger := zap.New(core, options...)
+	core := zapcore.NewCore(encoder, ws, level)
+
+	// Sampling keeps high-volume indexer logs in check
+	if opts.SampleInitial > 0 {
+		thereafter := opts.SampleThereafter
+		if thereafter <= 0 {
+			thereafter = 100
+		}
+		core = zapcore.NewSamplerWithOptions(core, time.Second, opts.SampleInitial, thereafter)
+	}
+
+	return zap.New(core)
+}
+
+// Init initializes the global logger from the log configuration.
+func Init(cfg config.Log) *zap.Logger {
+	logger := New(OptionsFromConfig(cfg), os.Stderr)
 
 	zap.ReplaceGlobals(logger)
 
 	return logger
 }
+
+// SetLevel changes the level of every logger built by Init or New.
+func SetLevel(lvl string) error {
+	return level.UnmarshalText([]byte(strings.ToLower(lvl)))
+}
+
+// Level returns the current log level.
+func Level() string {
+	return level.String()
+}
+
+// LevelHandler serves the current level on GET and changes it on PUT with a body like {"level":"info"}.
+func LevelHandler() http.Handler {
+	return level
+}
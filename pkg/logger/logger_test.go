@@ -3,8 +3,13 @@ package logger
 import (
 	"bytes"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"hw/pkg/config"
+
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -221,10 +226,66 @@ func TestInfoField(t *testing.T) {
 }
 
 func TestInit(t *testing.T) {
-	logger := Init()
+	logger := Init(config.Default().Log)
 	defer logger.Sync()
 
 	// Assert
 	assert.NotNil(t, logger, "Logger should not be nil")
 	assert.Equal(t, zap.L(), logger, "Global logger should be equal to the initialized logger")
 }
+
+func TestNew_JSONFormatAndLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Options{Level: "warn", Format: "json"}, zapcore.AddSync(&buf))
+	defer SetLevel("debug")
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 1)
+	entry := parseLogEntry(t, string(logLines[0]))
+	assert.Equal(t, "kept", entry["msg"])
+	assert.Equal(t, "warn", Level())
+}
+
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Options{Level: "info", Format: "json"}, zapcore.AddSync(&buf))
+	defer SetLevel("debug")
+
+	logger.Debug("dropped")
+	assert.NoError(t, SetLevel("DEBUG"))
+	logger.Debug("kept")
+
+	assert.NotContains(t, buf.String(), "dropped")
+	assert.Contains(t, buf.String(), "kept")
+	assert.Error(t, SetLevel("verbose"))
+}
+
+// TestLevelHandler tests reading and changing the log level through the admin handler.
+func TestLevelHandler(t *testing.T) {
+	defer SetLevel("debug")
+
+	w := httptest.NewRecorder()
+	LevelHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"warn"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "warn", Level())
+
+	w = httptest.NewRecorder()
+	LevelHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+	assert.Contains(t, w.Body.String(), `"level":"warn"`)
+}
+
+func TestNew_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Options{Level: "info", Format: "json", SampleInitial: 2, SampleThereafter: 1000}, zapcore.AddSync(&buf))
+	defer SetLevel("debug")
+
+	for i := 0; i < 10; i++ {
+		logger.Info("repeated")
+	}
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 2)
+}
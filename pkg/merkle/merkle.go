@@ -0,0 +1,142 @@
+// Package merkle builds the leaves and trees checked by Uniswap's MerkleDistributor contract.
+package merkle
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Leaf returns keccak256(abi.encodePacked(uint256 index, address account, uint256 amount)),
+// the leaf MerkleDistributor.claim verifies.
+func Leaf(index uint64, account common.Address, amount *big.Int) common.Hash {
+	return crypto.Keccak256Hash(
+		common.LeftPadBytes(new(big.Int).SetUint64(index).Bytes(), 32),
+		account.Bytes(),
+		common.LeftPadBytes(amount.Bytes(), 32),
+	)
+}
+
+// Tree is a Merkle tree over sorted, deduplicated leaves whose pairs are hashed in sorted order,
+// as built by Uniswap's merkle-distributor scripts and verified by OpenZeppelin's MerkleProof.
+// The last node of a layer with an odd number of nodes moves up unhashed.
+type Tree struct {
+	layers [][]common.Hash
+}
+
+// NewTree builds the tree of leaves.
+func NewTree(leaves []common.Hash) *Tree {
+	layer := append([]common.Hash(nil), leaves...)
+	sort.Slice(layer, func(i, j int) bool { return bytes.Compare(layer[i][:], layer[j][:]) < 0 })
+	deduped := layer[:0]
+	for i, leaf := range layer {
+		if i == 0 || leaf != layer[i-1] {
+			deduped = append(deduped, leaf)
+		}
+	}
+
+	layers := [][]common.Hash{deduped}
+	for layer := deduped; len(layer) > 1; {
+		next := make([]common.Hash, 0, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				next = append(next, layer[i])
+				continue
+			}
+			next = append(next, hashPair(layer[i], layer[i+1]))
+		}
+		layers = append(layers, next)
+		layer = next
+	}
+	return &Tree{layers: layers}
+}
+
+// Root returns the root of the tree, or the zero hash when it has no leaves.
+func (t *Tree) Root() common.Hash {
+	top := t.layers[len(t.layers)-1]
+	if len(top) == 0 {
+		return common.Hash{}
+	}
+	return top[0]
+}
+
+// Proof returns the sibling hashes proving leaf, from the leaf layer up, and whether leaf is in the tree.
+func (t *Tree) Proof(leaf common.Hash) ([]common.Hash, bool) {
+	leaves := t.layers[0]
+	index := sort.Search(len(leaves), func(i int) bool { return bytes.Compare(leaves[i][:], leaf[:]) >= 0 })
+	if index == len(leaves) || leaves[index] != leaf {
+		return nil, false
+	}
+
+	proof := []common.Hash{}
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		if sibling := index ^ 1; sibling < len(layer) {
+			proof = append(proof, layer[sibling])
+		}
+		index /= 2
+	}
+	return proof, true
+}
+
+// Verify reports whether proof proves leaf against root.
+func Verify(proof []common.Hash, root, leaf common.Hash) bool {
+	computed := leaf
+	for _, sibling := range proof {
+		computed = hashPair(computed, sibling)
+	}
+	return computed == root
+}
+
+// hashPair hashes two nodes in sorted order, so a proof does not need to tell left from right.
+func hashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
+
+// Balance is the amount an account can claim from a distribution.
+type Balance struct {
+	Account common.Address
+	Amount  *big.Int
+}
+
+// Claim is an account's entry in a distribution: the arguments of MerkleDistributor.claim.
+type Claim struct {
+	Index   uint64
+	Account common.Address
+	Amount  *big.Int
+	Proof   []common.Hash
+}
+
+// Distribution is the tree of a set of balances and the claim of every account.
+type Distribution struct {
+	Root       common.Hash
+	TokenTotal *big.Int
+	Claims     []Claim
+}
+
+// NewDistribution indexes the balances by account address and builds their tree.
+// Every account must appear once.
+func NewDistribution(balances []Balance) *Distribution {
+	sorted := append([]Balance(nil), balances...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].Account[:], sorted[j].Account[:]) < 0 })
+
+	leaves := make([]common.Hash, len(sorted))
+	total := new(big.Int)
+	for i, balance := range sorted {
+		leaves[i] = Leaf(uint64(i), balance.Account, balance.Amount)
+		total.Add(total, balance.Amount)
+	}
+	tree := NewTree(leaves)
+
+	claims := make([]Claim, len(sorted))
+	for i, balance := range sorted {
+		proof, _ := tree.Proof(leaves[i])
+		claims[i] = Claim{Index: uint64(i), Account: balance.Account, Amount: balance.Amount, Proof: proof}
+	}
+	return &Distribution{Root: tree.Root(), TokenTotal: total, Claims: claims}
+}
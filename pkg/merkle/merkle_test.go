@@ -0,0 +1,56 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLeaf tests the packed encoding of a leaf: 32-byte index, 20-byte account, 32-byte amount.
+func TestLeaf(t *testing.T) {
+	account := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	packed := append(common.LeftPadBytes([]byte{5}, 32), account.Bytes()...)
+	packed = append(packed, common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...)
+
+	assert.Equal(t, crypto.Keccak256Hash(packed), Leaf(5, account, big.NewInt(1000)))
+}
+
+// TestNewDistribution tests that every claim verifies against the root for trees of every shape.
+func TestNewDistribution(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		balances := make([]Balance, n)
+		for i := range balances {
+			// Descending accounts, so indexes follow the sorted order rather than the input order
+			balances[i] = Balance{Account: common.BigToAddress(big.NewInt(int64(100 - i))), Amount: big.NewInt(int64(i + 1))}
+		}
+
+		distribution := NewDistribution(balances)
+
+		assert.Len(t, distribution.Claims, n)
+		assert.Equal(t, big.NewInt(int64(n*(n+1)/2)), distribution.TokenTotal)
+		for i, claim := range distribution.Claims {
+			assert.Equal(t, uint64(i), claim.Index)
+			assert.Equal(t, balances[n-1-i].Account, claim.Account)
+			leaf := Leaf(claim.Index, claim.Account, claim.Amount)
+			assert.True(t, Verify(claim.Proof, distribution.Root, leaf), "claim %d of %d", i, n)
+			assert.False(t, Verify(claim.Proof, distribution.Root, Leaf(claim.Index, claim.Account, big.NewInt(1000))))
+		}
+	}
+}
+
+// TestTree tests the root of small trees and proofs of unknown leaves.
+func TestTree(t *testing.T) {
+	a, b, c := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+
+	assert.Equal(t, common.Hash{}, NewTree(nil).Root())
+	assert.Equal(t, a, NewTree([]common.Hash{a}).Root())
+	assert.Equal(t, crypto.Keccak256Hash(a[:], b[:]), NewTree([]common.Hash{b, a}).Root())
+	assert.Equal(t, hashPair(hashPair(a, b), c), NewTree([]common.Hash{c, b, a, b}).Root())
+
+	proof, ok := NewTree([]common.Hash{a, b}).Proof(c)
+	assert.False(t, ok)
+	assert.Nil(t, proof)
+}
@@ -12,8 +12,8 @@ import (
 
 // HTTPErrorLogging is a middleware function that logs HTTP request details including errors.
 func HTTPErrorLogging(w http.ResponseWriter, r *http.Request, err error) {
-	// Retrieve the request ID from the context
-	requestID := r.Context().Value("requestid").(string)
+	// Retrieve the request ID from the context; it is empty outside the request ID middleware
+	requestID, _ := r.Context().Value("requestid").(string)
 
 	// Initialize log fields with common request information
 	logFields := []zap.Field{
@@ -1,16 +1,72 @@
 package server
 
 import (
+	"fmt"
+	"net/http"
+	"time"
+
 	"hw/pkg/logger"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/cors"
 )
 
-// NewHTTPServer initializes and returns a new Chi router.
-func NewHTTPServer() *chi.Mux {
+// Option installs a middleware on the router created by NewHTTPServer.
+type Option func(router *chi.Mux)
+
+// WithCORS lets browser frontends served from origins call the router without a proxy: it
+// answers their preflight requests and allows their requests with the given methods and
+// request headers. An origin may be "*" or contain one wildcard, e.g. "https://*.example.com".
+func WithCORS(origins, methods, headers []string) Option {
+	return func(router *chi.Mux) {
+		router.Use(cors.Handler(cors.Options{
+			AllowedOrigins: origins,
+			AllowedMethods: methods,
+			AllowedHeaders: headers,
+			MaxAge:         300,
+		}))
+	}
+}
+
+// WithSecurityHeaders sets the standard security headers on every response: nosniff content
+// types, no framing and no referrer, plus Strict-Transport-Security with hstsMaxAge, covering
+// subdomains, when it is positive. Browsers ignore HSTS over plain HTTP, so enable it only
+// when the API is served over HTTPS.
+func WithSecurityHeaders(hstsMaxAge time.Duration) Option {
+	return func(router *chi.Mux) {
+		router.Use(SecurityHeaders(hstsMaxAge))
+	}
+}
+
+// SecurityHeaders returns the middleware of WithSecurityHeaders.
+func SecurityHeaders(hstsMaxAge time.Duration) func(http.Handler) http.Handler {
+	hsts := ""
+	if hstsMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", int64(hstsMaxAge/time.Second))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("X-Content-Type-Options", "nosniff")
+			header.Set("X-Frame-Options", "DENY")
+			header.Set("Referrer-Policy", "no-referrer")
+			if hsts != "" {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NewHTTPServer initializes and returns a new Chi router with the middleware of opts installed
+// in order, before any other.
+func NewHTTPServer(opts ...Option) *chi.Mux {
 	logger.Infof("Initializing Chi router.")
 
 	router := chi.NewRouter()
+	for _, opt := range opts {
+		opt(router)
+	}
 
 	return router
 }
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -13,3 +16,61 @@ func TestNewHTTPServer(t *testing.T) {
 	assert.NotNil(t, router, "Router should not be nil")
 	assert.IsType(t, &chi.Mux{}, router, "Should return an instance of *chi.Mux type")
 }
+
+// TestWithCORS tests that preflight and simple requests are answered for allowed origins only.
+func TestWithCORS(t *testing.T) {
+	router := NewHTTPServer(WithCORS([]string{"https://app.example.com", "https://*.preview.example.com"}, []string{"GET", "POST"}, []string{"Content-Type", "X-API-Key"}))
+	router.Get("/ping", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("pong")) })
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		headers     map[string]string
+		wantOrigin  string
+		wantMethods string
+	}{
+		{name: "preflight", method: http.MethodOptions, origin: "https://app.example.com", headers: map[string]string{"Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "X-API-Key"}, wantOrigin: "https://app.example.com", wantMethods: "POST"},
+		{name: "preflight of a wildcard origin", method: http.MethodOptions, origin: "https://pr-12.preview.example.com", headers: map[string]string{"Access-Control-Request-Method": "GET"}, wantOrigin: "https://pr-12.preview.example.com", wantMethods: "GET"},
+		{name: "preflight of a method not allowed", method: http.MethodOptions, origin: "https://app.example.com", headers: map[string]string{"Access-Control-Request-Method": "DELETE"}},
+		{name: "preflight of another origin", method: http.MethodOptions, origin: "https://evil.example.com", headers: map[string]string{"Access-Control-Request-Method": "GET"}},
+		{name: "request", method: http.MethodGet, origin: "https://app.example.com", wantOrigin: "https://app.example.com"},
+		{name: "request from another origin", method: http.MethodGet, origin: "https://evil.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/ping", nil)
+			req.Header.Set("Origin", tt.origin)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantOrigin, rr.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.wantMethods, rr.Header().Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
+
+// TestWithSecurityHeaders tests that the security headers are set, with HSTS only when enabled.
+func TestWithSecurityHeaders(t *testing.T) {
+	for _, tt := range []struct {
+		maxAge   time.Duration
+		wantHSTS string
+	}{
+		{maxAge: 365 * 24 * time.Hour, wantHSTS: "max-age=31536000; includeSubDomains"},
+		{maxAge: 0},
+	} {
+		router := NewHTTPServer(WithSecurityHeaders(tt.maxAge))
+		router.Get("/ping", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("pong")) })
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", rr.Header().Get("X-Frame-Options"))
+		assert.Equal(t, "no-referrer", rr.Header().Get("Referrer-Policy"))
+		assert.Equal(t, tt.wantHSTS, rr.Header().Get("Strict-Transport-Security"))
+	}
+}
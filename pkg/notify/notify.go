@@ -0,0 +1,139 @@
+// Package notify delivers notifications to users by email or through a webhook.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"hw/pkg/config"
+	"hw/pkg/logger"
+	"hw/pkg/request"
+)
+
+// Supported senders.
+const (
+	SenderLog     = "log"
+	SenderSMTP    = "smtp"
+	SenderWebhook = "webhook"
+)
+
+// Message is a plain-text notification to an email address.
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Sender delivers messages.
+type Sender interface {
+	// Send delivers a message, returning once it was accepted for delivery.
+	Send(ctx context.Context, msg Message) error
+}
+
+// New creates the Sender of the configured kind.
+func New(cfg config.Notifications) (Sender, error) {
+	switch cfg.Sender {
+	case SenderLog:
+		return LogSender{}, nil
+	case SenderSMTP:
+		return NewSMTPSender(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	case SenderWebhook:
+		if cfg.WebhookURL == "" {
+			return nil, errors.New("notifications webhook URL is required for sender webhook")
+		}
+		return NewWebhookSender(cfg.WebhookURL), nil
+	default:
+		return nil, fmt.Errorf("unknown notifications sender: %s", cfg.Sender)
+	}
+}
+
+// LogSender logs messages instead of delivering them, for development.
+type LogSender struct{}
+
+// Send logs the message.
+func (LogSender) Send(_ context.Context, msg Message) error {
+	logger.Infof("Notification to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// SMTPSender sends messages as plain-text emails through an SMTP server.
+type SMTPSender struct {
+	addr     string
+	from     string
+	auth     smtp.Auth
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPSender creates an SMTPSender on the server at addr (host:port), authenticating with
+// PLAIN auth when username is set.
+func NewSMTPSender(addr, username, password, from string) *SMTPSender {
+	s := &SMTPSender{addr: addr, from: from, sendMail: smtp.SendMail}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+// Send sends the message as an email.
+func (s *SMTPSender) Send(_ context.Context, msg Message) error {
+	data, err := s.format(msg, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := s.sendMail(s.addr, s.auth, s.from, []string{msg.To}, data); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// format encodes msg as an RFC 5322 email, rejecting header values that would inject headers.
+func (s *SMTPSender) format(msg Message, date time.Time) ([]byte, error) {
+	for _, value := range []string{s.from, msg.To, msg.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid email header value %q", value)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
+
+// WebhookSender posts each message as JSON to a URL, such as a mail relay or a chat integration.
+type WebhookSender struct {
+	url string
+}
+
+// NewWebhookSender creates a WebhookSender posting to url.
+func NewWebhookSender(url string) *WebhookSender {
+	return &WebhookSender{url: url}
+}
+
+// Send posts the message, retrying on 429 and 5xx responses.
+func (s *WebhookSender) Send(ctx context.Context, msg Message) error {
+	client := request.NewClient(request.Timeout("10s"), request.RetryOnServerError())
+	res, err := client.SetHeaders(map[string]string{"Content-Type": "application/json"}).SetBody(msg).
+		DoWithContext(ctx, http.MethodPost, s.url)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("failed to post notification: %w", &request.HTTPError{StatusCode: res.StatusCode, Body: res.Data})
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"hw/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNew tests that the configured sender is created.
+func TestNew(t *testing.T) {
+	cfg := config.Default().Notifications
+	sender, err := New(cfg)
+	assert.NoError(t, err)
+	assert.IsType(t, LogSender{}, sender)
+
+	cfg.Sender = SenderWebhook
+	_, err = New(cfg)
+	assert.Error(t, err)
+
+	cfg.Sender = "pigeon"
+	_, err = New(cfg)
+	assert.Error(t, err)
+}
+
+// TestSMTPSender tests the email sent for a message and the rejection of header injection.
+func TestSMTPSender(t *testing.T) {
+	sender := NewSMTPSender("smtp.example.com:587", "user", "password", "notifications@example.com")
+	var (
+		gotAddr string
+		gotTo   []string
+		gotMsg  []byte
+	)
+	sender.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, msg
+		return nil
+	}
+
+	err := sender.Send(context.Background(), Message{To: "user@example.com", Subject: "Weekly summary", Body: "line 1\nline 2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.Equal(t, []string{"user@example.com"}, gotTo)
+	assert.Contains(t, string(gotMsg), "To: user@example.com\r\nSubject: Weekly summary\r\n")
+	assert.Contains(t, string(gotMsg), "\r\n\r\nline 1\r\nline 2")
+
+	_, err = sender.format(Message{To: "user@example.com\r\nBcc: victim@example.com"}, time.Now())
+	assert.Error(t, err)
+}
+
+// TestWebhookSender tests that messages are posted as JSON and non-2xx responses fail.
+func TestWebhookSender(t *testing.T) {
+	status := http.StatusOK
+	var got Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(status)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	sender := NewWebhookSender(srv.URL)
+	msg := Message{To: "user@example.com", Subject: "Big swap", Body: "body"}
+
+	assert.NoError(t, sender.Send(context.Background(), msg))
+	assert.Equal(t, msg, got)
+
+	status = http.StatusBadRequest
+	assert.Error(t, sender.Send(context.Background(), msg))
+}
@@ -15,6 +15,7 @@ import (
 
 	pgx "github.com/jackc/pgx/v5"
 	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pgxpool "github.com/jackc/pgx/v5/pgxpool"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +42,21 @@ func (m *MockPgxPool) EXPECT() *MockPgxPoolMockRecorder {
 	return m.recorder
 }
 
+// Acquire mocks base method.
+func (m *MockPgxPool) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx)
+	ret0, _ := ret[0].(*pgxpool.Conn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockPgxPoolMockRecorder) Acquire(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockPgxPool)(nil).Acquire), ctx)
+}
+
 // Begin mocks base method.
 func (m *MockPgxPool) Begin(ctx context.Context) (pgx.Tx, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/mock/gomock"
+)
+
+// Query returns a matcher of the SQL of a statement named by pg.Queries, so expectations do not
+// repeat its text.
+func Query(name string) gomock.Matcher {
+	return queryMatcher(name)
+}
+
+type queryMatcher string
+
+func (m queryMatcher) Matches(x any) bool {
+	sql, ok := x.(string)
+	return ok && strings.HasPrefix(sql, "-- name: "+string(m)+"\n")
+}
+
+func (m queryMatcher) String() string {
+	return fmt.Sprintf("is query %s", string(m))
+}
@@ -3,8 +3,8 @@ package pg
 import (
 	"context"
 	"fmt"
-
-	"hw/pkg/common"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -15,6 +15,7 @@ import (
 
 // PgxPool defines the methods required by pgxpool.Pool.
 type PgxPool interface {
+	Acquire(ctx context.Context) (*pgxpool.Conn, error)
 	Begin(ctx context.Context) (pgx.Tx, error)
 	Close()
 	Ping(ctx context.Context) error
@@ -34,7 +35,55 @@ type PgxRows interface {
 
 // PostgresDB encapsulates a pgx connection pool.
 type PostgresDB struct {
-	pool PgxPool
+	pool             PgxPool
+	tracer           *QueryTracer
+	queries          *Queries
+	statementTimeout time.Duration
+	searchPath       string
+}
+
+// Option configures a PostgresDB.
+type Option func(*PostgresDB)
+
+// WithQueryTracer times the queries of the pool with tracer.
+func WithQueryTracer(tracer *QueryTracer) Option {
+	return func(db *PostgresDB) {
+		db.tracer = tracer
+	}
+}
+
+// WithPreparedQueries prepares the statements of queries on every new connection of the pool.
+func WithPreparedQueries(queries *Queries) Option {
+	return func(db *PostgresDB) {
+		db.queries = queries
+	}
+}
+
+// WithStatementTimeout sets the statement_timeout of the sessions of the pool, so the server
+// cancels a statement running longer, e.g. an aggregate whose client is gone. Zero keeps the
+// server's setting.
+func WithStatementTimeout(timeout time.Duration) Option {
+	return func(db *PostgresDB) {
+		db.statementTimeout = timeout
+	}
+}
+
+// WithSearchPath resolves the unqualified tables of the sessions of the pool in schema first, then
+// in public, e.g. to bind a pool to a scratch schema.
+func WithSearchPath(schema string) Option {
+	return func(db *PostgresDB) {
+		db.searchPath = schema + ", public"
+	}
+}
+
+// QueryTracer returns the tracer timing the queries of the pool, or nil.
+func (db *PostgresDB) QueryTracer() *QueryTracer {
+	return db.tracer
+}
+
+// Acquire reserves a single connection, e.g. for session-scoped advisory locks.
+func (db *PostgresDB) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	return db.pool.Acquire(ctx)
 }
 
 func (db *PostgresDB) Begin(ctx context.Context) (pgx.Tx, error) {
@@ -61,11 +110,10 @@ func (db *PostgresDB) QueryRow(ctx context.Context, sql string, args ...any) pgx
 	return db.pool.QueryRow(ctx, sql, args...)
 }
 
-// NewPostgresDB creates and initializes a new instance of PostgresDB.
-func NewPostgresDB() (*PostgresDB, error) {
-	connString := common.GetEnv("DATABASE_URL", "")
+// NewPostgresDB creates and initializes a new instance of PostgresDB connected to connString.
+func NewPostgresDB(connString string, opts ...Option) (*PostgresDB, error) {
 	if connString == "" {
-		return nil, fmt.Errorf("DATABASE_URL is not set")
+		return nil, fmt.Errorf("database URL is not set")
 	}
 
 	// Set up the connection pool configuration.
@@ -74,6 +122,26 @@ func NewPostgresDB() (*PostgresDB, error) {
 		return nil, fmt.Errorf("failed to parse connection string: %w", err)
 	}
 
+	db := &PostgresDB{}
+	for _, opt := range opts {
+		opt(db)
+	}
+	if db.tracer != nil {
+		config.ConnConfig.Tracer = db.tracer
+	}
+	if db.statementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(db.statementTimeout.Milliseconds(), 10)
+	}
+	if db.searchPath != "" {
+		config.ConnConfig.RuntimeParams["search_path"] = db.searchPath
+	}
+	if db.queries != nil {
+		config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+			_, err := db.queries.Prepare(ctx, conn)
+			return err
+		}
+	}
+
 	// Create the connection pool.
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
@@ -85,5 +153,6 @@ func NewPostgresDB() (*PostgresDB, error) {
 		return nil, fmt.Errorf("connection test failed: %w", err)
 	}
 
-	return &PostgresDB{pool: pool}, nil
+	db.pool = pool
+	return db, nil
 }
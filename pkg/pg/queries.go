@@ -0,0 +1,98 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"hw/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// queryNamePrefix starts the first line of a named query, as in sqlc.
+const queryNamePrefix = "-- name: "
+
+// Queries is a registry of named SQL statements. A named statement is its SQL prefixed by a
+// "-- name: <name>" line, so it is recognized in logs, metrics, pg_stat_statements and mocked
+// expectations, and is executed as is: a pool created WithPreparedQueries prepares every
+// registered statement on each new connection, and pgx then reuses its plan for every execution.
+type Queries struct {
+	mutex sync.RWMutex
+	sql   map[string]string
+}
+
+// NewQueries creates an empty registry.
+func NewQueries() *Queries {
+	return &Queries{sql: make(map[string]string)}
+}
+
+// Add registers sql under name and returns the named statement. It panics when name is already
+// registered, as statements are registered once when their package is initialized.
+func (q *Queries) Add(name, sql string) string {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if _, exists := q.sql[name]; exists {
+		panic(fmt.Sprintf("pg: query %s registered twice", name))
+	}
+	named := queryNamePrefix + name + "\n" + strings.TrimPrefix(sql, "\n")
+	q.sql[name] = named
+	return named
+}
+
+// SQL returns the named statement registered under name.
+func (q *Queries) SQL(name string) (string, bool) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	sql, exists := q.sql[name]
+	return sql, exists
+}
+
+// Names returns the names of the registered statements in order.
+func (q *Queries) Names() []string {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	names := make([]string, 0, len(q.sql))
+	for name := range q.sql {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Prepare prepares every registered statement on a connection and returns the number prepared.
+// A statement that cannot be prepared, e.g. before the migration creating its table has run, is
+// skipped: pgx prepares it on its first execution instead.
+func (q *Queries) Prepare(ctx context.Context, conn *pgx.Conn) (int, error) {
+	prepared := 0
+	var failed []string
+	for _, name := range q.Names() {
+		sql, _ := q.SQL(name)
+		// Preparing under the SQL itself lets Query, QueryRow and Exec find the statement by its text
+		if _, err := conn.Prepare(ctx, sql, sql); err != nil {
+			if ctx.Err() != nil {
+				return prepared, ctx.Err()
+			}
+			failed = append(failed, name)
+			continue
+		}
+		prepared++
+	}
+	if len(failed) > 0 {
+		logger.Warnf("Could not prepare %d queries, they are prepared on first use: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return prepared, nil
+}
+
+// QueryName returns the name of a named statement, or "" for any other SQL.
+func QueryName(sql string) string {
+	if !strings.HasPrefix(sql, queryNamePrefix) {
+		return ""
+	}
+	name, _, _ := strings.Cut(sql[len(queryNamePrefix):], "\n")
+	return name
+}
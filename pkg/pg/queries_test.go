@@ -0,0 +1,47 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQueries_Add tests that a statement is registered under its name, and that a name cannot be registered twice.
+func TestQueries_Add(t *testing.T) {
+	queries := NewQueries()
+	sql := queries.Add("GetToken", `
+	SELECT "id" FROM "tokens" WHERE "id" = $1
+`)
+	assert.Equal(t, "-- name: GetToken\n\tSELECT \"id\" FROM \"tokens\" WHERE \"id\" = $1\n", sql)
+	queries.Add("CreateToken", `INSERT INTO "tokens" ("id") VALUES ($1)`)
+
+	registered, exists := queries.SQL("GetToken")
+	assert.True(t, exists)
+	assert.Equal(t, sql, registered)
+	_, exists = queries.SQL("DeleteToken")
+	assert.False(t, exists)
+	assert.Equal(t, []string{"CreateToken", "GetToken"}, queries.Names())
+
+	assert.Panics(t, func() { queries.Add("GetToken", `SELECT 1`) })
+}
+
+// TestQueryName tests that the name of a named statement is read from its first line.
+func TestQueryName(t *testing.T) {
+	queries := NewQueries()
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{name: "named", sql: queries.Add("GetToken", `SELECT 1`), want: "GetToken"},
+		{name: "name only", sql: "-- name: GetToken", want: "GetToken"},
+		{name: "unnamed", sql: `SELECT 1`, want: ""},
+		{name: "other comment", sql: "-- get a token\nSELECT 1", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, QueryName(tt.sql))
+		})
+	}
+}
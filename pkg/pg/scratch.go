@@ -0,0 +1,145 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// scratchSchemaName is the form of scratch schema names, so they never need quoting in a search_path.
+var scratchSchemaName = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// CreateScratchSchema creates schema as a copy of the tables of public, with their data, defaults,
+// check constraints and indexes, so a pool bound to it with WithSearchPath writes nothing to public.
+// Partitioned tables are copied as plain tables and foreign keys are left out. Serial columns get
+// sequences of their own, continuing after the copied rows.
+func CreateScratchSchema(ctx context.Context, db PgxPool, schema string) error {
+	if !scratchSchemaName.MatchString(schema) || schema == "public" {
+		return fmt.Errorf("invalid scratch schema name: %q", schema)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tables, err := scratchTables(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "CREATE SCHEMA "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to create schema %s: %w", schema, err)
+	}
+
+	for _, table := range tables {
+		source, target := pgx.Identifier{"public", table}.Sanitize(), pgx.Identifier{schema, table}.Sanitize()
+		if _, err := tx.Exec(ctx, fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING INDEXES)", target, source)); err != nil {
+			return fmt.Errorf("failed to create table %s.%s: %w", schema, table, err)
+		}
+		if _, err := tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", target, source)); err != nil {
+			return fmt.Errorf("failed to copy table %s: %w", table, err)
+		}
+		if err := copySerials(ctx, tx, schema, table); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// scratchTables returns the tables of public copied into a scratch schema: every table and
+// partitioned table, but not the partitions, whose rows are copied through their parent.
+func scratchTables(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT c."relname"
+		FROM "pg_class" c
+		JOIN "pg_namespace" n ON n."oid" = c."relnamespace"
+		WHERE n."nspname" = 'public' AND c."relkind" IN ('r', 'p') AND NOT c."relispartition"
+		ORDER BY c."relname"`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	return tables, nil
+}
+
+// copySerials replaces the defaults of the serial columns of a copied table, which still draw from
+// the sequences of public, by sequences of the scratch schema starting after the copied rows.
+func copySerials(ctx context.Context, tx pgx.Tx, schema, table string) error {
+	rows, err := tx.Query(ctx, `
+		SELECT a."attname"
+		FROM "pg_attribute" a
+		WHERE a."attrelid" = $1::regclass AND a."attnum" > 0 AND NOT a."attisdropped"
+			AND pg_get_serial_sequence($1, a."attname") IS NOT NULL
+		ORDER BY a."attnum"`, pgx.Identifier{"public", table}.Sanitize())
+	if err != nil {
+		return fmt.Errorf("failed to list serial columns of %s: %w", table, err)
+	}
+	columns, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("failed to list serial columns of %s: %w", table, err)
+	}
+
+	target := pgx.Identifier{schema, table}.Sanitize()
+	for _, column := range columns {
+		sequence := pgx.Identifier{schema, table + "_" + column + "_seq"}.Sanitize()
+		statements := []string{
+			fmt.Sprintf("CREATE SEQUENCE %s OWNED BY %s.%s", sequence, target, pgx.Identifier{column}.Sanitize()),
+			fmt.Sprintf("SELECT setval('%s', COALESCE(MAX(%s), 0) + 1, false) FROM %s", sequence, pgx.Identifier{column}.Sanitize(), target),
+			fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT nextval('%s')", target, pgx.Identifier{column}.Sanitize(), sequence),
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return fmt.Errorf("failed to copy sequence of %s.%s: %w", table, column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ScratchRowCounts returns the number of rows of every table of a scratch schema.
+func ScratchRowCounts(ctx context.Context, db PgxPool, schema string) (map[string]int64, error) {
+	rows, err := db.Query(ctx, `
+		SELECT c."relname"
+		FROM "pg_class" c
+		JOIN "pg_namespace" n ON n."oid" = c."relnamespace"
+		WHERE n."nspname" = $1 AND c."relkind" = 'r'
+		ORDER BY c."relname"`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables of %s: %w", schema, err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables of %s: %w", schema, err)
+	}
+
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var count int64
+		if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM "+pgx.Identifier{schema, table}.Sanitize()).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count rows of %s.%s: %w", schema, table, err)
+		}
+		counts[table] = count
+	}
+	return counts, nil
+}
+
+// DropScratchSchema drops a scratch schema and everything in it.
+func DropScratchSchema(ctx context.Context, db PgxPool, schema string) error {
+	if !scratchSchemaName.MatchString(schema) || schema == "public" {
+		return fmt.Errorf("invalid scratch schema name: %q", schema)
+	}
+	if _, err := db.Exec(ctx, "DROP SCHEMA IF EXISTS "+pgx.Identifier{schema}.Sanitize()+" CASCADE"); err != nil {
+		return fmt.Errorf("failed to drop schema %s: %w", schema, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package pg
+
+import (
+	"context"
+	"testing"
+
+	"hw/pkg/pg/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// expectRows expects a query returning a single text column with values.
+func expectRows(rows *mocks.MockPgxRows, values ...string) {
+	for _, value := range values {
+		rows.EXPECT().Next().Return(true)
+		rows.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+			*(dest[0].(*string)) = value
+			return nil
+		})
+	}
+	rows.EXPECT().Next().Return(false)
+	rows.EXPECT().Err().Return(nil)
+	rows.EXPECT().Close()
+}
+
+// TestCreateScratchSchema tests that the tables of public are copied with their rows, and their
+// serial columns given sequences of the scratch schema, in a transaction.
+func TestCreateScratchSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockPool := mocks.NewMockPgxPool(ctrl)
+	mockTx := mocks.NewMockPgxTx(ctrl)
+	tableRows := mocks.NewMockPgxRows(ctrl)
+	columnRows := mocks.NewMockPgxRows(ctrl)
+	ctx := context.Background()
+
+	var statements []string
+	exec := func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+		statements = append(statements, sql)
+		return pgconn.CommandTag{}, nil
+	}
+
+	gomock.InOrder(
+		mockPool.EXPECT().Begin(ctx).Return(mockTx, nil),
+		mockTx.EXPECT().Query(ctx, gomock.Any()).Return(tableRows, nil),
+		mockTx.EXPECT().Exec(ctx, gomock.Any()).DoAndReturn(exec).Times(3),
+		mockTx.EXPECT().Query(ctx, gomock.Any(), `"public"."tokens"`).Return(columnRows, nil),
+		mockTx.EXPECT().Exec(ctx, gomock.Any()).DoAndReturn(exec).Times(3),
+		mockTx.EXPECT().Commit(ctx).Return(nil),
+		mockTx.EXPECT().Rollback(ctx).Return(nil),
+	)
+	expectRows(tableRows, "tokens")
+	expectRows(columnRows, "id")
+
+	assert.NoError(t, CreateScratchSchema(ctx, mockPool, "sim_campaign"))
+	assert.Equal(t, []string{
+		`CREATE SCHEMA "sim_campaign"`,
+		`CREATE TABLE "sim_campaign"."tokens" (LIKE "public"."tokens" INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING INDEXES)`,
+		`INSERT INTO "sim_campaign"."tokens" SELECT * FROM "public"."tokens"`,
+		`CREATE SEQUENCE "sim_campaign"."tokens_id_seq" OWNED BY "sim_campaign"."tokens"."id"`,
+		`SELECT setval('"sim_campaign"."tokens_id_seq"', COALESCE(MAX("id"), 0) + 1, false) FROM "sim_campaign"."tokens"`,
+		`ALTER TABLE "sim_campaign"."tokens" ALTER COLUMN "id" SET DEFAULT nextval('"sim_campaign"."tokens_id_seq"')`,
+	}, statements)
+}
+
+// TestScratchSchema_InvalidName tests that public and names needing quotes are rejected.
+func TestScratchSchema_InvalidName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockPool := mocks.NewMockPgxPool(ctrl)
+
+	for _, schema := range []string{"public", "", "Sim", "sim; DROP SCHEMA public", "1sim"} {
+		assert.ErrorContains(t, CreateScratchSchema(context.Background(), mockPool, schema), "invalid scratch schema name")
+		assert.ErrorContains(t, DropScratchSchema(context.Background(), mockPool, schema), "invalid scratch schema name")
+	}
+}
+
+// TestDropScratchSchema tests that a scratch schema is dropped with its tables.
+func TestDropScratchSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockPool := mocks.NewMockPgxPool(ctrl)
+	ctx := context.Background()
+
+	mockPool.EXPECT().Exec(ctx, `DROP SCHEMA IF EXISTS "sim_campaign" CASCADE`).Return(pgconn.NewCommandTag("DROP SCHEMA"), nil)
+
+	assert.NoError(t, DropScratchSchema(ctx, mockPool, "sim_campaign"))
+}
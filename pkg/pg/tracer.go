@@ -0,0 +1,227 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"hw/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultSlowQueryThreshold is the duration above which a query is logged when the database
+// does not configure slowQueryThreshold.
+var DefaultSlowQueryThreshold = 500 * time.Millisecond
+
+// QueryDurationBuckets are the upper bounds of the query duration histogram buckets; slower
+// queries fall in the last, unbounded bucket.
+var QueryDurationBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// QueryStats holds the counters and duration histogram recorded for a query name. Buckets[i]
+// counts the queries not slower than QueryDurationBuckets[i], and the last bucket the others.
+type QueryStats struct {
+	Count         uint64        `json:"count"`
+	Errors        uint64        `json:"errors"`
+	Slow          uint64        `json:"slow"`
+	TotalDuration time.Duration `json:"total_duration"`
+	MaxDuration   time.Duration `json:"max_duration"`
+	Buckets       []uint64      `json:"buckets"`
+}
+
+// QueryTracer is a pgx.QueryTracer timing every query by name, the repository method running
+// it, and logging the queries slower than a threshold with their arguments redacted. A nil
+// QueryTracer records nothing.
+type QueryTracer struct {
+	slow time.Duration
+
+	mutex sync.Mutex
+	stats map[string]*QueryStats
+}
+
+// NewQueryTracer creates a QueryTracer logging the queries slower than slow. A negative slow
+// never logs, and zero uses DefaultSlowQueryThreshold.
+func NewQueryTracer(slow time.Duration) *QueryTracer {
+	if slow == 0 {
+		slow = DefaultSlowQueryThreshold
+	}
+	return &QueryTracer{slow: slow, stats: make(map[string]*QueryStats)}
+}
+
+type queryTraceKey struct{}
+
+// queryTrace is the state of a query between its start and its end.
+type queryTrace struct {
+	name  string
+	sql   string
+	args  []any
+	start time.Time
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if t == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{
+		name:  queryName(),
+		sql:   data.SQL,
+		args:  data.Args,
+		start: time.Now(),
+	})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if t == nil || !ok {
+		return
+	}
+	duration := time.Since(trace.start)
+	slow := t.slow > 0 && duration > t.slow
+	t.Observe(trace.name, duration, data.Err, slow)
+
+	if slow {
+		logger.Warnf("Slow query %s took %s: %s args=%s", trace.name, duration, compactSQL(trace.sql), RedactArgs(trace.args))
+	}
+}
+
+// Observe records a query run.
+func (t *QueryTracer) Observe(name string, duration time.Duration, err error, slow bool) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats, exists := t.stats[name]
+	if !exists {
+		stats = &QueryStats{Buckets: make([]uint64, len(QueryDurationBuckets)+1)}
+		t.stats[name] = stats
+	}
+
+	stats.Count++
+	stats.TotalDuration += duration
+	if duration > stats.MaxDuration {
+		stats.MaxDuration = duration
+	}
+	if err != nil {
+		stats.Errors++
+	}
+	if slow {
+		stats.Slow++
+	}
+	bucket := len(QueryDurationBuckets)
+	for i, bound := range QueryDurationBuckets {
+		if duration <= bound {
+			bucket = i
+			break
+		}
+	}
+	stats.Buckets[bucket]++
+}
+
+// Snapshot returns a copy of the current counters keyed by query name.
+func (t *QueryTracer) Snapshot() map[string]QueryStats {
+	snapshot := make(map[string]QueryStats)
+	if t == nil {
+		return snapshot
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for name, stats := range t.stats {
+		copied := *stats
+		copied.Buckets = append([]uint64(nil), stats.Buckets...)
+		snapshot[name] = copied
+	}
+	return snapshot
+}
+
+// queryName names a query after the first caller outside of pgx and this package, e.g.
+// "repository.GetSwapTotalUsd" for a query of that repository method.
+func queryName() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		function := frame.Function
+		if !strings.HasPrefix(function, "github.com/jackc/pgx/") && !strings.HasPrefix(function, "hw/pkg/pg.") && function != "" {
+			return shortFunctionName(function)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// shortFunctionName reduces a qualified function name such as
+// "hw/internal/repository.(*repository).GetSwapTotalUsd.func1" to "repository.GetSwapTotalUsd".
+func shortFunctionName(function string) string {
+	function = function[strings.LastIndex(function, "/")+1:]
+	parts := strings.Split(function, ".")
+	name := make([]string, 0, len(parts))
+	for i, part := range parts {
+		if i > 0 && (strings.HasPrefix(part, "(") || strings.HasPrefix(part, "func")) {
+			continue
+		}
+		name = append(name, part)
+	}
+	return strings.Join(name, ".")
+}
+
+// compactSQL collapses the whitespace of a query onto one line.
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// RedactArgs formats the arguments of a query for logs. Numbers, booleans, times and NULLs are
+// kept; any other value, such as an address, an email or a signature, is replaced by its type.
+func RedactArgs(args []any) string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			redacted[i] = "NULL"
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			redacted[i] = fmt.Sprint(v)
+		case time.Time:
+			redacted[i] = v.Format(time.RFC3339Nano)
+		case time.Duration:
+			redacted[i] = v.String()
+		default:
+			redacted[i] = fmt.Sprintf("<%T>", v)
+		}
+	}
+	return "[" + strings.Join(redacted, ", ") + "]"
+}
+
+// QueryStatsPath is the path of the query metrics endpoint served by QueryStatsHandler.
+const QueryStatsPath = "/admin/db/queries"
+
+// QueryStatsHandler serves the query metrics of a tracer as JSON. Durations are in nanoseconds.
+func QueryStatsHandler(tracer *QueryTracer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(tracer.Snapshot()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQueryTracer_Observe tests that runs are counted in their histogram bucket with their errors and slow runs.
+func TestQueryTracer_Observe(t *testing.T) {
+	tracer := NewQueryTracer(0)
+	tracer.Observe("repository.GetSwapTotalUsd", 3*time.Millisecond, nil, false)
+	tracer.Observe("repository.GetSwapTotalUsd", 5*time.Millisecond, errors.New("timeout"), false)
+	tracer.Observe("repository.GetSwapTotalUsd", 8*time.Second, nil, true)
+
+	stats := tracer.Snapshot()["repository.GetSwapTotalUsd"]
+	assert.Equal(t, uint64(3), stats.Count)
+	assert.Equal(t, uint64(1), stats.Errors)
+	assert.Equal(t, uint64(1), stats.Slow)
+	assert.Equal(t, 8*time.Second, stats.MaxDuration)
+	assert.Equal(t, 8*time.Second+8*time.Millisecond, stats.TotalDuration)
+	if assert.Len(t, stats.Buckets, len(QueryDurationBuckets)+1) {
+		assert.Equal(t, uint64(2), stats.Buckets[1], "3ms and 5ms are in the 5ms bucket")
+		assert.Equal(t, uint64(1), stats.Buckets[len(QueryDurationBuckets)], "8s is above the last bound")
+	}
+
+	// The snapshot is a copy
+	stats.Buckets[1] = 0
+	assert.Equal(t, uint64(2), tracer.Snapshot()["repository.GetSwapTotalUsd"].Buckets[1])
+}
+
+// TestQueryTracer_Trace tests that a traced query is recorded, and that a nil tracer records nothing.
+func TestQueryTracer_Trace(t *testing.T) {
+	tracer := NewQueryTracer(-1)
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1", Args: []any{1}})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	snapshot := tracer.Snapshot()
+	assert.Len(t, snapshot, 1)
+	for _, stats := range snapshot {
+		assert.Equal(t, uint64(1), stats.Count)
+		assert.Zero(t, stats.Slow, "a negative threshold never logs")
+	}
+
+	var none *QueryTracer
+	none.TraceQueryEnd(none.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{}), nil, pgx.TraceQueryEndData{})
+	assert.Empty(t, none.Snapshot())
+}
+
+// TestShortFunctionName tests that query names drop the package path, receivers and closures.
+func TestShortFunctionName(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{function: "hw/internal/repository.(*repository).GetSwapTotalUsd", want: "repository.GetSwapTotalUsd"},
+		{function: "hw/internal/repository.(*repository).WithTransaction.func1", want: "repository.WithTransaction"},
+		{function: "main.main", want: "main.main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			assert.Equal(t, tt.want, shortFunctionName(tt.function))
+		})
+	}
+}
+
+// TestRedactArgs tests that only numbers, booleans, times and NULLs are logged as is.
+func TestRedactArgs(t *testing.T) {
+	at := time.Date(2024, 10, 22, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t,
+		`[42, true, 2024-10-22T00:00:00Z, NULL, <string>, <[]uint8>]`,
+		RedactArgs([]any{int64(42), true, at, nil, "alice@example.com", []byte("0xsignature")}),
+	)
+}
+
+// TestQueryStatsHandler tests that the query metrics are served as JSON.
+func TestQueryStatsHandler(t *testing.T) {
+	tracer := NewQueryTracer(0)
+	tracer.Observe("repository.GetLeaderboard", time.Millisecond, nil, false)
+
+	rr := httptest.NewRecorder()
+	QueryStatsHandler(tracer).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, QueryStatsPath, nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var stats map[string]QueryStats
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&stats))
+	assert.Equal(t, uint64(1), stats["repository.GetLeaderboard"].Count)
+}
@@ -0,0 +1,165 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+)
+
+const redacted = "[REDACTED]"
+
+// defaultRedactedHeaders are never logged in clear text.
+var defaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"Api-Key",
+}
+
+// sensitiveQueryParts marks query parameters whose value is redacted when a name contains one of them.
+var sensitiveQueryParts = []string{"key", "token", "secret", "signature"}
+
+// minRedactedPathSegment is the length from which a path segment is redacted: RPC providers such as
+// Alchemy and Infura put the API key in the path, e.g. /v2/<key>, while route segments are short.
+const minRedactedPathSegment = 16
+
+// DebugLogging logs the request and response bodies of every call made by this client at debug level.
+// Bodies are truncated to maxBodyBytes, and credentials in headers and in the URL are redacted.
+// Additional header names to redact can be passed in redactHeaders.
+func DebugLogging(maxBodyBytes int, redactHeaders ...string) Option {
+	redactSet := make(map[string]struct{}, len(defaultRedactedHeaders)+len(redactHeaders))
+	for _, name := range append(defaultRedactedHeaders, redactHeaders...) {
+		redactSet[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+
+	return func(client *resty.Client) {
+		client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+			logExchange(resp.Request, resp, nil, redactSet, maxBodyBytes)
+			return nil
+		})
+		client.OnError(func(req *resty.Request, err error) {
+			var resp *resty.Response
+			if respErr, ok := err.(*resty.ResponseError); ok {
+				resp = respErr.Response
+			}
+			logExchange(req, resp, err, redactSet, maxBodyBytes)
+		})
+	}
+}
+
+// logExchange writes a single debug entry for a request and its response or error.
+func logExchange(req *resty.Request, resp *resty.Response, err error, redactSet map[string]struct{}, maxBodyBytes int) {
+	if req == nil {
+		return
+	}
+
+	headers := req.Header
+	if req.RawRequest != nil {
+		// The raw request carries the final headers, including the auth token
+		headers = req.RawRequest.Header
+	}
+
+	fields := []interface{}{
+		"method", req.Method,
+		"url", redactURL(req.URL),
+		"request_headers", redactHeaderValues(headers, redactSet),
+		"request_body", truncateBody(requestBody(req.Body), maxBodyBytes),
+	}
+	if resp != nil {
+		fields = append(fields,
+			"status", resp.StatusCode(),
+			"response_headers", redactHeaderValues(resp.Header(), redactSet),
+			"response_body", truncateBody(resp.Body(), maxBodyBytes),
+			"duration", resp.Time(),
+		)
+	}
+	if err != nil {
+		fields = append(fields, "error", err.Error())
+	}
+
+	zap.S().Debugw("HTTP exchange", fields...)
+}
+
+// redactHeaderValues copies headers with sensitive values replaced.
+func redactHeaderValues(headers http.Header, redactSet map[string]struct{}) map[string]string {
+	result := make(map[string]string, len(headers))
+	for name, values := range headers {
+		if _, sensitive := redactSet[http.CanonicalHeaderKey(name)]; sensitive {
+			result[name] = redacted
+			continue
+		}
+		result[name] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// redactURL replaces the user info, the path segments of at least minRedactedPathSegment characters
+// and the values of credential-like query parameters. A URL that does not parse is logged as redacted.
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+
+	if parsed.User != nil {
+		parsed.User = url.User(redacted)
+	}
+	segments := strings.Split(parsed.Path, "/")
+	for i, segment := range segments {
+		if len(segment) >= minRedactedPathSegment {
+			segments[i] = redacted
+		}
+	}
+	parsed.Path = strings.Join(segments, "/")
+	parsed.RawPath = ""
+
+	if parsed.RawQuery == "" {
+		return parsed.String()
+	}
+	query := parsed.Query()
+	for name := range query {
+		lower := strings.ToLower(name)
+		for _, part := range sensitiveQueryParts {
+			if strings.Contains(lower, part) {
+				query.Set(name, redacted)
+				break
+			}
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// requestBody renders a resty request body for logging.
+func requestBody(body interface{}) []byte {
+	switch b := body.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return b
+	case string:
+		return []byte(b)
+	default:
+		data, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(b)
+		if err != nil {
+			return []byte(fmt.Sprintf("%v", b))
+		}
+		return data
+	}
+}
+
+// truncateBody caps a body at maxBytes; a non-positive maxBytes disables the cap.
+func truncateBody(body []byte, maxBytes int) string {
+	if maxBytes <= 0 || len(body) <= maxBytes {
+		return string(body)
+	}
+	return fmt.Sprintf("%s...(%d bytes truncated)", body[:maxBytes], len(body)-maxBytes)
+}
@@ -0,0 +1,62 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// HTTPError is returned by the JSON helpers when the server answers with a non-2xx status.
+type HTTPError struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, truncateBody(e.Body, 512))
+}
+
+// DoJSON sends a request with an optional JSON body and decodes a 2xx response into T.
+// Retry and backoff settings of the client apply. The body is sent with this request only,
+// so the same client can be reused for further calls.
+func DoJSON[T any](ctx context.Context, c *Client, method, url string, body interface{}) (T, error) {
+	var result T
+
+	res, err := c.execute(ctx, method, url, func(req *resty.Request) {
+		req.SetHeader("Accept", "application/json")
+		if body != nil {
+			req.SetHeader("Content-Type", "application/json")
+			req.SetBody(body)
+		}
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if res.StatusCode() < http.StatusOK || res.StatusCode() >= http.StatusMultipleChoices {
+		return result, &HTTPError{StatusCode: res.StatusCode(), Body: res.Body()}
+	}
+
+	if len(res.Body()) == 0 {
+		return result, nil
+	}
+	if err := jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(res.Body(), &result); err != nil {
+		return result, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetJSON sends a GET request and decodes the response into T.
+func GetJSON[T any](ctx context.Context, c *Client, url string) (T, error) {
+	return DoJSON[T](ctx, c, http.MethodGet, url, nil)
+}
+
+// PostJSON sends body as JSON in a POST request and decodes the response into T.
+func PostJSON[T any](ctx context.Context, c *Client, url string, body interface{}) (T, error) {
+	return DoJSON[T](ctx, c, http.MethodPost, url, body)
+}
@@ -0,0 +1,106 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TestGetJSON tests decoding a successful response into a typed struct.
+func TestGetJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"usdc","count":3}`))
+	}))
+	defer server.Close()
+
+	item, err := GetJSON[testItem](context.Background(), NewClient(BaseURL(server.URL)), "/item")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if item.Name != "usdc" || item.Count != 3 {
+		t.Errorf("Unexpected item %+v", item)
+	}
+}
+
+// TestGetJSON_HTTPError tests that a non-2xx status is returned as an HTTPError with the body.
+func TestGetJSON_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"not found"}`))
+	}))
+	defer server.Close()
+
+	_, err := GetJSON[testItem](context.Background(), NewClient(BaseURL(server.URL)), "/missing")
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Expected HTTPError, got %v", err)
+	}
+	if httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, httpErr.StatusCode)
+	}
+	if string(httpErr.Body) != `{"error":"not found"}` {
+		t.Errorf("Unexpected body %s", string(httpErr.Body))
+	}
+}
+
+// TestPostJSON tests that the body is sent per request so a client can be reused.
+func TestPostJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient(BaseURL(server.URL))
+
+	first, err := PostJSON[testItem](context.Background(), client, "/echo", testItem{Name: "first", Count: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := PostJSON[testItem](context.Background(), client, "/echo", testItem{Name: "second", Count: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first.Name != "first" || second.Name != "second" {
+		t.Errorf("Unexpected echoes %+v %+v", first, second)
+	}
+}
+
+// TestDoJSON_RetryOnServerError tests that 5xx responses are retried with the client's retry settings.
+func TestDoJSON_RetryOnServerError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"name":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		BaseURL(server.URL),
+		SetRetryCount(2),
+		Backoff("1ms", "5ms"),
+		RetryOnServerError(),
+	)
+
+	item, err := GetJSON[testItem](context.Background(), client, "/flaky")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if item.Name != "ok" || calls != 3 {
+		t.Errorf("Expected success after 3 calls, got %+v after %d", item, calls)
+	}
+}
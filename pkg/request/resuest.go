@@ -3,6 +3,8 @@ package request
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -105,6 +107,26 @@ func SetRetryCount(count int) Option {
 	}
 }
 
+// Backoff sets the minimum and maximum wait between retries.
+func Backoff(wait, maxWait string) Option {
+	return func(client *resty.Client) {
+		client.SetRetryWaitTime(MustParseDuration(wait))
+		client.SetRetryMaxWaitTime(MustParseDuration(maxWait))
+	}
+}
+
+// RetryOnServerError also retries responses with status 429 or 5xx, not only transport errors.
+func RetryOnServerError() Option {
+	return func(client *resty.Client) {
+		client.AddRetryCondition(func(res *resty.Response, err error) bool {
+			if res == nil {
+				return false
+			}
+			return res.StatusCode() == http.StatusTooManyRequests || res.StatusCode() >= http.StatusInternalServerError
+		})
+	}
+}
+
 // SetPathParams sets the path parameters for the resty client.
 func (c *Client) SetPathParams(params map[string]string) Option {
 	return func(client *resty.Client) {
@@ -128,6 +150,22 @@ func (c *Client) SetFormData(formData map[string]string) *Client {
 	return c
 }
 
+// SetHeaders sets headers for this request only, overriding client-level headers with the same name.
+func (c *Client) SetHeaders(headers map[string]string) *Client {
+	c.requestOptions = append(c.requestOptions, func(req *resty.Request) {
+		req.SetHeaders(headers)
+	})
+	return c
+}
+
+// SetQueryParams sets query parameters for this request only, overriding client-level parameters with the same name.
+func (c *Client) SetQueryParams(params map[string]string) *Client {
+	c.requestOptions = append(c.requestOptions, func(req *resty.Request) {
+		req.SetQueryParams(params)
+	})
+	return c
+}
+
 // SetResult sets the result object to store the response.
 func (c *Client) SetResult(result interface{}) *Client {
 	c.requestOptions = append(c.requestOptions, func(req *resty.Request) {
@@ -173,6 +211,17 @@ func NewClient(options ...Option) *Client {
 	return c
 }
 
+// supportedMethods lists the HTTP methods accepted by Do.
+var supportedMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+}
+
 // Response represents the HTTP response with status code and data.
 type Response struct {
 	StatusCode int
@@ -180,34 +229,18 @@ type Response struct {
 }
 
 // Do sends an HTTP request with the specified method and URL.
+// The request is bound to the context set with WithContext, if any.
 func (c *Client) Do(method string, url string) (*Response, error) {
-	var (
-		res *resty.Response
-		err error
-	)
-	req := c.client.R()
-
-	// Inject tracing headers if context is set
-	if c.ctx != nil {
-		propagator := otel.GetTextMapPropagator()
-		propagator.Inject(c.ctx, propagation.HeaderCarrier(req.Header))
-	}
-
-	req.SetContentLength(true)
-	for _, opt := range c.requestOptions {
-		opt(req)
-	}
-
-	// Execute the request based on the method
-	switch method {
-	case "GET":
-		res, err = req.Get(url)
-	case "POST":
-		res, err = req.Post(url)
-	default:
-		return nil, fmt.Errorf("unsupported method: %s", method)
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
+	return c.DoWithContext(ctx, method, url)
+}
 
+// DoWithContext sends an HTTP request that is cancelled when ctx is done.
+func (c *Client) DoWithContext(ctx context.Context, method string, url string) (*Response, error) {
+	res, err := c.execute(ctx, method, url)
 	if err != nil {
 		return nil, err
 	}
@@ -217,3 +250,27 @@ func (c *Client) Do(method string, url string) (*Response, error) {
 		Data:       res.Body(),
 	}, nil
 }
+
+// execute builds the request from the client and request options plus extra, and sends it.
+func (c *Client) execute(ctx context.Context, method string, url string, extra ...func(*resty.Request)) (*resty.Response, error) {
+	method = strings.ToUpper(method)
+	if !supportedMethods[method] {
+		return nil, fmt.Errorf("unsupported method: %s", method)
+	}
+
+	req := c.client.R().SetContext(ctx)
+
+	// Inject tracing headers
+	propagator := otel.GetTextMapPropagator()
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	req.SetContentLength(true)
+	for _, opt := range c.requestOptions {
+		opt(req)
+	}
+	for _, opt := range extra {
+		opt(req)
+	}
+
+	return req.Execute(method, url)
+}
@@ -1,6 +1,8 @@
 package request
 
 import (
+	"context"
+	"errors"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -85,11 +87,11 @@ func TestClient_Do(t *testing.T) {
 
 	// Test case: Unsupported HTTP method
 	t.Run("Unsupported HTTP Method", func(t *testing.T) {
-		_, err := client.Do("PUT", "/success")
+		_, err := client.Do("TRACE", "/success")
 		if err == nil {
 			t.Fatalf("Expected error for unsupported method, got nil")
 		}
-		expectedErr := "unsupported method: PUT"
+		expectedErr := "unsupported method: TRACE"
 		if err.Error() != expectedErr {
 			t.Errorf("Expected error '%s', got '%s'", expectedErr, err.Error())
 		}
@@ -328,11 +330,11 @@ func TestClient_Do_WithUnsupportedMethod(t *testing.T) {
 	)
 
 	// Execute request with unsupported method
-	_, err = client.Do("DELETE", "/")
+	_, err = client.Do("CONNECT", "/")
 	if err == nil {
 		t.Fatalf("Expected error for unsupported method, got nil")
 	}
-	expectedErr := "unsupported method: DELETE"
+	expectedErr := "unsupported method: CONNECT"
 	if err.Error() != expectedErr {
 		t.Errorf("Expected error '%s', got '%s'", expectedErr, err.Error())
 	}
@@ -404,3 +406,70 @@ func TestNewClient_DefaultSettings(t *testing.T) {
 		t.Errorf("Expected default RetryMaxWaitTime to be 1m, got %v", client.client.RetryMaxWaitTime)
 	}
 }
+
+// TestClient_Do_AllMethods tests that every supported HTTP method reaches the server.
+func TestClient_Do_AllMethods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"} {
+		t.Run(method, func(t *testing.T) {
+			resp, err := NewClient(BaseURL(server.URL), SetRetryCount(0)).Do(method, "/")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+			}
+		})
+	}
+}
+
+// TestClient_DoWithContext_Cancel tests that a cancelled context aborts the request.
+func TestClient_DoWithContext_Cancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewClient(BaseURL(server.URL), SetRetryCount(0)).DoWithContext(ctx, "GET", "/")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context deadline error, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected the request to be cancelled early, took %s", time.Since(start))
+	}
+}
+
+// TestClient_Do_RequestOverrides tests that per-request headers and query parameters override client-level ones.
+func TestClient_Do_RequestOverrides(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("X-Env") + "|" + r.URL.Query().Get("page")))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		BaseURL(server.URL),
+		Header(map[string]string{"X-Env": "client"}),
+		Query(map[string]string{"page": "1"}),
+	)
+
+	resp, err := client.
+		SetHeaders(map[string]string{"X-Env": "request"}).
+		SetQueryParams(map[string]string{"page": "2"}).
+		Do("GET", "/")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(resp.Data) != "request|2" {
+		t.Errorf("Expected request-level overrides, got %s", string(resp.Data))
+	}
+}
@@ -0,0 +1,213 @@
+// Package siwe parses and formats Sign-In With Ethereum (EIP-4361) messages.
+package siwe
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const headerSuffix = " wants you to sign in with your Ethereum account:"
+
+// Message is an EIP-4361 message. Optional fields are empty or zero when absent.
+type Message struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+	NotBefore      time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// ErrMalformed is wrapped by every error of Parse.
+var ErrMalformed = errors.New("malformed SIWE message")
+
+// Parse parses an EIP-4361 message. The address must be EIP-55 checksummed, as the standard requires.
+func Parse(message string) (*Message, error) {
+	p := &parser{lines: strings.Split(message, "\n")}
+	m := &Message{}
+
+	header := p.next()
+	if !strings.HasSuffix(header, headerSuffix) || header == headerSuffix {
+		return nil, p.errorf("first line must be \"<domain>%s\"", headerSuffix)
+	}
+	m.Domain = strings.TrimSuffix(header, headerSuffix)
+
+	address := p.next()
+	if !common.IsHexAddress(address) || common.HexToAddress(address).Hex() != address {
+		return nil, p.errorf("address must be EIP-55 checksummed, got %q", address)
+	}
+	m.Address = common.HexToAddress(address)
+
+	if p.next() != "" {
+		return nil, p.errorf("expected an empty line after the address")
+	}
+	// The statement is optional; older messages also omit the empty line that follows it
+	switch line := p.peek(); {
+	case line == "":
+		p.next()
+	case !strings.HasPrefix(line, "URI: "):
+		m.Statement = p.next()
+		if p.next() != "" {
+			return nil, p.errorf("expected an empty line after the statement")
+		}
+	}
+
+	var err error
+	if m.URI, err = p.field("URI", true); err != nil {
+		return nil, err
+	}
+	if _, err := url.Parse(m.URI); err != nil || m.URI == "" {
+		return nil, p.errorf("invalid URI %q", m.URI)
+	}
+	if m.Version, err = p.field("Version", true); err != nil {
+		return nil, err
+	}
+	if m.Version != "1" {
+		return nil, p.errorf("version must be 1, got %q", m.Version)
+	}
+	chainID, err := p.field("Chain ID", true)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID, err = strconv.ParseInt(chainID, 10, 64); err != nil || m.ChainID <= 0 {
+		return nil, p.errorf("invalid chain ID %q", chainID)
+	}
+	if m.Nonce, err = p.field("Nonce", true); err != nil {
+		return nil, err
+	}
+	if len(m.Nonce) < 8 || strings.IndexFunc(m.Nonce, func(r rune) bool { return !isAlphanumeric(r) }) >= 0 {
+		return nil, p.errorf("nonce must be at least 8 alphanumeric characters")
+	}
+	if m.IssuedAt, err = p.timeField("Issued At", true); err != nil {
+		return nil, err
+	}
+	if m.ExpirationTime, err = p.timeField("Expiration Time", false); err != nil {
+		return nil, err
+	}
+	if m.NotBefore, err = p.timeField("Not Before", false); err != nil {
+		return nil, err
+	}
+	if m.RequestID, err = p.field("Request ID", false); err != nil {
+		return nil, err
+	}
+	if p.peek() == "Resources:" {
+		p.next()
+		for strings.HasPrefix(p.peek(), "- ") {
+			m.Resources = append(m.Resources, strings.TrimPrefix(p.next(), "- "))
+		}
+	}
+
+	if !p.done() {
+		return nil, p.errorf("unexpected line %q", p.peek())
+	}
+	return m, nil
+}
+
+// String formats the message as signed by a wallet.
+func (m *Message) String() string {
+	var sb strings.Builder
+	sb.WriteString(m.Domain + headerSuffix + "\n")
+	sb.WriteString(m.Address.Hex() + "\n\n")
+	if m.Statement != "" {
+		sb.WriteString(m.Statement + "\n")
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "URI: %s\nVersion: %s\nChain ID: %d\nNonce: %s\nIssued At: %s", m.URI, m.Version, m.ChainID, m.Nonce, m.IssuedAt.Format(time.RFC3339))
+	if !m.ExpirationTime.IsZero() {
+		sb.WriteString("\nExpiration Time: " + m.ExpirationTime.Format(time.RFC3339))
+	}
+	if !m.NotBefore.IsZero() {
+		sb.WriteString("\nNot Before: " + m.NotBefore.Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		sb.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		sb.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			sb.WriteString("\n- " + resource)
+		}
+	}
+	return sb.String()
+}
+
+// ValidAt checks that the message has not expired and is already valid at now.
+func (m *Message) ValidAt(now time.Time) error {
+	if !m.ExpirationTime.IsZero() && !now.Before(m.ExpirationTime) {
+		return errors.New("message has expired")
+	}
+	if !m.NotBefore.IsZero() && now.Before(m.NotBefore) {
+		return errors.New("message is not valid yet")
+	}
+	return nil
+}
+
+// parser reads the lines of a message in order.
+type parser struct {
+	lines []string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.lines)
+}
+
+func (p *parser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.lines[p.pos]
+}
+
+func (p *parser) next() string {
+	line := p.peek()
+	p.pos++
+	return line
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrMalformed, p.pos, fmt.Sprintf(format, args...))
+}
+
+// field reads the "<name>: <value>" line, which may be skipped when it is not required.
+func (p *parser) field(name string, required bool) (string, error) {
+	value, found := strings.CutPrefix(p.peek(), name+": ")
+	if !found {
+		if required {
+			p.pos++
+			return "", p.errorf("expected %q", name+": ")
+		}
+		return "", nil
+	}
+	p.pos++
+	return value, nil
+}
+
+// timeField reads a field holding an RFC 3339 time.
+func (p *parser) timeField(name string, required bool) (time.Time, error) {
+	value, err := p.field(name, required)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, p.errorf("%s must be an RFC 3339 time, got %q", name, value)
+	}
+	return t, nil
+}
+
+func isAlphanumeric(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
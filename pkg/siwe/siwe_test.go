@@ -0,0 +1,106 @@
+package siwe
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const example = `example.com wants you to sign in with your Ethereum account:
+0x71562b71999873DB5b286dF957af199Ec94617F7
+
+Sign in to eth-indexer.
+
+URI: https://example.com/login
+Version: 1
+Chain ID: 1
+Nonce: 32891756aBcd
+Issued At: 2024-10-14T00:00:00Z
+Expiration Time: 2024-10-14T00:10:00Z
+Request ID: 42
+Resources:
+- https://example.com/terms
+- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq`
+
+// TestParse tests that every field is read and that String formats the message back unchanged.
+func TestParse(t *testing.T) {
+	m, err := Parse(example)
+	assert.NoError(t, err)
+	assert.Equal(t, &Message{
+		Domain:         "example.com",
+		Address:        common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7"),
+		Statement:      "Sign in to eth-indexer.",
+		URI:            "https://example.com/login",
+		Version:        "1",
+		ChainID:        1,
+		Nonce:          "32891756aBcd",
+		IssuedAt:       time.Date(2024, 10, 14, 0, 0, 0, 0, time.UTC),
+		ExpirationTime: time.Date(2024, 10, 14, 0, 10, 0, 0, time.UTC),
+		RequestID:      "42",
+		Resources:      []string{"https://example.com/terms", "ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq"},
+	}, m)
+	assert.Equal(t, example, m.String())
+}
+
+// TestParse_WithoutStatement tests both layouts of a message without a statement.
+func TestParse_WithoutStatement(t *testing.T) {
+	const fields = "URI: https://example.com\nVersion: 1\nChain ID: 1\nNonce: 32891756aBcd\nIssued At: 2024-10-14T00:00:00Z"
+	const header = "example.com wants you to sign in with your Ethereum account:\n0x71562b71999873DB5b286dF957af199Ec94617F7\n"
+
+	for _, message := range []string{header + "\n\n" + fields, header + "\n" + fields} {
+		m, err := Parse(message)
+		if assert.NoError(t, err) {
+			assert.Empty(t, m.Statement)
+			assert.Equal(t, header+"\n\n"+fields, m.String())
+		}
+	}
+}
+
+// TestParse_Invalid tests that malformed messages are rejected.
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"lowercase address": `example.com wants you to sign in with your Ethereum account:
+0x71562b71999873db5b286df957af199ec94617f7
+
+URI: https://example.com
+Version: 1
+Chain ID: 1
+Nonce: 32891756aBcd
+Issued At: 2024-10-14T00:00:00Z`,
+		"short nonce": `example.com wants you to sign in with your Ethereum account:
+0x71562b71999873DB5b286dF957af199Ec94617F7
+
+URI: https://example.com
+Version: 1
+Chain ID: 1
+Nonce: abc
+Issued At: 2024-10-14T00:00:00Z`,
+		"missing issued at": `example.com wants you to sign in with your Ethereum account:
+0x71562b71999873DB5b286dF957af199Ec94617F7
+
+URI: https://example.com
+Version: 1
+Chain ID: 1
+Nonce: 32891756aBcd`,
+		"trailing line": example + "\nextra",
+		"not siwe":      "Claim points for 0xabc at 1728000000",
+	}
+
+	for name, message := range tests {
+		_, err := Parse(message)
+		assert.True(t, errors.Is(err, ErrMalformed), name)
+	}
+}
+
+// TestValidAt tests the expiration time and not before bounds.
+func TestValidAt(t *testing.T) {
+	issued := time.Date(2024, 10, 14, 0, 0, 0, 0, time.UTC)
+	m := &Message{IssuedAt: issued, NotBefore: issued.Add(time.Minute), ExpirationTime: issued.Add(time.Hour)}
+
+	assert.Error(t, m.ValidAt(issued))
+	assert.NoError(t, m.ValidAt(issued.Add(time.Minute)))
+	assert.Error(t, m.ValidAt(issued.Add(time.Hour)))
+}
@@ -0,0 +1,182 @@
+package transactor
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// awsKeySpec is the key spec of the secp256k1 keys of AWS KMS.
+const awsKeySpec = "ECC_SECG_P256K1"
+
+// AWSKMSConfig defines the key and credentials of an AWS KMS key.
+type AWSKMSConfig struct {
+	Endpoint        string // defaults to https://kms.<region>.amazonaws.com
+	Region          string
+	KeyID           string // key ID, ARN or alias
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // of temporary credentials
+}
+
+// AWSKMS is a KMS talking to the AWS KMS JSON API with Signature Version 4.
+type AWSKMS struct {
+	config AWSKMSConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewAWSKMS creates an AWSKMS. The key must be an asymmetric ECC_SECG_P256K1 key for SIGN_VERIFY.
+func NewAWSKMS(config AWSKMSConfig) *AWSKMS {
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", config.Region)
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	return &AWSKMS{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}
+}
+
+// PublicKey returns the public key of the key, which must be a secp256k1 key.
+func (k *AWSKMS) PublicKey(ctx context.Context) ([]byte, error) {
+	var out struct {
+		KeySpec   string `json:"KeySpec"`
+		PublicKey []byte `json:"PublicKey"` // base64 in JSON
+	}
+	if err := k.do(ctx, "GetPublicKey", map[string]string{"KeyId": k.config.KeyID}, &out); err != nil {
+		return nil, err
+	}
+	if out.KeySpec != awsKeySpec {
+		return nil, fmt.Errorf("AWS KMS key %s has spec %s, not %s", k.config.KeyID, out.KeySpec, awsKeySpec)
+	}
+	return out.PublicKey, nil
+}
+
+// Sign signs a digest with ECDSA_SHA_256.
+func (k *AWSKMS) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	var out struct {
+		Signature []byte `json:"Signature"`
+	}
+	in := map[string]string{
+		"KeyId":            k.config.KeyID,
+		"Message":          base64.StdEncoding.EncodeToString(digest),
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}
+	if err := k.do(ctx, "Sign", in, &out); err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+// do calls an action of the KMS API and decodes its response into out.
+func (k *AWSKMS) do(ctx context.Context, action string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.config.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	if k.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", k.config.SessionToken)
+	}
+	k.sign(req, body)
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call AWS KMS %s: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to call AWS KMS %s: %w", action, responseError(resp))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode AWS KMS %s response: %w", action, err)
+	}
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers to a request to the root path of the endpoint.
+func (k *AWSKMS) sign(req *http.Request, body []byte) {
+	now := k.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	// Host, Content-Type and every x-amz-* header are signed
+	signed := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" {
+			signed[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := date + "/" + k.config.Region + "/kms/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+k.config.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, k.config.Region)
+	signingKey = hmacSHA256(signingKey, "kms")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		k.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
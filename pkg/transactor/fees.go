@@ -0,0 +1,202 @@
+package transactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/shopspring/decimal"
+)
+
+// Supported fee strategies, by how fast their transactions are mined.
+const (
+	FeeSlow     = "slow"
+	FeeStandard = "standard"
+	FeeFast     = "fast"
+)
+
+// feePercentiles are the percentiles of the tips paid in recent blocks bid by each strategy.
+var feePercentiles = map[string]float64{
+	FeeSlow:     10,
+	FeeStandard: 50,
+	FeeFast:     90,
+}
+
+const (
+	// feeHistoryBlocks is the number of recent blocks the tips are read from.
+	feeHistoryBlocks = 20
+	// baseFeeHeadroom multiplies the next base fee in the fee cap, so a transaction stays
+	// includable while the base fee rises for a few full blocks.
+	baseFeeHeadroom = 2
+	// replacementBump is the percentage by which nodes require the fees of a replacement to exceed
+	// those of the transaction it replaces.
+	replacementBump = 10
+)
+
+// ErrFeeCapExceeded is returned when the fees a transaction needs exceed the configured maximum.
+var ErrFeeCapExceeded = errors.New("fees exceed the configured maximum")
+
+// FeeBackend is the part of an Ethereum client used to estimate fees, implemented by ethclient.Client.
+type FeeBackend interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// Fees are the fees of a transaction: GasPrice on a chain without a base fee, GasTipCap and
+// GasFeeCap (EIP-1559) otherwise.
+type Fees struct {
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// FeeOracle estimates the fees of transactions from the tips paid in recent blocks
+// (eth_feeHistory), bidding the percentile of its strategy, within optional caps.
+type FeeOracle struct {
+	backend    FeeBackend
+	percentile float64
+	maxFee     *big.Int // of GasFeeCap or GasPrice, nil for no cap
+	maxTip     *big.Int // of GasTipCap, nil for no cap
+}
+
+// NewFeeOracle creates a FeeOracle with a strategy, FeeSlow, FeeStandard or FeeFast. maxFee caps
+// the fee cap or gas price and maxTip the tip, in wei; nil or zero means no cap.
+func NewFeeOracle(backend FeeBackend, strategy string, maxFee, maxTip *big.Int) (*FeeOracle, error) {
+	percentile, ok := feePercentiles[strategy]
+	if !ok {
+		return nil, fmt.Errorf("unknown fee strategy: %s", strategy)
+	}
+	if maxFee != nil && maxFee.Sign() == 0 {
+		maxFee = nil
+	}
+	if maxTip != nil && maxTip.Sign() == 0 {
+		maxTip = nil
+	}
+	return &FeeOracle{backend: backend, percentile: percentile, maxFee: maxFee, maxTip: maxTip}, nil
+}
+
+// Fees estimates the fees of a transaction sent now. It returns ErrFeeCapExceeded when the next
+// base fee, or the gas price, is above the maximum fee, as a transaction capped below it would not
+// be mined.
+func (o *FeeOracle) Fees(ctx context.Context) (*Fees, error) {
+	history, err := o.backend.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{o.percentile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+
+	// BaseFee has the base fee of the block after the history last
+	var baseFee *big.Int
+	if n := len(history.BaseFee); n > 0 {
+		baseFee = history.BaseFee[n-1]
+	}
+	if baseFee == nil || baseFee.Sign() == 0 {
+		gasPrice, err := o.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+		if o.maxFee != nil && gasPrice.Cmp(o.maxFee) > 0 {
+			return nil, fmt.Errorf("%w: gas price %s", ErrFeeCapExceeded, formatGwei(gasPrice))
+		}
+		return &Fees{GasPrice: gasPrice}, nil
+	}
+
+	tip := medianReward(history.Reward)
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(baseFeeHeadroom)), tip)
+	return o.capFees(baseFee, tip, feeCap)
+}
+
+// capFees lowers a tip and fee cap to the maximums, as long as the fee cap covers the base fee.
+func (o *FeeOracle) capFees(baseFee, tip, feeCap *big.Int) (*Fees, error) {
+	if o.maxTip != nil && tip.Cmp(o.maxTip) > 0 {
+		tip = o.maxTip
+	}
+	if o.maxFee != nil {
+		if baseFee.Cmp(o.maxFee) > 0 {
+			return nil, fmt.Errorf("%w: base fee %s", ErrFeeCapExceeded, formatGwei(baseFee))
+		}
+		if feeCap.Cmp(o.maxFee) > 0 {
+			feeCap = o.maxFee
+		}
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip = feeCap
+	}
+	return &Fees{GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
+
+// Bump raises fees to replace a transaction paying previous: every fee is at least replacementBump
+// percent above the previous one. It returns ErrFeeCapExceeded when that is above a maximum.
+func (o *FeeOracle) Bump(fees, previous *Fees) (*Fees, error) {
+	if fees.GasPrice != nil || previous.GasPrice != nil {
+		gasPrice := maxBig(fees.gasPrice(), bumped(previous.gasPrice()))
+		if o.maxFee != nil && gasPrice.Cmp(o.maxFee) > 0 {
+			return nil, fmt.Errorf("%w: replacement gas price %s", ErrFeeCapExceeded, formatGwei(gasPrice))
+		}
+		return &Fees{GasPrice: gasPrice}, nil
+	}
+
+	tip := maxBig(fees.GasTipCap, bumped(previous.GasTipCap))
+	feeCap := maxBig(fees.GasFeeCap, bumped(previous.GasFeeCap))
+	if o.maxTip != nil && tip.Cmp(o.maxTip) > 0 {
+		return nil, fmt.Errorf("%w: replacement tip %s", ErrFeeCapExceeded, formatGwei(tip))
+	}
+	if o.maxFee != nil && feeCap.Cmp(o.maxFee) > 0 {
+		return nil, fmt.Errorf("%w: replacement fee cap %s", ErrFeeCapExceeded, formatGwei(feeCap))
+	}
+	return &Fees{GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
+
+// gasPrice returns the most a transaction with fees pays per gas.
+func (f *Fees) gasPrice() *big.Int {
+	if f.GasPrice != nil {
+		return f.GasPrice
+	}
+	return f.GasFeeCap
+}
+
+// medianReward returns the median of the rewards of the first percentile of each block, or zero
+// without any. Empty blocks report a zero reward and pull the median down only when most are empty.
+func medianReward(rewards [][]*big.Int) *big.Int {
+	var values []*big.Int
+	for _, reward := range rewards {
+		if len(reward) > 0 && reward[0] != nil {
+			values = append(values, reward[0])
+		}
+	}
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+	return new(big.Int).Set(values[len(values)/2])
+}
+
+// bumped returns value raised by replacementBump percent, rounded up.
+func bumped(value *big.Int) *big.Int {
+	out := new(big.Int).Mul(value, big.NewInt(100+replacementBump))
+	return out.Add(out, big.NewInt(99)).Div(out, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// GweiToWei converts an amount of gwei to wei, or nil for zero.
+func GweiToWei(gwei float64) *big.Int {
+	if gwei <= 0 {
+		return nil
+	}
+	// decimal keeps the shortest decimal form of gwei, so e.g. 0.3 is not 299999999 wei
+	return decimal.NewFromFloat(gwei).Shift(9).BigInt()
+}
+
+// formatGwei formats an amount of wei in gwei.
+func formatGwei(wei *big.Int) string {
+	return new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.GWei)).Text('f', -1) + " gwei"
+}
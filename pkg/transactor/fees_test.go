@@ -0,0 +1,78 @@
+package transactor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeeOracle tests that each strategy bids its percentile of the recent tips, and that the
+// caps lower the fees as long as the base fee is covered.
+func TestFeeOracle(t *testing.T) {
+	backend := &fakeBackend{baseFee: big.NewInt(100), rewards: []int64{0, 5, 8, 3, 9}}
+	ctx := context.Background()
+
+	oracle, err := NewFeeOracle(backend, FeeFast, nil, nil)
+	assert.NoError(t, err)
+	fees, err := oracle.Fees(ctx)
+	assert.NoError(t, err)
+	// The median of the rewards at the percentile, over blocks
+	assert.Equal(t, &Fees{GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(205)}, fees)
+
+	oracle, err = NewFeeOracle(backend, FeeStandard, big.NewInt(150), big.NewInt(4))
+	assert.NoError(t, err)
+	fees, err = oracle.Fees(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &Fees{GasTipCap: big.NewInt(4), GasFeeCap: big.NewInt(150)}, fees)
+
+	backend.baseFee = big.NewInt(151)
+	_, err = oracle.Fees(ctx)
+	assert.ErrorIs(t, err, ErrFeeCapExceeded)
+
+	_, err = NewFeeOracle(backend, "instant", nil, nil)
+	assert.Error(t, err)
+}
+
+// TestFeeOracle_Legacy tests that a chain without a base fee gets the suggested gas price, within
+// the cap.
+func TestFeeOracle_Legacy(t *testing.T) {
+	oracle, err := NewFeeOracle(&fakeBackend{}, FeeSlow, big.NewInt(30), nil)
+	assert.NoError(t, err)
+	fees, err := oracle.Fees(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Fees{GasPrice: big.NewInt(30)}, fees)
+
+	oracle, err = NewFeeOracle(&fakeBackend{}, FeeSlow, big.NewInt(29), nil)
+	assert.NoError(t, err)
+	_, err = oracle.Fees(context.Background())
+	assert.ErrorIs(t, err, ErrFeeCapExceeded)
+}
+
+// TestBump tests that replacement fees are at least 10% above the previous ones, rounded up, and
+// within the caps.
+func TestBump(t *testing.T) {
+	oracle, err := NewFeeOracle(&fakeBackend{}, FeeStandard, big.NewInt(250), nil)
+	assert.NoError(t, err)
+
+	fees, err := oracle.Bump(&Fees{GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(300)}, &Fees{GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(200)})
+	assert.ErrorIs(t, err, ErrFeeCapExceeded)
+	assert.Nil(t, fees)
+
+	fees, err = oracle.Bump(&Fees{GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(150)}, &Fees{GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(201)})
+	assert.NoError(t, err)
+	assert.Equal(t, &Fees{GasTipCap: big.NewInt(6), GasFeeCap: big.NewInt(222)}, fees)
+
+	fees, err = oracle.Bump(&Fees{GasPrice: big.NewInt(100)}, &Fees{GasPrice: big.NewInt(100)})
+	assert.NoError(t, err)
+	assert.Equal(t, &Fees{GasPrice: big.NewInt(110)}, fees)
+}
+
+// TestGweiToWei tests the conversion of fractional gwei.
+func TestGweiToWei(t *testing.T) {
+	assert.Nil(t, GweiToWei(0))
+	assert.Equal(t, big.NewInt(1_500_000_000), GweiToWei(1.5))
+	assert.Equal(t, big.NewInt(300_000_000), GweiToWei(0.3))
+	assert.Equal(t, big.NewInt(1_000_000), GweiToWei(0.001))
+}
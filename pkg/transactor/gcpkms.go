@@ -0,0 +1,187 @@
+package transactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// gcpKMSEndpoint is the Cloud KMS REST API.
+	gcpKMSEndpoint = "https://cloudkms.googleapis.com"
+	// gcpMetadataTokenURL returns the access token of the service account of a Google Cloud instance.
+	gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	// gcpAlgorithm is the algorithm of the secp256k1 keys of Cloud KMS.
+	gcpAlgorithm = "EC_SIGN_SECP256K1_SHA256"
+)
+
+// crc32c is the checksum Cloud KMS verifies requests and responses with.
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// GCPKMSConfig defines a Cloud KMS key version and how to authenticate to it.
+type GCPKMSConfig struct {
+	Endpoint    string // defaults to https://cloudkms.googleapis.com
+	KeyName     string // projects/*/locations/*/keyRings/*/cryptoKeys/*/cryptoKeyVersions/*
+	AccessToken string // OAuth access token; the metadata server's token of the instance when empty
+}
+
+// GCPKMS is a KMS talking to the Cloud KMS REST API. Signatures are checked end to end with CRC32C
+// checksums, as Cloud KMS recommends.
+type GCPKMS struct {
+	config   GCPKMSConfig
+	client   *http.Client
+	tokenURL string
+	now      func() time.Time
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCPKMS creates a GCPKMS. The key must be an EC_SIGN_SECP256K1_SHA256 key, usually in an HSM.
+func NewGCPKMS(config GCPKMSConfig) *GCPKMS {
+	if config.Endpoint == "" {
+		config.Endpoint = gcpKMSEndpoint
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	return &GCPKMS{
+		config:   config,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		tokenURL: gcpMetadataTokenURL,
+		now:      time.Now,
+	}
+}
+
+// PublicKey returns the public key of the key version, which must be a secp256k1 key.
+func (k *GCPKMS) PublicKey(ctx context.Context) ([]byte, error) {
+	var out struct {
+		PEM       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := k.do(ctx, http.MethodGet, k.config.KeyName+"/publicKey", nil, &out); err != nil {
+		return nil, err
+	}
+	if out.Algorithm != gcpAlgorithm {
+		return nil, fmt.Errorf("Cloud KMS key %s has algorithm %s, not %s", k.config.KeyName, out.Algorithm, gcpAlgorithm)
+	}
+	block, _ := pem.Decode([]byte(out.PEM))
+	if block == nil {
+		return nil, errors.New("invalid PEM public key")
+	}
+	return block.Bytes, nil
+}
+
+// Sign signs a SHA-256 digest.
+func (k *GCPKMS) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	in := map[string]any{
+		"digest":       map[string]string{"sha256": base64.StdEncoding.EncodeToString(digest)},
+		"digestCrc32c": strconv.FormatUint(uint64(crc32.Checksum(digest, crc32c)), 10),
+	}
+	var out struct {
+		Signature            []byte `json:"signature"` // base64 in JSON
+		SignatureCrc32c      string `json:"signatureCrc32c"`
+		VerifiedDigestCrc32c bool   `json:"verifiedDigestCrc32c"`
+	}
+	if err := k.do(ctx, http.MethodPost, k.config.KeyName+":asymmetricSign", in, &out); err != nil {
+		return nil, err
+	}
+
+	// A corrupted request or response is reported rather than retried, as the signer can simply send again
+	if !out.VerifiedDigestCrc32c {
+		return nil, errors.New("Cloud KMS did not verify the digest checksum")
+	}
+	if out.SignatureCrc32c != strconv.FormatUint(uint64(crc32.Checksum(out.Signature, crc32c)), 10) {
+		return nil, errors.New("Cloud KMS signature checksum mismatch")
+	}
+	return out.Signature, nil
+}
+
+// do calls a method of a resource of the Cloud KMS API and decodes its response into out.
+func (k *GCPKMS) do(ctx context.Context, method, resource string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	token, err := k.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, k.config.Endpoint+"/v1/"+resource, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Cloud KMS %s: %w", resource, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to call Cloud KMS %s: %w", resource, responseError(resp))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Cloud KMS %s response: %w", resource, err)
+	}
+	return nil
+}
+
+// accessToken returns the configured access token, or the token of the metadata server, read again
+// a minute before it expires.
+func (k *GCPKMS) accessToken(ctx context.Context) (string, error) {
+	if k.config.AccessToken != "" {
+		return k.config.AccessToken, nil
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.token != "" && k.now().Before(k.tokenExpiry) {
+		return k.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token from the metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get access token from the metadata server: %w", responseError(resp))
+	}
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode access token: %w", err)
+	}
+
+	k.token = out.AccessToken
+	k.tokenExpiry = k.now().Add(time.Duration(out.ExpiresIn)*time.Second - time.Minute)
+	return k.token, nil
+}
@@ -0,0 +1,127 @@
+package transactor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KMS is a key management service holding a secp256k1 key that never leaves it.
+type KMS interface {
+	// PublicKey returns the DER-encoded SubjectPublicKeyInfo of the key.
+	PublicKey(ctx context.Context) ([]byte, error)
+	// Sign returns the DER-encoded ECDSA signature of a 32-byte digest.
+	Sign(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// secp256k1HalfN is half the order of secp256k1, the largest s of a canonical signature (EIP-2).
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// KMSSigner signs with a key held in a KMS.
+type KMSSigner struct {
+	kms     KMS
+	address common.Address
+}
+
+// NewKMSSigner creates a signer for the key of kms, deriving its address from its public key.
+func NewKMSSigner(ctx context.Context, kms KMS) (*KMSSigner, error) {
+	der, err := kms.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key: %w", err)
+	}
+	pub, err := parsePublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	return &KMSSigner{kms: kms, address: crypto.PubkeyToAddress(*pub)}, nil
+}
+
+// Address returns the address of the key.
+func (s *KMSSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx has the KMS sign the hash of tx.
+func (s *KMSSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+
+	der, err := s.kms.Sign(ctx, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	sig, err := recoverableSignature(der, hash[:], s.address)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// subjectPublicKeyInfo is the ASN.1 structure of a DER public key.
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// parsePublicKey parses a DER secp256k1 public key, which crypto/x509 does not support.
+func parsePublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var info subjectPublicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) > 0 {
+		return nil, errors.New("invalid DER public key")
+	}
+	pub, err := crypto.UnmarshalPubkey(info.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("public key is not a secp256k1 key: %w", err)
+	}
+	return pub, nil
+}
+
+// ecdsaSignature is the ASN.1 structure of a DER ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// recoverableSignature converts the DER signature of digest by address into the 65-byte [R || S || V]
+// form of Ethereum. KMSs return either s of a signature, so s is made canonical, and V, which they
+// do not return, is found by recovering the signer.
+func recoverableSignature(der, digest []byte, address common.Address) ([]byte, error) {
+	var sig ecdsaSignature
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) > 0 {
+		return nil, errors.New("invalid DER signature")
+	}
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.BitLen() > 256 || sig.S.BitLen() > 256 {
+		return nil, errors.New("invalid signature values")
+	}
+	if sig.S.Cmp(secp256k1HalfN) > 0 {
+		sig.S = new(big.Int).Sub(crypto.S256().Params().N, sig.S)
+	}
+
+	out := make([]byte, crypto.SignatureLength)
+	sig.R.FillBytes(out[:32])
+	sig.S.FillBytes(out[32:64])
+	for v := byte(0); v < 2; v++ {
+		out[64] = v
+		pub, err := crypto.SigToPub(digest, out)
+		if err == nil && crypto.PubkeyToAddress(*pub) == address {
+			return out, nil
+		}
+	}
+	return nil, fmt.Errorf("signature was not made by %s", address.Hex())
+}
+
+// responseError describes a failed response including the start of its body.
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,217 @@
+package transactor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"hw/pkg/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// The responses in testdata have the format of the AWS KMS and Cloud KMS APIs, for a key holding
+// testKey and the transaction of fixtureTx. The AWS signature has a high s, the Cloud KMS one a low s.
+
+// fixtureTx returns the unsigned transaction signed by the fixtures.
+func fixtureTx() *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     7,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(22),
+		Gas:       60000,
+		To:        &to,
+		Value:     new(big.Int),
+		Data:      []byte{0x01},
+	})
+}
+
+// readFixture returns the content of a file in testdata.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// assertSignedByTestKey tests that tx is fixtureTx signed by testKey, exactly as a KeySigner signs it.
+func assertSignedByTestKey(t *testing.T, tx *types.Transaction) {
+	t.Helper()
+	keySigner, err := NewKeySigner(testKey)
+	assert.NoError(t, err)
+	want, err := keySigner.SignTx(context.Background(), fixtureTx(), big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, want.Hash(), tx.Hash())
+
+	from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1)), tx)
+	assert.NoError(t, err)
+	assert.Equal(t, keySigner.Address(), from)
+}
+
+// TestAWSKMSSigner tests that the address is derived from the public key of an AWS KMS key, and
+// that its signatures are made canonical and recoverable.
+func TestAWSKMSSigner(t *testing.T) {
+	var targets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.Header.Get("X-Amz-Target")
+		targets = append(targets, target)
+		assert.Equal(t, "application/x-amz-json-1.1", r.Header.Get("Content-Type"))
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20241104/eu-west-1/kms/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, Signature="))
+
+		var in map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Equal(t, "alias/settlement", in["KeyId"])
+		switch target {
+		case "TrentService.GetPublicKey":
+			w.Write(readFixture(t, "awskms_get_public_key.json"))
+		case "TrentService.Sign":
+			assert.Equal(t, "DIGEST", in["MessageType"])
+			assert.Equal(t, "ECDSA_SHA_256", in["SigningAlgorithm"])
+			hash := types.LatestSignerForChainID(big.NewInt(1)).Hash(fixtureTx())
+			assert.Equal(t, base64.StdEncoding.EncodeToString(hash[:]), in["Message"])
+			w.Write(readFixture(t, "awskms_sign.json"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	kms := NewAWSKMS(AWSKMSConfig{
+		Endpoint:        server.URL,
+		Region:          "eu-west-1",
+		KeyID:           "alias/settlement",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+	})
+	kms.now = func() time.Time { return time.Date(2024, 11, 4, 12, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	signer, err := NewKMSSigner(ctx, kms)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), signer.Address())
+
+	tx, err := signer.SignTx(ctx, fixtureTx(), big.NewInt(1))
+	assert.NoError(t, err)
+	assertSignedByTestKey(t, tx)
+	assert.Equal(t, []string{"TrentService.GetPublicKey", "TrentService.Sign"}, targets)
+}
+
+// TestAWSKMS_Error tests that an error response of AWS KMS is returned with its body.
+func TestAWSKMS_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"__type":"AccessDeniedException","message":"not authorized to perform kms:Sign"}`)
+	}))
+	defer server.Close()
+
+	kms := NewAWSKMS(AWSKMSConfig{Endpoint: server.URL, KeyID: "alias/settlement"})
+	_, err := kms.Sign(context.Background(), make([]byte, 32))
+	assert.ErrorContains(t, err, "failed to call AWS KMS Sign: unexpected status 400")
+	assert.ErrorContains(t, err, "AccessDeniedException")
+}
+
+// TestGCPKMSSigner tests that the address is derived from the public key of a Cloud KMS key
+// version, authenticated by the metadata server, and that its signatures are recoverable.
+func TestGCPKMSSigner(t *testing.T) {
+	const name = "projects/rewards/locations/global/keyRings/settlement/cryptoKeys/signer/cryptoKeyVersions/1"
+	tokenReads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenReads++
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			io.WriteString(w, `{"access_token":"ya29.token","expires_in":3599,"token_type":"Bearer"}`)
+			return
+		}
+
+		assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/v1/" + name + "/publicKey":
+			w.Write(readFixture(t, "gcpkms_public_key.json"))
+		case "/v1/" + name + ":asymmetricSign":
+			var in struct {
+				Digest struct {
+					SHA256 []byte `json:"sha256"`
+				} `json:"digest"`
+				DigestCrc32c string `json:"digestCrc32c"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+			hash := types.LatestSignerForChainID(big.NewInt(1)).Hash(fixtureTx())
+			assert.Equal(t, hash[:], in.Digest.SHA256)
+			assert.NotEmpty(t, in.DigestCrc32c)
+			w.Write(readFixture(t, "gcpkms_sign.json"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	kms := NewGCPKMS(GCPKMSConfig{Endpoint: server.URL, KeyName: name})
+	kms.tokenURL = server.URL + "/token"
+	ctx := context.Background()
+
+	signer, err := NewKMSSigner(ctx, kms)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), signer.Address())
+
+	tx, err := signer.SignTx(ctx, fixtureTx(), big.NewInt(1))
+	assert.NoError(t, err)
+	assertSignedByTestKey(t, tx)
+	assert.Equal(t, 1, tokenReads)
+}
+
+// TestGCPKMS_Checksum tests that a signature not matching its checksum is rejected.
+func TestGCPKMS_Checksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var out map[string]any
+		assert.NoError(t, json.Unmarshal(readFixture(t, "gcpkms_sign.json"), &out))
+		out["signatureCrc32c"] = "1"
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	kms := NewGCPKMS(GCPKMSConfig{Endpoint: server.URL, KeyName: "projects/p/locations/l/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1", AccessToken: "token"})
+	_, err := kms.Sign(context.Background(), make([]byte, 32))
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
+
+// TestRecoverableSignature tests that a signature by another key is rejected.
+func TestRecoverableSignature(t *testing.T) {
+	var sign struct {
+		Signature []byte `json:"Signature"`
+	}
+	assert.NoError(t, json.Unmarshal(readFixture(t, "awskms_sign.json"), &sign))
+	hash := types.LatestSignerForChainID(big.NewInt(1)).Hash(fixtureTx())
+
+	_, err := recoverableSignature(sign.Signature, hash[:], common.HexToAddress("0x00000000000000000000000000000000000000f1"))
+	assert.ErrorContains(t, err, "signature was not made by")
+
+	_, err = recoverableSignature([]byte{0x30, 0x00}, hash[:], common.Address{})
+	assert.Error(t, err)
+}
+
+// TestNewSigner_KMS tests that KMS signers need a key ID.
+func TestNewSigner_KMS(t *testing.T) {
+	cfg := config.Default().Settlement
+	for _, kind := range []string{SignerAWSKMS, SignerGCPKMS} {
+		cfg.Signer = kind
+		_, err := NewSigner(context.Background(), cfg)
+		assert.ErrorContains(t, err, "KMS key ID is required")
+	}
+}
@@ -0,0 +1,95 @@
+// Package transactor signs and sends transactions from one account, managing its nonces, gas
+// limits and fees, and tracks their receipts. The account's key is held in memory or in AWS KMS or
+// Cloud KMS, which sign without ever exposing it.
+package transactor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"hw/pkg/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Supported signers.
+const (
+	SignerKey    = "key"
+	SignerAWSKMS = "awskms"
+	SignerGCPKMS = "gcpkms"
+)
+
+// Signer signs the transactions of an account.
+type Signer interface {
+	// Address returns the account whose transactions are signed.
+	Address() common.Address
+	// SignTx returns tx signed for the chain chainID.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// NewSigner creates the Signer of the configured kind. A KMS signer reads the public key of its
+// key, to derive its address.
+func NewSigner(ctx context.Context, cfg config.Settlement) (Signer, error) {
+	switch cfg.Signer {
+	case SignerKey:
+		if cfg.PrivateKey == "" {
+			return nil, errors.New("settlement private key is required for signer key")
+		}
+		return NewKeySigner(cfg.PrivateKey)
+	case SignerAWSKMS:
+		if cfg.KMSKeyID == "" {
+			return nil, fmt.Errorf("settlement KMS key ID is required for signer %s", cfg.Signer)
+		}
+		return NewKMSSigner(ctx, NewAWSKMS(AWSKMSConfig{
+			Endpoint:        cfg.KMSEndpoint,
+			Region:          cfg.KMSRegion,
+			KeyID:           cfg.KMSKeyID,
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		}))
+	case SignerGCPKMS:
+		if cfg.KMSKeyID == "" {
+			return nil, fmt.Errorf("settlement KMS key ID is required for signer %s", cfg.Signer)
+		}
+		return NewKMSSigner(ctx, NewGCPKMS(GCPKMSConfig{
+			Endpoint:    cfg.KMSEndpoint,
+			KeyName:     cfg.KMSKeyID,
+			AccessToken: cfg.GCPAccessToken,
+		}))
+	default:
+		return nil, fmt.Errorf("unknown settlement signer: %s", cfg.Signer)
+	}
+}
+
+// KeySigner signs with a private key held in memory.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner creates a signer from a hex secp256k1 private key, with or without a 0x prefix.
+func NewKeySigner(hexKey string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		// The error of crypto.HexToECDSA does not include the key
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+// Address returns the address of the key.
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs tx with the key.
+func (s *KeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
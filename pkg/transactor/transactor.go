@@ -0,0 +1,251 @@
+package transactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend is the part of an Ethereum client used to send transactions, implemented by
+// ethclient.Client.
+type Backend interface {
+	FeeBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// ErrReverted is returned by WaitReceipt for a transaction mined with a failed status.
+var ErrReverted = errors.New("transaction reverted")
+
+// Option configures a Transactor.
+type Option func(*Transactor)
+
+// WithGasLimitMargin adds percent to the gas estimate of every transaction, so a transaction
+// whose gas use grows between the estimate and its block does not run out of gas.
+func WithGasLimitMargin(percent int) Option {
+	return func(t *Transactor) {
+		t.gasLimitMargin = percent
+	}
+}
+
+// WithFeeOracle sets the fee oracle estimating the fees of transactions, by default a FeeStandard
+// oracle without caps.
+func WithFeeOracle(oracle *FeeOracle) Option {
+	return func(t *Transactor) {
+		t.fees = oracle
+	}
+}
+
+// WithReceiptPollInterval sets how often WaitReceipt polls for a receipt.
+func WithReceiptPollInterval(interval time.Duration) Option {
+	return func(t *Transactor) {
+		t.pollInterval = interval
+	}
+}
+
+// Transactor sends the transactions of the account of a signer. Transactions are sent one at a
+// time, numbered from the pending nonce of the account, read once and again after a failed send,
+// so transactions sent in a row do not wait for each other to be mined.
+type Transactor struct {
+	backend        Backend
+	signer         Signer
+	fees           *FeeOracle
+	gasLimitMargin int
+	pollInterval   time.Duration
+
+	mu      sync.Mutex
+	chainID *big.Int
+	nonce   *uint64 // next nonce, nil until read from the backend
+}
+
+// New creates a Transactor sending the transactions signed by signer through backend.
+func New(backend Backend, signer Signer, opts ...Option) *Transactor {
+	t := &Transactor{
+		backend:      backend,
+		signer:       signer,
+		fees:         &FeeOracle{backend: backend, percentile: feePercentiles[FeeStandard]},
+		pollInterval: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// From returns the account sending the transactions.
+func (t *Transactor) From() common.Address {
+	return t.signer.Address()
+}
+
+// Send signs and sends a transaction calling to with data and value, returning it once the
+// backend accepted it. The gas limit is estimated, and the fees come from the fee oracle.
+func (t *Transactor) Send(ctx context.Context, to common.Address, data []byte, value *big.Int) (*types.Transaction, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.loadChainID(ctx); err != nil {
+		return nil, err
+	}
+	if t.nonce == nil {
+		nonce, err := t.backend.PendingNonceAt(ctx, t.From())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nonce: %w", err)
+		}
+		t.nonce = &nonce
+	}
+
+	fees, err := t.fees.Fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := t.signAndSend(ctx, *t.nonce, to, data, value, fees)
+	if err != nil {
+		// The nonce may have been used, e.g. by a transaction sent elsewhere, so it is read again
+		t.nonce = nil
+		return nil, err
+	}
+
+	*t.nonce++
+	return tx, nil
+}
+
+// Replace sends a transaction with the nonce of the transaction previous, calling to with data and
+// value, so it is mined instead of previous: a speed-up of a transaction stuck with fees too low.
+// Its fees are those of the fee oracle, raised above the fees of previous when the backend still
+// has it, as nodes require; previous dropped by the backend is sent again with current fees. It
+// returns nil when previous is already mined.
+func (t *Transactor) Replace(ctx context.Context, previous common.Hash, nonce uint64, to common.Address, data []byte, value *big.Int) (*types.Transaction, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, isPending, err := t.backend.TransactionByHash(ctx, previous)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", previous.Hex(), err)
+	}
+	if err == nil && !isPending {
+		return nil, nil
+	}
+
+	if err := t.loadChainID(ctx); err != nil {
+		return nil, err
+	}
+	fees, err := t.fees.Fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil {
+		previousFees := &Fees{GasTipCap: prev.GasTipCap(), GasFeeCap: prev.GasFeeCap()}
+		if prev.Type() == types.LegacyTxType {
+			previousFees = &Fees{GasPrice: prev.GasPrice()}
+		}
+		if fees, err = t.fees.Bump(fees, previousFees); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.signAndSend(ctx, nonce, to, data, value, fees)
+}
+
+// loadChainID reads the chain ID from the backend once.
+func (t *Transactor) loadChainID(ctx context.Context) error {
+	if t.chainID != nil {
+		return nil
+	}
+	chainID, err := t.backend.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	t.chainID = chainID
+	return nil
+}
+
+// signAndSend estimates the gas limit of a transaction with a nonce and fees, and signs and sends it.
+func (t *Transactor) signAndSend(ctx context.Context, nonce uint64, to common.Address, data []byte, value *big.Int, fees *Fees) (*types.Transaction, error) {
+	gas, err := t.backend.EstimateGas(ctx, ethereum.CallMsg{From: t.From(), To: &to, Value: value, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	gas += gas * uint64(t.gasLimitMargin) / 100
+
+	var unsigned *types.Transaction
+	if fees.GasPrice != nil {
+		unsigned = types.NewTx(&types.LegacyTx{Nonce: nonce, To: &to, Value: value, Gas: gas, GasPrice: fees.GasPrice, Data: data})
+	} else {
+		unsigned = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   t.chainID,
+			Nonce:     nonce,
+			GasTipCap: fees.GasTipCap,
+			GasFeeCap: fees.GasFeeCap,
+			Gas:       gas,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		})
+	}
+
+	tx, err := t.signer.SignTx(ctx, unsigned, t.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := t.backend.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// Receipt returns the receipt of a transaction, or nil while it is not mined.
+func (t *Transactor) Receipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, err := t.backend.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt of %s: %w", hash.Hex(), err)
+	}
+	return receipt, nil
+}
+
+// WaitReceipt polls the receipt of a transaction until it is mined or ctx is done. A reverted
+// transaction returns its receipt with ErrReverted.
+func (t *Transactor) WaitReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := t.Receipt(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return receipt, fmt.Errorf("%w: %s", ErrReverted, hash.Hex())
+			}
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
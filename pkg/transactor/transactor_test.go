@@ -0,0 +1,222 @@
+package transactor
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"hw/pkg/config"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testKey is a well-known development key, never used with funds.
+const testKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// fakeBackend is a chain with a base fee, or without when baseFee is nil, whose recent blocks paid
+// the tips of rewards. It accepts every transaction unless sendErr is set, and keeps them pending.
+type fakeBackend struct {
+	pendingNonce uint64
+	nonceReads   int
+	baseFee      *big.Int
+	rewards      []int64
+	sendErr      error
+	sent         []*types.Transaction
+	receipts     map[common.Hash]*types.Receipt
+}
+
+func (b *fakeBackend) ChainID(context.Context) (*big.Int, error) { return big.NewInt(1), nil }
+
+func (b *fakeBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	b.nonceReads++
+	return b.pendingNonce, nil
+}
+
+func (b *fakeBackend) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 50000, nil
+}
+
+func (b *fakeBackend) FeeHistory(_ context.Context, blockCount uint64, _ *big.Int, percentiles []float64) (*ethereum.FeeHistory, error) {
+	history := &ethereum.FeeHistory{OldestBlock: big.NewInt(100)}
+	rewards := b.rewards
+	if rewards == nil {
+		rewards = []int64{2}
+	}
+	for i := uint64(0); i < blockCount; i++ {
+		history.Reward = append(history.Reward, []*big.Int{big.NewInt(rewards[int(i)%len(rewards)])})
+		history.BaseFee = append(history.BaseFee, b.baseFee)
+	}
+	history.BaseFee = append(history.BaseFee, b.baseFee)
+	return history, nil
+}
+
+func (b *fakeBackend) SuggestGasPrice(context.Context) (*big.Int, error) { return big.NewInt(30), nil }
+
+func (b *fakeBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	if b.sendErr != nil {
+		return b.sendErr
+	}
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func (b *fakeBackend) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	for _, tx := range b.sent {
+		if tx.Hash() == hash {
+			_, mined := b.receipts[hash]
+			return tx, !mined, nil
+		}
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (b *fakeBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	if receipt, ok := b.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+// TestNewSigner tests that the key signer needs a valid key.
+func TestNewSigner(t *testing.T) {
+	cfg := config.Default().Settlement
+	_, err := NewSigner(context.Background(), cfg)
+	assert.Error(t, err)
+
+	cfg.PrivateKey = testKey
+	signer, err := NewSigner(context.Background(), cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), signer.Address())
+
+	cfg.Signer = "vault"
+	_, err = NewSigner(context.Background(), cfg)
+	assert.Error(t, err)
+}
+
+// TestSend tests that transactions sent in a row are numbered from the pending nonce, with the
+// gas margin and the fees of the oracle, and that a failed send reads the nonce again.
+func TestSend(t *testing.T) {
+	signer, err := NewKeySigner(testKey)
+	assert.NoError(t, err)
+	backend := &fakeBackend{pendingNonce: 7, baseFee: big.NewInt(10)}
+	tr := New(backend, signer, WithGasLimitMargin(20))
+	ctx := context.Background()
+	to := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+
+	for range 2 {
+		_, err := tr.Send(ctx, to, []byte{0x01}, nil)
+		assert.NoError(t, err)
+	}
+	if assert.Len(t, backend.sent, 2) {
+		assert.Equal(t, uint64(7), backend.sent[0].Nonce())
+		assert.Equal(t, uint64(8), backend.sent[1].Nonce())
+		assert.Equal(t, uint64(60000), backend.sent[0].Gas())
+		assert.Equal(t, big.NewInt(2), backend.sent[0].GasTipCap())
+		assert.Equal(t, big.NewInt(22), backend.sent[0].GasFeeCap())
+		from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1)), backend.sent[0])
+		assert.NoError(t, err)
+		assert.Equal(t, signer.Address(), from)
+	}
+	assert.Equal(t, 1, backend.nonceReads)
+
+	backend.sendErr = errors.New("nonce too low")
+	_, err = tr.Send(ctx, to, nil, nil)
+	assert.Error(t, err)
+	backend.sendErr = nil
+	backend.pendingNonce = 12
+	tx, err := tr.Send(ctx, to, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), tx.Nonce())
+	assert.Equal(t, 2, backend.nonceReads)
+}
+
+// TestSend_Legacy tests that a chain without a base fee gets a legacy transaction.
+func TestSend_Legacy(t *testing.T) {
+	signer, err := NewKeySigner(testKey)
+	assert.NoError(t, err)
+	tr := New(&fakeBackend{}, signer)
+
+	tx, err := tr.Send(context.Background(), common.Address{}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(types.LegacyTxType), tx.Type())
+	assert.Equal(t, big.NewInt(30), tx.GasPrice())
+}
+
+// TestReplace tests that a pending transaction is replaced with the same nonce and fees bumped
+// above its own, that a dropped one is sent again with the fees of the oracle, and that a mined
+// one is not replaced.
+func TestReplace(t *testing.T) {
+	signer, err := NewKeySigner(testKey)
+	assert.NoError(t, err)
+	backend := &fakeBackend{pendingNonce: 7, baseFee: big.NewInt(100), rewards: []int64{20}}
+	tr := New(backend, signer)
+	ctx := context.Background()
+	to := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+
+	stuck, err := tr.Send(ctx, to, []byte{0x01}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(220), stuck.GasFeeCap())
+
+	// The fees did not change, so the bump sets them
+	replacement, err := tr.Replace(ctx, stuck.Hash(), stuck.Nonce(), to, []byte{0x01}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), replacement.Nonce())
+	assert.Equal(t, big.NewInt(22), replacement.GasTipCap())
+	assert.Equal(t, big.NewInt(242), replacement.GasFeeCap())
+
+	// The base fee rose above the bump
+	backend.baseFee = big.NewInt(200)
+	replacement, err = tr.Replace(ctx, replacement.Hash(), 7, to, []byte{0x01}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(25), replacement.GasTipCap())
+	assert.Equal(t, big.NewInt(420), replacement.GasFeeCap())
+
+	dropped, err := tr.Replace(ctx, common.Hash{9}, 8, to, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), dropped.Nonce())
+	assert.Equal(t, big.NewInt(20), dropped.GasTipCap())
+
+	backend.receipts = map[common.Hash]*types.Receipt{dropped.Hash(): {Status: types.ReceiptStatusSuccessful}}
+	mined, err := tr.Replace(ctx, dropped.Hash(), 8, to, nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, mined)
+
+	// Sending is unaffected by replacements
+	next, err := tr.Send(ctx, to, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), next.Nonce())
+}
+
+// TestWaitReceipt tests that a mined transaction returns its receipt, a reverted one
+// ErrReverted, and a pending one waits until the context is done.
+func TestWaitReceipt(t *testing.T) {
+	mined, reverted, pending := common.Hash{1}, common.Hash{2}, common.Hash{3}
+	backend := &fakeBackend{receipts: map[common.Hash]*types.Receipt{
+		mined:    {Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100)},
+		reverted: {Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(101)},
+	}}
+	signer, err := NewKeySigner(testKey)
+	assert.NoError(t, err)
+	tr := New(backend, signer, WithReceiptPollInterval(time.Millisecond))
+	ctx := context.Background()
+
+	receipt, err := tr.WaitReceipt(ctx, mined)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), receipt.BlockNumber)
+
+	_, err = tr.WaitReceipt(ctx, reverted)
+	assert.ErrorIs(t, err, ErrReverted)
+
+	receipt, err = tr.Receipt(ctx, pending)
+	assert.NoError(t, err)
+	assert.Nil(t, receipt)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = tr.WaitReceipt(ctx, pending)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}